@@ -4,9 +4,18 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	errUnknownGraphFormat = errors.New("unknown graph format")
 )
 
 // GetChainAliasesArgs are the arguments for Admin.GetChainAliases API call
@@ -25,3 +34,361 @@ func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs
 	reply.Aliases = service.chainManager.Aliases(ID)
 	return nil
 }
+
+// GetChainBootstrapStatusArgs are the arguments for Admin.GetChainBootstrapStatus API call
+type GetChainBootstrapStatusArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// GetChainBootstrapStatusReply are the results from calling Admin.GetChainBootstrapStatus
+type GetChainBootstrapStatusReply struct {
+	Status string `json:"status"`
+}
+
+// GetChainBootstrapStatus returns whether the chain [args.Chain] is waiting
+// for the chains it depends on, is bootstrapping, or has finished bootstrapping
+func (service *Admin) GetChainBootstrapStatus(r *http.Request, args *GetChainBootstrapStatusArgs, reply *GetChainBootstrapStatusReply) error {
+	service.log.Debug("Admin: GetChainBootstrapStatus called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		// The chain may not have been created yet, in which case it has no
+		// aliases other than its ID
+		chainID, err = ids.FromString(args.Chain)
+		if err != nil {
+			return err
+		}
+	}
+	reply.Status = service.chainManager.BootstrapStatus(chainID).String()
+	return nil
+}
+
+// GetContainerLimitsArgs are the arguments for Admin.GetContainerLimits API call
+type GetContainerLimitsArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// GetContainerLimitsReply are the results from calling Admin.GetContainerLimits
+type GetContainerLimitsReply struct {
+	MaxBlockSize  json.Uint32 `json:"maxBlockSize"`
+	MaxVertexSize json.Uint32 `json:"maxVertexSize"`
+	MaxTxSize     json.Uint32 `json:"maxTxSize"`
+}
+
+// GetContainerLimits returns the sizes, in bytes, of the largest blocks,
+// vertices and transactions the chain [args.Chain] issues and accepts
+func (service *Admin) GetContainerLimits(r *http.Request, args *GetContainerLimitsArgs, reply *GetContainerLimitsReply) error {
+	service.log.Debug("Admin: GetContainerLimits called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	limits, err := service.chainManager.ContainerLimits(chainID)
+	if err != nil {
+		return err
+	}
+	reply.MaxBlockSize = json.Uint32(limits.MaxBlockSize)
+	reply.MaxVertexSize = json.Uint32(limits.MaxVertexSize)
+	reply.MaxTxSize = json.Uint32(limits.MaxTxSize)
+	return nil
+}
+
+// GetMisbehaviorEvidenceArgs are the arguments for Admin.GetMisbehaviorEvidence API call
+type GetMisbehaviorEvidenceArgs struct{}
+
+// APIEvidence is evidence that a validator misbehaved
+type APIEvidence struct {
+	ValidatorID  ids.ShortID     `json:"validatorID"`
+	ChainID      ids.ID          `json:"chainID"`
+	Kind         json.Uint32     `json:"kind"`
+	Description  string          `json:"description"`
+	ContainerIDs []ids.ID        `json:"containerIDs"`
+	Container    formatting.CB58 `json:"container"`
+	Time         json.Uint64     `json:"time"`
+}
+
+// GetMisbehaviorEvidenceReply are the results from calling Admin.GetMisbehaviorEvidence
+type GetMisbehaviorEvidenceReply struct {
+	Evidence []APIEvidence `json:"evidence"`
+}
+
+// GetMisbehaviorEvidence returns the most recent evidence of validators
+// misbehaving on the chains this node runs, oldest first
+func (service *Admin) GetMisbehaviorEvidence(r *http.Request, args *GetMisbehaviorEvidenceArgs, reply *GetMisbehaviorEvidenceReply) error {
+	service.log.Debug("Admin: GetMisbehaviorEvidence called")
+
+	reply.Evidence = []APIEvidence{}
+	for _, evidence := range service.chainManager.MisbehaviorEvidence() {
+		reply.Evidence = append(reply.Evidence, APIEvidence{
+			ValidatorID:  evidence.ValidatorID,
+			ChainID:      evidence.ChainID,
+			Kind:         json.Uint32(evidence.Kind),
+			Description:  evidence.Kind.String(),
+			ContainerIDs: evidence.ContainerIDs,
+			Container:    formatting.CB58{Bytes: evidence.Container},
+			Time:         json.Uint64(evidence.Time.Unix()),
+		})
+	}
+	return nil
+}
+
+// GetRecentAcceptancesArgs are the arguments for Admin.GetRecentAcceptances API call
+type GetRecentAcceptancesArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+
+	// If set, only the acceptance of this transaction, or block, is returned
+	ID ids.ID `json:"id"`
+}
+
+// APIAcceptance is a transaction, or block, a chain accepted
+type APIAcceptance struct {
+	ID   ids.ID      `json:"id"`
+	Time json.Uint64 `json:"time"`
+}
+
+// GetRecentAcceptancesReply are the results from calling Admin.GetRecentAcceptances
+type GetRecentAcceptancesReply struct {
+	Acceptances []APIAcceptance `json:"acceptances"`
+}
+
+// GetRecentAcceptances returns the transactions, or blocks, the chain
+// [args.Chain] accepted within the node's acceptance window, oldest first
+func (service *Admin) GetRecentAcceptances(_ *http.Request, args *GetRecentAcceptancesArgs, reply *GetRecentAcceptancesReply) error {
+	service.log.Debug("Admin: GetRecentAcceptances called with Chain: %s, ID: %s", args.Chain, args.ID)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	acceptances, err := service.chainManager.RecentAcceptances(chainID)
+	if err != nil {
+		return err
+	}
+
+	reply.Acceptances = []APIAcceptance{}
+	for _, acceptance := range acceptances {
+		if !args.ID.IsZero() && !args.ID.Equals(acceptance.ID) {
+			continue
+		}
+		reply.Acceptances = append(reply.Acceptances, APIAcceptance{
+			ID:   acceptance.ID,
+			Time: json.Uint64(acceptance.Time.Unix()),
+		})
+	}
+	return nil
+}
+
+// ExportSnapshotArgs are the arguments for Admin.ExportSnapshot API call
+type ExportSnapshotArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// ExportSnapshotReply are the results from calling Admin.ExportSnapshot
+type ExportSnapshotReply struct {
+	// Path of the snapshot on this node
+	Path       string      `json:"path"`
+	Containers json.Uint64 `json:"containers"`
+}
+
+// ExportSnapshot writes the containers the chain [args.Chain] accepted to a
+// snapshot in the node's snapshot directory. A fresh node that has the
+// snapshot in its snapshot directory accepts its containers before it
+// bootstraps. The chain doesn't process messages while the snapshot is
+// written.
+func (service *Admin) ExportSnapshot(_ *http.Request, args *ExportSnapshotArgs, reply *ExportSnapshotReply) error {
+	service.log.Debug("Admin: ExportSnapshot called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	path, containers, err := service.chainManager.ExportSnapshot(chainID)
+	if err != nil {
+		return err
+	}
+	service.log.Info("exported %d containers of chain %s to %s", containers, chainID, path)
+
+	reply.Path = path
+	reply.Containers = json.Uint64(containers)
+	return nil
+}
+
+// Formats of the consensus graph returned by Admin.GetConsensusGraph
+const (
+	jsonGraphFormat = "json"
+	dotGraphFormat  = "dot"
+)
+
+// GetConsensusGraphArgs are the arguments for Admin.GetConsensusGraph API call
+type GetConsensusGraphArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+
+	// Format of the graph. One of {json, dot}. Defaults to json.
+	Format string `json:"format"`
+}
+
+// GetConsensusGraphReply are the results from calling Admin.GetConsensusGraph
+type GetConsensusGraphReply struct {
+	// The graph, if the format is json
+	Graph *snow.Graph `json:"graph,omitempty"`
+
+	// The graph in the DOT language of Graphviz, if the format is dot
+	DOT string `json:"dot,omitempty"`
+}
+
+// GetConsensusGraph returns the blocks, or vertices and transactions, the
+// chain [args.Chain] is processing, their status and confidence, and the
+// decisions they depend on, so that a stalled chain can be diagnosed
+func (service *Admin) GetConsensusGraph(_ *http.Request, args *GetConsensusGraphArgs, reply *GetConsensusGraphReply) error {
+	service.log.Debug("Admin: GetConsensusGraph called with Chain: %s, Format: %s", args.Chain, args.Format)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	graph, err := service.chainManager.ConsensusGraph(chainID)
+	if err != nil {
+		return err
+	}
+
+	switch args.Format {
+	case "", jsonGraphFormat:
+		reply.Graph = &graph
+	case dotGraphFormat:
+		reply.DOT = graph.DOT()
+	default:
+		return fmt.Errorf("%w %q. Should be one of {%s, %s}", errUnknownGraphFormat, args.Format, jsonGraphFormat, dotGraphFormat)
+	}
+	return nil
+}
+
+// GetChainResourceUsageArgs are the arguments for Admin.GetChainResourceUsage API call
+type GetChainResourceUsageArgs struct{}
+
+// APIChainResourceUsage is the resources used by a chain
+type APIChainResourceUsage struct {
+	ChainID        ids.ID      `json:"chainID"`
+	Aliases        []string    `json:"aliases"`
+	CPUBudget      float64     `json:"cpuBudget"`
+	ProcessingTime string      `json:"processingTime"`
+	Processed      json.Uint64 `json:"processed"`
+	Delayed        json.Uint64 `json:"delayed"`
+
+	// Bytes of requests the chain may hold back while throttled, bytes of the
+	// messages it holds until they're processed, and the most it has held at
+	// once
+	MemoryBudget    json.Uint64 `json:"memoryBudget"`
+	QueuedBytes     json.Uint64 `json:"queuedBytes"`
+	PeakQueuedBytes json.Uint64 `json:"peakQueuedBytes"`
+}
+
+// GetChainResourceUsageReply are the results from calling Admin.GetChainResourceUsage
+type GetChainResourceUsageReply struct {
+	Chains []APIChainResourceUsage `json:"chains"`
+}
+
+// GetChainResourceUsage returns the time each chain has spent processing
+// messages, the memory its queued messages hold, and whether the chain is
+// throttled. The memory the chain's VM allocates isn't tracked.
+func (service *Admin) GetChainResourceUsage(r *http.Request, args *GetChainResourceUsageArgs, reply *GetChainResourceUsageReply) error {
+	service.log.Debug("Admin: GetChainResourceUsage called")
+
+	for _, usage := range service.chainManager.ResourceUsage() {
+		reply.Chains = append(reply.Chains, APIChainResourceUsage{
+			ChainID:         usage.ChainID,
+			Aliases:         service.chainManager.Aliases(usage.ChainID),
+			CPUBudget:       usage.CPUBudget,
+			ProcessingTime:  usage.ProcessingTime.String(),
+			Processed:       json.Uint64(usage.Processed),
+			Delayed:         json.Uint64(usage.Delayed),
+			MemoryBudget:    json.Uint64(usage.MemoryBudget),
+			QueuedBytes:     json.Uint64(usage.QueuedBytes),
+			PeakQueuedBytes: json.Uint64(usage.PeakQueuedBytes),
+		})
+	}
+	return nil
+}
+
+// MountChainArgs are the arguments for calling MountChain
+type MountChainArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+
+	// URL prefix to serve the chain's API under, such as /public/x
+	Prefix string `json:"prefix"`
+}
+
+// MountChainReply are the results from calling MountChain
+type MountChainReply struct {
+	Success bool `json:"success"`
+}
+
+// MountChain serves the API of the chain [args.Chain] under [args.Prefix], in
+// addition to its other routes
+func (service *Admin) MountChain(_ *http.Request, args *MountChainArgs, reply *MountChainReply) error {
+	service.log.Debug("Admin: MountChain called with Chain: %s, Prefix: %s", args.Chain, args.Prefix)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	if err := service.httpServer.MountChain(args.Prefix, chainID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnmountArgs are the arguments for calling Unmount
+type UnmountArgs struct {
+	Prefix string `json:"prefix"`
+}
+
+// UnmountReply are the results from calling Unmount
+type UnmountReply struct {
+	Success bool `json:"success"`
+}
+
+// Unmount stops serving the API mounted at [args.Prefix]
+func (service *Admin) Unmount(_ *http.Request, args *UnmountArgs, reply *UnmountReply) error {
+	service.log.Debug("Admin: Unmount called with Prefix: %s", args.Prefix)
+
+	if err := service.httpServer.Unmount(args.Prefix); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetMountsArgs are the arguments for calling GetMounts
+type GetMountsArgs struct{}
+
+// APIMount is a URL prefix and the route mounted at it
+type APIMount struct {
+	Prefix string `json:"prefix"`
+	Target string `json:"target"`
+}
+
+// GetMountsReply are the results from calling GetMounts
+type GetMountsReply struct {
+	Mounts []APIMount `json:"mounts"`
+}
+
+// GetMounts returns the URL prefixes that APIs are mounted at
+func (service *Admin) GetMounts(_ *http.Request, _ *GetMountsArgs, reply *GetMountsReply) error {
+	service.log.Debug("Admin: GetMounts called")
+
+	for _, mount := range service.httpServer.Mounts() {
+		reply.Mounts = append(reply.Mounts, APIMount{
+			Prefix: mount.Prefix,
+			Target: mount.Target,
+		})
+	}
+	return nil
+}
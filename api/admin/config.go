@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+)
+
+// Reloadable can re-read this node's config file while the node is running
+type Reloadable interface {
+	// ReloadConfig applies the flags of the config file that changed and can
+	// be changed while the node is running. It returns the changed flags that
+	// were applied, and those that only take effect when the node restarts.
+	ReloadConfig() (applied []string, requireRestart []string, err error)
+}
+
+// ReloadConfigArgs are the arguments for calling ReloadConfig
+type ReloadConfigArgs struct{}
+
+// ReloadConfigReply are the results from calling ReloadConfig
+type ReloadConfigReply struct {
+	// Flags whose new value was applied
+	Applied []string `json:"applied"`
+
+	// Flags whose new value only takes effect when the node restarts
+	RequireRestart []string `json:"requireRestart"`
+}
+
+// ReloadConfig re-reads the config file this node was started with and
+// applies the flags that can be changed without restarting the node, such as
+// log levels and which APIs are enabled
+func (service *Admin) ReloadConfig(r *http.Request, args *ReloadConfigArgs, reply *ReloadConfigReply) error {
+	service.log.Debug("Admin: ReloadConfig called")
+
+	applied, requireRestart, err := service.config.ReloadConfig()
+	reply.Applied = applied
+	reply.RequireRestart = requireRestart
+	return err
+}
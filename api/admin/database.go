@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Compactable can compact this node's database while the node is running
+type Compactable interface {
+	// CompactDatabase compacts this node's whole database, and returns how
+	// long it took
+	CompactDatabase() (time.Duration, error)
+
+	// CompactChainDatabase compacts the databases the chain [chainID] stores
+	// its state in, and returns how long it took
+	CompactChainDatabase(chainID ids.ID) (time.Duration, error)
+}
+
+// CompactDatabaseArgs are the arguments for calling CompactDatabase
+type CompactDatabaseArgs struct {
+	// Alias or ID of the chain whose databases are compacted. If empty, the
+	// whole database is compacted.
+	Chain string `json:"chain"`
+}
+
+// CompactDatabaseReply are the results from calling CompactDatabase
+type CompactDatabaseReply struct {
+	// How long the compaction took
+	Duration string `json:"duration"`
+}
+
+// CompactDatabase compacts this node's database, or only the databases of a
+// chain, discarding deleted and overwritten data. It returns once the
+// compaction, which may take a while, and any compaction already in progress
+// finish.
+func (service *Admin) CompactDatabase(_ *http.Request, args *CompactDatabaseArgs, reply *CompactDatabaseReply) error {
+	service.log.Debug("Admin: CompactDatabase called with Chain: %s", args.Chain)
+
+	var (
+		duration time.Duration
+		err      error
+	)
+	if args.Chain == "" {
+		duration, err = service.database.CompactDatabase()
+	} else {
+		chainID, lookupErr := service.chainManager.Lookup(args.Chain)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		duration, err = service.database.CompactChainDatabase(chainID)
+	}
+	reply.Duration = duration.String()
+	return err
+}
@@ -4,16 +4,48 @@
 package admin
 
 import (
+	"errors"
+	"net"
 	"sort"
 
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/banlist"
+	"github.com/ava-labs/gecko/networking/versions"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/utils"
 )
 
+var (
+	errInvalidIP = errors.New("invalid IP")
+)
+
 // Peerable can return a group of peers
-type Peerable interface{ Peers() []utils.IPDesc }
+type Peerable interface {
+	Peers() []utils.IPDesc
+	Conns() ([]utils.IPDesc, []ids.ShortID)
+}
+
+// Bannable can ban IPs from connecting to this node
+type Bannable interface {
+	Ban(ip net.IP, reason string) error
+	Unban(ip net.IP) error
+	Bans() []banlist.Ban
+}
+
+// Versionable reports the versions run by this node and its peers
+type Versionable interface {
+	Version() string
+	Summary() []versions.Summary
+	Behind() bool
+}
 
 // Networking provides helper methods for tracking the current network state
-type Networking struct{ peers Peerable }
+type Networking struct {
+	peers     Peerable
+	latencies timeout.Latencies
+	bans      Bannable
+	versions  Versionable
+}
 
 // Peers returns the current peers
 func (n *Networking) Peers() ([]string, error) {
@@ -25,3 +57,52 @@ func (n *Networking) Peers() ([]string, error) {
 	sort.Strings(ips)
 	return ips, nil
 }
+
+// Latencies returns the round trip times to the current peers. The RTT of a
+// peer that hasn't replied to a ping yet is empty.
+func (n *Networking) Latencies() ([]PeerLatency, error) {
+	ipDescs, vdrIDs := n.peers.Conns()
+	latencies := make([]PeerLatency, len(ipDescs))
+	for i, ipDesc := range ipDescs {
+		latencies[i] = PeerLatency{
+			IP:     ipDesc.String(),
+			NodeID: vdrIDs[i],
+		}
+		if rtt, measured := n.latencies.RTT(vdrIDs[i]); measured {
+			latencies[i].RTT = rtt.String()
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].IP < latencies[j].IP })
+	return latencies, nil
+}
+
+// Ban [ip], which may include a port, for [reason]
+func (n *Networking) Ban(ip string, reason string) error {
+	parsedIP, err := parseIP(ip)
+	if err != nil {
+		return err
+	}
+	return n.bans.Ban(parsedIP, reason)
+}
+
+// Unban [ip], which may include a port
+func (n *Networking) Unban(ip string) error {
+	parsedIP, err := parseIP(ip)
+	if err != nil {
+		return err
+	}
+	return n.bans.Unban(parsedIP)
+}
+
+// Bans returns the banned IPs
+func (n *Networking) Bans() []banlist.Ban { return n.bans.Bans() }
+
+func parseIP(ip string) (net.IP, error) {
+	if parsedIP := net.ParseIP(ip); parsedIP != nil {
+		return parsedIP, nil
+	}
+	if ipDesc, err := utils.ToIPDesc(ip); err == nil {
+		return ipDesc.IP, nil
+	}
+	return nil, errInvalidIP
+}
@@ -11,7 +11,11 @@ import (
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/networking/banlist"
+	"github.com/ava-labs/gecko/networking/versions"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/utils/logging"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
@@ -26,10 +30,12 @@ type Admin struct {
 	performance  Performance
 	chainManager chains.Manager
 	httpServer   *api.Server
+	config       Reloadable
+	database     Compactable
 }
 
 // NewService returns a new admin API service
-func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainManager chains.Manager, peers Peerable, httpServer *api.Server) *common.HTTPHandler {
+func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainManager chains.Manager, peers Peerable, latencies timeout.Latencies, bans Bannable, versions Versionable, httpServer *api.Server, config Reloadable, database Compactable) *common.HTTPHandler {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
@@ -40,9 +46,14 @@ func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainM
 		log:          log,
 		chainManager: chainManager,
 		networking: Networking{
-			peers: peers,
+			peers:     peers,
+			latencies: latencies,
+			bans:      bans,
+			versions:  versions,
 		},
 		httpServer: httpServer,
+		config:     config,
+		database:   database,
 	}, "admin")
 	return &common.HTTPHandler{Handler: newServer}
 }
@@ -101,20 +112,147 @@ func (service *Admin) GetBlockchainID(r *http.Request, args *GetBlockchainIDArgs
 // PeersArgs are the arguments for calling Peers
 type PeersArgs struct{}
 
+// PeerLatency is the round trip time to a peer
+type PeerLatency struct {
+	IP     string      `json:"ip"`
+	NodeID ids.ShortID `json:"nodeID"`
+	RTT    string      `json:"rtt"`
+}
+
 // PeersReply are the results from calling Peers
 type PeersReply struct {
-	Peers []string `json:"peers"`
+	Peers     []string      `json:"peers"`
+	Latencies []PeerLatency `json:"latencies"`
 }
 
-// Peers returns the list of current validators
+// Peers returns the list of current validators, and the round trip time to
+// each of them
 func (service *Admin) Peers(r *http.Request, args *PeersArgs, reply *PeersReply) error {
 	service.log.Debug("Admin: Peers called")
 
 	peers, err := service.networking.Peers()
+	if err != nil {
+		return err
+	}
 	reply.Peers = peers
+	reply.Latencies, err = service.networking.Latencies()
 	return err
 }
 
+// GetNetworkVersionsArgs are the arguments for calling GetNetworkVersions
+type GetNetworkVersionsArgs struct{}
+
+// GetNetworkVersionsReply are the results from calling GetNetworkVersions
+type GetNetworkVersionsReply struct {
+	// Version this node is running
+	Version string `json:"version"`
+
+	// Versions run by this node and its peers, sorted by decreasing stake
+	Versions []versions.Summary `json:"versions"`
+
+	// Behind is true if validators with a majority of the stake are running a
+	// later version than this node
+	Behind bool `json:"behind"`
+}
+
+// GetNetworkVersions summarizes the versions run by this node's peers, weighted
+// by stake
+func (service *Admin) GetNetworkVersions(_ *http.Request, _ *GetNetworkVersionsArgs, reply *GetNetworkVersionsReply) error {
+	service.log.Debug("Admin: GetNetworkVersions called")
+
+	reply.Version = service.networking.versions.Version()
+	reply.Versions = service.networking.versions.Summary()
+	reply.Behind = service.networking.versions.Behind()
+	return nil
+}
+
+// GetWireSchemaArgs are the arguments for calling GetWireSchema
+type GetWireSchemaArgs struct{}
+
+// GetWireSchemaReply are the results from calling GetWireSchema
+type GetWireSchemaReply struct {
+	// Version this node is running
+	Version string `json:"version"`
+
+	// How each encoding a field may have is laid out
+	Encodings map[string]string `json:"encodings"`
+
+	// The messages this node sends and receives, ordered by op code
+	Messages []networking.MessageSchema `json:"messages"`
+}
+
+// GetWireSchema describes the messages this node sends to, and receives from,
+// its peers, so that other implementations can check that they're compatible
+// with it
+func (service *Admin) GetWireSchema(_ *http.Request, _ *GetWireSchemaArgs, reply *GetWireSchemaReply) error {
+	service.log.Debug("Admin: GetWireSchema called")
+
+	reply.Version = service.networking.versions.Version()
+	reply.Encodings = networking.Encodings
+	reply.Messages = networking.Schema()
+	return nil
+}
+
+// BanPeerArgs are the arguments for calling BanPeer
+type BanPeerArgs struct {
+	IP     string `json:"ip"`
+	Reason string `json:"reason"`
+}
+
+// BanPeerReply are the results from calling BanPeer
+type BanPeerReply struct {
+	Success bool `json:"success"`
+}
+
+// BanPeer bans an IP, which may include a port, from connecting to this node
+// and disconnects any peers at the IP. The ban persists across restarts.
+func (service *Admin) BanPeer(r *http.Request, args *BanPeerArgs, reply *BanPeerReply) error {
+	service.log.Debug("Admin: BanPeer called with %s", args.IP)
+
+	if err := service.networking.Ban(args.IP, args.Reason); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbanPeerArgs are the arguments for calling UnbanPeer
+type UnbanPeerArgs struct {
+	IP string `json:"ip"`
+}
+
+// UnbanPeerReply are the results from calling UnbanPeer
+type UnbanPeerReply struct {
+	Success bool `json:"success"`
+}
+
+// UnbanPeer lifts the ban of an IP, which may include a port
+func (service *Admin) UnbanPeer(r *http.Request, args *UnbanPeerArgs, reply *UnbanPeerReply) error {
+	service.log.Debug("Admin: UnbanPeer called with %s", args.IP)
+
+	if err := service.networking.Unban(args.IP); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ListBansArgs are the arguments for calling ListBans
+type ListBansArgs struct{}
+
+// ListBansReply are the results from calling ListBans
+type ListBansReply struct {
+	Bans []banlist.Ban `json:"bans"`
+}
+
+// ListBans returns the banned IPs
+func (service *Admin) ListBans(r *http.Request, args *ListBansArgs, reply *ListBansReply) error {
+	service.log.Debug("Admin: ListBans called")
+
+	reply.Bans = service.networking.Bans()
+	return nil
+}
+
 // StartCPUProfilerArgs are the arguments for calling StartCPUProfiler
 type StartCPUProfilerArgs struct {
 	Filename string `json:"filename"`
@@ -0,0 +1,321 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	// APIKeyHeader of a request carries its API key, as "Bearer <key>"
+	APIKeyHeader = "Authorization"
+
+	// DefaultAPIKeyPeriod is the period of an API key's quota if none is given
+	DefaultAPIKeyPeriod = time.Hour
+
+	// usageMethod can be called with any API key, whatever its scope and
+	// quota, so the key's owner can always see why their calls are refused
+	usageMethod = "auth.getusage"
+
+	bearerPrefix = "Bearer "
+	apiKeyLen    = 24
+)
+
+var (
+	errAPIKeysDisabled  = errors.New("API keys aren't enabled")
+	errNoAPIKeyName     = errors.New("API key name must not be empty")
+	errDuplicateAPIKey  = errors.New("an API key with that name already exists")
+	errUnknownAPIKey    = errors.New("unknown API key")
+	errMissingAPIKey    = errors.New("an API key is required")
+	errOutOfScope       = errors.New("API key may not call")
+	errAPIQuotaExceeded = errors.New("API key quota exceeded")
+)
+
+// APIKey lets a downstream application call the API on listeners that require
+// a key. The key itself is only stored hashed.
+type APIKey struct {
+	Name      string `serialize:"true"`
+	TokenHash []byte `serialize:"true"`
+
+	// Quota is the most calls that may be made with the key each [Period]. 0
+	// means unlimited.
+	Quota  uint64        `serialize:"true"`
+	Period time.Duration `serialize:"true"`
+
+	// Methods the key may call, such as avm.getBalance, or every method of a
+	// service, such as platform.*. If empty, the key may call every method.
+	// Requests that aren't JSON-RPC calls, such as GET bc/P/export, can only
+	// be made with keys that may call every method.
+	Methods []string `serialize:"true"`
+
+	Created uint64 `serialize:"true"` // Unix time
+}
+
+// APIKeyUsage counts the calls made with an API key since the node started
+type APIKeyUsage struct {
+	Calls   uint64 // Calls that were served
+	Refused uint64 // Calls that were out of scope or over quota
+
+	// The current period of the key's quota, and the calls served in it
+	PeriodStart time.Time
+	PeriodCalls uint64
+}
+
+// APIKeyStatus is an API key and its usage
+type APIKeyStatus struct {
+	APIKey
+	Usage APIKeyUsage
+}
+
+// apiKeys are the keys downstream applications call the API with on listeners
+// that require one
+type apiKeys struct {
+	lock   sync.Mutex
+	db     database.Database // nil until API keys are enabled
+	codec  codec.Codec
+	clock  timer.Clock
+	byHash map[string]*APIKeyStatus
+	byName map[string]*APIKeyStatus
+}
+
+func newAPIKeys() *apiKeys {
+	return &apiKeys{
+		codec:  codec.NewDefault(),
+		byHash: make(map[string]*APIKeyStatus),
+		byName: make(map[string]*APIKeyStatus),
+	}
+}
+
+// enable API keys, which are stored in [db]
+func (k *apiKeys) enable(db database.Database) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	iter := db.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		key := APIKey{}
+		if err := k.codec.Unmarshal(iter.Value(), &key); err != nil {
+			return fmt.Errorf("couldn't parse API key: %w", err)
+		}
+		k.add(key)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	k.db = db
+	return nil
+}
+
+// add [key]. Assumes the lock is held.
+func (k *apiKeys) add(key APIKey) {
+	status := &APIKeyStatus{APIKey: key}
+	k.byHash[string(key.TokenHash)] = status
+	k.byName[key.Name] = status
+}
+
+// create an API key, and return the token it's called with
+func (k *apiKeys) create(name string, quota uint64, period time.Duration, methods []string) (string, error) {
+	if name == "" {
+		return "", errNoAPIKeyName
+	}
+	if period <= 0 {
+		period = DefaultAPIKeyPeriod
+	}
+	tokenBytes := make([]byte, apiKeyLen)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	key := APIKey{
+		Name:      name,
+		TokenHash: hashing.ComputeHash256([]byte(token)),
+		Quota:     quota,
+		Period:    period,
+		Methods:   methods,
+		Created:   k.clock.Unix(),
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if k.db == nil {
+		return "", errAPIKeysDisabled
+	}
+	if _, exists := k.byName[name]; exists {
+		return "", fmt.Errorf("%w: %s", errDuplicateAPIKey, name)
+	}
+	keyBytes, err := k.codec.Marshal(&key)
+	if err != nil {
+		return "", err
+	}
+	if err := k.db.Put([]byte(name), keyBytes); err != nil {
+		return "", err
+	}
+	k.add(key)
+	return token, nil
+}
+
+// revoke the API key named [name]
+func (k *apiKeys) revoke(name string) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if k.db == nil {
+		return errAPIKeysDisabled
+	}
+	status, exists := k.byName[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", errUnknownAPIKey, name)
+	}
+	if err := k.db.Delete([]byte(name)); err != nil {
+		return err
+	}
+	delete(k.byName, name)
+	delete(k.byHash, string(status.TokenHash))
+	return nil
+}
+
+// list the API keys, sorted by name
+func (k *apiKeys) list() []APIKeyStatus {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	keys := make([]APIKeyStatus, 0, len(k.byName))
+	for _, status := range k.byName {
+		keys = append(keys, *status)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// status returns the API key that [token] is, and its usage
+func (k *apiKeys) status(token string) (APIKeyStatus, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	status, exists := k.byHash[string(hashing.ComputeHash256([]byte(token)))]
+	if !exists {
+		return APIKeyStatus{}, errUnknownAPIKey
+	}
+	return *status, nil
+}
+
+// authorize the calls of [methods] with the API key [token], and count them
+// against its quota. If [methods] is empty, the request isn't a JSON-RPC call.
+func (k *apiKeys) authorize(token string, methods []string) error {
+	if token == "" {
+		return errMissingAPIKey
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	status, exists := k.byHash[string(hashing.ComputeHash256([]byte(token)))]
+	if !exists {
+		return errUnknownAPIKey
+	}
+
+	calls := uint64(0)
+	if len(methods) == 0 {
+		if len(status.Methods) != 0 {
+			status.Usage.Refused++
+			return fmt.Errorf("%w: %s", errOutOfScope, "requests that aren't JSON-RPC calls")
+		}
+		calls = 1
+	}
+	for _, method := range methods {
+		method = strings.ToLower(method)
+		if method == usageMethod {
+			continue
+		}
+		if !inScope(status.Methods, method) {
+			status.Usage.Refused += uint64(len(methods))
+			return fmt.Errorf("%w: %s", errOutOfScope, method)
+		}
+		calls++
+	}
+
+	now := k.clock.Time()
+	if now.Sub(status.Usage.PeriodStart) >= status.Period {
+		status.Usage.PeriodStart = now
+		status.Usage.PeriodCalls = 0
+	}
+	if status.Quota != 0 && status.Usage.PeriodCalls+calls > status.Quota {
+		status.Usage.Refused += calls
+		return fmt.Errorf("%w: %d calls per %s", errAPIQuotaExceeded, status.Quota, status.Period)
+	}
+	status.Usage.PeriodCalls += calls
+	status.Usage.Calls += calls
+	return nil
+}
+
+// inScope returns true if [scope] allows calling the lowercase [method]
+func inScope(scope []string, method string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	for _, allowed := range scope {
+		allowed = strings.ToLower(allowed)
+		if allowed == method {
+			return true
+		}
+		if strings.HasSuffix(allowed, ".*") && strings.HasPrefix(method, allowed[:len(allowed)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// require returns [handler], but only serving requests with an API key that
+// may make them
+func (k *apiKeys) require(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls, err := readCalls(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		methods := []string(nil)
+		for _, call := range calls {
+			if method, ok := callMethod(call); ok {
+				methods = append(methods, method)
+			}
+		}
+
+		switch err := k.authorize(APIKeyFromRequest(request), methods); {
+		case err == nil:
+			handler.ServeHTTP(writer, request)
+		case errors.Is(err, errMissingAPIKey), errors.Is(err, errUnknownAPIKey):
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, errOutOfScope):
+			http.Error(writer, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(writer, err.Error(), http.StatusTooManyRequests)
+		}
+	})
+}
+
+// APIKeyFromRequest returns the API key [request] was made with, or the empty
+// string if it wasn't made with one
+func APIKeyFromRequest(request *http.Request) string {
+	header := request.Header.Get(APIKeyHeader)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):])
+}
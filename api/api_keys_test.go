@@ -0,0 +1,116 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestAPIKeys(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	service := &Service{}
+	server := rpc.NewServer()
+	server.RegisterCodec(json2.NewCodec(), "application/json")
+	server.RegisterService(service, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: server}, new(sync.RWMutex), "test", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	handler := s.router.keys.require(restrict(s.router, nil))
+
+	call := func(key, method string) int {
+		buf, err := json2.EncodeClientRequest(method, &Args{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest("POST", "/ext/test", bytes.NewBuffer(buf))
+		request.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			request.Header.Set(APIKeyHeader, "Bearer "+key)
+		}
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, request)
+		return writer.Code
+	}
+
+	if _, err := s.CreateAPIKey("app", 0, 0, nil); err != errAPIKeysDisabled {
+		t.Fatalf("expected %s but got %v", errAPIKeysDisabled, err)
+	}
+	db := memdb.New()
+	if err := s.EnableAPIKeys(db); err != nil {
+		t.Fatal(err)
+	}
+	key, err := s.CreateAPIKey("app", 2, time.Hour, []string{"test.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoped, err := s.CreateAPIKey("scoped", 0, 0, []string{"other.call"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateAPIKey("app", 0, 0, nil); err == nil {
+		t.Fatal("should have refused a duplicate key name")
+	}
+
+	if code := call("", "test.Call"); code != http.StatusUnauthorized {
+		t.Fatalf("expected a call without a key to fail with %d but got %d", http.StatusUnauthorized, code)
+	}
+	if code := call("unknown", "test.Call"); code != http.StatusUnauthorized {
+		t.Fatalf("expected a call with an unknown key to fail with %d but got %d", http.StatusUnauthorized, code)
+	}
+	if code := call(scoped, "test.Call"); code != http.StatusForbidden {
+		t.Fatalf("expected an out of scope call to fail with %d but got %d", http.StatusForbidden, code)
+	}
+	for i := 0; i < 2; i++ {
+		if code := call(key, "test.Call"); code != http.StatusOK {
+			t.Fatalf("expected the call to succeed but got %d", code)
+		}
+	}
+	if code := call(key, "test.Call"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a call over quota to fail with %d but got %d", http.StatusTooManyRequests, code)
+	}
+
+	status, err := s.APIKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Name != "app" || status.Usage.Calls != 2 || status.Usage.Refused != 1 || status.Usage.PeriodCalls != 2 {
+		t.Fatalf("unexpected usage %+v", status.Usage)
+	}
+
+	// The quota is replenished when its period ends
+	s.router.keys.clock.Set(status.Usage.PeriodStart.Add(time.Hour))
+	if code := call(key, "test.Call"); code != http.StatusOK {
+		t.Fatalf("expected the call to succeed in a new period but got %d", code)
+	}
+
+	// Keys survive restarts, but not revocation
+	s.router.keys = newAPIKeys()
+	if err := s.EnableAPIKeys(db); err != nil {
+		t.Fatal(err)
+	}
+	handler = s.router.keys.require(restrict(s.router, nil))
+	if keys := s.APIKeys(); len(keys) != 2 || keys[0].Name != "app" || keys[1].Name != "scoped" {
+		t.Fatalf("should have restored the keys")
+	}
+	if err := s.RevokeAPIKey("app"); err != nil {
+		t.Fatal(err)
+	}
+	if code := call(key, "test.Call"); code != http.StatusUnauthorized {
+		t.Fatalf("expected a call with a revoked key to fail with %d but got %d", http.StatusUnauthorized, code)
+	}
+}
@@ -0,0 +1,184 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// AdminMethods are the API methods that manage the API keys of every
+// downstream application. They're only served if the admin API is enabled,
+// and never on listeners that require an API key.
+var AdminMethods = []string{
+	"auth.newKey",
+	"auth.revokeKey",
+	"auth.listKeys",
+}
+
+// Auth is the API service for managing the API keys that downstream
+// applications call this node's API with
+type Auth struct {
+	log        logging.Logger
+	httpServer *api.Server
+}
+
+// NewService returns a new auth API service
+func NewService(log logging.Logger, httpServer *api.Server) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Auth{log: log, httpServer: httpServer}, "auth")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// APIKey is an API key, without the key itself, and its usage since the node
+// started
+type APIKey struct {
+	Name string `json:"name"`
+
+	// Most calls the key may make each [Period] seconds. 0 means unlimited.
+	Quota  cjson.Uint64 `json:"quota"`
+	Period cjson.Uint64 `json:"period"`
+
+	// Methods the key may call. If empty, it may call every method.
+	Methods []string `json:"methods"`
+
+	Created cjson.Uint64 `json:"created"` // Unix time
+
+	// Calls made with the key, and calls refused because they were out of
+	// scope or over quota
+	Calls   cjson.Uint64 `json:"calls"`
+	Refused cjson.Uint64 `json:"refused"`
+
+	// Calls made in the current period of the key's quota, which started at
+	// the Unix time [PeriodStart]
+	PeriodCalls cjson.Uint64 `json:"periodCalls"`
+	PeriodStart cjson.Uint64 `json:"periodStart"`
+}
+
+func newAPIKey(status api.APIKeyStatus) APIKey {
+	key := APIKey{
+		Name:        status.Name,
+		Quota:       cjson.Uint64(status.Quota),
+		Period:      cjson.Uint64(status.Period / time.Second),
+		Methods:     status.Methods,
+		Created:     cjson.Uint64(status.Created),
+		Calls:       cjson.Uint64(status.Usage.Calls),
+		Refused:     cjson.Uint64(status.Usage.Refused),
+		PeriodCalls: cjson.Uint64(status.Usage.PeriodCalls),
+	}
+	if key.Methods == nil {
+		key.Methods = []string{}
+	}
+	if !status.Usage.PeriodStart.IsZero() {
+		key.PeriodStart = cjson.Uint64(status.Usage.PeriodStart.Unix())
+	}
+	return key
+}
+
+// NewKeyArgs are the arguments for calling NewKey
+type NewKeyArgs struct {
+	Name string `json:"name"`
+
+	// Most calls the key may make each [Period] seconds. If [Quota] is 0, the
+	// key may make unlimited calls. If [Period] is 0, it's an hour.
+	Quota  cjson.Uint64 `json:"quota"`
+	Period cjson.Uint64 `json:"period"`
+
+	// Methods the key may call, such as avm.getBalance, or every method of a
+	// service, such as platform.*. If empty, the key may call every method.
+	Methods []string `json:"methods"`
+}
+
+// NewKeyReply are the results from calling NewKey
+type NewKeyReply struct {
+	// The key, which is sent in the Authorization header of requests, as
+	// "Bearer <key>". It can't be retrieved later.
+	Key string `json:"key"`
+}
+
+// NewKey creates an API key
+func (service *Auth) NewKey(_ *http.Request, args *NewKeyArgs, reply *NewKeyReply) error {
+	service.log.Debug("Auth: NewKey called with Name: %s", args.Name)
+
+	key, err := service.httpServer.CreateAPIKey(args.Name, uint64(args.Quota), time.Duration(args.Period)*time.Second, args.Methods)
+	if err != nil {
+		return err
+	}
+	reply.Key = key
+	return nil
+}
+
+// RevokeKeyArgs are the arguments for calling RevokeKey
+type RevokeKeyArgs struct {
+	Name string `json:"name"`
+}
+
+// RevokeKeyReply are the results from calling RevokeKey
+type RevokeKeyReply struct {
+	Success bool `json:"success"`
+}
+
+// RevokeKey revokes the API key [args.Name]. Requests with it are refused from
+// then on.
+func (service *Auth) RevokeKey(_ *http.Request, args *RevokeKeyArgs, reply *RevokeKeyReply) error {
+	service.log.Debug("Auth: RevokeKey called with Name: %s", args.Name)
+
+	if err := service.httpServer.RevokeAPIKey(args.Name); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ListKeysArgs are the arguments for calling ListKeys
+type ListKeysArgs struct{}
+
+// ListKeysReply are the results from calling ListKeys
+type ListKeysReply struct {
+	Keys []APIKey `json:"keys"`
+}
+
+// ListKeys returns the API keys and their usage, sorted by name
+func (service *Auth) ListKeys(_ *http.Request, _ *ListKeysArgs, reply *ListKeysReply) error {
+	service.log.Debug("Auth: ListKeys called")
+
+	statuses := service.httpServer.APIKeys()
+	reply.Keys = make([]APIKey, len(statuses))
+	for i, status := range statuses {
+		reply.Keys[i] = newAPIKey(status)
+	}
+	return nil
+}
+
+// GetUsageArgs are the arguments for calling GetUsage
+type GetUsageArgs struct{}
+
+// GetUsageReply are the results from calling GetUsage
+type GetUsageReply struct {
+	Key APIKey `json:"key"`
+}
+
+// GetUsage returns the API key the call was made with, and its usage. It can be
+// called with any API key, whatever its scope and quota.
+func (service *Auth) GetUsage(r *http.Request, _ *GetUsageArgs, reply *GetUsageReply) error {
+	service.log.Debug("Auth: GetUsage called")
+
+	status, err := service.httpServer.APIKey(api.APIKeyFromRequest(r))
+	if err != nil {
+		return cjson.UnauthorizedError(err)
+	}
+	reply.Key = newAPIKey(status)
+	return nil
+}
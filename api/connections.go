@@ -0,0 +1,165 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// HTTPConfig tunes the connections the API server accepts
+type HTTPConfig struct {
+	// HTTP2 enables HTTP/2, so that a client can have many requests in flight
+	// on one connection. Over TLS, it's negotiated with ALPN. Without TLS,
+	// clients must use HTTP/2 with prior knowledge (h2c).
+	HTTP2 bool
+
+	// MaxConcurrentStreams is the most requests a client may have in flight on
+	// one HTTP/2 connection
+	MaxConcurrentStreams uint32
+
+	// KeepAlives keeps HTTP/1.1 connections open between requests. If false,
+	// a connection is closed once its response is written.
+	KeepAlives bool
+
+	// IdleTimeout is how long a connection is kept open without a request in
+	// flight
+	IdleTimeout time.Duration
+}
+
+// DefaultHTTPConfig is the HTTPConfig of a server that wasn't configured
+var DefaultHTTPConfig = HTTPConfig{
+	HTTP2:                true,
+	MaxConcurrentStreams: 250,
+	KeepAlives:           true,
+	IdleTimeout:          2 * time.Minute,
+}
+
+// httpMetrics are the metrics of the connections the API server accepts and
+// the requests it serves
+type httpMetrics struct {
+	numConns    prometheus.Counter
+	openConns   prometheus.Gauge
+	numRequests *prometheus.CounterVec
+	inFlight    *prometheus.GaugeVec
+}
+
+func newHTTPMetrics() *httpMetrics {
+	return &httpMetrics{
+		numConns: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "connections",
+			Help:      "Number of connections the API server accepted",
+		}),
+		openConns: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "api",
+			Name:      "open_connections",
+			Help:      "Number of connections to the API server that are open",
+		}),
+		numRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "requests",
+			Help:      "Number of requests the API server received, by HTTP version",
+		}, []string{"protocol"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "api",
+			Name:      "requests_in_flight",
+			Help:      "Number of requests being served, by endpoint",
+		}, []string{"endpoint"}),
+	}
+}
+
+func (m *httpMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.numConns, m.openConns, m.numRequests, m.inFlight}
+}
+
+// countRequests returns [handler], but counting the requests it serves by
+// their HTTP version
+func (m *httpMetrics) countRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		m.numRequests.WithLabelValues(request.Proto).Inc()
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// countedListener counts the connections it accepts, and those of them that
+// are open
+type countedListener struct {
+	net.Listener
+	metrics *httpMetrics
+}
+
+func (l countedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.metrics.numConns.Inc()
+	l.metrics.openConns.Inc()
+	return &countedConn{Conn: conn, metrics: l.metrics}, nil
+}
+
+type countedConn struct {
+	net.Conn
+	metrics *httpMetrics
+	once    sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(c.metrics.openConns.Dec)
+	return c.Conn.Close()
+}
+
+// newHTTPServer returns a server of [handler] configured by [config]. [useTLS]
+// is true if it serves TLS connections.
+func newHTTPServer(config HTTPConfig, handler http.Handler, useTLS bool) (*http.Server, error) {
+	server := &http.Server{
+		Handler:     handler,
+		IdleTimeout: config.IdleTimeout,
+	}
+	server.SetKeepAlivesEnabled(config.KeepAlives)
+
+	if !config.HTTP2 {
+		// A non-nil, empty map keeps HTTP/2 from being negotiated
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return server, nil
+	}
+	h2Server := &http2.Server{
+		MaxConcurrentStreams: config.MaxConcurrentStreams,
+		IdleTimeout:          config.IdleTimeout,
+	}
+	if !useTLS {
+		server.Handler = h2c.NewHandler(handler, h2Server)
+		return server, nil
+	}
+	return server, http2.ConfigureServer(server, h2Server)
+}
+
+// serve [handler] on [address] until the server fails. If [certFile] is
+// non-empty, connections are served over TLS.
+func (s *Server) serve(address, certFile, keyFile string, handler http.Handler) error {
+	metrics := s.router.metrics
+	server, err := newHTTPServer(s.httpConfig, metrics.countRequests(handler), certFile != "")
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	listener = countedListener{Listener: listener, metrics: metrics}
+	if certFile != "" {
+		return server.ServeTLS(listener, certFile, keyFile)
+	}
+	return server.Serve(listener)
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"golang.org/x/net/http2"
+)
+
+func TestHTTP2WithoutTLS(t *testing.T) {
+	metrics := newHTTPMetrics()
+	protocols := make(chan string, 2)
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		protocols <- request.Proto
+	})
+
+	server, err := newHTTPServer(DefaultHTTPConfig, metrics.countRequests(handler), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(countedListener{Listener: listener, metrics: metrics})
+	defer server.Close()
+
+	// Clients with prior knowledge of HTTP/2 multiplex requests on one
+	// connection
+	client := http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	for i := 0; i < 2; i++ {
+		response, err := client.Get("http://" + listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if protocol := <-protocols; protocol != "HTTP/2.0" {
+			t.Fatalf("expected the request to be served over HTTP/2.0 but it was served over %s", protocol)
+		}
+	}
+
+	if conns := testutil.ToFloat64(metrics.numConns); conns != 1 {
+		t.Fatalf("expected the requests to share 1 connection but there were %v", conns)
+	}
+	if open := testutil.ToFloat64(metrics.openConns); open != 1 {
+		t.Fatalf("expected 1 open connection but there were %v", open)
+	}
+	if requests := testutil.ToFloat64(metrics.numRequests.WithLabelValues("HTTP/2.0")); requests != 2 {
+		t.Fatalf("expected 2 HTTP/2.0 requests but there were %v", requests)
+	}
+}
@@ -0,0 +1,238 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	errNoAddress        = errors.New("argument 'address' not given")
+	errUnsupportedChain = errors.New("the faucet only dispenses funds on the X-Chain and the P-Chain")
+	errRateLimited      = errors.New("funds were dispensed to this address too recently")
+)
+
+// WriteMethods are the API methods that issue txs. Read-only API replicas
+// don't serve them.
+var WriteMethods = []string{"faucet.drip"}
+
+// Config describes how the faucet dispenses funds
+type Config struct {
+	// Username and Password of the keystore user that holds the funds
+	Username string
+	Password string
+
+	// AssetID is the ID, or alias, of the asset that is dispensed on the
+	// X-Chain. $AVA is dispensed on the P-Chain.
+	AssetID string
+
+	// Amount of the asset dispensed per request
+	Amount uint64
+
+	// RateLimit is the minimum amount of time between two requests for
+	// the same address
+	RateLimit time.Duration
+}
+
+// Faucet is the API service that dispenses funds on test networks
+type Faucet struct {
+	log        logging.Logger
+	httpServer *api.Server
+	config     Config
+
+	clock timer.Clock
+
+	// lock guards [config] and [lastDrip]. It isn't held while funds are sent,
+	// so requests for different addresses are served concurrently.
+	lock sync.Mutex
+	// lastDrip maps a chain's address to the last time funds were dispensed
+	// to it, or are being dispensed to it
+	lastDrip map[string]time.Time
+}
+
+// NewService returns a new faucet API service, and the faucet it serves
+func NewService(log logging.Logger, httpServer *api.Server, config Config) (*Faucet, *common.HTTPHandler) {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	f := &Faucet{
+		log:        log,
+		httpServer: httpServer,
+		config:     config,
+		lastDrip:   make(map[string]time.Time),
+	}
+	newServer.RegisterService(f, "faucet")
+	return f, &common.HTTPHandler{Handler: newServer}
+}
+
+// SetLimits changes the amount dispensed per request and the minimum time
+// between two requests for the same address
+func (f *Faucet) SetLimits(amount uint64, rateLimit time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config.Amount = amount
+	f.config.RateLimit = rateLimit
+}
+
+// DripArgs are the arguments for calling Drip
+type DripArgs struct {
+	// Chain to dispense funds on, X or P. Defaults to the X-Chain.
+	Chain string `json:"chain"`
+
+	// Address to send the funds to
+	Address string `json:"address"`
+}
+
+// DripReply are the results from calling Drip
+type DripReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// Drip dispenses funds to the provided address
+func (f *Faucet) Drip(_ *http.Request, args *DripArgs, reply *DripReply) error {
+	f.log.Debug("Faucet: Drip called for %s", args.Address)
+
+	if args.Address == "" {
+		return errNoAddress
+	}
+	chain := ""
+	switch args.Chain {
+	case "", "X", "avm":
+		chain = "X"
+	case "P", "platform":
+		chain = "P"
+	default:
+		return errUnsupportedChain
+	}
+	key := chain + "/" + args.Address
+
+	// Reserve the address, so that concurrent requests for it are rate
+	// limited while the funds are sent
+	f.lock.Lock()
+	now := f.clock.Time()
+	f.prune(now)
+	if _, ok := f.lastDrip[key]; ok {
+		f.lock.Unlock()
+		return errRateLimited
+	}
+	f.lastDrip[key] = now
+	config := f.config
+	f.lock.Unlock()
+
+	txID, err := f.send(chain, args.Address, config)
+	if err != nil {
+		// Release the reservation, so the address may request funds again
+		f.lock.Lock()
+		if last, ok := f.lastDrip[key]; ok && last.Equal(now) {
+			delete(f.lastDrip, key)
+		}
+		f.lock.Unlock()
+		return fmt.Errorf("couldn't dispense funds: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// prune removes the addresses that are no longer rate limited
+func (f *Faucet) prune(now time.Time) {
+	for addr, last := range f.lastDrip {
+		if now.Sub(last) >= f.config.RateLimit {
+			delete(f.lastDrip, addr)
+		}
+	}
+}
+
+// send issues a transaction on [chain], the X-Chain or the P-Chain, that sends
+// [config.Amount] from the faucet's user to [address]
+func (f *Faucet) send(chain, address string, config Config) (ids.ID, error) {
+	var (
+		request []byte
+		err     error
+	)
+	switch chain {
+	case "P":
+		request, err = json2.EncodeClientRequest("platform.send", &platformSendArgs{
+			Username: config.Username,
+			Password: config.Password,
+			Amount:   cjson.Uint64(config.Amount),
+			To:       address,
+		})
+	default:
+		request, err = json2.EncodeClientRequest("avm.send", &sendArgs{
+			Username: config.Username,
+			Password: config.Password,
+			Amount:   cjson.Uint64(config.Amount),
+			AssetID:  config.AssetID,
+			To:       address,
+		})
+	}
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	writer := &responseWriter{header: http.Header{}}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if err := f.httpServer.CallChain(writer, chain, "", bytes.NewBuffer(request), headers); err != nil {
+		return ids.ID{}, err
+	}
+
+	reply := sendReply{}
+	if err := json2.DecodeClientResponse(&writer.body, &reply); err != nil {
+		return ids.ID{}, err
+	}
+	return reply.TxID, nil
+}
+
+// sendArgs mirrors the arguments of avm.send
+type sendArgs struct {
+	Username string       `json:"username"`
+	Password string       `json:"password"`
+	Amount   cjson.Uint64 `json:"amount"`
+	AssetID  string       `json:"assetID"`
+	To       string       `json:"to"`
+}
+
+// platformSendArgs mirrors the arguments of platform.send
+type platformSendArgs struct {
+	Username string       `json:"username"`
+	Password string       `json:"password"`
+	Amount   cjson.Uint64 `json:"amount"`
+	To       string       `json:"to"`
+}
+
+// sendReply mirrors the replies of avm.send and platform.send
+type sendReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// responseWriter buffers the response of an internal API call
+type responseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header         { return w.header }
+func (w *responseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *responseWriter) WriteHeader(int)             {}
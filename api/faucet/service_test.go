@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// The RPC server only registers methods whose arguments are exported types
+type (
+	SendArgs         sendArgs
+	PlatformSendArgs platformSendArgs
+	SendReply        sendReply
+)
+
+type testAVM struct{ sent []SendArgs }
+
+func (vm *testAVM) Send(_ *http.Request, args *SendArgs, reply *SendReply) error {
+	vm.sent = append(vm.sent, *args)
+	reply.TxID = ids.Empty.Prefix(uint64(len(vm.sent)))
+	return nil
+}
+
+type testPlatform struct{ sent []PlatformSendArgs }
+
+func (vm *testPlatform) Send(_ *http.Request, args *PlatformSendArgs, reply *SendReply) error {
+	vm.sent = append(vm.sent, *args)
+	reply.TxID = ids.Empty.Prefix(uint64(len(vm.sent)))
+	return nil
+}
+
+func TestDrip(t *testing.T) {
+	server := &api.Server{}
+	server.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	avm := &testAVM{}
+	avmServer := rpc.NewServer()
+	avmServer.RegisterCodec(cjson.NewCodec(), "application/json")
+	avmServer.RegisterService(avm, "avm")
+	if err := server.AddRoute(&common.HTTPHandler{Handler: avmServer}, &sync.RWMutex{}, "bc/X", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &Faucet{
+		log:        logging.NoLog{},
+		httpServer: server,
+		config: Config{
+			Username:  "faucet",
+			Password:  "password",
+			AssetID:   "AVA",
+			Amount:    1000,
+			RateLimit: time.Hour,
+		},
+		lastDrip: make(map[string]time.Time),
+	}
+	now := time.Now()
+	f.clock.Set(now)
+
+	reply := DripReply{}
+	if err := f.Drip(nil, &DripArgs{Address: "X-addr"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.TxID.Equals(ids.Empty.Prefix(1)) {
+		t.Fatalf("Returned the wrong txID")
+	}
+	if len(avm.sent) != 1 {
+		t.Fatalf("Should have sent funds once")
+	}
+	if sent := avm.sent[0]; sent.To != "X-addr" || sent.Amount != 1000 || sent.AssetID != "AVA" || sent.Username != "faucet" {
+		t.Fatalf("Sent funds with the wrong arguments")
+	}
+
+	if err := f.Drip(nil, &DripArgs{Address: "X-addr"}, &reply); err != errRateLimited {
+		t.Fatalf("Should have rate limited the address")
+	}
+	if err := f.Drip(nil, &DripArgs{Chain: "C", Address: "other"}, &reply); err != errUnsupportedChain {
+		t.Fatalf("Should have refused to dispense funds on the C-Chain")
+	}
+
+	// The P-Chain isn't served yet, so the address isn't rate limited
+	if err := f.Drip(nil, &DripArgs{Chain: "P", Address: "addr"}, &reply); err == nil {
+		t.Fatalf("Should have failed to dispense funds on the P-Chain")
+	}
+	platform := &testPlatform{}
+	platformServer := rpc.NewServer()
+	platformServer.RegisterCodec(cjson.NewCodec(), "application/json")
+	platformServer.RegisterService(platform, "platform")
+	if err := server.AddRoute(&common.HTTPHandler{Handler: platformServer}, &sync.RWMutex{}, "bc/P", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Drip(nil, &DripArgs{Chain: "P", Address: "addr"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if sent := platform.sent; len(sent) != 1 || sent[0].To != "addr" || sent[0].Amount != 1000 || sent[0].Username != "faucet" {
+		t.Fatalf("Sent funds on the P-Chain with the wrong arguments")
+	}
+	if err := f.Drip(nil, &DripArgs{Chain: "P", Address: "addr"}, &reply); err != errRateLimited {
+		t.Fatalf("Should have rate limited the address on the P-Chain")
+	}
+
+	f.clock.Set(now.Add(time.Hour))
+	if err := f.Drip(nil, &DripArgs{Address: "X-addr"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(avm.sent) != 2 {
+		t.Fatalf("Should have sent funds again once the rate limit expired")
+	}
+
+	f.SetLimits(10, 2*time.Hour)
+	f.clock.Set(now.Add(2 * time.Hour))
+	if err := f.Drip(nil, &DripArgs{Address: "X-addr"}, &reply); err != errRateLimited {
+		t.Fatalf("Should have rate limited the address with the new limit")
+	}
+	f.clock.Set(now.Add(3 * time.Hour))
+	if err := f.Drip(nil, &DripArgs{Address: "X-addr"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if sent := avm.sent[len(avm.sent)-1]; sent.Amount != 10 {
+		t.Fatalf("Should have dispensed the new amount")
+	}
+}
@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package info implements the public API that lets callers learn, and verify,
+// facts about this node
+package info
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// NonceLen is the length of the nonce a node ID is proven with. It's long
+	// enough that a nonce chosen at random is never repeated, so an old proof
+	// can't be replayed. Every proof is the same length, so it can't be passed
+	// off as any other message signed with the staking key.
+	NonceLen = staking.SignedNonceLen
+)
+
+var (
+	errNoStakingKey     = errors.New("staking is disabled, so this node has no staking key to prove its node ID with")
+	errNotSigner        = errors.New("staking key can't sign")
+	errUnsupportedKey   = errors.New("certificate has an unsupported public key type")
+	errWrongNodeID      = errors.New("certificate is for a different node ID")
+	errInvalidNonceSize = fmt.Errorf("nonce must be %d bytes", NonceLen)
+)
+
+// Info is the API service for facts about this node
+type Info struct {
+	log    logging.Logger
+	nodeID ids.ShortID
+	signer crypto.Signer // nil if staking is disabled
+	cert   []byte
+}
+
+// NewService returns a new info API service. [cert] is this node's staking
+// certificate and key, or nil if staking is disabled.
+func NewService(log logging.Logger, nodeID ids.ShortID, cert *tls.Certificate) (*common.HTTPHandler, error) {
+	service := &Info{
+		log:    log,
+		nodeID: nodeID,
+	}
+	if cert != nil {
+		signer, ok := cert.PrivateKey.(crypto.Signer)
+		if !ok || len(cert.Certificate) == 0 {
+			return nil, errNotSigner
+		}
+		service.signer = signer
+		service.cert = cert.Certificate[0]
+	}
+
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(service, "info")
+	return &common.HTTPHandler{Handler: newServer}, nil
+}
+
+// Digest returns the digest that is signed to prove a node ID with [nonce]
+func Digest(nonce [NonceLen]byte) []byte {
+	return hashing.ComputeHash256(staking.NodeIDProofMessage(nonce))
+}
+
+// VerifyProof returns nil if [sig] is a signature of [nonce] made with the key
+// of [cert], and [cert] is the staking certificate of [nodeID]
+func VerifyProof(nodeID ids.ShortID, nonce [NonceLen]byte, certBytes, sig []byte) error {
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse certificate: %w", err)
+	}
+	certNodeID, err := staking.NodeID(cert.Raw)
+	if err != nil {
+		return err
+	}
+	if !certNodeID.Equals(nodeID) {
+		return fmt.Errorf("%w: %s", errWrongNodeID, certNodeID)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errUnsupportedKey
+	}
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, Digest(nonce), sig); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// ProveNodeIDArgs are the arguments for calling ProveNodeID
+type ProveNodeIDArgs struct {
+	// NonceLen random bytes chosen by the caller, so that the proof can't have
+	// been made before the call
+	Nonce formatting.CB58 `json:"nonce"`
+}
+
+// ProveNodeIDReply are the results from calling ProveNodeID
+type ProveNodeIDReply struct {
+	NodeID      ids.ShortID     `json:"nodeID"`
+	Certificate formatting.CB58 `json:"certificate"` // DER encoded staking certificate
+	Signature   formatting.CB58 `json:"signature"`   // Signature of the nonce
+}
+
+// ProveNodeID signs the caller's nonce with this node's staking key. The
+// signature, and the staking certificate it's verified with, prove that this
+// node controls the key of its node ID, so a delegator can check the node it's
+// about to delegate to is the node it's talking to.
+func (service *Info) ProveNodeID(_ *http.Request, args *ProveNodeIDArgs, reply *ProveNodeIDReply) error {
+	service.log.Debug("Info: ProveNodeID called")
+
+	if service.signer == nil {
+		return errNoStakingKey
+	}
+	if size := len(args.Nonce.Bytes); size != NonceLen {
+		return fmt.Errorf("%w but is %d bytes", errInvalidNonceSize, size)
+	}
+	nonce := [NonceLen]byte{}
+	copy(nonce[:], args.Nonce.Bytes)
+
+	sig, err := service.signer.Sign(rand.Reader, Digest(nonce), crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("couldn't sign nonce: %w", err)
+	}
+	reply.NodeID = service.nodeID
+	reply.Certificate.Bytes = service.cert
+	reply.Signature.Bytes = sig
+	return nil
+}
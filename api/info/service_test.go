@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}
+}
+
+func TestProveNodeID(t *testing.T) {
+	cert := testCertificate(t)
+	nodeID, err := staking.NodeID(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewService(logging.NoLog{}, nodeID, &cert); err != nil {
+		t.Fatal(err)
+	}
+	service := &Info{
+		log:    logging.NoLog{},
+		nodeID: nodeID,
+		signer: cert.PrivateKey.(*rsa.PrivateKey),
+		cert:   cert.Certificate[0],
+	}
+
+	nonce := [NonceLen]byte{}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		t.Fatal(err)
+	}
+	reply := ProveNodeIDReply{}
+	if err := service.ProveNodeID(nil, &ProveNodeIDArgs{Nonce: formatting.CB58{Bytes: nonce[:]}}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.NodeID.Equals(nodeID) {
+		t.Fatalf("expected node ID %s but got %s", nodeID, reply.NodeID)
+	}
+	if err := VerifyProof(nodeID, nonce, reply.Certificate.Bytes, reply.Signature.Bytes); err != nil {
+		t.Fatal(err)
+	}
+
+	// The proof is only valid for the nonce, and node ID, it was made for
+	otherNonce := nonce
+	otherNonce[0]++
+	if err := VerifyProof(nodeID, otherNonce, reply.Certificate.Bytes, reply.Signature.Bytes); err == nil {
+		t.Fatal("proof shouldn't be valid for a different nonce")
+	}
+	otherNodeID := ids.NewShortID([20]byte{1})
+	if err := VerifyProof(otherNodeID, nonce, reply.Certificate.Bytes, reply.Signature.Bytes); !errors.Is(err, errWrongNodeID) {
+		t.Fatalf("expected %s but got %v", errWrongNodeID, err)
+	}
+
+	// Nonces of any other length aren't signed
+	for _, size := range []int{0, NonceLen - 1, NonceLen + 1, 1 << 10} {
+		args := ProveNodeIDArgs{Nonce: formatting.CB58{Bytes: make([]byte, size)}}
+		if err := service.ProveNodeID(nil, &args, &ProveNodeIDReply{}); !errors.Is(err, errInvalidNonceSize) {
+			t.Fatalf("expected %s for a %d byte nonce but got %v", errInvalidNonceSize, size, err)
+		}
+	}
+}
+
+func TestProveNodeIDStakingDisabled(t *testing.T) {
+	service := &Info{log: logging.NoLog{}, nodeID: ids.NewShortID([20]byte{1})}
+	args := ProveNodeIDArgs{Nonce: formatting.CB58{Bytes: make([]byte, NonceLen)}}
+	if err := service.ProveNodeID(nil, &args, &ProveNodeIDReply{}); err != errNoStakingKey {
+		t.Fatalf("expected %s but got %v", errNoStakingKey, err)
+	}
+}
+
+func TestArgsRoundTrip(t *testing.T) {
+	json.TestArgsRoundTrip(t, &Info{})
+}
@@ -15,11 +15,17 @@ import (
 type ChainIPC struct {
 	log    logging.Logger
 	socket mangos.Socket
+	format Format
 }
 
 // Accept delivers a message to the ChainIPC
 func (cipc *ChainIPC) Accept(chainID, containerID ids.ID, container []byte) error {
-	err := cipc.socket.Send(container)
+	msg, err := cipc.format.encode(chainID, containerID, container)
+	if err != nil {
+		cipc.log.Error("%s while trying to encode container %s", err, containerID)
+		return err
+	}
+	err = cipc.socket.Send(msg)
 	if err != nil {
 		cipc.log.Error("%s while trying to send:\n%s", err, formatting.DumpBytes{Bytes: container})
 	}
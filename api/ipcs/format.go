@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+// Format is how a ChainIPC serializes the accepted containers it publishes.
+// Each format is published over its own socket, so consumers of different
+// formats can subscribe to the same chain.
+type Format string
+
+// The formats accepted containers may be published in
+const (
+	// RawFormat publishes the bytes of each container as is. Consumers must
+	// know the codec of the chain's VM to make sense of them.
+	RawFormat Format = "raw"
+
+	// JSONFormat publishes each container in a JSON envelope:
+	//   {"chainID": "...", "containerID": "...", "container": "<CB58 bytes>"}
+	JSONFormat Format = "json"
+
+	// ProtobufFormat publishes each container in a protocol buffers envelope,
+	// whose schema is:
+	//   message Container {
+	//     bytes chain_id = 1;
+	//     bytes container_id = 2;
+	//     bytes container = 3;
+	//   }
+	ProtobufFormat Format = "protobuf"
+)
+
+var errUnknownFormat = errors.New("unknown IPC format")
+
+// parseFormat returns the format named [name]. The raw format is the default.
+func parseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case "":
+		return RawFormat, nil
+	case RawFormat, JSONFormat, ProtobufFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w %q, expected %q, %q or %q", errUnknownFormat, name, RawFormat, JSONFormat, ProtobufFormat)
+	}
+}
+
+// url returns the URL of the socket the containers of [chainID] are published
+// over in this format. The raw format keeps the URL it had before formats
+// could be chosen.
+func (f Format) url(chainID ids.ID) string {
+	if f == RawFormat {
+		return baseURL + chainID.String() + ".ipc"
+	}
+	return baseURL + chainID.String() + "." + string(f) + ".ipc"
+}
+
+// eventName returns the name the socket that publishes in this format is
+// registered under with the event dispatcher
+func (f Format) eventName() string {
+	if f == RawFormat {
+		return "ipc"
+	}
+	return "ipc-" + string(f)
+}
+
+// jsonContainer is the JSON envelope of an accepted container
+type jsonContainer struct {
+	ChainID     ids.ID          `json:"chainID"`
+	ContainerID ids.ID          `json:"containerID"`
+	Container   formatting.CB58 `json:"container"`
+}
+
+// encode returns the message that publishes [container], the container with ID
+// [containerID] accepted by the chain [chainID], in this format
+func (f Format) encode(chainID, containerID ids.ID, container []byte) ([]byte, error) {
+	switch f {
+	case RawFormat:
+		return container, nil
+	case JSONFormat:
+		return json.Marshal(jsonContainer{
+			ChainID:     chainID,
+			ContainerID: containerID,
+			Container:   formatting.CB58{Bytes: container},
+		})
+	case ProtobufFormat:
+		msg := []byte(nil)
+		msg = appendProtobufBytes(msg, 1, chainID.Bytes())
+		msg = appendProtobufBytes(msg, 2, containerID.Bytes())
+		msg = appendProtobufBytes(msg, 3, container)
+		return msg, nil
+	default:
+		return nil, fmt.Errorf("%w %q", errUnknownFormat, f)
+	}
+}
+
+// appendProtobufBytes appends the bytes field [field] of a protocol buffers
+// message, whose value is [value], to [msg]
+func appendProtobufBytes(msg []byte, field uint64, value []byte) []byte {
+	const lengthDelimited = 2 // The wire type of bytes fields
+
+	varint := make([]byte, binary.MaxVarintLen64)
+	msg = append(msg, varint[:binary.PutUvarint(varint, field<<3|lengthDelimited)]...)
+	msg = append(msg, varint[:binary.PutUvarint(varint, uint64(len(value)))]...)
+	return append(msg, value...)
+}
@@ -0,0 +1,64 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestFormats(t *testing.T) {
+	chainID := ids.Empty.Prefix(1)
+	containerID := ids.Empty.Prefix(2)
+	container := []byte{1, 2, 3}
+
+	if format, err := parseFormat(""); err != nil || format != RawFormat {
+		t.Fatalf("expected the raw format by default but got %q, %v", format, err)
+	}
+	if _, err := parseFormat("xml"); !errors.Is(err, errUnknownFormat) {
+		t.Fatalf("expected %s but got %v", errUnknownFormat, err)
+	}
+	if url := RawFormat.url(chainID); url != baseURL+chainID.String()+".ipc" {
+		t.Fatalf("the raw format should keep its URL but got %s", url)
+	}
+	if JSONFormat.url(chainID) == ProtobufFormat.url(chainID) || JSONFormat.eventName() == ProtobufFormat.eventName() {
+		t.Fatal("each format should be published over its own socket")
+	}
+
+	msg, err := RawFormat.encode(chainID, containerID, container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, container) {
+		t.Fatalf("expected the raw container but got %v", msg)
+	}
+
+	msg, err = JSONFormat.encode(chainID, containerID, container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := jsonContainer{}
+	if err := json.Unmarshal(msg, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.ChainID.Equals(chainID) || !decoded.ContainerID.Equals(containerID) || !bytes.Equal(decoded.Container.Bytes, container) {
+		t.Fatalf("wrong JSON envelope %s", msg)
+	}
+
+	msg, err = ProtobufFormat.encode(chainID, containerID, container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append([]byte{0x0a, 32}, chainID.Bytes()...)
+	expected = append(expected, 0x12, 32)
+	expected = append(expected, containerID.Bytes()...)
+	expected = append(expected, 0x1a, 3, 1, 2, 3)
+	if !bytes.Equal(msg, expected) {
+		t.Fatalf("expected the protobuf envelope %v but got %v", expected, msg)
+	}
+}
@@ -30,7 +30,14 @@ type IPCs struct {
 	chainManager chains.Manager
 	httpServer   *api.Server
 	events       *triggers.EventDispatcher
-	chains       map[[32]byte]*ChainIPC
+	chains       map[chainFormat]*ChainIPC
+}
+
+// chainFormat identifies the socket that publishes the containers of a chain
+// in a format
+type chainFormat struct {
+	chainID [32]byte
+	format  Format
 }
 
 // NewService returns a new IPCs API service
@@ -44,7 +51,7 @@ func NewService(log logging.Logger, chainManager chains.Manager, events *trigger
 		chainManager: chainManager,
 		httpServer:   httpServer,
 		events:       events,
-		chains:       map[[32]byte]*ChainIPC{},
+		chains:       map[chainFormat]*ChainIPC{},
 	}, "ipcs")
 	return &common.HTTPHandler{Handler: newServer}
 }
@@ -52,6 +59,10 @@ func NewService(log logging.Logger, chainManager chains.Manager, events *trigger
 // PublishBlockchainArgs are the arguments for calling PublishBlockchain
 type PublishBlockchainArgs struct {
 	BlockchainID string `json:"blockchainID"`
+
+	// How the containers are serialized: raw, json or protobuf. Defaults to
+	// raw.
+	Format string `json:"format"`
 }
 
 // PublishBlockchainReply are the results from calling PublishBlockchain
@@ -59,7 +70,8 @@ type PublishBlockchainReply struct {
 	URL string `json:"url"`
 }
 
-// PublishBlockchain publishes the finalized accepted transactions from the blockchainID over the IPC
+// PublishBlockchain publishes the finalized accepted transactions from the blockchainID over the IPC,
+// serialized in the requested format. Each format is published over its own socket.
 func (ipc *IPCs) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs, reply *PublishBlockchainReply) error {
 	chainID, err := ipc.chainManager.Lookup(args.BlockchainID)
 	if err != nil {
@@ -67,14 +79,18 @@ func (ipc *IPCs) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs,
 		return err
 	}
 
-	chainIDKey := chainID.Key()
-	chainIDStr := chainID.String()
-	url := baseURL + chainIDStr + ".ipc"
+	format, err := parseFormat(args.Format)
+	if err != nil {
+		return err
+	}
+
+	key := chainFormat{chainID: chainID.Key(), format: format}
+	url := format.url(chainID)
 
 	reply.URL = url
 
-	if _, ok := ipc.chains[chainIDKey]; ok {
-		ipc.log.Info("returning existing blockchainID %s", chainIDStr)
+	if _, ok := ipc.chains[key]; ok {
+		ipc.log.Info("returning existing blockchainID %s in format %s", chainID, format)
 		return nil
 	}
 
@@ -93,20 +109,24 @@ func (ipc *IPCs) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs,
 	chainIPC := &ChainIPC{
 		log:    ipc.log,
 		socket: sock,
+		format: format,
 	}
-	if err := ipc.events.RegisterChain(chainID, "ipc", chainIPC); err != nil {
+	if err := ipc.events.RegisterChain(chainID, format.eventName(), chainIPC); err != nil {
 		ipc.log.Error("couldn't register event: %s", err)
 		sock.Close()
 		return err
 	}
 
-	ipc.chains[chainIDKey] = chainIPC
+	ipc.chains[key] = chainIPC
 	return nil
 }
 
 // UnpublishBlockchainArgs are the arguments for calling UnpublishBlockchain
 type UnpublishBlockchainArgs struct {
 	BlockchainID string `json:"blockchainID"`
+
+	// The format the containers are published in. Defaults to raw.
+	Format string `json:"format"`
 }
 
 // UnpublishBlockchainReply are the results from calling UnpublishBlockchain
@@ -122,19 +142,24 @@ func (ipc *IPCs) UnpublishBlockchain(r *http.Request, args *UnpublishBlockchainA
 		return err
 	}
 
-	chainIDKey := chainID.Key()
+	format, err := parseFormat(args.Format)
+	if err != nil {
+		return err
+	}
+
+	key := chainFormat{chainID: chainID.Key(), format: format}
 
-	chain, ok := ipc.chains[chainIDKey]
+	chain, ok := ipc.chains[key]
 	if !ok {
-		return fmt.Errorf("blockchainID not publishing: %s", chainID)
+		return fmt.Errorf("blockchainID not publishing in format %s: %s", format, chainID)
 	}
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		chain.Stop(),
-		ipc.events.DeregisterChain(chainID, "ipc"),
+		ipc.events.DeregisterChain(chainID, format.eventName()),
 	)
-	delete(ipc.chains, chainIDKey)
+	delete(ipc.chains, key)
 
 	reply.Success = true
 	return errs.Err
@@ -0,0 +1,190 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+const (
+	// DefaultUserQuota is the default number of bytes a user may store in the
+	// keystore
+	DefaultUserQuota = 128 * 1024 * 1024
+)
+
+var (
+	// ErrQuotaExceeded is returned when a write would make a user store more
+	// bytes than their quota allows
+	ErrQuotaExceeded = errors.New("keystore quota exceeded")
+)
+
+// usage is the number of bytes a user stores in the keystore. It's shared by
+// all the databases of the user.
+type usage struct {
+	lock  sync.Mutex
+	bytes uint64
+}
+
+// quotaDB rejects writes to the database of a user that would make the user
+// store more bytes than their quota. The size of an entry is the length of its
+// key plus the length of its value.
+type quotaDB struct {
+	database.Database
+	username string
+	quota    uint64 // 0 means unlimited
+	usage    *usage
+}
+
+// size returns the size of the entry stored under [key], or 0 if there isn't
+// one
+func (db *quotaDB) size(key []byte) (uint64, error) {
+	value, err := db.Database.Get(key)
+	switch err {
+	case nil:
+		return uint64(len(key) + len(value)), nil
+	case database.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// reserve returns an error if the user's usage can't change from [removed]
+// bytes to [added] bytes. Assumes [db.usage.lock] is held.
+func (db *quotaDB) reserve(removed, added uint64) error {
+	if db.quota == 0 || added <= removed {
+		return nil
+	}
+	if newUsage := db.usage.bytes - removed + added; newUsage > db.quota {
+		return fmt.Errorf("%w: %s would store %d bytes, but may only store %d", ErrQuotaExceeded, db.username, newUsage, db.quota)
+	}
+	return nil
+}
+
+// update the user's usage from [removed] bytes to [added] bytes. Assumes
+// [db.usage.lock] is held.
+func (db *quotaDB) update(removed, added uint64) {
+	db.usage.bytes = db.usage.bytes - removed + added
+}
+
+// Put implements the Database interface
+func (db *quotaDB) Put(key, value []byte) error {
+	db.usage.lock.Lock()
+	defer db.usage.lock.Unlock()
+
+	removed, err := db.size(key)
+	if err != nil {
+		return err
+	}
+	added := uint64(len(key) + len(value))
+	if err := db.reserve(removed, added); err != nil {
+		return err
+	}
+	if err := db.Database.Put(key, value); err != nil {
+		return err
+	}
+	db.update(removed, added)
+	return nil
+}
+
+// Delete implements the Database interface
+func (db *quotaDB) Delete(key []byte) error {
+	db.usage.lock.Lock()
+	defer db.usage.lock.Unlock()
+
+	removed, err := db.size(key)
+	if err != nil {
+		return err
+	}
+	if err := db.Database.Delete(key); err != nil {
+		return err
+	}
+	db.update(removed, 0)
+	return nil
+}
+
+// NewBatch implements the Database interface
+func (db *quotaDB) NewBatch() database.Batch {
+	return &quotaBatch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+type quotaBatch struct {
+	database.Batch
+	db     *quotaDB
+	writes []keyValue
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// Put implements the Batch interface
+func (b *quotaBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{key: copyBytes(key), value: copyBytes(value)})
+	return b.Batch.Put(key, value)
+}
+
+// Delete implements the Batch interface
+func (b *quotaBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{key: copyBytes(key), delete: true})
+	return b.Batch.Delete(key)
+}
+
+// Write implements the Batch interface
+func (b *quotaBatch) Write() error {
+	b.db.usage.lock.Lock()
+	defer b.db.usage.lock.Unlock()
+
+	// Size of each entry the batch writes, after the batch is written
+	sizes := make(map[string]uint64)
+	removed := uint64(0)
+	for _, kv := range b.writes {
+		if _, seen := sizes[string(kv.key)]; !seen {
+			size, err := b.db.size(kv.key)
+			if err != nil {
+				return err
+			}
+			removed += size
+		}
+		if kv.delete {
+			sizes[string(kv.key)] = 0
+		} else {
+			sizes[string(kv.key)] = uint64(len(kv.key) + len(kv.value))
+		}
+	}
+	added := uint64(0)
+	for _, size := range sizes {
+		added += size
+	}
+
+	if err := b.db.reserve(removed, added); err != nil {
+		return err
+	}
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	b.db.update(removed, added)
+	return nil
+}
+
+// Reset implements the Batch interface
+func (b *quotaBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.Batch.Reset()
+}
+
+func copyBytes(bytes []byte) []byte {
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
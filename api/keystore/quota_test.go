@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestQuota(t *testing.T) {
+	baseDB := memdb.New()
+
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, baseDB)
+	ks.SetQuotas(1024, map[string]uint64{"alice": 0})
+
+	for _, username := range []string{"bob", "alice"} {
+		if err := ks.CreateUser(nil, &CreateUserArgs{
+			Username: username,
+			Password: "launch",
+		}, &CreateUserReply{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("small"), make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("big"), make([]byte, 1024)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Should have errored due to the quota being exceeded, got %v", err)
+	}
+
+	// Overwriting and deleting entries frees space
+	if err := db.Put([]byte("small"), make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete([]byte("small")); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := db.NewBatch()
+	for i := byte(0); i < 8; i++ {
+		if err := batch.Put([]byte{i}, make([]byte, 100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.Write(); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Should have errored due to the quota being exceeded, got %v", err)
+	}
+	if has, err := db.Has([]byte{0}); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Batch that exceeded the quota shouldn't have been written")
+	}
+
+	// Users whose quota is overridden to 0 are unlimited
+	aliceDB, err := ks.GetDatabase(ids.Empty, "alice", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := aliceDB.Put([]byte("big"), make([]byte, 4096)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The usage of existing data is counted after a restart
+	if err := db.Put([]byte("medium"), make([]byte, 600)); err != nil {
+		t.Fatal(err)
+	}
+	restarted := Keystore{}
+	restarted.Initialize(logging.NoLog{}, baseDB)
+	restarted.SetQuotas(1024, nil)
+
+	db, err = restarted.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("medium2"), make([]byte, 600)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Should have errored due to the quota being exceeded, got %v", err)
+	}
+}
+
+func TestQuotaImportUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("big"), make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	exportReply := ExportUserReply{}
+	if err := ks.ExportUser(nil, &ExportUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &exportReply); err != nil {
+		t.Fatal(err)
+	}
+
+	baseDB := memdb.New()
+	newKS := Keystore{}
+	newKS.Initialize(logging.NoLog{}, baseDB)
+	newKS.SetQuotas(1024, nil)
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     exportReply.User,
+	}, &ImportUserReply{}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Should have errored due to the quota being exceeded, got %v", err)
+	}
+
+	// Nothing of a user that failed to be imported is left behind
+	iter := baseDB.NewIterator()
+	defer iter.Release()
+	if iter.Next() {
+		t.Fatalf("Import that exceeded the quota shouldn't have written %x", iter.Key())
+	}
+
+	newKS.SetQuotas(0, nil)
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     exportReply.User,
+	}, &ImportUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newKS.getUserMetadata("bob"); err != nil {
+		t.Fatalf("Imported user's metadata should have been written: %s", err)
+	}
+}
@@ -8,23 +8,33 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/encdb"
 	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/vms/components/codec"
 
 	jsoncodec "github.com/ava-labs/gecko/utils/json"
 )
 
 var (
-	errEmptyUsername = errors.New("username can't be the empty string")
+	usersPrefix        = []byte("users")
+	userMetadataPrefix = []byte("userMetadata")
+	bcsPrefix          = []byte("bcs")
+)
+
+var (
+	errEmptyUsername = jsoncodec.ParseError(errors.New("username can't be the empty string"))
+	errUnknownUser   = errors.New("unknown user")
 )
 
 // KeyValuePair ...
@@ -35,10 +45,34 @@ type KeyValuePair struct {
 
 // UserDB describes the full content of a user
 type UserDB struct {
+	User   `serialize:"true"`
+	Data   []KeyValuePair `serialize:"true"`
+	Params PasswordParams `serialize:"true"`
+}
+
+// legacyUserDB is how users were exported before the password hashing
+// parameters were configurable
+type legacyUserDB struct {
 	User `serialize:"true"`
 	Data []KeyValuePair `serialize:"true"`
 }
 
+// WriteMethods are the API methods that change keystore users. Read-only API
+// replicas don't serve them.
+var WriteMethods = []string{
+	"keystore.createUser",
+	"keystore.importUser",
+	"keystore.deleteUser",
+}
+
+// AdminMethods are the API methods that reveal or remove the users of other
+// people. They're only served if the admin API is enabled.
+var AdminMethods = []string{
+	"keystore.listUsers",
+	"keystore.getUserMetadata",
+	"keystore.deleteUser",
+}
+
 // Keystore is the RPC interface for keystore management
 type Keystore struct {
 	lock sync.Mutex
@@ -46,12 +80,28 @@ type Keystore struct {
 
 	codec codec.Codec
 
+	// The parameters passwords are hashed with
+	params PasswordParams
+
+	// The number of bytes a user may store, unless the user's quota is
+	// overridden. 0 means unlimited.
+	quota uint64
+	// Key: username
+	// Value: The number of bytes the user may store. 0 means unlimited.
+	quotaOverrides map[string]uint64
+	// Key: username
+	// Value: The number of bytes the user stores
+	usages map[string]*usage
+
 	// Key: username
 	// Value: The user with that name
 	users map[string]*User
 
-	// Used to persist users and their data
+	// Used to persist users, their metadata and their data. [db] is the
+	// database the others are prefixes of.
+	db     database.Database
 	userDB database.Database
+	metaDB database.Database
 	bcDB   database.Database
 	//           BaseDB
 	//          /      \
@@ -60,15 +110,114 @@ type Keystore struct {
 	//               Usr     Usr    Usr
 	//            /   |   \
 	//          BID  BID  BID
+
+	// Users whose passwords were recently given correctly
+	sessions sessionCache
+
+	// Used to record when users are created and log in, and to expire
+	// sessions
+	clock timer.Clock
 }
 
 // Initialize the keystore
 func (ks *Keystore) Initialize(log logging.Logger, db database.Database) {
 	ks.log = log
 	ks.codec = codec.NewDefault()
+	ks.params = DefaultPasswordParams
+	ks.quota = DefaultUserQuota
+	ks.quotaOverrides = make(map[string]uint64)
+	ks.usages = make(map[string]*usage)
 	ks.users = make(map[string]*User)
-	ks.userDB = prefixdb.New([]byte("users"), db)
-	ks.bcDB = prefixdb.New([]byte("bcs"), db)
+	ks.db = db
+	ks.userDB = prefixdb.New(usersPrefix, db)
+	ks.metaDB = prefixdb.New(userMetadataPrefix, db)
+	ks.bcDB = prefixdb.New(bcsPrefix, db)
+	if err := ks.sessions.initialize(DefaultSessionCacheSize, DefaultSessionTTL); err != nil {
+		log.Error("couldn't initialize the keystore session cache, so passwords won't be remembered: %s", err)
+	}
+}
+
+// SetPasswordParams sets the parameters passwords are hashed with. The
+// passwords of existing users are re-hashed with them the next time the users
+// provide their passwords.
+func (ks *Keystore) SetPasswordParams(params PasswordParams) error {
+	if err := params.Verify(); err != nil {
+		return err
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.params = params
+	return nil
+}
+
+// SetSessionCache sets the number of users whose correctly given passwords are
+// remembered to [size], and the time they're remembered for to [ttl]. A
+// remembered password is checked without hashing it with argon2id, so repeated
+// calls from the same wallet are cheap. A size of 0 means passwords aren't
+// remembered.
+func (ks *Keystore) SetSessionCache(size int, ttl time.Duration) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if ttl <= 0 {
+		size = 0
+	}
+	ks.sessions.resize(size, ttl)
+}
+
+// SetQuotas sets the number of bytes each user may store to [quota], except
+// for the users in [overrides], which may store the number of bytes they map
+// to. A quota of 0 means unlimited.
+func (ks *Keystore) SetQuotas(quota uint64, overrides map[string]uint64) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.quota = quota
+	ks.quotaOverrides = make(map[string]uint64, len(overrides))
+	for username, userQuota := range overrides {
+		ks.quotaOverrides[username] = userQuota
+	}
+}
+
+// userDatabase returns the database of the user whose name is [username],
+// which enforces the user's quota
+// Assumes [ks.lock] is held
+func (ks *Keystore) userDatabase(username string) (database.Database, error) {
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+
+	usg, exists := ks.usages[username]
+	if !exists {
+		usg = &usage{}
+
+		it := userDB.NewIterator()
+		defer it.Release()
+		for it.Next() {
+			usg.bytes += uint64(len(it.Key()) + len(it.Value()))
+		}
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+		ks.usages[username] = usg
+	}
+
+	return &quotaDB{
+		Database: userDB,
+		username: username,
+		quota:    ks.userQuota(username),
+		usage:    usg,
+	}, nil
+}
+
+// userQuota returns the number of bytes the user whose name is [username] may
+// store. 0 means unlimited.
+// Assumes [ks.lock] is held
+func (ks *Keystore) userQuota(username string) uint64 {
+	if quota, overridden := ks.quotaOverrides[username]; overridden {
+		return quota
+	}
+	return ks.quota
 }
 
 // CreateHandler returns a new service object that can send requests to thisAPI.
@@ -90,12 +239,144 @@ func (ks *Keystore) getUser(username string) (*User, error) {
 	}
 	// The user is not in memory; try the database
 	usrBytes, err := ks.userDB.Get([]byte(username))
-	if err != nil { // Most likely bc user doesn't exist in database
+	if err == database.ErrNotFound {
+		return nil, jsoncodec.NotFoundError(fmt.Errorf("%w: %s", errUnknownUser, username))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ks.unmarshalUser(usrBytes)
+}
+
+// unmarshalUser parses a user, which may have been stored before the password
+// hashing parameters were configurable
+func (ks *Keystore) unmarshalUser(usrBytes []byte) (*User, error) {
+	stored := storedUser{}
+	if err := ks.codec.Unmarshal(usrBytes, &stored); err == nil {
+		stored.User.Params = stored.Params
+		return &stored.User, nil
+	}
+
+	usr := &User{}
+	if err := ks.codec.Unmarshal(usrBytes, usr); err != nil {
+		return nil, err
+	}
+	usr.Params = legacyPasswordParams
+	return usr, nil
+}
+
+// marshalUser returns the bytes [usr] is persisted as
+func (ks *Keystore) marshalUser(usr *User) ([]byte, error) {
+	return ks.codec.Marshal(&storedUser{
+		User:   *usr,
+		Params: usr.Params,
+	})
+}
+
+// putUser persists [usr] as the user whose name is [username]
+func (ks *Keystore) putUser(username string, usr *User) error {
+	usrBytes, err := ks.marshalUser(usr)
+	if err != nil {
+		return err
+	}
+
+	if err := ks.userDB.Put([]byte(username), usrBytes); err != nil {
+		return err
+	}
+	ks.users[username] = usr
+	return nil
+}
+
+// checkPassword returns true if [password] is the password of [usr]. If so,
+// and the password wasn't hashed with the current parameters, it is re-hashed
+// with them. A password that was recently given correctly is remembered, and
+// isn't hashed again.
+func (ks *Keystore) checkPassword(username string, usr *User, password string) bool {
+	now := ks.clock.Time()
+	if ks.sessions.check(username, password, now) {
+		ks.recordLogin(username)
+		return true
+	}
+	if !usr.CheckPassword(password) {
+		return false
+	}
+	ks.sessions.put(username, password, now)
+	ks.recordLogin(username)
+	if usr.Params == ks.params {
+		return true
+	}
+
+	rehashed := &User{}
+	if err := rehashed.Initialize(password, ks.params); err != nil {
+		ks.log.Error("couldn't re-hash the password of %s: %s", username, err)
+		return true
+	}
+	if err := ks.putUser(username, rehashed); err != nil {
+		ks.log.Error("couldn't store the re-hashed password of %s: %s", username, err)
+		return true
+	}
+	*usr = *rehashed
+	ks.log.Debug("re-hashed the password of %s", username)
+	return true
+}
+
+// getUserMetadata returns the metadata of the user whose name is [username]. If
+// none was recorded, the metadata is empty.
+func (ks *Keystore) getUserMetadata(username string) (*UserMetadata, error) {
+	meta := &UserMetadata{}
+	metaBytes, err := ks.metaDB.Get([]byte(username))
+	switch err {
+	case nil:
+		return meta, ks.codec.Unmarshal(metaBytes, meta)
+	case database.ErrNotFound:
+		return meta, nil
+	default:
 		return nil, err
 	}
+}
 
-	usr = &User{}
-	return usr, ks.codec.Unmarshal(usrBytes, usr)
+// putUserMetadata persists [meta] as the metadata of the user whose name is
+// [username]
+func (ks *Keystore) putUserMetadata(username string, meta *UserMetadata) error {
+	metaBytes, err := ks.codec.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return ks.metaDB.Put([]byte(username), metaBytes)
+}
+
+// recordCreation records that the user whose name is [username] was created
+// now
+func (ks *Keystore) recordCreation(username string) error {
+	return ks.putUserMetadata(username, ks.creationMetadata())
+}
+
+// creationMetadata returns the metadata of a user created now
+func (ks *Keystore) creationMetadata() *UserMetadata {
+	now := ks.clock.Time().Unix()
+	return &UserMetadata{
+		CreatedAt: now,
+		LastLogin: now,
+	}
+}
+
+// recordLogin records that the password of the user whose name is [username]
+// was given correctly now. Failing to record it doesn't fail the login.
+func (ks *Keystore) recordLogin(username string) {
+	meta, err := ks.getUserMetadata(username)
+	if err != nil {
+		ks.log.Error("couldn't get the metadata of %s: %s", username, err)
+		return
+	}
+	now := ks.clock.Time().Unix()
+	if meta.LastLogin == now {
+		return
+	}
+	meta.LastLogin = now
+	if err := ks.putUserMetadata(username, meta); err != nil {
+		ks.log.Error("couldn't record the login of %s: %s", username, err)
+	}
 }
 
 // CreateUserArgs are arguments for passing into CreateUser requests
@@ -120,23 +401,20 @@ func (ks *Keystore) CreateUser(_ *http.Request, args *CreateUserArgs, reply *Cre
 		return errEmptyUsername
 	}
 	if usr, err := ks.getUser(args.Username); err == nil || usr != nil {
-		return fmt.Errorf("user already exists: %s", args.Username)
+		return jsoncodec.ConflictError(fmt.Errorf("user already exists: %s", args.Username))
 	}
 
 	usr := &User{}
-	if err := usr.Initialize(args.Password); err != nil {
+	if err := usr.Initialize(args.Password, ks.params); err != nil {
 		return err
 	}
 
-	usrBytes, err := ks.codec.Marshal(usr)
-	if err != nil {
+	if err := ks.putUser(args.Username, usr); err != nil {
 		return err
 	}
-
-	if err := ks.userDB.Put([]byte(args.Username), usrBytes); err != nil {
+	if err := ks.recordCreation(args.Username); err != nil {
 		return err
 	}
-	ks.users[args.Username] = usr
 	reply.Success = true
 	return nil
 }
@@ -149,7 +427,8 @@ type ListUsersReply struct {
 	Users []string `json:"users"`
 }
 
-// ListUsers lists all the registered usernames
+// ListUsers lists all the registered usernames. It's only served if the admin
+// API is enabled.
 func (ks *Keystore) ListUsers(_ *http.Request, args *ListUsersArgs, reply *ListUsersReply) error {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
@@ -166,6 +445,94 @@ func (ks *Keystore) ListUsers(_ *http.Request, args *ListUsersArgs, reply *ListU
 	return it.Error()
 }
 
+// GetUserMetadataArgs are the arguments to GetUserMetadata
+type GetUserMetadataArgs struct {
+	Username string `json:"username"`
+}
+
+// GetUserMetadataReply is the reply from GetUserMetadata
+type GetUserMetadataReply struct {
+	// Unix times the user was created and last logged in. 0 if unknown.
+	CreatedAt jsoncodec.Uint64 `json:"createdAt"`
+	LastLogin jsoncodec.Uint64 `json:"lastLogin"`
+}
+
+// GetUserMetadata returns when a user was created and when the user's password
+// was last given correctly. It's only served if the admin API is enabled.
+func (ks *Keystore) GetUserMetadata(_ *http.Request, args *GetUserMetadataArgs, reply *GetUserMetadataReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("GetUserMetadata called for %s", args.Username)
+
+	if _, err := ks.getUser(args.Username); err != nil {
+		return err
+	}
+	meta, err := ks.getUserMetadata(args.Username)
+	if err != nil {
+		return err
+	}
+	reply.CreatedAt = jsoncodec.Uint64(meta.CreatedAt)
+	reply.LastLogin = jsoncodec.Uint64(meta.LastLogin)
+	return nil
+}
+
+// DeleteUserArgs are the arguments to DeleteUser
+type DeleteUserArgs struct {
+	Username string `json:"username"`
+}
+
+// DeleteUserReply is the reply from DeleteUser
+type DeleteUserReply struct {
+	Success bool `json:"success"`
+}
+
+// DeleteUser removes a user and all of the user's data. The user's password
+// isn't needed, so operators can purge stale users. It's only served if the
+// admin API is enabled.
+func (ks *Keystore) DeleteUser(_ *http.Request, args *DeleteUserArgs, reply *DeleteUserReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("DeleteUser called for %s", args.Username)
+
+	if _, err := ks.getUser(args.Username); err != nil {
+		return err
+	}
+
+	// The user's data is deleted first, so a user that was only partly
+	// deleted can be deleted again
+	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
+	batch := userDB.NewBatch()
+	it := userDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	if err := ks.metaDB.Delete([]byte(args.Username)); err != nil {
+		return err
+	}
+	if err := ks.userDB.Delete([]byte(args.Username)); err != nil {
+		return err
+	}
+	delete(ks.users, args.Username)
+	delete(ks.usages, args.Username)
+	ks.sessions.evict(args.Username)
+
+	ks.log.Info("deleted keystore user %s", args.Username)
+	reply.Success = true
+	return nil
+}
+
 // ExportUserArgs are the arguments to ExportUser
 type ExportUserArgs struct {
 	Username string `json:"username"`
@@ -188,14 +555,15 @@ func (ks *Keystore) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Exp
 	if err != nil {
 		return err
 	}
-	if !usr.CheckPassword(args.Password) {
-		return fmt.Errorf("incorrect password for %s", args.Username)
+	if !ks.checkPassword(args.Username, usr, args.Password) {
+		return jsoncodec.UnauthorizedError(fmt.Errorf("incorrect password for %s", args.Username))
 	}
 
 	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
 
 	userData := UserDB{
-		User: *usr,
+		User:   *usr,
+		Params: usr.Params,
 	}
 
 	it := userDB.NewIterator()
@@ -239,40 +607,92 @@ func (ks *Keystore) ImportUser(r *http.Request, args *ImportUserArgs, reply *Imp
 	ks.log.Verbo("ImportUser called for %s", args.Username)
 
 	if usr, err := ks.getUser(args.Username); err == nil || usr != nil {
-		return fmt.Errorf("user already exists: %s", args.Username)
+		return jsoncodec.ConflictError(fmt.Errorf("user already exists: %s", args.Username))
 	}
 
 	cb58 := formatting.CB58{}
 	if err := cb58.FromString(args.User); err != nil {
-		return err
+		return jsoncodec.ParseError(err)
 	}
 
-	userData := UserDB{}
-	if err := ks.codec.Unmarshal(cb58.Bytes, &userData); err != nil {
-		return err
+	userData, err := ks.unmarshalUserDB(cb58.Bytes)
+	if err != nil {
+		return jsoncodec.ParseError(err)
+	}
+	usr := &userData.User
+	if !usr.CheckPassword(args.Password) {
+		return jsoncodec.UnauthorizedError(fmt.Errorf("incorrect password for %s", args.Username))
+	}
+	if usr.Params != ks.params {
+		if err := usr.Initialize(args.Password, ks.params); err != nil {
+			return err
+		}
 	}
 
-	usrBytes, err := ks.codec.Marshal(&userData.User)
+	// The user, their metadata and their data are written in one batch, so
+	// that an import that fails leaves none of them behind
+	vdb := versiondb.New(ks.db)
+	defer vdb.Abort()
+
+	usrBytes, err := ks.marshalUser(usr)
 	if err != nil {
 		return err
 	}
-
-	// TODO: Should add batching to prevent creating a user without importing
-	// the account
-	if err := ks.userDB.Put([]byte(args.Username), usrBytes); err != nil {
+	if err := prefixdb.New(usersPrefix, vdb).Put([]byte(args.Username), usrBytes); err != nil {
+		return err
+	}
+	metaBytes, err := ks.codec.Marshal(ks.creationMetadata())
+	if err != nil {
+		return err
+	}
+	if err := prefixdb.New(userMetadataPrefix, vdb).Put([]byte(args.Username), metaBytes); err != nil {
 		return err
 	}
-	ks.users[args.Username] = &userData.User
-
-	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
-	batch := userDB.NewBatch()
 
+	dataDB := prefixdb.New([]byte(args.Username), prefixdb.New(bcsPrefix, vdb))
+	dataLen := uint64(0)
 	for _, kvp := range userData.Data {
-		batch.Put(kvp.Key, kvp.Value)
+		if err := dataDB.Put(kvp.Key, kvp.Value); err != nil {
+			return err
+		}
+		dataLen += uint64(len(kvp.Key) + len(kvp.Value))
+	}
+	if quota := ks.userQuota(args.Username); quota != 0 && dataLen > quota {
+		return fmt.Errorf("%w: %s would store %d bytes, but may only store %d", ErrQuotaExceeded, args.Username, dataLen, quota)
 	}
 
+	batch, err := vdb.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	ks.users[args.Username] = usr
+	delete(ks.usages, args.Username)
 	reply.Success = true
-	return batch.Write()
+	return nil
+}
+
+// unmarshalUserDB parses an exported user, which may have been exported before
+// the password hashing parameters were configurable
+func (ks *Keystore) unmarshalUserDB(userBytes []byte) (*UserDB, error) {
+	userData := &UserDB{}
+	if err := ks.codec.Unmarshal(userBytes, userData); err == nil {
+		userData.User.Params = userData.Params
+		return userData, nil
+	}
+
+	legacy := legacyUserDB{}
+	if err := ks.codec.Unmarshal(userBytes, &legacy); err != nil {
+		return nil, err
+	}
+	legacy.User.Params = legacyPasswordParams
+	return &UserDB{
+		User:   legacy.User,
+		Data:   legacy.Data,
+		Params: legacyPasswordParams,
+	}, nil
 }
 
 // NewBlockchainKeyStore ...
@@ -292,11 +712,14 @@ func (ks *Keystore) GetDatabase(bID ids.ID, username, password string) (database
 	if err != nil {
 		return nil, err
 	}
-	if !usr.CheckPassword(password) {
-		return nil, fmt.Errorf("incorrect password for user '%s'", username)
+	if !ks.checkPassword(username, usr, password) {
+		return nil, jsoncodec.UnauthorizedError(fmt.Errorf("incorrect password for user '%s'", username))
 	}
 
-	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	userDB, err := ks.userDatabase(username)
+	if err != nil {
+		return nil, err
+	}
 	bcDB := prefixdb.NewNested(bID.Bytes(), userDB)
 	encDB, err := encdb.New([]byte(password), bcDB)
 
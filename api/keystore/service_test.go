@@ -6,10 +6,14 @@ package keystore
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
+
+	jsoncodec "github.com/ava-labs/gecko/utils/json"
 )
 
 func TestServiceListNoUsers(t *testing.T) {
@@ -84,6 +88,36 @@ func TestServiceCreateDuplicate(t *testing.T) {
 	}
 }
 
+func TestServiceErrorCodes(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{})
+	if code := jsoncodec.ErrorCode(err); code != jsoncodec.CodeConflict {
+		t.Fatalf("expected code %d but got %d", jsoncodec.CodeConflict, code)
+	}
+
+	_, err = ks.GetDatabase(ids.Empty, "bob", "launch!")
+	if code := jsoncodec.ErrorCode(err); code != jsoncodec.CodeUnauthorized {
+		t.Fatalf("expected code %d but got %d", jsoncodec.CodeUnauthorized, code)
+	}
+
+	_, err = ks.GetDatabase(ids.Empty, "alice", "launch")
+	if code := jsoncodec.ErrorCode(err); code != jsoncodec.CodeNotFound {
+		t.Fatalf("expected code %d but got %d", jsoncodec.CodeNotFound, code)
+	}
+}
+
 func TestServiceCreateUserNoName(t *testing.T) {
 	ks := Keystore{}
 	ks.Initialize(logging.NoLog{}, memdb.New())
@@ -200,3 +234,209 @@ func TestServiceExportImport(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceRehashLegacyUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	// Store a user the way users were stored before the password hashing
+	// parameters were configurable
+	legacy := User{}
+	if err := legacy.Initialize("launch", legacyPasswordParams); err != nil {
+		t.Fatal(err)
+	}
+	legacyBytes, err := ks.codec.Marshal(&legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.userDB.Put([]byte("bob"), legacyBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	params := PasswordParams{Time: 2, Memory: 8 * 1024, Threads: 1}
+	if err := ks.SetPasswordParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch!"); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+	if usr, err := ks.getUser("bob"); err != nil {
+		t.Fatal(err)
+	} else if usr.Params != legacyPasswordParams {
+		t.Fatalf("Password shouldn't have been re-hashed after a failed login")
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The re-hashed user should have been persisted
+	usrBytes, err := ks.userDB.Get([]byte("bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	usr, err := ks.unmarshalUser(usrBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usr.Params != params {
+		t.Fatalf("Password should have been re-hashed with %+v but was hashed with %+v", params, usr.Params)
+	}
+	if !usr.CheckPassword("launch") {
+		t.Fatalf("Re-hashed password should have verified")
+	}
+}
+
+func TestServiceImportLegacyUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	legacy := legacyUserDB{}
+	if err := legacy.User.Initialize("launch", legacyPasswordParams); err != nil {
+		t.Fatal(err)
+	}
+	legacyBytes, err := ks.codec.Marshal(&legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb58 := formatting.CB58{Bytes: legacyBytes}
+
+	if err := ks.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch!",
+		User:     cb58.String(),
+	}, &ImportUserReply{}); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+
+	if err := ks.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     cb58.String(),
+	}, &ImportUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if usr, err := ks.getUser("bob"); err != nil {
+		t.Fatal(err)
+	} else if usr.Params != DefaultPasswordParams {
+		t.Fatalf("Imported user's password should have been re-hashed with the default parameters")
+	}
+}
+
+func TestServiceSetPasswordParams(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	for _, params := range []PasswordParams{
+		{Time: 0, Memory: 64 * 1024, Threads: 4},
+		{Time: 1, Memory: 0, Threads: 4},
+		{Time: 1, Memory: 64 * 1024, Threads: 0},
+		{Time: MaxPasswordTime + 1, Memory: 64 * 1024, Threads: 4},
+		{Time: 1, Memory: MaxPasswordMemory + 1, Threads: 4},
+		{Time: 1, Memory: 16, Threads: 4},
+	} {
+		if err := ks.SetPasswordParams(params); err == nil {
+			t.Fatalf("Should have errored due to invalid parameters %+v", params)
+		}
+	}
+}
+
+func TestServiceUserMetadata(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	ks.clock.Set(time.Unix(1000, 0))
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ks.clock.Set(time.Unix(2000, 0))
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "wrong"); err == nil {
+		t.Fatal("should have failed with the wrong password")
+	}
+
+	reply := GetUserMetadataReply{}
+	if err := ks.GetUserMetadata(nil, &GetUserMetadataArgs{Username: "bob"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.CreatedAt != 1000 || reply.LastLogin != 1000 {
+		t.Fatalf("expected bob to be created and to have logged in at 1000 but got %d and %d", reply.CreatedAt, reply.LastLogin)
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.GetUserMetadata(nil, &GetUserMetadataArgs{Username: "bob"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.CreatedAt != 1000 || reply.LastLogin != 2000 {
+		t.Fatalf("expected bob to be created at 1000 and to have logged in at 2000 but got %d and %d", reply.CreatedAt, reply.LastLogin)
+	}
+
+	if err := ks.GetUserMetadata(nil, &GetUserMetadataArgs{Username: "alice"}, &reply); err == nil {
+		t.Fatal("should have failed to get the metadata of an unknown user")
+	}
+}
+
+func TestServiceDeleteUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := DeleteUserReply{}
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{Username: "bob"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatal("bob should have been deleted")
+	}
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{Username: "bob"}, &reply); err == nil {
+		t.Fatal("shouldn't be able to delete bob twice")
+	}
+
+	users := ListUsersReply{}
+	if err := ks.ListUsers(nil, &ListUsersArgs{}, &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Users) != 0 {
+		t.Fatalf("no users should remain but there are %v", users.Users)
+	}
+
+	// A new user with the same name doesn't inherit the deleted user's data
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	db, err = ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("the deleted user's data should have been deleted")
+	}
+}
+
+func TestServiceArgsRoundTrip(t *testing.T) {
+	jsoncodec.TestArgsRoundTrip(t, &Keystore{})
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"time"
+)
+
+const (
+	// DefaultSessionCacheSize is the default number of users whose correctly
+	// given passwords are remembered
+	DefaultSessionCacheSize = 256
+
+	// DefaultSessionTTL is the default time a correctly given password is
+	// remembered for
+	DefaultSessionTTL = 5 * time.Minute
+)
+
+// session remembers that a user's password was given correctly, so the
+// password isn't hashed with argon2id again each time it's given
+type session struct {
+	username string
+	// HMAC of the password, keyed with the cache's secret. Zeroed when the
+	// session is evicted.
+	key    [sha256.Size]byte
+	expiry time.Time
+}
+
+// sessionCache is a bounded cache of sessions that expire after a TTL. When
+// the cache is full, the least recently used session is evicted. It isn't
+// safe for concurrent use.
+type sessionCache struct {
+	// Key the passwords are HMACed with. Generated when the cache is
+	// initialized, so remembered passwords can't be checked against outside
+	// of this process.
+	secret [sha256.Size]byte
+
+	size int           // 0 means sessions aren't remembered
+	ttl  time.Duration // How long a session is remembered for
+
+	sessions map[string]*list.Element // username -> session
+	order    *list.List               // Least recently used first
+}
+
+// initialize the cache to remember at most [size] sessions, each for [ttl].
+// If the secret can't be generated, sessions aren't remembered.
+func (c *sessionCache) initialize(size int, ttl time.Duration) error {
+	c.sessions = make(map[string]*list.Element)
+	c.order = list.New()
+	if _, err := rand.Read(c.secret[:]); err != nil {
+		return err
+	}
+	c.resize(size, ttl)
+	return nil
+}
+
+// resize the cache to remember at most [size] sessions, each for [ttl].
+// Sessions beyond the new size are evicted.
+func (c *sessionCache) resize(size int, ttl time.Duration) {
+	c.size = size
+	c.ttl = ttl
+	for c.order.Len() > c.size {
+		c.evictElement(c.order.Front())
+	}
+}
+
+// check returns true if [password] was given correctly for [username] in a
+// session that hasn't expired at [now]
+func (c *sessionCache) check(username, password string, now time.Time) bool {
+	e, exists := c.sessions[username]
+	if !exists {
+		return false
+	}
+	s := e.Value.(*session)
+	if !now.Before(s.expiry) {
+		c.evictElement(e)
+		return false
+	}
+	key := c.hash(password)
+	defer zero(key[:])
+	if !hmac.Equal(key[:], s.key[:]) {
+		return false
+	}
+	c.order.MoveToBack(e)
+	return true
+}
+
+// put remembers that [password] was given correctly for [username] at [now]
+func (c *sessionCache) put(username, password string, now time.Time) {
+	if c.size <= 0 {
+		return
+	}
+	c.evict(username)
+	for c.order.Len() >= c.size {
+		c.evictElement(c.order.Front())
+	}
+	c.sessions[username] = c.order.PushBack(&session{
+		username: username,
+		key:      c.hash(password),
+		expiry:   now.Add(c.ttl),
+	})
+}
+
+// evict the session of [username], if there is one
+func (c *sessionCache) evict(username string) {
+	if e, exists := c.sessions[username]; exists {
+		c.evictElement(e)
+	}
+}
+
+// flush evicts every session
+func (c *sessionCache) flush() {
+	for c.order.Len() > 0 {
+		c.evictElement(c.order.Front())
+	}
+}
+
+func (c *sessionCache) evictElement(e *list.Element) {
+	s := c.order.Remove(e).(*session)
+	delete(c.sessions, s.username)
+	zero(s.key[:])
+}
+
+func (c *sessionCache) hash(password string) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, c.secret[:])
+	mac.Write([]byte(password))
+	key := [sha256.Size]byte{}
+	mac.Sum(key[:0])
+	return key
+}
+
+// zero overwrites [b] so key material doesn't outlive its use
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
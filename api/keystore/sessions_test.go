@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestSessionCache(t *testing.T) {
+	c := sessionCache{}
+	if err := c.initialize(2, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+
+	if c.check("alice", "pw", now) {
+		t.Fatal("an unknown session shouldn't be remembered")
+	}
+	c.put("alice", "pw", now)
+	if !c.check("alice", "pw", now) {
+		t.Fatal("the session should have been remembered")
+	}
+	if c.check("alice", "wrong", now) {
+		t.Fatal("a wrong password shouldn't match the session")
+	}
+
+	// Sessions expire after the TTL, and their keys are zeroed
+	s := c.sessions["alice"].Value.(*session)
+	if c.check("alice", "pw", now.Add(time.Minute)) {
+		t.Fatal("the session should have expired")
+	}
+	if s.key != [len(s.key)]byte{} {
+		t.Fatal("the key of an evicted session should have been zeroed")
+	}
+	if _, exists := c.sessions["alice"]; exists {
+		t.Fatal("an expired session should have been evicted")
+	}
+
+	// The least recently used session is evicted when the cache is full
+	c.put("alice", "pw", now)
+	c.put("bob", "pw", now)
+	c.check("alice", "pw", now)
+	c.put("carol", "pw", now)
+	if c.check("bob", "pw", now) {
+		t.Fatal("the least recently used session should have been evicted")
+	}
+	if !c.check("alice", "pw", now) || !c.check("carol", "pw", now) {
+		t.Fatal("the most recently used sessions should have been kept")
+	}
+
+	c.resize(0, time.Minute)
+	if c.order.Len() != 0 || len(c.sessions) != 0 {
+		t.Fatal("shrinking the cache should have evicted its sessions")
+	}
+	c.put("alice", "pw", now)
+	if c.check("alice", "pw", now) {
+		t.Fatal("a cache of size 0 shouldn't remember sessions")
+	}
+}
+
+func TestServiceSessions(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	ks.clock.Set(time.Unix(1000, 0))
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{Username: "bob", Password: "launch"}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err != nil {
+		t.Fatal(err)
+	}
+	if !ks.sessions.check("bob", "launch", ks.clock.Time()) {
+		t.Fatal("the password should have been remembered")
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "wrong"); err == nil {
+		t.Fatal("a wrong password shouldn't be accepted")
+	}
+
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{Username: "bob"}, &DeleteUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := ks.sessions.sessions["bob"]; exists {
+		t.Fatal("deleting a user should have evicted the user's session")
+	}
+	if err := ks.CreateUser(nil, &CreateUserArgs{Username: "bob", Password: "other"}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err == nil {
+		t.Fatal("the deleted user's password shouldn't be accepted")
+	}
+}
@@ -6,30 +6,126 @@ package keystore
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
 
 	"golang.org/x/crypto/argon2"
 )
 
+const (
+	// MaxPasswordTime is the largest number of passes over memory allowed
+	// when hashing a password
+	MaxPasswordTime = 64
+
+	// MaxPasswordMemory is the most memory, in KiB, allowed to be used when
+	// hashing a password
+	MaxPasswordMemory = 4 * 1024 * 1024
+
+	// MaxPasswordThreads is the most threads allowed to be used when hashing a
+	// password
+	MaxPasswordThreads = 64
+)
+
+var (
+	// DefaultPasswordParams are the parameters passwords are hashed with
+	// unless the operator configures others
+	DefaultPasswordParams = PasswordParams{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	// legacyPasswordParams are the parameters the passwords of users that
+	// were stored before the parameters were configurable were hashed with
+	legacyPasswordParams = PasswordParams{
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	errZeroPasswordParam    = errors.New("password hashing parameters must be positive")
+	errPasswordTimeTooLarge = errors.New("password hashing time is too large")
+	errPasswordMemTooLarge  = errors.New("password hashing memory is too large")
+	errPasswordMemTooSmall  = errors.New("password hashing memory must be at least 8 KiB per thread")
+	errTooManyThreads       = errors.New("password hashing uses too many threads")
+)
+
+// PasswordParams are the argon2id parameters a password is hashed with
+type PasswordParams struct {
+	Time    uint32 `serialize:"true" json:"time"`    // Number of passes over memory
+	Memory  uint32 `serialize:"true" json:"memory"`  // KiB of memory
+	Threads uint8  `serialize:"true" json:"threads"` // Degree of parallelism
+}
+
+// Verify returns nil iff these parameters can be used to hash passwords
+func (p PasswordParams) Verify() error {
+	switch {
+	case p.Time == 0 || p.Memory == 0 || p.Threads == 0:
+		return errZeroPasswordParam
+	case p.Time > MaxPasswordTime:
+		return errPasswordTimeTooLarge
+	case p.Memory > MaxPasswordMemory:
+		return errPasswordMemTooLarge
+	case p.Memory < 8*uint32(p.Threads):
+		return errPasswordMemTooSmall
+	case p.Threads > MaxPasswordThreads:
+		return errTooManyThreads
+	default:
+		return nil
+	}
+}
+
 // User describes a user of the keystore
 type User struct {
 	Password [32]byte `serialize:"true"` // The salted, hashed password
 	Salt     [16]byte `serialize:"true"` // The salt
+
+	// Params the password was hashed with. They're serialized after the user
+	// so that users serialized before the parameters were configurable can
+	// still be parsed.
+	Params PasswordParams
 }
 
-// Initialize ...
-func (usr *User) Initialize(password string) error {
+// UserMetadata is what the keystore records about the use of a user
+type UserMetadata struct {
+	// Unix time the user was created or imported. 0 if the user was created
+	// before the keystore recorded it.
+	CreatedAt int64 `serialize:"true"`
+
+	// Unix time the user's password was last given correctly. 0 if it hasn't
+	// been since the keystore started recording it.
+	LastLogin int64 `serialize:"true"`
+}
+
+// storedUser is how a user is serialized
+type storedUser struct {
+	User   `serialize:"true"`
+	Params PasswordParams `serialize:"true"`
+}
+
+// Initialize this user with [password], hashed with [params]
+func (usr *User) Initialize(password string, params PasswordParams) error {
+	if err := params.Verify(); err != nil {
+		return err
+	}
 	_, err := rand.Read(usr.Salt[:])
 	if err != nil {
 		return err
 	}
+	usr.Params = params
 	// pw is the salted, hashed password
-	pw := argon2.IDKey([]byte(password), usr.Salt[:], 1, 64*1024, 4, 32)
+	pw := usr.hash(password)
 	copy(usr.Password[:], pw[:32])
 	return nil
 }
 
 // CheckPassword ...
 func (usr *User) CheckPassword(password string) bool {
-	pw := argon2.IDKey([]byte(password), usr.Salt[:], 1, 64*1024, 4, 32)
-	return bytes.Equal(pw, usr.Password[:])
+	if usr.Params.Verify() != nil {
+		return false
+	}
+	return bytes.Equal(usr.hash(password), usr.Password[:])
+}
+
+func (usr *User) hash(password string) []byte {
+	return argon2.IDKey([]byte(password), usr.Salt[:], usr.Params.Time, usr.Params.Memory, usr.Params.Threads, 32)
 }
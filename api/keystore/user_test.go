@@ -9,7 +9,7 @@ import (
 
 func TestUser(t *testing.T) {
 	usr := User{}
-	if err := usr.Initialize("heytherepal"); err != nil {
+	if err := usr.Initialize("heytherepal", DefaultPasswordParams); err != nil {
 		t.Fatal(err)
 	}
 	if !usr.CheckPassword("heytherepal") {
@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Listener is an address the API server listens on, and the APIs it doesn't
+// serve there
+type Listener struct {
+	// Address to listen on, such as 127.0.0.1:9650
+	Address string
+
+	// TLS certificate and private key files. If empty, the listener serves
+	// plain HTTP.
+	CertFile, KeyFile string
+
+	// DisabledAPIs aren't served on this listener. Each is either a route, such
+	// as "keystore" or "bc/P", whose endpoints under /ext aren't served, an
+	// endpoint of a route, such as "bc/P/export", or a JSON-RPC method, such as
+	// "platform.sign" or "eth_sendRawTransaction", that can't be called.
+	DisabledAPIs []string
+
+	// RequireAPIKey makes every request on this listener carry an API key that
+	// may make it, in its Authorization header
+	RequireAPIKey bool
+}
+
+type restrictionsKey struct{}
+
+// restrictions are the routes and methods a listener doesn't serve
+type restrictions struct {
+	routes  map[string]bool // URLs of disabled routes, such as /ext/keystore
+	methods map[string]bool // Lowercase names of disabled methods
+}
+
+func newRestrictions(disabledAPIs []string) *restrictions {
+	r := &restrictions{
+		routes:  make(map[string]bool),
+		methods: make(map[string]bool),
+	}
+	for _, api := range disabledAPIs {
+		switch {
+		case api == "":
+		case strings.ContainsAny(api, "._") && !strings.Contains(api, "/"):
+			// Compared case insensitively, so no spelling of a disabled
+			// method gets through
+			r.methods[strings.ToLower(api)] = true
+		default:
+			r.routes[fmt.Sprintf("%s/%s", baseURL, strings.Trim(api, "/"))] = true
+		}
+	}
+	return r
+}
+
+// restrict returns [handler], but with requests carrying [disabledAPIs] in
+// their context so they're refused by the routes and methods they disable
+func restrict(handler http.Handler, disabledAPIs []string) http.Handler {
+	r := newRestrictions(disabledAPIs)
+	if len(r.routes) == 0 && len(r.methods) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ctx := context.WithValue(request.Context(), restrictionsKey{}, r)
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// allowed returns true if the listener [request] arrived on serves the
+// endpoint [endpoint] of the route [base], whose aliases are [aliases]. If it
+// doesn't, an error has been written to [writer].
+func (r *restrictions) allowed(writer http.ResponseWriter, request *http.Request, base, endpoint string, aliases []string) bool {
+	disabled := r.routes[base] || r.routes[base+endpoint]
+	for _, alias := range aliases {
+		disabled = disabled || r.routes[alias] || r.routes[alias+endpoint]
+	}
+	if disabled {
+		http.NotFound(writer, request)
+		return false
+	}
+
+	if len(r.methods) == 0 {
+		return true
+	}
+	calls, err := readCalls(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	// A batch of calls is refused if any of them calls a disabled method
+	for _, call := range calls {
+		method, ok := callMethod(call)
+		if !ok {
+			continue
+		}
+		if r.methods[strings.ToLower(method)] {
+			writeError(writer, call["id"], errCodeMethodNotFound, fmt.Sprintf("method %s isn't served on this address", method))
+			return false
+		}
+	}
+	return true
+}
+
+// readCalls returns the JSON-RPC calls [request] makes, which are several if
+// it's a batch and none if it isn't a JSON-RPC request. The body of [request]
+// can still be read afterwards.
+func readCalls(request *http.Request) ([]map[string]json.RawMessage, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	calls := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &calls); err != nil {
+		call := map[string]json.RawMessage{}
+		if err := json.Unmarshal(body, &call); err != nil {
+			return nil, nil // Not a JSON-RPC call
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+// callMethod returns the method [call] calls, if it names one
+func callMethod(call map[string]json.RawMessage) (string, bool) {
+	method := ""
+	if err := json.Unmarshal(call["method"], &method); err != nil {
+		return "", false
+	}
+	return method, true
+}
@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestListenerDisabledAPIs(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	chain := &Service{}
+	chainServer := rpc.NewServer()
+	chainServer.RegisterCodec(json2.NewCodec(), "application/json")
+	chainServer.RegisterService(chain, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: chainServer}, new(sync.RWMutex), "bc/chainID", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddAliases("bc/chainID", "bc/X"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddAliases("bc/X", "X"); err != nil {
+		t.Fatal(err)
+	}
+
+	keystore := &Service{}
+	keystoreServer := rpc.NewServer()
+	keystoreServer.RegisterCodec(json2.NewCodec(), "application/json")
+	keystoreServer.RegisterService(keystore, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: keystoreServer}, new(sync.RWMutex), "keystore", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(handler http.Handler, url, method string) *httptest.ResponseRecorder {
+		buf, err := json2.EncodeClientRequest(method, &Args{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest("POST", url, bytes.NewBuffer(buf))
+		request.Header.Set("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, request)
+		return writer
+	}
+
+	public := restrict(s.router, []string{"keystore", "test.call"})
+	if writer := call(public, "/ext/keystore", "test.Call"); writer.Code != http.StatusNotFound {
+		t.Fatalf("Disabled route should have responded with %d but got %d", http.StatusNotFound, writer.Code)
+	}
+	if err := json2.DecodeClientResponse(call(public, "/ext/bc/X", "test.Call").Body, &Reply{}); err == nil {
+		t.Fatalf("Disabled method shouldn't have been served")
+	}
+	batch := httptest.NewRequest("POST", "/ext/bc/X", strings.NewReader(`[{"jsonrpc":"2.0","method":"test.getBalance","id":1},{"jsonrpc":"2.0","method":"test.Call","id":2}]`))
+	writer := httptest.NewRecorder()
+	public.ServeHTTP(writer, batch)
+	if !strings.Contains(writer.Body.String(), "isn't served") {
+		t.Fatalf("Batch calling a disabled method should have been refused, got %q", writer.Body.String())
+	}
+	if keystore.called || chain.called {
+		t.Fatalf("Shouldn't have been called")
+	}
+
+	private := restrict(s.router, nil)
+	call(private, "/ext/keystore", "test.Call")
+	if !keystore.called {
+		t.Fatalf("Should have been called")
+	}
+
+	// Disabling a chain's alias disables all of its routes
+	chainless := restrict(s.router, []string{"bc/X"})
+	for _, url := range []string{"/ext/bc/chainID", "/ext/bc/X", "/ext/X"} {
+		if writer := call(chainless, url, "test.Call"); writer.Code != http.StatusNotFound {
+			t.Fatalf("Disabled route %s should have responded with %d but got %d", url, http.StatusNotFound, writer.Code)
+		}
+	}
+	if chain.called {
+		t.Fatalf("Shouldn't have been called")
+	}
+
+	// Disabling an endpoint of a chain's alias disables only that endpoint
+	export := &Service{}
+	exportServer := rpc.NewServer()
+	exportServer.RegisterCodec(json2.NewCodec(), "application/json")
+	exportServer.RegisterService(export, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: exportServer}, new(sync.RWMutex), "bc/chainID", "/export", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	exportless := restrict(s.router, []string{"bc/X/export"})
+	for _, url := range []string{"/ext/bc/chainID/export", "/ext/X/export"} {
+		if writer := call(exportless, url, "test.Call"); writer.Code != http.StatusNotFound {
+			t.Fatalf("Disabled endpoint %s should have responded with %d but got %d", url, http.StatusNotFound, writer.Code)
+		}
+	}
+	if export.called {
+		t.Fatalf("Shouldn't have been called")
+	}
+	call(exportless, "/ext/X", "test.Call")
+	if !chain.called {
+		t.Fatalf("Should have been called")
+	}
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RetryAfterHeader is set on a response refused while the node is
+	// unhealthy to the number of seconds after which the call may be retried
+	RetryAfterHeader = "Retry-After"
+)
+
+// loadShedder refuses calls of the JSON-RPC methods that are expensive to serve
+// while the node is unhealthy, so that load balancers stop sending them to
+// this node and serving them doesn't slow down its recovery
+type loadShedder struct {
+	lock       sync.RWMutex
+	methods    []string      // Lowercase names of the shed methods, or services such as platform.*
+	retryAfter time.Duration // How long callers are asked to wait before retrying
+	unhealthy  error         // Why the node is unhealthy, or nil if it's healthy
+
+	numShed *prometheus.CounterVec
+}
+
+func newLoadShedder() *loadShedder {
+	return &loadShedder{
+		numShed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "calls_shed",
+			Help:      "Number of API calls refused because the node was unhealthy",
+		}, []string{"method"}),
+	}
+}
+
+// set refuses calls of [methods] while the node is unhealthy, asking callers
+// to retry after [retryAfter]
+func (s *loadShedder) set(retryAfter time.Duration, methods []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.retryAfter = retryAfter
+	s.methods = nil
+	for _, method := range methods {
+		if method != "" {
+			s.methods = append(s.methods, strings.ToLower(method))
+		}
+	}
+}
+
+// setHealth records whether the node is healthy. [unhealthy] is why it isn't,
+// or nil if it is.
+func (s *loadShedder) setHealth(unhealthy error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unhealthy = unhealthy
+}
+
+// shedding returns how long callers should wait before retrying calls of the
+// shed methods, and why they're refused. It returns a nil error if they're
+// served.
+func (s *loadShedder) shedding() (time.Duration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.methods) == 0 {
+		return 0, nil
+	}
+	return s.retryAfter, s.unhealthy
+}
+
+func (s *loadShedder) sheds(method string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.methods) != 0 && inScope(s.methods, strings.ToLower(method))
+}
+
+// serveHTTP refuses [request] with 503 Service Unavailable if the node is
+// unhealthy and [request] calls a shed method. Otherwise, it's served by
+// [handler].
+func (s *loadShedder) serveHTTP(writer http.ResponseWriter, request *http.Request, handler http.Handler) {
+	retryAfter, unhealthy := s.shedding()
+	if unhealthy == nil {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+
+	calls, err := readCalls(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, call := range calls {
+		method, ok := callMethod(call)
+		if !ok || !s.sheds(method) {
+			continue
+		}
+		s.numShed.WithLabelValues(method).Inc()
+		writer.Header().Set(RetryAfterHeader, strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		http.Error(writer, fmt.Sprintf("%s isn't served while the node is unhealthy: %s", method, unhealthy), http.StatusServiceUnavailable)
+		return
+	}
+	handler.ServeHTTP(writer, request)
+}
@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestShedLoad(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	serv := &CountingService{}
+	newServer := rpc.NewServer()
+	newServer.RegisterCodec(json2.NewCodec(), "application/json")
+	newServer.RegisterService(serv, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: newServer}, new(sync.RWMutex), "vm/lol", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("POST", "/ext/vm/lol", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		s.router.ServeHTTP(writer, request)
+		return writer
+	}
+	count := `{"jsonrpc":"2.0","method":"test.Count","params":{},"id":1}`
+
+	// Calls aren't refused until methods are shed
+	s.SetHealth(errors.New("bootstrapping"))
+	if writer := call(count); writer.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, writer.Code)
+	}
+
+	s.ShedLoad(1500*time.Millisecond, []string{"test.*"})
+	writer := call(count)
+	if writer.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, writer.Code)
+	}
+	if retryAfter := writer.Header().Get(RetryAfterHeader); retryAfter != "2" {
+		t.Fatalf("expected to be asked to retry after 2 seconds but got %q", retryAfter)
+	}
+	if writer := call(`[` + count + `]`); writer.Code != http.StatusServiceUnavailable {
+		t.Fatalf("a batch with a shed call should have been refused but got %d", writer.Code)
+	}
+
+	s.ShedLoad(time.Second, []string{"test.other"})
+	if writer := call(count); writer.Code != http.StatusOK {
+		t.Fatalf("calls of methods that aren't shed should be served but got %d", writer.Code)
+	}
+
+	s.ShedLoad(time.Second, []string{"test.count"})
+	s.SetHealth(nil)
+	if writer := call(count); writer.Code != http.StatusOK {
+		t.Fatalf("calls should be served while the node is healthy but got %d", writer.Code)
+	}
+	if serv.calls != 3 {
+		t.Fatalf("expected 3 calls to have been served but %d were", serv.calls)
+	}
+}
@@ -0,0 +1,178 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DeprecationHeader is set on the response to a call of a deprecated
+	// method
+	DeprecationHeader = "Deprecation"
+
+	// WarningHeader describes, in the response to a call of a deprecated
+	// method, which method replaces it
+	WarningHeader = "Warning"
+
+	// JSON-RPC 2.0 error codes
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+)
+
+// MethodAlias keeps a JSON-RPC method working after it has been renamed.
+// Deprecation happens in two phases. While the alias isn't [Removed], calls of
+// the deprecated method are served by the replacement and the response carries
+// a deprecation warning. Once it is [Removed], calls of the deprecated method
+// fail with an error that names the replacement.
+type MethodAlias struct {
+	// Deprecated is the full name of the deprecated method (e.g. avm.getbalance)
+	Deprecated string
+
+	// Replacement is the full name of the method that replaces it (e.g.
+	// avm.getBalance)
+	Replacement string
+
+	// Removed is true once the deprecated method can no longer be called
+	Removed bool
+
+	// ConvertParams, if non-nil, converts the params of a call of the
+	// deprecated method to the params the replacement expects
+	ConvertParams func(params json.RawMessage) (json.RawMessage, error)
+}
+
+// methodAliases rewrites calls of deprecated JSON-RPC methods into calls of the
+// methods that replace them
+type methodAliases struct {
+	lock    sync.RWMutex
+	aliases map[string]MethodAlias // Maps a deprecated method to its alias
+
+	numDeprecatedCalls *prometheus.CounterVec
+}
+
+func newMethodAliases() *methodAliases {
+	return &methodAliases{
+		aliases: make(map[string]MethodAlias),
+		numDeprecatedCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "deprecated_calls",
+			Help:      "Number of calls of deprecated API methods",
+		}, []string{"method"}),
+	}
+}
+
+func (m *methodAliases) add(alias MethodAlias) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if alias.Deprecated == alias.Replacement {
+		return fmt.Errorf("method %s can't replace itself", alias.Deprecated)
+	}
+	if _, exists := m.aliases[alias.Deprecated]; exists {
+		return fmt.Errorf("method %s is already aliased", alias.Deprecated)
+	}
+	m.aliases[alias.Deprecated] = alias
+	return nil
+}
+
+func (m *methodAliases) empty() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.aliases) == 0
+}
+
+func (m *methodAliases) get(method string) (MethodAlias, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	alias, exists := m.aliases[method]
+	return alias, exists
+}
+
+// serveHTTP serves [request] with [handler] after rewriting the request if it
+// calls a deprecated method
+func (m *methodAliases) serveHTTP(writer http.ResponseWriter, request *http.Request, handler http.Handler) {
+	if request.Body == nil || m.empty() {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	call := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &call); err != nil {
+		handler.ServeHTTP(writer, request) // Not a JSON-RPC call
+		return
+	}
+	method := ""
+	if err := json.Unmarshal(call["method"], &method); err != nil {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+	alias, exists := m.get(method)
+	if !exists {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+
+	m.numDeprecatedCalls.WithLabelValues(method).Inc()
+
+	if alias.Removed {
+		writeError(writer, call["id"], errCodeMethodNotFound, fmt.Sprintf("method %s was removed; use %s", alias.Deprecated, alias.Replacement))
+		return
+	}
+
+	if alias.ConvertParams != nil {
+		params, err := alias.ConvertParams(call["params"])
+		if err != nil {
+			writeError(writer, call["id"], errCodeInvalidParams, fmt.Sprintf("couldn't convert the params of deprecated method %s: %s", alias.Deprecated, err))
+			return
+		}
+		call["params"] = params
+	}
+	call["method"], _ = json.Marshal(alias.Replacement)
+
+	body, err = json.Marshal(call)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+
+	writer.Header().Set(DeprecationHeader, "true")
+	writer.Header().Set(WarningHeader, fmt.Sprintf("299 - \"method %s is deprecated; use %s\"", alias.Deprecated, alias.Replacement))
+	handler.ServeHTTP(writer, request)
+}
+
+// writeError responds to the JSON-RPC call with ID [id] with an error
+func writeError(writer http.ResponseWriter, id json.RawMessage, code int, message string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	response, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+		"id": id,
+	})
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Write(response)
+}
@@ -0,0 +1,103 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	errInvalidMountPrefix = errors.New("mount prefix must be a clean, absolute path other than / and outside of " + baseURL)
+	errUnknownMount       = errors.New("nothing is mounted at that prefix")
+)
+
+// Mount is a URL prefix under which the routes below [Target] are served
+type Mount struct {
+	Prefix string
+	Target string
+}
+
+// mounts rewrites the paths of requests made under a mounted prefix into the
+// paths of the routes they are mounted from. Mounts can be added and removed
+// while the server is running.
+type mounts struct {
+	lock    sync.RWMutex
+	targets map[string]string // Maps a prefix to the route it's mounted from
+}
+
+func newMounts() *mounts {
+	return &mounts{targets: make(map[string]string)}
+}
+
+func (m *mounts) add(prefix, target string) error {
+	if prefix == "/" || path.Clean(prefix) != prefix || !path.IsAbs(prefix) ||
+		prefix == baseURL || strings.HasPrefix(prefix, baseURL+"/") {
+		return fmt.Errorf("%w: %s", errInvalidMountPrefix, prefix)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if existing, exists := m.targets[prefix]; exists {
+		return fmt.Errorf("%s is already mounted from %s", prefix, existing)
+	}
+	m.targets[prefix] = target
+	return nil
+}
+
+func (m *mounts) remove(prefix string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, exists := m.targets[prefix]; !exists {
+		return fmt.Errorf("%w: %s", errUnknownMount, prefix)
+	}
+	delete(m.targets, prefix)
+	return nil
+}
+
+// list returns the mounts, sorted by prefix
+func (m *mounts) list() []Mount {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	list := make([]Mount, 0, len(m.targets))
+	for prefix, target := range m.targets {
+		list = append(list, Mount{
+			Prefix: prefix,
+			Target: target,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Prefix < list[j].Prefix })
+	return list
+}
+
+// rewrite returns the path that [urlPath] is mounted from. If [urlPath] isn't
+// under a mounted prefix, it's returned unchanged. If several mounted prefixes
+// match, the longest one is used.
+func (m *mounts) rewrite(urlPath string) string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for prefix := urlPath; prefix != "/" && prefix != "."; prefix = path.Dir(prefix) {
+		if target, exists := m.targets[prefix]; exists {
+			return target + urlPath[len(prefix):]
+		}
+	}
+	return urlPath
+}
+
+// rewriteRequest rewrites the path of [request] if it's under a mounted prefix
+func (m *mounts) rewriteRequest(request *http.Request) {
+	if rewritten := m.rewrite(request.URL.Path); rewritten != request.URL.Path {
+		request.URL.Path = rewritten
+		request.URL.RawPath = ""
+	}
+}
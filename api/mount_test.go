@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type pathHandler struct{ path string }
+
+func (h *pathHandler) ServeHTTP(_ http.ResponseWriter, r *http.Request) { h.path = r.URL.Path }
+
+func TestMountChain(t *testing.T) {
+	chainID := ids.NewID([32]byte{1})
+	newChainID := ids.NewID([32]byte{2})
+	r := newRouter()
+	s := Server{log: logging.NoLog{}, router: r}
+
+	handler := &pathHandler{}
+	newHandler := &pathHandler{}
+	if err := r.AddRouter("/ext/bc/"+chainID.String(), "/rpc", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddRouter("/ext/bc/"+newChainID.String(), "/rpc", newHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prefix := range []string{"/", "public/x", "/public/x/", "/ext", "/ext/x"} {
+		if err := s.MountChain(prefix, chainID); !errors.Is(err, errInvalidMountPrefix) {
+			t.Fatalf("should have failed to mount at %q but got %v", prefix, err)
+		}
+	}
+	if err := s.MountChain("/public/x", chainID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MountChain("/x", chainID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MountChain("/x", newChainID); err == nil {
+		t.Fatal("should have failed to mount twice at the same prefix")
+	}
+
+	serve := func(path string) int {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", path, nil))
+		return recorder.Code
+	}
+
+	serve("/public/x/rpc")
+	if expected := "/ext/bc/" + chainID.String() + "/rpc"; handler.path != expected {
+		t.Fatalf("should have rewritten the path to %s but got %s", expected, handler.path)
+	}
+	handler.path = ""
+	serve("/x/rpc")
+	if handler.path == "" {
+		t.Fatal("should have served the second mount")
+	}
+	if code := serve("/public/rpc"); code != http.StatusNotFound {
+		t.Fatalf("should have responded with %d but got %d", http.StatusNotFound, code)
+	}
+
+	if mounts := s.Mounts(); len(mounts) != 2 || mounts[0].Prefix != "/public/x" || mounts[1].Prefix != "/x" {
+		t.Fatalf("unexpected mounts %v", mounts)
+	}
+
+	// Move the mount to a new chain
+	if err := s.Unmount("/public/x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unmount("/public/x"); !errors.Is(err, errUnknownMount) {
+		t.Fatalf("should have failed with %s but got %v", errUnknownMount, err)
+	}
+	if code := serve("/public/x/rpc"); code != http.StatusNotFound {
+		t.Fatalf("should have responded with %d but got %d", http.StatusNotFound, code)
+	}
+	if err := s.MountChain("/public/x", newChainID); err != nil {
+		t.Fatal(err)
+	}
+	serve("/public/x/rpc")
+	if expected := "/ext/bc/" + newChainID.String() + "/rpc"; newHandler.path != expected {
+		t.Fatalf("should have rewritten the path to %s but got %s", expected, newHandler.path)
+	}
+}
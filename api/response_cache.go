@@ -0,0 +1,265 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// The most responses that are cached
+	maxCachedResponses = 1024
+
+	// Responses larger than this aren't cached, so that the cache stays small
+	maxCachedResponseSize = 1 << 20 // 1 MiB
+
+	// AgeHeader is set on a response served from the cache to the number of
+	// seconds since the response was computed
+	AgeHeader = "Age"
+
+	// CacheControlHeader of a request controls whether it is served from the
+	// cache. "no-cache" recomputes the response, "no-store" recomputes it and
+	// doesn't cache it, and "max-age=N" only accepts a cached response that
+	// was computed at most N seconds ago.
+	CacheControlHeader = "Cache-Control"
+)
+
+// cachedResponse is the response to a JSON-RPC call, without its ID
+type cachedResponse struct {
+	header   http.Header
+	response map[string]json.RawMessage
+	computed time.Time
+}
+
+// responseCache caches, for a short time, the responses to calls of the
+// JSON-RPC methods that are expensive to compute and often called with the
+// same params
+type responseCache struct {
+	lock    sync.RWMutex
+	ttl     time.Duration
+	methods map[string]bool // Lowercase names of the cached methods
+	clock   timer.Clock
+
+	responses cache.LRU
+
+	numHits   *prometheus.CounterVec
+	numMisses *prometheus.CounterVec
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		methods:   make(map[string]bool),
+		responses: cache.LRU{Size: maxCachedResponses},
+		numHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "cache_hits",
+			Help:      "Number of API calls served from the response cache",
+		}, []string{"method"}),
+		numMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "api",
+			Name:      "cache_misses",
+			Help:      "Number of calls of cached API methods whose response was computed",
+		}, []string{"method"}),
+	}
+}
+
+// set caches the responses to calls of [methods] for [ttl]. If [ttl] is
+// non-positive, no response is cached.
+func (c *responseCache) set(ttl time.Duration, methods []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ttl = ttl
+	c.methods = make(map[string]bool)
+	for _, method := range methods {
+		if method != "" {
+			c.methods[strings.ToLower(method)] = true
+		}
+	}
+	c.responses.Flush()
+}
+
+func (c *responseCache) enabled() (time.Duration, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.ttl, c.ttl > 0 && len(c.methods) > 0
+}
+
+func (c *responseCache) caches(method string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.methods[strings.ToLower(method)]
+}
+
+// serveHTTP serves [request] from the cache if it calls a cached method and
+// the response to the same call was computed recently. Otherwise, it's served
+// by [handler], and the response is cached.
+func (c *responseCache) serveHTTP(writer http.ResponseWriter, request *http.Request, handler http.Handler) {
+	ttl, enabled := c.enabled()
+	if !enabled || request.Body == nil {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Batches of calls aren't cached
+	call := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &call); err != nil {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+	method := ""
+	if err := json.Unmarshal(call["method"], &method); err != nil || !c.caches(method) {
+		handler.ServeHTTP(writer, request)
+		return
+	}
+
+	maxAge, noCache, noStore := cacheControl(request.Header.Get(CacheControlHeader))
+	if maxAge < 0 || maxAge > ttl {
+		maxAge = ttl
+	}
+	key := responseKey(request.URL.Path, method, call["params"])
+	now := c.clock.Time()
+
+	if !noCache && !noStore {
+		if cachedIntf, ok := c.responses.Get(key); ok {
+			cached := cachedIntf.(*cachedResponse)
+			if age := now.Sub(cached.computed); age <= maxAge {
+				c.numHits.WithLabelValues(method).Inc()
+				cached.write(writer, call["id"], age)
+				return
+			}
+		}
+	}
+	c.numMisses.WithLabelValues(method).Inc()
+
+	recorder := newResponseRecorder(writer)
+	handler.ServeHTTP(recorder, request)
+	if noStore || recorder.status != http.StatusOK || recorder.body.Len() > maxCachedResponseSize {
+		return
+	}
+
+	response := map[string]json.RawMessage{}
+	if err := json.Unmarshal(recorder.body.Bytes(), &response); err != nil {
+		return
+	}
+	if errMsg, failed := response["error"]; failed && string(errMsg) != "null" {
+		return // Failures aren't cached, as they may be transient
+	}
+	delete(response, "id")
+	c.responses.Put(key, &cachedResponse{
+		header:   recorder.Header().Clone(),
+		response: response,
+		computed: now,
+	})
+}
+
+// write the cached response to [writer] as the response to the call with ID
+// [id]
+func (r *cachedResponse) write(writer http.ResponseWriter, id json.RawMessage, age time.Duration) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	response := make(map[string]json.RawMessage, len(r.response)+1)
+	for key, value := range r.response {
+		response[key] = value
+	}
+	response["id"] = id
+	body, err := json.Marshal(response)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for key, values := range r.header {
+		writer.Header()[key] = values
+	}
+	writer.Header().Set(AgeHeader, strconv.Itoa(int(age/time.Second)))
+	writer.Write(body)
+}
+
+// cacheControl parses the Cache-Control header of a request. [maxAge] is
+// negative if the header doesn't bound the age of a cached response.
+func cacheControl(header string) (maxAge time.Duration, noCache, noStore bool) {
+	maxAge = -1
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache":
+			noCache = true
+		case directive == "no-store":
+			noStore = true
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseUint(strings.TrimPrefix(directive, "max-age="), 10, 32)
+			if err == nil {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge, noCache, noStore
+}
+
+// responseKey returns the key the response to the call of [method] with
+// [params] at [path] is cached under
+func responseKey(path, method string, params json.RawMessage) ids.ID {
+	compacted := bytes.Buffer{}
+	if err := json.Compact(&compacted, params); err != nil {
+		compacted.Reset()
+		compacted.Write(params)
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%s", path, strings.ToLower(method), compacted.Bytes())
+	return ids.NewID(hashing.ComputeHash256Array([]byte(key)))
+}
+
+// responseRecorder writes a response through to a ResponseWriter while keeping
+// a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder(writer http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{
+		ResponseWriter: writer,
+		status:         http.StatusOK,
+	}
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.body.Len() <= maxCachedResponseSize {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
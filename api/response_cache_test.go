@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type CountingService struct{ calls int }
+
+type CountArgs struct{ Fail bool }
+
+type CountReply struct{ Calls int }
+
+func (s *CountingService) Count(_ *http.Request, args *CountArgs, reply *CountReply) error {
+	s.calls++
+	if args.Fail {
+		return errors.New("failed")
+	}
+	reply.Calls = s.calls
+	return nil
+}
+
+func TestCacheResponses(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	serv := &CountingService{}
+	newServer := rpc.NewServer()
+	newServer.RegisterCodec(json2.NewCodec(), "application/json")
+	newServer.RegisterService(serv, "test")
+	if err := s.AddRoute(&common.HTTPHandler{Handler: newServer}, new(sync.RWMutex), "vm/lol", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000, 0)
+	s.router.cache.clock.Set(now)
+	s.CacheResponses(time.Minute, []string{"test.count"})
+
+	call := func(id int, params string, cacheControl string) (int, *httptest.ResponseRecorder) {
+		body := `{"jsonrpc":"2.0","method":"test.Count","params":` + params + `,"id":` + strconv.Itoa(id) + `}`
+		request := httptest.NewRequest("POST", "/ext/vm/lol", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if cacheControl != "" {
+			request.Header.Set(CacheControlHeader, cacheControl)
+		}
+		writer := httptest.NewRecorder()
+		s.router.ServeHTTP(writer, request)
+
+		response := struct {
+			Result CountReply `json:"result"`
+			ID     int        `json:"id"`
+		}{}
+		if err := json.Unmarshal(writer.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.ID != id {
+			t.Fatalf("expected the response to have ID %d but it had %d", id, response.ID)
+		}
+		return response.Result.Calls, writer
+	}
+
+	if calls, _ := call(1, `{}`, ""); calls != 1 {
+		t.Fatalf("should have computed the response")
+	}
+	calls, writer := call(2, `{ }`, "")
+	if calls != 1 || serv.calls != 1 {
+		t.Fatalf("should have served the response from the cache")
+	}
+	if age := writer.Header().Get(AgeHeader); age != "0" {
+		t.Fatalf("expected an age of 0 but got %q", age)
+	}
+
+	// The cache can be bypassed
+	if calls, _ := call(3, `{}`, "no-cache"); calls != 2 {
+		t.Fatalf("should have recomputed the response")
+	}
+	if calls, _ := call(4, `{}`, "no-store"); calls != 3 {
+		t.Fatalf("should have recomputed the response")
+	}
+
+	// "no-store" didn't replace the cached response
+	s.router.cache.clock.Set(now.Add(30 * time.Second))
+	if calls, _ := call(5, `{}`, ""); calls != 2 {
+		t.Fatalf("should have served the response from the cache")
+	}
+	if calls, _ := call(6, `{}`, "max-age=10"); calls != 4 {
+		t.Fatalf("should have recomputed the response that's older than requested")
+	}
+
+	// Responses expire
+	s.router.cache.clock.Set(now.Add(2 * time.Minute))
+	if calls, _ := call(7, `{}`, ""); calls != 5 {
+		t.Fatalf("should have recomputed the expired response")
+	}
+
+	// Failures aren't cached
+	call(8, `{"Fail":true}`, "")
+	call(9, `{"Fail":true}`, "")
+	if serv.calls != 7 {
+		t.Fatalf("shouldn't have cached the failed call")
+	}
+
+	// Methods that aren't cached are always computed
+	s.CacheResponses(time.Minute, []string{"test.other"})
+	call(1, `{}`, "")
+	if serv.calls != 8 {
+		t.Fatalf("shouldn't have cached a call of an uncached method")
+	}
+}
@@ -25,6 +25,16 @@ type router struct {
 	reservedRoutes map[string]bool                    // Reserves routes so that there can't be alias that conflict
 	aliases        map[string][]string                // Maps a route to a set of reserved routes
 	routes         map[string]map[string]http.Handler // Maps routes to a handler
+
+	methods *methodAliases // Rewrites calls of deprecated methods
+	mounts  *mounts        // Rewrites the paths of requests under mounted prefixes
+	cache   *responseCache // Serves calls of expensive methods from a cache
+	shedder *loadShedder   // Refuses calls of expensive methods while the node is unhealthy
+	metrics *httpMetrics   // Counts connections and requests in flight
+	keys    *apiKeys       // Authorizes requests on listeners that require an API key
+
+	disabledLock sync.RWMutex
+	disabled     map[string]bool // Routes that are registered but not served
 }
 
 func newRouter() *router {
@@ -33,6 +43,13 @@ func newRouter() *router {
 		reservedRoutes: make(map[string]bool),
 		aliases:        make(map[string][]string),
 		routes:         make(map[string]map[string]http.Handler),
+		methods:        newMethodAliases(),
+		mounts:         newMounts(),
+		cache:          newResponseCache(),
+		shedder:        newLoadShedder(),
+		metrics:        newHTTPMetrics(),
+		keys:           newAPIKeys(),
+		disabled:       make(map[string]bool),
 	}
 }
 
@@ -40,7 +57,8 @@ func (r *router) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
-	r.router.ServeHTTP(writer, request)
+	r.mounts.rewriteRequest(request)
+	r.methods.serveHTTP(writer, request, r.router)
 }
 
 func (r *router) GetHandler(base, endpoint string) (http.Handler, error) {
@@ -75,6 +93,79 @@ func (r *router) addRouter(base, endpoint string, handler http.Handler) error {
 	return r.forceAddRouter(base, endpoint, handler)
 }
 
+// SetEnabled starts or stops serving the routes under [base], and its aliases.
+// Disabled routes respond as if they didn't exist.
+func (r *router) SetEnabled(base string, enabled bool) error {
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	if _, exists := r.routes[base]; !exists {
+		return errUnknownBaseURL
+	}
+
+	r.disabledLock.Lock()
+	defer r.disabledLock.Unlock()
+
+	if enabled {
+		delete(r.disabled, base)
+	} else {
+		r.disabled[base] = true
+	}
+	return nil
+}
+
+// allAliases returns the aliases of [base], including aliases of its aliases.
+// Assumes the lock is held.
+func (r *router) allAliases(base string) []string {
+	aliases := []string(nil)
+	for i, next := 0, []string{base}; i < len(next); i++ {
+		for _, alias := range r.aliases[next[i]] {
+			aliases = append(aliases, alias)
+			next = append(next, alias)
+		}
+	}
+	return aliases
+}
+
+func (r *router) enabled(base string) bool {
+	r.disabledLock.RLock()
+	defer r.disabledLock.RUnlock()
+
+	return !r.disabled[base]
+}
+
+// toggledHandler serves [handler], the endpoint [endpoint] of [base], unless
+// the routes under [base] are disabled. Aliases of [base] share its handlers,
+// so they're disabled along with it.
+type toggledHandler struct {
+	r        *router
+	base     string
+	endpoint string
+	handler  http.Handler
+}
+
+func (th toggledHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !th.r.enabled(th.base) {
+		http.NotFound(writer, request)
+		return
+	}
+	if r, ok := request.Context().Value(restrictionsKey{}).(*restrictions); ok {
+		// The routes lock is held while serving requests that arrived on a
+		// listener, so the aliases can't change
+		if !r.allowed(writer, request, th.base, th.endpoint, th.r.allAliases(th.base)) {
+			return
+		}
+	}
+
+	inFlight := th.r.metrics.inFlight.WithLabelValues(th.base)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	th.r.shedder.serveHTTP(writer, request, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		th.r.cache.serveHTTP(writer, request, th.handler)
+	}))
+}
+
 func (r *router) forceAddRouter(base, endpoint string, handler http.Handler) error {
 	endpoints := r.routes[base]
 	if endpoints == nil {
@@ -10,14 +10,20 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/gorilla/handlers"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/rs/cors"
 
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 const baseURL = "/ext"
@@ -28,10 +34,11 @@ var (
 
 // Server maintains the HTTP router
 type Server struct {
-	log     logging.Logger
-	factory logging.Factory
-	router  *router
-	portURL string
+	log        logging.Logger
+	factory    logging.Factory
+	router     *router
+	portURL    string
+	httpConfig HTTPConfig
 }
 
 // Initialize creates the API server at the provided port
@@ -40,18 +47,32 @@ func (s *Server) Initialize(log logging.Logger, factory logging.Factory, port ui
 	s.factory = factory
 	s.portURL = fmt.Sprintf(":%d", port)
 	s.router = newRouter()
+	s.httpConfig = DefaultHTTPConfig
 }
 
+// ConfigureHTTP tunes the connections the server accepts once it's dispatched
+func (s *Server) ConfigureHTTP(config HTTPConfig) { s.httpConfig = config }
+
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
 	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServe(s.portURL, handler)
+	return s.serve(s.portURL, "", "", handler)
+}
+
+// DispatchListener starts serving the API on [listener.Address], except for
+// the APIs [listener] disables
+func (s *Server) DispatchListener(listener Listener) error {
+	handler := restrict(s.router, listener.DisabledAPIs)
+	if listener.RequireAPIKey {
+		handler = s.router.keys.require(handler)
+	}
+	return s.serve(listener.Address, listener.CertFile, listener.KeyFile, cors.Default().Handler(handler))
 }
 
 // DispatchTLS starts the API server with the provided TLS certificate
 func (s *Server) DispatchTLS(certFile, keyFile string) error {
 	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServeTLS(s.portURL, certFile, keyFile, handler)
+	return s.serve(s.portURL, certFile, keyFile, handler)
 }
 
 // RegisterChain registers the API endpoints associated with this chain That
@@ -93,24 +114,46 @@ func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	url := fmt.Sprintf("%s/%s", baseURL, base)
 	s.log.Info("adding route %s%s", url, endpoint)
 	h := handlers.CombinedLoggingHandler(log, handler.Handler)
+	var routeHandler http.Handler
 	switch handler.LockOptions {
 	case common.WriteLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		routeHandler = middlewareHandler{
 			before:  lock.Lock,
 			after:   lock.Unlock,
 			handler: h,
-		})
+		}
 	case common.ReadLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		routeHandler = middlewareHandler{
 			before:  lock.RLock,
 			after:   lock.RUnlock,
 			handler: h,
-		})
+		}
 	case common.NoLock:
-		return s.router.AddRouter(url, endpoint, h)
+		routeHandler = h
 	default:
 		return errUnknownLockOption
 	}
+	return s.router.AddRouter(url, endpoint, toggledHandler{
+		r:        s.router,
+		base:     url,
+		endpoint: endpoint,
+		handler:  routeHandler,
+	})
+}
+
+// SetRouteEnabled starts or stops serving the endpoints under [base]. The
+// route must have been added already.
+func (s *Server) SetRouteEnabled(base string, enabled bool) error {
+	url := fmt.Sprintf("%s/%s", baseURL, base)
+	if err := s.router.SetEnabled(url, enabled); err != nil {
+		return fmt.Errorf("couldn't toggle route %s: %w", url, err)
+	}
+	if enabled {
+		s.log.Info("enabled route %s", url)
+	} else {
+		s.log.Info("disabled route %s", url)
+	}
+	return nil
 }
 
 // AddAliases registers aliases to the server
@@ -135,6 +178,102 @@ func (s *Server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
+// AddMethodAlias keeps [alias.Deprecated] callable, on every route, as
+// [alias.Replacement] until the alias is removed
+func (s *Server) AddMethodAlias(alias MethodAlias) error { return s.router.methods.add(alias) }
+
+// MountChain serves the API of the chain with ID [chainID] under [prefix], in
+// addition to its other routes. For example, if [prefix] is /public/x, the
+// endpoint /ext/bc/[chainID]/rpc is also served at /public/x/rpc. [prefix] can
+// later be unmounted and mounted again, from another chain.
+func (s *Server) MountChain(prefix string, chainID ids.ID) error {
+	target := fmt.Sprintf("%s/bc/%s", baseURL, chainID)
+	if err := s.router.mounts.add(prefix, target); err != nil {
+		return err
+	}
+	s.log.Info("mounted %s at %s", target, prefix)
+	return nil
+}
+
+// Unmount stops serving the routes mounted at [prefix]
+func (s *Server) Unmount(prefix string) error {
+	if err := s.router.mounts.remove(prefix); err != nil {
+		return err
+	}
+	s.log.Info("unmounted %s", prefix)
+	return nil
+}
+
+// Mounts returns the prefixes that routes are mounted at, sorted by prefix
+func (s *Server) Mounts() []Mount { return s.router.mounts.list() }
+
+// CacheResponses serves calls of the JSON-RPC [methods], such as
+// platform.getCurrentValidators, from a cache for up to [ttl] after their
+// response was computed. Calls with the same params on the same route share a
+// response. If [ttl] is non-positive, no response is cached.
+func (s *Server) CacheResponses(ttl time.Duration, methods []string) {
+	s.router.cache.set(ttl, methods)
+}
+
+// ShedLoad refuses calls of the JSON-RPC [methods], such as avm.getUTXOs, or of
+// every method of a service, such as platform.*, with 503 Service Unavailable
+// while the node is unhealthy. The response asks the caller to retry after
+// [retryAfter]. If [methods] is empty, no call is refused.
+func (s *Server) ShedLoad(retryAfter time.Duration, methods []string) {
+	s.router.shedder.set(retryAfter, methods)
+}
+
+// SetHealth records whether the node is healthy. [unhealthy] is why it isn't,
+// or nil if it is.
+func (s *Server) SetHealth(unhealthy error) { s.router.shedder.setHealth(unhealthy) }
+
+// EnableAPIKeys lets API keys, which are stored in [db], be created. Listeners
+// that require an API key refuse every request until API keys are enabled.
+func (s *Server) EnableAPIKeys(db database.Database) error { return s.router.keys.enable(db) }
+
+// CreateAPIKey creates the API key [name], and returns the key. The key may
+// make [quota] calls each [period], or unlimited calls if [quota] is 0, of
+// [methods], or of every method if [methods] is empty. A method may be a
+// service's every method, such as platform.*.
+func (s *Server) CreateAPIKey(name string, quota uint64, period time.Duration, methods []string) (string, error) {
+	key, err := s.router.keys.create(name, quota, period, methods)
+	if err == nil {
+		s.log.Info("created API key %s", name)
+	}
+	return key, err
+}
+
+// RevokeAPIKey revokes the API key [name]
+func (s *Server) RevokeAPIKey(name string) error {
+	if err := s.router.keys.revoke(name); err != nil {
+		return err
+	}
+	s.log.Info("revoked API key %s", name)
+	return nil
+}
+
+// APIKeys returns the API keys and their usage, sorted by name
+func (s *Server) APIKeys() []APIKeyStatus { return s.router.keys.list() }
+
+// APIKey returns the API key [key] and its usage
+func (s *Server) APIKey(key string) (APIKeyStatus, error) { return s.router.keys.status(key) }
+
+// RegisterMetrics registers the server's metrics, such as the number of calls
+// of deprecated methods and the number of open connections, with [registerer]
+func (s *Server) RegisterMetrics(registerer prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(s.router.methods.numDeprecatedCalls),
+		registerer.Register(s.router.cache.numHits),
+		registerer.Register(s.router.cache.numMisses),
+		registerer.Register(s.router.shedder.numShed),
+	)
+	for _, collector := range s.router.metrics.collectors() {
+		errs.Add(registerer.Register(collector))
+	}
+	return errs.Err
+}
+
 // Call ...
 func (s *Server) Call(
 	writer http.ResponseWriter,
@@ -145,7 +284,29 @@ func (s *Server) Call(
 	headers map[string]string,
 ) error {
 	url := fmt.Sprintf("%s/vm/%s", baseURL, base)
+	return s.call(writer, url, endpoint, body, headers)
+}
+
+// CallChain calls the API handler at [endpoint] of the chain [chain], which may
+// be an alias of the chain, and writes the response to [writer]
+func (s *Server) CallChain(
+	writer http.ResponseWriter,
+	chain,
+	endpoint string,
+	body io.Reader,
+	headers map[string]string,
+) error {
+	url := fmt.Sprintf("%s/bc/%s", baseURL, chain)
+	return s.call(writer, url, endpoint, body, headers)
+}
 
+func (s *Server) call(
+	writer http.ResponseWriter,
+	url,
+	endpoint string,
+	body io.Reader,
+	headers map[string]string,
+) error {
 	handler, err := s.router.GetHandler(url, endpoint)
 	if err != nil {
 		return err
@@ -159,7 +320,7 @@ func (s *Server) Call(
 		req.Header.Set(key, value)
 	}
 
-	handler.ServeHTTP(writer, req)
+	s.router.methods.serveHTTP(writer, req, handler)
 
 	return nil
 }
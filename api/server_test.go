@@ -7,11 +7,13 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
@@ -58,3 +60,128 @@ func TestCall(t *testing.T) {
 		t.Fatalf("Should have been called")
 	}
 }
+
+func TestCallDeprecatedMethod(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	serv := &Service{}
+	newServer := rpc.NewServer()
+	newServer.RegisterCodec(json2.NewCodec(), "application/json")
+	newServer.RegisterService(serv, "test")
+
+	if err := s.AddRoute(&common.HTTPHandler{Handler: newServer}, new(sync.RWMutex), "vm/lol", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddMethodAlias(MethodAlias{Deprecated: "test.old", Replacement: "test.Call"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddMethodAlias(MethodAlias{Deprecated: "test.old", Replacement: "test.Call"}); err == nil {
+		t.Fatalf("Should have errored due to the method already being aliased")
+	}
+	if err := s.AddMethodAlias(MethodAlias{Deprecated: "test.gone", Replacement: "test.Call", Removed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := json2.EncodeClientRequest("test.old", &Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := httptest.NewRecorder()
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	s.Call(writer, "POST", "lol", "", bytes.NewBuffer(buf), headers)
+
+	if !serv.called {
+		t.Fatalf("Should have been called")
+	}
+	if writer.Header().Get(DeprecationHeader) != "true" {
+		t.Fatalf("Should have been marked as deprecated")
+	}
+	if warning := writer.Header().Get(WarningHeader); !strings.Contains(warning, "test.Call") {
+		t.Fatalf("Warning %q should have named the replacement", warning)
+	}
+	if err := json2.DecodeClientResponse(writer.Body, &Reply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	serv.called = false
+	buf, err = json2.EncodeClientRequest("test.gone", &Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer = httptest.NewRecorder()
+	s.Call(writer, "POST", "lol", "", bytes.NewBuffer(buf), headers)
+
+	if serv.called {
+		t.Fatalf("Shouldn't have been called")
+	}
+	if err := json2.DecodeClientResponse(writer.Body, &Reply{}); err == nil || !strings.Contains(err.Error(), "was removed") {
+		t.Fatalf("Should have errored due to the method being removed, got %v", err)
+	}
+
+	if count := testutil.ToFloat64(s.router.methods.numDeprecatedCalls.WithLabelValues("test.old")); count != 1 {
+		t.Fatalf("Expected 1 call of test.old but got %v", count)
+	}
+	if count := testutil.ToFloat64(s.router.methods.numDeprecatedCalls.WithLabelValues("test.gone")); count != 1 {
+		t.Fatalf("Expected 1 call of test.gone but got %v", count)
+	}
+}
+
+func TestSetRouteEnabled(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+
+	serv := &Service{}
+	newServer := rpc.NewServer()
+	newServer.RegisterCodec(json2.NewCodec(), "application/json")
+	newServer.RegisterService(serv, "test")
+
+	if err := s.SetRouteEnabled("vm/lol", false); err == nil {
+		t.Fatalf("Should have errored due to the route not existing")
+	}
+	if err := s.AddRoute(&common.HTTPHandler{Handler: newServer}, new(sync.RWMutex), "vm/lol", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddAliases("vm/lol", "vm/alias"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetRouteEnabled("vm/lol", false); err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	call := func(base string) *httptest.ResponseRecorder {
+		buf, err := json2.EncodeClientRequest("test.Call", &Args{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer := httptest.NewRecorder()
+		if err := s.Call(writer, "POST", base, "", bytes.NewBuffer(buf), headers); err != nil {
+			t.Fatal(err)
+		}
+		return writer
+	}
+
+	for _, base := range []string{"lol", "alias"} {
+		if writer := call(base); writer.Code != http.StatusNotFound {
+			t.Fatalf("Disabled route %s should have responded with %d but got %d", base, http.StatusNotFound, writer.Code)
+		}
+	}
+	if serv.called {
+		t.Fatalf("Shouldn't have been called")
+	}
+
+	if err := s.SetRouteEnabled("vm/lol", true); err != nil {
+		t.Fatal(err)
+	}
+	call("alias")
+	if !serv.called {
+		t.Fatalf("Should have been called")
+	}
+}
@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Acceptance is a transaction, or block, that a chain accepted, and when it
+// was accepted
+type Acceptance struct {
+	ID   ids.ID
+	Time time.Time
+}
+
+// acceptanceLog remembers the decisions a chain accepted within the last
+// [window], so that whether a transaction was recently accepted can be
+// answered without indexing the chain
+type acceptanceLog struct {
+	clock  *timer.Clock
+	window time.Duration
+
+	lock sync.Mutex
+	// The acceptances within the window, oldest first
+	accepted []Acceptance
+}
+
+// newAcceptanceLog returns a log of the decisions accepted within the last
+// [window], as measured by [clock]
+func newAcceptanceLog(clock *timer.Clock, window time.Duration) *acceptanceLog {
+	return &acceptanceLog{
+		clock:  clock,
+		window: window,
+	}
+}
+
+// Accept implements the triggers.Acceptor interface
+func (l *acceptanceLog) Accept(_, containerID ids.ID, _ []byte) error {
+	now := l.clock.Time()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.prune(now)
+	l.accepted = append(l.accepted, Acceptance{
+		ID:   containerID,
+		Time: now,
+	})
+	return nil
+}
+
+// Recent returns the decisions accepted within the window, oldest first
+func (l *acceptanceLog) Recent() []Acceptance {
+	now := l.clock.Time()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.prune(now)
+	accepted := make([]Acceptance, len(l.accepted))
+	copy(accepted, l.accepted)
+	return accepted
+}
+
+// prune the acceptances that are older than the window at [now]. Assumes the
+// lock is held.
+func (l *acceptanceLog) prune(now time.Time) {
+	cutoff := now.Add(-l.window)
+	expired := 0
+	for expired < len(l.accepted) && l.accepted[expired].Time.Before(cutoff) {
+		expired++
+	}
+	if expired == 0 {
+		return
+	}
+
+	// Copy the remaining acceptances to the front, so the expired ones can be
+	// garbage collected
+	remaining := copy(l.accepted, l.accepted[expired:])
+	l.accepted = l.accepted[:remaining]
+}
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+func TestAcceptanceLog(t *testing.T) {
+	clock := timer.Clock{}
+	start := time.Unix(1000000, 0)
+	clock.Set(start)
+
+	l := newAcceptanceLog(&clock, time.Hour)
+	if accepted := l.Recent(); len(accepted) != 0 {
+		t.Fatalf("shouldn't have accepted anything yet")
+	}
+
+	txID0 := ids.Empty.Prefix(0)
+	txID1 := ids.Empty.Prefix(1)
+	txID2 := ids.Empty.Prefix(2)
+	if err := l.Accept(ids.Empty, txID0, nil); err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(start.Add(30 * time.Minute))
+	if err := l.Accept(ids.Empty, txID1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := l.Recent()
+	if len(accepted) != 2 {
+		t.Fatalf("expected 2 acceptances but got %d", len(accepted))
+	}
+	if !accepted[0].ID.Equals(txID0) || !accepted[0].Time.Equal(start) {
+		t.Fatalf("expected %s accepted at %s first", txID0, start)
+	}
+	if !accepted[1].ID.Equals(txID1) {
+		t.Fatalf("expected %s accepted second", txID1)
+	}
+
+	// The first acceptance leaves the window
+	clock.Set(start.Add(time.Hour + time.Second))
+	if err := l.Accept(ids.Empty, txID2, nil); err != nil {
+		t.Fatal(err)
+	}
+	accepted = l.Recent()
+	if len(accepted) != 2 || !accepted[0].ID.Equals(txID1) || !accepted[1].ID.Equals(txID2) {
+		t.Fatalf("expected only %s and %s to be in the window but got %v", txID1, txID2, accepted)
+	}
+
+	clock.Set(start.Add(3 * time.Hour))
+	if accepted := l.Recent(); len(accepted) != 0 {
+		t.Fatalf("every acceptance should have left the window")
+	}
+}
@@ -0,0 +1,315 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package atomic implements the snow.SharedMemory that lets the chains running
+// on a node pass messages to each other. The queues are persisted in the same
+// database as the chains' databases, so messages survive restarts and are
+// written atomically with the state of the chains that send and consume them.
+package atomic
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// maxMessageSize is the largest a serialized message may be
+const maxMessageSize = snow.MaxMessagePayloadSize + 1<<10
+
+var (
+	nonceKey = []byte("nonce")
+
+	errSameChain            = errors.New("a chain can't send messages to itself")
+	errPayloadTooLarge      = errors.New("payload is too large")
+	errVerifierRegistered   = errors.New("a verifier is already registered for that message type")
+	errUnknownMessageType   = errors.New("no verifier is registered for that message type")
+	errWrongDestination     = errors.New("message wasn't sent to this chain")
+	errMessageNotInQueue    = errors.New("message isn't in the queue")
+	errMalformedMessageData = errors.New("malformed message")
+)
+
+// Memory holds the message queues between the chains running on this node
+type Memory struct {
+	lock      sync.Mutex
+	log       logging.Logger
+	db        database.Database
+	verifiers map[[32]byte]map[uint32]snow.MessageVerifier // destination -> type -> verifier
+}
+
+// Initialize this memory, which stores its queues in [db]. [db] must be stored
+// in the same database as the databases of the chains that use this memory.
+func (m *Memory) Initialize(log logging.Logger, db database.Database) {
+	m.log = log
+	m.db = db
+	m.verifiers = make(map[[32]byte]map[uint32]snow.MessageVerifier)
+}
+
+// NewSharedMemory returns the view of this memory that the chain with ID
+// [chainID] uses to send and receive messages
+func (m *Memory) NewSharedMemory(chainID ids.ID) *SharedMemory {
+	return &SharedMemory{
+		m:       m,
+		chainID: chainID,
+	}
+}
+
+// queue returns the database that the queue from [source] to [destination] is
+// stored in
+func (m *Memory) queue(source, destination ids.ID) database.Database {
+	return prefixdb.New(append(destination.Bytes(), source.Bytes()...), m.db)
+}
+
+// SharedMemory is the view of the memory of one chain
+type SharedMemory struct {
+	m       *Memory
+	chainID ids.ID
+}
+
+// RegisterVerifier implements the snow.SharedMemory interface
+func (sm *SharedMemory) RegisterVerifier(msgType uint32, verifier snow.MessageVerifier) error {
+	sm.m.lock.Lock()
+	defer sm.m.lock.Unlock()
+
+	chainKey := sm.chainID.Key()
+	verifiers, exists := sm.m.verifiers[chainKey]
+	if !exists {
+		verifiers = make(map[uint32]snow.MessageVerifier)
+		sm.m.verifiers[chainKey] = verifiers
+	}
+	if _, exists := verifiers[msgType]; exists {
+		return errVerifierRegistered
+	}
+	verifiers[msgType] = verifier
+	return nil
+}
+
+// Send implements the snow.SharedMemory interface
+func (sm *SharedMemory) Send(batch database.Batch, msgs ...*snow.Message) error {
+	for _, msg := range msgs {
+		switch {
+		case msg.Destination.Equals(sm.chainID):
+			return errSameChain
+		case len(msg.Payload) > snow.MaxMessagePayloadSize:
+			return errPayloadTooLarge
+		}
+	}
+
+	sm.m.lock.Lock()
+	defer sm.m.lock.Unlock()
+
+	// The next nonce of each queue the messages are sent into
+	nonces := make(map[[32]byte]uint64)
+	queues := make(map[[32]byte]database.Batch)
+	for _, msg := range msgs {
+		destinationKey := msg.Destination.Key()
+		queue, exists := queues[destinationKey]
+		if !exists {
+			queueDB := sm.m.queue(sm.chainID, msg.Destination)
+			nonce, err := getNonce(queueDB)
+			if err != nil {
+				return err
+			}
+			queue = queueDB.NewBatch()
+			queues[destinationKey] = queue
+			nonces[destinationKey] = nonce
+		}
+
+		msg.Source = sm.chainID
+		msg.Nonce = nonces[destinationKey]
+		bytes, err := marshal(msg)
+		if err != nil {
+			return err
+		}
+		if err := queue.Put(packNonce(msg.Nonce), bytes); err != nil {
+			return err
+		}
+		nonces[destinationKey]++
+	}
+
+	batches := []database.Batch{batch}
+	for destinationKey, queue := range queues {
+		if err := queue.Put(nonceKey, packNonce(nonces[destinationKey])); err != nil {
+			return err
+		}
+		batches = append(batches, queue)
+	}
+	return writeAll(batches...)
+}
+
+// Receive implements the snow.SharedMemory interface
+func (sm *SharedMemory) Receive(source ids.ID, limit int) ([]*snow.Message, error) {
+	sm.m.lock.Lock()
+	defer sm.m.lock.Unlock()
+
+	queue := sm.m.queue(source, sm.chainID)
+	iter := queue.NewIterator()
+	defer iter.Release()
+
+	msgs := []*snow.Message(nil)
+	invalid := [][]byte(nil)
+	for len(msgs) < limit && iter.Next() {
+		key := iter.Key()
+		if len(key) != wrappers.LongLen {
+			continue // The nonce counter
+		}
+		msg, err := unmarshal(iter.Value(), source, sm.chainID)
+		if err == nil {
+			err = sm.verify(msg)
+		}
+		if err == errUnknownMessageType {
+			continue
+		}
+		if err != nil {
+			sm.m.log.Debug("dropping message from %s to %s: %s", source, sm.chainID, err)
+			invalid = append(invalid, append([]byte(nil), key...))
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range invalid {
+		if err := queue.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
+}
+
+// Consume implements the snow.SharedMemory interface
+func (sm *SharedMemory) Consume(batch database.Batch, msgs ...*snow.Message) error {
+	sm.m.lock.Lock()
+	defer sm.m.lock.Unlock()
+
+	consumed := ids.Set{}
+	queues := make(map[[32]byte]database.Batch)
+	for _, msg := range msgs {
+		if !msg.Destination.Equals(sm.chainID) {
+			return errWrongDestination
+		}
+		queueDB := sm.m.queue(msg.Source, sm.chainID)
+		key := packNonce(msg.Nonce)
+		if has, err := queueDB.Has(key); err != nil {
+			return err
+		} else if !has || consumed.Contains(msg.ID) {
+			return fmt.Errorf("%w: message %s", errMessageNotInQueue, msg.ID)
+		}
+		consumed.Add(msg.ID)
+
+		sourceKey := msg.Source.Key()
+		queue, exists := queues[sourceKey]
+		if !exists {
+			queue = queueDB.NewBatch()
+			queues[sourceKey] = queue
+		}
+		if err := queue.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	batches := []database.Batch{batch}
+	for _, queue := range queues {
+		batches = append(batches, queue)
+	}
+	return writeAll(batches...)
+}
+
+// verify [msg] with the verifier of its type
+// Assumes [sm.m.lock] is held
+func (sm *SharedMemory) verify(msg *snow.Message) error {
+	verifier, exists := sm.m.verifiers[sm.chainID.Key()][msg.Type]
+	if !exists {
+		return errUnknownMessageType
+	}
+	return verifier.VerifyMessage(msg)
+}
+
+// writeAll writes [batches] in one atomic write. The batches must be of
+// databases that are stored in the same database. Nil batches are skipped.
+func writeAll(batches ...database.Batch) error {
+	var base database.Batch
+	for _, batch := range batches {
+		switch {
+		case batch == nil:
+		case base == nil:
+			base = batch.Inner()
+		default:
+			if err := batch.Inner().Replay(base); err != nil {
+				return err
+			}
+		}
+	}
+	if base == nil {
+		return nil
+	}
+	return base.Write()
+}
+
+// getNonce returns the nonce of the next message sent into [queue]
+func getNonce(queue database.Database) (uint64, error) {
+	nonceBytes, err := queue.Get(nonceKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: nonceBytes}
+	nonce := p.UnpackLong()
+	return nonce, p.Err
+}
+
+// packNonce returns the key of the message with [nonce] in its queue. The keys
+// sort in the order the messages were sent.
+func packNonce(nonce uint64) []byte {
+	p := wrappers.Packer{MaxSize: wrappers.LongLen}
+	p.PackLong(nonce)
+	return p.Bytes
+}
+
+// marshal [msg] and set its ID
+func marshal(msg *snow.Message) ([]byte, error) {
+	p := wrappers.Packer{MaxSize: maxMessageSize}
+	p.PackFixedBytes(msg.Source.Bytes())
+	p.PackFixedBytes(msg.Destination.Bytes())
+	p.PackInt(msg.Type)
+	p.PackLong(msg.Nonce)
+	p.PackBytes(msg.Payload)
+	if p.Errored() {
+		return nil, p.Err
+	}
+	msg.ID = ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+	return p.Bytes, nil
+}
+
+// unmarshal a message that was sent from [source] to [destination]
+func unmarshal(bytes []byte, source, destination ids.ID) (*snow.Message, error) {
+	p := wrappers.Packer{Bytes: bytes}
+	sourceBytes := p.UnpackFixedBytes(hashing.HashLen)
+	destinationBytes := p.UnpackFixedBytes(hashing.HashLen)
+	msg := &snow.Message{
+		Type:    p.UnpackInt(),
+		Nonce:   p.UnpackLong(),
+		Payload: p.UnpackBytes(),
+	}
+	if p.Errored() || p.Offset != len(bytes) {
+		return nil, errMalformedMessageData
+	}
+	msg.Source, _ = ids.ToID(sourceBytes)
+	msg.Destination, _ = ids.ToID(destinationBytes)
+	if !msg.Source.Equals(source) || !msg.Destination.Equals(destination) {
+		return nil, errMalformedMessageData
+	}
+	msg.ID = ids.NewID(hashing.ComputeHash256Array(bytes))
+	return msg, nil
+}
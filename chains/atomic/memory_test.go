@@ -0,0 +1,235 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testVerifier struct{ err error }
+
+func (v *testVerifier) VerifyMessage(*snow.Message) error { return v.err }
+
+// send a message of type [msgType] carrying [payload] from [sm] to [destination]
+func send(sm *SharedMemory, destination ids.ID, msgType uint32, payload []byte) error {
+	return sm.Send(nil, &snow.Message{Destination: destination, Type: msgType, Payload: payload})
+}
+
+func TestSharedMemory(t *testing.T) {
+	db := memdb.New()
+	chainA := ids.NewID([32]byte{'a'})
+	chainB := ids.NewID([32]byte{'b'})
+
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, db)
+	smA := m.NewSharedMemory(chainA)
+	smB := m.NewSharedMemory(chainB)
+
+	if err := send(smA, chainA, 0, nil); err != errSameChain {
+		t.Fatalf("should have failed to send a message to the same chain")
+	}
+	if err := send(smA, chainB, 0, make([]byte, snow.MaxMessagePayloadSize+1)); err != errPayloadTooLarge {
+		t.Fatalf("should have failed to send a payload that's too large")
+	}
+
+	if err := smB.RegisterVerifier(0, &testVerifier{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := smB.RegisterVerifier(0, &testVerifier{}); err != errVerifierRegistered {
+		t.Fatalf("should have failed to register a second verifier")
+	}
+	if err := smB.RegisterVerifier(1, &testVerifier{err: errors.New("invalid")}); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := [][]byte{{1}, {2}, {3}}
+	for _, payload := range payloads {
+		if err := send(smA, chainB, 0, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := send(smA, chainB, 1, []byte{4}); err != nil { // Fails verification
+		t.Fatal(err)
+	}
+	if err := send(smA, chainB, 2, []byte{5}); err != nil { // No verifier
+		t.Fatal(err)
+	}
+
+	if msgs, err := smA.Receive(chainB, 10); err != nil {
+		t.Fatal(err)
+	} else if len(msgs) != 0 {
+		t.Fatalf("chain A shouldn't have received any messages")
+	}
+
+	msgs, err := smB.Receive(chainA, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("should have received 2 messages but received %d", len(msgs))
+	}
+	for i, msg := range msgs {
+		if !bytes.Equal(msg.Payload, payloads[i]) {
+			t.Fatalf("received message %d out of order", i)
+		}
+		if !msg.Source.Equals(chainA) || !msg.Destination.Equals(chainB) {
+			t.Fatalf("message %d has the wrong source or destination", i)
+		}
+	}
+	if err := smB.Consume(nil, msgs...); err != nil {
+		t.Fatal(err)
+	}
+	if err := smB.Consume(nil, msgs[0]); !errors.Is(err, errMessageNotInQueue) {
+		t.Fatalf("should have failed to consume a message twice")
+	}
+	if err := smA.Consume(nil, msgs[0]); err != errWrongDestination {
+		t.Fatalf("should have failed to consume a message sent to another chain")
+	}
+
+	// Restart over the same database
+	m = Memory{}
+	m.Initialize(logging.NoLog{}, db)
+	smA = m.NewSharedMemory(chainA)
+	smB = m.NewSharedMemory(chainB)
+	if err := smB.RegisterVerifier(0, &testVerifier{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := smB.RegisterVerifier(1, &testVerifier{err: errors.New("invalid")}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err = smB.Receive(chainA, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || !bytes.Equal(msgs[0].Payload, payloads[2]) {
+		t.Fatalf("should have received only the last unconsumed message")
+	}
+	lastID := msgs[0].ID
+
+	// The invalid message was dropped, so it isn't received once it's valid
+	if err := smB.RegisterVerifier(2, &testVerifier{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(smA, chainB, 0, []byte{6}); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err = smB.Receive(chainA, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("should have received 3 messages but received %d", len(msgs))
+	}
+	if !msgs[0].ID.Equals(lastID) {
+		t.Fatalf("message ID changed across restarts")
+	}
+	if !bytes.Equal(msgs[1].Payload, []byte{5}) || !bytes.Equal(msgs[2].Payload, []byte{6}) {
+		t.Fatalf("received messages out of order")
+	}
+	if msgs[2].Nonce != 5 {
+		t.Fatalf("nonce should have been 5 but was %d", msgs[2].Nonce)
+	}
+}
+
+// Sending and consuming messages is written atomically with the chain's state
+func TestSharedMemoryWritesBatch(t *testing.T) {
+	db := memdb.New()
+	chainA := ids.NewID([32]byte{'a'})
+	chainB := ids.NewID([32]byte{'b'})
+
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, prefixdb.New([]byte("atomic"), db))
+	smA := m.NewSharedMemory(chainA)
+	smB := m.NewSharedMemory(chainB)
+	if err := smB.RegisterVerifier(0, &testVerifier{}); err != nil {
+		t.Fatal(err)
+	}
+	dbA := versiondb.New(prefixdb.New(chainA.Bytes(), db))
+	dbB := versiondb.New(prefixdb.New(chainB.Bytes(), db))
+
+	key := []byte("state")
+	if err := dbA.Put(key, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	batch, err := dbA.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed send writes neither the message nor the state
+	if err := smA.Send(batch, &snow.Message{Destination: chainA}); err != errSameChain {
+		t.Fatalf("should have failed to send a message to the same chain")
+	}
+	if has, err := prefixdb.New(chainA.Bytes(), db).Has(key); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("a failed send shouldn't have written the chain's state")
+	}
+
+	msgs := []*snow.Message{
+		{Destination: chainB, Payload: []byte{1}},
+		{Destination: chainB, Payload: []byte{2}},
+	}
+	if err := smA.Send(batch, msgs...); err != nil {
+		t.Fatal(err)
+	}
+	dbA.Abort()
+	if msgs[0].Nonce != 0 || msgs[1].Nonce != 1 || !msgs[1].Source.Equals(chainA) {
+		t.Fatalf("should have set the source and nonces of the messages")
+	}
+	if value, err := dbA.Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte{1}) {
+		t.Fatalf("should have written the chain's state")
+	}
+
+	received, err := smB.Receive(chainA, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 || !received[1].ID.Equals(msgs[1].ID) {
+		t.Fatalf("should have received the messages")
+	}
+
+	if err := dbB.Put(key, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	batch, err = dbB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Consuming the same message twice fails, without writing the state
+	if err := smB.Consume(batch, received[0], received[0]); !errors.Is(err, errMessageNotInQueue) {
+		t.Fatalf("should have failed to consume a message twice")
+	}
+	if has, err := prefixdb.New(chainB.Bytes(), db).Has(key); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("a failed consume shouldn't have written the chain's state")
+	}
+	if err := smB.Consume(batch, received[0]); err != nil {
+		t.Fatal(err)
+	}
+	dbB.Abort()
+	if has, err := prefixdb.New(chainB.Bytes(), db).Has(key); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("should have written the chain's state")
+	}
+	if received, err := smB.Receive(chainA, 10); err != nil {
+		t.Fatal(err)
+	} else if len(received) != 1 || !received[0].ID.Equals(msgs[1].ID) {
+		t.Fatalf("should have consumed the first message")
+	}
+}
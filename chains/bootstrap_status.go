@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+// BootstrapStatus is the bootstrapping progress of a chain
+type BootstrapStatus uint32
+
+// List of possible bootstrap status values
+const (
+	// Unknown chains haven't been created on this node
+	Unknown BootstrapStatus = iota
+	// Blocked chains are waiting for their dependencies to finish bootstrapping
+	Blocked
+	// Bootstrapping chains have been created and are bootstrapping
+	Bootstrapping
+	// Bootstrapped chains have finished bootstrapping
+	Bootstrapped
+)
+
+func (s BootstrapStatus) String() string {
+	switch s {
+	case Unknown:
+		return "Unknown"
+	case Blocked:
+		return "Blocked"
+	case Bootstrapping:
+		return "Bootstrapping"
+	case Bootstrapped:
+		return "Bootstrapped"
+	default:
+		return "Invalid status"
+	}
+}
@@ -4,15 +4,25 @@
 package chains
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/engine/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/avalanche/state"
@@ -26,6 +36,8 @@ import (
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/vms"
 
 	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
@@ -35,9 +47,21 @@ import (
 	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
 )
 
+var (
+	errUnknownChain    = errors.New("chain isn't running")
+	errNoAcceptanceLog = errors.New("chains don't remember the decisions they accept, as the acceptance window is 0")
+	errNoSnapshotDir   = errors.New("no snapshot directory is configured")
+	errNotBootstrapped = errors.New("chain hasn't finished bootstrapping")
+	errDiverged        = errors.New("chain's accepted frontier has diverged from its validators'")
+)
+
 const (
 	defaultChannelSize = 1000
 	requestTimeout     = 2 * time.Second
+
+	// The most evidence of misbehavior kept. When more is reported, the oldest
+	// evidence is dropped.
+	maxEvidence = 1024
 )
 
 // Manager manages the chains running on this node.
@@ -51,12 +75,44 @@ type Manager interface {
 	// Return the router this Manager is using to route consensus messages to chains
 	Router() router.Router
 
-	// Create a chain in the future
+	// Create a chain once all of its dependencies have finished bootstrapping
 	CreateChain(ChainParameters)
 
-	// Create a chain now
+	// Create a chain now, regardless of its dependencies
 	ForceCreateChain(ChainParameters)
 
+	// Return the bootstrapping progress of a chain
+	BootstrapStatus(ids.ID) BootstrapStatus
+
+	// Return the resources used by each chain
+	ResourceUsage() []ResourceUsage
+
+	// Return the maximum container sizes of a running chain
+	ContainerLimits(ids.ID) (snow.Limits, error)
+
+	// Return the databases a running chain stores its state in
+	Databases(ids.ID) ([]database.Database, error)
+
+	// Return the most recent evidence of validators misbehaving, oldest first
+	MisbehaviorEvidence() []snow.Evidence
+
+	// Return why the chains are unhealthy, or nil if they're healthy. A chain
+	// is unhealthy while it's bootstrapping, or while its accepted frontier
+	// has diverged from its validators'.
+	Health() error
+
+	// Return the decisions a running chain accepted within the acceptance
+	// window, oldest first
+	RecentAcceptances(ids.ID) ([]Acceptance, error)
+
+	// Write the containers a running chain accepted to a snapshot in the
+	// snapshot directory, and return its path and how many containers it holds
+	ExportSnapshot(ids.ID) (string, int, error)
+
+	// Return the decisions a running chain's consensus is processing, and the
+	// decisions they depend on
+	ConsensusGraph(ids.ID) (snow.Graph, error)
+
 	// Add a registrant [r]. Every time a chain is
 	// created, [r].RegisterChain([new chain]) is called
 	AddRegistrant(Registrant)
@@ -67,6 +123,10 @@ type Manager interface {
 	// Given an alias, return the ID of the VM associated with that alias
 	LookupVM(string) (ids.ID, error)
 
+	// Return an error if a chain running the VM [vmID], with the feature
+	// extensions [fxIDs], can't be initialized with [genesisData]
+	VerifyGenesis(vmID ids.ID, fxIDs []ids.ID, genesisData []byte) error
+
 	// Return the aliases associated with a chain
 	Aliases(ids.ID) []string
 
@@ -84,6 +144,10 @@ type ChainParameters struct {
 	VMAlias     string   // The ID of the vm this chain is running
 	FxAliases   []string // The IDs of the feature extensions this chain is running
 
+	// The IDs of the chains that must finish bootstrapping before this chain
+	// is created
+	Dependencies []ids.ID
+
 	CustomBeacons validators.Set // Should only be set if the default beacons can't be used.
 }
 
@@ -92,32 +156,71 @@ type manager struct {
 	// That is, [chainID].String() is an alias for the chain, too
 	ids.Aliaser
 
-	log             logging.Logger
-	logFactory      logging.Factory
-	vmManager       vms.Manager // Manage mappings from vm ID --> vm
-	decisionEvents  *triggers.EventDispatcher
-	consensusEvents *triggers.EventDispatcher
-	db              database.Database
-	chainRouter     router.Router         // Routes incoming messages to the appropriate chain
-	sender          sender.ExternalSender // Sends consensus messages to other validators
-	timeoutManager  *timeout.Manager      // Manages request timeouts when sending messages to other validators
-	consensusParams avacon.Parameters     // The consensus parameters (alpha, beta, etc.) for new chains
-	validators      validators.Manager    // Validators validating on this chain
-	registrants     []Registrant          // Those notified when a chain is created
-	nodeID          ids.ShortID           // The ID of this node
-	networkID       uint32                // ID of the network this node is connected to
-	awaiter         Awaiter               // Waits for required connections before running bootstrapping
-	server          *api.Server           // Handles HTTP API calls
-	keystore        *keystore.Keystore
-
-	unblocked     bool
+	log              logging.Logger
+	logFactory       logging.Factory
+	vmManager        vms.Manager // Manage mappings from vm ID --> vm
+	decisionEvents   *triggers.EventDispatcher
+	consensusEvents  *triggers.EventDispatcher
+	db               database.Database
+	chainRouter      router.Router         // Routes incoming messages to the appropriate chain
+	sender           sender.ExternalSender // Sends consensus messages to other validators
+	timeoutManager   *timeout.Manager      // Manages request timeouts when sending messages to other validators
+	consensusParams  avacon.Parameters     // The consensus parameters (alpha, beta, etc.) for new chains
+	validators       validators.Manager    // Validators validating on this chain
+	registrants      []Registrant          // Those notified when a chain is created
+	nodeID           ids.ShortID           // The ID of this node
+	networkID        uint32                // ID of the network this node is connected to
+	awaiter          Awaiter               // Waits for required connections before running bootstrapping
+	server           *api.Server           // Handles HTTP API calls
+	keystore         *keystore.Keystore
+	cpuBudget        float64                      // Fraction of time each chain, other than the P-Chain, may spend processing messages
+	memoryBudget     uint64                       // Bytes of requests each throttled chain may hold back
+	observer         bool                         // If true, chains follow consensus without voting or proposing containers
+	atomicMemory     atomic.Memory                // Passes messages between the chains on this node
+	limits           snow.Limits                  // Maximum sizes of the containers chains issue and accept
+	frontierMonitor  common.FrontierMonitorConfig // How chains compare their accepted frontier with validators'
+	acceptanceWindow time.Duration                // How long chains remember the decisions they accepted, or 0 to not remember them
+	snapshotDir      string                       // Where chain snapshots are imported from and exported to, or "" to not use snapshots
+	chainConfigDir   string                       // Where the configurations of chains are read from, or "" if chains aren't configured
+	clock            timer.Clock                  // The clock chains run by, which may run faster than real time
+	seed             int64                        // Seeds the sources of randomness chains' consensus samples from
+
+	// Protects the bootstrap status of the chains and the blocked chains
+	lock sync.Mutex
+	// Key: Chain ID
+	// Value: Bootstrapping progress of the chain
+	status map[[32]byte]BootstrapStatus
+	// Chains waiting for their dependencies to finish bootstrapping
 	blockedChains []ChainParameters
+	// Key: Chain ID
+	// Value: Handler passing messages to the chain's consensus engine
+	handlers map[[32]byte]*handler.Handler
+	// Key: Chain ID
+	// Value: The databases the chain stores its state in
+	dbs map[[32]byte][]database.Database
+	// Key: Chain ID
+	// Value: The decisions the chain accepted within the acceptance window
+	acceptances map[[32]byte]*acceptanceLog
+	// Key: Chain ID
+	// Value: Writes the containers the chain accepted to a snapshot
+	exporters map[[32]byte]func(*common.SnapshotWriter) (int, error)
+	// Key: Chain ID
+	// Value: Returns the decisions the chain's consensus is processing
+	graphs map[[32]byte]func() snow.Graph
+	// Key: Chain ID
+	// Value: Returns true if the chain's accepted frontier has diverged from
+	// its validators'
+	divergences map[[32]byte]func() bool
+	// Evidence of validators misbehaving on the chains, oldest first
+	evidence []snow.Evidence
 }
 
 // New returns a new Manager where:
-//     <db> is this node's database
-//     <sender> sends messages to other validators
-//     <validators> validate this chain
+//
+//	<db> is this node's database
+//	<sender> sends messages to other validators
+//	<validators> validate this chain
+//
 // TODO: Make this function take less arguments
 func New(
 	log logging.Logger,
@@ -133,47 +236,97 @@ func New(
 	nodeID ids.ShortID,
 	networkID uint32,
 	awaiter Awaiter,
+	latencies timeout.Latencies,
 	server *api.Server,
 	keystore *keystore.Keystore,
+	cpuBudget float64,
+	memoryBudget uint64,
+	observer bool,
+	limits snow.Limits,
+	frontierMonitor common.FrontierMonitorConfig,
+	acceptanceWindow time.Duration,
+	snapshotDir string,
+	chainConfigDir string,
+	clock timer.Clock,
+	seed int64,
 ) Manager {
+	// Request timeouts bound real network round trips, so they're measured in
+	// real time even if the chains' clock runs faster
 	timeoutManager := timeout.Manager{}
 	timeoutManager.Initialize(requestTimeout)
+	timeoutManager.SetLatencies(latencies)
+	if err := timeoutManager.InitializeLeakDetection(log, consensusParams.Metrics); err != nil {
+		log.Error("failed to register the request leak metrics: %s", err)
+	}
 	go log.RecoverAndPanic(timeoutManager.Dispatch)
 
 	router.Initialize(log, &timeoutManager)
+	if metered, ok := router.(interface {
+		InitializeMetrics(prometheus.Registerer) error
+	}); ok {
+		if err := metered.InitializeMetrics(consensusParams.Metrics); err != nil {
+			log.Error("failed to register the router's metrics: %s", err)
+		}
+	}
 
 	m := &manager{
-		log:             log,
-		logFactory:      logFactory,
-		vmManager:       vmManager,
-		decisionEvents:  decisionEvents,
-		consensusEvents: consensusEvents,
-		db:              db,
-		chainRouter:     router,
-		sender:          sender,
-		timeoutManager:  &timeoutManager,
-		consensusParams: consensusParams,
-		validators:      validators,
-		nodeID:          nodeID,
-		networkID:       networkID,
-		awaiter:         awaiter,
-		server:          server,
-		keystore:        keystore,
+		log:              log,
+		logFactory:       logFactory,
+		vmManager:        vmManager,
+		decisionEvents:   decisionEvents,
+		consensusEvents:  consensusEvents,
+		db:               db,
+		chainRouter:      router,
+		sender:           sender,
+		timeoutManager:   &timeoutManager,
+		consensusParams:  consensusParams,
+		validators:       validators,
+		nodeID:           nodeID,
+		networkID:        networkID,
+		awaiter:          awaiter,
+		server:           server,
+		keystore:         keystore,
+		cpuBudget:        cpuBudget,
+		memoryBudget:     memoryBudget,
+		observer:         observer,
+		limits:           limits,
+		frontierMonitor:  frontierMonitor,
+		acceptanceWindow: acceptanceWindow,
+		snapshotDir:      snapshotDir,
+		chainConfigDir:   chainConfigDir,
+		clock:            clock,
+		seed:             seed,
+		status:           make(map[[32]byte]BootstrapStatus),
+		handlers:         make(map[[32]byte]*handler.Handler),
+		dbs:              make(map[[32]byte][]database.Database),
+		acceptances:      make(map[[32]byte]*acceptanceLog),
+		exporters:        make(map[[32]byte]func(*common.SnapshotWriter) (int, error)),
+		graphs:           make(map[[32]byte]func() snow.Graph),
+		divergences:      make(map[[32]byte]func() bool),
 	}
 	m.Initialize()
+	m.atomicMemory.Initialize(log, prefixdb.New([]byte("atomic"), db))
 	return m
 }
 
 // Router that this chain manager is using to route consensus messages to chains
 func (m *manager) Router() router.Router { return m.chainRouter }
 
-// Create a chain
+// Create a chain once all of its dependencies have finished bootstrapping
 func (m *manager) CreateChain(chain ChainParameters) {
-	if !m.unblocked {
+	m.lock.Lock()
+	if !m.dependenciesBootstrapped(chain) {
+		m.log.Info("chain %s is waiting for its dependencies to finish bootstrapping", chain.ID)
+		if m.status[chain.ID.Key()] == Unknown {
+			m.status[chain.ID.Key()] = Blocked
+		}
 		m.blockedChains = append(m.blockedChains, chain)
-	} else {
-		m.ForceCreateChain(chain)
+		m.lock.Unlock()
+		return
 	}
+	m.lock.Unlock()
+
+	m.ForceCreateChain(chain)
 }
 
 // Create a chain
@@ -247,6 +400,12 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		HTTP:                m.server,
 		Keystore:            m.keystore.NewBlockchainKeyStore(chain.ID),
 		BCLookup:            m,
+		SharedMemory:        m.atomicMemory.NewSharedMemory(chain.ID),
+		Limits:              m.limits,
+		Clock:               m.clock,
+		// Each chain has its own source, so its sampling is reproducible from
+		// the seed no matter what the other chains do
+		Source: random.NewSource(m.seed ^ int64(binary.BigEndian.Uint64(chain.ID.Bytes()))),
 	}
 	consensusParams := m.consensusParams
 	if alias, err := m.PrimaryAlias(ctx.ChainID); err == nil {
@@ -254,6 +413,27 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	} else {
 		consensusParams.Namespace = fmt.Sprintf("gecko_%s", ctx.ChainID)
 	}
+	ctx.Namespace = consensusParams.Namespace
+	if ctx.Config, err = m.chainConfig(ctx.ChainID); err != nil {
+		m.log.Error("error while reading the chain's configuration %s", err)
+		return
+	}
+	ctx.Metrics = consensusParams.Metrics
+	ctx.Misbehavior.OnReport(m.recordEvidence)
+	if err := m.decisionEvents.RegisterChain(ctx.ChainID, "hooks", &ctx.Hooks); err != nil {
+		m.log.Error("error while registering the chain's hooks %s", err)
+		return
+	}
+	if m.acceptanceWindow > 0 {
+		acceptances := newAcceptanceLog(&m.clock, m.acceptanceWindow)
+		if err := m.decisionEvents.RegisterChain(ctx.ChainID, "acceptances", acceptances); err != nil {
+			m.log.Error("error while registering the chain's acceptance log %s", err)
+			return
+		}
+		m.lock.Lock()
+		m.acceptances[ctx.ChainID.Key()] = acceptances
+		m.lock.Unlock()
+	}
 
 	// The validators of this blockchain
 	validators, ok := m.validators.GetValidatorSet(ids.Empty) // TODO: Change argument to chain.SubnetID
@@ -267,6 +447,8 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		beacons = chain.CustomBeacons
 	}
 
+	m.setStatus(chain.ID, Bootstrapping)
+
 	switch vm := vm.(type) {
 	case avalanche.DAGVM:
 		err := m.createAvalancheChain(
@@ -280,6 +462,7 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		)
 		if err != nil {
 			m.log.Error("error while creating new avalanche vm %s", err)
+			m.setStatus(chain.ID, Unknown)
 			return
 		}
 	case smeng.ChainVM:
@@ -294,10 +477,12 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		)
 		if err != nil {
 			m.log.Error("error while creating new snowman vm %s", err)
+			m.setStatus(chain.ID, Unknown)
 			return
 		}
 	default:
 		m.log.Error("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
+		m.setStatus(chain.ID, Unknown)
 		return
 	}
 
@@ -311,11 +496,188 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
-func (m *manager) unblockChains() {
-	m.unblocked = true
-	blocked := m.blockedChains
-	m.blockedChains = nil
-	for _, chain := range blocked {
+// Implements Manager.ResourceUsage
+func (m *manager) ResourceUsage() []ResourceUsage {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	usage := []ResourceUsage(nil)
+	for chainKey, handler := range m.handlers {
+		usage = append(usage, ResourceUsage{
+			ChainID:      ids.NewID(chainKey),
+			CPUBudget:    handler.CPUBudget(),
+			MemoryBudget: handler.MemoryBudget(),
+			Usage:        handler.Usage(),
+		})
+	}
+	return usage
+}
+
+// Implements Manager.ContainerLimits
+func (m *manager) ContainerLimits(chainID ids.ID) (snow.Limits, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	handler, exists := m.handlers[chainID.Key()]
+	if !exists {
+		return snow.Limits{}, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+	return handler.Context().Limits, nil
+}
+
+// Implements Manager.Databases
+func (m *manager) Databases(chainID ids.ID) ([]database.Database, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	dbs, exists := m.dbs[chainID.Key()]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+	return dbs, nil
+}
+
+// addDatabases records that the chain [chainID] stores its state in [dbs]
+func (m *manager) addDatabases(chainID ids.ID, dbs ...database.Database) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.dbs[chainID.Key()] = dbs
+}
+
+// Implements Manager.MisbehaviorEvidence
+func (m *manager) MisbehaviorEvidence() []snow.Evidence {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	evidence := make([]snow.Evidence, len(m.evidence))
+	copy(evidence, m.evidence)
+	return evidence
+}
+
+// Implements Manager.Health
+func (m *manager) Health() error {
+	m.lock.Lock()
+	for chainKey, status := range m.status {
+		if status != Bootstrapped {
+			m.lock.Unlock()
+			return fmt.Errorf("%w: %s is %s", errNotBootstrapped, ids.NewID(chainKey), status)
+		}
+	}
+	divergences := make(map[[32]byte]func() bool, len(m.divergences))
+	for chainKey, diverged := range m.divergences {
+		divergences[chainKey] = diverged
+	}
+	m.lock.Unlock()
+
+	// The chains' locks are grabbed without holding [m.lock]
+	for chainKey, diverged := range divergences {
+		if diverged() {
+			return fmt.Errorf("%w: %s", errDiverged, ids.NewID(chainKey))
+		}
+	}
+	return nil
+}
+
+// Implements Manager.RecentAcceptances
+func (m *manager) RecentAcceptances(chainID ids.ID) ([]Acceptance, error) {
+	if m.acceptanceWindow <= 0 {
+		return nil, errNoAcceptanceLog
+	}
+
+	m.lock.Lock()
+	acceptances, exists := m.acceptances[chainID.Key()]
+	m.lock.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+	return acceptances.Recent(), nil
+}
+
+// recordEvidence keeps [evidence] of a validator misbehaving
+func (m *manager) recordEvidence(evidence snow.Evidence) {
+	m.log.Warn("validator %s misbehaved on chain %s: %s",
+		evidence.ValidatorID, evidence.ChainID, evidence.Kind)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(m.evidence) == maxEvidence {
+		copy(m.evidence, m.evidence[1:])
+		m.evidence = m.evidence[:maxEvidence-1]
+	}
+	m.evidence = append(m.evidence, evidence)
+}
+
+// Track the resources used by [handler]. Every chain other than the P-Chain is
+// limited to the CPU budget so that a misbehaving chain can't prevent this node
+// from participating in the default subnet's consensus. If this node is an
+// observer, [handler] only follows consensus.
+func (m *manager) addHandler(chainID ids.ID, handler *handler.Handler) {
+	if !chainID.Equals(ids.Empty) { // The P-Chain's ID is ids.Empty
+		handler.SetCPUBudget(m.cpuBudget)
+		handler.SetMemoryBudget(m.memoryBudget)
+	}
+	handler.SetObserver(m.observer)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handlers[chainID.Key()] = handler
+}
+
+// Implements Manager.BootstrapStatus
+func (m *manager) BootstrapStatus(chainID ids.ID) BootstrapStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.status[chainID.Key()]
+}
+
+func (m *manager) setStatus(chainID ids.ID, status BootstrapStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if status == Unknown {
+		delete(m.status, chainID.Key())
+	} else {
+		m.status[chainID.Key()] = status
+	}
+}
+
+// Returns true iff every dependency of [chain] has finished bootstrapping
+// Assumes [m.lock] is held
+func (m *manager) dependenciesBootstrapped(chain ChainParameters) bool {
+	for _, dependency := range chain.Dependencies {
+		if m.status[dependency.Key()] != Bootstrapped {
+			return false
+		}
+	}
+	return true
+}
+
+// Marks [chainID] as bootstrapped and creates the blocked chains whose
+// dependencies have now all finished bootstrapping
+func (m *manager) markBootstrapped(chainID ids.ID) {
+	m.log.Info("chain %s finished bootstrapping", chainID)
+
+	m.lock.Lock()
+	m.status[chainID.Key()] = Bootstrapped
+
+	unblocked := []ChainParameters(nil)
+	stillBlocked := []ChainParameters(nil)
+	for _, chain := range m.blockedChains {
+		if m.dependenciesBootstrapped(chain) {
+			unblocked = append(unblocked, chain)
+		} else {
+			stillBlocked = append(stillBlocked, chain)
+		}
+	}
+	m.blockedChains = stillBlocked
+	m.lock.Unlock()
+
+	for _, chain := range unblocked {
 		m.ForceCreateChain(chain)
 	}
 }
@@ -338,6 +700,7 @@ func (m *manager) createAvalancheChain(
 	vertexDB := prefixdb.New([]byte("vertex"), db)
 	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bootstrapping"), db)
 	txBootstrappingDB := prefixdb.New([]byte("tx_bootstrapping"), db)
+	m.addDatabases(ctx.ChainID, vmDB, vertexDB, vertexBootstrappingDB, txBootstrappingDB)
 
 	vtxBlocker, err := queue.New(vertexBootstrappingDB)
 	if err != nil {
@@ -361,11 +724,23 @@ func (m *manager) createAvalancheChain(
 	vtxState := &state.Serializer{}
 	vtxState.Initialize(ctx, vm, vertexDB)
 
+	// A fresh chain accepts the vertices of its snapshot, if it has one, so
+	// that it only has to fetch the vertices accepted since from its peers
+	if len(vtxState.Edge()) == 0 {
+		m.importSnapshot(ctx, ctx.Limits.MaxVertexSize, func(r *common.SnapshotReader) (int, error) {
+			return avaeng.ImportSnapshot(vtxState, r)
+		})
+	}
+	m.addExporter(ctx, func(w *common.SnapshotWriter) (int, error) {
+		return avaeng.ExportSnapshot(vtxState, w)
+	})
+
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
 
 	// The engine handles consensus
+	consensus := &avacon.Topological{}
 	engine := avaeng.Transitive{
 		Config: avaeng.Config{
 			BootstrapConfig: avaeng.BootstrapConfig{
@@ -384,19 +759,29 @@ func (m *manager) createAvalancheChain(
 				Beacons:    beacons,
 				Alpha:      (beacons.Len() + 1) / 2,
 				Sender:     &sender,
+
+				FrontierMonitor: m.frontierMonitor,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
 			State:      vtxState,
 			VM:         vm,
+			Bootstrapped: func() {
+				ctx.Hooks.Bootstrapped()
+				m.markBootstrapped(ctx.ChainID)
+			},
 		},
 		Params:    consensusParams,
-		Consensus: &avacon.Topological{},
+		Consensus: consensus,
 	})
+	m.addGraph(ctx, consensus.Graph)
+	m.addDivergence(ctx, engine.FrontierDiverged)
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
 	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	handler.SetMaxContainerSize(ctx.Limits.MaxVertexSize)
+	m.addHandler(ctx.ChainID, handler)
 
 	// Allows messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
@@ -435,6 +820,8 @@ func (m *manager) createSnowmanChain(
 	db := prefixdb.New(ctx.ChainID.Bytes(), m.db)
 	vmDB := prefixdb.New([]byte("vm"), db)
 	bootstrappingDB := prefixdb.New([]byte("bootstrapping"), db)
+	engineDB := prefixdb.New([]byte("engine"), db)
+	m.addDatabases(ctx.ChainID, vmDB, bootstrappingDB, engineDB)
 
 	blocked, err := queue.New(bootstrappingDB)
 	if err != nil {
@@ -450,11 +837,26 @@ func (m *manager) createSnowmanChain(
 		return err
 	}
 
+	// A fresh chain, which has only accepted its genesis block, accepts the
+	// blocks of its snapshot, if it has one, so that it only has to fetch the
+	// blocks accepted since from its peers
+	if fresh, err := onlyGenesisAccepted(vm); err != nil {
+		return err
+	} else if fresh {
+		m.importSnapshot(ctx, ctx.Limits.MaxBlockSize, func(r *common.SnapshotReader) (int, error) {
+			return smeng.ImportSnapshot(vm, r)
+		})
+	}
+	m.addExporter(ctx, func(w *common.SnapshotWriter) (int, error) {
+		return smeng.ExportSnapshot(vm, w)
+	})
+
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
 
 	// The engine handles consensus
+	consensus := &smcon.Topological{}
 	engine := smeng.Transitive{}
 	engine.Initialize(smeng.Config{
 		BootstrapConfig: smeng.BootstrapConfig{
@@ -464,18 +866,28 @@ func (m *manager) createSnowmanChain(
 				Beacons:    beacons,
 				Alpha:      (beacons.Len() + 1) / 2,
 				Sender:     &sender,
+
+				FrontierMonitor: m.frontierMonitor,
+			},
+			Blocked: blocked,
+			VM:      vm,
+			Bootstrapped: func() {
+				ctx.Hooks.Bootstrapped()
+				m.markBootstrapped(ctx.ChainID)
 			},
-			Blocked:      blocked,
-			VM:           vm,
-			Bootstrapped: m.unblockChains,
 		},
 		Params:    consensusParams,
-		Consensus: &smcon.Topological{},
+		Consensus: consensus,
+		DB:        engineDB,
 	})
+	m.addGraph(ctx, consensus.Graph)
+	m.addDivergence(ctx, engine.FrontierDiverged)
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
 	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	handler.SetMaxContainerSize(ctx.Limits.MaxBlockSize)
+	m.addHandler(ctx.ChainID, handler)
 
 	// Allow incoming messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
@@ -497,12 +909,222 @@ func (m *manager) createSnowmanChain(
 	return nil
 }
 
+// onlyGenesisAccepted returns true if the only block [vm] accepted is its
+// genesis block
+func onlyGenesisAccepted(vm smeng.ChainVM) (bool, error) {
+	lastAccepted, err := vm.GetBlock(vm.LastAccepted())
+	if err != nil {
+		return false, err
+	}
+	return lastAccepted.Parent().Status() != choices.Accepted, nil
+}
+
+// chainConfig returns the configuration of the chain [chainID], which is read
+// from the file in the chain config directory named after the chain's ID, or
+// one of its aliases, with the extension .json. Returns nil if the chain isn't
+// configured.
+func (m *manager) chainConfig(chainID ids.ID) ([]byte, error) {
+	if m.chainConfigDir == "" {
+		return nil, nil
+	}
+	for _, name := range append([]string{chainID.String()}, m.Aliases(chainID)...) {
+		config, err := ioutil.ReadFile(filepath.Join(m.chainConfigDir, fmt.Sprintf("%s.json", name)))
+		if err == nil {
+			return config, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// snapshotPath returns the path of the snapshot of the chain [chainID]
+func (m *manager) snapshotPath(chainID ids.ID) string {
+	return filepath.Join(m.snapshotDir, fmt.Sprintf("%s.snapshot", chainID))
+}
+
+// importSnapshot accepts the containers of the chain's snapshot, if it has
+// one, with [importer]. A snapshot that can't be imported isn't fatal, as the
+// chain still bootstraps from its peers, so errors are only logged.
+// Assumes [ctx.Lock] is held.
+func (m *manager) importSnapshot(ctx *snow.Context, maxContainerSize int, importer func(*common.SnapshotReader) (int, error)) {
+	if m.snapshotDir == "" {
+		return
+	}
+	path := m.snapshotPath(ctx.ChainID)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		ctx.Log.Warn("couldn't open snapshot %s: %s", path, err)
+		return
+	}
+	defer file.Close()
+
+	r, err := common.NewSnapshotReader(file, ctx.ChainID, maxContainerSize)
+	if err != nil {
+		ctx.Log.Warn("couldn't read snapshot %s: %s", path, err)
+		return
+	}
+	imported, err := importer(r)
+	if err != nil {
+		ctx.Log.Warn("stopped importing snapshot %s after %d containers: %s", path, imported, err)
+		return
+	}
+	ctx.Log.Info("imported %d containers from snapshot %s", imported, path)
+}
+
+// addExporter records that the containers the chain accepted are written to
+// a snapshot by [exporter], which is called with [ctx.Lock] held
+func (m *manager) addExporter(ctx *snow.Context, exporter func(*common.SnapshotWriter) (int, error)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.exporters[ctx.ChainID.Key()] = func(w *common.SnapshotWriter) (int, error) {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		return exporter(w)
+	}
+}
+
+// Implements Manager.ExportSnapshot
+// The chain doesn't process messages while its snapshot is written.
+func (m *manager) ExportSnapshot(chainID ids.ID) (string, int, error) {
+	if m.snapshotDir == "" {
+		return "", 0, errNoSnapshotDir
+	}
+
+	m.lock.Lock()
+	exporter, exists := m.exporters[chainID.Key()]
+	m.lock.Unlock()
+
+	if !exists {
+		return "", 0, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+
+	if err := os.MkdirAll(m.snapshotDir, 0700); err != nil {
+		return "", 0, err
+	}
+	// The snapshot is written to a temporary file, which replaces the chain's
+	// snapshot once it's complete, so a snapshot is never partially written
+	file, err := ioutil.TempFile(m.snapshotDir, fmt.Sprintf("%s.*.tmp", chainID))
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name()) // Fails once the file is renamed
+
+	exported, err := writeSnapshot(file, chainID, exporter)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", exported, err
+	}
+
+	path := m.snapshotPath(chainID)
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", exported, err
+	}
+	return path, exported, nil
+}
+
+// addGraph records that the decisions the chain's consensus is processing are
+// returned by [graph], which is called with [ctx.Lock] held
+func (m *manager) addGraph(ctx *snow.Context, graph func() snow.Graph) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.graphs[ctx.ChainID.Key()] = func() snow.Graph {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		return graph()
+	}
+}
+
+// addDivergence records that whether the chain's accepted frontier has
+// diverged from its validators' is returned by [diverged], which is called
+// with [ctx.Lock] held
+func (m *manager) addDivergence(ctx *snow.Context, diverged func() bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.divergences[ctx.ChainID.Key()] = func() bool {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		return diverged()
+	}
+}
+
+// Implements Manager.ConsensusGraph
+// Consensus only starts once the chain has bootstrapped.
+func (m *manager) ConsensusGraph(chainID ids.ID) (snow.Graph, error) {
+	m.lock.Lock()
+	graph, exists := m.graphs[chainID.Key()]
+	bootstrapped := m.status[chainID.Key()] == Bootstrapped
+	m.lock.Unlock()
+
+	switch {
+	case !exists:
+		return snow.Graph{}, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	case !bootstrapped:
+		return snow.Graph{}, fmt.Errorf("%w: %s", errNotBootstrapped, chainID)
+	}
+	return graph(), nil
+}
+
+// writeSnapshot writes the snapshot of the chain [chainID] to [file] with
+// [exporter], and syncs it
+func writeSnapshot(file *os.File, chainID ids.ID, exporter func(*common.SnapshotWriter) (int, error)) (int, error) {
+	w, err := common.NewSnapshotWriter(file, chainID)
+	if err != nil {
+		return 0, err
+	}
+	exported, err := exporter(w)
+	if err != nil {
+		return exported, err
+	}
+	if err := w.Close(); err != nil {
+		return exported, err
+	}
+	return exported, file.Sync()
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() { m.chainRouter.Shutdown() }
 
 // LookupVM returns the ID of the VM associated with an alias
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.vmManager.Lookup(alias) }
 
+// VerifyGenesis returns an error if a chain running the VM [vmID], with the
+// feature extensions [fxIDs], can't be initialized with [genesisData]. VMs that
+// can't verify genesis data before a chain is created accept any.
+func (m *manager) VerifyGenesis(vmID ids.ID, fxIDs []ids.ID, genesisData []byte) error {
+	vmFactory, err := m.vmManager.GetVMFactory(vmID)
+	if err != nil {
+		return err
+	}
+	vm, ok := vmFactory.New().(common.GenesisVerifier)
+	if !ok {
+		return nil
+	}
+
+	fxs := make([]*common.Fx, len(fxIDs))
+	for i, fxID := range fxIDs {
+		fxFactory, err := m.vmManager.GetVMFactory(fxID)
+		if err != nil {
+			return err
+		}
+		fxs[i] = &common.Fx{
+			ID: fxID,
+			Fx: fxFactory.New(),
+		}
+	}
+	return vm.VerifyGenesis(m.limits, genesisData, fxs)
+}
+
 // Notify registrants [those who want to know about the creation of chains]
 // that the specified chain has been created
 func (m *manager) notifyRegistrants(ctx *snow.Context, vm interface{}) {
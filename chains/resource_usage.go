@@ -0,0 +1,24 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+)
+
+// ResourceUsage is the amount of resources that a chain has used
+type ResourceUsage struct {
+	ChainID ids.ID
+
+	// Fraction of time the chain may spend processing messages, or 0 if the
+	// chain isn't throttled
+	CPUBudget float64
+
+	// Bytes of requests the chain may hold back while throttled, or 0 if they
+	// aren't limited
+	MemoryBudget uint64
+
+	handler.Usage
+}
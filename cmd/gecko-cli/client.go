@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// client calls the JSON-RPC API of a node
+type client struct {
+	// URI of the node's HTTP server, such as http://127.0.0.1:9650
+	uri  string
+	http http.Client
+}
+
+// newClient returns a client of the node served at [uri]. Calls that take
+// longer than [timeout] fail.
+func newClient(uri string, timeout time.Duration) *client {
+	return &client{
+		uri:  strings.TrimSuffix(uri, "/"),
+		http: http.Client{Timeout: timeout},
+	}
+}
+
+// call [method] of the API served at [endpoint], such as "bc/X", with [args]
+// and decode the result into [reply]
+func (c *client) call(endpoint, method string, args, reply interface{}) error {
+	body, err := json2.EncodeClientRequest(method, args)
+	if err != nil {
+		return fmt.Errorf("couldn't encode the call to %s: %w", method, err)
+	}
+
+	url := fmt.Sprintf("%s/ext/%s", c.uri, endpoint)
+	resp, err := c.http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("couldn't call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	// A failed call may still be reported in the body, so the body is decoded
+	// before the status is checked
+	if err := json2.DecodeClientResponse(resp.Body, reply); err != nil {
+		if _, ok := err.(*json2.Error); !ok && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returned status %s", url, resp.Status)
+		}
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	return nil
+}
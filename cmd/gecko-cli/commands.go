@@ -0,0 +1,365 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+// The endpoints of the APIs the commands call
+const (
+	adminEndpoint    = "admin"
+	keystoreEndpoint = "keystore"
+	platformEndpoint = "bc/P"
+)
+
+var (
+	errNoUsername = errors.New("missing flag -username")
+	errNoAddress  = errors.New("missing flag -address")
+	errNoPayer    = errors.New("missing flag -payer")
+	errNoAmount   = errors.New("flag -amount must be positive")
+)
+
+// command is a subcommand of gecko-cli
+type command struct {
+	// What the command does
+	description string
+
+	// run parses the command's flags from [args], calls the node with [c] and
+	// returns the command's result
+	run func(c *client, args []string) (*result, error)
+}
+
+var commands = map[string]command{
+	"create-user": command{
+		description: "Create a user in the node's keystore",
+		run:         createUser,
+	},
+	"create-address": command{
+		description: "Create an address controlled by a user on an AVM chain",
+		run:         createAddress,
+	},
+	"balance": command{
+		description: "Print the balance of an address on an AVM chain, or of an account on the P-Chain",
+		run:         balance,
+	},
+	"stake": command{
+		description: "Add a validator to the default subnet, staking from a user's P-Chain account",
+		run:         stake,
+	},
+	"validator-status": command{
+		description: "Print whether a node is a current or pending validator",
+		run:         validatorStatus,
+	},
+}
+
+// flagSets are the flag sets of the commands that were run, by name, so that
+// their flags can be printed when -help is passed
+var flagSets = map[string]*flag.FlagSet{}
+
+// newFlagSet returns the flag set of the command [name]. Parse errors are
+// returned rather than printed.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Usage = func() {}
+	flagSets[name] = fs
+	return fs
+}
+
+// createUser creates a user in the keystore
+func createUser(c *client, args []string) (*result, error) {
+	fs := newFlagSet("create-user")
+	username := fs.String("username", "", "Name of the user to create")
+	password := fs.String("password", "", "Password of the user to create")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *username == "" {
+		return nil, errNoUsername
+	}
+
+	reply := keystore.CreateUserReply{}
+	if err := c.call(keystoreEndpoint, "keystore.createUser", &keystore.CreateUserArgs{
+		Username: *username,
+		Password: *password,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	return &result{
+		header: []string{"USERNAME", "CREATED"},
+		rows:   [][]string{{*username, fmt.Sprint(reply.Success)}},
+		value:  reply,
+	}, nil
+}
+
+// createAddress creates an address on an AVM chain
+func createAddress(c *client, args []string) (*result, error) {
+	fs := newFlagSet("create-address")
+	username := fs.String("username", "", "Name of the user that controls the address")
+	password := fs.String("password", "", "Password of the user")
+	chain := fs.String("chain", "X", "ID, or alias, of the AVM chain")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *username == "" {
+		return nil, errNoUsername
+	}
+
+	reply := avm.CreateAddressReply{}
+	if err := c.call("bc/"+*chain, "avm.createAddress", &avm.CreateAddressArgs{
+		Username: *username,
+		Password: *password,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	return &result{
+		header: []string{"ADDRESS"},
+		rows:   [][]string{{reply.Address}},
+		value:  reply,
+	}, nil
+}
+
+// balance prints the balance of an address. The balance of an address on the
+// P-Chain is the balance of its account.
+func balance(c *client, args []string) (*result, error) {
+	fs := newFlagSet("balance")
+	address := fs.String("address", "", "Address to print the balance of")
+	asset := fs.String("asset", "AVA", "ID, or alias, of the asset. Ignored on the P-Chain")
+	chain := fs.String("chain", "X", "ID, or alias, of the AVM chain, or P for the P-Chain")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *address == "" {
+		return nil, errNoAddress
+	}
+
+	if *chain == "P" {
+		accountAddress, err := ids.ShortFromString(*address)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse address: %w", err)
+		}
+		reply := platformvm.GetAccountReply{}
+		if err := c.call(platformEndpoint, "platform.getAccount", &platformvm.GetAccountArgs{
+			Address: accountAddress,
+		}, &reply); err != nil {
+			return nil, err
+		}
+		return &result{
+			header: []string{"ADDRESS", "BALANCE", "NONCE"},
+			rows:   [][]string{{reply.Address.String(), fmt.Sprint(uint64(reply.Balance)), fmt.Sprint(uint64(reply.Nonce))}},
+			value:  reply,
+		}, nil
+	}
+
+	reply := avm.GetBalanceReply{}
+	if err := c.call("bc/"+*chain, "avm.getBalance", &avm.GetBalanceArgs{
+		Address: *address,
+		AssetID: *asset,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	return &result{
+		header: []string{"ADDRESS", "ASSET", "BALANCE"},
+		rows:   [][]string{{*address, *asset, fmt.Sprint(uint64(reply.Balance))}},
+		value:  reply,
+	}, nil
+}
+
+// stake adds a validator to the default subnet. The transaction is built,
+// signed by the payer's key in the keystore, and issued.
+func stake(c *client, args []string) (*result, error) {
+	fs := newFlagSet("stake")
+	username := fs.String("username", "", "Name of the user that controls the payer's key")
+	password := fs.String("password", "", "Password of the user")
+	payerStr := fs.String("payer", "", "Address of the P-Chain account the stake is paid from")
+	nodeIDStr := fs.String("node-id", "", "ID of the node that validates. Defaults to the ID of the node called")
+	destinationStr := fs.String("destination", "", "Address of the P-Chain account the stake and reward are returned to. Defaults to the payer")
+	amount := fs.Uint64("amount", 0, "Amount, in nAVA, to stake")
+	startIn := fs.Duration("start-in", 5*time.Minute, "How long from now the validator starts validating")
+	duration := fs.Duration("duration", platformvm.MinimumStakingDuration, "How long the validator validates for")
+	feeRate := fs.Uint("delegation-fee-rate", 0, fmt.Sprintf("Fee, out of %d, the validator charges delegators", platformvm.NumberOfShares))
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	switch {
+	case *username == "":
+		return nil, errNoUsername
+	case *payerStr == "":
+		return nil, errNoPayer
+	case *amount == 0:
+		return nil, errNoAmount
+	}
+
+	payer, err := ids.ShortFromString(*payerStr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse payer: %w", err)
+	}
+	destination := payer
+	if *destinationStr != "" {
+		if destination, err = ids.ShortFromString(*destinationStr); err != nil {
+			return nil, fmt.Errorf("couldn't parse destination: %w", err)
+		}
+	}
+	nodeID := ids.ShortID{}
+	if *nodeIDStr == "" {
+		reply := admin.GetNodeIDReply{}
+		if err := c.call(adminEndpoint, "admin.getNodeID", &admin.GetNodeIDArgs{}, &reply); err != nil {
+			return nil, err
+		}
+		nodeID = reply.NodeID
+	} else if nodeID, err = ids.ShortFromString(*nodeIDStr); err != nil {
+		return nil, fmt.Errorf("couldn't parse node ID: %w", err)
+	}
+
+	account := platformvm.GetAccountReply{}
+	if err := c.call(platformEndpoint, "platform.getAccount", &platformvm.GetAccountArgs{
+		Address: payer,
+	}, &account); err != nil {
+		return nil, err
+	}
+
+	start := time.Now().Add(*startIn)
+	end := start.Add(*duration)
+	stakeAmount := json.Uint64(*amount)
+	unsigned := platformvm.AddDefaultSubnetValidatorResponse{}
+	if err := c.call(platformEndpoint, "platform.addDefaultSubnetValidator", &platformvm.AddDefaultSubnetValidatorArgs{
+		APIDefaultSubnetValidator: platformvm.APIDefaultSubnetValidator{
+			APIValidator: platformvm.APIValidator{
+				StartTime:   json.Uint64(start.Unix()),
+				EndTime:     json.Uint64(end.Unix()),
+				StakeAmount: &stakeAmount,
+				ID:          nodeID,
+			},
+			Destination:       destination,
+			DelegationFeeRate: json.Uint32(*feeRate),
+		},
+		PayerNonce: account.Nonce + 1,
+	}, &unsigned); err != nil {
+		return nil, err
+	}
+
+	signed := platformvm.SignResponse{}
+	if err := c.call(platformEndpoint, "platform.sign", &platformvm.SignArgs{
+		Tx:       unsigned.UnsignedTx,
+		Signer:   payer,
+		Username: *username,
+		Password: *password,
+	}, &signed); err != nil {
+		return nil, err
+	}
+
+	issued := platformvm.IssueTxResponse{}
+	if err := c.call(platformEndpoint, "platform.issueTx", &platformvm.IssueTxArgs{
+		Tx: signed.Tx,
+	}, &issued); err != nil {
+		return nil, err
+	}
+	return &result{
+		header: []string{"TX ID", "NODE ID", "START", "END"},
+		rows:   [][]string{{issued.TxID.String(), nodeID.String(), formatTime(start), formatTime(end)}},
+		value:  issued,
+	}, nil
+}
+
+// validatorStatus prints whether a node is a current, or pending, validator
+// of a subnet
+func validatorStatus(c *client, args []string) (*result, error) {
+	fs := newFlagSet("validator-status")
+	nodeIDStr := fs.String("node-id", "", "ID of the node. Defaults to the ID of the node called")
+	subnetStr := fs.String("subnet", "", "ID of the subnet. Defaults to the default subnet")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var (
+		nodeID   ids.ShortID
+		subnetID ids.ID
+		err      error
+	)
+	if *nodeIDStr == "" {
+		reply := admin.GetNodeIDReply{}
+		if err := c.call(adminEndpoint, "admin.getNodeID", &admin.GetNodeIDArgs{}, &reply); err != nil {
+			return nil, err
+		}
+		nodeID = reply.NodeID
+	} else if nodeID, err = ids.ShortFromString(*nodeIDStr); err != nil {
+		return nil, fmt.Errorf("couldn't parse node ID: %w", err)
+	}
+	if *subnetStr != "" {
+		if subnetID, err = ids.FromString(*subnetStr); err != nil {
+			return nil, fmt.Errorf("couldn't parse subnet: %w", err)
+		}
+	}
+
+	current := platformvm.GetCurrentValidatorsReply{}
+	if err := c.call(platformEndpoint, "platform.getCurrentValidators", &platformvm.GetCurrentValidatorsArgs{
+		SubnetID: subnetID,
+	}, &current); err != nil {
+		return nil, err
+	}
+	pending := platformvm.GetPendingValidatorsReply{}
+	if err := c.call(platformEndpoint, "platform.getPendingValidators", &platformvm.GetPendingValidatorsArgs{
+		SubnetID: subnetID,
+	}, &pending); err != nil {
+		return nil, err
+	}
+
+	status := validatorStatusReply{NodeID: nodeID, Status: "not a validator"}
+	for _, validator := range current.Validators {
+		if validator.ID.Equals(nodeID) {
+			status.Status = "current"
+			status.Validator = &validator
+			break
+		}
+	}
+	if status.Validator == nil {
+		for _, validator := range pending.Validators {
+			if validator.ID.Equals(nodeID) {
+				status.Status = "pending"
+				status.Validator = &validator
+				break
+			}
+		}
+	}
+
+	row := []string{nodeID.String(), status.Status, "", "", ""}
+	if v := status.Validator; v != nil {
+		row[2] = formatTime(time.Unix(int64(v.StartTime), 0))
+		row[3] = formatTime(time.Unix(int64(v.EndTime), 0))
+		switch {
+		case v.StakeAmount != nil:
+			row[4] = fmt.Sprint(uint64(*v.StakeAmount))
+		case v.Weight != nil:
+			row[4] = fmt.Sprint(uint64(*v.Weight))
+		}
+	}
+	return &result{
+		header: []string{"NODE ID", "STATUS", "START", "END", "STAKE"},
+		rows:   [][]string{row},
+		value:  status,
+	}, nil
+}
+
+// validatorStatusReply is the JSON output of validator-status
+type validatorStatusReply struct {
+	NodeID ids.ShortID `json:"nodeID"`
+	// One of "current", "pending" or "not a validator"
+	Status    string                   `json:"status"`
+	Validator *platformvm.APIValidator `json:"validator,omitempty"`
+}
+
+// formatTime formats [t] in UTC
+func formatTime(t time.Time) string { return t.UTC().Format(time.RFC3339) }
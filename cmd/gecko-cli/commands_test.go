@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestValidatorStatus(t *testing.T) {
+	nodeID := ids.NewShortID([20]byte{1})
+	pendingID := ids.NewShortID([20]byte{2})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := struct {
+			Method string `json:"method"`
+			ID     uint64 `json:"id"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Fatal(err)
+		}
+		if r.URL.Path != "/ext/bc/P" {
+			t.Fatalf("called %s at the wrong endpoint %s", call.Method, r.URL.Path)
+		}
+
+		validators := ""
+		switch call.Method {
+		case "platform.getCurrentValidators":
+			validators = fmt.Sprintf(`{"id":"%s","startTime":"0","endtime":"100","stakeAmount":"20"}`, nodeID)
+		case "platform.getPendingValidators":
+			validators = fmt.Sprintf(`{"id":"%s","startTime":"100","endtime":"200","stakeAmount":"10"}`, pendingID)
+		default:
+			t.Fatalf("unexpected call to %s", call.Method)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"validators":[%s]}}`, call.ID, validators)
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, time.Second)
+	tests := []struct {
+		nodeID ids.ShortID
+		status string
+	}{
+		{nodeID: nodeID, status: "current"},
+		{nodeID: pendingID, status: "pending"},
+		{nodeID: ids.NewShortID([20]byte{3}), status: "not a validator"},
+	}
+	for _, test := range tests {
+		res, err := validatorStatus(c, []string{"-node-id", test.nodeID.String()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := res.value.(validatorStatusReply).Status; status != test.status {
+			t.Fatalf("expected %s to be %s but was %s", test.nodeID, test.status, status)
+		}
+
+		table := &bytes.Buffer{}
+		if err := res.print(table, tableFormat); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(table.String(), test.status) {
+			t.Fatalf("expected the table to show the status %s but got:\n%s", test.status, table)
+		}
+	}
+}
+
+func TestCallError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"problem"}}`)
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, time.Second)
+	if err := c.call(platformEndpoint, "platform.getAccount", struct{}{}, &struct{}{}); err == nil || !strings.Contains(err.Error(), "problem") {
+		t.Fatalf("expected the call's error to be returned but got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// main runs a command, such as stake, against the JSON-RPC API of a node, so
+// that common operations don't require hand-written API calls
+func main() {
+	uri := flag.String("uri", "http://127.0.0.1:9650", "URI of the node's HTTP server")
+	format := flag.String("output", tableFormat, fmt.Sprintf("Format of the output. One of {%s, %s}", tableFormat, jsonFormat))
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout of each API call")
+	flag.Usage = func() { usage(os.Stderr) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	name := flag.Arg(0)
+	cmd, exists := commands[name]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	if *format != tableFormat && *format != jsonFormat {
+		fmt.Fprintf(os.Stderr, "%s\n", errUnknownFormat)
+		os.Exit(2)
+	}
+
+	res, err := cmd.run(newClient(*uri, *timeout), flag.Args()[1:])
+	if err == flag.ErrHelp {
+		commandUsage(os.Stdout, name)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		os.Exit(1)
+	}
+	if err := res.print(os.Stdout, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the flags and commands of gecko-cli to [w]
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: gecko-cli [flags] <command> [command flags]\n\nFlags:\n")
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-18s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintf(w, "\nRun gecko-cli <command> -help for the flags of a command\n")
+}
+
+// commandUsage prints the flags of the command [name] to [w]
+func commandUsage(w io.Writer, name string) {
+	fmt.Fprintf(w, "Usage: gecko-cli [flags] %s [command flags]\n\n%s\n\nCommand flags:\n", name, commands[name].description)
+	fs := flagSets[name]
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// The formats results can be printed in
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+)
+
+var errUnknownFormat = fmt.Errorf("output format must be one of {%s, %s}", tableFormat, jsonFormat)
+
+// result is the output of a command. It's printed as a table of [rows] under
+// [header], or as the JSON encoding of [value].
+type result struct {
+	header []string
+	rows   [][]string
+	value  interface{}
+}
+
+// print [r] to [w] in [format]
+func (r *result) print(w io.Writer, format string) error {
+	switch format {
+	case tableFormat:
+		return r.printTable(w)
+	case jsonFormat:
+		out, err := json.MarshalIndent(r.value, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", out)
+		return err
+	default:
+		return errUnknownFormat
+	}
+}
+
+// printTable prints [r] as a table, with its columns aligned
+func (r *result) printTable(w io.Writer) error {
+	for _, row := range r.rows {
+		if len(row) != len(r.header) {
+			return errors.New("each row must have a cell for each column")
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(r.header, "\t"))
+	for _, row := range r.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -23,6 +23,11 @@ type Batch interface {
 
 	// Replay replays the batch contents.
 	Replay(w KeyValueWriter) error
+
+	// Inner returns the batch of the database at the bottom of the stack of
+	// databases that this batch writes to. Replaying this batch's changes into
+	// another batch of that database lets them be written atomically.
+	Inner() Batch
 }
 
 // Batcher wraps the NewBatch method of a backing data store.
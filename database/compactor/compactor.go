@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compactor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const day = 24 * time.Hour
+
+// Compactor compacts databases one at a time, either on demand or during the
+// off-peak hours of each day, so that compactions don't pile up while the node
+// is busy accepting containers
+type Compactor struct {
+	log   logging.Logger
+	clock timer.Clock
+
+	// Held while compacting, so only one compaction runs at a time
+	lock sync.Mutex
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Initialize this compactor
+func (c *Compactor) Initialize(log logging.Logger) {
+	c.log = log
+	c.stop = make(chan struct{})
+}
+
+// Compact [dbs] in full, once any compaction in progress finishes, and return
+// how long it took
+func (c *Compactor) Compact(dbs ...database.Compacter) (time.Duration, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	start := time.Now()
+	for _, db := range dbs {
+		if err := db.Compact(nil, nil); err != nil {
+			return time.Since(start), err
+		}
+	}
+	return time.Since(start), nil
+}
+
+// Schedule compacting [db] every day at [offPeak] after midnight UTC, until
+// Stop is called
+func (c *Compactor) Schedule(db database.Compacter, offPeak time.Duration) {
+	c.wg.Add(1)
+	go c.log.RecoverAndPanic(func() {
+		defer c.wg.Done()
+
+		for {
+			next := nextCompaction(c.clock.Time(), offPeak)
+			c.log.Info("scheduled the next database compaction for %s", next)
+
+			t := time.NewTimer(next.Sub(c.clock.Time()))
+			select {
+			case <-t.C:
+			case <-c.stop:
+				t.Stop()
+				return
+			}
+
+			duration, err := c.Compact(db)
+			if err != nil {
+				c.log.Error("scheduled database compaction failed after %s due to %s", duration, err)
+			} else {
+				c.log.Info("scheduled database compaction took %s", duration)
+			}
+		}
+	})
+}
+
+// Stop scheduled compactions. Waits for a scheduled compaction in progress to
+// finish.
+func (c *Compactor) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+}
+
+// nextCompaction returns the first time after [now] that's [offPeak] after
+// midnight UTC
+func nextCompaction(now time.Time, offPeak time.Duration) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(offPeak % day)
+	if !next.After(now) {
+		next = next.Add(day)
+	}
+	return next
+}
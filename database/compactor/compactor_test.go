@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compactor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/mockdb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestNextCompaction(t *testing.T) {
+	offPeak := 3 * time.Hour
+	tests := []struct {
+		now, next time.Time
+	}{
+		{
+			now:  time.Date(2020, 4, 1, 1, 0, 0, 0, time.UTC),
+			next: time.Date(2020, 4, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			now:  time.Date(2020, 4, 1, 3, 0, 0, 0, time.UTC),
+			next: time.Date(2020, 4, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			now:  time.Date(2020, 4, 30, 23, 0, 0, 0, time.UTC),
+			next: time.Date(2020, 5, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			// Off-peak hours are in UTC, whatever the time zone of [now]
+			now:  time.Date(2020, 4, 1, 1, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60)),
+			next: time.Date(2020, 4, 2, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		if next := nextCompaction(test.now, offPeak); !next.Equal(test.next) {
+			t.Fatalf("after %s, expected the next compaction at %s but got %s", test.now, test.next, next)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	c := Compactor{}
+	c.Initialize(logging.NoLog{})
+
+	compacted := 0
+	db := mockdb.New()
+	db.OnCompact = func(start, limit []byte) error {
+		if start != nil || limit != nil {
+			t.Fatalf("should have compacted the whole database")
+		}
+		compacted++
+		return nil
+	}
+	if _, err := c.Compact(db, db); err != nil {
+		t.Fatal(err)
+	}
+	if compacted != 2 {
+		t.Fatalf("should have compacted both databases but compacted %d", compacted)
+	}
+
+	errFailed := errors.New("compaction failed")
+	failing := mockdb.New()
+	failing.OnCompact = func([]byte, []byte) error { return errFailed }
+	if _, err := c.Compact(failing, db); err != errFailed {
+		t.Fatalf("expected %s but got %v", errFailed, err)
+	}
+	if compacted != 2 {
+		t.Fatalf("shouldn't have compacted after a compaction failed")
+	}
+
+	// Stopping doesn't wait for compactions that aren't due yet
+	c.Schedule(db, 0)
+	c.Stop()
+}
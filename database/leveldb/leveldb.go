@@ -174,6 +174,9 @@ func (b *batch) Reset() {
 	b.size = 0
 }
 
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
 // Replay the batch contents.
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	replay := &replayer{writer: w}
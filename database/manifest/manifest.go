@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package manifest records which network, genesis and schema a database was
+// created for, so that a node refuses to run against a database it didn't
+// create
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// SchemaVersion is the version of the layout of the state this node
+	// stores. It's increased whenever a database written by an earlier
+	// version can't be read by this one.
+	SchemaVersion = 1
+
+	// batchSize is the most bytes written to a batch before it's written to
+	// the database when a database is reset
+	batchSize = 1 << 20
+)
+
+var (
+	prefix = []byte("manifest")
+	key    = []byte("manifest")
+)
+
+var (
+	// ErrMismatch is returned when a database was created for a different
+	// network, genesis or schema than expected
+	ErrMismatch = errors.New("database was created for a different network, genesis or schema")
+
+	// ErrNoManifest is returned when a database that already holds state has
+	// no manifest, so what it was created for isn't known
+	ErrNoManifest = errors.New("database holds state but has no manifest")
+)
+
+// Manifest is what a database was created for
+type Manifest struct {
+	NetworkID     uint32
+	GenesisHash   ids.ID
+	SchemaVersion uint32
+}
+
+func (m Manifest) String() string {
+	return fmt.Sprintf("network ID %d, genesis hash %s, schema version %d", m.NetworkID, m.GenesisHash, m.SchemaVersion)
+}
+
+// Get returns the manifest recorded in [db], and false if there isn't one
+func Get(db database.Database) (Manifest, bool, error) {
+	b, err := prefixdb.New(prefix, db).Get(key)
+	if err == database.ErrNotFound {
+		return Manifest{}, false, nil
+	} else if err != nil {
+		return Manifest{}, false, err
+	}
+
+	p := wrappers.Packer{Bytes: b}
+	networkID := p.UnpackInt()
+	genesisHash := p.UnpackFixedBytes(hashing.HashLen)
+	schemaVersion := p.UnpackInt()
+	if p.Errored() {
+		return Manifest{}, false, fmt.Errorf("couldn't parse the database's manifest: %w", p.Err)
+	}
+	m := Manifest{
+		NetworkID:     networkID,
+		SchemaVersion: schemaVersion,
+	}
+	m.GenesisHash, err = ids.ToID(genesisHash)
+	return m, true, err
+}
+
+// Put records [m] as the manifest of [db]
+func Put(db database.Database, m Manifest) error {
+	p := wrappers.Packer{MaxSize: 2*wrappers.IntLen + hashing.HashLen}
+	p.PackInt(m.NetworkID)
+	p.PackFixedBytes(m.GenesisHash.Bytes())
+	p.PackInt(m.SchemaVersion)
+	if p.Errored() {
+		return p.Err
+	}
+	return prefixdb.New(prefix, db).Put(key, p.Bytes)
+}
+
+// Check that [db] was created for [expected]. [expected] is recorded as the
+// manifest of an empty database. A database that holds state but has no
+// manifest was created before manifests were recorded, and ErrNoManifest is
+// returned, as it may be for any network. Returns false if [db] had no
+// manifest.
+func Check(db database.Database, expected Manifest) (bool, error) {
+	m, exists, err := Get(db)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		empty, err := isEmpty(db)
+		switch {
+		case err != nil:
+			return false, err
+		case !empty:
+			return false, ErrNoManifest
+		}
+		return false, Put(db, expected)
+	}
+
+	mismatches := []string(nil)
+	if m.NetworkID != expected.NetworkID {
+		mismatches = append(mismatches, fmt.Sprintf("network ID is %d, not %d", m.NetworkID, expected.NetworkID))
+	}
+	if !m.GenesisHash.Equals(expected.GenesisHash) {
+		mismatches = append(mismatches, fmt.Sprintf("genesis hash is %s, not %s", m.GenesisHash, expected.GenesisHash))
+	}
+	if m.SchemaVersion != expected.SchemaVersion {
+		mismatches = append(mismatches, fmt.Sprintf("schema version is %d, not %d", m.SchemaVersion, expected.SchemaVersion))
+	}
+	if len(mismatches) > 0 {
+		return true, fmt.Errorf("%w: %s", ErrMismatch, strings.Join(mismatches, ", "))
+	}
+	return true, nil
+}
+
+// HasChainState returns true if [db] holds state of the chain [chainID]. The
+// IDs of the chains a network's genesis creates depend on the network, so a
+// database without a manifest that holds their state was created for that
+// network.
+func HasChainState(db database.Database, chainID ids.ID) (bool, error) {
+	// Every chain stores its VM's state under the "vm" prefix of its database
+	vmDB := prefixdb.New([]byte("vm"), prefixdb.New(chainID.Bytes(), db))
+	empty, err := isEmpty(vmDB)
+	return !empty, err
+}
+
+func isEmpty(db database.Database) (bool, error) {
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	if iter.Next() {
+		return false, nil
+	}
+	return true, iter.Error()
+}
+
+// Reset deletes everything in [db], and then records [m] as its manifest
+func Reset(db database.Database, m Manifest) error {
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	batch := db.NewBatch()
+	for iter.Next() {
+		if err := batch.Delete(iter.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() < batchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	return Put(db, m)
+}
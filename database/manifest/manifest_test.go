@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestCheck(t *testing.T) {
+	db := memdb.New()
+	m := Manifest{
+		NetworkID:     12345,
+		GenesisHash:   ids.Empty.Prefix(1),
+		SchemaVersion: SchemaVersion,
+	}
+
+	// The first check records the manifest
+	if existed, err := Check(db, m); err != nil {
+		t.Fatal(err)
+	} else if existed {
+		t.Fatal("a new database shouldn't have a manifest")
+	}
+	if recorded, exists, err := Get(db); err != nil {
+		t.Fatal(err)
+	} else if !exists || recorded.NetworkID != m.NetworkID || !recorded.GenesisHash.Equals(m.GenesisHash) || recorded.SchemaVersion != m.SchemaVersion {
+		t.Fatalf("expected the manifest %s to be recorded but got %s", m, recorded)
+	}
+	if existed, err := Check(db, m); err != nil {
+		t.Fatal(err)
+	} else if !existed {
+		t.Fatal("the manifest should have been recorded")
+	}
+
+	for _, other := range []Manifest{
+		{NetworkID: 1, GenesisHash: m.GenesisHash, SchemaVersion: m.SchemaVersion},
+		{NetworkID: m.NetworkID, GenesisHash: ids.Empty.Prefix(2), SchemaVersion: m.SchemaVersion},
+		{NetworkID: m.NetworkID, GenesisHash: m.GenesisHash, SchemaVersion: m.SchemaVersion + 1},
+	} {
+		if _, err := Check(db, other); !errors.Is(err, ErrMismatch) {
+			t.Fatalf("expected %s checking %s against %s but got %v", ErrMismatch, other, m, err)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	db := memdb.New()
+	m := Manifest{
+		NetworkID:     12345,
+		GenesisHash:   ids.Empty.Prefix(1),
+		SchemaVersion: SchemaVersion,
+	}
+	if err := Put(db, m); err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(0); i < 10; i++ {
+		if err := db.Put([]byte{i}, []byte{i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	other := Manifest{
+		NetworkID:     1,
+		GenesisHash:   ids.Empty.Prefix(2),
+		SchemaVersion: SchemaVersion,
+	}
+	if err := Reset(db, other); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has([]byte{0}); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("reset should have deleted the database's state")
+	}
+	if _, err := Check(db, other); err != nil {
+		t.Fatalf("reset should have recorded the new manifest: %s", err)
+	}
+}
+
+func TestCheckNoManifest(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte{0}, []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	m := Manifest{
+		NetworkID:     12345,
+		GenesisHash:   ids.Empty.Prefix(1),
+		SchemaVersion: SchemaVersion,
+	}
+
+	// A database that holds state isn't assumed to be for this network
+	if _, err := Check(db, m); err != ErrNoManifest {
+		t.Fatalf("expected %s but got %v", ErrNoManifest, err)
+	}
+	if _, exists, err := Get(db); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Fatal("a manifest shouldn't have been recorded")
+	}
+}
+
+func TestHasChainState(t *testing.T) {
+	db := memdb.New()
+	chainID := ids.Empty.Prefix(1)
+	if has, err := HasChainState(db, chainID); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("an empty database shouldn't hold chain state")
+	}
+
+	vmDB := prefixdb.New([]byte("vm"), prefixdb.New(chainID.Bytes(), db))
+	if err := vmDB.Put([]byte{0}, []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := HasChainState(db, chainID); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatal("the database should hold the chain's state")
+	}
+	if has, err := HasChainState(db, ids.Empty.Prefix(2)); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("the database shouldn't hold another chain's state")
+	}
+}
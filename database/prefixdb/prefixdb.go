@@ -138,9 +138,27 @@ func (db *Database) Compact(start, limit []byte) error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
+	if limit == nil {
+		// A nil limit is after every key in this database, rather than before
+		return db.db.Compact(db.prefix(start), prefixLimit(db.dbPrefix))
+	}
 	return db.db.Compact(db.prefix(start), db.prefix(limit))
 }
 
+// prefixLimit returns the first key after every key that starts with [prefix],
+// or nil if there is no such key
+func prefixLimit(prefix []byte) []byte {
+	limit := make([]byte, len(prefix))
+	copy(limit, prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		limit[i]++
+		if limit[i] != 0 {
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	db.lock.Lock()
@@ -202,6 +220,9 @@ func (b *batch) Reset() {
 	b.Batch.Reset()
 }
 
+// Inner returns the batch of the database this database is prefixing
+func (b *batch) Inner() database.Batch { return b.Batch.Inner() }
+
 // Replay replays the batch contents.
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, keyvalue := range b.writes {
@@ -4,6 +4,7 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
@@ -21,3 +22,37 @@ func TestInterface(t *testing.T) {
 		test(t, NewNested([]byte("ld"), New([]byte("wor"), db)))
 	}
 }
+
+// compactRecorder records the range it was last compacted over
+type compactRecorder struct {
+	*memdb.Database
+	start, limit []byte
+}
+
+func (db *compactRecorder) Compact(start, limit []byte) error {
+	db.start, db.limit = start, limit
+	return nil
+}
+
+func TestCompactRange(t *testing.T) {
+	recorder := &compactRecorder{Database: memdb.New()}
+	db := NewNested([]byte("hello"), recorder)
+
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	start, limit := recorder.start, recorder.limit
+	if !bytes.Equal(start, db.dbPrefix) {
+		t.Fatalf("should have compacted from the first key of the prefix")
+	}
+	if bytes.Compare(limit, db.dbPrefix) <= 0 || !bytes.Equal(limit, prefixLimit(db.dbPrefix)) {
+		t.Fatalf("should have compacted up to the last key of the prefix")
+	}
+
+	if got := prefixLimit([]byte{1, 0xff}); !bytes.Equal(got, []byte{2}) {
+		t.Fatalf("expected the limit 0x02 but got 0x%x", got)
+	}
+	if got := prefixLimit([]byte{0xff, 0xff}); got != nil {
+		t.Fatalf("expected no limit but got 0x%x", got)
+	}
+}
@@ -191,22 +191,56 @@ func (db *Database) Commit() error {
 		return nil
 	}
 
+	batch, err := db.commitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
+	return nil
+}
+
+// CommitBatch returns a batch of the underlying database that contains all the
+// operations of this database. Writing the batch commits them, so they can be
+// written atomically with the batches of other databases. Abort should be
+// called once the batch is written.
+func (db *Database) CommitBatch() (database.Batch, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+	return db.commitBatch()
+}
+
+// commitBatch returns a batch of the underlying database that contains all the
+// operations of this database. Assumes [db.lock] is held.
+func (db *Database) commitBatch() (database.Batch, error) {
 	batch := db.db.NewBatch()
 	for key, value := range db.mem {
 		if value.delete {
 			if err := batch.Delete([]byte(key)); err != nil {
-				return err
+				return nil, err
 			}
 		} else if err := batch.Put([]byte(key), value.value); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	if err := batch.Write(); err != nil {
-		return err
-	}
+	return batch, nil
+}
 
-	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
-	return nil
+// Abort all the operations of this database that haven't been committed
+func (db *Database) Abort() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.mem != nil {
+		db.mem = make(map[string]valueDelete, memdb.DefaultSize)
+	}
 }
 
 // Close implements the database.Database interface
@@ -275,6 +309,9 @@ func (b *batch) Reset() {
 	b.size = 0
 }
 
+// Inner implements the Database interface
+func (b *batch) Inner() database.Batch { return b }
+
 // Replay implements the Database interface
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, kv := range b.writes {
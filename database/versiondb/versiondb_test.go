@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
 )
 
 func TestInterface(t *testing.T) {
@@ -297,3 +298,61 @@ func TestSetDatabaseClosed(t *testing.T) {
 		t.Fatalf("Unexpected database from db.GetDatabase")
 	}
 }
+
+func TestCommitBatch(t *testing.T) {
+	baseDB := memdb.New()
+	db1 := New(prefixdb.New([]byte("1"), baseDB))
+	db2 := New(prefixdb.New([]byte("2"), baseDB))
+
+	key := []byte("hello")
+	value := []byte("world")
+
+	if err := db1.Put(key, value); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db2.Put(key, value); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	batch1, err := db1.CommitBatch()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.CommitBatch: %s", err)
+	}
+	batch2, err := db2.CommitBatch()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.CommitBatch: %s", err)
+	}
+	if has, err := prefixdb.New([]byte("1"), baseDB).Has(key); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("db.CommitBatch shouldn't have written to the underlying database")
+	}
+
+	// Both batches are written to the base database in one write
+	inner := batch1.Inner()
+	if err := batch2.Inner().Replay(inner); err != nil {
+		t.Fatalf("Unexpected error on batch.Replay: %s", err)
+	} else if err := inner.Write(); err != nil {
+		t.Fatalf("Unexpected error on batch.Write: %s", err)
+	}
+	db1.Abort()
+	db2.Abort()
+
+	for _, prefix := range []string{"1", "2"} {
+		if v, err := prefixdb.New([]byte(prefix), baseDB).Get(key); err != nil {
+			t.Fatalf("Unexpected error on db.Get: %s", err)
+		} else if !bytes.Equal(v, value) {
+			t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", v, value)
+		}
+	}
+
+	// Aborting dropped the operations, which were written through the batch
+	if err := db1.Delete(key); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+	db1.Abort()
+	if has, err := db1.Has(key); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if !has {
+		t.Fatalf("db.Abort should have dropped the delete")
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/vms/avm"
 	"github.com/ava-labs/gecko/vms/evm"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/platformvm/reward"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 	"github.com/ava-labs/gecko/vms/timestampvm"
@@ -42,6 +44,10 @@ const (
 
 var (
 	validNetworkName = regexp.MustCompile(`network-[0-9]+`)
+
+	// upgradeTime is the chain time at which the changes to the rules of the
+	// platform chains of the public networks take effect
+	upgradeTime = time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
 )
 
 // Hard coded genesis constants
@@ -499,6 +505,30 @@ func Genesis(networkID uint32) []byte {
 	}
 }
 
+// RewardCurve returns the emission schedule stakers of the network with ID
+// [networkID] are rewarded by. Every network currently uses the platform
+// chain's default.
+func RewardCurve(networkID uint32) reward.Curve { return platformvm.DefaultRewardCurve }
+
+// Upgrades returns the changes to the rules of the platform chain of the
+// network with ID [networkID], and when they take effect. Local networks are
+// created anew, so their changes are in effect from genesis. They create
+// subnets and chains for free, so that they can be tested cheaply.
+func Upgrades(networkID uint32) platformvm.Upgrades {
+	if networkID == LocalID {
+		return platformvm.Upgrades{}
+	}
+	return platformvm.Upgrades{
+		DelegationLimitsTime: upgradeTime,
+		StartTimeBoundTime:   upgradeTime,
+		CreationFeeTime:      upgradeTime,
+		CreationFees:         platformvm.DefaultCreationFees,
+		TransferTime:         upgradeTime,
+		MessageTime:          upgradeTime,
+		AccountRootTime:      upgradeTime,
+	}
+}
+
 // VMGenesis ...
 func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
 	genesisBytes := Genesis(networkID)
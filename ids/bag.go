@@ -6,6 +6,23 @@ package ids
 import (
 	"fmt"
 	"strings"
+	"sync"
+)
+
+const (
+	// maxPooledBagSize is the largest number of ids a released bag may hold for
+	// its maps to be reused. Larger maps are left to the garbage collector so
+	// that one large poll doesn't pin its memory.
+	maxPooledBagSize = 1024
+)
+
+var (
+	// countsPool and thresholdPool hold the maps of released bags. Bags are
+	// allocated for every poll, and several more times while the poll's votes
+	// are applied, so reusing their maps takes pressure off the garbage
+	// collector at high transaction rates.
+	countsPool    = sync.Pool{New: func() interface{} { return make(map[[32]byte]int) }}
+	thresholdPool = sync.Pool{New: func() interface{} { return make(Set) }}
 )
 
 // Bag is a multiset of IDs.
@@ -25,7 +42,7 @@ type Bag struct {
 
 func (b *Bag) init() {
 	if b.counts == nil {
-		b.counts = make(map[[32]byte]int)
+		b.counts = countsPool.Get().(map[[32]byte]int)
 	}
 }
 
@@ -67,6 +84,9 @@ func (b *Bag) AddCount(id ID, count int) {
 		b.modeFreq = totalCount
 	}
 	if totalCount >= b.threshold {
+		if b.metThreshold == nil {
+			b.metThreshold = thresholdPool.Get().(Set)
+		}
 		b.metThreshold.Add(id)
 	}
 }
@@ -121,6 +141,25 @@ func (b *Bag) Split(index uint) [2]Bag {
 	return splitVotes
 }
 
+// Release empties this bag and returns its memory to be reused by other bags.
+// The bag may be used again once it's released, but copies of the bag made
+// before it was released, and the set returned by Threshold, must not be.
+func (b *Bag) Release() {
+	if b.counts != nil && len(b.counts) <= maxPooledBagSize {
+		for vote := range b.counts {
+			delete(b.counts, vote)
+		}
+		countsPool.Put(b.counts)
+	}
+	if b.metThreshold != nil && len(b.metThreshold) <= maxPooledBagSize {
+		for vote := range b.metThreshold {
+			delete(b.metThreshold, vote)
+		}
+		thresholdPool.Put(b.metThreshold)
+	}
+	*b = Bag{}
+}
+
 func (b *Bag) String() string {
 	sb := strings.Builder{}
 
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"runtime"
+	"testing"
+)
+
+// reportGCPause reports the time the garbage collector paused the program for,
+// per operation, since [before]
+func reportGCPause(b *testing.B, before *runtime.MemStats) {
+	after := runtime.MemStats{}
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+}
+
+// benchmarkBagSplit benchmarks splitting a poll of [size] votes, as the
+// snowball tree does for every binary node a poll reaches. The split bags are
+// released when [release] is set.
+func benchmarkBagSplit(b *testing.B, size int, release bool) {
+	votes := Bag{}
+	for i := 0; i < size; i++ {
+		votes.Add(Empty.Prefix(uint64(i)))
+	}
+
+	before := runtime.MemStats{}
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		split := votes.Split(0)
+		if release {
+			split[0].Release()
+			split[1].Release()
+		}
+	}
+	b.StopTimer()
+	reportGCPause(b, &before)
+}
+
+func BenchmarkBagSplit20(b *testing.B)          { benchmarkBagSplit(b, 20, false) }
+func BenchmarkBagSplit20Released(b *testing.B)  { benchmarkBagSplit(b, 20, true) }
+func BenchmarkBagSplit200(b *testing.B)         { benchmarkBagSplit(b, 200, false) }
+func BenchmarkBagSplit200Released(b *testing.B) { benchmarkBagSplit(b, 200, true) }
+
+// benchmarkBagPoll benchmarks collecting the votes of a poll of [size]
+// validators that voted for [choices] different ids. The bag is released when
+// [release] is set.
+func benchmarkBagPoll(b *testing.B, size, choices int, release bool) {
+	ids := make([]ID, choices)
+	for i := range ids {
+		ids[i] = Empty.Prefix(uint64(i))
+	}
+
+	before := runtime.MemStats{}
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		votes := Bag{}
+		for i := 0; i < size; i++ {
+			votes.Add(ids[i%choices])
+		}
+		if release {
+			votes.Release()
+		}
+	}
+	b.StopTimer()
+	reportGCPause(b, &before)
+}
+
+func BenchmarkBagPoll(b *testing.B)         { benchmarkBagPoll(b, 20, 4, false) }
+func BenchmarkBagPollReleased(b *testing.B) { benchmarkBagPoll(b, 20, 4, true) }
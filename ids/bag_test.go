@@ -199,3 +199,42 @@ func TestBagString(t *testing.T) {
 		t.Fatalf("Bag.String:\nReturned:\n%s\nExpected:\n%s", bagString, expected)
 	}
 }
+
+func TestBagRelease(t *testing.T) {
+	id0 := Empty
+	id1 := NewID([32]byte{1})
+
+	bag := Bag{}
+	bag.SetThreshold(2)
+	bag.AddCount(id0, 2)
+	bag.Add(id1)
+	bag.Release()
+
+	if size := bag.Len(); size != 0 {
+		t.Fatalf("Bag.Len returned %d expected %d", size, 0)
+	} else if count := bag.Count(id0); count != 0 {
+		t.Fatalf("Bag.Count returned %d expected %d", count, 0)
+	} else if mode, freq := bag.Mode(); !mode.IsZero() || freq != 0 {
+		t.Fatalf("Bag.Mode returned (%s, %d) expected (%s, %d)", mode, freq, ID{}, 0)
+	} else if threshold := bag.Threshold(); threshold.Len() != 0 {
+		t.Fatalf("Bag.Threshold returned %s expected %s", threshold, Set{})
+	}
+
+	// Bags reusing the released maps start out empty
+	for i := 0; i < 2; i++ {
+		reused := Bag{}
+		reused.Add(id1)
+		if count := reused.Count(id0); count != 0 {
+			t.Fatalf("Bag.Count returned %d expected %d", count, 0)
+		} else if threshold := reused.Threshold(); threshold.Len() != 1 {
+			t.Fatalf("Bag.Threshold returned %s expected only %s", threshold, id1)
+		}
+		reused.Release()
+	}
+
+	// A released bag can be used again
+	bag.Add(id1)
+	if count := bag.Count(id1); count != 1 {
+		t.Fatalf("Bag.Count returned %d expected %d", count, 1)
+	}
+}
@@ -52,16 +52,17 @@ func (id ID) MarshalJSON() ([]byte, error) {
 	return cb58.MarshalJSON()
 }
 
-// UnmarshalJSON ...
+// UnmarshalJSON parses an ID in CB58, or hex, encoding. null leaves the ID
+// unchanged. A malformed ID is an error that describes what's wrong with it.
 func (id *ID) UnmarshalJSON(b []byte) error {
 	if string(b) == "null" {
 		return nil
 	}
-	cb58 := formatting.CB58{}
-	if err := cb58.UnmarshalJSON(b); err != nil {
+	idBytes, err := unmarshalJSONID(b, "ID", hashing.HashLen)
+	if err != nil {
 		return err
 	}
-	newID, err := ToID(cb58.Bytes)
+	newID, err := ToID(idBytes)
 	if err != nil {
 		return err
 	}
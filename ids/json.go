@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	hexAlphabet    = "0123456789abcdefABCDEF"
+	hexPrefix      = "0x"
+)
+
+var (
+	errInvalidJSONID = errors.New("invalid ID")
+)
+
+// unmarshalJSONID returns the [size] bytes of the [kind] in the JSON string
+// [b], which must be in checksummed base-58 or checksummed, 0x-prefixed hex
+// encoding. The returned error describes what's wrong with [b], and where.
+func unmarshalJSONID(b []byte, kind string, size int) ([]byte, error) {
+	expected := fmt.Sprintf("expected a %d byte %s in CB58, or 0x-prefixed hex, encoding", size, kind)
+
+	str := string(b)
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return nil, fmt.Errorf("%w: %s isn't a string; %s", errInvalidJSONID, str, expected)
+	}
+	str = str[1 : len(str)-1]
+	if str == "" {
+		return nil, fmt.Errorf("%w: the %s is empty; %s", errInvalidJSONID, kind, expected)
+	}
+
+	digits, alphabet, encoding := str, base58Alphabet, "CB58"
+	if strings.HasPrefix(str, hexPrefix) {
+		digits, alphabet, encoding = str[len(hexPrefix):], hexAlphabet, "hex"
+	}
+	for i, c := range digits {
+		if !strings.ContainsRune(alphabet, c) {
+			return nil, fmt.Errorf("%w %q: character %q at index %d isn't valid in %s; %s",
+				errInvalidJSONID, str, c, len(str)-len(digits)+i, encoding, expected)
+		}
+	}
+
+	cb58 := formatting.CB58{}
+	if err := cb58.FromString(str); err != nil {
+		return nil, fmt.Errorf("%w %q: %s; %s", errInvalidJSONID, str, err, expected)
+	}
+	if len(cb58.Bytes) != size {
+		return nil, fmt.Errorf("%w %q: it's %d bytes long; %s", errInvalidJSONID, str, len(cb58.Bytes), expected)
+	}
+	return cb58.Bytes, nil
+}
@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+func TestUnmarshalJSON(t *testing.T) {
+	id := NewID([32]byte{'a', 'v', 'a'})
+	shortID := NewShortID([20]byte{'a', 'v', 'a'})
+
+	args := struct {
+		ID      ID      `json:"id"`
+		ShortID ShortID `json:"shortID"`
+	}{}
+	input := `{"id":"` + id.String() + `","shortID":"` + formatting.HexEncoding.Encode(shortID.Bytes()) + `"}`
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		t.Fatal(err)
+	}
+	if !args.ID.Equals(id) || !args.ShortID.Equals(shortID) {
+		t.Fatalf("parsed the wrong IDs from %s", input)
+	}
+
+	tests := []struct {
+		input    string
+		contains string
+	}{
+		{input: `{"id":5}`, contains: "isn't a string"},
+		{input: `{"id":""}`, contains: "the ID is empty"},
+		{input: `{"id":"` + id.String()[:3] + `0` + id.String()[4:] + `"}`, contains: "'0' at index 3 isn't valid in CB58"},
+		{input: `{"id":"0x12g4"}`, contains: "'g' at index 4 isn't valid in hex"},
+		{input: `{"id":"` + id.String()[:len(id.String())-1] + `"}`, contains: "checksum"},
+		{input: `{"id":"` + shortID.String() + `"}`, contains: "it's 20 bytes long; expected a 32 byte ID"},
+		{input: `{"shortID":"` + id.String() + `"}`, contains: "it's 32 bytes long; expected a 20 byte short ID"},
+	}
+	for _, test := range tests {
+		args.ID, args.ShortID = ID{}, ShortID{}
+		err := json.Unmarshal([]byte(test.input), &args)
+		if !errors.Is(err, errInvalidJSONID) {
+			t.Fatalf("parsing %s should have failed with %s but got %v", test.input, errInvalidJSONID, err)
+		}
+		if !strings.Contains(err.Error(), test.contains) {
+			t.Fatalf("parsing %s should have failed with an error containing %q but got %q", test.input, test.contains, err)
+		}
+		if !args.ID.IsZero() || !args.ShortID.IsZero() {
+			t.Fatalf("a malformed ID shouldn't have been parsed")
+		}
+	}
+}
@@ -49,16 +49,17 @@ func (id ShortID) MarshalJSON() ([]byte, error) {
 	return cb58.MarshalJSON()
 }
 
-// UnmarshalJSON ...
+// UnmarshalJSON parses a short ID in CB58, or hex, encoding. null leaves the
+// ID unchanged. A malformed ID is an error that describes what's wrong with it.
 func (id *ShortID) UnmarshalJSON(b []byte) error {
 	if string(b) == "null" {
 		return nil
 	}
-	cb58 := formatting.CB58{}
-	if err := cb58.UnmarshalJSON(b); err != nil {
+	idBytes, err := unmarshalJSONID(b, "short ID", hashing.AddrLen)
+	if err != nil {
 		return err
 	}
-	newID, err := ToShortID(cb58.Bytes)
+	newID, err := ToShortID(idBytes)
 	if err != nil {
 		return err
 	}
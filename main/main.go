@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path"
 
 	"github.com/ava-labs/gecko/node"
@@ -19,6 +20,11 @@ func main() {
 	// Err is set based on the CLI arguments
 	if Err != nil {
 		fmt.Printf("parsing parameters returned with error %s\n", Err)
+		os.Exit(1)
+	}
+	if CheckParams {
+		fmt.Println("parameters are valid")
+		Config.DB.Close()
 		return
 	}
 
@@ -51,11 +57,6 @@ func main() {
 	}
 	crypto.EnableCrypto = Config.EnableCrypto
 
-	if err := Config.ConsensusParams.Valid(); err != nil {
-		log.Fatal("consensus parameters are invalid: %s", err)
-		return
-	}
-
 	// Track if assertions should be executed
 	if Config.LoggingConfig.Assertions {
 		log.Warn("assertions are enabled. This may slow down execution")
@@ -7,35 +7,54 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"net"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/api/auth"
+	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/database/leveldb"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/platformvm"
 )
 
 // Results of parsing the CLI
 var (
 	Config = node.Config{}
 	Err    error
+
+	// CheckParams is true if the node should exit once its parameters are
+	// parsed, rather than run
+	CheckParams bool
 )
 
 var (
 	errBootstrapMismatch = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errSlowTime          = errors.New("time can't run slower than real time")
+	errFutureEpoch       = errors.New("time acceleration can't start in the future")
 )
 
+// consensusFlags names the flag that sets each consensus parameter, so that an
+// invalid parameter can be traced back to the flag that set it
+const consensusFlags = "K is snow-sample-size, Alpha is snow-quorum-size, BetaVirtuous is snow-virtuous-commit-threshold, BetaRogue is snow-rogue-commit-threshold, Parents is snow-avalanche-num-parents and BatchSize is snow-avalanche-batch-size"
+
 // Parse the CLI arguments
 func init() {
 	errs := &wrappers.Errs{}
@@ -50,6 +69,53 @@ func init() {
 	// Ava fees:
 	flag.Uint64Var(&Config.AvaTxFee, "ava-tx-fee", 0, "Ava transaction fee, in $nAva")
 
+	// Transaction re-gossiping:
+	flag.DurationVar(&Config.TxRegossipFrequency, "tx-regossip-frequency", 30*time.Second, "Time a locally issued transaction may remain undecided before it is re-gossiped. Non-positive disables re-gossiping")
+	flag.DurationVar(&Config.TxMaxRegossipFrequency, "tx-max-regossip-frequency", 10*time.Minute, "Maximum backoff between re-gossips of a locally issued transaction")
+
+	// AVM transaction batching:
+	flag.IntVar(&Config.AVMBatchSize, "avm-batch-size", 30, "Number of AVM transactions to batch together before issuing them to consensus. A chain's configuration may override it")
+	flag.DurationVar(&Config.AVMBatchTimeout, "avm-batch-timeout", time.Second, "Maximum time an AVM transaction waits to be batched while the chain is under load. A chain's configuration may override it")
+
+	// AVM transaction fees:
+	flag.StringVar(&Config.AVMFeeAsset, "avm-fee-asset", "AVA", "ID, or alias, of the asset AVM transaction fees are paid in. A transaction's fee is the amount of this asset it burns")
+	flag.Uint64Var(&Config.AVMMinFee, "avm-min-fee", 0, "Minimum fee an AVM transaction issued to this node must pay. Transactions are issued to consensus in order of decreasing fee")
+	watchAllowedHosts := flag.String("watch-allowed-hosts", "", "Comma separated list of the only hosts that address watches may send callbacks to. If empty, callbacks may be sent to any host with a public address, but never to loopback, link-local or private addresses")
+
+	// Chain resource budgets:
+	flag.Float64Var(&Config.ChainCPUBudget, "chain-cpu-budget", 0, "Fraction of time each chain, other than the P-Chain, may spend processing messages. Requests to a throttled chain are delayed, not dropped. Non-positive disables throttling")
+	flag.Uint64Var(&Config.ChainMemoryBudget, "chain-memory-budget", 64*1024*1024, "Bytes of requests from peers each throttled chain may hold back before the node stops reading that chain's requests. 0 means only the number of requests is limited")
+
+	// Container size limits:
+	flag.IntVar(&Config.ContainerLimits.MaxBlockSize, "max-block-size", snow.DefaultLimits.MaxBlockSize, "Size, in bytes, of the largest block chains issue and accept. Must match the rest of the network")
+	flag.IntVar(&Config.ContainerLimits.MaxVertexSize, "max-vertex-size", snow.DefaultLimits.MaxVertexSize, "Size, in bytes, of the largest vertex chains issue and accept. Must match the rest of the network")
+	flag.IntVar(&Config.ContainerLimits.MaxTxSize, "max-tx-size", snow.DefaultLimits.MaxTxSize, "Size, in bytes, of the largest transaction chains issue and accept. Must match the rest of the network")
+
+	// Accepted frontier monitoring:
+	flag.DurationVar(&Config.FrontierMonitor.Frequency, "frontier-check-frequency", time.Minute, "How often bootstrapped chains compare their accepted frontier with a sample of their validators'. 0 disables the comparison")
+	flag.IntVar(&Config.FrontierMonitor.SampleSize, "frontier-check-sample-size", 5, "Number of validators, sampled by stake, whose accepted frontiers are compared with a chain's each time")
+	flag.DurationVar(&Config.FrontierMonitor.Threshold, "frontier-divergence-threshold", 5*time.Minute, "How long a chain's accepted frontier may diverge from most of the sampled validators' before it's reported as diverged")
+	flag.BoolVar(&Config.FrontierMonitor.Resync, "frontier-resync-enabled", false, "If true, a chain whose accepted frontier has diverged fetches the containers of the validators it disagrees with")
+
+	// Acceptance log:
+	flag.DurationVar(&Config.AcceptanceWindow, "acceptance-log-window", time.Hour, "How long each chain remembers the transactions, or blocks, it accepted, so they can be queried with admin.getRecentAcceptances. 0 disables the log")
+
+	// Snapshots:
+	flag.StringVar(&Config.SnapshotDir, "snapshot-dir", "", "Directory of chain snapshots. A fresh chain accepts the containers of its snapshot, <chain ID>.snapshot, before bootstrapping, and admin.exportSnapshot writes snapshots to it. Empty disables snapshots")
+
+	// Chain configurations:
+	flag.StringVar(&Config.ChainConfigDir, "chain-config-dir", "", "Directory of chain configurations. A chain is configured by the file <chain ID or alias>.json, whose format is defined by the chain's VM. AVM chains may set the asset their txs are ordered by, feeAsset, and minimum fee, minFee. The fee every AVM tx must burn is set by the chain's genesis. Empty disables chain configurations")
+
+	// Chain time:
+	flag.DurationVar(&Config.MinStartTimeLead, "min-start-time-lead", 0, "How long after this node's time a staker must start for this node to propose adding it. 0 uses the default")
+	flag.DurationVar(&Config.StartTimeMargin, "start-time-margin", 0, "How long after this node's time a validator must start for platform.addDefaultSubnetValidator to build the transaction adding it. 0 uses the default")
+	flag.DurationVar(&Config.AdvanceTimePacing, "advance-time-pacing", 0, "Minimum time between two proposals this node makes to advance the platform chain's time")
+	timeAcceleration := flag.Float64("time-acceleration", 1, "How many times faster than real time the chains' clocks run. Meant for test networks, such as to make staking periods last minutes instead of days. Must match the rest of the network")
+	timeAccelerationEpoch := flag.Int64("time-acceleration-epoch", 0, "Unix time, in seconds, from which the chains' clocks run faster than real time. 0 uses the time this node starts. Must match the rest of the network")
+
+	// Consensus sampling:
+	flag.Int64Var(&Config.ConsensusSeed, "consensus-seed", 0, "Seed of the sources of randomness the chains' consensus samples validators from. Running with the same seed reproduces the same samples. 0 uses a random seed")
+
 	// Assertions:
 	flag.BoolVar(&loggingConfig.Assertions, "assertions-enabled", true, "Turn on assertion execution")
 
@@ -59,12 +125,37 @@ func init() {
 	// Database:
 	db := flag.Bool("db-enabled", true, "Turn on persistent storage")
 	dbDir := flag.String("db-dir", "db", "Database directory for Ava state")
+	dbCompactionTime := flag.String("db-compaction-time", "", "Time of day, in UTC, as HH:MM, to compact the database at every day. Should be during off-peak hours, as compacting slows down accepting containers. If empty, the database is only compacted when LevelDB decides to, or when admin.compactDatabase is called")
+	flag.BoolVar(&Config.ForceReset, "force-reset", false, "If the database was created for a different network, genesis or database schema, or has no manifest and holds none of this network's chains, delete its contents rather than refusing to start")
+	flag.BoolVar(&Config.Reindex, "reindex", false, "Rebuild the address indexes and supply counters from the chains' state on startup")
 
 	// IP:
 	consensusIP := flag.String("public-ip", "", "Public IP of this node")
 
+	// Liveness probes:
+	probePort := flag.Uint("probe-port", 0, "UDP port to answer liveness probes on with a signature from the staking key. 0 disables the probe responder")
+
+	// Version check:
+	flag.DurationVar(&Config.VersionCheckFrequency, "version-check-frequency", time.Minute, "How often to check whether validators with a majority of the stake run a later version than this node, which is logged and reported by the version_behind metric. 0 disables the check")
+
 	// HTTP Server:
+	flag.StringVar(&Config.HTTPHost, "http-host", "", "Host the HTTP server listens on. If empty, it listens on all interfaces. Set to 127.0.0.1 to only serve privileged APIs locally")
 	httpPort := flag.Uint("http-port", 9650, "Port of the HTTP server")
+	httpDisabledAPIs := flag.String("http-disabled-apis", "", "Comma separated list of APIs not served on http-host:http-port. Each is a route, such as keystore or bc/P, an endpoint, such as bc/P/export, or a method, such as platform.sign. platform.internal names the P-Chain methods that use keystore users, watch names the methods that manage address watches and auth.admin names the methods that manage API keys")
+	flag.BoolVar(&Config.HTTPRequireAPIKey, "http-require-api-key", false, "If true, every request on http-host:http-port must carry an API key, created with auth.newKey, in its Authorization header")
+	flag.BoolVar(&Config.ReadOnlyReplica, "read-only-replica", false, "If true, this node serves query APIs but refuses calls that issue txs or change keystore users, and doesn't vote or propose blocks. Meant for public API nodes behind a load balancer. The node's staking key shouldn't be staked")
+	httpPublicAddress := flag.String("http-public-address", "", "Additional address the HTTP server listens on, such as 0.0.0.0:9660. If empty, there's no additional address")
+	httpPublicDisabledAPIs := flag.String("http-public-disabled-apis", "admin,keystore,ipcs,faucet,platform.internal,bc/P/export,watch,auth.admin", "Comma separated list of APIs not served on http-public-address, in the format of http-disabled-apis")
+	httpPublicRequireAPIKey := flag.Bool("http-public-require-api-key", false, "If true, every request on http-public-address must carry an API key, created with auth.newKey, in its Authorization header")
+	flag.BoolVar(&Config.HTTPConfig.HTTP2, "http2-enabled", api.DefaultHTTPConfig.HTTP2, "If true, the HTTP server accepts HTTP/2 connections, which multiplex many requests. Without TLS, clients must use HTTP/2 with prior knowledge")
+	httpMaxConcurrentStreams := flag.Uint("http2-max-concurrent-streams", uint(api.DefaultHTTPConfig.MaxConcurrentStreams), "Most requests a client may have in flight on one HTTP/2 connection")
+	flag.BoolVar(&Config.HTTPConfig.KeepAlives, "http-keep-alives-enabled", api.DefaultHTTPConfig.KeepAlives, "If true, HTTP/1.1 connections are kept open between requests")
+	flag.DurationVar(&Config.HTTPConfig.IdleTimeout, "http-idle-timeout", api.DefaultHTTPConfig.IdleTimeout, "How long an HTTP connection is kept open without a request in flight")
+	flag.DurationVar(&Config.APICacheTTL, "api-cache-ttl", 0, "How long the responses to calls of api-cache-methods are served from a cache. Calls can bypass the cache with a Cache-Control header. If 0, no response is cached")
+	apiCacheMethods := flag.String("api-cache-methods", "platform.getCurrentValidators,platform.getSubnets,avm.getUTXOs", "Comma separated list of the JSON-RPC methods whose responses are cached for api-cache-ttl")
+	flag.DurationVar(&Config.HealthCheckFrequency, "health-check-frequency", 10*time.Second, "How often the node checks whether it's healthy. It's unhealthy while a chain is bootstrapping, has diverged from its validators or is dropping requests for lack of CPU. If 0, calls of api-shed-methods are never refused")
+	apiShedMethods := flag.String("api-shed-methods", "avm.getUTXOs,avm.getAddressInfo,platform.getCurrentValidators,platform.getPendingValidators,platform.getAccountProof", "Comma separated list of the JSON-RPC methods, or services such as platform.*, that are refused with 503 Service Unavailable while the node is unhealthy")
+	flag.DurationVar(&Config.APIShedRetryAfter, "api-shed-retry-after", 30*time.Second, "Time callers of api-shed-methods are asked to wait before retrying while the node is unhealthy")
 	flag.BoolVar(&Config.EnableHTTPS, "http-tls-enabled", false, "Upgrade the HTTP server to HTTPs")
 	flag.StringVar(&Config.HTTPSKeyFile, "http-tls-key-file", "", "TLS private key file for the HTTPs server")
 	flag.StringVar(&Config.HTTPSCertFile, "http-tls-cert-file", "", "TLS certificate file for the HTTPs server")
@@ -90,19 +181,61 @@ func init() {
 	flag.IntVar(&Config.ConsensusParams.BetaRogue, "snow-rogue-commit-threshold", 30, "Beta value to use for rogue transactions")
 	flag.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	flag.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
+	streamWindow := flag.Int("snow-stream-window", router.DefaultStreamWindow, "Bytes of the messages from one peer to one chain that may wait to be processed. Further requests from the peer are dropped until the chain catches up. Responses to this node's requests are dropped once twice as many bytes wait. 0 processes every chain's messages as they arrive, so a slow chain holds up the others")
 
 	// Enable/Disable APIs:
 	flag.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", true, "If true, this node exposes the Admin API")
 	flag.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
 	flag.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
+	flag.BoolVar(&Config.AuthAPIEnabled, "api-auth-enabled", true, "If true, this node exposes the Auth API, which manages API keys")
+	flag.BoolVar(&Config.InfoAPIEnabled, "api-info-enabled", true, "If true, this node exposes the Info API, which proves this node's ID to delegators")
 	flag.BoolVar(&Config.IPCEnabled, "api-ipcs-enabled", false, "If true, IPCs can be opened")
 
+	// Keystore:
+	keystorePasswordTime := flag.Uint("keystore-password-time", uint(keystore.DefaultPasswordParams.Time), "Number of passes over memory argon2id makes when hashing keystore passwords")
+	keystorePasswordMemory := flag.Uint("keystore-password-memory", uint(keystore.DefaultPasswordParams.Memory), "KiB of memory argon2id uses when hashing keystore passwords")
+	keystorePasswordThreads := flag.Uint("keystore-password-threads", uint(keystore.DefaultPasswordParams.Threads), "Number of threads argon2id uses when hashing keystore passwords")
+	flag.Uint64Var(&Config.KeystoreUserQuota, "keystore-user-quota", keystore.DefaultUserQuota, "Number of bytes each keystore user may store. 0 means unlimited")
+	flag.IntVar(&Config.KeystoreSessionCacheSize, "keystore-session-cache-size", keystore.DefaultSessionCacheSize, "Number of keystore users whose correctly given passwords are remembered, so they aren't hashed again on each call. 0 means passwords aren't remembered")
+	flag.DurationVar(&Config.KeystoreSessionTTL, "keystore-session-ttl", keystore.DefaultSessionTTL, "Time a correctly given keystore password is remembered for")
+	keystoreQuotaOverrides := flag.String("keystore-quota-overrides", "", "Comma separated list of user=bytes pairs that override the quota of keystore users. 0 means unlimited")
+
+	// Faucet:
+	flag.BoolVar(&Config.FaucetAPIEnabled, "api-faucet-enabled", false, "If true, this node exposes a faucet API that dispenses funds. Should only be enabled on test networks")
+	flag.StringVar(&Config.FaucetConfig.Username, "faucet-username", "", "Keystore user that holds the funds dispensed by the faucet")
+	flag.StringVar(&Config.FaucetConfig.Password, "faucet-password", "", "Password of the keystore user that holds the funds dispensed by the faucet")
+	flag.StringVar(&Config.FaucetConfig.AssetID, "faucet-asset-id", "AVA", "ID, or alias, of the asset dispensed by the faucet on the X-Chain. $AVA is dispensed on the P-Chain")
+	flag.Uint64Var(&Config.FaucetConfig.Amount, "faucet-amount", 1000, "Amount dispensed by the faucet per request, in $nAVA on the P-Chain")
+	flag.DurationVar(&Config.FaucetConfig.RateLimit, "faucet-rate-limit", 24*time.Hour, "Minimum time between two faucet requests for the same address")
+
 	// Throughput Server
 	throughputPort := flag.Uint("xput-server-port", 9652, "Port of the deprecated throughput test server")
 	flag.BoolVar(&Config.ThroughputServerEnabled, "xput-server-enabled", false, "If true, throughput test server is created")
 
+	// Config file:
+	configFile := flag.String("config-file", "", "JSON file of flag values. Flags given on the command line take precedence. Log levels, API enable flags, keystore-user-quota, faucet-amount and faucet-rate-limit are reloaded from it on SIGHUP or admin.reloadConfig")
+
+	flag.BoolVar(&CheckParams, "check-params", false, "If true, the parameters are checked, and the node exits with a non-zero status if they're invalid, without being started")
+
 	flag.Parse()
 
+	Config.Flags = flag.CommandLine
+	Config.CommandLineFlags = make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { Config.CommandLineFlags[f.Name] = true })
+	if *configFile != "" {
+		Config.ConfigFile = *configFile
+		values, err := node.ReadConfigFile(*configFile)
+		errs.Add(err)
+		for name, value := range values {
+			if Config.CommandLineFlags[name] {
+				continue
+			}
+			if err := flag.Set(name, value); err != nil {
+				errs.Add(fmt.Errorf("invalid value %q for flag %s in config file: %w", value, name, err))
+			}
+		}
+	}
+
 	networkID, err := genesis.NetworkID(*networkName)
 	errs.Add(err)
 
@@ -122,6 +255,14 @@ func init() {
 	} else {
 		Config.DB = memdb.New()
 	}
+	if *dbCompactionTime != "" {
+		compactionTime, err := time.Parse("15:04", *dbCompactionTime)
+		if err != nil {
+			errs.Add(fmt.Errorf("invalid db-compaction-time %q: %w", *dbCompactionTime, err))
+		}
+		Config.DBCompactionEnabled = true
+		Config.DBCompactionTime = time.Duration(compactionTime.Hour())*time.Hour + time.Duration(compactionTime.Minute())*time.Minute
+	}
 
 	Config.Nat = nat.Any()
 
@@ -178,8 +319,98 @@ func init() {
 		}
 	}
 
+	// Address watches:
+	for _, host := range strings.Split(*watchAllowedHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			Config.WatchAllowedHosts = append(Config.WatchAllowedHosts, host)
+		}
+	}
+
 	// HTTP:
 	Config.HTTPPort = uint16(*httpPort)
+	Config.HTTPDisabledAPIs = parseDisabledAPIs(*httpDisabledAPIs)
+	Config.HTTPConfig.MaxConcurrentStreams = uint32(*httpMaxConcurrentStreams)
+	for _, method := range strings.Split(*apiCacheMethods, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			Config.APICachedMethods = append(Config.APICachedMethods, method)
+		}
+	}
+	for _, method := range strings.Split(*apiShedMethods, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			Config.APIShedMethods = append(Config.APIShedMethods, method)
+		}
+	}
+	if *httpPublicAddress != "" {
+		publicListener := api.Listener{
+			Address:       *httpPublicAddress,
+			DisabledAPIs:  parseDisabledAPIs(*httpPublicDisabledAPIs),
+			RequireAPIKey: *httpPublicRequireAPIKey,
+		}
+		if Config.EnableHTTPS {
+			publicListener.CertFile = Config.HTTPSCertFile
+			publicListener.KeyFile = Config.HTTPSKeyFile
+		}
+		Config.HTTPListeners = append(Config.HTTPListeners, publicListener)
+	}
+	Config.ProbePort = uint16(*probePort)
+
+	// Container size limits:
+	errs.Add(Config.ContainerLimits.Verify())
+
+	// Consensus parameters:
+	if err := Config.ConsensusParams.Valid(); err != nil {
+		errs.Add(fmt.Errorf("consensus parameters are unsafe: %w (%s)", err, consensusFlags))
+	}
+
+	// Consensus sampling:
+	if Config.ConsensusSeed == 0 {
+		Config.ConsensusSeed = time.Now().UnixNano()
+	}
+
+	// Chain time:
+	epoch := time.Now()
+	if *timeAccelerationEpoch != 0 {
+		epoch = time.Unix(*timeAccelerationEpoch, 0)
+	}
+	switch {
+	case *timeAcceleration < 1:
+		errs.Add(errSlowTime)
+	case epoch.After(time.Now()):
+		errs.Add(errFutureEpoch)
+	default:
+		Config.Clock.Accelerate(epoch, *timeAcceleration)
+	}
+
+	// Keystore:
+	Config.KeystorePasswordParams = keystore.PasswordParams{
+		Time:    uint32(*keystorePasswordTime),
+		Memory:  uint32(*keystorePasswordMemory),
+		Threads: uint8(*keystorePasswordThreads),
+	}
+	if *keystorePasswordTime > math.MaxUint32 || *keystorePasswordMemory > math.MaxUint32 || *keystorePasswordThreads > math.MaxUint8 {
+		errs.Add(errors.New("keystore password hashing parameters are out of range"))
+	} else {
+		errs.Add(Config.KeystorePasswordParams.Verify())
+	}
+	if Config.KeystoreSessionCacheSize < 0 {
+		errs.Add(errors.New("keystore session cache size can't be negative"))
+	}
+	Config.KeystoreQuotaOverrides = make(map[string]uint64)
+	if *keystoreQuotaOverrides != "" {
+		for _, override := range strings.Split(*keystoreQuotaOverrides, ",") {
+			fields := strings.SplitN(override, "=", 2)
+			if len(fields) != 2 || fields[0] == "" {
+				errs.Add(fmt.Errorf("invalid keystore quota override %q", override))
+				continue
+			}
+			quota, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				errs.Add(fmt.Errorf("invalid keystore quota override %q: %w", override, err))
+				continue
+			}
+			Config.KeystoreQuotaOverrides[fields[0]] = quota
+		}
+	}
 
 	// Logging:
 	if *logsDir != "" {
@@ -203,4 +434,30 @@ func init() {
 
 	// Router used for consensus
 	Config.ConsensusRouter = &router.ChainRouter{}
+	if *streamWindow > 0 {
+		Config.ConsensusRouter = router.NewStreamRouter(Config.ConsensusRouter, *streamWindow)
+	}
+}
+
+// parseDisabledAPIs parses a comma separated list of APIs, in which
+// platform.internal stands for the P-Chain's internal methods, watch for the
+// methods that manage the X-Chain's and P-Chain's address watches and
+// auth.admin for the methods that manage API keys
+func parseDisabledAPIs(list string) []string {
+	apis := []string(nil)
+	for _, name := range strings.Split(list, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "platform.internal":
+			apis = append(apis, platformvm.InternalMethods...)
+		case "watch":
+			apis = append(apis, avm.WatchMethods...)
+			apis = append(apis, platformvm.WatchMethods...)
+		case "auth.admin":
+			apis = append(apis, auth.AdminMethods...)
+		default:
+			apis = append(apis, name)
+		}
+	}
+	return apis
 }
@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package banlist
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// maxBanSize is the largest a serialized ban may be
+	maxBanSize = 1 << 10
+)
+
+var (
+	errInvalidIP = errors.New("invalid IP")
+	errNotBanned = errors.New("IP isn't banned")
+)
+
+// Ban is an IP that this node refuses to connect to
+type Ban struct {
+	IP     string      `json:"ip"`
+	Reason string      `json:"reason"`
+	Since  json.Uint64 `json:"since"` // Unix time the IP was banned at
+}
+
+// Banlist is the set of banned IPs. Bans are persisted to a database so they
+// survive restarts.
+type Banlist struct {
+	lock  sync.RWMutex
+	db    database.Database
+	clock timer.Clock
+	bans  map[string]Ban // IP -> Ban
+}
+
+// Initialize this banlist with the bans stored in [db]
+func (b *Banlist) Initialize(db database.Database) error {
+	b.db = db
+	b.bans = make(map[string]Ban)
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		ip := net.IP(iter.Key())
+		p := wrappers.Packer{Bytes: iter.Value()}
+		since := p.UnpackLong()
+		reason := p.UnpackStr()
+		if p.Errored() {
+			return fmt.Errorf("couldn't parse the ban of %s: %w", ip, p.Err)
+		}
+		b.bans[ip.String()] = Ban{
+			IP:     ip.String(),
+			Reason: reason,
+			Since:  json.Uint64(since),
+		}
+	}
+	return iter.Error()
+}
+
+// Ban [ip] for [reason]. Banning an IP that is already banned replaces the
+// reason.
+func (b *Banlist) Ban(ip net.IP, reason string) error {
+	key := ip.To16()
+	if key == nil {
+		return errInvalidIP
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	since := b.clock.Unix()
+	p := wrappers.Packer{MaxSize: maxBanSize}
+	p.PackLong(since)
+	p.PackStr(reason)
+	if p.Errored() {
+		return fmt.Errorf("couldn't serialize the ban of %s: %w", ip, p.Err)
+	}
+	if err := b.db.Put(key, p.Bytes); err != nil {
+		return err
+	}
+
+	b.bans[ip.String()] = Ban{
+		IP:     ip.String(),
+		Reason: reason,
+		Since:  json.Uint64(since),
+	}
+	return nil
+}
+
+// Unban [ip]
+func (b *Banlist) Unban(ip net.IP) error {
+	key := ip.To16()
+	if key == nil {
+		return errInvalidIP
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, banned := b.bans[ip.String()]; !banned {
+		return errNotBanned
+	}
+	if err := b.db.Delete(key); err != nil {
+		return err
+	}
+	delete(b.bans, ip.String())
+	return nil
+}
+
+// IsBanned returns true if [ip] is banned
+func (b *Banlist) IsBanned(ip net.IP) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	_, banned := b.bans[ip.String()]
+	return banned
+}
+
+// Bans returns the bans, sorted by IP
+func (b *Banlist) Bans() []Ban {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	bans := make([]Ban, 0, len(b.bans))
+	for _, ban := range b.bans {
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].IP < bans[j].IP })
+	return bans
+}
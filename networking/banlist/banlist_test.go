@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package banlist
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func TestBanlist(t *testing.T) {
+	db := memdb.New()
+
+	b := Banlist{}
+	if err := b.Initialize(db); err != nil {
+		t.Fatal(err)
+	}
+	b.clock.Set(time.Unix(1000, 0))
+
+	ip := net.ParseIP("10.0.0.1")
+	otherIP := net.ParseIP("10.0.0.2")
+
+	if b.IsBanned(ip) {
+		t.Fatalf("IP shouldn't have been banned yet")
+	}
+	if err := b.Unban(ip); err == nil {
+		t.Fatalf("Should have errored due to the IP not being banned")
+	}
+	if err := b.Ban(net.IP{1, 2, 3}, "bad"); err == nil {
+		t.Fatalf("Should have errored due to the IP being invalid")
+	}
+
+	if err := b.Ban(ip, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsBanned(ip) {
+		t.Fatalf("IP should have been banned")
+	}
+	if b.IsBanned(otherIP) {
+		t.Fatalf("Other IP shouldn't have been banned")
+	}
+	if !b.IsBanned(net.ParseIP("::ffff:10.0.0.1")) {
+		t.Fatalf("IPv4-mapped IPv6 form of the IP should have been banned")
+	}
+
+	// The bans should survive a restart
+	restarted := Banlist{}
+	if err := restarted.Initialize(db); err != nil {
+		t.Fatal(err)
+	}
+	if bans := restarted.Bans(); len(bans) != 1 {
+		t.Fatalf("Should have had 1 ban but had %d", len(bans))
+	} else if ban := bans[0]; ban.IP != "10.0.0.1" || ban.Reason != "spam" || ban.Since != 1000 {
+		t.Fatalf("Wrong ban %+v", ban)
+	}
+
+	if err := restarted.Unban(ip); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.IsBanned(ip) {
+		t.Fatalf("IP should have been unbanned")
+	}
+
+	restarted = Banlist{}
+	if err := restarted.Initialize(db); err != nil {
+		t.Fatal(err)
+	}
+	if bans := restarted.Bans(); len(bans) != 0 {
+		t.Fatalf("Unban should have been persisted, but had %d bans", len(bans))
+	}
+}
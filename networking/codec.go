@@ -44,6 +44,7 @@ func (Codec) Pack(op salticidae.Opcode, fields map[Field]interface{}) (Msg, erro
 	return &msg{
 		op:     op,
 		ds:     salticidae.NewDataStreamFromBytes(p.Bytes, false),
+		bytes:  p.Bytes,
 		fields: fields,
 	}, nil
 }
@@ -64,18 +65,29 @@ func (Codec) Parse(op salticidae.Opcode, ds salticidae.DataStream) (Msg, error)
 
 	copy(p.Bytes, byteHandle.Get())
 
-	fields := make(map[Field]interface{}, len(message))
-	for _, field := range message {
-		fields[field] = field.Unpacker()(&p)
-	}
-
-	if p.Offset != size {
-		return nil, errBadLength
+	fields, err := parseFields(message, &p)
+	if err != nil {
+		return nil, err
 	}
 
 	return &msg{
 		op:     op,
 		ds:     ds,
+		bytes:  p.Bytes,
 		fields: fields,
-	}, p.Err
+	}, nil
+}
+
+// parseFields unpacks the fields of [message] from [p], which must contain
+// exactly those fields
+func parseFields(message []Field, p *wrappers.Packer) (map[Field]interface{}, error) {
+	fields := make(map[Field]interface{}, len(message))
+	for _, field := range message {
+		fields[field] = field.Unpacker()(p)
+	}
+
+	if p.Offset != len(p.Bytes) {
+		return nil, errBadLength
+	}
+	return fields, p.Err
 }
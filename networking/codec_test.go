@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/salticidae-go"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// FuzzCodecParse checks that parsing arbitrary bytes received from a peer
+// never panics, and that a parsed message packs back into the bytes it was
+// parsed from. Inputs that fail are saved in testdata/fuzz, so they're rerun
+// by go test.
+func FuzzCodecParse(f *testing.F) {
+	containerID := make([]byte, 32)
+	seeds := map[salticidae.Opcode]map[Field]interface{}{
+		Version: {
+			NetworkID:  uint32(12345),
+			MyTime:     uint64(1589000000),
+			VersionStr: "avalanche/0.2.0",
+		},
+		Put: {
+			ChainID:        containerID,
+			RequestID:      uint32(1),
+			ContainerID:    containerID,
+			ContainerBytes: []byte{1, 2, 3},
+		},
+		Chits: {
+			ChainID:      containerID,
+			RequestID:    uint32(2),
+			ContainerIDs: [][]byte{containerID, containerID},
+		},
+		MultiPut: {
+			ChainID:             containerID,
+			RequestID:           uint32(3),
+			MultiContainerBytes: [][]byte{{1, 2, 3}, {}},
+		},
+	}
+	for op, fields := range seeds {
+		p := wrappers.Packer{MaxSize: 1024}
+		for _, field := range Messages[op] {
+			field.Packer()(&p, fields[field])
+		}
+		if p.Errored() {
+			f.Fatal(p.Err)
+		}
+		f.Add(uint8(op), p.Bytes)
+	}
+
+	f.Fuzz(func(t *testing.T, op uint8, b []byte) {
+		message, ok := Messages[salticidae.Opcode(op)]
+		if !ok {
+			return
+		}
+
+		fields, err := parseFields(message, &wrappers.Packer{Bytes: b})
+		if err != nil {
+			return
+		}
+
+		p := wrappers.Packer{MaxSize: len(b)}
+		for _, field := range message {
+			field.Packer()(&p, fields[field])
+		}
+		if p.Errored() {
+			t.Fatalf("couldn't repack %v: %s", fields, p.Err)
+		}
+		if !bytes.Equal(p.Bytes, b) {
+			t.Fatalf("parsed %v from %v but it packed into %v", fields, b, p.Bytes)
+		}
+	})
+}
@@ -14,19 +14,20 @@ type Field uint32
 
 // Fields that may be packed. These values are not sent over the wire.
 const (
-	VersionStr     Field = iota // Used in handshake
-	NetworkID                   // Used in handshake
-	MyTime                      // Used in handshake
-	Peers                       // Used in handshake
-	ChainID                     // Used for dispatching
-	RequestID                   // Used for all messages
-	ContainerID                 // Used for querying
-	ContainerBytes              // Used for gossiping
-	ContainerIDs                // Used for querying
-	Bytes                       // Used as arbitrary data
-	TxID                        // Used for throughput tests
-	Tx                          // Used for throughput tests
-	Status                      // Used for throughput tests
+	VersionStr          Field = iota // Used in handshake
+	NetworkID                        // Used in handshake
+	MyTime                           // Used in handshake
+	Peers                            // Used in handshake
+	ChainID                          // Used for dispatching
+	RequestID                        // Used for all messages
+	ContainerID                      // Used for querying
+	ContainerBytes                   // Used for gossiping
+	ContainerIDs                     // Used for querying
+	Bytes                            // Used as arbitrary data
+	TxID                             // Used for throughput tests
+	Tx                               // Used for throughput tests
+	Status                           // Used for throughput tests
+	MultiContainerBytes              // Used for fetching ancestors
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -58,6 +59,8 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackBytes
 	case Status:
 		return wrappers.TryPackInt
+	case MultiContainerBytes:
+		return wrappers.TryPack2DBytes
 	default:
 		return nil
 	}
@@ -92,11 +95,50 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackBytes
 	case Status:
 		return wrappers.TryUnpackInt
+	case MultiContainerBytes:
+		return wrappers.TryUnpack2DBytes
 	default:
 		return nil
 	}
 }
 
+// Encoding returns how this field is packed, as one of the encodings described
+// by Encodings
+func (f Field) Encoding() string {
+	switch f {
+	case VersionStr:
+		return EncodingStr
+	case NetworkID:
+		return EncodingUint32
+	case MyTime:
+		return EncodingUint64
+	case Peers:
+		return EncodingIPList
+	case ChainID:
+		return EncodingHash
+	case RequestID:
+		return EncodingUint32
+	case ContainerID:
+		return EncodingHash
+	case ContainerBytes:
+		return EncodingBytes
+	case ContainerIDs:
+		return EncodingHashes
+	case Bytes:
+		return EncodingBytes
+	case TxID:
+		return EncodingHash
+	case Tx:
+		return EncodingBytes
+	case Status:
+		return EncodingUint32
+	case MultiContainerBytes:
+		return Encoding2DBytes
+	default:
+		return ""
+	}
+}
+
 func (f Field) String() string {
 	switch f {
 	case VersionStr:
@@ -109,6 +151,8 @@ func (f Field) String() string {
 		return "Peers"
 	case ChainID:
 		return "ChainID"
+	case RequestID:
+		return "RequestID"
 	case ContainerID:
 		return "ContainerID"
 	case ContainerBytes:
@@ -123,6 +167,8 @@ func (f Field) String() string {
 		return "Tx"
 	case Status:
 		return "Status"
+	case MultiContainerBytes:
+		return "MultiContainerBytes"
 	default:
 		return "Unknown Field"
 	}
@@ -154,6 +200,9 @@ const (
 	// Throughput test:
 	IssueTx
 	DecidedTx
+	// Bootstrapping:
+	GetAncestors
+	MultiPut
 )
 
 // Defines the messages that can be sent/received with this network
@@ -183,5 +232,8 @@ var (
 		// Throughput test:
 		IssueTx:   []Field{ChainID, Tx},
 		DecidedTx: []Field{TxID, Status},
+		// Bootstrapping:
+		GetAncestors: []Field{ChainID, RequestID, ContainerID},
+		MultiPut:     []Field{ChainID, RequestID, MultiContainerBytes},
 	}
 )
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
 	"sync"
 	"time"
 	"unsafe"
@@ -28,7 +29,11 @@ import (
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/banlist"
+	"github.com/ava-labs/gecko/networking/uptime"
+	"github.com/ava-labs/gecko/networking/versions"
 	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/hashing"
@@ -70,6 +75,23 @@ const (
 	// GetVersionTimeout is the amount of time to wait before sending a
 	// getVersion message to a partially connected peer
 	GetVersionTimeout = 2 * time.Second
+	// PingFrequency is the amount of time to wait between pinging the
+	// connected peers to measure the round trip times to them
+	PingFrequency = 30 * time.Second
+	// SendQueueSize is the number of messages that may be waiting to be sent
+	// to a peer. Once a peer's queue is full, its lowest priority messages are
+	// dropped.
+	SendQueueSize = 1 << 10
+	// SendBufferSize is the number of messages the write buffer of a peer's
+	// connection holds. Once a peer's write buffer is full, messages wait in
+	// its send queue.
+	SendBufferSize = 1 << 7
+	// SendRetryDelay is the amount of time to wait before retrying to write a
+	// message to a peer whose write buffer is full
+	SendRetryDelay = 10 * time.Millisecond
+	// SendTimeout is the amount of time a message waits for a peer's write
+	// buffer to take it before it's dropped
+	SendTimeout = 10 * time.Second
 )
 
 // Manager is the struct that will be accessed on event calls
@@ -93,7 +115,8 @@ type Handshake struct {
 	myAddr        salticidae.NetAddr
 	myID          ids.ShortID
 	net           salticidae.PeerNetwork
-	enableStaking bool // Should only be false for local tests
+	queues        sendQueues // Messages waiting to be sent to each peer
+	enableStaking bool       // Should only be false for local tests
 
 	clock       timer.Clock
 	pending     AddrCert // Connections that I haven't gotten version messages from
@@ -101,9 +124,23 @@ type Handshake struct {
 
 	versionTimeout   timer.TimeoutManager
 	peerListGossiper *timer.Repeater
+	pinger           *timer.Repeater
+	versionChecker   *timer.Repeater // nil if the version check is disabled
+
+	latencies timeout.LatencyTracker // Round trip times to connected peers
+	banlist   *banlist.Banlist       // IPs this node refuses to connect to
+	versions  versions.Tracker       // Versions run by connected peers
+	uptimes   uptime.Tracker         // When peers were connected and last heard from
+	behind    bool                   // Result of the last version check
 
 	awaitingLock sync.Mutex
 	awaiting     []*networking.AwaitingConnections
+
+	// knownIPs maps the IDs of peers this node has finished a handshake with
+	// to their IPs, so that this node can reconnect to them if they become
+	// validators
+	knownIPsLock sync.Mutex
+	knownIPs     map[[20]byte]utils.IPDesc
 }
 
 // Initialize to the c networking library. This should only be done once during
@@ -117,6 +154,8 @@ func (nm *Handshake) Initialize(
 	registerer prometheus.Registerer,
 	enableStaking bool,
 	networkID uint32,
+	bans *banlist.Banlist,
+	versionCheckFrequency time.Duration,
 ) {
 	log.AssertTrue(nm.net == nil, "Should only register network handlers once")
 	nm.log = log
@@ -126,6 +165,11 @@ func (nm *Handshake) Initialize(
 	nm.net = peerNet
 	nm.enableStaking = enableStaking
 	nm.networkID = networkID
+	nm.knownIPs = make(map[[20]byte]utils.IPDesc)
+	nm.latencies.Initialize()
+	nm.banlist = bans
+	nm.versions.Initialize(vdrs, myID, CurrentVersion)
+	nm.uptimes.Initialize(myID)
 
 	net := peerNet.AsMsgNetwork()
 
@@ -140,11 +184,63 @@ func (nm *Handshake) Initialize(
 	net.RegHandler(PeerList, salticidae.MsgNetworkMsgCallback(C.peerList), nil)
 
 	nm.handshakeMetrics.Initialize(nm.log, registerer)
+	nm.queues.Initialize(nm.log, peerNet, SendQueueSize, SendRetryDelay, SendTimeout, registerer)
 
 	nm.versionTimeout.Initialize(GetVersionTimeout)
 	go nm.log.RecoverAndPanic(nm.versionTimeout.Dispatch)
 	nm.peerListGossiper = timer.NewRepeater(nm.gossipPeerList, PeerListGossipSpacing)
 	go nm.log.RecoverAndPanic(nm.peerListGossiper.Dispatch)
+	nm.pinger = timer.NewRepeater(nm.ping, PingFrequency)
+	go nm.log.RecoverAndPanic(nm.pinger.Dispatch)
+	if versionCheckFrequency > 0 {
+		nm.versionChecker = timer.NewRepeater(nm.checkVersion, versionCheckFrequency)
+		go nm.log.RecoverAndPanic(nm.versionChecker.Dispatch)
+	}
+
+	// When staking is disabled, the validator set is populated by the
+	// connections themselves, so it can't be used to drive them.
+	if enableStaking {
+		vdrs.RegisterCallbackListener(nm)
+	}
+}
+
+// OnValidatorAdded implements the validators.SetCallbackListener interface.
+// This node proactively connects to validators that joined the validator set
+// if it knows their IP.
+func (nm *Handshake) OnValidatorAdded(vdrID ids.ShortID) {
+	if vdrID.Equals(nm.myID) || nm.connections.ContainsID(vdrID) || nm.pending.ContainsID(vdrID) {
+		return
+	}
+
+	nm.knownIPsLock.Lock()
+	ip, known := nm.knownIPs[vdrID.Key()]
+	nm.knownIPsLock.Unlock()
+
+	if !known {
+		nm.log.Debug("Validator %s joined, but its IP isn't known", vdrID)
+		return
+	}
+	if nm.banlist.IsBanned(ip.IP) {
+		nm.log.Debug("Validator %s joined, but its IP %s is banned", vdrID, ip)
+		return
+	}
+
+	nm.log.Debug("Connecting to validator %s at %s", vdrID, ip)
+	addr := toAddr(ip, false)
+	nm.net.AddPeer(addr)
+	addr.Free()
+}
+
+// OnValidatorRemoved implements the validators.SetCallbackListener interface.
+// This node drops its connection to validators that left the validator set.
+func (nm *Handshake) OnValidatorRemoved(vdrID ids.ShortID) {
+	addr, connected := nm.connections.GetIP(vdrID)
+	if !connected {
+		return
+	}
+
+	nm.log.Debug("Disconnecting from %s as it is no longer a validator", vdrID)
+	nm.net.DelPeer(addr)
 }
 
 // AwaitConnections ...
@@ -204,14 +300,100 @@ func (nm *Handshake) gossipPeerList() {
 	nm.SendPeerList(ips...)
 }
 
+// ping the connected peers so that the round trip times to them are measured
+// when they reply
+func (nm *Handshake) ping() {
+	addrs, certs := nm.connections.RawConns()
+	if len(addrs) == 0 {
+		return
+	}
+
+	for _, cert := range certs {
+		nm.latencies.Sent(cert)
+	}
+
+	build := Builder{}
+	p, err := build.Ping()
+	nm.log.AssertNoError(err)
+	nm.send(p, addrs...)
+	nm.numPingSent.Add(float64(len(addrs)))
+}
+
+// checkVersion warns if validators with a majority of the stake are running a
+// later version than this node
+func (nm *Handshake) checkVersion() {
+	behind := nm.versions.Behind()
+	if behind && !nm.behind {
+		nm.log.Warn("Validators with a majority of the stake are running a later version than %s. This node should be upgraded", CurrentVersion)
+	}
+	nm.behind = behind
+
+	if behind {
+		nm.versionBehind.Set(1)
+	} else {
+		nm.versionBehind.Set(0)
+	}
+}
+
 // Connections returns the object that tracks the nodes that are currently
 // connected to this node.
 func (nm *Handshake) Connections() Connections { return &nm.connections }
 
+// SendQueues returns the queues of the messages waiting to be sent to the
+// nodes that are currently connected to this node.
+func (nm *Handshake) SendQueues() SendQueues { return &nm.queues }
+
+// Latencies returns the object that tracks the round trip times to the nodes
+// that are currently connected to this node.
+func (nm *Handshake) Latencies() *timeout.LatencyTracker { return &nm.latencies }
+
+// Versions returns the object that tracks the versions run by the nodes that
+// are currently connected to this node.
+func (nm *Handshake) Versions() *versions.Tracker { return &nm.versions }
+
+// Uptimes returns the object that tracks when this node was connected to its
+// peers, and when it last heard from them.
+func (nm *Handshake) Uptimes() *uptime.Tracker { return &nm.uptimes }
+
+// Ban [ip] for [reason] and disconnect from the peers at it
+func (nm *Handshake) Ban(ip net.IP, reason string) error {
+	if err := nm.banlist.Ban(ip, reason); err != nil {
+		return err
+	}
+	nm.log.Info("Banned %s due to: %s", ip, reason)
+
+	for _, conns := range []*AddrCert{&nm.pending, &nm.connections} {
+		addrs, _ := conns.RawConns()
+		for _, addr := range addrs {
+			if toIPDesc(addr).IP.Equal(ip) {
+				nm.net.DelPeer(addr)
+			}
+		}
+	}
+	return nil
+}
+
+// Unban [ip]
+func (nm *Handshake) Unban(ip net.IP) error {
+	if err := nm.banlist.Unban(ip); err != nil {
+		return err
+	}
+	nm.log.Info("Unbanned %s", ip)
+	return nil
+}
+
+// Bans returns the banned IPs
+func (nm *Handshake) Bans() []banlist.Ban { return nm.banlist.Bans() }
+
 // Shutdown the network
 func (nm *Handshake) Shutdown() {
 	nm.versionTimeout.Stop()
 	nm.peerListGossiper.Stop()
+	nm.pinger.Stop()
+	if nm.versionChecker != nil {
+		nm.versionChecker.Stop()
+	}
+	nm.queues.Shutdown()
 }
 
 // SendGetVersion to the requested peer
@@ -268,20 +450,7 @@ func (nm *Handshake) SendPeerList(addrs ...salticidae.NetAddr) error {
 }
 
 func (nm *Handshake) send(msg Msg, addrs ...salticidae.NetAddr) {
-	ds := msg.DataStream()
-	defer ds.Free()
-	ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
-	defer ba.Free()
-	cMsg := salticidae.NewMsgMovedFromByteArray(msg.Op(), ba, false)
-	defer cMsg.Free()
-
-	switch len(addrs) {
-	case 0:
-	case 1:
-		nm.net.SendMsg(cMsg, addrs[0])
-	default:
-		nm.net.MulticastMsgByMove(cMsg, addrs)
-	}
+	nm.queues.Send(msg, priority(msg.Op()), ids.Empty, addrs...)
 }
 
 // checkPeerCertificate of a new inbound connection
@@ -316,6 +485,10 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 
 		HandshakeNet.pending.RemoveIP(addr)
 		HandshakeNet.connections.RemoveIP(addr)
+		HandshakeNet.queues.remove(ip)
+		HandshakeNet.latencies.Remove(cert)
+		HandshakeNet.versions.Disconnected(cert)
+		HandshakeNet.uptimes.Disconnected(cert)
 
 		HandshakeNet.numPeers.Set(float64(HandshakeNet.connections.Len()))
 
@@ -331,6 +504,12 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 		return
 	}
 
+	if HandshakeNet.banlist.IsBanned(ip.IP) {
+		HandshakeNet.log.Debug("Dropping connection to banned IP %s", ip)
+		HandshakeNet.net.DelPeer(addr)
+		return
+	}
+
 	HandshakeNet.log.Debug("Connected to %s", ip)
 
 	// If we're enforcing staking, use a peer's certificate to uniquely identify them
@@ -359,6 +538,10 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 func unknownPeerHandler(_addr *C.netaddr_t, _cert *C.x509_t, _ unsafe.Pointer) {
 	addr := salticidae.NetAddrFromC(salticidae.CNetAddr(_addr))
 	ip := toIPDesc(addr)
+	if HandshakeNet.banlist.IsBanned(ip.IP) {
+		HandshakeNet.log.Debug("Not adding peer %s as its IP is banned", ip)
+		return
+	}
 	HandshakeNet.log.Info("Adding peer %s", ip)
 	HandshakeNet.net.AddPeer(addr)
 }
@@ -366,6 +549,8 @@ func unknownPeerHandler(_addr *C.netaddr_t, _cert *C.x509_t, _ unsafe.Pointer) {
 // ping handles the recept of a ping message
 //export ping
 func ping(_ *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	HandshakeNet.numPingReceived.Inc()
+
 	conn := salticidae.PeerNetworkConnFromC(salticidae.CPeerNetworkConn(_conn))
 	addr := conn.GetPeerAddr(false)
 	defer addr.Free()
@@ -379,11 +564,27 @@ func ping(_ *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer
 	HandshakeNet.log.AssertNoError(err)
 
 	HandshakeNet.send(pong, addr)
+	HandshakeNet.numPongSent.Inc()
 }
 
 // pong handles the recept of a pong message
 //export pong
-func pong(*C.struct_msg_t, *C.struct_msgnetwork_conn_t, unsafe.Pointer) {}
+func pong(_ *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	HandshakeNet.numPongReceived.Inc()
+
+	conn := salticidae.PeerNetworkConnFromC(salticidae.CPeerNetworkConn(_conn))
+	addr := conn.GetPeerAddr(false)
+	defer addr.Free()
+	if addr.IsNull() {
+		HandshakeNet.log.Warn("Pong sent from unknown peer")
+		return
+	}
+
+	if cert, exists := HandshakeNet.connections.GetID(addr); exists {
+		HandshakeNet.latencies.Received(cert)
+		HandshakeNet.uptimes.Heard(cert)
+	}
+}
 
 // getVersion handles the recept of a getVersion message
 //export getVersion
@@ -449,7 +650,8 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 		return
 	}
 
-	if peerVersion := pMsg.Get(VersionStr).(string); !checkCompatibility(CurrentVersion, peerVersion) {
+	peerVersion := pMsg.Get(VersionStr).(string)
+	if !checkCompatibility(CurrentVersion, peerVersion) {
 		HandshakeNet.log.Warn("Bad version")
 
 		HandshakeNet.net.DelPeer(addr)
@@ -460,6 +662,12 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 
 	HandshakeNet.SendPeerList(addr)
 	HandshakeNet.connections.Add(addr, cert)
+	HandshakeNet.versions.Connected(cert, peerVersion)
+	HandshakeNet.uptimes.Connected(cert)
+
+	HandshakeNet.knownIPsLock.Lock()
+	HandshakeNet.knownIPs[cert.Key()] = toIPDesc(addr)
+	HandshakeNet.knownIPsLock.Unlock()
 
 	HandshakeNet.versionTimeout.Remove(cert.LongID())
 
@@ -526,7 +734,7 @@ func peerList(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 		if cErr.GetCode() == 0 && !HandshakeNet.myAddr.IsEq(addr) { // Make sure not to connect to myself
 			ip := toIPDesc(addr)
 
-			if !HandshakeNet.pending.ContainsIP(addr) && !HandshakeNet.connections.ContainsIP(addr) {
+			if !HandshakeNet.pending.ContainsIP(addr) && !HandshakeNet.connections.ContainsIP(addr) && !HandshakeNet.banlist.IsBanned(ip.IP) {
 				HandshakeNet.log.Debug("Adding peer %s", ip)
 				HandshakeNet.net.AddPeer(addr)
 			}
@@ -10,12 +10,14 @@ import (
 )
 
 type handshakeMetrics struct {
-	numPeers prometheus.Gauge
+	numPeers, versionBehind prometheus.Gauge
 
 	numGetVersionSent, numGetVersionReceived,
 	numVersionSent, numVersionReceived,
 	numGetPeerlistSent, numGetPeerlistReceived,
-	numPeerlistSent, numPeerlistReceived prometheus.Counter
+	numPeerlistSent, numPeerlistReceived,
+	numPingSent, numPingReceived,
+	numPongSent, numPongReceived prometheus.Counter
 }
 
 func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus.Registerer) {
@@ -25,6 +27,12 @@ func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus
 			Name:      "peers",
 			Help:      "Number of network peers",
 		})
+	hm.versionBehind = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "version_behind",
+			Help:      "1 if validators with a majority of the stake are running a later version than this node, 0 otherwise",
+		})
 	hm.numGetVersionSent = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "gecko",
@@ -73,10 +81,37 @@ func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus
 			Name:      "peerlist_received",
 			Help:      "Number of peerlist messages received",
 		})
+	hm.numPingSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "ping_sent",
+			Help:      "Number of ping messages sent",
+		})
+	hm.numPingReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "ping_received",
+			Help:      "Number of ping messages received",
+		})
+	hm.numPongSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "pong_sent",
+			Help:      "Number of pong messages sent",
+		})
+	hm.numPongReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "pong_received",
+			Help:      "Number of pong messages received",
+		})
 
 	if err := registerer.Register(hm.numPeers); err != nil {
 		log.Error("Failed to register peers statistics due to %s", err)
 	}
+	if err := registerer.Register(hm.versionBehind); err != nil {
+		log.Error("Failed to register version_behind statistics due to %s", err)
+	}
 	if err := registerer.Register(hm.numGetVersionSent); err != nil {
 		log.Error("Failed to register get_version_sent statistics due to %s", err)
 	}
@@ -101,4 +136,16 @@ func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus
 	if err := registerer.Register(hm.numPeerlistReceived); err != nil {
 		log.Error("Failed to register peerlist_received statistics due to %s", err)
 	}
+	if err := registerer.Register(hm.numPingSent); err != nil {
+		log.Error("Failed to register ping_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(hm.numPingReceived); err != nil {
+		log.Error("Failed to register ping_received statistics due to %s", err)
+	}
+	if err := registerer.Register(hm.numPongSent); err != nil {
+		log.Error("Failed to register pong_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(hm.numPongReceived); err != nil {
+		log.Error("Failed to register pong_received statistics due to %s", err)
+	}
 }
@@ -12,11 +12,13 @@ type Msg interface {
 	Op() salticidae.Opcode
 	Get(Field) interface{}
 	DataStream() salticidae.DataStream
+	Bytes() []byte
 }
 
 type msg struct {
 	op     salticidae.Opcode
 	ds     salticidae.DataStream
+	bytes  []byte
 	fields map[Field]interface{}
 }
 
@@ -26,5 +28,8 @@ func (msg *msg) Op() salticidae.Opcode { return msg.op }
 // Field returns the value of the specified field in this message
 func (msg *msg) Get(field Field) interface{} { return msg.fields[field] }
 
-// Bytes returns this message in bytes
+// DataStream returns this message as a datastream
 func (msg *msg) DataStream() salticidae.DataStream { return msg.ds }
+
+// Bytes returns this message in bytes
+func (msg *msg) Bytes() []byte { return msg.bytes }
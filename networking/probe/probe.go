@@ -0,0 +1,91 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package probe implements lightweight UDP liveness probes. A monitor sends a
+// random nonce to a node, and the node replies with its staking certificate
+// and a signature of the nonce made with its staking key. This proves that the
+// node is alive and that it holds the key of its node ID, without a TLS
+// session or the HTTP API.
+package probe
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// Magic starts every probe request and response
+	Magic = "avaprobe"
+
+	// NonceLen is the length of the nonce in a probe request
+	NonceLen = staking.SignedNonceLen
+
+	// MaxPacketSize is the largest probe that is read or written
+	MaxPacketSize = 1 << 14
+)
+
+var (
+	errWrongMagic     = errors.New("probe doesn't start with the magic bytes")
+	errWrongNonce     = errors.New("response is for a different nonce")
+	errUnsupportedKey = errors.New("certificate has an unsupported public key type")
+	errTrailingBytes  = errors.New("response has trailing bytes")
+)
+
+// Request returns a probe request of [size] bytes carrying [nonce]. A node
+// never responds with more bytes than it was sent, so [size] must be large
+// enough for the response, which carries the node's certificate.
+func Request(nonce [NonceLen]byte, size int) []byte {
+	if minSize := len(Magic) + NonceLen; size < minSize {
+		size = minSize
+	}
+	request := make([]byte, size)
+	copy(request, Magic)
+	copy(request[len(Magic):], nonce[:])
+	return request
+}
+
+// Digest returns the digest that is signed to answer a probe with [nonce]
+func Digest(nonce [NonceLen]byte) []byte {
+	return hashing.ComputeHash256(staking.ProbeMessage(nonce))
+}
+
+// Verify that [response] answers the probe with [nonce]. It returns the ID of
+// the node that answered.
+func Verify(response []byte, nonce [NonceLen]byte) (ids.ShortID, error) {
+	if len(response) < len(Magic)+NonceLen || string(response[:len(Magic)]) != Magic {
+		return ids.ShortID{}, errWrongMagic
+	}
+	p := wrappers.Packer{Bytes: response, Offset: len(Magic)}
+	responseNonce := p.UnpackFixedBytes(NonceLen)
+	sig := p.UnpackBytes()
+	certBytes := p.UnpackBytes()
+	switch {
+	case p.Errored():
+		return ids.ShortID{}, fmt.Errorf("couldn't parse response: %w", p.Err)
+	case p.Offset != len(response):
+		return ids.ShortID{}, errTrailingBytes
+	case string(responseNonce) != string(nonce[:]):
+		return ids.ShortID{}, errWrongNonce
+	}
+
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("couldn't parse certificate: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ids.ShortID{}, errUnsupportedKey
+	}
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, Digest(nonce), sig); err != nil {
+		return ids.ShortID{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return staking.NodeID(cert.Raw)
+}
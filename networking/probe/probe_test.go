@@ -0,0 +1,122 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package probe
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}
+}
+
+func TestRespond(t *testing.T) {
+	cert := testCertificate(t)
+	nodeID, err := staking.NodeID(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewResponder(logging.NoLog{}, nil, cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	r.clock.Set(now)
+
+	nonce := [NonceLen]byte{1, 2, 3}
+	if _, ok := r.respond(Request(nonce, 0)); ok {
+		t.Fatalf("Shouldn't have answered a probe smaller than the response")
+	}
+	if _, ok := r.respond(make([]byte, MaxPacketSize)); ok {
+		t.Fatalf("Shouldn't have answered a probe without the magic bytes")
+	}
+
+	response, ok := r.respond(Request(nonce, MaxPacketSize))
+	if !ok {
+		t.Fatalf("Should have answered the probe")
+	}
+	if len(response) > MaxPacketSize {
+		t.Fatalf("Response is larger than the probe")
+	}
+	if id, err := Verify(response, nonce); err != nil {
+		t.Fatal(err)
+	} else if !id.Equals(nodeID) {
+		t.Fatalf("Response was from %s but should have been from %s", id, nodeID)
+	}
+	if _, err := Verify(response, [NonceLen]byte{4}); err == nil {
+		t.Fatalf("Should have errored due to the response being for a different nonce")
+	}
+
+	if _, ok := r.respond(Request(nonce, MaxPacketSize)); ok {
+		t.Fatalf("Shouldn't have answered probes more frequently than the minimum interval")
+	}
+	r.clock.Set(now.Add(MinResponseInterval))
+	if _, ok := r.respond(Request(nonce, MaxPacketSize)); !ok {
+		t.Fatalf("Should have answered the probe after the minimum interval")
+	}
+}
+
+func TestResponderDispatch(t *testing.T) {
+	cert := testCertificate(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("couldn't listen on UDP: %s", err)
+	}
+	r, err := NewResponder(logging.NoLog{}, conn, cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go r.Dispatch()
+	defer r.Stop()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	nonce := [NonceLen]byte{5}
+	if _, err := client.Write(Request(nonce, 4096)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, MaxPacketSize)
+	n, err := client.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(buf[:n], nonce); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package probe
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// MinResponseInterval is the least amount of time between two responses.
+	// Signing is expensive, so this bounds the CPU a flood of probes can use.
+	MinResponseInterval = 10 * time.Millisecond
+)
+
+var (
+	errNoCertificate = errors.New("no certificate provided")
+	errNotSigner     = errors.New("private key can't sign")
+)
+
+// Responder answers probes sent to a UDP socket
+type Responder struct {
+	log    logging.Logger
+	conn   net.PacketConn
+	signer crypto.Signer
+	cert   []byte
+
+	clock        timer.Clock
+	lastResponse time.Time
+}
+
+// NewResponder returns a responder that answers the probes sent to [conn] by
+// signing them with [cert]
+func NewResponder(log logging.Logger, conn net.PacketConn, cert tls.Certificate) (*Responder, error) {
+	if len(cert.Certificate) == 0 {
+		return nil, errNoCertificate
+	}
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errNotSigner
+	}
+	return &Responder{
+		log:    log,
+		conn:   conn,
+		signer: signer,
+		cert:   cert.Certificate[0],
+	}, nil
+}
+
+// Dispatch answers probes until the responder is stopped
+func (r *Responder) Dispatch() {
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, addr, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			r.log.Debug("Probe responder stopped due to: %s", err)
+			return
+		}
+
+		response, ok := r.respond(buf[:n])
+		if !ok {
+			continue
+		}
+		if _, err := r.conn.WriteTo(response, addr); err != nil {
+			r.log.Debug("Failed to answer probe from %s due to: %s", addr, err)
+		}
+	}
+}
+
+// Stop answering probes
+func (r *Responder) Stop() error { return r.conn.Close() }
+
+// respond returns the response to [request], and false if the request should
+// be ignored
+func (r *Responder) respond(request []byte) ([]byte, bool) {
+	if len(request) < len(Magic)+NonceLen || string(request[:len(Magic)]) != Magic {
+		return nil, false
+	}
+
+	// The response carries the certificate, so it's larger than a request
+	// that isn't padded. Never sending more bytes than were received keeps the
+	// responder from amplifying spoofed traffic. This check is repeated once
+	// the size of the signature is known.
+	if len(request) < len(Magic)+NonceLen+2*wrappers.IntLen+len(r.cert) {
+		return nil, false
+	}
+
+	now := r.clock.Time()
+	if now.Sub(r.lastResponse) < MinResponseInterval {
+		return nil, false
+	}
+	r.lastResponse = now
+
+	nonce := [NonceLen]byte{}
+	copy(nonce[:], request[len(Magic):])
+	sig, err := r.signer.Sign(rand.Reader, Digest(nonce), crypto.SHA256)
+	if err != nil {
+		r.log.Error("Failed to sign probe due to: %s", err)
+		return nil, false
+	}
+
+	p := wrappers.Packer{MaxSize: MaxPacketSize}
+	p.PackFixedBytes([]byte(Magic))
+	p.PackFixedBytes(nonce[:])
+	p.PackBytes(sig)
+	p.PackBytes(r.cert)
+	if p.Errored() || len(p.Bytes) > len(request) {
+		return nil, false
+	}
+	return p.Bytes, true
+}
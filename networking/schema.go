@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sort"
+
+	"github.com/ava-labs/salticidae-go"
+)
+
+// The encodings fields are packed with
+const (
+	EncodingUint32  = "uint32"
+	EncodingUint64  = "uint64"
+	EncodingStr     = "string"
+	EncodingBytes   = "bytes"
+	EncodingHash    = "hash"
+	EncodingHashes  = "[]hash"
+	Encoding2DBytes = "[]bytes"
+	EncodingIPList  = "[]ip"
+)
+
+// Encodings describes how each encoding is laid out on the wire. Integers are
+// big endian.
+var Encodings = map[string]string{
+	EncodingUint32:  "4 byte unsigned integer",
+	EncodingUint64:  "8 byte unsigned integer",
+	EncodingStr:     "uint16 length, followed by that many bytes of UTF-8",
+	EncodingBytes:   "uint32 length, followed by that many bytes",
+	EncodingHash:    "32 bytes",
+	EncodingHashes:  "uint32 count, followed by that many 32 byte hashes",
+	Encoding2DBytes: "uint32 count, followed by that many bytes fields",
+	EncodingIPList:  "uint32 count, followed by that many 16 byte IPv6 addresses, each followed by a uint16 port",
+}
+
+// opNames are the names of the public commands
+var opNames = map[salticidae.Opcode]string{
+	GetVersion:          "GetVersion",
+	Version:             "Version",
+	GetPeerList:         "GetPeerList",
+	PeerList:            "PeerList",
+	GetAcceptedFrontier: "GetAcceptedFrontier",
+	AcceptedFrontier:    "AcceptedFrontier",
+	GetAccepted:         "GetAccepted",
+	Accepted:            "Accepted",
+	Get:                 "Get",
+	Put:                 "Put",
+	PushQuery:           "PushQuery",
+	PullQuery:           "PullQuery",
+	Chits:               "Chits",
+	Ping:                "Ping",
+	Pong:                "Pong",
+	Data:                "Data",
+	IssueTx:             "IssueTx",
+	DecidedTx:           "DecidedTx",
+	GetAncestors:        "GetAncestors",
+	MultiPut:            "MultiPut",
+}
+
+// OpName returns the name of the command [op]
+func OpName(op salticidae.Opcode) string {
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+	return "Unknown Op"
+}
+
+// FieldSchema describes a field of a message
+type FieldSchema struct {
+	Name     string `json:"name"`
+	Encoding string `json:"encoding"`
+}
+
+// MessageSchema describes a message. Its payload is its fields, packed in
+// order, with nothing between them.
+type MessageSchema struct {
+	Name   string        `json:"name"`
+	Op     uint8         `json:"op"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// Schema returns the messages that can be sent/received with this network,
+// ordered by op code. It's generated from Messages, so it always matches the
+// messages this node packs and parses.
+func Schema() []MessageSchema {
+	ops := make([]int, 0, len(Messages))
+	for op := range Messages {
+		ops = append(ops, int(op))
+	}
+	sort.Ints(ops)
+
+	schema := make([]MessageSchema, len(ops))
+	for i, op := range ops {
+		message := Messages[salticidae.Opcode(op)]
+		fields := make([]FieldSchema, len(message))
+		for j, field := range message {
+			fields[j] = FieldSchema{
+				Name:     field.String(),
+				Encoding: field.Encoding(),
+			}
+		}
+		schema[i] = MessageSchema{
+			Name:   OpName(salticidae.Opcode(op)),
+			Op:     uint8(op),
+			Fields: fields,
+		}
+	}
+	return schema
+}
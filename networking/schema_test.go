@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	if len(schema) != len(Messages) {
+		t.Fatalf("expected %d messages but the schema has %d", len(Messages), len(schema))
+	}
+	for i, message := range schema {
+		if i > 0 && schema[i-1].Op >= message.Op {
+			t.Fatalf("messages should be ordered by op code but %s follows %s", message.Name, schema[i-1].Name)
+		}
+		if message.Name == OpName(255) {
+			t.Fatalf("op %d has no name", message.Op)
+		}
+		for _, field := range message.Fields {
+			if _, ok := Encodings[field.Encoding]; !ok {
+				t.Fatalf("field %s of %s has the undescribed encoding %q", field.Name, message.Name, field.Encoding)
+			}
+		}
+	}
+
+	put := schema[Put]
+	if put.Name != "Put" || len(put.Fields) != 4 {
+		t.Fatalf("unexpected schema of Put: %+v", put)
+	}
+	if field := put.Fields[3]; field.Name != ContainerBytes.String() || field.Encoding != EncodingBytes {
+		t.Fatalf("unexpected schema of Put's last field: %+v", field)
+	}
+}
@@ -0,0 +1,211 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/salticidae-go"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/sendqueue"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// SendQueues queues the messages sent to each peer until the peer's
+// connection can take them
+type SendQueues interface {
+	// Send [msg], a message of the chain [chainID], to [addrs] with priority
+	// [p]. Messages that aren't specific to a chain are sent with the chain
+	// ID ids.Empty.
+	Send(msg Msg, p sendqueue.Priority, chainID ids.ID, addrs ...salticidae.NetAddr)
+}
+
+// outboundMsg is a message queued to be sent to a peer
+type outboundMsg struct {
+	op       salticidae.Opcode
+	bytes    []byte
+	priority sendqueue.Priority
+}
+
+// peerQueue is the send queue of one peer
+type peerQueue struct {
+	queue    *sendqueue.Queue
+	dropping bool // True if messages were dropped since the queue was last empty
+}
+
+// sendQueues holds a bounded send queue for each peer, each drained by its own
+// goroutine, so that a peer that doesn't keep up with the messages sent to it
+// only delays, and drops, its own messages. Within a peer's queue, each chain's
+// messages are a separate stream, so a chain sending the peer many messages
+// only delays, and drops, its own messages. A message is only handed to the
+// network once the write buffer of the peer's connection takes it, so the
+// messages a slow peer hasn't read wait in its send queue.
+type sendQueues struct {
+	log  logging.Logger
+	net  salticidae.PeerNetwork
+	size int
+
+	retryDelay, timeout time.Duration
+
+	numDropped [sendqueue.High + 1]prometheus.Counter // Indexed by priority
+
+	lock   sync.Mutex
+	queues map[string]*peerQueue // IP -> send queue
+}
+
+// Initialize the send queues. Each queue holds at most [size] messages. While a
+// peer's write buffer is full, the next message is retried every [retryDelay],
+// and dropped if the buffer hasn't taken it within [timeout].
+func (sq *sendQueues) Initialize(log logging.Logger, net salticidae.PeerNetwork, size int, retryDelay, timeout time.Duration, registerer prometheus.Registerer) {
+	sq.log = log
+	sq.net = net
+	sq.size = size
+	sq.retryDelay = retryDelay
+	sq.timeout = timeout
+	sq.queues = make(map[string]*peerQueue)
+
+	for _, p := range []sendqueue.Priority{sendqueue.Low, sendqueue.Medium, sendqueue.High} {
+		name := p.String() + "_priority_msgs_dropped"
+		sq.numDropped[p] = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "gecko",
+				Name:      name,
+				Help:      "Number of " + p.String() + " priority messages dropped because a peer's send queue was full, or its write buffer stayed full",
+			})
+		if err := registerer.Register(sq.numDropped[p]); err != nil {
+			log.Error("Failed to register %s statistics due to %s", name, err)
+		}
+	}
+}
+
+// Send implements the SendQueues interface
+func (sq *sendQueues) Send(msg Msg, p sendqueue.Priority, chainID ids.ID, addrs ...salticidae.NetAddr) {
+	// The message is queued as bytes, so that it can be sent from each
+	// peer's goroutine
+	msg.DataStream().Free()
+	outbound := outboundMsg{
+		op:       msg.Op(),
+		bytes:    msg.Bytes(),
+		priority: p,
+	}
+
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+
+	for _, addr := range addrs {
+		ip := toIPDesc(addr)
+		key := ip.String()
+		pq, exists := sq.queues[key]
+		if !exists {
+			pq = &peerQueue{queue: sendqueue.New(sq.size)}
+			sq.queues[key] = pq
+			go sq.log.RecoverAndPanic(func() { sq.drain(ip, pq) })
+		}
+
+		dropped, ok := pq.queue.Push(outbound, p, chainID.Key())
+		if !ok {
+			continue
+		}
+		sq.numDropped[dropped].Inc()
+		if !pq.dropping {
+			pq.dropping = true
+			sq.log.Warn("Dropping %s priority messages to %s, whose send queue is full", dropped, ip)
+		}
+	}
+}
+
+// drain sends the messages queued for the peer at [ip] until its queue is
+// closed
+func (sq *sendQueues) drain(ip utils.IPDesc, pq *peerQueue) {
+	addr := toAddr(ip, false)
+	defer addr.Free()
+
+	for {
+		msgIntf, ok := pq.queue.Pop()
+		if !ok {
+			return
+		}
+		outbound := msgIntf.(outboundMsg)
+
+		ds := salticidae.NewDataStreamFromBytes(outbound.bytes, false)
+		ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
+		cMsg := salticidae.NewMsgMovedFromByteArray(outbound.op, ba, false)
+		sent := sq.write(pq, cMsg, addr)
+		cMsg.Free()
+		ba.Free()
+		ds.Free()
+
+		switch {
+		case pq.queue.Closed():
+			return
+		case !sent:
+			sq.numDropped[outbound.priority].Inc()
+			sq.log.Debug("Dropping a %s priority message to %s, whose write buffer stayed full for %s", outbound.priority, ip, sq.timeout)
+		}
+
+		if pq.queue.Len() == 0 {
+			sq.lock.Lock()
+			if pq.dropping {
+				pq.dropping = false
+				sq.log.Info("Send queue of %s has drained", ip)
+			}
+			sq.lock.Unlock()
+		}
+	}
+}
+
+// write [cMsg] to the connection of the peer at [addr], waiting for its write
+// buffer to take the message. Returns false if the buffer didn't take it
+// within the timeout, or if [pq] was closed.
+func (sq *sendQueues) write(pq *peerQueue, cMsg salticidae.Msg, addr salticidae.NetAddr) bool {
+	deadline := time.Now().Add(sq.timeout)
+	for !sq.net.SendMsg(cMsg, addr) {
+		if pq.queue.Closed() || !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(sq.retryDelay)
+	}
+	return true
+}
+
+// remove the send queue of the peer at [ip], dropping the messages queued for
+// it
+func (sq *sendQueues) remove(ip utils.IPDesc) {
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+
+	key := ip.String()
+	if pq, exists := sq.queues[key]; exists {
+		pq.queue.Close()
+		delete(sq.queues, key)
+	}
+}
+
+// Shutdown closes every send queue
+func (sq *sendQueues) Shutdown() {
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+
+	for key, pq := range sq.queues {
+		pq.queue.Close()
+		delete(sq.queues, key)
+	}
+}
+
+// priority returns the priority of the messages with opcode [op]
+func priority(op salticidae.Opcode) sendqueue.Priority {
+	switch op {
+	case GetVersion, Version, Ping, Pong, Get, Put, PushQuery, PullQuery, Chits:
+		return sendqueue.High
+	case GetAcceptedFrontier, AcceptedFrontier, GetAccepted, Accepted, GetAncestors, MultiPut:
+		return sendqueue.Medium
+	default:
+		return sendqueue.Low
+	}
+}
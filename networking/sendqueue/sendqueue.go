@@ -0,0 +1,204 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sendqueue
+
+import (
+	"sync"
+)
+
+// Priority is the class of a queued message. When a queue is full, messages
+// of a lower priority are dropped first.
+type Priority int
+
+// Priorities of queued messages, from lowest to highest
+const (
+	Low    Priority = iota // Gossip, which is resent later anyway
+	Medium                 // Bootstrapping requests and their responses
+	High                   // Consensus queries, votes and handshake messages
+
+	numPriorities = iota
+)
+
+func (p Priority) String() string {
+	switch p {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	case High:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// Queue is a bounded queue of the messages waiting to be sent to one peer.
+// Messages are popped highest priority first. Within a priority, each stream,
+// such as the messages of one chain, is served in turn, and each stream's
+// messages are popped in the order they were pushed. A chain sending many
+// messages, such as one serving a peer that's bootstrapping, only delays its
+// own messages. When the queue is full, the oldest message of the longest
+// stream of the lowest priority is dropped, so that a peer that doesn't keep up
+// can't use an unbounded amount of memory.
+type Queue struct {
+	lock   sync.Mutex
+	cond   *sync.Cond
+	closed bool
+
+	size, capacity int
+	msgs           [numPriorities]streams
+}
+
+// streams are the messages of one priority
+type streams struct {
+	streams map[[32]byte]*stream // The streams with messages queued
+	ready   []*stream            // The streams with messages queued, in the order they're served
+}
+
+// stream of messages with one priority
+type stream struct {
+	key  [32]byte
+	msgs []interface{} // Oldest first
+}
+
+// New returns a queue that holds at most [capacity] messages
+func New(capacity int) *Queue {
+	q := &Queue{capacity: capacity}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Push [msg] onto the stream [key] of the queue with priority [p]. If the
+// queue is full, the oldest message of the longest stream with the lowest
+// priority, no higher than [p], is dropped to make room for [msg]. If every
+// queued message has a higher priority than [p], [msg] is dropped instead.
+// Returns the priority of the dropped message, and true if a message was
+// dropped. Messages pushed onto a closed queue are dropped.
+func (q *Queue) Push(msg interface{}, p Priority, key [32]byte) (Priority, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return p, true
+	}
+
+	if q.size >= q.capacity {
+		dropped := Low
+		for dropped < p && len(q.msgs[dropped].ready) == 0 {
+			dropped++
+		}
+		if len(q.msgs[dropped].ready) == 0 {
+			return p, true
+		}
+		q.msgs[dropped].dropLongest()
+		q.msgs[p].push(msg, key)
+		return dropped, true
+	}
+
+	q.msgs[p].push(msg, key)
+	q.size++
+	q.cond.Signal()
+	return p, false
+}
+
+// Pop the next message to send, blocking until there is one. Returns false
+// once the queue is closed.
+func (q *Queue) Pop() (interface{}, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.size == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+
+	for p := Priority(numPriorities - 1); p >= Low; p-- {
+		if len(q.msgs[p].ready) > 0 {
+			q.size--
+			return q.msgs[p].pop(), true
+		}
+	}
+	return nil, false
+}
+
+// Len returns the number of messages in the queue
+func (q *Queue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.size
+}
+
+// Closed returns true if the queue was closed
+func (q *Queue) Closed() bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.closed
+}
+
+// Close the queue. The queued messages are dropped, and Pop returns
+// immediately.
+func (q *Queue) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.closed = true
+	q.size = 0
+	q.msgs = [numPriorities]streams{}
+	q.cond.Broadcast()
+}
+
+// push [msg] onto the stream [key]
+func (s *streams) push(msg interface{}, key [32]byte) {
+	st, exists := s.streams[key]
+	if !exists {
+		if s.streams == nil {
+			s.streams = make(map[[32]byte]*stream)
+		}
+		st = &stream{key: key}
+		s.streams[key] = st
+		s.ready = append(s.ready, st)
+	}
+	st.msgs = append(st.msgs, msg)
+}
+
+// pop the oldest message of the next stream to be served. Assumes there is a
+// stream with messages queued.
+func (s *streams) pop() interface{} {
+	st := s.ready[0]
+	s.ready[0] = nil
+	s.ready = s.ready[1:]
+	msg := st.msgs[0]
+	st.msgs[0] = nil // Allow the message to be garbage collected
+	st.msgs = st.msgs[1:]
+	if len(st.msgs) != 0 {
+		s.ready = append(s.ready, st)
+	} else {
+		delete(s.streams, st.key)
+	}
+	return msg
+}
+
+// dropLongest drops the oldest message of the stream with the most messages
+// queued. Assumes there is a stream with messages queued.
+func (s *streams) dropLongest() {
+	longest := 0
+	for i, st := range s.ready {
+		if len(st.msgs) > len(s.ready[longest].msgs) {
+			longest = i
+		}
+	}
+	st := s.ready[longest]
+	st.msgs[0] = nil // Allow the message to be garbage collected
+	st.msgs = st.msgs[1:]
+	if len(st.msgs) == 0 {
+		copy(s.ready[longest:], s.ready[longest+1:])
+		s.ready[len(s.ready)-1] = nil
+		s.ready = s.ready[:len(s.ready)-1]
+		delete(s.streams, st.key)
+	}
+}
@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sendqueue
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	chain0 = [32]byte{}
+	chain1 = [32]byte{1}
+)
+
+func TestQueuePriorities(t *testing.T) {
+	q := New(10)
+	q.Push(0, Low, chain0)
+	q.Push(1, High, chain0)
+	q.Push(2, Medium, chain0)
+	q.Push(3, High, chain0)
+
+	for _, expected := range []int{1, 3, 2, 0} {
+		msg, ok := q.Pop()
+		if !ok {
+			t.Fatalf("expected to pop %d", expected)
+		}
+		if msg != expected {
+			t.Fatalf("expected to pop %d but popped %v", expected, msg)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected the queue to be empty but it has %d messages", q.Len())
+	}
+}
+
+func TestQueueDropsOldestLowestPriority(t *testing.T) {
+	q := New(3)
+	q.Push(0, Medium, chain0)
+	q.Push(1, Low, chain0)
+	q.Push(2, Low, chain0)
+
+	// The oldest low priority message makes room for a high priority message
+	if p, dropped := q.Push(3, High, chain0); !dropped || p != Low {
+		t.Fatalf("expected a %s priority message to be dropped but dropped = %v, priority = %s", Low, dropped, p)
+	}
+	// A low priority message can only replace another low priority message
+	if p, dropped := q.Push(4, Low, chain0); !dropped || p != Low {
+		t.Fatalf("expected a %s priority message to be dropped but dropped = %v, priority = %s", Low, dropped, p)
+	}
+	if q.Len() != 3 {
+		t.Fatalf("expected the queue to hold 3 messages but it has %d", q.Len())
+	}
+
+	for _, expected := range []int{3, 0, 4} {
+		if msg, _ := q.Pop(); msg != expected {
+			t.Fatalf("expected to pop %d but popped %v", expected, msg)
+		}
+	}
+}
+
+func TestQueueDropsNewMessage(t *testing.T) {
+	q := New(1)
+	q.Push(0, High, chain0)
+
+	// Every queued message has a higher priority, so the new one is dropped
+	if p, dropped := q.Push(1, Medium, chain0); !dropped || p != Medium {
+		t.Fatalf("expected the new message to be dropped but dropped = %v, priority = %s", dropped, p)
+	}
+	if msg, _ := q.Pop(); msg != 0 {
+		t.Fatalf("expected to pop 0 but popped %v", msg)
+	}
+}
+
+func TestQueueClose(t *testing.T) {
+	q := New(1)
+	q.Push(0, High, chain0)
+
+	popped := make(chan bool)
+	go func() {
+		q.Pop()
+		_, ok := q.Pop()
+		popped <- ok
+	}()
+
+	select {
+	case <-popped:
+		t.Fatal("Pop shouldn't return before there is a message")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if q.Closed() {
+		t.Fatal("the queue shouldn't be closed yet")
+	}
+	q.Close()
+	if !q.Closed() {
+		t.Fatal("the queue should be closed")
+	}
+	if ok := <-popped; ok {
+		t.Fatal("Pop shouldn't return a message once the queue is closed")
+	}
+	if _, dropped := q.Push(1, High, chain0); !dropped {
+		t.Fatal("a message pushed onto a closed queue should be dropped")
+	}
+}
+
+func TestQueueServesStreamsInTurn(t *testing.T) {
+	q := New(10)
+	q.Push(0, Medium, chain0)
+	q.Push(1, Medium, chain0)
+	q.Push(2, Medium, chain0)
+	q.Push(3, Medium, chain1)
+	q.Push(4, High, chain0)
+	q.Push(5, Medium, chain1)
+
+	// The messages of one chain don't hold up the messages of another chain
+	// with the same priority
+	for _, expected := range []int{4, 0, 3, 1, 5, 2} {
+		if msg, _ := q.Pop(); msg != expected {
+			t.Fatalf("expected to pop %d but popped %v", expected, msg)
+		}
+	}
+}
+
+func TestQueueDropsFromLongestStream(t *testing.T) {
+	q := New(4)
+	q.Push(0, Medium, chain1)
+	q.Push(1, Medium, chain0)
+	q.Push(2, Medium, chain0)
+	q.Push(3, Medium, chain0)
+
+	// The chain with the most messages queued loses its oldest message
+	if p, dropped := q.Push(4, Medium, chain1); !dropped || p != Medium {
+		t.Fatalf("expected a %s priority message to be dropped but dropped = %v, priority = %s", Medium, dropped, p)
+	}
+	for _, expected := range []int{0, 2, 4, 3} {
+		if msg, _ := q.Pop(); msg != expected {
+			t.Fatalf("expected to pop %d but popped %v", expected, msg)
+		}
+	}
+}
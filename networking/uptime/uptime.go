@@ -0,0 +1,169 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// The most past connections remembered per peer. When a peer has reconnected
+// more often, its oldest connections are forgotten, so it's only credited
+// with its more recent uptime.
+const maxConnections = 1024
+
+// connection is a period this node was connected to a peer
+type connection struct{ start, end time.Time }
+
+type peer struct {
+	connected   bool
+	connectedAt time.Time // Start of the current connection
+	lastSeen    time.Time // Last time a message was received from the peer
+
+	past []connection // Oldest first
+}
+
+// Tracker tracks when this node was connected to its peers, and when it last
+// heard from them
+type Tracker struct {
+	lock  sync.Mutex
+	clock timer.Clock
+	myID  ids.ShortID
+	start time.Time // When this node started tracking its peers
+
+	peers map[[20]byte]*peer
+}
+
+// Initialize this tracker. [myID] is the ID of this node, which is always
+// considered connected.
+func (t *Tracker) Initialize(myID ids.ShortID) {
+	t.myID = myID
+	t.start = t.clock.Time()
+	t.peers = make(map[[20]byte]*peer)
+}
+
+// Connected records that this node connected to [peerID]
+func (t *Tracker) Connected(peerID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p := t.peer(peerID)
+	if p.connected {
+		return
+	}
+	now := t.clock.Time()
+	p.connected = true
+	p.connectedAt = now
+	p.lastSeen = now
+}
+
+// Disconnected records that this node disconnected from [peerID]
+func (t *Tracker) Disconnected(peerID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, exists := t.peers[peerID.Key()]
+	if !exists || !p.connected {
+		return
+	}
+	p.connected = false
+	if len(p.past) == maxConnections {
+		copy(p.past, p.past[1:])
+		p.past = p.past[:maxConnections-1]
+	}
+	p.past = append(p.past, connection{
+		start: p.connectedAt,
+		end:   t.clock.Time(),
+	})
+}
+
+// Heard records that this node received a message from [peerID]
+func (t *Tracker) Heard(peerID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if p, exists := t.peers[peerID.Key()]; exists {
+		p.lastSeen = t.clock.Time()
+	}
+}
+
+// Status returns whether this node is connected to [peerID], and the last
+// time it received a message from [peerID]. The time is zero if this node
+// never has.
+func (t *Tracker) Status(peerID ids.ShortID) (bool, time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if peerID.Equals(t.myID) {
+		return true, t.clock.Time()
+	}
+	p, exists := t.peers[peerID.Key()]
+	if !exists {
+		return false, time.Time{}
+	}
+	return p.connected, p.lastSeen
+}
+
+// Uptime returns the fraction of the time since [since] that this node was
+// connected to [peerID], and how long that time is. Time before this node
+// started tracking its peers isn't counted.
+func (t *Tracker) Uptime(peerID ids.ShortID, since time.Time) (float64, time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	start := since
+	if start.Before(t.start) {
+		start = t.start
+	}
+	now := t.clock.Time()
+	if !now.After(start) {
+		return 1, 0
+	}
+	observed := now.Sub(start)
+
+	if peerID.Equals(t.myID) {
+		return 1, observed
+	}
+	p, exists := t.peers[peerID.Key()]
+	if !exists {
+		return 0, observed
+	}
+	up := time.Duration(0)
+	for _, c := range p.past {
+		up += overlap(c, start, now)
+	}
+	if p.connected {
+		up += overlap(connection{start: p.connectedAt, end: now}, start, now)
+	}
+	return float64(up) / float64(observed), observed
+}
+
+// peer returns the state of [peerID], which is created if it doesn't exist.
+// Assumes [t.lock] is held.
+func (t *Tracker) peer(peerID ids.ShortID) *peer {
+	key := peerID.Key()
+	p, exists := t.peers[key]
+	if !exists {
+		p = &peer{}
+		t.peers[key] = p
+	}
+	return p
+}
+
+// overlap returns how much of [c] was between [start] and [end]
+func overlap(c connection, start, end time.Time) time.Duration {
+	if c.start.After(start) {
+		start = c.start
+	}
+	if c.end.Before(end) {
+		end = c.end
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestTracker(t *testing.T) {
+	myID := ids.NewShortID([20]byte{1})
+	peerID := ids.NewShortID([20]byte{2})
+	unknownID := ids.NewShortID([20]byte{3})
+
+	start := time.Unix(1000, 0)
+	tracker := Tracker{}
+	tracker.clock.Set(start)
+	tracker.Initialize(myID)
+
+	if connected, _ := tracker.Status(peerID); connected {
+		t.Fatal("shouldn't be connected to a peer that never connected")
+	}
+
+	tracker.clock.Set(start.Add(10 * time.Second))
+	tracker.Connected(peerID)
+	tracker.clock.Set(start.Add(20 * time.Second))
+	tracker.Heard(peerID)
+	tracker.clock.Set(start.Add(30 * time.Second))
+	tracker.Disconnected(peerID)
+
+	connected, lastSeen := tracker.Status(peerID)
+	if connected {
+		t.Fatal("shouldn't be connected to a peer that disconnected")
+	}
+	if expected := start.Add(20 * time.Second); !lastSeen.Equal(expected) {
+		t.Fatalf("expected the peer to be last seen at %s but was %s", expected, lastSeen)
+	}
+
+	tracker.clock.Set(start.Add(40 * time.Second))
+	tracker.Connected(peerID)
+	if connected, _ := tracker.Status(peerID); !connected {
+		t.Fatal("should be connected to the peer")
+	}
+
+	// Connected from 10 to 30 and from 40 to 50
+	tracker.clock.Set(start.Add(50 * time.Second))
+	if uptime, observed := tracker.Uptime(peerID, time.Time{}); uptime != 0.6 || observed != 50*time.Second {
+		t.Fatalf("expected an uptime of %f over %s but got %f over %s", 0.6, 50*time.Second, uptime, observed)
+	}
+	if uptime, observed := tracker.Uptime(peerID, start.Add(25*time.Second)); uptime != 0.6 || observed != 25*time.Second {
+		t.Fatalf("expected an uptime of %f over %s but got %f over %s", 0.6, 25*time.Second, uptime, observed)
+	}
+	if uptime, _ := tracker.Uptime(unknownID, time.Time{}); uptime != 0 {
+		t.Fatalf("expected no uptime for an unknown peer but got %f", uptime)
+	}
+	if uptime, _ := tracker.Uptime(myID, time.Time{}); uptime != 1 {
+		t.Fatalf("this node should always be up but got %f", uptime)
+	}
+}
@@ -0,0 +1,187 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package versions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	errInvalidVersion = errors.New("version must be of the form app/major.minor.patch")
+)
+
+// Version is a parsed version string, such as "avalanche/0.0.1"
+type Version struct {
+	App                 string
+	Major, Minor, Patch int
+}
+
+// Parse [version], which must be of the form app/major.minor.patch
+func Parse(version string) (Version, error) {
+	parts := strings.SplitN(version, "/", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return Version{}, fmt.Errorf("%w: %q", errInvalidVersion, version)
+	}
+	numbers := strings.Split(parts[1], ".")
+	if len(numbers) != 3 {
+		return Version{}, fmt.Errorf("%w: %q", errInvalidVersion, version)
+	}
+	parsed := [3]int{}
+	for i, number := range numbers {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("%w: %q", errInvalidVersion, version)
+		}
+		parsed[i] = n
+	}
+	return Version{
+		App:   parts[0],
+		Major: parsed[0],
+		Minor: parsed[1],
+		Patch: parsed[2],
+	}, nil
+}
+
+// Before returns true if [v] is an earlier version of the same app as [o]
+func (v Version) Before(o Version) bool {
+	switch {
+	case v.App != o.App:
+		return false
+	case v.Major != o.Major:
+		return v.Major < o.Major
+	case v.Minor != o.Minor:
+		return v.Minor < o.Minor
+	default:
+		return v.Patch < o.Patch
+	}
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s/%d.%d.%d", v.App, v.Major, v.Minor, v.Patch)
+}
+
+// Summary is the number of connected peers running a version, and the stake
+// of the validators among them
+type Summary struct {
+	Version  string      `json:"version"`
+	NumPeers json.Uint32 `json:"numPeers"`
+	Stake    json.Uint64 `json:"stake"`
+}
+
+// Tracker tracks the versions that connected peers reported in their
+// handshakes
+type Tracker struct {
+	lock      sync.Mutex
+	vdrs      validators.Set
+	myID      ids.ShortID
+	myVersion string
+
+	versions map[[20]byte]string // Peer -> Version it reported
+}
+
+// Initialize this tracker. [vdrs] is used to weigh the peers by stake.
+// [myID] and [myVersion] identify this node and the version it's running.
+func (t *Tracker) Initialize(vdrs validators.Set, myID ids.ShortID, myVersion string) {
+	t.vdrs = vdrs
+	t.myID = myID
+	t.myVersion = myVersion
+	t.versions = make(map[[20]byte]string)
+}
+
+// Version returns the version this node is running
+func (t *Tracker) Version() string { return t.myVersion }
+
+// Connected records that [peerID] is running [version]
+func (t *Tracker) Connected(peerID ids.ShortID, version string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.versions[peerID.Key()] = version
+}
+
+// Disconnected forgets the version of [peerID]. This should be called when the
+// peer disconnects.
+func (t *Tracker) Disconnected(peerID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.versions, peerID.Key())
+}
+
+// Summary returns the versions run by connected peers and by this node, sorted
+// by decreasing stake
+func (t *Tracker) Summary() []Summary {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stakes := t.stakes()
+	summaries := make(map[string]*Summary)
+	add := func(version string, stake uint64) {
+		s, exists := summaries[version]
+		if !exists {
+			s = &Summary{Version: version}
+			summaries[version] = s
+		}
+		s.NumPeers++
+		s.Stake += json.Uint64(stake)
+	}
+	for key, version := range t.versions {
+		add(version, stakes[key])
+	}
+	add(t.myVersion, stakes[t.myID.Key()])
+
+	list := make([]Summary, 0, len(summaries))
+	for _, s := range summaries {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Stake != list[j].Stake {
+			return list[i].Stake > list[j].Stake
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
+
+// Behind returns true if validators with a majority of the stake are running
+// a later version than this node
+func (t *Tracker) Behind() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	myVersion, err := Parse(t.myVersion)
+	if err != nil {
+		return false
+	}
+
+	totalStake, laterStake := uint64(0), uint64(0)
+	stakes := t.stakes()
+	for _, stake := range stakes {
+		totalStake += stake
+	}
+	for key, version := range t.versions {
+		if peerVersion, err := Parse(version); err == nil && myVersion.Before(peerVersion) {
+			laterStake += stakes[key]
+		}
+	}
+	return laterStake > totalStake/2
+}
+
+// stakes returns the stake of each validator
+func (t *Tracker) stakes() map[[20]byte]uint64 {
+	stakes := make(map[[20]byte]uint64)
+	for _, vdr := range t.vdrs.List() {
+		stakes[vdr.ID().Key()] += vdr.Weight()
+	}
+	return stakes
+}
@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package versions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("avalanche/1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Version{App: "avalanche", Major: 1, Minor: 2, Patch: 3}); v != expected {
+		t.Fatalf("expected %s but got %s", expected, v)
+	}
+
+	for _, invalid := range []string{"", "avalanche", "avalanche/1.2", "/1.2.3", "avalanche/1.2.x", "avalanche/1.-2.3"} {
+		if _, err := Parse(invalid); !errors.Is(err, errInvalidVersion) {
+			t.Fatalf("should have failed to parse %q", invalid)
+		}
+	}
+
+	if !v.Before(Version{App: "avalanche", Major: 1, Minor: 3}) {
+		t.Fatal("1.2.3 should be before 1.3.0")
+	}
+	if v.Before(Version{App: "avalanche", Major: 1, Minor: 2, Patch: 3}) {
+		t.Fatal("a version shouldn't be before itself")
+	}
+	if v.Before(Version{App: "other", Major: 2}) {
+		t.Fatal("versions of different apps shouldn't be ordered")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	myID := ids.NewShortID([20]byte{1})
+	peer0 := ids.NewShortID([20]byte{2})
+	peer1 := ids.NewShortID([20]byte{3})
+	nonValidator := ids.NewShortID([20]byte{4})
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(myID, 2))
+	vdrs.Add(validators.NewValidator(peer0, 2))
+	vdrs.Add(validators.NewValidator(peer1, 1))
+
+	tracker := Tracker{}
+	tracker.Initialize(vdrs, myID, "avalanche/0.0.1")
+
+	tracker.Connected(peer0, "avalanche/0.1.0")
+	tracker.Connected(nonValidator, "avalanche/0.1.0")
+	tracker.Connected(peer1, "avalanche/0.0.1")
+
+	summary := tracker.Summary()
+	if len(summary) != 2 {
+		t.Fatalf("expected %d versions but got %d", 2, len(summary))
+	}
+	if s := summary[0]; s.Version != "avalanche/0.0.1" || s.NumPeers != 2 || s.Stake != 3 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if s := summary[1]; s.Version != "avalanche/0.1.0" || s.NumPeers != 2 || s.Stake != 2 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+
+	// 2 of the 5 staked tokens run a later version
+	if tracker.Behind() {
+		t.Fatal("shouldn't be behind without a stake majority")
+	}
+
+	tracker.Connected(peer1, "avalanche/0.1.0")
+	if !tracker.Behind() {
+		t.Fatal("should be behind a stake majority")
+	}
+
+	tracker.Disconnected(peer0)
+	if tracker.Behind() {
+		t.Fatal("shouldn't count the stake of disconnected peers")
+	}
+}
@@ -10,6 +10,8 @@ package networking
 // void accepted(msg_t *, msgnetwork_conn_t *, void *);
 // void get(msg_t *, msgnetwork_conn_t *, void *);
 // void put(msg_t *, msgnetwork_conn_t *, void *);
+// void getAncestors(msg_t *, msgnetwork_conn_t *, void *);
+// void multiPut(msg_t *, msgnetwork_conn_t *, void *);
 // void pushQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void pullQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void chits(msg_t *, msgnetwork_conn_t *, void *);
@@ -25,6 +27,7 @@ import (
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/sendqueue"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -47,23 +50,26 @@ type Voting struct {
 
 	log   logging.Logger
 	vdrs  validators.Set
-	net   salticidae.PeerNetwork
-	conns Connections
+	net    salticidae.PeerNetwork
+	conns  Connections
+	queues SendQueues
 
 	router   router.Router
 	executor timer.Executor
 }
 
 // Initialize to the c networking library. Should only be called once ever.
-func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet salticidae.PeerNetwork, conns Connections, router router.Router, registerer prometheus.Registerer) {
+func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet salticidae.PeerNetwork, conns Connections, queues SendQueues, router router.Router, registerer prometheus.Registerer) {
 	log.AssertTrue(s.net == nil, "Should only register network handlers once")
 	log.AssertTrue(s.conns == nil, "Should only set connections once")
+	log.AssertTrue(s.queues == nil, "Should only set the send queues once")
 	log.AssertTrue(s.router == nil, "Should only set the router once")
 
 	s.log = log
 	s.vdrs = vdrs
 	s.net = peerNet
 	s.conns = conns
+	s.queues = queues
 	s.router = router
 
 	s.votingMetrics.Initialize(log, registerer)
@@ -76,6 +82,8 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 	net.RegHandler(Accepted, salticidae.MsgNetworkMsgCallback(C.accepted), nil)
 	net.RegHandler(Get, salticidae.MsgNetworkMsgCallback(C.get), nil)
 	net.RegHandler(Put, salticidae.MsgNetworkMsgCallback(C.put), nil)
+	net.RegHandler(GetAncestors, salticidae.MsgNetworkMsgCallback(C.getAncestors), nil)
+	net.RegHandler(MultiPut, salticidae.MsgNetworkMsgCallback(C.multiPut), nil)
 	net.RegHandler(PushQuery, salticidae.MsgNetworkMsgCallback(C.pushQuery), nil)
 	net.RegHandler(PullQuery, salticidae.MsgNetworkMsgCallback(C.pullQuery), nil)
 	net.RegHandler(Chits, salticidae.MsgNetworkMsgCallback(C.chits), nil)
@@ -114,7 +122,9 @@ func (s *Voting) Accept(chainID, containerID ids.ID, container []byte) error {
 		containerID,
 		formatting.DumpBytes{Bytes: container},
 	)
-	s.send(msg, addrs...)
+	// Gossip is dropped first when a peer falls behind, as it's sent again
+	// after later decisions
+	s.queues.Send(msg, sendqueue.Low, chainID, addrs...)
 	s.numPutSent.Add(float64(len(addrs)))
 	return nil
 }
@@ -146,7 +156,7 @@ func (s *Voting) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID,
 		chainID,
 		requestID,
 	)
-	s.send(msg, addrs...)
+	s.send(msg, chainID, addrs...)
 	s.numGetAcceptedFrontierSent.Add(float64(len(addrs)))
 }
 
@@ -177,7 +187,7 @@ func (s *Voting) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, reque
 		requestID,
 		containerIDs,
 	)
-	s.send(msg, addr)
+	s.send(msg, chainID, addr)
 	s.numAcceptedFrontierSent.Inc()
 }
 
@@ -218,7 +228,7 @@ func (s *Voting) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestI
 		requestID,
 		containerIDs,
 	)
-	s.send(msg, addrs...)
+	s.send(msg, chainID, addrs...)
 	s.numGetAcceptedSent.Add(float64(len(addrs)))
 }
 
@@ -249,7 +259,7 @@ func (s *Voting) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uin
 		requestID,
 		containerIDs,
 	)
-	s.send(msg, addr)
+	s.send(msg, chainID, addr)
 	s.numAcceptedSent.Inc()
 }
 
@@ -278,7 +288,7 @@ func (s *Voting) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 		requestID,
 		containerID,
 	)
-	s.send(msg, addr)
+	s.send(msg, chainID, addr)
 	s.numGetSent.Inc()
 }
 
@@ -311,10 +321,70 @@ func (s *Voting) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 		containerID,
 		formatting.DumpBytes{Bytes: container},
 	)
-	s.send(msg, addr)
+	s.send(msg, chainID, addr)
 	s.numPutSent.Inc()
 }
 
+// GetAncestors implements the Sender interface.
+func (s *Voting) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a GetAncestors message to a disconnected validator: %s", validatorID)
+		s.executor.Add(func() { s.router.GetAncestorsFailed(validatorID, chainID, requestID) })
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.GetAncestors(chainID, requestID, containerID)
+	s.log.AssertNoError(err)
+
+	s.log.Verbo("Sending a GetAncestors message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nContainer ID: %s",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		containerID,
+	)
+	s.send(msg, chainID, addr)
+	s.numGetAncestorsSent.Inc()
+}
+
+// MultiPut implements the Sender interface.
+func (s *Voting) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a MultiPut message to a disconnected validator: %s", validatorID)
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.MultiPut(chainID, requestID, containers)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a MultiPut message.\nNumber of containers: %d", len(containers))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a MultiPut message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nNumber of containers: %d",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		len(containers),
+	)
+	s.send(msg, chainID, addr)
+	s.numMultiPutSent.Inc()
+}
+
 // PushQuery implements the Sender interface.
 func (s *Voting) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
 	addrs := []salticidae.NetAddr(nil)
@@ -354,7 +424,7 @@ func (s *Voting) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 		containerID,
 		formatting.DumpBytes{Bytes: container},
 	)
-	s.send(msg, addrs...)
+	s.send(msg, chainID, addrs...)
 	s.numPushQuerySent.Add(float64(len(addrs)))
 }
 
@@ -387,7 +457,7 @@ func (s *Voting) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 		requestID,
 		containerID,
 	)
-	s.send(msg, addrs...)
+	s.send(msg, chainID, addrs...)
 	s.numPullQuerySent.Add(float64(len(addrs)))
 }
 
@@ -418,25 +488,12 @@ func (s *Voting) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32
 		requestID,
 		votes.Len(),
 	)
-	s.send(msg, addr)
+	s.send(msg, chainID, addr)
 	s.numChitsSent.Inc()
 }
 
-func (s *Voting) send(msg Msg, addrs ...salticidae.NetAddr) {
-	ds := msg.DataStream()
-	defer ds.Free()
-	ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
-	defer ba.Free()
-	cMsg := salticidae.NewMsgMovedFromByteArray(msg.Op(), ba, false)
-	defer cMsg.Free()
-
-	switch len(addrs) {
-	case 0:
-	case 1:
-		s.net.SendMsg(cMsg, addrs[0])
-	default:
-		s.net.MulticastMsgByMove(cMsg, addrs)
-	}
+func (s *Voting) send(msg Msg, chainID ids.ID, addrs ...salticidae.NetAddr) {
+	s.queues.Send(msg, priority(msg.Op()), chainID, addrs...)
 }
 
 // getAcceptedFrontier handles the recept of a getAcceptedFrontier container
@@ -560,6 +617,39 @@ func put(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Point
 	VotingNet.router.Put(validatorID, chainID, requestID, containerID, containerBytes)
 }
 
+// getAncestors handles the receipt of a request for a container and its
+// ancestors
+//export getAncestors
+func getAncestors(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGetAncestorsReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, GetAncestors)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	containerID, _ := ids.ToID(msg.Get(ContainerID).([]byte))
+
+	VotingNet.router.GetAncestors(validatorID, chainID, requestID, containerID)
+}
+
+// multiPut handles the receipt of a container and its ancestors
+//export multiPut
+func multiPut(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numMultiPutReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, MultiPut)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	containers := msg.Get(MultiContainerBytes).([][]byte)
+
+	VotingNet.router.MultiPut(validatorID, chainID, requestID, containers)
+}
+
 // pushQuery handles the recept of a pull query message
 //export pushQuery
 func pushQuery(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
@@ -631,6 +721,7 @@ func (s *Voting) sanitize(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_
 	if !exists {
 		return ids.ShortID{}, ids.ID{}, 0, nil, fmt.Errorf("message received from an un-registered source: %s", toIPDesc(addr))
 	}
+	HandshakeNet.uptimes.Heard(validatorID)
 
 	msg := salticidae.MsgFromC(salticidae.CMsg(_msg))
 	codec := Codec{}
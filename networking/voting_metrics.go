@@ -16,6 +16,8 @@ type votingMetrics struct {
 	numAcceptedSent, numAcceptedReceived,
 	numGetSent, numGetReceived,
 	numPutSent, numPutReceived,
+	numGetAncestorsSent, numGetAncestorsReceived,
+	numMultiPutSent, numMultiPutReceived,
 	numPushQuerySent, numPushQueryReceived,
 	numPullQuerySent, numPullQueryReceived,
 	numChitsSent, numChitsReceived prometheus.Counter
@@ -94,6 +96,30 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 			Name:      "put_received",
 			Help:      "Number of put messages received",
 		})
+	vm.numGetAncestorsSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_ancestors_sent",
+			Help:      "Number of get ancestors messages sent",
+		})
+	vm.numGetAncestorsReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_ancestors_received",
+			Help:      "Number of get ancestors messages received",
+		})
+	vm.numMultiPutSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "multi_put_sent",
+			Help:      "Number of multi put messages sent",
+		})
+	vm.numMultiPutReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "multi_put_received",
+			Help:      "Number of multi put messages received",
+		})
 	vm.numPushQuerySent = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "gecko",
@@ -167,6 +193,18 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 	if err := registerer.Register(vm.numPutReceived); err != nil {
 		log.Error("Failed to register put_received statistics due to %s", err)
 	}
+	if err := registerer.Register(vm.numGetAncestorsSent); err != nil {
+		log.Error("Failed to register get_ancestors_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetAncestorsReceived); err != nil {
+		log.Error("Failed to register get_ancestors_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numMultiPutSent); err != nil {
+		log.Error("Failed to register multi_put_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numMultiPutReceived); err != nil {
+		log.Error("Failed to register multi_put_received statistics due to %s", err)
+	}
 	if err := registerer.Register(vm.numPushQuerySent); err != nil {
 		log.Error("Failed to register push_query_sent statistics due to %s", err)
 	}
@@ -4,13 +4,22 @@
 package node
 
 import (
+	"flag"
+	"time"
+
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/api/faucet"
+	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Config contains all of the configurations of an Ava node.
@@ -24,6 +33,50 @@ type Config struct {
 	// Transaction fee configuration
 	AvaTxFee uint64
 
+	// Transaction re-gossip configuration
+	TxRegossipFrequency    time.Duration
+	TxMaxRegossipFrequency time.Duration
+
+	// AVM transaction batching configuration
+	AVMBatchSize    int
+	AVMBatchTimeout time.Duration
+
+	// AVM transaction fee configuration
+	AVMFeeAsset string
+	AVMMinFee   uint64
+
+	// Hosts that address watches may send callbacks to. If empty, callbacks may
+	// be sent to any public address.
+	WatchAllowedHosts []string
+
+	// Fraction of time each chain, other than the P-Chain, may spend
+	// processing messages. Non-positive disables throttling.
+	ChainCPUBudget float64
+
+	// Bytes of requests from peers each throttled chain may hold back. 0 means
+	// only the number of requests is limited.
+	ChainMemoryBudget uint64
+
+	// Maximum sizes of the blocks, vertices and transactions chains issue and
+	// accept
+	ContainerLimits snow.Limits
+
+	// How bootstrapped chains compare their accepted frontier with their
+	// validators'
+	FrontierMonitor common.FrontierMonitorConfig
+
+	// How long chains remember the decisions they accepted, so they can be
+	// queried over the admin API. 0 disables remembering them.
+	AcceptanceWindow time.Duration
+
+	// Where chain snapshots are imported from, when a chain is fresh, and
+	// exported to over the admin API. "" disables snapshots.
+	SnapshotDir string
+
+	// Where the configurations of chains, <chain ID or alias>.json, are read
+	// from. "" disables chain configurations.
+	ChainConfigDir string
+
 	// Assertions configuration
 	EnableAssertions bool
 
@@ -33,25 +86,114 @@ type Config struct {
 	// Database to use for the node
 	DB database.Database
 
+	// If true, the database is compacted every day at [DBCompactionTime] after
+	// midnight UTC
+	DBCompactionEnabled bool
+	DBCompactionTime    time.Duration
+
+	// If true, a database created for a different network, genesis or schema
+	// is emptied, rather than the node refusing to run against it
+	ForceReset bool
+
+	// Rebuild the optional indexes from the chains' state on startup
+	Reindex bool
+
+	// Chain time limits enforced by the platform chain. 0 uses the default.
+	MinStartTimeLead time.Duration
+
+	// How long after this node's time a validator must start for the platform
+	// API to build the transaction adding it. 0 uses the default.
+	StartTimeMargin time.Duration
+
+	// Minimum time between two proposals to advance the platform chain's time
+	AdvanceTimePacing time.Duration
+
+	// Clock the chains run by. It runs faster than real time on test networks
+	// that accelerate time.
+	Clock timer.Clock
+
+	// Seeds the sources of randomness the chains' consensus samples from, so
+	// sampling can be reproduced
+	ConsensusSeed int64
+
 	// Staking configuration
 	StakingIP       utils.IPDesc
 	EnableStaking   bool
 	StakingKeyFile  string
 	StakingCertFile string
 
+	// Port of the UDP liveness probe responder. 0 disables it.
+	ProbePort uint16
+
+	// How often to check whether a stake majority runs a later version than
+	// this node. 0 disables the check.
+	VersionCheckFrequency time.Duration
+
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
 
 	// HTTP configuration
+	HTTPHost      string
 	HTTPPort      uint16
 	EnableHTTPS   bool
 	HTTPSKeyFile  string
 	HTTPSCertFile string
 
+	// APIs that aren't served on [HTTPHost]:[HTTPPort]
+	HTTPDisabledAPIs []string
+
+	// If true, requests on [HTTPHost]:[HTTPPort] must carry an API key
+	HTTPRequireAPIKey bool
+
+	// Additional addresses the HTTP server listens on, each of which may
+	// disable other APIs
+	HTTPListeners []api.Listener
+
+	// Tunes the connections the HTTP server accepts, such as whether they may
+	// use HTTP/2
+	HTTPConfig api.HTTPConfig
+
+	// The responses to calls of [APICachedMethods] are served from a cache
+	// for up to [APICacheTTL]. If it's 0, no response is cached.
+	APICacheTTL      time.Duration
+	APICachedMethods []string
+
+	// The node's health is checked every [HealthCheckFrequency]. While it's
+	// unhealthy, calls of [APIShedMethods] are refused, and callers are asked
+	// to retry after [APIShedRetryAfter]. If it's 0, no call is refused.
+	HealthCheckFrequency time.Duration
+	APIShedMethods       []string
+	APIShedRetryAfter    time.Duration
+
+	// If true, this node serves query APIs but refuses calls that issue txs or
+	// change keystore users, and its chains follow consensus without voting or
+	// proposing containers
+	ReadOnlyReplica bool
+
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
+	AuthAPIEnabled     bool
+	InfoAPIEnabled     bool
+
+	// Parameters keystore passwords are hashed with
+	KeystorePasswordParams keystore.PasswordParams
+
+	// Number of bytes each keystore user may store, where 0 means unlimited
+	KeystoreUserQuota uint64
+
+	// Keystore users whose quota differs from [KeystoreUserQuota]
+	KeystoreQuotaOverrides map[string]uint64
+
+	// Number of keystore users whose correctly given passwords are
+	// remembered, and the time they're remembered for
+	KeystoreSessionCacheSize int
+	KeystoreSessionTTL       time.Duration
+
+	// Faucet configuration
+	FaucetAPIEnabled bool
+	FaucetConfig     faucet.Config
 
 	// Logging configuration
 	LoggingConfig logging.Config
@@ -68,4 +210,15 @@ type Config struct {
 
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter router.Router
+
+	// JSON file of flag values, some of which can be reloaded while the node
+	// is running. Empty if the node wasn't started with a config file.
+	ConfigFile string
+
+	// Flags the node was configured with
+	Flags *flag.FlagSet
+
+	// Names of the flags given on the command line. They take precedence over
+	// the config file, so they're never reloaded.
+	CommandLineFlags map[string]bool
 }
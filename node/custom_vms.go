@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+var (
+	errNilVMFactory    = errors.New("VM factory can't be nil")
+	errNodeInitialized = errors.New("VMs must be registered before the node is initialized")
+	errVMRegistered    = errors.New("a VM with this ID has already been registered")
+	errVMAliasTaken    = errors.New("VM alias is already used by a genesis VM")
+)
+
+// customVM is a VM registered by a program that embeds this node
+type customVM struct {
+	vmID    ids.ID
+	factory vms.VMFactory
+	aliases []string
+}
+
+// RegisterVMFactory registers [factory] as the factory of the VM whose ID is
+// [vmID], so that programs that embed this node can run their own VMs
+// in-process. The VM is also known by [aliases]. Chains of the VM are created
+// like those of any other VM, by issuing a CreateChainTx to the P-Chain.
+//
+// It must be called before the node is initialized.
+func (n *Node) RegisterVMFactory(vmID ids.ID, factory vms.VMFactory, aliases ...string) error {
+	switch {
+	case factory == nil:
+		return errNilVMFactory
+	case n.vmManager != nil:
+		return errNodeInitialized
+	case vmID.Equals(platformvm.ID):
+		return fmt.Errorf("%w: %s", errVMRegistered, vmID)
+	}
+	for _, vm := range n.customVMs {
+		if vm.vmID.Equals(vmID) {
+			return fmt.Errorf("%w: %s", errVMRegistered, vmID)
+		}
+	}
+
+	n.customVMs = append(n.customVMs, customVM{
+		vmID:    vmID,
+		factory: factory,
+		aliases: aliases,
+	})
+	return nil
+}
+
+// registerCustomVMs registers the VMs registered with RegisterVMFactory with
+// the VM manager. Their aliases may not be aliases the genesis gives the VMs
+// that come with this node.
+func (n *Node) registerCustomVMs() error {
+	_, _, genesisAliases := genesis.Aliases(n.Config.NetworkID)
+	taken := make(map[string]bool)
+	for _, aliases := range genesisAliases {
+		for _, alias := range aliases {
+			taken[alias] = true
+		}
+	}
+
+	for _, vm := range n.customVMs {
+		if err := n.vmManager.RegisterVMFactory(vm.vmID, vm.factory); err != nil {
+			return err
+		}
+		for _, alias := range vm.aliases {
+			if taken[alias] {
+				return fmt.Errorf("%w: %s", errVMAliasTaken, alias)
+			}
+			if err := n.vmManager.Alias(vm.vmID, alias); err != nil {
+				return err
+			}
+		}
+		n.Log.Info("registered VM %s with aliases %v", vm.vmID, vm.aliases)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errNotChecked = errors.New("node's health hasn't been checked yet")
+	errStarved    = errors.New("chain is delaying requests from peers because it's out of CPU budget")
+)
+
+// initHealthChecks checks the health of the node every
+// [n.Config.HealthCheckFrequency], and refuses calls of
+// [n.Config.APIShedMethods] while it's unhealthy
+func (n *Node) initHealthChecks() {
+	if n.Config.HealthCheckFrequency <= 0 || len(n.Config.APIShedMethods) == 0 {
+		return
+	}
+	n.Log.Info("refusing calls of %v while the node is unhealthy", n.Config.APIShedMethods)
+	n.APIServer.ShedLoad(n.Config.APIShedRetryAfter, n.Config.APIShedMethods)
+
+	// Until the first check, the chains are bootstrapping
+	n.APIServer.SetHealth(errNotChecked)
+	n.healthChecks = time.NewTicker(n.Config.HealthCheckFrequency)
+	go n.Log.RecoverAndPanic(func() {
+		delayed := make(map[[32]byte]uint64)
+		healthy := false
+		for range n.healthChecks.C {
+			err := n.health(delayed)
+			switch {
+			case err == nil && !healthy:
+				n.Log.Info("node is healthy, so calls of %v are served", n.Config.APIShedMethods)
+			case err != nil && healthy:
+				n.Log.Warn("node is unhealthy, so calls of %v are refused: %s", n.Config.APIShedMethods, err)
+			}
+			healthy = err == nil
+			n.APIServer.SetHealth(err)
+		}
+	})
+}
+
+// health returns why the node is unhealthy, or nil if it's healthy. [delayed]
+// maps each chain to the number of requests it had delayed when the node's
+// health was last checked, and is updated.
+func (n *Node) health(delayed map[[32]byte]uint64) error {
+	starved := error(nil)
+	for _, usage := range n.chainManager.ResourceUsage() {
+		key := usage.ChainID.Key()
+		if usage.Delayed > delayed[key] && starved == nil {
+			starved = fmt.Errorf("%w: %s", errStarved, usage.ChainID)
+		}
+		delayed[key] = usage.Delayed
+	}
+	if err := n.chainManager.Health(); err != nil {
+		return err
+	}
+	return starved
+}
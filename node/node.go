@@ -9,30 +9,41 @@ package node
 import "C"
 
 import (
-	"crypto/x509"
-	"encoding/pem"
+	"crypto/tls"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/auth"
+	"github.com/ava-labs/gecko/api/faucet"
+	"github.com/ava-labs/gecko/api/info"
 	"github.com/ava-labs/gecko/api/ipcs"
 	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/api/metrics"
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/compactor"
+	"github.com/ava-labs/gecko/database/manifest"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/networking/banlist"
+	"github.com/ava-labs/gecko/networking/probe"
 	"github.com/ava-labs/gecko/networking/xputtest"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/vms"
@@ -65,15 +76,25 @@ type Node struct {
 	// Storage for this node
 	DB database.Database
 
+	// Compacts the database on demand and during off-peak hours
+	compactor compactor.Compactor
+
 	// Handles calls to Keystore API
 	keystoreServer keystore.Keystore
 
+	// Dispenses funds on test networks
+	faucet *faucet.Faucet
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
 	// Manages Virtual Machines
 	vmManager vms.Manager
 
+	// VMs registered by a program that embeds this node, which are registered
+	// with [vmManager] when the node is initialized
+	customVMs []customVM
+
 	// dispatcher for events as they happen in consensus
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
@@ -101,8 +122,20 @@ type Node struct {
 	// Handles HTTP API calls
 	APIServer api.Server
 
+	// Answers UDP liveness probes. nil if disabled.
+	probeResponder *probe.Responder
+
+	// Ticks when the node's health is checked. nil if it isn't.
+	healthChecks *time.Ticker
+
 	// This node's configuration
 	Config *Config
+
+	// Serializes reloads of the config file
+	reloadLock sync.Mutex
+	// Receives SIGHUP, which reloads the config file. nil if the node wasn't
+	// started with a config file.
+	reloadSignals chan os.Signal
 }
 
 /*
@@ -140,8 +173,11 @@ func (n *Node) initNetlib() error {
 
 	// Create peer network config, may have tls enabled
 	peerConfig := salticidae.NewPeerNetworkConfig()
+	msgConfig := peerConfig.AsMsgNetworkConfig()
+	// Bound each connection's write buffer, so that the messages a slow peer
+	// hasn't read wait in its send queue
+	msgConfig.QueueCapacity(networking.SendBufferSize)
 	if n.Config.EnableStaking {
-		msgConfig := peerConfig.AsMsgNetworkConfig()
 		msgConfig.MaxMsgSize(maxMessageSize)
 		msgConfig.EnableTLS(true)
 		msgConfig.TLSKeyFile(n.Config.StakingKeyFile)
@@ -188,6 +224,11 @@ func (n *Node) initValidatorNet() error {
 		return errors.New(salticidae.StrError(code))
 	}
 
+	bans := &banlist.Banlist{}
+	if err := bans.Initialize(prefixdb.New([]byte("banlist"), n.DB)); err != nil {
+		return err
+	}
+
 	n.ValidatorAPI = &networking.HandshakeNet
 	n.ValidatorAPI.Initialize(
 		/*log=*/ n.Log,
@@ -198,6 +239,8 @@ func (n *Node) initValidatorNet() error {
 		/*metrics=*/ n.Config.ConsensusParams.Metrics,
 		/*enableStaking=*/ n.Config.EnableStaking,
 		/*networkID=*/ n.Config.NetworkID,
+		/*banlist=*/ bans,
+		/*versionCheckFrequency=*/ n.Config.VersionCheckFrequency,
 	)
 
 	return nil
@@ -208,7 +251,7 @@ func (n *Node) initConsensusNet() {
 	n.Log.AssertTrue(ok, "should have initialize the validator set already")
 
 	n.ConsensusAPI = &networking.VotingNet
-	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
+	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.ValidatorAPI.SendQueues(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
 
 	n.Log.AssertNoError(n.ConsensusDispatcher.Register("gossip", n.ConsensusAPI))
 }
@@ -285,7 +328,86 @@ func (n *Node) Dispatch() { n.EC.Dispatch() }
  ******************************************************************************
  */
 
-func (n *Node) initDatabase() { n.DB = n.Config.DB }
+func (n *Node) initDatabase() error {
+	n.DB = n.Config.DB
+
+	// Running against a database another network, or an incompatible version
+	// of the node, wrote would corrupt it, or worse, appear to work
+	expected := manifest.Manifest{
+		NetworkID:     n.Config.NetworkID,
+		GenesisHash:   ids.NewID(hashing.ComputeHash256Array(genesis.Genesis(n.Config.NetworkID))),
+		SchemaVersion: manifest.SchemaVersion,
+	}
+	existed, err := manifest.Check(n.DB, expected)
+	if err == manifest.ErrNoManifest {
+		err = n.adoptDatabase(expected)
+	}
+	switch {
+	case (errors.Is(err, manifest.ErrMismatch) || err == manifest.ErrNoManifest) && n.Config.ForceReset:
+		n.Log.Warn("deleting the database's contents, as --force-reset is set and %s", err)
+		if err := manifest.Reset(n.DB, expected); err != nil {
+			return fmt.Errorf("couldn't reset the database: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("%w. Set --db-dir to another directory, or set --force-reset to delete the database's contents", err)
+	case !existed:
+		n.Log.Info("recorded that the database is for %s", expected)
+	}
+
+	n.compactor.Initialize(n.Log)
+	if n.Config.DBCompactionEnabled {
+		n.compactor.Schedule(n.DB, n.Config.DBCompactionTime)
+	}
+	return nil
+}
+
+// adoptDatabase records [expected] as the manifest of a database that was
+// created before manifests were recorded, if the database holds the state of
+// one of the chains this network's genesis creates. Otherwise, the network the
+// database was created for isn't known, and ErrNoManifest is returned.
+func (n *Node) adoptDatabase(expected manifest.Manifest) error {
+	genesisState := &platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesis.Genesis(n.Config.NetworkID), genesisState); err != nil {
+		return fmt.Errorf("couldn't parse the genesis state: %w", err)
+	}
+	if err := genesisState.Initialize(); err != nil {
+		return fmt.Errorf("couldn't parse the genesis state: %w", err)
+	}
+
+	// The platform chain's ID is the same on every network, so its state
+	// doesn't tell which network the database was created for
+	for _, chain := range genesisState.Chains {
+		chainID := chain.ID()
+		has, err := manifest.HasChainState(n.DB, chainID)
+		if err != nil {
+			return err
+		}
+		if !has {
+			continue
+		}
+		n.Log.Info("the database has no manifest, but holds the state of this network's chain %s", chainID)
+		return manifest.Put(n.DB, expected)
+	}
+	return manifest.ErrNoManifest
+}
+
+// CompactDatabase compacts this node's whole database, and returns how long it
+// took
+func (n *Node) CompactDatabase() (time.Duration, error) { return n.compactor.Compact(n.DB) }
+
+// CompactChainDatabase compacts the databases the chain [chainID] stores its
+// state in, and returns how long it took
+func (n *Node) CompactChainDatabase(chainID ids.ID) (time.Duration, error) {
+	dbs, err := n.chainManager.Databases(chainID)
+	if err != nil {
+		return 0, err
+	}
+	compacters := make([]database.Compacter, len(dbs))
+	for i, db := range dbs {
+		compacters[i] = db
+	}
+	return n.compactor.Compact(compacters...)
+}
 
 // Initialize this node's ID
 // If staking is disabled, a node's ID is a hash of its IP
@@ -298,36 +420,68 @@ func (n *Node) initNodeID() error {
 		return nil
 	}
 
-	stakeCert, err := ioutil.ReadFile(n.Config.StakingCertFile)
+	nodeID, err := staking.NodeIDFromFile(n.Config.StakingCertFile)
 	if err != nil {
-		return fmt.Errorf("problem reading staking certificate: %w", err)
+		return fmt.Errorf("problem deriving staker ID from certificate: %w", err)
 	}
+	n.ID = nodeID
+	n.Log.Info("Set node's ID to %s", n.ID)
+	return nil
+}
 
-	block, _ := pem.Decode(stakeCert)
-	cert, err := x509.ParseCertificate(block.Bytes)
+// initProbeResponder starts answering liveness probes with the staking key, if
+// enabled
+func (n *Node) initProbeResponder() error {
+	if n.Config.ProbePort == 0 {
+		return nil
+	}
+	if !n.Config.EnableStaking {
+		n.Log.Warn("Not answering liveness probes as staking is disabled, so there is no staking key to sign them with")
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(n.Config.StakingCertFile, n.Config.StakingKeyFile)
 	if err != nil {
-		return fmt.Errorf("problem parsing staking certificate: %w", err)
+		return fmt.Errorf("couldn't load staking key: %w", err)
 	}
-	n.ID, err = ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+	conn, err := net.ListenPacket("udp", fmt.Sprintf(":%d", n.Config.ProbePort))
 	if err != nil {
-		return fmt.Errorf("problem deriving staker ID from certificate: %w", err)
+		return fmt.Errorf("couldn't listen for probes: %w", err)
 	}
-	n.Log.Info("Set node's ID to %s", n.ID)
+	n.probeResponder, err = probe.NewResponder(n.Log, conn, cert)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	n.Log.Info("Answering liveness probes on UDP port %d", n.Config.ProbePort)
+	go n.Log.RecoverAndPanic(n.probeResponder.Dispatch)
 	return nil
 }
 
 // Create the vmManager and register the following vms:
-// AVM, EVM, Simple Payments DAG, Simple Payments Chain
+// AVM, EVM, Simple Payments DAG, Simple Payments Chain, and the VMs registered
+// with RegisterVMFactory
 // The Platform VM is registered in initStaking because
 // its factory needs to reference n.chainManager, which is nil right now
-func (n *Node) initVMManager() {
+func (n *Node) initVMManager() error {
 	n.vmManager = vms.NewManager(&n.APIServer, n.HTTPLog)
-	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{})
+	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
+		RegossipFrequency:    n.Config.TxRegossipFrequency,
+		MaxRegossipFrequency: n.Config.TxMaxRegossipFrequency,
+		BatchSize:            n.Config.AVMBatchSize,
+		BatchTimeout:         n.Config.AVMBatchTimeout,
+		FeeAsset:             n.Config.AVMFeeAsset,
+		MinFee:               n.Config.AVMMinFee,
+		Reindex:              n.Config.Reindex,
+		WatchAllowedHosts:    n.Config.WatchAllowedHosts,
+	})
 	n.vmManager.RegisterVMFactory(evm.ID, &evm.Factory{})
 	n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{TxFee: n.Config.AvaTxFee})
 	n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{})
 	n.vmManager.RegisterVMFactory(secp256k1fx.ID, &secp256k1fx.Factory{})
 	n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{})
+	return n.registerCustomVMs()
 }
 
 // Create the EventDispatcher used for hooking events
@@ -356,8 +510,16 @@ func (n *Node) initChains() {
 	n.vmManager.RegisterVMFactory(
 		/*vmID=*/ platformvm.ID,
 		/*vmFactory=*/ &platformvm.Factory{
-			ChainManager: n.chainManager,
-			Validators:   vdrs,
+			ChainManager:      n.chainManager,
+			Uptimes:           n.ValidatorAPI.Uptimes(),
+			Validators:        vdrs,
+			Reindex:           n.Config.Reindex,
+			MinStartTimeLead:  n.Config.MinStartTimeLead,
+			StartTimeMargin:   n.Config.StartTimeMargin,
+			AdvanceTimePacing: n.Config.AdvanceTimePacing,
+			RewardCurve:       genesis.RewardCurve(n.Config.NetworkID),
+			Upgrades:          genesis.Upgrades(n.Config.NetworkID),
+			WatchAllowedHosts: n.Config.WatchAllowedHosts,
 		},
 	)
 
@@ -377,28 +539,93 @@ func (n *Node) initChains() {
 	})
 }
 
+// platformExportRoute is the P-Chain's account export, which is only served if
+// the admin API is enabled
+const platformExportRoute = "bc/P" + platformvm.ExportEndpoint
+
 // initAPIServer initializes the server that handles HTTP calls
 func (n *Node) initAPIServer() {
 	n.Log.Info("Initializing API server")
 
 	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort)
+	n.APIServer.ConfigureHTTP(n.Config.HTTPConfig)
+	if n.Config.APICacheTTL > 0 {
+		n.Log.Info("caching the responses to calls of %v for %s", n.Config.APICachedMethods, n.Config.APICacheTTL)
+		n.APIServer.CacheResponses(n.Config.APICacheTTL, n.Config.APICachedMethods)
+	}
+
+	if err := n.APIServer.EnableAPIKeys(prefixdb.New([]byte("apikeys"), n.DB)); err != nil {
+		n.Log.Error("couldn't load the API keys: %s", err)
+	}
 
+	listener := api.Listener{
+		Address:       fmt.Sprintf("%s:%d", n.Config.HTTPHost, n.Config.HTTPPort),
+		DisabledAPIs:  n.disabledAPIs(n.Config.HTTPDisabledAPIs, n.Config.HTTPRequireAPIKey),
+		RequireAPIKey: n.Config.HTTPRequireAPIKey,
+	}
 	if n.Config.EnableHTTPS {
 		n.Log.Debug("Initializing API server with TLS Enabled")
+		tlsListener := listener
+		tlsListener.CertFile = n.Config.HTTPSCertFile
+		tlsListener.KeyFile = n.Config.HTTPSKeyFile
 		go n.Log.RecoverAndPanic(func() {
-			if err := n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile); err != nil {
+			if err := n.APIServer.DispatchListener(tlsListener); err != nil {
 				n.Log.Warn("API server initialization failed with %s, attempting to create insecure API server", err)
-				n.APIServer.Dispatch()
+				n.APIServer.DispatchListener(listener)
 			}
 		})
 	} else {
 		n.Log.Debug("Initializing API server with TLS Disabled")
-		go n.Log.RecoverAndPanic(func() { n.APIServer.Dispatch() })
+		go n.Log.RecoverAndPanic(func() { n.APIServer.DispatchListener(listener) })
+	}
+
+	for _, listener := range n.Config.HTTPListeners {
+		listener := listener
+		listener.DisabledAPIs = n.disabledAPIs(listener.DisabledAPIs, listener.RequireAPIKey)
+		n.Log.Info("serving the API on %s, except for %v", listener.Address, listener.DisabledAPIs)
+		go n.Log.RecoverAndPanic(func() {
+			if err := n.APIServer.DispatchListener(listener); err != nil {
+				n.Log.Error("API server stopped listening on %s: %s", listener.Address, err)
+			}
+		})
 	}
 }
 
+// disabledAPIs returns [apis], the keystore methods that reveal or remove other
+// people's users, the P-Chain's account export and the methods that manage API
+// keys if the admin API is disabled, the methods that manage API keys if
+// [requireAPIKey], and the methods that issue txs or change keystore users if
+// this node is a read-only replica
+func (n *Node) disabledAPIs(apis []string, requireAPIKey bool) []string {
+	disabled := append([]string(nil), apis...)
+	if !n.Config.AdminAPIEnabled {
+		disabled = append(disabled, keystore.AdminMethods...)
+		disabled = append(disabled, platformExportRoute)
+	}
+	if !n.Config.AdminAPIEnabled || requireAPIKey {
+		disabled = append(disabled, auth.AdminMethods...)
+	}
+	if !n.Config.ReadOnlyReplica {
+		return disabled
+	}
+	for _, methods := range [][]string{
+		keystore.WriteMethods,
+		faucet.WriteMethods,
+		avm.WriteMethods,
+		platformvm.WriteMethods,
+		evm.WriteMethods,
+		spchainvm.WriteMethods,
+		spdagvm.WriteMethods,
+		timestampvm.WriteMethods,
+	} {
+		disabled = append(disabled, methods...)
+	}
+	return disabled
+}
+
 // Assumes n.DB, n.vdrs all initialized (non-nil)
 func (n *Node) initChainManager() {
+	n.Log.Info("consensus sampling is seeded with %d", n.Config.ConsensusSeed)
 	n.chainManager = chains.New(
 		n.Log,
 		n.LogFactory,
@@ -413,8 +640,19 @@ func (n *Node) initChainManager() {
 		n.ID,
 		n.Config.NetworkID,
 		n.ValidatorAPI,
+		n.ValidatorAPI.Latencies(),
 		&n.APIServer,
 		&n.keystoreServer,
+		n.Config.ChainCPUBudget,
+		n.Config.ChainMemoryBudget,
+		n.Config.ReadOnlyReplica,
+		n.Config.ContainerLimits,
+		n.Config.FrontierMonitor,
+		n.Config.AcceptanceWindow,
+		n.Config.SnapshotDir,
+		n.Config.ChainConfigDir,
+		n.Config.Clock,
+		n.Config.ConsensusSeed,
 	)
 
 	n.chainManager.AddRegistrant(&n.APIServer)
@@ -426,10 +664,13 @@ func (n *Node) initKeystoreAPI() {
 	n.Log.Info("initializing Keystore API")
 	keystoreDB := prefixdb.New([]byte("keystore"), n.DB)
 	n.keystoreServer.Initialize(n.Log, keystoreDB)
-	keystoreHandler := n.keystoreServer.CreateHandler()
-	if n.Config.KeystoreAPIEnabled {
-		n.APIServer.AddRoute(keystoreHandler, &sync.RWMutex{}, "keystore", "", n.HTTPLog)
+	if err := n.keystoreServer.SetPasswordParams(n.Config.KeystorePasswordParams); err != nil {
+		n.Log.Error("invalid keystore password hashing parameters, using the defaults: %s", err)
 	}
+	n.keystoreServer.SetQuotas(n.Config.KeystoreUserQuota, n.Config.KeystoreQuotaOverrides)
+	n.keystoreServer.SetSessionCache(n.Config.KeystoreSessionCacheSize, n.Config.KeystoreSessionTTL)
+	keystoreHandler := n.keystoreServer.CreateHandler()
+	n.addAPI(keystoreHandler, "keystore", n.Config.KeystoreAPIEnabled)
 }
 
 // initMetricsAPI initializes the Metrics API
@@ -437,29 +678,76 @@ func (n *Node) initKeystoreAPI() {
 func (n *Node) initMetricsAPI() {
 	n.Log.Info("initializing Metrics API")
 	registry, handler := metrics.NewService()
-	if n.Config.MetricsAPIEnabled {
-		n.APIServer.AddRoute(handler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
-	}
+	n.addAPI(handler, "metrics", n.Config.MetricsAPIEnabled)
 	n.Config.ConsensusParams.Metrics = registry
+	if err := n.APIServer.RegisterMetrics(registry); err != nil {
+		n.Log.Error("failed to register the API server's metrics: %s", err)
+	}
 }
 
 // initAdminAPI initializes the Admin API service
 // Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
 func (n *Node) initAdminAPI() {
-	if n.Config.AdminAPIEnabled {
-		n.Log.Info("initializing Admin API")
-		service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), &n.APIServer)
-		n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
+	n.Log.Info("initializing Admin API")
+	service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), n.ValidatorAPI.Latencies(), n.ValidatorAPI, n.ValidatorAPI.Versions(), &n.APIServer, n, n)
+	n.addAPI(service, "admin", n.Config.AdminAPIEnabled)
+}
+
+// initAuthAPI initializes the Auth API service
+// Assumes n.log and n.APIServer already initialized
+func (n *Node) initAuthAPI() {
+	n.Log.Info("initializing Auth API")
+	service := auth.NewService(n.Log, &n.APIServer)
+	n.addAPI(service, "auth", n.Config.AuthAPIEnabled)
+}
+
+// initInfoAPI initializes the Info API service
+// Assumes n.log and n.APIServer already initialized
+func (n *Node) initInfoAPI() {
+	n.Log.Info("initializing Info API")
+	cert := (*tls.Certificate)(nil)
+	if n.Config.EnableStaking {
+		keyPair, err := tls.LoadX509KeyPair(n.Config.StakingCertFile, n.Config.StakingKeyFile)
+		if err != nil {
+			n.Log.Error("couldn't load staking key, so the Info API can't prove this node's ID: %s", err)
+		} else {
+			cert = &keyPair
+		}
+	}
+	service, err := info.NewService(n.Log, n.ID, cert)
+	if err != nil {
+		n.Log.Error("couldn't create the Info API: %s", err)
+		return
 	}
+	n.addAPI(service, "info", n.Config.InfoAPIEnabled)
+}
+
+// initFaucetAPI initializes the Faucet API service
+// Assumes n.log and n.APIServer already initialized
+func (n *Node) initFaucetAPI() {
+	n.Log.Info("initializing Faucet API")
+	f, service := faucet.NewService(n.Log, &n.APIServer, n.Config.FaucetConfig)
+	n.faucet = f
+	n.addAPI(service, "faucet", n.Config.FaucetAPIEnabled)
 }
 
 // initIPCAPI initializes the IPC API service
 // Assumes n.log and n.chainManager already initialized
 func (n *Node) initIPCAPI() {
-	if n.Config.IPCEnabled {
-		n.Log.Info("initializing IPC API")
-		service := ipcs.NewService(n.Log, n.chainManager, n.DecisionDispatcher, &n.APIServer)
-		n.APIServer.AddRoute(service, &sync.RWMutex{}, "ipcs", "", n.HTTPLog)
+	n.Log.Info("initializing IPC API")
+	service := ipcs.NewService(n.Log, n.chainManager, n.DecisionDispatcher, &n.APIServer)
+	n.addAPI(service, "ipcs", n.Config.IPCEnabled)
+}
+
+// addAPI adds the route [base] to [handler]. If [enabled] is false, the route
+// isn't served until it's enabled by reloading the config file.
+func (n *Node) addAPI(handler *common.HTTPHandler, base string, enabled bool) {
+	if err := n.APIServer.AddRoute(handler, &sync.RWMutex{}, base, "", n.HTTPLog); err != nil {
+		n.Log.Error("couldn't add route %s: %s", base, err)
+		return
+	}
+	if !enabled {
+		n.Log.AssertNoError(n.APIServer.SetRouteEnabled(base, false))
 	}
 }
 
@@ -496,12 +784,18 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	}
 	n.HTTPLog = httpLog
 
-	n.initDatabase() // Set up the node's database
+	if err = n.initDatabase(); err != nil { // Set up the node's database
+		return fmt.Errorf("problem initializing database: %w", err)
+	}
 
 	if err = n.initNodeID(); err != nil { // Derive this node's ID
 		return fmt.Errorf("problem initializing staker ID: %w", err)
 	}
 
+	if err = n.initProbeResponder(); err != nil { // Answer liveness probes
+		return fmt.Errorf("problem initializing probe responder: %w", err)
+	}
+
 	// Start HTTP APIs
 	n.initAPIServer()   // Start the API Server
 	n.initKeystoreAPI() // Start the Keystore API
@@ -511,8 +805,12 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	if err = n.initNetlib(); err != nil { // Set up all networking
 		return fmt.Errorf("problem initializing networking: %w", err)
 	}
-	n.initValidatorNet()    // Set up the validator handshake + authentication
-	n.initVMManager()       // Set up the vm manager
+	if err = n.initValidatorNet(); err != nil { // Set up the validator handshake + authentication
+		return fmt.Errorf("problem initializing validator network: %w", err)
+	}
+	if err = n.initVMManager(); err != nil { // Set up the vm manager
+		return fmt.Errorf("problem initializing VM manager: %w", err)
+	}
 	n.initEventDispatcher() // Set up the event dipatcher
 	n.initChainManager()    // Set up the chain manager
 	n.initConsensusNet()    // Set up the main consensus network
@@ -522,10 +820,17 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 		n.initClients() // Set up the client servers
 	}
 
-	n.initAdminAPI() // Start the Admin API
-	n.initIPCAPI()   // Start the IPC API
-	n.initAliases()  // Set up aliases
-	n.initChains()   // Start the Platform chain
+	n.initAdminAPI()  // Start the Admin API
+	n.initAuthAPI()   // Start the Auth API
+	n.initInfoAPI()   // Start the Info API
+	n.initFaucetAPI() // Start the Faucet API
+	n.initIPCAPI()    // Start the IPC API
+	n.initAliases()   // Set up aliases
+	n.initChains()    // Start the Platform chain
+
+	n.initHealthChecks() // Refuse expensive API calls while unhealthy
+
+	n.initReloadSignal() // Reload the config file on SIGHUP
 
 	return nil
 }
@@ -533,7 +838,18 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 // Shutdown this node
 func (n *Node) Shutdown() {
 	n.Log.Info("shutting down the node")
+	if n.reloadSignals != nil {
+		signal.Stop(n.reloadSignals)
+		close(n.reloadSignals)
+	}
 	n.ValidatorAPI.Shutdown()
 	n.ConsensusAPI.Shutdown()
 	n.chainManager.Shutdown()
+	n.compactor.Stop()
+	if n.healthChecks != nil {
+		n.healthChecks.Stop()
+	}
+	if n.probeResponder != nil {
+		n.Log.AssertNoError(n.probeResponder.Stop())
+	}
 }
@@ -0,0 +1,239 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	errNoConfigFile       = errors.New("the node wasn't started with a config file")
+	errUnknownFlag        = errors.New("unknown flag")
+	errInvalidConfigValue = errors.New("config values must be strings, numbers or booleans")
+)
+
+// ReadConfigFile reads the JSON object at [path], which maps flag names to
+// their values
+func ReadConfigFile(path string) (map[string]string, error) {
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read config file %s: %w", path, err)
+	}
+
+	raw := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	decoder.UseNumber() // Keep numbers as they were written
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("couldn't parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch value.(type) {
+		case string, json.Number, bool:
+			values[name] = fmt.Sprint(value)
+		default:
+			return nil, fmt.Errorf("%w: %s", errInvalidConfigValue, name)
+		}
+	}
+	return values, nil
+}
+
+// reloaders returns how to apply each flag that can be changed while the node
+// is running. A reloader is called after the flag has been set to its new
+// value.
+func (n *Node) reloaders() map[string]func() error {
+	return map[string]func() error{
+		"log-level": func() error {
+			level, err := n.flagLevel("log-level")
+			if err == nil {
+				n.LogFactory.SetLogLevel(level)
+			}
+			return err
+		},
+		"log-display-level": func() error {
+			level, err := n.flagLevel("log-display-level")
+			if err == nil {
+				n.LogFactory.SetDisplayLevel(level)
+			}
+			return err
+		},
+		"api-admin-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("admin", n.Config.AdminAPIEnabled)
+		},
+		"api-keystore-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("keystore", n.Config.KeystoreAPIEnabled)
+		},
+		"api-metrics-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("metrics", n.Config.MetricsAPIEnabled)
+		},
+		"api-auth-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("auth", n.Config.AuthAPIEnabled)
+		},
+		"api-info-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("info", n.Config.InfoAPIEnabled)
+		},
+		"api-faucet-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("faucet", n.Config.FaucetAPIEnabled)
+		},
+		"api-ipcs-enabled": func() error {
+			return n.APIServer.SetRouteEnabled("ipcs", n.Config.IPCEnabled)
+		},
+		"keystore-user-quota": func() error {
+			n.keystoreServer.SetQuotas(n.Config.KeystoreUserQuota, n.Config.KeystoreQuotaOverrides)
+			return nil
+		},
+		"faucet-amount":     n.reloadFaucetLimits,
+		"faucet-rate-limit": n.reloadFaucetLimits,
+	}
+}
+
+func (n *Node) flagLevel(name string) (logging.Level, error) {
+	return logging.ToLevel(n.Config.Flags.Lookup(name).Value.String())
+}
+
+func (n *Node) reloadFaucetLimits() error {
+	n.faucet.SetLimits(n.Config.FaucetConfig.Amount, n.Config.FaucetConfig.RateLimit)
+	return nil
+}
+
+// ReloadConfig re-reads the config file and applies the flags in it that
+// changed and can be changed while the node is running. It returns the changed
+// flags that were applied, and those that only take effect when the node
+// restarts. Flags given on the command line take precedence over the config
+// file, so they're never changed.
+func (n *Node) ReloadConfig() ([]string, []string, error) {
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	if n.Config.ConfigFile == "" {
+		return nil, nil, errNoConfigFile
+	}
+	values, err := ReadConfigFile(n.Config.ConfigFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Check the whole file before changing anything
+	changed := []string{}
+	for _, name := range names {
+		f := n.Config.Flags.Lookup(name)
+		if f == nil {
+			return nil, nil, fmt.Errorf("%w: %s", errUnknownFlag, name)
+		}
+		if n.Config.CommandLineFlags[name] {
+			continue
+		}
+		same, err := sameValue(f, values[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value %q for flag %s: %w", values[name], name, err)
+		}
+		if !same {
+			changed = append(changed, name)
+		}
+	}
+
+	reloaders := n.reloaders()
+	applied, requireRestart := []string{}, []string{}
+	for _, name := range changed {
+		reload, reloadable := reloaders[name]
+		if !reloadable {
+			requireRestart = append(requireRestart, name)
+			continue
+		}
+
+		f := n.Config.Flags.Lookup(name)
+		oldValue := f.Value.String()
+		if err := f.Value.Set(values[name]); err != nil {
+			return applied, requireRestart, fmt.Errorf("couldn't set flag %s: %w", name, err)
+		}
+		if err := reload(); err != nil {
+			n.Log.AssertNoError(f.Value.Set(oldValue))
+			return applied, requireRestart, fmt.Errorf("couldn't apply flag %s: %w", name, err)
+		}
+		n.Log.Info("reloaded flag %s, which was %q and is now %q", name, oldValue, values[name])
+		applied = append(applied, name)
+	}
+	return applied, requireRestart, nil
+}
+
+// sameValue returns true if [value] parses to the current value of [f]
+func sameValue(f *flag.Flag, value string) (bool, error) {
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return f.Value.String() == value, nil
+	}
+
+	current := getter.Get()
+	var (
+		parsed interface{}
+		err    error
+	)
+	// Values are parsed the same way as the flag package parses them
+	switch current.(type) {
+	case bool:
+		parsed, err = strconv.ParseBool(value)
+	case int:
+		var v int64
+		v, err = strconv.ParseInt(value, 0, strconv.IntSize)
+		parsed = int(v)
+	case int64:
+		parsed, err = strconv.ParseInt(value, 0, 64)
+	case uint:
+		var v uint64
+		v, err = strconv.ParseUint(value, 0, strconv.IntSize)
+		parsed = uint(v)
+	case uint64:
+		parsed, err = strconv.ParseUint(value, 0, 64)
+	case float64:
+		parsed, err = strconv.ParseFloat(value, 64)
+	case time.Duration:
+		parsed, err = time.ParseDuration(value)
+	case string:
+		parsed = value
+	default:
+		return f.Value.String() == value, nil
+	}
+	return parsed == current, err
+}
+
+// initReloadSignal reloads the config file whenever the node receives SIGHUP
+func (n *Node) initReloadSignal() {
+	if n.Config.ConfigFile == "" {
+		return
+	}
+	n.reloadSignals = make(chan os.Signal, 1)
+	signal.Notify(n.reloadSignals, syscall.SIGHUP)
+	go func() {
+		for range n.reloadSignals {
+			n.Log.Info("reloading config file %s", n.Config.ConfigFile)
+			applied, requireRestart, err := n.ReloadConfig()
+			if err != nil {
+				n.Log.Error("couldn't reload config file %s: %s", n.Config.ConfigFile, err)
+			}
+			n.Log.Info("applied flags %v from the config file", applied)
+			if len(requireRestart) > 0 {
+				n.Log.Warn("flags %v of the config file changed, but only take effect when the node restarts", requireRestart)
+			}
+		}
+	}()
+}
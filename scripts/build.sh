@@ -18,3 +18,5 @@ else
 fi
 go build -o "$PREFIX/ava" "$GECKO_PATH/main/"*.go
 go build -o "$PREFIX/xputtest" "$GECKO_PATH/xputtest/"*.go
+go build -o "$PREFIX/stakingkey" "$GECKO_PATH/stakingkey/"*.go
+go build -o "$PREFIX/gecko-cli" "$GECKO_PKG/cmd/gecko-cli"
@@ -66,6 +66,10 @@ type Consensus interface {
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// Graph returns the processing vertices, their transactions, and the
+	// vertices and transactions they depend on
+	Graph() snow.Graph
 }
 
 // Vertex is a collection of multiple transactions tied to other vertices
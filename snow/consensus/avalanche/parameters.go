@@ -20,7 +20,7 @@ type Parameters struct {
 func (p Parameters) Valid() error {
 	switch {
 	case p.Parents <= 1:
-		return fmt.Errorf("parents = %d: Fails the condition that: 1 < Parents", p.Parents)
+		return fmt.Errorf("parents = %d: Fails the condition that: 1 < Parents, so the vertices would form a chain rather than a DAG", p.Parents)
 	case p.BatchSize <= 0:
 		return fmt.Errorf("batchSize = %d: Fails the condition that: 0 < BatchSize", p.BatchSize)
 	default:
@@ -168,6 +168,7 @@ func (ta *Topological) RecordPoll(responses ids.UniqueBag) {
 	// Update the conflict graph: O(|Transactions|)
 	ta.ctx.Log.Verbo("Updating consumer confidences based on:\n%s", &votes)
 	ta.cg.RecordPoll(votes)
+	votes.Release()
 	// Update the dag: O(|Live Set|)
 	ta.updateFrontiers()
 }
@@ -178,6 +179,70 @@ func (ta *Topological) Quiesce() bool { return ta.cg.Quiesce() }
 // Finalized implements the Avalanche interface
 func (ta *Topological) Finalized() bool { return ta.cg.Finalized() }
 
+// Graph implements the Avalanche interface
+func (ta *Topological) Graph() snow.Graph {
+	graph := snow.Graph{}
+	added := ids.Set{}
+	addNode := func(node snow.GraphNode) {
+		if !added.Contains(node.ID) {
+			added.Add(node.ID)
+			graph.Nodes = append(graph.Nodes, node)
+		}
+	}
+	addEdge := func(from, to ids.ID, kind string) {
+		graph.Edges = append(graph.Edges, snow.GraphEdge{From: from, To: to, Kind: kind})
+	}
+	preferences := ta.cg.Preferences()
+	txNode := func(tx snowstorm.Tx) snow.GraphNode {
+		txID := tx.ID()
+		confidence, bias := ta.cg.Confidence(tx)
+		return snow.GraphNode{
+			ID:         txID,
+			Kind:       snow.TxNode,
+			Status:     tx.Status(),
+			Preferred:  tx.Status() == choices.Accepted || preferences.Contains(txID),
+			Confidence: confidence,
+			Bias:       bias,
+		}
+	}
+	vtxNode := func(vtx Vertex) snow.GraphNode {
+		vtxID := vtx.ID()
+		return snow.GraphNode{
+			ID:        vtxID,
+			Kind:      snow.VertexNode,
+			Status:    vtx.Status(),
+			Preferred: vtx.Status() == choices.Accepted || ta.preferenceCache[vtxID.Key()],
+		}
+	}
+
+	// Transactions may be in more than one vertex, but their dependencies are
+	// only added once
+	expanded := ids.Set{}
+	for _, vtx := range ta.nodes {
+		vtxID := vtx.ID()
+		addNode(vtxNode(vtx))
+		for _, parent := range vtx.Parents() {
+			addNode(vtxNode(parent))
+			addEdge(vtxID, parent.ID(), snow.ParentEdge)
+		}
+		for _, tx := range vtx.Txs() {
+			txID := tx.ID()
+			addNode(txNode(tx))
+			addEdge(vtxID, txID, snow.TxEdge)
+			if expanded.Contains(txID) {
+				continue
+			}
+			expanded.Add(txID)
+			for _, dep := range tx.Dependencies() {
+				addNode(txNode(dep))
+				addEdge(txID, dep.ID(), snow.DependencyEdge)
+			}
+		}
+	}
+	graph.Sort()
+	return graph
+}
+
 // Takes in a list of votes and sets up the topological ordering. Returns the
 // reachable section of the graph annotated with the number of inbound edges and
 // the non-transitively applied votes. Also returns the list of leaf nodes.
@@ -751,3 +751,115 @@ func TestAvalancheOrphans(t *testing.T) {
 		t.Fatalf("Wrong orphan")
 	}
 }
+
+func TestAvalancheGraph(t *testing.T) {
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:      prometheus.NewRegistry(),
+			K:            2,
+			Alpha:        2,
+			BetaVirtuous: 2,
+			BetaRogue:    2,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{&Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}, &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}}
+	utxos := []ids.ID{GenerateID(), GenerateID()}
+
+	ta := Topological{}
+	ta.Initialize(snow.DefaultContextTest(), params, vts)
+
+	tx0 := &snowstorm.TestTx{
+		Identifier: GenerateID(),
+		Stat:       choices.Processing,
+	}
+	tx0.Ins.Add(utxos[0])
+
+	vtx0 := &Vtx{
+		dependencies: vts,
+		id:           GenerateID(),
+		txs:          []snowstorm.Tx{tx0},
+		height:       1,
+		status:       choices.Processing,
+	}
+
+	tx1 := &snowstorm.TestTx{
+		Identifier: GenerateID(),
+		Stat:       choices.Processing,
+	}
+	tx1.Ins.Add(utxos[0])
+
+	vtx1 := &Vtx{
+		dependencies: vts,
+		id:           GenerateID(),
+		txs:          []snowstorm.Tx{tx1},
+		height:       1,
+		status:       choices.Processing,
+	}
+
+	ta.Add(vtx0)
+	ta.Add(vtx1)
+
+	sm := make(ids.UniqueBag)
+	sm.Add(0, vtx1.id)
+	sm.Add(1, vtx1.id)
+	ta.RecordPoll(sm)
+
+	tx2 := &snowstorm.TestTx{
+		Identifier: GenerateID(),
+		Deps:       []snowstorm.Tx{tx1},
+		Stat:       choices.Processing,
+	}
+	tx2.Ins.Add(utxos[1])
+
+	vtx2 := &Vtx{
+		dependencies: []Vertex{vtx1},
+		id:           GenerateID(),
+		txs:          []snowstorm.Tx{tx2},
+		height:       2,
+		status:       choices.Processing,
+	}
+
+	ta.Add(vtx2)
+
+	graph := ta.Graph()
+	if len(graph.Nodes) != 8 {
+		t.Fatalf("expected 5 vertices and 3 transactions but got %d nodes", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 9 {
+		t.Fatalf("expected 9 edges but got %d", len(graph.Edges))
+	}
+	nodes := make(map[[32]byte]snow.GraphNode)
+	for _, node := range graph.Nodes {
+		nodes[node.ID.Key()] = node
+	}
+	if node := nodes[vtx0.id.Key()]; node.Kind != snow.VertexNode || node.Preferred {
+		t.Fatalf("vertex %s shouldn't be preferred", vtx0.id)
+	}
+	if node := nodes[vtx1.id.Key()]; node.Kind != snow.VertexNode || !node.Preferred {
+		t.Fatalf("vertex %s should be preferred", vtx1.id)
+	}
+	if node := nodes[tx1.ID().Key()]; node.Kind != snow.TxNode || !node.Preferred || node.Confidence != 1 || node.Bias != 1 {
+		t.Fatalf("transaction %s should be preferred with a confidence and bias of 1 but was %+v", tx1.ID(), node)
+	}
+	if node := nodes[tx0.ID().Key()]; node.Preferred || node.Confidence != 0 || node.Bias != 0 {
+		t.Fatalf("transaction %s shouldn't be preferred, or have been voted for, but was %+v", tx0.ID(), node)
+	}
+
+	found := false
+	for _, edge := range graph.Edges {
+		if edge.From.Equals(tx2.ID()) && edge.To.Equals(tx1.ID()) && edge.Kind == snow.DependencyEdge {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an edge from %s to its dependency %s", tx2.ID(), tx1.ID())
+	}
+}
@@ -16,17 +16,21 @@ type Parameters struct {
 	K, Alpha, BetaVirtuous, BetaRogue int
 }
 
-// Valid returns nil if the parameters describe a valid initialization.
+// Valid returns nil if the parameters describe a valid initialization. The
+// parameters are invalid if consensus couldn't be safe with them, or couldn't
+// make progress.
 func (p Parameters) Valid() error {
 	switch {
+	case p.K <= 0:
+		return fmt.Errorf("K = %d: Fails the condition that: 0 < K", p.K)
 	case p.Alpha <= p.K/2:
-		return fmt.Errorf("K = %d, Alpha = %d: Fails the condition that: K/2 < Alpha", p.K, p.Alpha)
+		return fmt.Errorf("K = %d, Alpha = %d: Fails the condition that: K/2 < Alpha, so two conflicting decisions could both reach a quorum", p.K, p.Alpha)
 	case p.K < p.Alpha:
-		return fmt.Errorf("K = %d, Alpha = %d: Fails the condition that: Alpha <= K", p.K, p.Alpha)
+		return fmt.Errorf("K = %d, Alpha = %d: Fails the condition that: Alpha <= K, so a quorum could never be reached", p.K, p.Alpha)
 	case p.BetaVirtuous <= 0:
 		return fmt.Errorf("BetaVirtuous = %d: Fails the condition that: 0 < BetaVirtuous", p.BetaVirtuous)
 	case p.BetaRogue < p.BetaVirtuous:
-		return fmt.Errorf("BetaVirtuous = %d, BetaRogue = %d: Fails the condition that: BetaVirtuous <= BetaRogue", p.BetaVirtuous, p.BetaRogue)
+		return fmt.Errorf("BetaVirtuous = %d, BetaRogue = %d: Fails the condition that: BetaVirtuous <= BetaRogue, so conflicting decisions would be finalized sooner than virtuous ones", p.BetaVirtuous, p.BetaRogue)
 	default:
 		return nil
 	}
@@ -71,3 +71,29 @@ func TestParametersInvalidBetaRogue(t *testing.T) {
 		t.Fatalf("Should have failed due to invalid beta rogue")
 	}
 }
+
+func TestParametersAlphaGreaterThanK(t *testing.T) {
+	p := Parameters{
+		K:            2,
+		Alpha:        3,
+		BetaVirtuous: 1,
+		BetaRogue:    1,
+	}
+
+	if err := p.Valid(); err == nil {
+		t.Fatalf("Should have failed due to alpha greater than k")
+	}
+}
+
+func TestParametersNegativeK(t *testing.T) {
+	p := Parameters{
+		K:            -2,
+		Alpha:        -1,
+		BetaVirtuous: 1,
+		BetaRogue:    1,
+	}
+
+	if err := p.Valid(); err == nil {
+		t.Fatalf("Should have failed due to negative k")
+	}
+}
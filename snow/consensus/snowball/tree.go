@@ -79,6 +79,7 @@ func (t *Tree) RecordPoll(votes ids.Bag) {
 	// Now that the votes have been restricted to valid votes, pass them into
 	// the first snowball instance
 	t.root = t.root.RecordPoll(filteredVotes, t.shouldReset)
+	filteredVotes.Release()
 
 	// Because we just passed the reset into the snowball instance, we should no
 	// longer reset.
@@ -397,6 +398,7 @@ func (u *unaryNode) Add(newChoice ids.ID) node {
 func (u *unaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 	// This ensures that votes for rejected colors are dropped
 	votes = votes.Filter(u.decidedPrefix, u.commonPrefix, u.preference)
+	defer votes.Release()
 
 	// If my parent didn't get enough votes previously, then neither did I
 	if reset {
@@ -411,6 +413,7 @@ func (u *unaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 		if u.child != nil {
 			decidedPrefix := u.child.DecidedPrefix()
 			filteredVotes := votes.Filter(u.commonPrefix, decidedPrefix, u.preference)
+			defer filteredVotes.Release()
 			// If I'm now decided, return my child
 			if u.Finalized() {
 				return u.child.RecordPoll(filteredVotes, u.shouldReset)
@@ -489,6 +492,8 @@ func (b *binaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 	// The list of votes we are passed is split into votes for bit 0 and votes
 	// for bit 1
 	splitVotes := votes.Split(uint(b.bit))
+	defer splitVotes[0].Release()
+	defer splitVotes[1].Release()
 
 	bit := 0 // Because alpha > k/2, only the larger count could be increased
 	if splitVotes[0].Len() < splitVotes[1].Len() {
@@ -512,6 +517,7 @@ func (b *binaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 			// count for the child
 			filteredVotes := prunedVotes.Filter(
 				b.bit+1, child.DecidedPrefix(), b.preferences[bit])
+			defer filteredVotes.Release()
 
 			if b.snowball.Finalized() {
 				// If we are decided here, that means we must have decided due
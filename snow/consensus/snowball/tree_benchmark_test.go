@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowball
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// benchmarkTreeRecordPoll benchmarks recording polls of [k] votes, split
+// between [numChoices] choices, in a tree that never finalizes
+func benchmarkTreeRecordPoll(b *testing.B, numChoices, k int) {
+	params := Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       k, Alpha: k/2 + 1, BetaVirtuous: math.MaxInt32, BetaRogue: math.MaxInt32,
+	}
+	choices := make([]ids.ID, numChoices)
+	for i := range choices {
+		choices[i] = ids.Empty.Prefix(uint64(i))
+	}
+
+	tree := Tree{}
+	tree.Initialize(params, choices[0])
+	for _, choice := range choices[1:] {
+		tree.Add(choice)
+	}
+
+	votes := ids.Bag{}
+	votes.AddCount(choices[0], params.Alpha)
+	for i := params.Alpha; i < k; i++ {
+		votes.Add(choices[i%numChoices])
+	}
+
+	before := runtime.MemStats{}
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree.RecordPoll(votes)
+	}
+	b.StopTimer()
+
+	after := runtime.MemStats{}
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+}
+
+func BenchmarkTreeRecordPoll2(b *testing.B)  { benchmarkTreeRecordPoll(b, 2, 20) }
+func BenchmarkTreeRecordPoll16(b *testing.B) { benchmarkTreeRecordPoll(b, 16, 20) }
@@ -36,4 +36,8 @@ type Consensus interface {
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// Graph returns the processing blocks and the last accepted block they
+	// build on
+	Graph() snow.Graph
 }
@@ -10,6 +10,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	dto "github.com/prometheus/client_model/go"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -612,6 +614,66 @@ func MetricsErrorTest(t *testing.T, factory Factory) {
 	sm.Initialize(ctx, params, Genesis.ID())
 }
 
+func MetricsTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	registry := prometheus.NewRegistry()
+	params := snowball.Parameters{
+		Namespace: "gecko",
+		Metrics:   registry,
+		K:         1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 2,
+	}
+	sm.Initialize(ctx, params, Genesis.ID())
+
+	gather := func() map[string]*dto.Metric {
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		metrics := make(map[string]*dto.Metric)
+		for _, family := range families {
+			metrics[family.GetName()] = family.GetMetric()[0]
+		}
+		return metrics
+	}
+
+	blk0 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(1),
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     ids.Empty.Prefix(2),
+	}
+	sm.Add(blk0)
+	sm.Add(blk1)
+
+	metrics := gather()
+	if depth := metrics["gecko_processing_depth"].GetGauge().GetValue(); depth != 2 {
+		t.Fatalf("processing depth should be 2 but is %f", depth)
+	}
+
+	votes := ids.Bag{}
+	votes.Add(blk1.id)
+	sm.RecordPoll(votes)
+
+	if !sm.Finalized() {
+		t.Fatalf("Finalized too late")
+	}
+
+	metrics = gather()
+	if depth := metrics["gecko_processing_depth"].GetGauge().GetValue(); depth != 0 {
+		t.Fatalf("processing depth should be 0 but is %f", depth)
+	}
+	if count := metrics["gecko_accept_latency"].GetHistogram().GetSampleCount(); count != 2 {
+		t.Fatalf("accept latency should have 2 samples but has %d", count)
+	}
+	if polls := metrics["gecko_accept_polls"].GetHistogram(); polls.GetSampleCount() != 2 || polls.GetSampleSum() != 2 {
+		t.Fatalf("each block should have been accepted after 1 poll but %d blocks took %f polls", polls.GetSampleCount(), polls.GetSampleSum())
+	}
+}
+
 func ConsistentTest(t *testing.T, factory Factory) {
 	numColors := 50
 	numNodes := 100
@@ -641,3 +703,64 @@ func ConsistentTest(t *testing.T, factory Factory) {
 		t.Fatalf("Network agreed on inconsistent values")
 	}
 }
+
+func GraphTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 3, BetaRogue: 5,
+	}
+	sm.Initialize(snow.DefaultContextTest(), params, Genesis.ID())
+
+	dep0 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(1),
+		status: choices.Processing,
+	}
+	sm.Add(dep0)
+	dep1 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(2),
+		status: choices.Processing,
+	}
+	sm.Add(dep1)
+	dep2 := &Blk{
+		parent: dep0,
+		id:     ids.Empty.Prefix(3),
+		status: choices.Processing,
+	}
+	sm.Add(dep2)
+
+	graph := sm.Graph()
+	if len(graph.Nodes) != 4 {
+		t.Fatalf("expected the last accepted block and 3 processing blocks but got %d nodes", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 3 {
+		t.Fatalf("expected an edge from each processing block to its parent but got %d edges", len(graph.Edges))
+	}
+	for _, node := range graph.Nodes {
+		switch {
+		case node.ID.Equals(Genesis.ID()):
+			if node.Status != choices.Accepted || !node.Preferred {
+				t.Fatalf("the last accepted block should be accepted and preferred")
+			}
+			if node.Snowball == "" {
+				t.Fatalf("the last accepted block should report the snowball instance deciding between its children")
+			}
+		case node.ID.Equals(dep1.ID()):
+			if node.Preferred {
+				t.Fatalf("block %s shouldn't be preferred", node.ID)
+			}
+		default:
+			if !node.Preferred {
+				t.Fatalf("block %s should be preferred", node.ID)
+			}
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.From.Equals(dep2.ID()) && !edge.To.Equals(dep0.ID()) {
+			t.Fatalf("expected an edge from %s to its parent %s but it was to %s", dep2.ID(), dep0.ID(), edge.To)
+		}
+	}
+}
@@ -4,10 +4,13 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 )
 
@@ -24,8 +27,12 @@ type Topological struct {
 	ctx    *snow.Context
 	params snowball.Parameters
 
-	numProcessing            prometheus.Gauge
-	numAccepted, numRejected prometheus.Counter
+	numProcessing, processingDepth prometheus.Gauge
+	numAccepted, numRejected       prometheus.Counter
+	acceptLatency, acceptPolls     prometheus.Histogram
+
+	// Number of polls recorded so far
+	numPolls int
 
 	head  ids.ID
 	nodes map[[32]byte]node // ParentID -> Snowball instance
@@ -41,6 +48,10 @@ type node struct {
 	shouldFalter bool
 	sb           snowball.Consensus
 	children     map[[32]byte]Block
+
+	// When the block was added, and how many polls had been recorded then
+	added      time.Time
+	addedPolls int
 }
 
 // Used to track the kahn topological sort status
@@ -80,6 +91,26 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 			Name:      "rejected",
 			Help:      "Number of blocks rejected",
 		})
+	ts.processingDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: params.Namespace,
+			Name:      "processing_depth",
+			Help:      "Number of processing blocks on the preferred branch",
+		})
+	ts.acceptLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: params.Namespace,
+			Name:      "accept_latency",
+			Help:      "Milliseconds from when a block was issued into consensus to when it was accepted",
+			Buckets:   prometheus.ExponentialBuckets(10, 2, 14), // 10ms to ~80s
+		})
+	ts.acceptPolls = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: params.Namespace,
+			Name:      "accept_polls",
+			Help:      "Number of polls recorded while a block was processing, for accepted blocks",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10), // 1 to 512 polls
+		})
 
 	if err := ts.params.Metrics.Register(ts.numProcessing); err != nil {
 		ts.ctx.Log.Error("Failed to register processing statistics due to %s", err)
@@ -90,6 +121,15 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 	if err := ts.params.Metrics.Register(ts.numRejected); err != nil {
 		ts.ctx.Log.Error("Failed to register rejected statistics due to %s", err)
 	}
+	if err := ts.params.Metrics.Register(ts.processingDepth); err != nil {
+		ts.ctx.Log.Error("Failed to register processing_depth statistics due to %s", err)
+	}
+	if err := ts.params.Metrics.Register(ts.acceptLatency); err != nil {
+		ts.ctx.Log.Error("Failed to register accept_latency statistics due to %s", err)
+	}
+	if err := ts.params.Metrics.Register(ts.acceptPolls); err != nil {
+		ts.ctx.Log.Error("Failed to register accept_polls statistics due to %s", err)
+	}
 
 	ts.head = rootID
 	ts.nodes = map[[32]byte]node{
@@ -121,14 +161,17 @@ func (ts *Topological) Add(blk Block) {
 		ts.nodes[parentKey] = parent
 
 		ts.nodes[blkID.Key()] = node{
-			ts:    ts,
-			blkID: blkID,
-			blk:   blk,
+			ts:         ts,
+			blkID:      blkID,
+			blk:        blk,
+			added:      time.Now(),
+			addedPolls: ts.numPolls,
 		}
 
 		// If we are extending the tail, this is the new tail
 		if ts.tail.Equals(parentID) {
 			ts.tail = blkID
+			ts.processingDepth.Inc()
 		}
 
 		ts.numProcessing.Inc()
@@ -168,6 +211,8 @@ func (ts *Topological) Preference() ids.ID { return ts.tail }
 // Runtime = 3 * |live set| + |votes|
 // Space = |live set| + |votes|
 func (ts *Topological) RecordPoll(votes ids.Bag) {
+	ts.numPolls++
+
 	// Runtime = |live set| + |votes| ; Space = |live set| + |votes|
 	kahnGraph, leaves := ts.calculateInDegree(votes)
 
@@ -182,11 +227,62 @@ func (ts *Topological) RecordPoll(votes ids.Bag) {
 	}
 
 	ts.tail = tn.blkID
+	ts.updateProcessingDepth()
+
+	// The votes of the kahn nodes are no longer needed
+	for _, kahn := range kahnGraph {
+		kahn.votes.Release()
+	}
+}
+
+// updateProcessingDepth sets the processing depth to the number of blocks from
+// the last accepted block to the preferred block
+func (ts *Topological) updateProcessingDepth() {
+	depth := 0
+	for n := ts.nodes[ts.tail.Key()]; n.blk != nil && !n.blkID.Equals(ts.head); n = ts.nodes[n.blk.Parent().ID().Key()] {
+		depth++
+	}
+	ts.processingDepth.Set(float64(depth))
 }
 
 // Finalized implements the Snowman interface
 func (ts *Topological) Finalized() bool { return len(ts.nodes) == 1 }
 
+// Graph implements the Snowman interface
+func (ts *Topological) Graph() snow.Graph {
+	// The preferred blocks are those from the last accepted block to the tail
+	preferred := ids.Set{}
+	for n := ts.nodes[ts.tail.Key()]; n.blk != nil && !n.blkID.Equals(ts.head); n = ts.nodes[n.blk.Parent().ID().Key()] {
+		preferred.Add(n.blkID)
+	}
+
+	graph := snow.Graph{}
+	for _, n := range ts.nodes {
+		node := snow.GraphNode{
+			ID:        n.blkID,
+			Kind:      snow.BlockNode,
+			Status:    choices.Accepted,
+			Preferred: true,
+		}
+		// The last accepted block's parent is no longer tracked
+		if !n.blkID.Equals(ts.head) {
+			node.Status = n.blk.Status()
+			node.Preferred = preferred.Contains(n.blkID)
+			graph.Edges = append(graph.Edges, snow.GraphEdge{
+				From: n.blkID,
+				To:   n.blk.Parent().ID(),
+				Kind: snow.ParentEdge,
+			})
+		}
+		if n.sb != nil {
+			node.Snowball = n.sb.String()
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	graph.Sort()
+	return graph
+}
+
 // takes in a list of votes and sets up the topological ordering. Returns the
 // reachable section of the graph annotated with the number of inbound edges and
 // the non-transitively applied votes. Also returns the list of leaf nodes.
@@ -376,6 +472,10 @@ func (ts *Topological) accept(n node) {
 
 	ts.head = pref
 	child := n.children[pref.Key()]
+	if childNode, ok := ts.nodes[pref.Key()]; ok {
+		ts.acceptLatency.Observe(float64(time.Since(childNode.added)) / float64(time.Millisecond))
+		ts.acceptPolls.Observe(float64(ts.numPolls - childNode.addedPolls))
+	}
 	ts.ctx.Log.Verbo("Accepting block with ID %s", child.ID())
 
 	bytes := child.Bytes()
@@ -29,4 +29,8 @@ func TestTopologicalIssuedTest(t *testing.T) { IssuedTest(t, TopologicalFactory{
 
 func TestTopologicalMetricsError(t *testing.T) { MetricsErrorTest(t, TopologicalFactory{}) }
 
+func TestTopologicalMetrics(t *testing.T) { MetricsTest(t, TopologicalFactory{}) }
+
 func TestTopologicalConsistent(t *testing.T) { ConsistentTest(t, TopologicalFactory{}) }
+
+func TestTopologicalGraph(t *testing.T) { GraphTest(t, TopologicalFactory{}) }
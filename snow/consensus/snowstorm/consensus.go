@@ -44,6 +44,10 @@ type Consensus interface {
 	// Returns the set of transactions conflicting with <Tx>
 	Conflicts(Tx) ids.Set
 
+	// Returns the confidence and bias of transaction <Tx>, which are 0 if
+	// <Tx> isn't being voted on
+	Confidence(Tx) (int, int)
+
 	// Collects the results of a network poll. Assumes all transactions
 	// have been previously added
 	RecordPoll(ids.Bag)
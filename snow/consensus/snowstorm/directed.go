@@ -250,6 +250,24 @@ func (dg *Directed) Issued(tx Tx) bool {
 	return ok
 }
 
+// Confidence implements the Consensus interface
+func (dg *Directed) Confidence(tx Tx) (int, int) {
+	fn, exists := dg.nodes[tx.ID().Key()]
+	if !exists {
+		return 0, 0
+	}
+	return dg.confidence(fn), fn.bias
+}
+
+// confidence returns the confidence of [fn], which is reset if [fn] wasn't
+// voted for in the last poll
+func (dg *Directed) confidence(fn *flatNode) int {
+	if fn.lastVote != dg.currentVote {
+		return 0
+	}
+	return fn.confidence
+}
+
 // Virtuous implements the Consensus interface
 func (dg *Directed) Virtuous() ids.Set { return dg.virtuous }
 
@@ -322,12 +340,8 @@ func (dg *Directed) String() string {
 		formatting.IntFormat(dg.params.BetaRogue-1))
 
 	for i, fn := range nodes {
-		confidence := fn.confidence
-		if fn.lastVote != dg.currentVote {
-			confidence = 0
-		}
 		sb.WriteString(fmt.Sprintf(format,
-			i, fn.tx.ID(), confidence, fn.bias))
+			i, fn.tx.ID(), dg.confidence(fn), fn.bias))
 	}
 
 	if len(nodes) > 0 {
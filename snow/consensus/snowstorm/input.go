@@ -408,6 +408,37 @@ func (ig *Input) removeConflict(id ids.ID, inputIDs ...ids.ID) {
 	}
 }
 
+// Confidence implements the ConflictGraph interface
+func (ig *Input) Confidence(tx Tx) (int, int) {
+	tn, exists := ig.txs[tx.ID().Key()]
+	if !exists {
+		return 0, 0
+	}
+	return ig.confidence(tn), tn.bias
+}
+
+// confidence returns the confidence of [tn], which is the lowest confidence of
+// its inputs, or 0 if one of its inputs wasn't voted for in the last poll or
+// prefers another transaction
+func (ig *Input) confidence(tn txNode) int {
+	id := tn.tx.ID()
+	confidence := ig.params.BetaRogue
+	for _, inputID := range tn.tx.InputIDs().List() {
+		input := ig.inputs[inputID.Key()]
+		if input.lastVote != ig.currentVote {
+			return 0
+		}
+
+		if input.confidence < confidence {
+			confidence = input.confidence
+		}
+		if !id.Equals(input.color) {
+			return 0
+		}
+	}
+	return confidence
+}
+
 // Quiesce implements the ConflictGraph interface
 func (ig *Input) Quiesce() bool {
 	numVirtuous := ig.virtuousVoting.Len()
@@ -425,29 +456,10 @@ func (ig *Input) Finalized() bool {
 func (ig *Input) String() string {
 	nodes := []tempNode{}
 	for _, tx := range ig.txs {
-		id := tx.tx.ID()
-
-		confidence := ig.params.BetaRogue
-		for _, inputID := range tx.tx.InputIDs().List() {
-			input := ig.inputs[inputID.Key()]
-			if input.lastVote != ig.currentVote {
-				confidence = 0
-				break
-			}
-
-			if input.confidence < confidence {
-				confidence = input.confidence
-			}
-			if !id.Equals(input.color) {
-				confidence = 0
-				break
-			}
-		}
-
 		nodes = append(nodes, tempNode{
-			id:         id,
+			id:         tx.tx.ID(),
 			bias:       tx.bias,
-			confidence: confidence,
+			confidence: ig.confidence(tx),
 		})
 	}
 	sortTempNodes(nodes)
@@ -8,10 +8,14 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Callable ...
@@ -34,6 +38,19 @@ type AliasLookup interface {
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
 // [NodeID] is the ID of this node
+// [Namespace] is the metrics namespace of this chain
+// [Metrics] registers metrics reported by this chain, it may be nil
+// [Hooks] are side effects that run when this chain decides a container or
+// finishes bootstrapping
+// [SharedMemory] passes messages between this chain and the other chains on
+// this node
+// [Limits] are the maximum sizes of the containers this chain issues and
+// accepts
+// [Misbehavior] collects evidence of validators misbehaving on this chain
+// [Clock] is the clock this chain runs by, which may run faster than real time
+// on test networks
+// [Source] is the source of randomness this chain's consensus samples from. If
+// it's seeded, sampling is reproducible. If it's nil, the global source is used.
 type Context struct {
 	NetworkID           uint32
 	ChainID             ids.ID
@@ -45,6 +62,18 @@ type Context struct {
 	HTTP                Callable
 	Keystore            Keystore
 	BCLookup            AliasLookup
+	Namespace           string
+	Metrics             prometheus.Registerer
+	Hooks               Hooks
+	SharedMemory        SharedMemory
+	Limits              Limits
+	Misbehavior         Misbehavior
+	Clock               timer.Clock
+	Source              random.Source
+
+	// Config is this node's configuration of the chain, or nil if the chain
+	// isn't configured. Its format is defined by the chain's VM.
+	Config []byte
 }
 
 // DefaultContextTest ...
@@ -60,5 +89,7 @@ func DefaultContextTest() *Context {
 		DecisionDispatcher:  &decisionED,
 		ConsensusDispatcher: &consensusED,
 		BCLookup:            &ids.Aliaser{},
+		Limits:              DefaultLimits,
+		Source:              random.NewSource(0),
 	}
 }
@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+const (
+	// acceptedCacheSize is the number of recently accepted vertices that are
+	// kept in memory to serve requests from peers
+	acceptedCacheSize = 2048
+
+	// acceptedCacheID identifies the accepted cache in the consensus
+	// dispatcher
+	acceptedCacheID = "engineAcceptedCache"
+)
+
+type acceptedVertex struct {
+	bytes []byte
+
+	// parentIDs are only known once the vertex has been loaded from the state
+	parentsKnown bool
+	parentIDs    []ids.ID
+}
+
+// acceptedCache maps recently accepted vertices to their bytes and parents, so
+// that serving bootstrapping peers doesn't require hitting the database. It's
+// filled as vertices are accepted by consensus and as accepted vertices are
+// served.
+type acceptedCache struct{ vtxs cache.LRU }
+
+func (c *acceptedCache) init() { c.vtxs.Size = acceptedCacheSize }
+
+// Accept implements the triggers.Acceptor interface. The parents of the vertex
+// are filled in the first time it's served as an ancestor.
+func (c *acceptedCache) Accept(_, vtxID ids.ID, vtxBytes []byte) error {
+	c.vtxs.Put(vtxID, &acceptedVertex{bytes: vtxBytes})
+	return nil
+}
+
+// get returns the bytes, and possibly the parents, of the vertex if it was
+// recently accepted
+func (c *acceptedCache) get(vtxID ids.ID) (*acceptedVertex, bool) {
+	if vtx, ok := c.vtxs.Get(vtxID); ok {
+		return vtx.(*acceptedVertex), true
+	}
+	return nil, false
+}
+
+// add caches [vtx] if it's accepted and returns its bytes
+func (c *acceptedCache) add(vtx avalanche.Vertex) []byte {
+	vtxBytes := vtx.Bytes()
+	if vtx.Status() == choices.Accepted {
+		c.put(vtx.ID(), vtxBytes, vtx.Parents())
+	}
+	return vtxBytes
+}
+
+func (c *acceptedCache) put(vtxID ids.ID, vtxBytes []byte, parents []avalanche.Vertex) {
+	parentIDs := make([]ids.ID, len(parents))
+	for i, parent := range parents {
+		parentIDs[i] = parent.ID()
+	}
+	c.vtxs.Put(vtxID, &acceptedVertex{
+		bytes:        vtxBytes,
+		parentsKnown: true,
+		parentIDs:    parentIDs,
+	})
+}
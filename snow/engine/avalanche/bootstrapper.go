@@ -5,6 +5,7 @@ package avalanche
 
 import (
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/common"
@@ -23,6 +24,8 @@ type BootstrapConfig struct {
 
 	State State
 	VM    DAGVM
+
+	Bootstrapped func()
 }
 
 type bootstrapper struct {
@@ -30,7 +33,10 @@ type bootstrapper struct {
 	metrics
 	common.Bootstrapper
 
-	pending    ids.Set
+	// outstandingRequests tracks which validators were asked for which
+	// vertices in which requests
+	outstandingRequests common.Requests
+
 	finished   bool
 	onFinished func()
 }
@@ -79,38 +85,77 @@ func (b *bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) {
 		b.fetch(vtxID)
 	}
 
-	if numPending := b.pending.Len(); numPending == 0 {
+	if numPending := b.outstandingRequests.Len(); numPending == 0 {
 		// TODO: This typically indicates bootstrapping has failed, so this
 		// should be handled appropriately
 		b.finish()
 	}
 }
 
-// Put ...
-func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxBytes []byte) {
-	b.BootstrapConfig.Context.Log.Verbo("Put called for vertexID %s", vtxID)
+// MultiPut ...
+func (b *bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	// Make sure this is in response to a request we made
+	wantedVtxID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("received unexpected MultiPut from %s with ID %d", vdr, requestID)
+		return
+	}
 
-	if !b.pending.Contains(vtxID) {
+	if len(vtxs) == 0 {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains no vertices", vdr, requestID)
+		b.sendRequest(wantedVtxID)
 		return
 	}
+	if len(vtxs) > common.MaxContainersPerMultiPut {
+		vtxs = vtxs[:common.MaxContainersPerMultiPut]
+	}
 
-	vtx, err := b.State.ParseVertex(vtxBytes)
+	// The first vertex must be the one we asked for
+	wantedVtx, err := b.State.ParseVertex(vtxs[0])
 	if err != nil {
-		b.BootstrapConfig.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
+		b.BootstrapConfig.Context.Log.Debug("ParseVertex failed due to %s for vertex:\n%s",
 			err,
-			formatting.DumpBytes{Bytes: vtxBytes})
-		b.GetFailed(vdr, requestID, vtxID)
+			formatting.DumpBytes{Bytes: vtxs[0]})
+		b.BootstrapConfig.Context.ReportMisbehavior(vdr, snow.InvalidContainer, vtxs[0], wantedVtxID)
+		b.sendRequest(wantedVtxID)
+		return
+	}
+	if !wantedVtx.ID().Equals(wantedVtxID) {
+		b.BootstrapConfig.Context.Log.Debug("expected the first vertex of MultiPut(%s, %d) to be %s but was %s",
+			vdr, requestID, wantedVtxID, wantedVtx.ID())
+		b.BootstrapConfig.Context.ReportMisbehavior(vdr, snow.MismatchedContainer, vtxs[0], wantedVtxID, wantedVtx.ID())
+		b.sendRequest(wantedVtxID)
 		return
 	}
 
-	b.addVertex(vtx)
+	// The rest are the ancestors of the vertex we asked for
+	ancestors := make(map[[32]byte]avalanche.Vertex, len(vtxs)-1)
+	for _, vtxBytes := range vtxs[1:] {
+		vtx, err := b.State.ParseVertex(vtxBytes)
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("ParseVertex failed due to %s for vertex:\n%s",
+				err,
+				formatting.DumpBytes{Bytes: vtxBytes})
+			break
+		}
+		ancestors[vtx.ID().Key()] = vtx
+	}
+
+	b.addVertex(wantedVtx, ancestors)
 }
 
-// GetFailed ...
-func (b *bootstrapper) GetFailed(_ ids.ShortID, _ uint32, vtxID ids.ID) { b.sendRequest(vtxID) }
+// GetAncestorsFailed ...
+func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	vtxID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+		return
+	}
+	b.sendRequest(vtxID)
+}
 
 func (b *bootstrapper) fetch(vtxID ids.ID) {
-	if b.pending.Contains(vtxID) {
+	if b.outstandingRequests.Contains(vtxID) {
 		return
 	}
 
@@ -119,11 +164,11 @@ func (b *bootstrapper) fetch(vtxID ids.ID) {
 		b.sendRequest(vtxID)
 		return
 	}
-	b.addVertex(vtx)
+	b.addVertex(vtx, nil)
 }
 
 func (b *bootstrapper) sendRequest(vtxID ids.ID) {
-	validators := b.BootstrapConfig.Validators.Sample(1)
+	validators := b.BootstrapConfig.Validators.SampleFrom(1, b.BootstrapConfig.Context.Source)
 	if len(validators) == 0 {
 		b.BootstrapConfig.Context.Log.Error("Dropping request for %s as there are no validators", vtxID)
 		return
@@ -131,13 +176,16 @@ func (b *bootstrapper) sendRequest(vtxID ids.ID) {
 	validatorID := validators[0].ID()
 	b.RequestID++
 
-	b.pending.Add(vtxID)
-	b.BootstrapConfig.Sender.Get(validatorID, b.RequestID, vtxID)
+	b.outstandingRequests.Add(validatorID, b.RequestID, vtxID)
+	b.BootstrapConfig.Sender.GetAncestors(validatorID, b.RequestID, vtxID)
 
-	b.numPendingRequests.Set(float64(b.pending.Len()))
+	b.numPendingRequests.Set(float64(b.outstandingRequests.Len()))
 }
 
-func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
+// addVertex queues [vtx] and its processing ancestors to be accepted.
+// Ancestors this node doesn't have are taken from [ancestors] if they're there,
+// and requested otherwise.
+func (b *bootstrapper) addVertex(vtx avalanche.Vertex, ancestors map[[32]byte]avalanche.Vertex) {
 	vts := []avalanche.Vertex{vtx}
 
 	for len(vts) > 0 {
@@ -146,11 +194,18 @@ func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
 		vts = vts[:newLen]
 
 		vtxID := vtx.ID()
-		switch status := vtx.Status(); status {
+		status := vtx.Status()
+		if ancestor, ok := ancestors[vtxID.Key()]; ok && status == choices.Unknown {
+			vtx = ancestor
+			status = vtx.Status()
+		}
+		switch status {
 		case choices.Unknown:
-			b.sendRequest(vtxID)
+			if !b.outstandingRequests.Contains(vtxID) {
+				b.sendRequest(vtxID)
+			}
 		case choices.Processing:
-			b.pending.Remove(vtxID)
+			b.outstandingRequests.RemoveAny(vtxID)
 
 			if err := b.VtxBlocked.Push(&vertexJob{
 				numAccepted: b.numBootstrappedVtx,
@@ -179,7 +234,7 @@ func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
 		}
 	}
 
-	numPending := b.pending.Len()
+	numPending := b.outstandingRequests.Len()
 	b.numPendingRequests.Set(float64(numPending))
 	if numPending == 0 {
 		b.finish()
@@ -197,6 +252,10 @@ func (b *bootstrapper) finish() {
 	// Start consensus
 	b.onFinished()
 	b.finished = true
+
+	if b.Bootstrapped != nil {
+		b.Bootstrapped()
+	}
 }
 
 func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge) {
@@ -133,7 +133,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 
 	vtxIDToReqID := map[[32]byte]uint32{}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -153,7 +153,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if numReqs := len(vtxIDToReqID); numReqs != 3 {
 		t.Fatalf("Should have requested %d vertices, %d were requested", 3, numReqs)
@@ -196,17 +196,19 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
+	bootstrapped := new(bool)
+	bs.Bootstrapped = func() { *bootstrapped = true }
 
 	for vtxKey, reqID := range vtxIDToReqID {
 		vtxID := ids.NewID(vtxKey)
 
 		switch {
 		case vtxID.Equals(vtxID0):
-			bs.Put(peerID, reqID, vtxID, vtxBytes0)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes0})
 		case vtxID.Equals(vtxID1):
-			bs.Put(peerID, reqID, vtxID, vtxBytes1)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes1})
 		case vtxID.Equals(vtxID2):
-			bs.Put(peerID, reqID, vtxID, vtxBytes2)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes2})
 		default:
 			t.Fatalf("Requested unknown vertex")
 		}
@@ -219,6 +221,9 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	if !*finished {
 		t.Fatalf("Bootstrapping should have finished")
 	}
+	if !*bootstrapped {
+		t.Fatalf("Bootstrapped callback should have been called")
+	}
 	if vtx0.Status() != choices.Accepted {
 		t.Fatalf("Vertex should be accepted")
 	}
@@ -272,7 +277,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -288,7 +293,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -316,8 +321,26 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, vtxID1, vtxBytes1)
-	bs.Put(peerID, *requestID, vtxID0, vtxBytes0)
+	// A response that doesn't start with the requested vertex is dropped, and
+	// the vertex is requested again
+	oldReqID := *requestID
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		if !vtxID.Equals(vtxID0) {
+			t.Fatalf("Should have requested vertex %s again, requested %s", vtxID0, vtxID)
+		}
+		*requestID = reqID
+	}
+	bs.MultiPut(peerID, *requestID, [][]byte{vtxBytes1})
+	sender.GetAncestorsF = nil
+	if *requestID == oldReqID {
+		t.Fatalf("Should have requested vertex %s again", vtxID0)
+	}
+
+	bs.MultiPut(peerID, oldReqID, [][]byte{vtxBytes0})
+	if *finished {
+		t.Fatalf("Shouldn't have accepted a response to an abandoned request")
+	}
+	bs.MultiPut(peerID, *requestID, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	state.edge = nil
@@ -377,7 +400,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -393,7 +416,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -403,7 +426,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -416,10 +439,10 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if vtx0.Status() != choices.Unknown {
 		t.Fatalf("Vertex should be unknown")
@@ -463,7 +486,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
@@ -551,7 +574,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -567,7 +590,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -577,7 +600,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -590,10 +613,10 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if tx0.Status() != choices.Processing {
 		t.Fatalf("Tx should be processing")
@@ -655,7 +678,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
@@ -747,7 +770,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -763,7 +786,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -773,7 +796,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -786,10 +809,10 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if tx0.Status() != choices.Unknown {
 		t.Fatalf("Tx should be unknown")
@@ -847,7 +870,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
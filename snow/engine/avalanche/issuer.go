@@ -29,6 +29,7 @@ func (i *issuer) Abandon() {
 	if !i.abandoned {
 		vtxID := i.vtx.ID()
 		i.t.pending.Remove(vtxID)
+		i.t.orphans.Remove(vtxID)
 		i.abandoned = true
 
 		i.t.vtxBlocked.Abandon(vtxID)
@@ -57,7 +58,7 @@ func (i *issuer) Update() {
 	i.t.Consensus.Add(i.vtx)
 
 	p := i.t.Consensus.Parameters()
-	vdrs := i.t.Config.Validators.Sample(p.K) // Validators to sample
+	vdrs := i.t.Config.Validators.SampleFrom(p.K, i.t.Config.Context.Source) // Validators to sample
 
 	vdrSet := ids.ShortSet{} // Validators to sample repr. as a set
 	for _, vdr := range vdrs {
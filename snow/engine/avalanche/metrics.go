@@ -14,7 +14,7 @@ type metrics struct {
 	numBootstrappedVtx, numDroppedVtx,
 	numBootstrappedTx, numDroppedTx prometheus.Counter
 
-	numPolls, numVtxRequests, numTxRequests, numPendingVtx prometheus.Gauge
+	numPolls, numVtxRequests, numTxRequests, numPendingVtx, numOrphanVtx prometheus.Gauge
 }
 
 // Initialize implements the Engine interface
@@ -85,6 +85,12 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "av_blocked_vts",
 			Help:      "Number of blocked vertices",
 		})
+	m.numOrphanVtx = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_orphan_vts",
+			Help:      "Number of vertices waiting on ancestors that haven't been fetched",
+		})
 
 	if err := registerer.Register(m.numPendingRequests); err != nil {
 		log.Error("Failed to register av_bs_vtx_requests statistics due to %s", err)
@@ -119,4 +125,7 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 	if err := registerer.Register(m.numPendingVtx); err != nil {
 		log.Error("Failed to register av_blocked_vts statistics due to %s", err)
 	}
+	if err := registerer.Register(m.numOrphanVtx); err != nil {
+		log.Error("Failed to register av_orphan_vts statistics due to %s", err)
+	}
 }
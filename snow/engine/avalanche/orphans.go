@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"container/list"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// maxOrphans is the maximum number of vertices that can be waiting on
+// ancestors that haven't been fetched
+const maxOrphans = 1024
+
+type orphan struct {
+	i       *issuer
+	missing ids.Set       // Ancestors of this vertex that haven't been fetched
+	element *list.Element // This vertex's position in the arrival order
+}
+
+// orphans tracks the pending vertices that arrived before their parents. The
+// pool is bounded; when it's full, the orphan that arrived first is evicted.
+type orphans struct {
+	max     int
+	vtxs    map[[32]byte]*orphan
+	waiting map[[32]byte]ids.Set // Maps a missing ancestor to the orphans waiting on it
+	order   *list.List           // Orphan IDs in the order they arrived
+}
+
+func (o *orphans) init() {
+	if o.vtxs == nil {
+		o.vtxs = make(map[[32]byte]*orphan)
+		o.waiting = make(map[[32]byte]ids.Set)
+		o.order = list.New()
+	}
+}
+
+// Len returns the number of orphans in the pool
+func (o *orphans) Len() int { return len(o.vtxs) }
+
+// Add [i]'s vertex to the pool as waiting on [missing]. If the pool is full,
+// the issuer of the evicted orphan is returned.
+func (o *orphans) Add(i *issuer, missing ids.Set) *issuer {
+	o.init()
+
+	vtxID := i.vtx.ID()
+	key := vtxID.Key()
+	if existing, exists := o.vtxs[key]; exists {
+		for _, ancestorID := range missing.List() {
+			if !existing.missing.Contains(ancestorID) {
+				existing.missing.Add(ancestorID)
+				o.wait(ancestorID, vtxID)
+			}
+		}
+		return nil
+	}
+
+	o.vtxs[key] = &orphan{
+		i:       i,
+		missing: missing,
+		element: o.order.PushBack(vtxID),
+	}
+	for _, ancestorID := range missing.List() {
+		o.wait(ancestorID, vtxID)
+	}
+
+	if o.Len() <= o.max {
+		return nil
+	}
+	oldestID := o.order.Front().Value.(ids.ID)
+	evicted := o.vtxs[oldestID.Key()].i
+	o.Remove(oldestID)
+	return evicted
+}
+
+// Fetched marks [ancestorID] as no longer missing. Orphans that aren't missing
+// any other ancestors are removed from the pool.
+func (o *orphans) Fetched(ancestorID ids.ID) {
+	o.init()
+
+	ancestorKey := ancestorID.Key()
+	waiting := o.waiting[ancestorKey]
+	delete(o.waiting, ancestorKey)
+
+	for _, vtxID := range waiting.List() {
+		orphan := o.vtxs[vtxID.Key()]
+		orphan.missing.Remove(ancestorID)
+		if orphan.missing.Len() == 0 {
+			o.Remove(vtxID)
+		}
+	}
+}
+
+// Remove [vtxID] from the pool, if it's there
+func (o *orphans) Remove(vtxID ids.ID) {
+	o.init()
+
+	key := vtxID.Key()
+	orphan, exists := o.vtxs[key]
+	if !exists {
+		return
+	}
+	delete(o.vtxs, key)
+	o.order.Remove(orphan.element)
+
+	for _, ancestorID := range orphan.missing.List() {
+		ancestorKey := ancestorID.Key()
+		waiting := o.waiting[ancestorKey]
+		waiting.Remove(vtxID)
+		if waiting.Len() == 0 {
+			delete(o.waiting, ancestorKey)
+		} else {
+			o.waiting[ancestorKey] = waiting
+		}
+	}
+}
+
+func (o *orphans) wait(ancestorID, vtxID ids.ID) {
+	ancestorKey := ancestorID.Key()
+	waiting := o.waiting[ancestorKey]
+	waiting.Add(vtxID)
+	o.waiting[ancestorKey] = waiting
+}
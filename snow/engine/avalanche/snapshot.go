@@ -0,0 +1,179 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var (
+	errSnapshotLink = errors.New("snapshot vertex references a vertex that isn't accepted")
+	errSnapshotTxs  = errors.New("snapshot has transactions whose dependencies aren't in it")
+)
+
+// ExportSnapshot writes the vertices [state] accepted to [w], each after its
+// parents, and returns how many were written. Assumes the chain's context lock
+// is held.
+func ExportSnapshot(state State, w *common.SnapshotWriter) (int, error) {
+	// Vertices are written in post-order, from the accepted frontier, so that
+	// each vertex is written after its parents
+	type frame struct {
+		vtx      avalanche.Vertex
+		expanded bool
+	}
+	stack := []frame(nil)
+	for _, vtxID := range state.Edge() {
+		vtx, err := state.GetVertex(vtxID)
+		if err != nil {
+			return 0, err
+		}
+		stack = append(stack, frame{vtx: vtx})
+	}
+
+	written := 0
+	visited := ids.Set{}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		vtxID := top.vtx.ID()
+		if top.expanded {
+			if err := w.Write(top.vtx.Bytes()); err != nil {
+				return written, err
+			}
+			written++
+			continue
+		}
+		if visited.Contains(vtxID) {
+			continue
+		}
+		visited.Add(vtxID)
+
+		stack = append(stack, frame{vtx: top.vtx, expanded: true})
+		for _, parent := range top.vtx.Parents() {
+			if !visited.Contains(parent.ID()) && parent.Status() == choices.Accepted {
+				stack = append(stack, frame{vtx: parent})
+			}
+		}
+	}
+	return written, nil
+}
+
+// ImportSnapshot accepts the vertices, and their transactions, of the snapshot
+// [r], and returns how many vertices were accepted. The parents of each vertex
+// must be accepted, or be earlier in the snapshot. As when bootstrapping, a
+// vertex is accepted once its parents and transactions are, and each
+// transaction is verified before it's accepted. Assumes the chain's context
+// lock is held.
+func ImportSnapshot(state State, r *common.SnapshotReader) (int, error) {
+	accepted := 0
+	// pending are the vertices of the snapshot that can't be accepted until
+	// transactions of later vertices are
+	pending := []avalanche.Vertex(nil)
+	pendingIDs := ids.Set{}
+	for {
+		vtxBytes, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return accepted, err
+		}
+
+		vtx, err := state.ParseVertex(vtxBytes)
+		if err != nil {
+			return accepted, fmt.Errorf("couldn't parse snapshot vertex %d: %w", r.Count(), err)
+		}
+		if vtx.Status() == choices.Accepted {
+			continue
+		}
+		for _, parent := range vtx.Parents() {
+			if parent.Status() != choices.Accepted && !pendingIDs.Contains(parent.ID()) {
+				return accepted, fmt.Errorf("%w: %s's parent %s", errSnapshotLink, vtx.ID(), parent.ID())
+			}
+		}
+		pending = append(pending, vtx)
+		pendingIDs.Add(vtx.ID())
+
+		// Accepting a vertex may allow vertices before it to be accepted
+		for progress := true; progress; {
+			progress = false
+			remaining := pending[:0]
+			for _, vtx := range pending {
+				done, err := tryAccept(vtx)
+				if err != nil {
+					return accepted, fmt.Errorf("snapshot vertex %s is invalid: %w", vtx.ID(), err)
+				}
+				if !done {
+					remaining = append(remaining, vtx)
+					continue
+				}
+				pendingIDs.Remove(vtx.ID())
+				accepted++
+				progress = true
+			}
+			pending = remaining
+		}
+	}
+	if len(pending) > 0 {
+		return accepted, fmt.Errorf("%w: %d vertices weren't accepted", errSnapshotTxs, len(pending))
+	}
+	return accepted, nil
+}
+
+// tryAccept verifies and accepts the transactions of [vtx] whose dependencies
+// are accepted, and then accepts [vtx] if its parents and transactions are
+// accepted. A transaction may depend on transactions later in the vertex.
+// Returns true if [vtx] was accepted.
+func tryAccept(vtx avalanche.Vertex) (bool, error) {
+	for _, parent := range vtx.Parents() {
+		if parent.Status() != choices.Accepted {
+			return false, nil
+		}
+	}
+
+	pending := vtx.Txs()
+	for len(pending) > 0 {
+		remaining := []snowstorm.Tx(nil)
+		for _, tx := range pending {
+			switch tx.Status() {
+			case choices.Accepted:
+				continue
+			case choices.Rejected:
+				return false, fmt.Errorf("transaction %s was rejected", tx.ID())
+			}
+			if !dependenciesAccepted(tx) {
+				remaining = append(remaining, tx)
+				continue
+			}
+			if err := tx.Verify(); err != nil {
+				return false, fmt.Errorf("transaction %s is invalid: %w", tx.ID(), err)
+			}
+			tx.Accept()
+		}
+		if len(remaining) == len(pending) {
+			return false, nil
+		}
+		pending = remaining
+	}
+	vtx.Accept()
+	return true, nil
+}
+
+// dependenciesAccepted returns true if the dependencies of [tx] are accepted
+func dependenciesAccepted(tx snowstorm.Tx) bool {
+	for _, dep := range tx.Dependencies() {
+		if dep.Status() != choices.Accepted {
+			return false
+		}
+	}
+	return true
+}
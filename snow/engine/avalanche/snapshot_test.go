@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainID := ids.Empty.Prefix(100)
+
+	// newDAG returns the vertices of a DAG whose genesis vertex is accepted,
+	// and whose other vertices, and their transactions, have the status
+	// [status]. The last vertex is the only vertex of the frontier.
+	newDAG := func(status choices.Status) []*Vtx {
+		tx0 := &snowstorm.TestTx{Identifier: ids.Empty.Prefix(10), Stat: status}
+		tx1 := &snowstorm.TestTx{Identifier: ids.Empty.Prefix(11), Stat: status, Deps: []snowstorm.Tx{tx0}}
+		tx2 := &snowstorm.TestTx{Identifier: ids.Empty.Prefix(12), Stat: status}
+		// tx3 depends on a transaction after it in the same vertex
+		tx3 := &snowstorm.TestTx{Identifier: ids.Empty.Prefix(13), Stat: status, Deps: []snowstorm.Tx{tx2}}
+
+		vtx0 := &Vtx{id: ids.Empty.Prefix(0), status: choices.Accepted, bytes: []byte{0}}
+		vtx1 := &Vtx{
+			parents: []avalanche.Vertex{vtx0},
+			id:      ids.Empty.Prefix(1),
+			txs:     []snowstorm.Tx{tx0},
+			height:  1,
+			status:  status,
+			bytes:   []byte{1},
+		}
+		vtx2 := &Vtx{
+			parents: []avalanche.Vertex{vtx0},
+			id:      ids.Empty.Prefix(2),
+			txs:     []snowstorm.Tx{tx1},
+			height:  1,
+			status:  status,
+			bytes:   []byte{2},
+		}
+		vtx3 := &Vtx{
+			parents: []avalanche.Vertex{vtx1, vtx2},
+			id:      ids.Empty.Prefix(3),
+			txs:     []snowstorm.Tx{tx3, tx2},
+			height:  2,
+			status:  status,
+			bytes:   []byte{3},
+		}
+		return []*Vtx{vtx0, vtx1, vtx2, vtx3}
+	}
+	newState := func(vtxs []*Vtx) *stateTest {
+		state := &stateTest{t: t}
+		state.Default(true)
+		state.edge = func() []ids.ID { return []ids.ID{vtxs[len(vtxs)-1].ID()} }
+		state.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+			for _, vtx := range vtxs {
+				if vtx.ID().Equals(vtxID) {
+					return vtx, nil
+				}
+			}
+			return nil, errUnknownVertex
+		}
+		state.parseVertex = func(b []byte) (avalanche.Vertex, error) {
+			for _, vtx := range vtxs {
+				if bytes.Equal(vtx.Bytes(), b) {
+					return vtx, nil
+				}
+			}
+			return nil, errUnknownVertex
+		}
+		return state
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := common.NewSnapshotWriter(buf, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ExportSnapshot(newState(newDAG(choices.Accepted)), w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 4 {
+		t.Fatalf("expected 4 vertices to be exported but %d were", exported)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := buf.Bytes()
+
+	// The vertices, and their transactions, are accepted on a fresh chain
+	fresh := newDAG(choices.Processing)
+	r, err := common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportSnapshot(newState(fresh), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 3 {
+		t.Fatalf("expected 3 vertices to be imported but %d were", imported)
+	}
+	for _, vtx := range fresh {
+		if vtx.Status() != choices.Accepted {
+			t.Fatalf("vertex %s should have been accepted", vtx.ID())
+		}
+		for _, tx := range vtx.Txs() {
+			if tx.Status() != choices.Accepted {
+				t.Fatalf("transaction %s should have been accepted", tx.ID())
+			}
+		}
+	}
+
+	// A vertex whose parent isn't accepted isn't accepted
+	broken := newDAG(choices.Processing)
+	broken[1].parents = []avalanche.Vertex{&Vtx{id: ids.Empty.Prefix(300), status: choices.Processing}}
+	r, err = common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportSnapshot(newState(broken), r); err == nil {
+		t.Fatalf("shouldn't have imported a vertex whose parent isn't accepted")
+	}
+	if broken[1].Status() == choices.Accepted || broken[3].Status() == choices.Accepted {
+		t.Fatalf("shouldn't have accepted a vertex whose parent isn't accepted")
+	}
+}
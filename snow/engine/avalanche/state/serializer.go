@@ -28,8 +28,9 @@ const (
 )
 
 var (
-	errUnknownVertex = errors.New("unknown vertex")
-	errWrongChainID  = errors.New("wrong ChainID in vertex")
+	errUnknownVertex  = errors.New("unknown vertex")
+	errWrongChainID   = errors.New("wrong ChainID in vertex")
+	errVertexTooLarge = errors.New("vertex is larger than the maximum vertex size")
 )
 
 // Serializer manages the state of multiple vertices
@@ -61,6 +62,9 @@ func (s *Serializer) Initialize(ctx *snow.Context, vm avaeng.DAGVM, db database.
 
 // ParseVertex implements the avalanche.State interface
 func (s *Serializer) ParseVertex(b []byte) (avacon.Vertex, error) {
+	if len(b) > s.ctx.Limits.MaxVertexSize {
+		return nil, errVertexTooLarge
+	}
 	vtx, err := s.parseVertex(b)
 	if err != nil {
 		return nil, err
@@ -102,7 +106,7 @@ func (s *Serializer) BuildVertex(parentSet ids.Set, txs []snowstorm.Tx) (avacon.
 		txs:       txs,
 	}
 
-	bytes, err := vtx.Marshal()
+	bytes, err := vtx.Marshal(s.ctx.Limits.MaxVertexSize)
 	if err != nil {
 		return nil, err
 	}
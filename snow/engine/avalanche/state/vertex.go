@@ -16,9 +16,6 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
-// maxSize is the maximum allowed vertex size. It is necessary to deter DoS.
-const maxSize = 1 << 20
-
 var (
 	errBadCodec       = errors.New("invalid codec")
 	errExtraSpace     = errors.New("trailing buffer space")
@@ -66,8 +63,9 @@ func (vtx *vertex) Verify() error {
  *     Tx       | ?? bytes
  */
 
-// Marshal creates the byte representation of the vertex
-func (vtx *vertex) Marshal() ([]byte, error) {
+// Marshal creates the byte representation of the vertex, which may be at most
+// [maxSize] bytes long
+func (vtx *vertex) Marshal(maxSize int) ([]byte, error) {
 	p := wrappers.Packer{MaxSize: maxSize}
 
 	p.PackInt(uint32(CustomID))
@@ -11,9 +11,16 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
+// vertexHeaderLen is the number of bytes of a vertex, other than its parent
+// IDs and transactions: its codec, chain ID, height and the lengths of its
+// parent IDs and transactions
+const vertexHeaderLen = wrappers.IntLen + hashing.HashLen + wrappers.LongLen + 2*wrappers.IntLen
+
 // Transitive implements the Engine interface by attempting to fetch all
 // transitive dependencies.
 type Transitive struct {
@@ -30,6 +37,13 @@ type Transitive struct {
 	// txBlocked tracks operations that are blocked on transactions
 	vtxBlocked, txBlocked events.Blocker
 
+	// orphans tracks pending vertices that are waiting on ancestors that
+	// haven't been fetched
+	orphans orphans
+
+	// acceptedCache keeps recently accepted vertices in memory to serve peers
+	acceptedCache acceptedCache
+
 	bootstrapped bool
 }
 
@@ -38,6 +52,16 @@ func (t *Transitive) Initialize(config Config) {
 	config.Context.Log.Info("Initializing Avalanche consensus")
 
 	t.Config = config
+	if vm, ok := config.VM.(ParentsConfigurableVM); ok {
+		if parents := vm.VertexParents(); parents > 0 {
+			t.Params.Parents = parents
+		}
+	}
+	t.acceptedCache.init()
+	if err := config.Context.ConsensusDispatcher.RegisterChain(config.Context.ChainID, acceptedCacheID, &t.acceptedCache); err != nil {
+		config.Context.Log.Warn("Accepted vertices won't be cached as they're accepted due to %s", err)
+	}
+	t.orphans.max = maxOrphans
 	t.metrics.Initialize(config.Context.Log, config.Params.Namespace, config.Params.Metrics)
 
 	t.onFinished = t.finishBootstrapping
@@ -60,11 +84,16 @@ func (t *Transitive) finishBootstrapping() {
 	}
 	t.Consensus.Initialize(t.Config.Context, t.Params, frontier)
 	t.bootstrapped = true
+	t.StartFrontierMonitor(t.Params.Namespace, t.Params.Metrics, t.sendRequest)
 }
 
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() {
 	t.Config.Context.Log.Info("Shutting down Avalanche consensus")
+	t.StopFrontierMonitor()
+	if err := t.Config.Context.ConsensusDispatcher.DeregisterChain(t.Config.Context.ChainID, acceptedCacheID); err != nil {
+		t.Config.Context.Log.Debug("Couldn't deregister the accepted cache due to %s", err)
+	}
 	t.Config.VM.Shutdown()
 }
 
@@ -73,9 +102,15 @@ func (t *Transitive) Context() *snow.Context { return t.Config.Context }
 
 // Get implements the Engine interface
 func (t *Transitive) Get(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	// If the requested vertex was recently accepted, serve it from memory
+	if vtx, ok := t.acceptedCache.get(vtxID); ok {
+		t.Config.Sender.Put(vdr, requestID, vtxID, vtx.bytes)
+		return
+	}
+
 	// If this engine has access to the requested vertex, provide it
 	if vtx, err := t.Config.State.GetVertex(vtxID); err == nil {
-		t.Config.Sender.Put(vdr, requestID, vtxID, vtx.Bytes())
+		t.Config.Sender.Put(vdr, requestID, vtxID, t.acceptedCache.add(vtx))
 	}
 }
 
@@ -84,7 +119,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 	t.Config.Context.Log.Verbo("Put called for vertexID %s", vtxID)
 
 	if !t.bootstrapped {
-		t.bootstrapper.Put(vdr, requestID, vtxID, vtxBytes)
+		t.Config.Context.Log.Debug("Dropping Put for %s due to bootstrapping", vtxID)
 		return
 	}
 
@@ -93,16 +128,20 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		t.Config.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
 			err,
 			formatting.DumpBytes{Bytes: vtxBytes})
+		t.Config.Context.ReportMisbehavior(vdr, snow.InvalidContainer, vtxBytes, vtxID)
 		t.GetFailed(vdr, requestID, vtxID)
 		return
 	}
+	if parsedID := vtx.ID(); !parsedID.Equals(vtxID) {
+		t.Config.Context.ReportMisbehavior(vdr, snow.MismatchedContainer, vtxBytes, vtxID, parsedID)
+	}
 	t.insertFrom(vdr, vtx)
 }
 
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
 	if !t.bootstrapped {
-		t.bootstrapper.GetFailed(vdr, requestID, vtxID)
+		t.Config.Context.Log.Debug("Dropping GetFailed for %s due to bootstrapping", vtxID)
 		return
 	}
 
@@ -121,6 +160,88 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 	t.numVtxRequests.Set(float64(t.vtxReqs.Len()))
 	t.numTxRequests.Set(float64(t.missingTxs.Len()))
 	t.numBlockedVtx.Set(float64(t.pending.Len()))
+	t.numOrphanVtx.Set(float64(t.orphans.Len()))
+}
+
+// GetAncestors implements the Engine interface. It sends the vertex and as many
+// of its ancestors, in breadth first order, as fit in one MultiPut message.
+// Recently accepted ancestors are served from memory.
+func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	// An ancestor is either a vertex that was loaded from the state, or the ID
+	// of a vertex that may still need to be loaded
+	type ancestor struct {
+		id  ids.ID
+		vtx avalanche.Vertex
+	}
+
+	ancestorsBytes := [][]byte(nil)
+	ancestorsBytesLen := 0 // Length, in bytes, of all elements of ancestorsBytes
+	queue := []ancestor{{id: vtxID}}
+	visited := ids.Set{}
+	visited.Add(vtxID)
+	for len(queue) > 0 && len(ancestorsBytes) < common.MaxContainersPerMultiPut {
+		next := queue[0]
+		queue = queue[1:]
+
+		vtxBytes := []byte(nil)
+		parents := []ancestor(nil)
+		if cached, ok := t.acceptedCache.get(next.id); ok && cached.parentsKnown {
+			vtxBytes = cached.bytes
+			for _, parentID := range cached.parentIDs {
+				parents = append(parents, ancestor{id: parentID})
+			}
+		} else {
+			vtx := next.vtx
+			if vtx == nil {
+				var err error
+				if vtx, err = t.Config.State.GetVertex(next.id); err != nil {
+					if len(ancestorsBytes) == 0 {
+						t.Config.Context.Log.Debug("Dropping GetAncestors for %s as the vertex couldn't be fetched due to %s", vtxID, err)
+						return
+					}
+					continue
+				}
+			}
+			vtxBytes = t.acceptedCache.add(vtx)
+			for _, parent := range vtx.Parents() {
+				if parent.Status().Fetched() {
+					parents = append(parents, ancestor{id: parent.ID(), vtx: parent})
+				}
+			}
+		}
+
+		// Ensure the MultiPut message doesn't get too big
+		if ancestorsBytesLen += len(vtxBytes) + wrappers.IntLen; ancestorsBytesLen > common.MaxContainersLen {
+			break
+		}
+		ancestorsBytes = append(ancestorsBytes, vtxBytes)
+		for _, parent := range parents {
+			if !visited.Contains(parent.id) {
+				visited.Add(parent.id)
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	t.Config.Sender.MultiPut(vdr, requestID, ancestorsBytes)
+}
+
+// MultiPut implements the Engine interface
+func (t *Transitive) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping MultiPut(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.MultiPut(vdr, requestID, vtxs)
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (t *Transitive) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping GetAncestorsFailed(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.GetAncestorsFailed(vdr, requestID)
 }
 
 // PullQuery implements the Engine interface
@@ -226,25 +347,39 @@ func (t *Transitive) insertFrom(vdr ids.ShortID, vtx avalanche.Vertex) bool {
 			continue
 		}
 
+		missing := ids.Set{}
 		for _, parent := range vtx.Parents() {
 			if !parent.Status().Fetched() {
-				t.sendRequest(vdr, parent.ID())
+				parentID := parent.ID()
+				t.sendRequest(vdr, parentID)
+				missing.Add(parentID)
 				issued = false
 			} else {
 				vts = append(vts, parent)
 			}
 		}
 
-		t.insert(vtx)
+		i := t.insert(vtx)
+
+		// If the vertex arrived before its parents, it waits in the orphan
+		// pool until they're fetched
+		if missing.Len() > 0 {
+			if evicted := t.orphans.Add(i, missing); evicted != nil {
+				t.Config.Context.Log.Debug("Dropping orphaned vertex %s because the orphan pool is full", evicted.vtx.ID())
+				evicted.Abandon()
+			}
+			t.numOrphanVtx.Set(float64(t.orphans.Len()))
+		}
 	}
 	return issued
 }
 
-func (t *Transitive) insert(vtx avalanche.Vertex) {
+func (t *Transitive) insert(vtx avalanche.Vertex) *issuer {
 	vtxID := vtx.ID()
 
 	t.pending.Add(vtxID)
 	t.vtxReqs.Remove(vtxID)
+	t.orphans.Fetched(vtxID)
 
 	i := &issuer{
 		t:   t,
@@ -290,29 +425,50 @@ func (t *Transitive) insert(vtx avalanche.Vertex) {
 	t.numVtxRequests.Set(float64(t.vtxReqs.Len()))
 	t.numTxRequests.Set(float64(t.missingTxs.Len()))
 	t.numBlockedVtx.Set(float64(t.pending.Len()))
+	t.numOrphanVtx.Set(float64(t.orphans.Len()))
+	return i
 }
 
 func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
+	// Vertices are built no larger than the vertices this chain accepts
+	maxBatchLen := t.Config.Context.Limits.MaxVertexSize - vertexHeaderLen - t.Params.Parents*hashing.HashLen
+
 	batch := []snowstorm.Tx(nil)
+	batchLen := 0
 	issuedTxs := ids.Set{}
 	consumed := ids.Set{}
 	issued := false
 	for _, tx := range txs {
+		txLen := len(tx.Bytes()) + wrappers.IntLen
+		if txLen > maxBatchLen {
+			t.Config.Context.Log.Debug("Dropping transaction %s, which doesn't fit in a vertex", tx.ID())
+			t.dropTx(tx)
+			continue
+		}
+
 		inputs := tx.InputIDs()
 		overlaps := consumed.Overlaps(inputs)
-		if len(batch) >= t.Params.BatchSize || (force && overlaps) {
+		if len(batch) >= t.Params.BatchSize || batchLen+txLen > maxBatchLen || (force && overlaps) {
 			t.issueBatch(batch)
 			batch = nil
+			batchLen = 0
 			consumed.Clear()
 			issued = true
 			overlaps = false
 		}
 
 		// Force allows for a conflict to be issued
-		if txID := tx.ID(); !overlaps && !issuedTxs.Contains(txID) && (force || (t.Consensus.IsVirtuous(tx))) && !tx.Status().Decided() {
+		txID := tx.ID()
+		switch {
+		case issuedTxs.Contains(txID) || tx.Status().Decided():
+		case !overlaps && (force || t.Consensus.IsVirtuous(tx)):
 			batch = append(batch, tx)
+			batchLen += txLen
 			issuedTxs.Add(txID)
 			consumed.Union(inputs)
+		case !t.Consensus.TxIssued(tx):
+			t.Config.Context.Log.Debug("Dropping transaction %s, which conflicts with a processing transaction", txID)
+			t.dropTx(tx)
 		}
 	}
 
@@ -324,8 +480,10 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
 func (t *Transitive) issueBatch(txs []snowstorm.Tx) {
 	t.Config.Context.Log.Verbo("Batching %d transactions into a new vertex", len(txs))
 
+	// Sorted, so the same parents are sampled from the same seed
 	virtuousIDs := t.Consensus.Virtuous().List()
-	sampler := random.Uniform{N: len(virtuousIDs)}
+	ids.SortIDs(virtuousIDs)
+	sampler := random.Uniform{N: len(virtuousIDs), Source: t.Config.Context.Source}
 	parentIDs := ids.Set{}
 	for i := 0; i < t.Params.Parents && sampler.CanSample(); i++ {
 		parentIDs.Add(virtuousIDs[sampler.Sample()])
@@ -335,6 +493,17 @@ func (t *Transitive) issueBatch(txs []snowstorm.Tx) {
 		t.insert(vtx)
 	} else {
 		t.Config.Context.Log.Warn("Error building new vertex with %d parents and %d transactions", len(parentIDs), len(txs))
+		for _, tx := range txs {
+			t.dropTx(tx)
+		}
+	}
+}
+
+// dropTx tells the VM, if it wants to know, that [tx] won't be issued into
+// consensus
+func (t *Transitive) dropTx(tx snowstorm.Tx) {
+	if vm, ok := t.Config.VM.(DroppedTxsVM); ok {
+		vm.DroppedTx(tx)
 	}
 }
 
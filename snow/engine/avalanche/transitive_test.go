@@ -17,6 +17,8 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 var (
@@ -796,6 +798,108 @@ func TestEngineRejectDoubleSpendTx(t *testing.T) {
 	te.Notify(common.PendingTxs)
 }
 
+func TestEngineBatchesByVertexSize(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params.BatchSize = 10
+
+	// Only two of the transactions fit in a vertex
+	txLen := 10
+	config.Context.Limits.MaxVertexSize = vertexHeaderLen + config.Params.Parents*hashing.HashLen + 2*(txLen+wrappers.IntLen)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	vm := &VMTest{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	mVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	gTx := &TestTx{
+		TestTx: snowstorm.TestTx{
+			Identifier: GenerateID(),
+			Stat:       choices.Accepted,
+		},
+	}
+
+	txs := []snowstorm.Tx{}
+	for i := 0; i < 3; i++ {
+		tx := &TestTx{
+			TestTx: snowstorm.TestTx{
+				Identifier: GenerateID(),
+				Deps:       []snowstorm.Tx{gTx},
+				Stat:       choices.Processing,
+			},
+			bytes: make([]byte, txLen),
+		}
+		tx.Ins.Add(GenerateID())
+		txs = append(txs, tx)
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID(), mVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case id.Equals(gVtx.ID()):
+			return gVtx, nil
+		case id.Equals(mVtx.ID()):
+			return mVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	batchSizes := []int{}
+	st.buildVertex = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		batchSizes = append(batchSizes, len(txs))
+		return &Vtx{
+			parents: []avalanche.Vertex{gVtx, mVtx},
+			id:      GenerateID(),
+			txs:     txs,
+			status:  choices.Processing,
+			bytes:   []byte{1},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	sender.CantPushQuery = false
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return txs }
+	te.Notify(common.PendingTxs)
+
+	if len(batchSizes) != 2 || batchSizes[0] != 2 || batchSizes[1] != 1 {
+		t.Fatalf("Should have built vertices of 2 and 1 transactions, but built %v", batchSizes)
+	}
+}
+
 func TestEngineRejectDoubleSpendIssuedTx(t *testing.T) {
 	config := DefaultConfig()
 
@@ -1307,6 +1411,204 @@ func TestEngineGetVertex(t *testing.T) {
 	te.Get(vdr.ID(), 0, mVtx.ID())
 }
 
+func TestEngineGetAncestors(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{0},
+	}
+	mVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{1},
+	}
+	missingVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Unknown,
+	}
+	vtx0 := &Vtx{
+		parents: []avalanche.Vertex{gVtx, mVtx},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{2},
+	}
+	vtx1 := &Vtx{
+		parents: []avalanche.Vertex{mVtx, missingVtx},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{3},
+	}
+	vtx2 := &Vtx{
+		parents: []avalanche.Vertex{vtx0, vtx1},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{4},
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID(), mVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case id.Equals(gVtx.ID()):
+			return gVtx, nil
+		case id.Equals(mVtx.ID()):
+			return mVtx, nil
+		case id.Equals(vtx2.ID()):
+			return vtx2, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	sent := new(bool)
+	sender.MultiPutF = func(v ids.ShortID, requestID uint32, vtxs [][]byte) {
+		if !v.Equals(vdr.ID()) {
+			t.Fatalf("Wrong validator")
+		}
+		if requestID != 123 {
+			t.Fatalf("Wrong request id")
+		}
+		// Ancestors are sent once each, closest first, and missing ones are
+		// skipped
+		expected := [][]byte{vtx2.Bytes(), vtx0.Bytes(), vtx1.Bytes(), gVtx.Bytes(), mVtx.Bytes()}
+		if len(vtxs) != len(expected) {
+			t.Fatalf("Should have sent %d vertices but sent %d", len(expected), len(vtxs))
+		}
+		for i, vtxBytes := range vtxs {
+			if !bytes.Equal(vtxBytes, expected[i]) {
+				t.Fatalf("Vertex %d should have been %v but was %v", i, expected[i], vtxBytes)
+			}
+		}
+		*sent = true
+	}
+
+	te.GetAncestors(vdr.ID(), 123, vtx2.ID())
+
+	if !*sent {
+		t.Fatalf("Should have sent vertices to peer")
+	}
+}
+
+func TestEngineServeAcceptedAncestorsFromCache(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{0},
+	}
+	vtx0 := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		status:  choices.Accepted,
+		bytes:   []byte{1},
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{vtx0.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case id.Equals(gVtx.ID()):
+			return gVtx, nil
+		case id.Equals(vtx0.ID()):
+			return vtx0, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	ctx := te.Context()
+	for _, vtx := range []avalanche.Vertex{gVtx, vtx0} {
+		ctx.ConsensusDispatcher.Accept(ctx.ChainID, vtx.ID(), vtx.Bytes())
+	}
+
+	// Accepted vertices should be served without loading them
+	st.getVertex = nil
+
+	put := new(bool)
+	sender.PutF = func(_ ids.ShortID, requestID uint32, vtxID ids.ID, vtxBytes []byte) {
+		if !vtxID.Equals(vtx0.ID()) || !bytes.Equal(vtxBytes, vtx0.Bytes()) {
+			t.Fatalf("Sent the wrong vertex")
+		}
+		*put = true
+	}
+
+	te.Get(vdr.ID(), 123, vtx0.ID())
+
+	if !*put {
+		t.Fatalf("Should have sent vertex to peer")
+	}
+
+	sent := new(int)
+	sender.MultiPutF = func(_ ids.ShortID, requestID uint32, vtxs [][]byte) {
+		expected := [][]byte{vtx0.Bytes(), gVtx.Bytes()}
+		if len(vtxs) != len(expected) {
+			t.Fatalf("Should have sent %d vertices but sent %d", len(expected), len(vtxs))
+		}
+		for i, vtxBytes := range vtxs {
+			if !bytes.Equal(vtxBytes, expected[i]) {
+				t.Fatalf("Vertex %d should have been %v but was %v", i, expected[i], vtxBytes)
+			}
+		}
+		*sent++
+	}
+
+	// The parents of an accepted vertex are loaded the first time it's served
+	// as an ancestor
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(vtx0.ID()) {
+			return vtx0, nil
+		}
+		t.Fatalf("Should have served vertex %s from memory", id)
+		panic("Should have errored")
+	}
+	te.GetAncestors(vdr.ID(), 123, vtx0.ID())
+
+	// After which the vertex and its ancestors are served from memory
+	st.getVertex = nil
+	te.GetAncestors(vdr.ID(), 124, vtx0.ID())
+
+	if *sent != 2 {
+		t.Fatalf("Should have sent vertices to peer twice")
+	}
+}
+
 func TestEngineInsufficientValidators(t *testing.T) {
 	config := DefaultConfig()
 
@@ -2255,7 +2557,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 		panic("Unknown vertex requested")
 	}
 
-	sender.GetF = func(inVdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(inVdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdrID.Equals(inVdr) {
 			t.Fatalf("Asking wrong validator for vertex")
 		}
@@ -2268,7 +2570,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	te.Accepted(vdrID, *requestID, acceptedFrontier)
 
 	st.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseTxF = func(b []byte) (snowstorm.Tx, error) {
 		switch {
@@ -2298,7 +2600,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 		panic("Unknown bytes provided")
 	}
 
-	te.Put(vdrID, *requestID, vtxID0, vtxBytes0)
+	te.MultiPut(vdrID, *requestID, [][]byte{vtxBytes0})
 
 	vm.ParseTxF = nil
 	st.parseVertex = nil
@@ -2363,3 +2665,289 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	sender.PushQueryF = nil
 	st.getVertex = nil
 }
+
+func TestEngineOrphanPool(t *testing.T) {
+	config := DefaultConfig()
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.orphans.max = 1
+
+	missingVtx0 := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		height:  1,
+		status:  choices.Unknown,
+		bytes:   []byte{0},
+	}
+	missingVtx1 := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		height:  1,
+		status:  choices.Unknown,
+		bytes:   []byte{1},
+	}
+	orphanVtx0 := &Vtx{
+		parents: []avalanche.Vertex{missingVtx0},
+		id:      GenerateID(),
+		height:  2,
+		status:  choices.Processing,
+		bytes:   []byte{2},
+	}
+	orphanVtx1 := &Vtx{
+		parents: []avalanche.Vertex{missingVtx1},
+		id:      GenerateID(),
+		height:  2,
+		status:  choices.Processing,
+		bytes:   []byte{3},
+	}
+
+	requested := ids.Set{}
+	sender.GetF = func(_ ids.ShortID, _ uint32, vtxID ids.ID) {
+		if requested.Contains(vtxID) {
+			t.Fatalf("Requested %s multiple times", vtxID)
+		}
+		requested.Add(vtxID)
+	}
+	st.parseVertex = func(b []byte) (avalanche.Vertex, error) {
+		for _, vtx := range []*Vtx{missingVtx0, missingVtx1, orphanVtx0, orphanVtx1} {
+			if bytes.Equal(b, vtx.Bytes()) {
+				return vtx, nil
+			}
+		}
+		t.Fatalf("Unknown bytes")
+		panic("Should have errored")
+	}
+
+	te.Put(vdr.ID(), 0, orphanVtx0.ID(), orphanVtx0.Bytes())
+	if !requested.Contains(missingVtx0.ID()) {
+		t.Fatalf("Should have requested the missing parent")
+	}
+	if te.orphans.Len() != 1 {
+		t.Fatalf("Should have added the vertex to the orphan pool")
+	}
+
+	// The orphan pool is full, so the first orphan is dropped
+	te.Put(vdr.ID(), 0, orphanVtx1.ID(), orphanVtx1.Bytes())
+	if te.orphans.Len() != 1 {
+		t.Fatalf("Orphan pool should be bounded")
+	}
+	if te.pending.Contains(orphanVtx0.ID()) {
+		t.Fatalf("Should have dropped the oldest orphan")
+	}
+	if !te.pending.Contains(orphanVtx1.ID()) {
+		t.Fatalf("Should still be waiting to issue the newest orphan")
+	}
+
+	// When the missing parent arrives, the orphan is issued
+	queried := ids.Set{}
+	sender.PushQueryF = func(_ ids.ShortSet, _ uint32, vtxID ids.ID, _ []byte) { queried.Add(vtxID) }
+
+	missingVtx1.status = choices.Processing
+	te.Put(vdr.ID(), 0, missingVtx1.ID(), missingVtx1.Bytes())
+	if te.orphans.Len() != 0 {
+		t.Fatalf("Should have resolved the orphan")
+	}
+	if te.pending.Len() != 0 {
+		t.Fatalf("Should have issued the vertices")
+	}
+	if !queried.Contains(missingVtx1.ID()) || !queried.Contains(orphanVtx1.ID()) {
+		t.Fatalf("Should have queried the network for the vertices")
+	}
+}
+
+// parentsVM is a VM that configures the parents of its chain's vertices
+type parentsVM struct {
+	*VMTest
+	parents int
+}
+
+func (vm *parentsVM) VertexParents() int { return vm.parents }
+
+func TestEngineVMConfiguresParents(t *testing.T) {
+	config := DefaultConfig()
+	config.VM = &parentsVM{VMTest: &VMTest{}, parents: 7}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	if te.Params.Parents != 7 {
+		t.Fatalf("the VM should have configured %d parents but there are %d", 7, te.Params.Parents)
+	}
+
+	config = DefaultConfig()
+	config.VM = &parentsVM{VMTest: &VMTest{}}
+
+	te = &Transitive{}
+	te.Initialize(config)
+	if te.Params.Parents != 2 {
+		t.Fatalf("a VM that doesn't configure parents should keep the consensus parameters' %d but there are %d", 2, te.Params.Parents)
+	}
+}
+
+// droppingVM is a VM that records the txs the engine dropped
+type droppingVM struct {
+	*VMTest
+	dropped ids.Set
+}
+
+func (vm *droppingVM) DroppedTx(tx snowstorm.Tx) { vm.dropped.Add(tx.ID()) }
+
+func TestEngineDropsTxs(t *testing.T) {
+	config := DefaultConfig()
+
+	// Only one small transaction fits in a vertex
+	txLen := 10
+	config.Context.Limits.MaxVertexSize = vertexHeaderLen + config.Params.Parents*hashing.HashLen + txLen + wrappers.IntLen
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(validators.GenerateRandomValidator(1))
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	vm := &droppingVM{VMTest: &VMTest{}}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	mVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	gTx := &TestTx{
+		TestTx: snowstorm.TestTx{
+			Identifier: GenerateID(),
+			Stat:       choices.Accepted,
+		},
+	}
+
+	newTx := func(size int) *TestTx {
+		tx := &TestTx{
+			TestTx: snowstorm.TestTx{
+				Identifier: GenerateID(),
+				Deps:       []snowstorm.Tx{gTx},
+				Stat:       choices.Processing,
+			},
+			bytes: make([]byte, size),
+		}
+		tx.Ins.Add(GenerateID())
+		return tx
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID(), mVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case id.Equals(gVtx.ID()):
+			return gVtx, nil
+		case id.Equals(mVtx.ID()):
+			return mVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	sender.CantPushQuery = false
+
+	// A transaction that can never fit in a vertex is dropped
+	largeTx := newTx(txLen + 1)
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{largeTx} }
+	te.Notify(common.PendingTxs)
+
+	if !vm.dropped.Contains(largeTx.ID()) {
+		t.Fatalf("Should have dropped the transaction that doesn't fit in a vertex")
+	}
+
+	// A transaction whose vertex couldn't be built is dropped
+	failedTx := newTx(txLen)
+	st.buildVertex = func(ids.Set, []snowstorm.Tx) (avalanche.Vertex, error) {
+		return nil, errors.New("unknown error")
+	}
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{failedTx} }
+	te.Notify(common.PendingTxs)
+
+	if !vm.dropped.Contains(failedTx.ID()) {
+		t.Fatalf("Should have dropped the transaction whose vertex couldn't be built")
+	}
+
+	// A transaction that conflicts with a processing transaction is dropped,
+	// but the processing transaction isn't
+	issuedTx := newTx(txLen)
+	conflictingTx := newTx(txLen)
+	conflictingTx.Ins = issuedTx.Ins
+	st.buildVertex = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		return &Vtx{
+			parents: []avalanche.Vertex{gVtx, mVtx},
+			id:      GenerateID(),
+			txs:     txs,
+			status:  choices.Processing,
+			bytes:   []byte{1},
+		}, nil
+	}
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{issuedTx} }
+	te.Notify(common.PendingTxs)
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{issuedTx, conflictingTx} }
+	te.Notify(common.PendingTxs)
+
+	switch {
+	case vm.dropped.Contains(issuedTx.ID()):
+		t.Fatalf("Shouldn't have dropped the issued transaction")
+	case !vm.dropped.Contains(conflictingTx.ID()):
+		t.Fatalf("Should have dropped the conflicting transaction")
+	}
+}
@@ -23,3 +23,23 @@ type DAGVM interface {
 	// Retrieve a transaction that was submitted previously
 	GetTx(ids.ID) (snowstorm.Tx, error)
 }
+
+// ParentsConfigurableVM is a DAGVM that may configure, for its chain, the most
+// parents each vertex built by this node references
+type ParentsConfigurableVM interface {
+	DAGVM
+
+	// VertexParents returns the most parents each vertex built by this node
+	// references, or 0 to use the chain's consensus parameters
+	VertexParents() int
+}
+
+// DroppedTxsVM is a DAGVM that is told when this node drops one of the txs
+// returned by PendingTxs instead of issuing it into consensus. A dropped tx is
+// never decided unless it's returned by PendingTxs again.
+type DroppedTxsVM interface {
+	DAGVM
+
+	// DroppedTx is called with a tx that this node won't issue into consensus
+	DroppedTx(snowstorm.Tx)
+}
@@ -18,6 +18,9 @@ type Bootstrapper struct {
 	accepted        ids.Bag
 
 	RequestID uint32
+
+	// Compares the accepted frontier with validators' once bootstrapped
+	monitor frontierMonitor
 }
 
 // Initialize implements the Engine interface.
@@ -60,6 +63,9 @@ func (b *Bootstrapper) GetAcceptedFrontierFailed(validatorID ids.ShortID, reques
 
 // AcceptedFrontier implements the Engine interface.
 func (b *Bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	if b.monitorFrontier(validatorID, requestID, containerIDs) {
+		return
+	}
 	if !b.pendingAcceptedFrontier.Contains(validatorID) {
 		b.Context.Log.Debug("Received an AcceptedFrontier message from %s unexpectedly", validatorID)
 		return
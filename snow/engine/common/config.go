@@ -18,4 +18,8 @@ type Config struct {
 	Alpha         int
 	Sender        Sender
 	Bootstrapable Bootstrapable
+
+	// How the chain compares its accepted frontier with its validators' once
+	// it has finished bootstrapping
+	FrontierMonitor FrontierMonitorConfig
 }
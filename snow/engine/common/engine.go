@@ -80,6 +80,19 @@ type FetchHandler interface {
 
 	// Notify this engine that a get request it issued has failed.
 	GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+
+	// GetAncestors notifies this consensus engine that the specified validator
+	// requested that this engine send the specified container and as many of
+	// its ancestors as fit in one MultiPut message.
+	GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+
+	// MultiPut gives this engine the containers it requested with GetAncestors.
+	// The first container is the requested one, and each one after it is an
+	// ancestor of a container before it.
+	MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte)
+
+	// Notify this engine that a GetAncestors request it issued has failed.
+	GetAncestorsFailed(validatorID ids.ShortID, requestID uint32)
 }
 
 // QueryHandler defines how a consensus engine reacts to query messages from
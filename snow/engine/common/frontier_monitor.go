@@ -0,0 +1,215 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// maxResyncFetches is the most containers fetched per check when resyncing
+const maxResyncFetches = 16
+
+// FrontierMonitorConfig configures how a bootstrapped chain compares its
+// accepted frontier with the accepted frontiers of its validators
+type FrontierMonitorConfig struct {
+	// How often the frontiers are compared. If 0, they aren't.
+	Frequency time.Duration
+
+	// Number of validators, sampled by stake, whose frontiers are compared
+	// with this chain's frontier each time
+	SampleSize int
+
+	// How long the frontier may diverge before the chain is reported as
+	// diverged. It diverges when, by stake, most of the sampled validators
+	// have a frontier this chain hasn't accepted any of.
+	Threshold time.Duration
+
+	// If true, a chain that has diverged fetches the frontiers of the sampled
+	// validators it disagrees with, so it can catch up with them
+	Resync bool
+}
+
+// frontierMonitor compares the accepted frontier of a bootstrapped chain with
+// the accepted frontiers of a sample of its validators
+type frontierMonitor struct {
+	config FrontierMonitorConfig
+	fetch  func(validatorID ids.ShortID, containerID ids.ID)
+
+	timer   *time.Timer
+	stopped bool
+
+	// The validators whose frontiers were requested in the current check, their
+	// stake, and the stake of the validators that agree and disagree with this
+	// chain
+	requestID        uint32
+	pending          ids.ShortSet
+	weights          map[[20]byte]uint64
+	agree, disagree  uint64
+	disagreeing      map[[20]byte]ids.Set // The frontiers this chain disagrees with
+	divergedSince    time.Time            // Zero if the frontier doesn't diverge
+	reportedDiverged bool
+
+	diverged prometheus.Gauge
+}
+
+// StartFrontierMonitor starts comparing the accepted frontier of this chain
+// with the accepted frontiers of its validators, as configured by
+// [b.FrontierMonitor], and reports divergence with the frontier_diverged
+// metric. If [b.FrontierMonitor.Resync], [fetch] is called to fetch the
+// containers of the frontiers this chain disagrees with. Must be called with
+// the context's lock held, once the chain has finished bootstrapping.
+func (b *Bootstrapper) StartFrontierMonitor(namespace string, registerer prometheus.Registerer, fetch func(validatorID ids.ShortID, containerID ids.ID)) {
+	m := &b.monitor
+	m.config = b.Config.FrontierMonitor
+	if m.config.Frequency <= 0 || m.config.SampleSize <= 0 || m.timer != nil {
+		return
+	}
+	m.fetch = fetch
+	m.diverged = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "frontier_diverged",
+		Help:      "1 if the accepted frontier has diverged from the validators' accepted frontiers for longer than the threshold, 0 otherwise",
+	})
+	if err := registerer.Register(m.diverged); err != nil {
+		b.Context.Log.Error("Failed to register frontier_diverged statistics due to %s", err)
+	}
+	m.timer = time.AfterFunc(m.config.Frequency, b.checkFrontier)
+}
+
+// StopFrontierMonitor stops comparing the accepted frontier of this chain with
+// the accepted frontiers of its validators. Must be called with the context's
+// lock held.
+func (b *Bootstrapper) StopFrontierMonitor() {
+	m := &b.monitor
+	m.stopped = true
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+}
+
+// FrontierDiverged returns true if the accepted frontier of this chain has
+// diverged from the accepted frontiers of its validators for longer than the
+// threshold. Must be called with the context's lock held.
+func (b *Bootstrapper) FrontierDiverged() bool { return b.monitor.reportedDiverged }
+
+// checkFrontier requests the accepted frontiers of a sample of validators, and
+// schedules the next check
+func (b *Bootstrapper) checkFrontier() {
+	b.Context.Lock.Lock()
+	defer b.Context.Lock.Unlock()
+
+	m := &b.monitor
+	if m.stopped {
+		return
+	}
+	defer m.timer.Reset(m.config.Frequency)
+
+	// The previous check is still waiting for responses
+	if m.pending.Len() != 0 {
+		return
+	}
+
+	vdrs := ids.ShortSet{}
+	m.weights = make(map[[20]byte]uint64)
+	for _, vdr := range b.Validators.SampleFrom(m.config.SampleSize, b.Context.Source) {
+		if vdrID := vdr.ID(); !vdrID.Equals(b.Context.NodeID) {
+			vdrs.Add(vdrID)
+			m.weights[vdrID.Key()] = vdr.Weight()
+		}
+	}
+	if vdrs.Len() == 0 {
+		return
+	}
+
+	m.pending.Union(vdrs)
+	m.agree = 0
+	m.disagree = 0
+	m.disagreeing = make(map[[20]byte]ids.Set)
+
+	b.RequestID++
+	m.requestID = b.RequestID
+	b.Sender.GetAcceptedFrontier(vdrs, m.requestID)
+}
+
+// monitorFrontier records that [validatorID] has the accepted frontier
+// [containerIDs], if it's a response to the current check. It returns false if
+// it isn't.
+func (b *Bootstrapper) monitorFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) bool {
+	m := &b.monitor
+	if requestID != m.requestID || !m.pending.Contains(validatorID) {
+		return false
+	}
+	m.pending.Remove(validatorID)
+
+	// A validator that didn't respond doesn't count
+	if containerIDs.Len() != 0 {
+		weight := m.weights[validatorID.Key()]
+		if b.Bootstrapable.FilterAccepted(containerIDs).Len() != 0 {
+			m.agree += weight
+		} else {
+			m.disagree += weight
+			m.disagreeing[validatorID.Key()] = containerIDs
+		}
+	}
+
+	if m.pending.Len() == 0 {
+		b.finishFrontierCheck()
+	}
+	return true
+}
+
+// finishFrontierCheck reports whether the frontier has diverged once every
+// sampled validator has responded or failed to
+func (b *Bootstrapper) finishFrontierCheck() {
+	m := &b.monitor
+	if m.agree == 0 && m.disagree == 0 {
+		return // No validator responded
+	}
+
+	now := b.Context.Clock.Time()
+	if m.disagree <= m.agree {
+		if m.reportedDiverged {
+			b.Context.Log.Info("accepted frontier agrees with the validators' accepted frontiers again")
+		}
+		m.divergedSince = time.Time{}
+		m.reportedDiverged = false
+		m.diverged.Set(0)
+		return
+	}
+
+	if m.divergedSince.IsZero() {
+		m.divergedSince = now
+	}
+	divergence := now.Sub(m.divergedSince)
+	if divergence < m.config.Threshold {
+		return
+	}
+	if !m.reportedDiverged {
+		b.Context.Log.Warn("accepted frontier has diverged from the accepted frontiers of validators with %d of %d sampled stake for %s",
+			m.disagree, m.agree+m.disagree, divergence)
+	}
+	m.reportedDiverged = true
+	m.diverged.Set(1)
+
+	if !m.config.Resync || m.fetch == nil {
+		return
+	}
+	fetched := ids.Set{}
+	for vdrKey, containerIDs := range m.disagreeing {
+		vdrID := ids.NewShortID(vdrKey)
+		for _, containerID := range containerIDs.List() {
+			if fetched.Len() >= maxResyncFetches {
+				return
+			}
+			if !fetched.Contains(containerID) {
+				fetched.Add(containerID)
+				m.fetch(vdrID, containerID)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestFrontierMonitor(t *testing.T) {
+	config := DefaultConfigTest()
+	config.FrontierMonitor = FrontierMonitorConfig{
+		Frequency:  time.Hour,
+		SampleSize: 3,
+		Threshold:  time.Minute,
+		Resync:     true,
+	}
+
+	vdr0 := validators.GenerateRandomValidator(1)
+	vdr1 := validators.GenerateRandomValidator(1)
+	vdr2 := validators.GenerateRandomValidator(1)
+	config.Validators.Add(vdr0)
+	config.Validators.Add(vdr1)
+	config.Validators.Add(vdr2)
+
+	sender := config.Sender.(*SenderTest)
+	sender.T = t
+	sender.Default(true)
+
+	accepted := ids.Empty.Prefix(0)
+	unknown := ids.Empty.Prefix(1)
+	bootstrapable := config.Bootstrapable.(*BootstrapableTest)
+	bootstrapable.T = t
+	bootstrapable.Default(true)
+	bootstrapable.FilterAcceptedF = func(containerIDs ids.Set) ids.Set {
+		filtered := ids.Set{}
+		if containerIDs.Contains(accepted) {
+			filtered.Add(accepted)
+		}
+		return filtered
+	}
+
+	b := Bootstrapper{}
+	b.Initialize(config)
+
+	fetched := ids.Set{}
+	b.StartFrontierMonitor("", prometheus.NewRegistry(), func(_ ids.ShortID, containerID ids.ID) {
+		fetched.Add(containerID)
+	})
+	defer b.StopFrontierMonitor()
+
+	requestID := new(uint32)
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, reqID uint32) {
+		if vdrs.Len() != 3 {
+			t.Fatalf("should have requested the frontiers of 3 validators but requested %d", vdrs.Len())
+		}
+		*requestID = reqID
+	}
+	check := func(frontiers ...ids.ID) {
+		b.checkFrontier()
+		for i, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
+			b.AcceptedFrontier(vdr.ID(), *requestID, ids.Set{frontiers[i].Key(): true})
+		}
+	}
+
+	// Most of the stake agrees with this chain
+	check(accepted, accepted, unknown)
+	if b.FrontierDiverged() {
+		t.Fatalf("shouldn't have diverged when most of the stake agrees")
+	}
+
+	// Most of the stake disagrees, but not for long enough yet
+	now := time.Now()
+	b.Context.Clock.Set(now)
+	check(unknown, unknown, accepted)
+	if b.FrontierDiverged() || fetched.Len() != 0 {
+		t.Fatalf("shouldn't have diverged before the threshold")
+	}
+
+	b.Context.Clock.Set(now.Add(time.Minute))
+	check(unknown, unknown, accepted)
+	if !b.FrontierDiverged() {
+		t.Fatalf("should have diverged after the threshold")
+	}
+	if fetched.Len() != 1 || !fetched.Contains(unknown) {
+		t.Fatalf("should have fetched the frontier this chain disagrees with")
+	}
+
+	// Responses to stale requests are ignored
+	b.AcceptedFrontier(vdr0.ID(), *requestID, ids.Set{accepted.Key(): true})
+
+	check(accepted, accepted, accepted)
+	if b.FrontierDiverged() {
+		t.Fatalf("should have stopped diverging once the stake agrees again")
+	}
+}
@@ -0,0 +1,155 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// MeteredEngine wraps an engine and reports the number of messages of each
+// type it handles, and how long handling them takes
+type MeteredEngine struct {
+	Engine
+
+	handleDuration *prometheus.HistogramVec
+}
+
+// Initialize this engine to wrap [engine]. The metrics are registered with
+// [registerer] under [namespace].
+func (e *MeteredEngine) Initialize(engine Engine, namespace string, registerer prometheus.Registerer) error {
+	e.Engine = engine
+	e.handleDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "handle_duration",
+			Help:      "Time spent handling each type of message, in milliseconds",
+			Buckets:   prometheus.ExponentialBuckets(.1, 2, 16),
+		},
+		[]string{"message"},
+	)
+	return registerer.Register(e.handleDuration)
+}
+
+// observe records that a message of type [message] was handled since [start]
+func (e *MeteredEngine) observe(message string, start time.Time) {
+	e.handleDuration.WithLabelValues(message).Observe(float64(time.Since(start)) / float64(time.Millisecond))
+}
+
+// Startup implements the Engine interface
+func (e *MeteredEngine) Startup() {
+	defer e.observe("startup", time.Now())
+	e.Engine.Startup()
+}
+
+// Shutdown implements the Engine interface
+func (e *MeteredEngine) Shutdown() {
+	defer e.observe("shutdown", time.Now())
+	e.Engine.Shutdown()
+}
+
+// Notify implements the Engine interface
+func (e *MeteredEngine) Notify(msg Message) {
+	defer e.observe("notify", time.Now())
+	e.Engine.Notify(msg)
+}
+
+// GetAcceptedFrontier implements the Engine interface
+func (e *MeteredEngine) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
+	defer e.observe("get_accepted_frontier", time.Now())
+	e.Engine.GetAcceptedFrontier(validatorID, requestID)
+}
+
+// AcceptedFrontier implements the Engine interface
+func (e *MeteredEngine) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	defer e.observe("accepted_frontier", time.Now())
+	e.Engine.AcceptedFrontier(validatorID, requestID, containerIDs)
+}
+
+// GetAcceptedFrontierFailed implements the Engine interface
+func (e *MeteredEngine) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
+	defer e.observe("get_accepted_frontier_failed", time.Now())
+	e.Engine.GetAcceptedFrontierFailed(validatorID, requestID)
+}
+
+// GetAccepted implements the Engine interface
+func (e *MeteredEngine) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	defer e.observe("get_accepted", time.Now())
+	e.Engine.GetAccepted(validatorID, requestID, containerIDs)
+}
+
+// Accepted implements the Engine interface
+func (e *MeteredEngine) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	defer e.observe("accepted", time.Now())
+	e.Engine.Accepted(validatorID, requestID, containerIDs)
+}
+
+// GetAcceptedFailed implements the Engine interface
+func (e *MeteredEngine) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
+	defer e.observe("get_accepted_failed", time.Now())
+	e.Engine.GetAcceptedFailed(validatorID, requestID)
+}
+
+// Get implements the Engine interface
+func (e *MeteredEngine) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	defer e.observe("get", time.Now())
+	e.Engine.Get(validatorID, requestID, containerID)
+}
+
+// Put implements the Engine interface
+func (e *MeteredEngine) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
+	defer e.observe("put", time.Now())
+	e.Engine.Put(validatorID, requestID, containerID, container)
+}
+
+// GetFailed implements the Engine interface
+func (e *MeteredEngine) GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	defer e.observe("get_failed", time.Now())
+	e.Engine.GetFailed(validatorID, requestID, containerID)
+}
+
+// GetAncestors implements the Engine interface
+func (e *MeteredEngine) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	defer e.observe("get_ancestors", time.Now())
+	e.Engine.GetAncestors(validatorID, requestID, containerID)
+}
+
+// MultiPut implements the Engine interface
+func (e *MeteredEngine) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	defer e.observe("multi_put", time.Now())
+	e.Engine.MultiPut(validatorID, requestID, containers)
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (e *MeteredEngine) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	defer e.observe("get_ancestors_failed", time.Now())
+	e.Engine.GetAncestorsFailed(validatorID, requestID)
+}
+
+// PullQuery implements the Engine interface
+func (e *MeteredEngine) PullQuery(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	defer e.observe("pull_query", time.Now())
+	e.Engine.PullQuery(validatorID, requestID, containerID)
+}
+
+// PushQuery implements the Engine interface
+func (e *MeteredEngine) PushQuery(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
+	defer e.observe("push_query", time.Now())
+	e.Engine.PushQuery(validatorID, requestID, containerID, container)
+}
+
+// Chits implements the Engine interface
+func (e *MeteredEngine) Chits(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	defer e.observe("chits", time.Now())
+	e.Engine.Chits(validatorID, requestID, containerIDs)
+}
+
+// QueryFailed implements the Engine interface
+func (e *MeteredEngine) QueryFailed(validatorID ids.ShortID, requestID uint32) {
+	defer e.observe("query_failed", time.Now())
+	e.Engine.QueryFailed(validatorID, requestID)
+}
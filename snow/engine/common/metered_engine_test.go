@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	_ Engine = &NoOpEngine{}
+	_ Engine = &MeteredEngine{}
+	_ Engine = &ScriptedEngine{}
+)
+
+func TestMeteredEngine(t *testing.T) {
+	scripted := &ScriptedEngine{T: t}
+	reacted := false
+	scripted.OnCall = func(call Call) { reacted = reacted || call.Op == "Chits" }
+
+	registry := prometheus.NewRegistry()
+	engine := &MeteredEngine{}
+	if err := engine.Initialize(scripted, "test", registry); err != nil {
+		t.Fatal(err)
+	}
+
+	vdr := ids.NewShortID([20]byte{1})
+	containerID := ids.Empty.Prefix(1)
+	votes := ids.Set{}
+	votes.Add(containerID)
+
+	engine.PullQuery(vdr, 1, containerID)
+	engine.Chits(vdr, 2, votes)
+	engine.PullQuery(vdr, 3, containerID)
+	engine.Notify(PendingTxs)
+
+	scripted.Expect(
+		Call{Op: "PullQuery", ValidatorID: vdr, RequestID: 1, ContainerID: containerID},
+		Call{Op: "Chits", ValidatorID: vdr, RequestID: 2, ContainerIDs: votes},
+		Call{Op: "PullQuery", ValidatorID: vdr, RequestID: 3, ContainerID: containerID},
+		Call{Op: "Notify", Message: PendingTxs},
+	)
+	if !reacted {
+		t.Fatalf("The scripted reaction to Chits should have run")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				counts[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	if counts["pull_query"] != 2 || counts["chits"] != 1 || counts["notify"] != 1 {
+		t.Fatalf("Recorded the wrong number of messages: %v", counts)
+	}
+}
@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+)
+
+// NoOpEngine is an engine that ignores every message it receives
+type NoOpEngine struct{ Ctx *snow.Context }
+
+// Context implements the Engine interface
+func (e *NoOpEngine) Context() *snow.Context { return e.Ctx }
+
+// Startup implements the Engine interface
+func (*NoOpEngine) Startup() {}
+
+// Shutdown implements the Engine interface
+func (*NoOpEngine) Shutdown() {}
+
+// Notify implements the Engine interface
+func (*NoOpEngine) Notify(Message) {}
+
+// GetAcceptedFrontier implements the Engine interface
+func (*NoOpEngine) GetAcceptedFrontier(ids.ShortID, uint32) {}
+
+// AcceptedFrontier implements the Engine interface
+func (*NoOpEngine) AcceptedFrontier(ids.ShortID, uint32, ids.Set) {}
+
+// GetAcceptedFrontierFailed implements the Engine interface
+func (*NoOpEngine) GetAcceptedFrontierFailed(ids.ShortID, uint32) {}
+
+// GetAccepted implements the Engine interface
+func (*NoOpEngine) GetAccepted(ids.ShortID, uint32, ids.Set) {}
+
+// Accepted implements the Engine interface
+func (*NoOpEngine) Accepted(ids.ShortID, uint32, ids.Set) {}
+
+// GetAcceptedFailed implements the Engine interface
+func (*NoOpEngine) GetAcceptedFailed(ids.ShortID, uint32) {}
+
+// Get implements the Engine interface
+func (*NoOpEngine) Get(ids.ShortID, uint32, ids.ID) {}
+
+// Put implements the Engine interface
+func (*NoOpEngine) Put(ids.ShortID, uint32, ids.ID, []byte) {}
+
+// GetFailed implements the Engine interface
+func (*NoOpEngine) GetFailed(ids.ShortID, uint32, ids.ID) {}
+
+// GetAncestors implements the Engine interface
+func (*NoOpEngine) GetAncestors(ids.ShortID, uint32, ids.ID) {}
+
+// MultiPut implements the Engine interface
+func (*NoOpEngine) MultiPut(ids.ShortID, uint32, [][]byte) {}
+
+// GetAncestorsFailed implements the Engine interface
+func (*NoOpEngine) GetAncestorsFailed(ids.ShortID, uint32) {}
+
+// PullQuery implements the Engine interface
+func (*NoOpEngine) PullQuery(ids.ShortID, uint32, ids.ID) {}
+
+// PushQuery implements the Engine interface
+func (*NoOpEngine) PushQuery(ids.ShortID, uint32, ids.ID, []byte) {}
+
+// Chits implements the Engine interface
+func (*NoOpEngine) Chits(ids.ShortID, uint32, ids.Set) {}
+
+// QueryFailed implements the Engine interface
+func (*NoOpEngine) QueryFailed(ids.ShortID, uint32) {}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestRequests(t *testing.T) {
+	vdr0 := ids.NewShortID([20]byte{1})
+	vdr1 := ids.NewShortID([20]byte{2})
+	id0 := ids.Empty.Prefix(0)
+	id1 := ids.Empty.Prefix(1)
+
+	r := Requests{}
+	if r.Len() != 0 || r.Contains(id0) {
+		t.Fatalf("shouldn't have any outstanding requests")
+	}
+	if _, ok := r.Remove(vdr0, 0); ok {
+		t.Fatalf("shouldn't have removed a request that was never added")
+	}
+
+	r.Add(vdr0, 0, id0)
+	r.Add(vdr1, 0, id1)
+	if r.Len() != 2 || !r.Contains(id0) || !r.Contains(id1) {
+		t.Fatalf("should have two outstanding requests")
+	}
+
+	if _, ok := r.Remove(vdr1, 1); ok {
+		t.Fatalf("shouldn't have removed a request with an unknown requestID")
+	}
+	if containerID, ok := r.Remove(vdr0, 0); !ok || !containerID.Equals(id0) {
+		t.Fatalf("should have removed the request for %s", id0)
+	}
+	if r.Len() != 1 || r.Contains(id0) {
+		t.Fatalf("should have one outstanding request")
+	}
+
+	if !r.RemoveAny(id1) {
+		t.Fatalf("should have removed the request for %s", id1)
+	}
+	if r.RemoveAny(id1) {
+		t.Fatalf("shouldn't have removed the request for %s twice", id1)
+	}
+	if _, ok := r.Remove(vdr1, 0); ok || r.Len() != 0 {
+		t.Fatalf("shouldn't have any outstanding requests")
+	}
+}
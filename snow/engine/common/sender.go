@@ -7,6 +7,16 @@ import (
 	"github.com/ava-labs/gecko/ids"
 )
 
+const (
+	// MaxContainersPerMultiPut is the maximum number of containers a MultiPut
+	// message may contain
+	MaxContainersPerMultiPut = 2000
+
+	// MaxContainersLen is the maximum number of bytes of containers a MultiPut
+	// message may contain. It's well under the maximum size of a message.
+	MaxContainersLen = 1 << 21
+)
+
 // Sender defines how a consensus engine sends messages and requests to other
 // validators
 type Sender interface {
@@ -52,6 +62,14 @@ type FetchSender interface {
 	// Tell the specified validator that the container whose ID is <containerID>
 	// has body <container>
 	Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
+
+	// GetAncestors requests that the specified validator send the specified
+	// container and as many of its ancestors as fit in one MultiPut message
+	GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+
+	// MultiPut responds to a GetAncestors message with the requested container
+	// followed by its ancestors
+	MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte)
 }
 
 // QuerySender defines how a consensus engine sends query messages to other
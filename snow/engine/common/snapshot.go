@@ -0,0 +1,238 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// SnapshotWriter
+const SnapshotVersion = 1
+
+var (
+	snapshotMagic = []byte("geckosnp")
+
+	errNotSnapshot          = errors.New("not a snapshot")
+	errSnapshotWrongChain   = errors.New("snapshot is of another chain")
+	errSnapshotVersion      = errors.New("unsupported snapshot version")
+	errSnapshotChecksum     = errors.New("snapshot checksum mismatch")
+	errSnapshotCount        = errors.New("snapshot holds a different number of containers than its footer claims")
+	errSnapshotEmptyWrite   = errors.New("can't write an empty container to a snapshot")
+	errSnapshotTooLarge     = errors.New("snapshot container is larger than the maximum container size")
+	errSnapshotWriterClosed = errors.New("snapshot writer is closed")
+)
+
+// A snapshot is a file of the accepted containers of a chain, ordered so that
+// each container follows the containers it references. It's laid out as:
+//
+//	header:    magic (8 bytes) | version (uint32) | chain ID (32 bytes)
+//	container: length (uint32, > 0) | container bytes
+//	footer:    0 (uint32) | number of containers (uint64) | sha256 of every preceding byte
+//
+// Integers are big endian. The checksum detects a truncated, or corrupted,
+// snapshot. The containers themselves are verified by the engine that imports
+// them, as the ID of a container is the hash of its bytes.
+
+// SnapshotWriter writes the containers of a chain to a snapshot
+type SnapshotWriter struct {
+	w      *bufio.Writer
+	hash   hash.Hash
+	count  uint64
+	closed bool
+}
+
+// NewSnapshotWriter returns a writer of a snapshot of the chain [chainID] to
+// [w]
+func NewSnapshotWriter(w io.Writer, chainID ids.ID) (*SnapshotWriter, error) {
+	sw := &SnapshotWriter{
+		w:    bufio.NewWriter(w),
+		hash: sha256.New(),
+	}
+	header := make([]byte, 0, len(snapshotMagic)+4+32)
+	header = append(header, snapshotMagic...)
+	header = appendUint32(header, SnapshotVersion)
+	header = append(header, chainID.Bytes()...)
+	return sw, sw.write(header)
+}
+
+// Write [container] to the snapshot. A container must be written after the
+// containers it references.
+func (sw *SnapshotWriter) Write(container []byte) error {
+	switch {
+	case sw.closed:
+		return errSnapshotWriterClosed
+	case len(container) == 0:
+		return errSnapshotEmptyWrite
+	}
+	if err := sw.write(appendUint32(nil, uint32(len(container)))); err != nil {
+		return err
+	}
+	if err := sw.write(container); err != nil {
+		return err
+	}
+	sw.count++
+	return nil
+}
+
+// Count returns the number of containers written
+func (sw *SnapshotWriter) Count() uint64 { return sw.count }
+
+// Close writes the footer of the snapshot and flushes it. It doesn't close
+// the underlying writer.
+func (sw *SnapshotWriter) Close() error {
+	if sw.closed {
+		return errSnapshotWriterClosed
+	}
+	sw.closed = true
+
+	footer := appendUint32(nil, 0)
+	footer = appendUint64(footer, sw.count)
+	if err := sw.write(footer); err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(sw.hash.Sum(nil)); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+// write [b] to the snapshot and add it to the checksum
+func (sw *SnapshotWriter) write(b []byte) error {
+	sw.hash.Write(b)
+	_, err := sw.w.Write(b)
+	return err
+}
+
+// SnapshotReader reads the containers of a chain from a snapshot
+type SnapshotReader struct {
+	r                *bufio.Reader
+	hash             hash.Hash
+	maxContainerSize int
+	count            uint64
+	done             bool
+}
+
+// NewSnapshotReader returns a reader of the snapshot of the chain [chainID]
+// in [r]. Containers larger than [maxContainerSize] aren't read.
+func NewSnapshotReader(r io.Reader, chainID ids.ID, maxContainerSize int) (*SnapshotReader, error) {
+	sr := &SnapshotReader{
+		r:                bufio.NewReader(r),
+		hash:             sha256.New(),
+		maxContainerSize: maxContainerSize,
+	}
+
+	header, err := sr.read(len(snapshotMagic) + 4 + 32)
+	if err != nil {
+		return nil, errNotSnapshot
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return nil, errNotSnapshot
+	}
+	header = header[len(snapshotMagic):]
+	if version := binary.BigEndian.Uint32(header); version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", errSnapshotVersion, version)
+	}
+	snapshotChainID, err := ids.ToID(header[4:])
+	if err != nil {
+		return nil, err
+	}
+	if !snapshotChainID.Equals(chainID) {
+		return nil, fmt.Errorf("%w %s", errSnapshotWrongChain, snapshotChainID)
+	}
+	return sr, nil
+}
+
+// Next returns the next container of the snapshot. After the last container,
+// the footer is verified and io.EOF is returned.
+func (sr *SnapshotReader) Next() ([]byte, error) {
+	if sr.done {
+		return nil, io.EOF
+	}
+
+	lengthBytes, err := sr.read(4)
+	if err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(lengthBytes)
+	if length == 0 {
+		return nil, sr.verifyFooter()
+	}
+	if int64(length) > int64(sr.maxContainerSize) {
+		return nil, fmt.Errorf("%w: %d bytes", errSnapshotTooLarge, length)
+	}
+
+	container, err := sr.read(int(length))
+	if err != nil {
+		return nil, err
+	}
+	sr.count++
+	return container, nil
+}
+
+// Count returns the number of containers read
+func (sr *SnapshotReader) Count() uint64 { return sr.count }
+
+// verifyFooter reads the footer and returns io.EOF if it matches the
+// snapshot's containers
+func (sr *SnapshotReader) verifyFooter() error {
+	countBytes, err := sr.read(8)
+	if err != nil {
+		return err
+	}
+	expectedSum := sr.hash.Sum(nil)
+	sum := make([]byte, len(expectedSum))
+	if _, err := io.ReadFull(sr.r, sum); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	switch {
+	case !bytes.Equal(sum, expectedSum):
+		return errSnapshotChecksum
+	case binary.BigEndian.Uint64(countBytes) != sr.count:
+		return errSnapshotCount
+	}
+	sr.done = true
+	return io.EOF
+}
+
+// read the next [n] bytes of the snapshot and add them to the checksum
+func (sr *SnapshotReader) read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(sr.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	sr.hash.Write(b)
+	return b, nil
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF if [err] is io.EOF, as a snapshot
+// only ends after its footer
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	containers := [][]byte{{1}, {2, 2}, {3, 3, 3}}
+
+	buf := &bytes.Buffer{}
+	w, err := NewSnapshotWriter(buf, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, container := range containers {
+		if err := w.Write(container); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write(nil); err != errSnapshotEmptyWrite {
+		t.Fatalf("expected %s but got %v", errSnapshotEmptyWrite, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]byte{4}); err != errSnapshotWriterClosed {
+		t.Fatalf("expected %s but got %v", errSnapshotWriterClosed, err)
+	}
+	snapshot := buf.Bytes()
+
+	// readAll returns the containers of [snapshot] and the error that ended
+	// the read
+	readAll := func(snapshot []byte, maxContainerSize int) ([][]byte, error) {
+		r, err := NewSnapshotReader(bytes.NewReader(snapshot), chainID, maxContainerSize)
+		if err != nil {
+			return nil, err
+		}
+		read := [][]byte(nil)
+		for {
+			container, err := r.Next()
+			if err != nil {
+				return read, err
+			}
+			read = append(read, container)
+		}
+	}
+
+	read, err := readAll(snapshot, 3)
+	if err != io.EOF {
+		t.Fatalf("expected the snapshot to be read to its end but got %v", err)
+	}
+	if len(read) != len(containers) {
+		t.Fatalf("expected %d containers but read %d", len(containers), len(read))
+	}
+	for i, container := range containers {
+		if !bytes.Equal(read[i], container) {
+			t.Fatalf("expected container %d to be %v but was %v", i, container, read[i])
+		}
+	}
+
+	if _, err := readAll(snapshot, 2); err == nil || err == io.EOF {
+		t.Fatalf("shouldn't have read a container larger than the maximum size")
+	}
+	if _, err := NewSnapshotReader(bytes.NewReader(snapshot), ids.Empty.Prefix(1), 3); err == nil {
+		t.Fatalf("shouldn't have read the snapshot of another chain")
+	}
+	if _, err := NewSnapshotReader(bytes.NewReader([]byte("not a snapshot")), chainID, 3); err != errNotSnapshot {
+		t.Fatalf("expected %s but got %v", errNotSnapshot, err)
+	}
+	if _, err := readAll(snapshot[:len(snapshot)-1], 3); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected a truncated snapshot to fail with %s but got %v", io.ErrUnexpectedEOF, err)
+	}
+
+	corrupted := append([]byte(nil), snapshot...)
+	corrupted[len(corrupted)-46]++ // A byte of the last container
+	if _, err := readAll(corrupted, 3); err != errSnapshotChecksum {
+		t.Fatalf("expected %s but got %v", errSnapshotChecksum, err)
+	}
+}
@@ -33,18 +33,23 @@ type EngineTest struct {
 	CantGetFailed,
 	CantPut,
 
+	CantGetAncestors,
+	CantGetAncestorsFailed,
+	CantMultiPut,
+
 	CantPushQuery,
 	CantPullQuery,
 	CantQueryFailed,
 	CantChits bool
 
-	StartupF, ShutdownF                                                                func()
-	ContextF                                                                           func() *snow.Context
-	NotifyF                                                                            func(Message)
-	GetF, GetFailedF, PullQueryF                                                       func(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
-	PutF, PushQueryF                                                                   func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
-	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
-	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                 func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
+	StartupF, ShutdownF                                                                                     func()
+	ContextF                                                                                                func() *snow.Context
+	NotifyF                                                                                                 func(Message)
+	GetF, GetFailedF, GetAncestorsF, PullQueryF                                                             func(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+	PutF, PushQueryF                                                                                        func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
+	MultiPutF                                                                                               func(validatorID ids.ShortID, requestID uint32, containers [][]byte)
+	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, GetAncestorsFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
+	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                                      func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
 }
 
 // Default ...
@@ -68,6 +73,10 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantGetFailed = cant
 	e.CantPut = cant
 
+	e.CantGetAncestors = cant
+	e.CantGetAncestorsFailed = cant
+	e.CantMultiPut = cant
+
 	e.CantPushQuery = cant
 	e.CantPullQuery = cant
 	e.CantQueryFailed = cant
@@ -193,6 +202,33 @@ func (e *EngineTest) Put(validatorID ids.ShortID, requestID uint32, containerID
 	}
 }
 
+// GetAncestors ...
+func (e *EngineTest) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	if e.GetAncestorsF != nil {
+		e.GetAncestorsF(validatorID, requestID, containerID)
+	} else if e.CantGetAncestors && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAncestors")
+	}
+}
+
+// GetAncestorsFailed ...
+func (e *EngineTest) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	if e.GetAncestorsFailedF != nil {
+		e.GetAncestorsFailedF(validatorID, requestID)
+	} else if e.CantGetAncestorsFailed && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAncestorsFailed")
+	}
+}
+
+// MultiPut ...
+func (e *EngineTest) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	if e.MultiPutF != nil {
+		e.MultiPutF(validatorID, requestID, containers)
+	} else if e.CantMultiPut && e.T != nil {
+		e.T.Fatalf("Unexpectedly called MultiPut")
+	}
+}
+
 // PushQuery ...
 func (e *EngineTest) PushQuery(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
 	if e.PushQueryF != nil {
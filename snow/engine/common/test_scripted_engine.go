@@ -0,0 +1,197 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+)
+
+// Call is a message that an engine received. Only the fields the message
+// carries are set.
+type Call struct {
+	Op           string // Name of the engine method, such as "PullQuery"
+	ValidatorID  ids.ShortID
+	RequestID    uint32
+	ContainerID  ids.ID
+	ContainerIDs ids.Set
+	Container    []byte
+	Containers   [][]byte
+	Message      Message
+}
+
+// Equals returns true if [c] and [o] are the same message
+func (c Call) Equals(o Call) bool {
+	return c.Op == o.Op &&
+		c.ValidatorID.Equals(o.ValidatorID) &&
+		c.RequestID == o.RequestID &&
+		c.ContainerID.Equals(o.ContainerID) &&
+		c.ContainerIDs.Equals(o.ContainerIDs) &&
+		bytes.Equal(c.Container, o.Container) &&
+		equalContainers(c.Containers, o.Containers) &&
+		c.Message == o.Message
+}
+
+func equalContainers(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, container := range a {
+		if !bytes.Equal(container, b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s(validatorID=%s, requestID=%d, containerID=%s, containerIDs=%s, container=%x, containers=%x, message=%s)",
+		c.Op, c.ValidatorID, c.RequestID, c.ContainerID, c.ContainerIDs, c.Container, c.Containers, c.Message)
+}
+
+// ScriptedEngine is a test engine that records the messages it receives, so
+// that a test can check the engine received exactly the expected messages, in
+// order. Unlike EngineTest, it accepts every message. If [OnCall] is set, it's
+// called with every message, which lets a test script the engine's reactions.
+type ScriptedEngine struct {
+	T      *testing.T
+	Ctx    *snow.Context
+	OnCall func(Call)
+
+	lock  sync.Mutex
+	calls []Call
+}
+
+// Calls returns the messages this engine received, in order
+func (e *ScriptedEngine) Calls() []Call {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return append([]Call(nil), e.calls...)
+}
+
+// Expect fails the test unless this engine received exactly [expected], in
+// order, since it was created or Expect was last called
+func (e *ScriptedEngine) Expect(expected ...Call) {
+	e.lock.Lock()
+	calls := e.calls
+	e.calls = nil
+	e.lock.Unlock()
+
+	if len(calls) != len(expected) {
+		e.T.Fatalf("Expected %d messages but received %d: %v", len(expected), len(calls), calls)
+	}
+	for i, call := range calls {
+		if !call.Equals(expected[i]) {
+			e.T.Fatalf("Message %d should have been %s but was %s", i, expected[i], call)
+		}
+	}
+}
+
+func (e *ScriptedEngine) record(call Call) {
+	e.lock.Lock()
+	e.calls = append(e.calls, call)
+	e.lock.Unlock()
+
+	if e.OnCall != nil {
+		e.OnCall(call)
+	}
+}
+
+// Context implements the Engine interface
+func (e *ScriptedEngine) Context() *snow.Context { return e.Ctx }
+
+// Startup implements the Engine interface
+func (e *ScriptedEngine) Startup() { e.record(Call{Op: "Startup"}) }
+
+// Shutdown implements the Engine interface
+func (e *ScriptedEngine) Shutdown() { e.record(Call{Op: "Shutdown"}) }
+
+// Notify implements the Engine interface
+func (e *ScriptedEngine) Notify(msg Message) { e.record(Call{Op: "Notify", Message: msg}) }
+
+// GetAcceptedFrontier implements the Engine interface
+func (e *ScriptedEngine) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
+	e.record(Call{Op: "GetAcceptedFrontier", ValidatorID: validatorID, RequestID: requestID})
+}
+
+// AcceptedFrontier implements the Engine interface
+func (e *ScriptedEngine) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	e.record(Call{Op: "AcceptedFrontier", ValidatorID: validatorID, RequestID: requestID, ContainerIDs: containerIDs})
+}
+
+// GetAcceptedFrontierFailed implements the Engine interface
+func (e *ScriptedEngine) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
+	e.record(Call{Op: "GetAcceptedFrontierFailed", ValidatorID: validatorID, RequestID: requestID})
+}
+
+// GetAccepted implements the Engine interface
+func (e *ScriptedEngine) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	e.record(Call{Op: "GetAccepted", ValidatorID: validatorID, RequestID: requestID, ContainerIDs: containerIDs})
+}
+
+// Accepted implements the Engine interface
+func (e *ScriptedEngine) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	e.record(Call{Op: "Accepted", ValidatorID: validatorID, RequestID: requestID, ContainerIDs: containerIDs})
+}
+
+// GetAcceptedFailed implements the Engine interface
+func (e *ScriptedEngine) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
+	e.record(Call{Op: "GetAcceptedFailed", ValidatorID: validatorID, RequestID: requestID})
+}
+
+// Get implements the Engine interface
+func (e *ScriptedEngine) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	e.record(Call{Op: "Get", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID})
+}
+
+// Put implements the Engine interface
+func (e *ScriptedEngine) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
+	e.record(Call{Op: "Put", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID, Container: container})
+}
+
+// GetFailed implements the Engine interface
+func (e *ScriptedEngine) GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	e.record(Call{Op: "GetFailed", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID})
+}
+
+// GetAncestors implements the Engine interface
+func (e *ScriptedEngine) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	e.record(Call{Op: "GetAncestors", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID})
+}
+
+// MultiPut implements the Engine interface
+func (e *ScriptedEngine) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	e.record(Call{Op: "MultiPut", ValidatorID: validatorID, RequestID: requestID, Containers: containers})
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (e *ScriptedEngine) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	e.record(Call{Op: "GetAncestorsFailed", ValidatorID: validatorID, RequestID: requestID})
+}
+
+// PullQuery implements the Engine interface
+func (e *ScriptedEngine) PullQuery(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	e.record(Call{Op: "PullQuery", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID})
+}
+
+// PushQuery implements the Engine interface
+func (e *ScriptedEngine) PushQuery(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
+	e.record(Call{Op: "PushQuery", ValidatorID: validatorID, RequestID: requestID, ContainerID: containerID, Container: container})
+}
+
+// Chits implements the Engine interface
+func (e *ScriptedEngine) Chits(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	e.record(Call{Op: "Chits", ValidatorID: validatorID, RequestID: requestID, ContainerIDs: containerIDs})
+}
+
+// QueryFailed implements the Engine interface
+func (e *ScriptedEngine) QueryFailed(validatorID ids.ShortID, requestID uint32) {
+	e.record(Call{Op: "QueryFailed", ValidatorID: validatorID, RequestID: requestID})
+}
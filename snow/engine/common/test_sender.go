@@ -16,6 +16,7 @@ type SenderTest struct {
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
 	CantGet, CantPut,
+	CantGetAncestors, CantMultiPut,
 	CantPullQuery, CantPushQuery, CantChits bool
 
 	GetAcceptedFrontierF func(ids.ShortSet, uint32)
@@ -24,6 +25,8 @@ type SenderTest struct {
 	AcceptedF            func(ids.ShortID, uint32, ids.Set)
 	GetF                 func(ids.ShortID, uint32, ids.ID)
 	PutF                 func(ids.ShortID, uint32, ids.ID, []byte)
+	GetAncestorsF        func(ids.ShortID, uint32, ids.ID)
+	MultiPutF            func(ids.ShortID, uint32, [][]byte)
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, ids.Set)
@@ -37,6 +40,8 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantAccepted = cant
 	s.CantGet = cant
 	s.CantPut = cant
+	s.CantGetAncestors = cant
+	s.CantMultiPut = cant
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
@@ -108,6 +113,28 @@ func (s *SenderTest) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtx []
 	}
 }
 
+// GetAncestors calls GetAncestorsF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetAncestors(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	if s.GetAncestorsF != nil {
+		s.GetAncestorsF(vdr, requestID, vtxID)
+	} else if s.CantGetAncestors && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetAncestors")
+	}
+}
+
+// MultiPut calls MultiPutF if it was initialized. If it wasn't initialized and
+// this function shouldn't be called and testing was initialized, then testing
+// will fail.
+func (s *SenderTest) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	if s.MultiPutF != nil {
+		s.MultiPutF(vdr, requestID, vtxs)
+	} else if s.CantMultiPut && s.T != nil {
+		s.T.Fatalf("Unexpectedly called MultiPut")
+	}
+}
+
 // PushQuery calls PushQueryF if it was initialized. If it wasn't initialized
 // and this function shouldn't be called and testing was initialized, then
 // testing will fail.
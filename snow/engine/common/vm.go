@@ -73,3 +73,16 @@ type StaticVM interface {
 	// genesis bytes this VM can interpret.
 	CreateStaticHandlers() map[string]*HTTPHandler
 }
+
+// GenesisVerifier describes a VM that can verify the genesis data of a chain
+// before the chain is created, so that a chain that could never be initialized
+// isn't created.
+type GenesisVerifier interface {
+	// VerifyGenesis returns an error if a chain running this VM, with the
+	// feature extensions [fxs], can't be initialized with [genesisBytes] when
+	// its containers are limited to [limits].
+	//
+	// It's called on a new instance of the VM, which hasn't been initialized,
+	// and new instances of the feature extensions.
+	VerifyGenesis(limits snow.Limits, genesisBytes []byte, fxs []*Fx) error
+}
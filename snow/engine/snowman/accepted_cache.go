@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+const (
+	// acceptedCacheSize is the number of recently accepted blocks that are
+	// kept in memory to serve requests from peers
+	acceptedCacheSize = 2048
+
+	// acceptedCacheID identifies the accepted cache in the consensus
+	// dispatcher
+	acceptedCacheID = "engineAcceptedCache"
+)
+
+type acceptedBlock struct {
+	bytes    []byte
+	parentID ids.ID // Zero if the block has no parent
+}
+
+// acceptedCache maps recently accepted blocks to their bytes and parents, so
+// that serving bootstrapping peers doesn't require hitting the database. It's
+// filled as blocks are accepted by consensus and as accepted blocks are served.
+type acceptedCache struct {
+	vm   ChainVM
+	blks cache.LRU
+}
+
+func (c *acceptedCache) init(vm ChainVM) {
+	c.vm = vm
+	c.blks.Size = acceptedCacheSize
+}
+
+// Accept implements the triggers.Acceptor interface
+func (c *acceptedCache) Accept(_, blkID ids.ID, blkBytes []byte) error {
+	blk, err := c.vm.GetBlock(blkID)
+	if err != nil {
+		return err
+	}
+	c.put(blk, blkBytes)
+	return nil
+}
+
+// get returns the bytes and parent of the block, if it was recently accepted
+func (c *acceptedCache) get(blkID ids.ID) (*acceptedBlock, bool) {
+	if blk, ok := c.blks.Get(blkID); ok {
+		return blk.(*acceptedBlock), true
+	}
+	return nil, false
+}
+
+// add caches [blk] if it's accepted and returns its bytes
+func (c *acceptedCache) add(blk snowman.Block) []byte {
+	blkBytes := blk.Bytes()
+	if blk.Status() == choices.Accepted {
+		c.put(blk, blkBytes)
+	}
+	return blkBytes
+}
+
+func (c *acceptedCache) put(blk snowman.Block, blkBytes []byte) {
+	parentID := ids.ID{}
+	if parent := blk.Parent(); parent != nil {
+		parentID = parent.ID()
+	}
+	c.blks.Put(blk.ID(), &acceptedBlock{
+		bytes:    blkBytes,
+		parentID: parentID,
+	})
+}
@@ -5,6 +5,7 @@ package snowman
 
 import (
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
@@ -30,7 +31,10 @@ type bootstrapper struct {
 	metrics
 	common.Bootstrapper
 
-	pending    ids.Set
+	// outstandingRequests tracks which validators were asked for which blocks
+	// in which requests
+	outstandingRequests common.Requests
+
 	finished   bool
 	onFinished func()
 }
@@ -73,38 +77,77 @@ func (b *bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) {
 		b.fetch(blkID)
 	}
 
-	if numPending := b.pending.Len(); numPending == 0 {
+	if numPending := b.outstandingRequests.Len(); numPending == 0 {
 		// TODO: This typically indicates bootstrapping has failed, so this
 		// should be handled appropriately
 		b.finish()
 	}
 }
 
-// Put ...
-func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkBytes []byte) {
-	b.BootstrapConfig.Context.Log.Verbo("Put called for blkID %s", blkID)
+// MultiPut ...
+func (b *bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte) {
+	// Make sure this is in response to a request we made
+	wantedBlkID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("received unexpected MultiPut from %s with ID %d", vdr, requestID)
+		return
+	}
 
-	if !b.pending.Contains(blkID) {
+	if len(blks) == 0 {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains no blocks", vdr, requestID)
+		b.sendRequest(wantedBlkID)
 		return
 	}
+	if len(blks) > common.MaxContainersPerMultiPut {
+		blks = blks[:common.MaxContainersPerMultiPut]
+	}
 
-	blk, err := b.VM.ParseBlock(blkBytes)
+	// The first block must be the one we asked for
+	wantedBlk, err := b.VM.ParseBlock(blks[0])
 	if err != nil {
-		b.BootstrapConfig.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
+		b.BootstrapConfig.Context.Log.Debug("ParseBlock failed due to %s for block:\n%s",
 			err,
-			formatting.DumpBytes{Bytes: blkBytes})
-		b.GetFailed(vdr, requestID, blkID)
+			formatting.DumpBytes{Bytes: blks[0]})
+		b.BootstrapConfig.Context.ReportMisbehavior(vdr, snow.InvalidContainer, blks[0], wantedBlkID)
+		b.sendRequest(wantedBlkID)
+		return
+	}
+	if !wantedBlk.ID().Equals(wantedBlkID) {
+		b.BootstrapConfig.Context.Log.Debug("expected the first block of MultiPut(%s, %d) to be %s but was %s",
+			vdr, requestID, wantedBlkID, wantedBlk.ID())
+		b.BootstrapConfig.Context.ReportMisbehavior(vdr, snow.MismatchedContainer, blks[0], wantedBlkID, wantedBlk.ID())
+		b.sendRequest(wantedBlkID)
 		return
 	}
 
-	b.addBlock(blk)
+	// The rest are the ancestors of the block we asked for
+	ancestors := make(map[[32]byte]snowman.Block, len(blks)-1)
+	for _, blkBytes := range blks[1:] {
+		blk, err := b.VM.ParseBlock(blkBytes)
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("ParseBlock failed due to %s for block:\n%s",
+				err,
+				formatting.DumpBytes{Bytes: blkBytes})
+			break
+		}
+		ancestors[blk.ID().Key()] = blk
+	}
+
+	b.addBlock(wantedBlk, ancestors)
 }
 
-// GetFailed ...
-func (b *bootstrapper) GetFailed(_ ids.ShortID, _ uint32, blkID ids.ID) { b.sendRequest(blkID) }
+// GetAncestorsFailed ...
+func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	blkID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+		return
+	}
+	b.sendRequest(blkID)
+}
 
 func (b *bootstrapper) fetch(blkID ids.ID) {
-	if b.pending.Contains(blkID) {
+	if b.outstandingRequests.Contains(blkID) {
 		return
 	}
 
@@ -113,11 +156,11 @@ func (b *bootstrapper) fetch(blkID ids.ID) {
 		b.sendRequest(blkID)
 		return
 	}
-	b.addBlock(blk)
+	b.addBlock(blk, nil)
 }
 
 func (b *bootstrapper) sendRequest(blkID ids.ID) {
-	validators := b.BootstrapConfig.Validators.Sample(1)
+	validators := b.BootstrapConfig.Validators.SampleFrom(1, b.BootstrapConfig.Context.Source)
 	if len(validators) == 0 {
 		b.BootstrapConfig.Context.Log.Error("Dropping request for %s as there are no validators", blkID)
 		return
@@ -125,17 +168,20 @@ func (b *bootstrapper) sendRequest(blkID ids.ID) {
 	validatorID := validators[0].ID()
 	b.RequestID++
 
-	b.pending.Add(blkID)
-	b.BootstrapConfig.Sender.Get(validatorID, b.RequestID, blkID)
+	b.outstandingRequests.Add(validatorID, b.RequestID, blkID)
+	b.BootstrapConfig.Sender.GetAncestors(validatorID, b.RequestID, blkID)
 
-	b.numPendingRequests.Set(float64(b.pending.Len()))
+	b.numPendingRequests.Set(float64(b.outstandingRequests.Len()))
 }
 
-func (b *bootstrapper) addBlock(blk snowman.Block) {
+// addBlock queues [blk] and its processing ancestors to be accepted. Ancestors
+// this node doesn't have are taken from [ancestors] if they're there, and
+// requested otherwise.
+func (b *bootstrapper) addBlock(blk snowman.Block, ancestors map[[32]byte]snowman.Block) {
 	status := blk.Status()
 	blkID := blk.ID()
 	for status == choices.Processing {
-		b.pending.Remove(blkID)
+		b.outstandingRequests.RemoveAny(blkID)
 
 		if err := b.Blocked.Push(&blockJob{
 			numAccepted: b.numBootstrapped,
@@ -146,8 +192,11 @@ func (b *bootstrapper) addBlock(blk snowman.Block) {
 		}
 
 		blk = blk.Parent()
-		status = blk.Status()
 		blkID = blk.ID()
+		if ancestor, ok := ancestors[blkID.Key()]; ok && blk.Status() == choices.Unknown {
+			blk = ancestor
+		}
+		status = blk.Status()
 	}
 
 	switch status := blk.Status(); status {
@@ -159,7 +208,7 @@ func (b *bootstrapper) addBlock(blk snowman.Block) {
 		b.BootstrapConfig.Context.Log.Error("Bootstrapping wants to accept %s, however it was previously rejected", blkID)
 	}
 
-	numPending := b.pending.Len()
+	numPending := b.outstandingRequests.Len()
 	b.numPendingRequests.Set(float64(numPending))
 	if numPending == 0 {
 		b.finish()
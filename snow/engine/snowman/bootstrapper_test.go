@@ -113,7 +113,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 
 	reqID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, innerReqID uint32, blkID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, innerReqID uint32, blkID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -129,7 +129,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
@@ -143,7 +143,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqID, blkID1, blkBytes1)
+	bs.MultiPut(peerID, *reqID, [][]byte{blkBytes1})
 
 	vm.ParseBlockF = nil
 	bs.onFinished = nil
@@ -206,7 +206,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -222,12 +222,14 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
 		case bytes.Equal(blkBytes, blkBytes1):
 			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			return blk2, nil
 		}
 		t.Fatal(errUnknownBlock)
 		return nil, errUnknownBlock
@@ -236,8 +238,26 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, blkID2, blkBytes2)
-	bs.Put(peerID, *requestID, blkID1, blkBytes1)
+	// A response that doesn't start with the requested block is dropped, and
+	// the block is requested again
+	oldReqID := *requestID
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !blkID.Equals(blkID1) {
+			t.Fatalf("Should have requested block %s again, requested %s", blkID1, blkID)
+		}
+		*requestID = reqID
+	}
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes2})
+	sender.GetAncestorsF = nil
+	if *requestID == oldReqID {
+		t.Fatalf("Should have requested block %s again", blkID1)
+	}
+
+	bs.MultiPut(peerID, oldReqID, [][]byte{blkBytes1})
+	if *finished {
+		t.Fatalf("Shouldn't have accepted a response to an abandoned request")
+	}
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
 
 	vm.ParseBlockF = nil
 
@@ -302,7 +322,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -318,7 +338,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
@@ -336,7 +356,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, blkID1, blkBytes1)
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
 
 	if !*finished {
 		t.Fatalf("Bootstrapping should have finished")
@@ -349,6 +369,120 @@ func TestBootstrapperDependency(t *testing.T) {
 	}
 }
 
+func TestBootstrapperMultiPut(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+	blkID3 := ids.Empty.Prefix(3)
+
+	blkBytes0 := []byte{0}
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+	blkBytes3 := []byte{3}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+		bytes:  blkBytes0,
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Processing,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Processing,
+		bytes:  blkBytes2,
+	}
+	blk3 := &Blk{
+		parent: blk2,
+		id:     blkID3,
+		height: 3,
+		status: choices.Processing,
+		bytes:  blkBytes3,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID3)
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID3):
+			return nil, errUnknownBlock
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
+		}
+		if !blkID.Equals(blkID3) {
+			t.Fatalf("Requested unknown block")
+		}
+		*requestID = reqID
+	}
+
+	bs.ForceAccepted(acceptedIDs)
+
+	vm.GetBlockF = nil
+
+	// A failed request is sent again
+	oldReqID := *requestID
+	bs.GetAncestorsFailed(peerID, *requestID)
+	if *requestID == oldReqID {
+		t.Fatalf("Should have requested block %s again", blkID3)
+	}
+
+	sender.GetAncestorsF = nil
+
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			return blk2, nil
+		case bytes.Equal(blkBytes, blkBytes3):
+			return blk3, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	// The block and all its missing ancestors arrive in one message, so
+	// nothing else is requested
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes3, blkBytes2, blkBytes1})
+
+	vm.ParseBlockF = nil
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	for _, blk := range []*Blk{blk1, blk2, blk3} {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("Block %s should be accepted", blk.ID())
+		}
+	}
+}
+
 func TestBootstrapperAcceptedFrontier(t *testing.T) {
 	config, _, _, vm := newConfig(t)
 
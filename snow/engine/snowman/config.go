@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 )
@@ -14,4 +15,9 @@ type Config struct {
 
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
+
+	// DB stores the blocks that are processing when the engine shuts down, so
+	// that a restarted engine resumes voting on them without fetching them
+	// again. If nil, they aren't stored.
+	DB database.Database
 }
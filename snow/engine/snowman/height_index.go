@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errHeightIndex = errors.New("height index doesn't end at the last accepted block")
+	errNotAncestor = errors.New("accepted block isn't an ancestor of the last accepted block")
+)
+
+// lastAcceptedHeight returns the height of [vm]'s last accepted block, and
+// checks that the height index has the last accepted block at that height
+func lastAcceptedHeight(vm HeightIndexedChainVM) (uint64, error) {
+	height, err := vm.LastAcceptedHeight()
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get the last accepted block's height: %w", err)
+	}
+	blkID, err := vm.GetBlockIDAtHeight(height)
+	if err != nil {
+		return 0, err
+	}
+	if lastAcceptedID := vm.LastAccepted(); !blkID.Equals(lastAcceptedID) {
+		return 0, fmt.Errorf("%w: %s is at height %d but %s was last accepted", errHeightIndex, blkID, height, lastAcceptedID)
+	}
+	return height, nil
+}
+
+// verifyAncestor checks that the accepted block [blkID] is the ancestor at
+// [height] of [vm]'s last accepted block, whose height is [lastHeight]. The
+// ancestor is looked up in the height index, rather than by walking back
+// through the parents of the last accepted block.
+func verifyAncestor(vm HeightIndexedChainVM, blkID ids.ID, height, lastHeight uint64) error {
+	if height > lastHeight {
+		return fmt.Errorf("%w: %s would be at height %d, above the last accepted height %d", errNotAncestor, blkID, height, lastHeight)
+	}
+	ancestorID, err := vm.GetBlockIDAtHeight(height)
+	if err != nil {
+		return err
+	}
+	if !ancestorID.Equals(blkID) {
+		return fmt.Errorf("%w: %s was accepted at height %d, not %s", errNotAncestor, ancestorID, height, blkID)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var errNoHeight = errors.New("no block at height")
+
+// heightIndexedVM is a VMTest whose accepted blocks are indexed by height
+type heightIndexedVM struct {
+	*VMTest
+	blkIDs []ids.ID
+}
+
+func (vm *heightIndexedVM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	if height >= uint64(len(vm.blkIDs)) {
+		return ids.ID{}, errNoHeight
+	}
+	return vm.blkIDs[height], nil
+}
+
+func (vm *heightIndexedVM) LastAcceptedHeight() (uint64, error) {
+	return uint64(len(vm.blkIDs) - 1), nil
+}
+
+// newHeightIndexedVM returns a VM whose chain is a genesis block and [numBlks]
+// blocks built on it, of which the first [numAccepted] are accepted. The IDs of
+// the blocks are offset by [idOffset], other than the genesis block's.
+func newHeightIndexedVM(t *testing.T, numBlks, numAccepted int, idOffset uint64) (*heightIndexedVM, []*Blk) {
+	blks := []*Blk{{
+		parent: &Blk{id: ids.Empty.Prefix(1000000), status: choices.Unknown},
+		id:     ids.Empty.Prefix(0),
+		status: choices.Accepted,
+		bytes:  []byte{0},
+	}}
+	for i := 1; i <= numBlks; i++ {
+		status := choices.Accepted
+		if i > numAccepted {
+			status = choices.Processing
+		}
+		blks = append(blks, &Blk{
+			parent: blks[i-1],
+			id:     ids.Empty.Prefix(uint64(i) + idOffset),
+			height: i,
+			status: status,
+			bytes:  []byte{byte(i), byte(i >> 8), byte(idOffset)},
+		})
+	}
+
+	vm := &heightIndexedVM{VMTest: &VMTest{}}
+	vm.T = t
+	vm.Default(true)
+	for _, blk := range blks[:numAccepted+1] {
+		vm.blkIDs = append(vm.blkIDs, blk.ID())
+	}
+	vm.LastAcceptedF = func() ids.ID { return blks[numAccepted].ID() }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID().Equals(blkID) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	return vm, blks
+}
+
+func TestLastAcceptedHeight(t *testing.T) {
+	for _, numBlks := range []int{0, 1, 2, 7, 64} {
+		vm, _ := newHeightIndexedVM(t, numBlks, numBlks, 0)
+		height, err := lastAcceptedHeight(vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != uint64(numBlks) {
+			t.Fatalf("expected the last accepted block at height %d but got %d", numBlks, height)
+		}
+	}
+
+	// The index must end at the last accepted block
+	vm, _ := newHeightIndexedVM(t, 10, 10, 0)
+	vm.blkIDs = vm.blkIDs[:5]
+	if _, err := lastAcceptedHeight(vm); !errors.Is(err, errHeightIndex) {
+		t.Fatalf("expected %s but got %v", errHeightIndex, err)
+	}
+}
+
+func TestImportIndexedSnapshot(t *testing.T) {
+	chainID := ids.Empty.Prefix(100)
+	exporter, _ := newHeightIndexedVM(t, 5, 5, 0)
+
+	buf := &bytes.Buffer{}
+	w, err := common.NewSnapshotWriter(buf, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExportSnapshot(exporter, w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := buf.Bytes()
+
+	// The blocks the chain already accepted are checked against the index,
+	// and the rest are accepted
+	vm, blks := newHeightIndexedVM(t, 5, 3, 0)
+	r, err := common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := ImportSnapshot(vm, r); err != nil {
+		t.Fatal(err)
+	} else if imported != 2 {
+		t.Fatalf("expected 2 blocks to be imported but %d were", imported)
+	}
+	for _, blk := range blks {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("block %s should have been accepted", blk.ID())
+		}
+	}
+
+	// An accepted block at a height where the chain accepted another block
+	// isn't an ancestor of the last accepted block
+	vm, blks = newHeightIndexedVM(t, 5, 3, 0)
+	vm.blkIDs[2] = ids.Empty.Prefix(500)
+	vm.LastAcceptedF = func() ids.ID { return vm.blkIDs[3] }
+	r, err = common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportSnapshot(vm, r); !errors.Is(err, errNotAncestor) {
+		t.Fatalf("expected %s but got %v", errNotAncestor, err)
+	}
+	if blks[4].Status() == choices.Accepted {
+		t.Fatalf("shouldn't have accepted blocks after a block that isn't an ancestor")
+	}
+
+	// An accepted block above the last accepted height isn't an ancestor
+	vm, _ = newHeightIndexedVM(t, 5, 5, 0)
+	vm.blkIDs = vm.blkIDs[:3]
+	vm.LastAcceptedF = func() ids.ID { return vm.blkIDs[2] }
+	r, err = common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportSnapshot(vm, r); !errors.Is(err, errNotAncestor) {
+		t.Fatalf("expected %s but got %v", errNotAncestor, err)
+	}
+}
+
+func TestExportIndexedSnapshot(t *testing.T) {
+	chainID := ids.Empty.Prefix(100)
+	vm, blks := newHeightIndexedVM(t, 300, 300, 0)
+
+	buf := &bytes.Buffer{}
+	w, err := common.NewSnapshotWriter(buf, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ExportSnapshot(vm, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 300 {
+		t.Fatalf("expected every block but the genesis block to be exported but %d were", exported)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := common.NewSnapshotReader(bytes.NewReader(buf.Bytes()), chainID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, blk := range blks[1:] {
+		blkBytes, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blkBytes, blk.Bytes()) {
+			t.Fatalf("expected block %s to be exported in order", blk.ID())
+		}
+	}
+}
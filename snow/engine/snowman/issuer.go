@@ -26,6 +26,7 @@ func (i *issuer) Abandon(ids.ID) {
 	if !i.abandoned {
 		blkID := i.blk.ID()
 		i.t.pending.Remove(blkID)
+		delete(i.t.verifying, blkID.Key())
 		i.t.blocked.Abandon(blkID)
 
 		// Tracks performance statistics
@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// processingKey is the key the processing blocks are stored under in the
+// engine's database
+var processingKey = []byte("processing")
+
+// saveProcessing stores the bytes of the blocks that are processing in the
+// engine's database, so that they're issued again when the engine restarts
+// instead of being fetched from peers. The preferred blocks come first, so
+// that issuing the blocks in order restores the preference.
+func (t *Transitive) saveProcessing() error {
+	if t.Config.DB == nil || !t.bootstrapped {
+		return nil
+	}
+
+	preferred := [][]byte(nil)
+	others := [][]byte(nil)
+	size := wrappers.IntLen
+	for _, node := range t.Consensus.Graph().Nodes {
+		if node.Status != choices.Processing {
+			continue
+		}
+		blk, err := t.Config.VM.GetBlock(node.ID)
+		if err != nil {
+			return err
+		}
+		blkBytes := blk.Bytes()
+		if node.Preferred {
+			preferred = append(preferred, blkBytes)
+		} else {
+			others = append(others, blkBytes)
+		}
+		size += wrappers.IntLen + len(blkBytes)
+	}
+	if len(preferred)+len(others) == 0 {
+		return t.Config.DB.Delete(processingKey)
+	}
+
+	p := wrappers.Packer{MaxSize: size}
+	p.Pack2DByteSlice(append(preferred, others...))
+	if p.Errored() {
+		return p.Err
+	}
+	return t.Config.DB.Put(processingKey, p.Bytes)
+}
+
+// restoreProcessing issues the blocks that were processing when the engine
+// last shut down, and forgets them, so that they're only restored once. Blocks
+// that were decided while bootstrapping are skipped. Assumes the engine has
+// finished bootstrapping.
+func (t *Transitive) restoreProcessing() error {
+	if t.Config.DB == nil {
+		return nil
+	}
+
+	processingBytes, err := t.Config.DB.Get(processingKey)
+	if err == database.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := t.Config.DB.Delete(processingKey); err != nil {
+		return err
+	}
+
+	p := wrappers.Packer{Bytes: processingBytes}
+	blks := p.Unpack2DByteSlice()
+	if p.Errored() {
+		return p.Err
+	}
+
+	restored := 0
+	for _, blkBytes := range blks {
+		blk, err := t.Config.VM.ParseBlock(blkBytes)
+		if err != nil {
+			t.Config.Context.Log.Debug("couldn't parse a block that was processing: %s", err)
+			continue
+		}
+		if blk.Status() != choices.Processing || t.Consensus.Issued(blk) || t.pending.Contains(blk.ID()) {
+			continue
+		}
+		// A block waits for its parent, which is restored too, to be issued
+		t.insert(blk)
+		restored++
+	}
+	t.Config.Context.Log.Info("restored %d of the %d blocks that were processing when the chain shut down", restored, len(blks))
+	return nil
+}
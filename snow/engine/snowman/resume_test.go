@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestEngineResumesProcessingBlocks(t *testing.T) {
+	db := memdb.New()
+	gBlk := &Blk{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	preferred := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{1},
+	}
+	tail := &Blk{
+		parent: preferred,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{2},
+	}
+	conflicting := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{3},
+	}
+	blks := []*Blk{preferred, tail, conflicting}
+
+	newEngine := func() (*Transitive, *VMTest) {
+		config := DefaultConfig()
+		config.DB = db
+
+		vals := validators.NewSet()
+		vals.Add(validators.GenerateRandomValidator(1))
+		config.Validators = vals
+
+		sender := &common.SenderTest{}
+		sender.T = t
+		sender.Default(false)
+		config.Sender = sender
+
+		vm := &VMTest{}
+		vm.T = t
+		vm.Default(true)
+		vm.CantSetPreference = false
+		vm.CantShutdown = false
+		vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+		vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+			for _, blk := range blks {
+				if blk.ID().Equals(blkID) {
+					return blk, nil
+				}
+			}
+			t.Fatalf("unexpected block %s", blkID)
+			return nil, errGetBlock
+		}
+		config.VM = vm
+
+		te := &Transitive{}
+		te.Initialize(config)
+		return te, vm
+	}
+
+	te, _ := newEngine()
+	te.finishBootstrapping()
+	for _, blk := range blks {
+		if !te.insertAll(blk) {
+			t.Fatalf("block %s should have been issued", blk.ID())
+		}
+	}
+	te.Shutdown()
+
+	te, vm := newEngine()
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		t.Fatal(errUnknownBytes)
+		return nil, errUnknownBytes
+	}
+	te.finishBootstrapping()
+
+	for _, blk := range blks {
+		if !te.Consensus.Issued(blk) {
+			t.Fatalf("block %s should have been restored", blk.ID())
+		}
+	}
+	if pref := te.Consensus.Preference(); !pref.Equals(tail.ID()) {
+		t.Fatalf("the preference should have been restored to %s but it's %s", tail.ID(), pref)
+	}
+	if has, err := db.Has(processingKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("the blocks should only be restored once")
+	}
+}
@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var errSnapshotLink = errors.New("snapshot block isn't a child of the last accepted block")
+
+// ExportSnapshot writes the blocks [vm] accepted after its genesis block to
+// [w], oldest first, and returns how many were written. Assumes the chain's
+// context lock is held.
+func ExportSnapshot(vm ChainVM, w *common.SnapshotWriter) (int, error) {
+	if vm, ok := vm.(HeightIndexedChainVM); ok {
+		return exportIndexedSnapshot(vm, w)
+	}
+
+	blk, err := vm.GetBlock(vm.LastAccepted())
+	if err != nil {
+		return 0, err
+	}
+
+	// Only the IDs are kept while walking back to the genesis block, so that
+	// the chain doesn't have to fit in memory
+	blkIDs := []ids.ID(nil)
+	for parent := blk.Parent(); parent.Status() == choices.Accepted; parent = blk.Parent() {
+		blkIDs = append(blkIDs, blk.ID())
+		blk = parent
+	}
+
+	for i := len(blkIDs) - 1; i >= 0; i-- {
+		blk, err := vm.GetBlock(blkIDs[i])
+		if err != nil {
+			return len(blkIDs) - 1 - i, err
+		}
+		if err := w.Write(blk.Bytes()); err != nil {
+			return len(blkIDs) - 1 - i, err
+		}
+	}
+	return len(blkIDs), nil
+}
+
+// exportIndexedSnapshot writes the blocks [vm] accepted after its genesis block
+// to [w] by looking them up by height, so that their IDs aren't kept in memory
+func exportIndexedSnapshot(vm HeightIndexedChainVM, w *common.SnapshotWriter) (int, error) {
+	lastHeight, err := lastAcceptedHeight(vm)
+	if err != nil {
+		return 0, err
+	}
+
+	for height := uint64(1); height <= lastHeight; height++ {
+		blkID, err := vm.GetBlockIDAtHeight(height)
+		if err != nil {
+			return int(height - 1), err
+		}
+		blk, err := vm.GetBlock(blkID)
+		if err != nil {
+			return int(height - 1), err
+		}
+		if err := w.Write(blk.Bytes()); err != nil {
+			return int(height - 1), err
+		}
+	}
+	return int(lastHeight), nil
+}
+
+// ImportSnapshot accepts the blocks of the snapshot [r], and returns how many
+// were accepted. Each block must be a child of the block before it, and the
+// first block that isn't accepted yet must be a child of the last accepted
+// block. If [vm] indexes its blocks by height, the blocks that are already
+// accepted must be the ancestors of the last accepted block at their heights.
+// Each block is verified before it's accepted, as it would be when
+// bootstrapping. Assumes the chain's context lock is held.
+func ImportSnapshot(vm ChainVM, r *common.SnapshotReader) (int, error) {
+	accepted := 0
+	lastAcceptedID := vm.LastAccepted()
+
+	indexedVM, indexed := vm.(HeightIndexedChainVM)
+	lastHeight := uint64(0)
+	if indexed {
+		var err error
+		if lastHeight, err = lastAcceptedHeight(indexedVM); err != nil {
+			return 0, err
+		}
+	}
+	for {
+		blkBytes, err := r.Next()
+		if err == io.EOF {
+			return accepted, nil
+		} else if err != nil {
+			return accepted, err
+		}
+
+		blk, err := vm.ParseBlock(blkBytes)
+		if err != nil {
+			return accepted, fmt.Errorf("couldn't parse snapshot block %d: %w", r.Count(), err)
+		}
+		if blk.Status() == choices.Accepted {
+			// The snapshot starts with the block after the genesis block, so
+			// the block's height is its position in the snapshot
+			if indexed {
+				if err := verifyAncestor(indexedVM, blk.ID(), r.Count(), lastHeight+uint64(accepted)); err != nil {
+					return accepted, fmt.Errorf("snapshot block %d: %w", r.Count(), err)
+				}
+			}
+			continue
+		}
+		if parentID := blk.Parent().ID(); !parentID.Equals(lastAcceptedID) {
+			return accepted, fmt.Errorf("%w: %s's parent is %s, not %s", errSnapshotLink, blk.ID(), parentID, lastAcceptedID)
+		}
+		if err := blk.Verify(); err != nil {
+			return accepted, fmt.Errorf("snapshot block %s is invalid: %w", blk.ID(), err)
+		}
+		blk.Accept()
+		lastAcceptedID = blk.ID()
+		accepted++
+	}
+}
@@ -0,0 +1,122 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainID := ids.Empty.Prefix(100)
+
+	// newChain returns a genesis block, and [n] blocks built on it with the
+	// status [status]
+	newChain := func(n int, status choices.Status) []*Blk {
+		blks := []*Blk{&Blk{
+			parent: &Blk{id: ids.Empty.Prefix(200), status: choices.Unknown},
+			id:     ids.Empty.Prefix(0),
+			status: choices.Accepted,
+			bytes:  []byte{0},
+		}}
+		for i := 1; i <= n; i++ {
+			blks = append(blks, &Blk{
+				parent: blks[i-1],
+				id:     ids.Empty.Prefix(uint64(i)),
+				height: i,
+				status: status,
+				bytes:  []byte{byte(i)},
+			})
+		}
+		return blks
+	}
+	newVM := func(blks []*Blk) *VMTest {
+		vm := &VMTest{}
+		vm.T = t
+		vm.Default(true)
+		vm.LastAcceptedF = func() ids.ID {
+			last := blks[0]
+			for _, blk := range blks {
+				if blk.Status() == choices.Accepted {
+					last = blk
+				}
+			}
+			return last.ID()
+		}
+		vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+			for _, blk := range blks {
+				if blk.ID().Equals(blkID) {
+					return blk, nil
+				}
+			}
+			return nil, errUnknownBlock
+		}
+		vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+			for _, blk := range blks {
+				if bytes.Equal(blk.Bytes(), b) {
+					return blk, nil
+				}
+			}
+			return nil, errUnknownBlock
+		}
+		return vm
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := common.NewSnapshotWriter(buf, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ExportSnapshot(newVM(newChain(3, choices.Accepted)), w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 3 {
+		t.Fatalf("expected every block but the genesis block to be exported but %d were", exported)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := buf.Bytes()
+
+	// The blocks are accepted on a fresh chain
+	fresh := newChain(3, choices.Processing)
+	r, err := common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportSnapshot(newVM(fresh), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 3 {
+		t.Fatalf("expected 3 blocks to be imported but %d were", imported)
+	}
+	for _, blk := range fresh {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("block %s should have been accepted", blk.ID())
+		}
+	}
+
+	// A block that doesn't extend the last accepted block isn't accepted
+	forked := newChain(3, choices.Processing)
+	forked[2].parent = &Blk{id: ids.Empty.Prefix(300), status: choices.Accepted}
+	r, err = common.NewSnapshotReader(bytes.NewReader(snapshot), chainID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := ImportSnapshot(newVM(forked), r); err == nil {
+		t.Fatalf("shouldn't have imported a block that isn't linked to the last accepted block")
+	} else if imported != 1 {
+		t.Fatalf("expected only the first block to be imported but %d were", imported)
+	}
+	if forked[2].Status() == choices.Accepted || forked[3].Status() == choices.Accepted {
+		t.Fatalf("shouldn't have accepted the blocks after the broken link")
+	}
+}
@@ -0,0 +1,52 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+// StatelessBlock is a block that can separate the verification that doesn't
+// depend on the chain's state, such as checking signatures, from the
+// verification against the state of its parent.
+//
+// The engine may run VerifyStateless in parallel with the verification of
+// other blocks, so it must not access the VM's state. If VerifyStateless
+// returns an error, the block is dropped without calling Verify.
+type StatelessBlock interface {
+	snowman.Block
+
+	// VerifyStateless verifies the parts of this block that don't depend on
+	// the chain's state.
+	VerifyStateless() error
+}
+
+// statelessVerifier runs the stateless verification of blocks in the
+// background, on at most a fixed number of goroutines at a time
+type statelessVerifier struct {
+	workers chan struct{} // Holds a token for each running verification
+}
+
+func (v *statelessVerifier) initialize(maxWorkers int) {
+	v.workers = make(chan struct{}, maxWorkers)
+}
+
+// verify starts verifying [blk] in the background and returns the channel its
+// result will be sent on. If all the workers are busy, nil is returned and the
+// block should be verified when it's needed.
+func (v *statelessVerifier) verify(blk StatelessBlock) <-chan error {
+	select {
+	case v.workers <- struct{}{}:
+	default:
+		return nil
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		err := blk.VerifyStateless()
+		<-v.workers
+		result <- err
+	}()
+	return result
+}
@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"runtime"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -11,6 +13,7 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -25,6 +28,14 @@ type Transitive struct {
 
 	blocked events.Blocker // track operations that are blocked on blocks
 
+	// verifier runs the stateless verification of pending blocks while they
+	// wait to be issued, and verifying maps the blocks to its results
+	verifier  statelessVerifier
+	verifying map[[32]byte]<-chan error
+
+	// acceptedCache keeps recently accepted blocks in memory to serve peers
+	acceptedCache acceptedCache
+
 	bootstrapped bool
 }
 
@@ -33,6 +44,12 @@ func (t *Transitive) Initialize(config Config) {
 	config.Context.Log.Info("Initializing Snowman consensus")
 
 	t.Config = config
+	t.acceptedCache.init(config.VM)
+	if err := config.Context.ConsensusDispatcher.RegisterChain(config.Context.ChainID, acceptedCacheID, &t.acceptedCache); err != nil {
+		config.Context.Log.Warn("Accepted blocks won't be cached as they're accepted due to %s", err)
+	}
+	t.verifier.initialize(runtime.NumCPU())
+	t.verifying = make(map[[32]byte]<-chan error)
 	t.metrics.Initialize(config.Context.Log, config.Params.Namespace, config.Params.Metrics)
 
 	t.onFinished = t.finishBootstrapping
@@ -49,11 +66,23 @@ func (t *Transitive) finishBootstrapping() {
 	t.Config.VM.SetPreference(tail)
 	t.Consensus.Initialize(t.Config.Context, t.Params, tail)
 	t.bootstrapped = true
+	t.StartFrontierMonitor(t.Params.Namespace, t.Params.Metrics, t.sendRequest)
+
+	if err := t.restoreProcessing(); err != nil {
+		t.Config.Context.Log.Warn("couldn't restore the blocks that were processing: %s", err)
+	}
 }
 
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() {
 	t.Config.Context.Log.Info("Shutting down Snowman consensus")
+	t.StopFrontierMonitor()
+	if err := t.Config.Context.ConsensusDispatcher.DeregisterChain(t.Config.Context.ChainID, acceptedCacheID); err != nil {
+		t.Config.Context.Log.Debug("Couldn't deregister the accepted cache due to %s", err)
+	}
+	if err := t.saveProcessing(); err != nil {
+		t.Config.Context.Log.Warn("couldn't save the blocks that are processing: %s", err)
+	}
 	t.Config.VM.Shutdown()
 }
 
@@ -62,8 +91,14 @@ func (t *Transitive) Context() *snow.Context { return t.Config.Context }
 
 // Get implements the Engine interface
 func (t *Transitive) Get(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	// If the requested block was recently accepted, serve it from memory
+	if blk, ok := t.acceptedCache.get(blkID); ok {
+		t.Config.Sender.Put(vdr, requestID, blkID, blk.bytes)
+		return
+	}
+
 	if blk, err := t.Config.VM.GetBlock(blkID); err == nil {
-		t.Config.Sender.Put(vdr, requestID, blkID, blk.Bytes())
+		t.Config.Sender.Put(vdr, requestID, blkID, t.acceptedCache.add(blk))
 	}
 }
 
@@ -72,7 +107,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 	t.Config.Context.Log.Verbo("Put called for blockID %s", blkID)
 
 	if !t.bootstrapped {
-		t.bootstrapper.Put(vdr, requestID, blkID, blkBytes)
+		t.Config.Context.Log.Debug("Dropping Put for %s due to bootstrapping", blkID)
 		return
 	}
 
@@ -81,9 +116,13 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		t.Config.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
 			err,
 			formatting.DumpBytes{Bytes: blkBytes})
+		t.Config.Context.ReportMisbehavior(vdr, snow.InvalidContainer, blkBytes, blkID)
 		t.GetFailed(vdr, requestID, blkID)
 		return
 	}
+	if parsedID := blk.ID(); !parsedID.Equals(blkID) {
+		t.Config.Context.ReportMisbehavior(vdr, snow.MismatchedContainer, blkBytes, blkID, parsedID)
+	}
 
 	t.insertFrom(vdr, blk)
 }
@@ -91,7 +130,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
 	if !t.bootstrapped {
-		t.bootstrapper.GetFailed(vdr, requestID, blkID)
+		t.Config.Context.Log.Debug("Dropping GetFailed for %s due to bootstrapping", blkID)
 		return
 	}
 
@@ -103,6 +142,70 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 	t.numBlockedBlk.Set(float64(t.pending.Len()))
 }
 
+// GetAncestors implements the Engine interface. It sends the block and as many
+// of its ancestors, youngest first, as fit in one MultiPut message. Recently
+// accepted ancestors are served from memory.
+func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	ancestorsBytes := [][]byte(nil)
+	ancestorsBytesLen := 0 // Length, in bytes, of all elements of ancestorsBytes
+	blk := snowman.Block(nil)
+	for len(ancestorsBytes) < common.MaxContainersPerMultiPut {
+		blkBytes := []byte(nil)
+		if cached, ok := t.acceptedCache.get(blkID); ok {
+			blkBytes, blk = cached.bytes, nil
+			blkID = cached.parentID
+		} else {
+			if blk == nil {
+				var err error
+				if blk, err = t.Config.VM.GetBlock(blkID); err != nil {
+					if len(ancestorsBytes) == 0 {
+						t.Config.Context.Log.Debug("Dropping GetAncestors for %s as the block couldn't be fetched due to %s", blkID, err)
+						return
+					}
+					break
+				}
+			} else if !blk.Status().Fetched() {
+				break
+			}
+			blkBytes = t.acceptedCache.add(blk)
+			if blk = blk.Parent(); blk != nil {
+				blkID = blk.ID()
+			} else {
+				blkID = ids.ID{}
+			}
+		}
+
+		// Ensure the MultiPut message doesn't get too big
+		if ancestorsBytesLen += len(blkBytes) + wrappers.IntLen; ancestorsBytesLen > common.MaxContainersLen {
+			break
+		}
+		ancestorsBytes = append(ancestorsBytes, blkBytes)
+		if blkID.IsZero() {
+			break
+		}
+	}
+
+	t.Config.Sender.MultiPut(vdr, requestID, ancestorsBytes)
+}
+
+// MultiPut implements the Engine interface
+func (t *Transitive) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping MultiPut(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.MultiPut(vdr, requestID, blks)
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (t *Transitive) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping GetAncestorsFailed(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.GetAncestorsFailed(vdr, requestID)
+}
+
 // PullQuery implements the Engine interface
 func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
 	if !t.bootstrapped {
@@ -145,6 +248,9 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 	// Since this is snowman, there should only be one ID in the vote set
 	if votes.Len() != 1 {
 		t.Config.Context.Log.Warn("Chits was called with the wrong number of votes %d. ValidatorID: %s, RequestID: %d", votes.Len(), vdr, requestID)
+		if votes.Len() > 1 {
+			t.Config.Context.ReportMisbehavior(vdr, snow.ConflictingVotes, nil, votes.List()...)
+		}
 		t.QueryFailed(vdr, requestID)
 		return
 	}
@@ -191,6 +297,12 @@ func (t *Transitive) Notify(msg common.Message) {
 	switch msg {
 	case common.PendingTxs:
 		if blk, err := t.Config.VM.BuildBlock(); err == nil {
+			// Other nodes would refuse a block larger than the limit, so it
+			// isn't issued
+			if blkLen, maxLen := len(blk.Bytes()), t.Config.Context.Limits.MaxBlockSize; blkLen > maxLen {
+				t.Config.Context.Log.Warn("VM.BuildBlock returned a %d byte block, which is larger than the limit of %d bytes", blkLen, maxLen)
+				return
+			}
 			if status := blk.Status(); status != choices.Processing {
 				t.Config.Context.Log.Warn("Attempting to issue a block with status: %s, expected Processing", status)
 			}
@@ -258,6 +370,14 @@ func (t *Transitive) insert(blk snowman.Block) {
 	t.pending.Add(blkID)
 	t.blkReqs.Remove(blkID)
 
+	// Start verifying the block's signatures, etc. while it waits for its
+	// ancestors to be verified and issued
+	if blk, ok := blk.(StatelessBlock); ok {
+		if result := t.verifier.verify(blk); result != nil {
+			t.verifying[blkID.Key()] = result
+		}
+	}
+
 	i := &issuer{
 		t:   t,
 		blk: blk,
@@ -291,7 +411,7 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
 func (t *Transitive) pullSample(blkID ids.ID) {
 	t.Config.Context.Log.Verbo("About to sample from: %s", t.Config.Validators)
 	p := t.Consensus.Parameters()
-	vdrs := t.Config.Validators.Sample(p.K)
+	vdrs := t.Config.Validators.SampleFrom(p.K, t.Config.Context.Source)
 	vdrSet := ids.ShortSet{}
 	for _, vdr := range vdrs {
 		vdrSet.Add(vdr.ID())
@@ -308,7 +428,7 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 func (t *Transitive) pushSample(blk snowman.Block) {
 	t.Config.Context.Log.Verbo("About to sample from: %s", t.Config.Validators)
 	p := t.Consensus.Parameters()
-	vdrs := t.Config.Validators.Sample(p.K)
+	vdrs := t.Config.Validators.SampleFrom(p.K, t.Config.Context.Source)
 	vdrSet := ids.ShortSet{}
 	for _, vdr := range vdrs {
 		vdrSet.Add(vdr.ID())
@@ -323,14 +443,15 @@ func (t *Transitive) pushSample(blk snowman.Block) {
 }
 
 func (t *Transitive) deliver(blk snowman.Block) {
+	blkID := blk.ID()
 	if t.Consensus.Issued(blk) {
+		delete(t.verifying, blkID.Key())
 		return
 	}
 
-	blkID := blk.ID()
 	t.pending.Remove(blkID)
 
-	if err := blk.Verify(); err != nil {
+	if err := t.verify(blk); err != nil {
 		t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
 		t.blocked.Abandon(blkID)
 		t.numBlockedBlk.Set(float64(t.pending.Len())) // Tracks performance statistics
@@ -347,7 +468,7 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	switch blk := blk.(type) {
 	case OracleBlock:
 		for _, blk := range blk.Options() {
-			if err := blk.Verify(); err != nil {
+			if err := t.verify(blk); err != nil {
 				t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
 				t.blocked.Abandon(blk.ID())
 				dropped = append(dropped, blk)
@@ -377,3 +498,20 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	t.numBlkRequests.Set(float64(t.blkReqs.Len()))
 	t.numBlockedBlk.Set(float64(t.pending.Len()))
 }
+
+// verify [blk] statelessly, waiting for the result if the stateless
+// verification is already running, and then against its parent's state
+func (t *Transitive) verify(blk snowman.Block) error {
+	key := blk.ID().Key()
+	if result, ok := t.verifying[key]; ok {
+		delete(t.verifying, key)
+		if err := <-result; err != nil {
+			return err
+		}
+	} else if blk, ok := blk.(StatelessBlock); ok {
+		if err := blk.VerifyStateless(); err != nil {
+			return err
+		}
+	}
+	return blk.Verify()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
@@ -536,6 +537,166 @@ func TestEngineFetchBlock(t *testing.T) {
 	}
 }
 
+func TestEngineFetchAcceptedBlockCached(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(false)
+
+	gBlk.(*Blk).bytes = []byte{1}
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		if id.Equals(gBlk.ID()) {
+			return gBlk, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have failed")
+	}
+
+	added := new(int)
+	sender.PutF = func(inVdr ids.ShortID, requestID uint32, blkID ids.ID, blk []byte) {
+		if !gBlk.ID().Equals(blkID) {
+			t.Fatalf("Wrong blockID")
+		}
+		if !bytes.Equal(blk, gBlk.Bytes()) {
+			t.Fatalf("Wrong block bytes")
+		}
+		*added++
+	}
+
+	te.Get(vdr.ID(), 123, gBlk.ID())
+
+	// The accepted block should now be served without asking the VM
+	vm.GetBlockF = nil
+	vm.CantGetBlock = true
+
+	te.Get(vdr.ID(), 124, gBlk.ID())
+
+	if *added != 2 {
+		t.Fatalf("Should have sent block to peer twice")
+	}
+}
+
+func TestEngineFetchAncestors(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+
+	gBlk.(*Blk).bytes = []byte{0}
+	gBlk.(*Blk).parent = &Blk{
+		id:     GenerateID(),
+		status: choices.Unknown,
+	}
+	blk1 := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{1},
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{2},
+	}
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		if id.Equals(blk2.ID()) {
+			return blk2, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have failed")
+	}
+
+	sent := new(bool)
+	sender.MultiPutF = func(inVdr ids.ShortID, requestID uint32, blks [][]byte) {
+		if !vdr.ID().Equals(inVdr) {
+			t.Fatalf("Wrong validator")
+		}
+		if requestID != 123 {
+			t.Fatalf("Wrong request id")
+		}
+		// The block comes first, followed by its ancestors
+		expected := [][]byte{blk2.Bytes(), blk1.Bytes(), gBlk.Bytes()}
+		if len(blks) != len(expected) {
+			t.Fatalf("Should have sent %d blocks but sent %d", len(expected), len(blks))
+		}
+		for i, blkBytes := range blks {
+			if !bytes.Equal(blkBytes, expected[i]) {
+				t.Fatalf("Block %d should have been %v but was %v", i, expected[i], blkBytes)
+			}
+		}
+		*sent = true
+	}
+
+	te.GetAncestors(vdr.ID(), 123, blk2.ID())
+
+	if !*sent {
+		t.Fatalf("Should have sent blocks to peer")
+	}
+}
+
+func TestEngineServeAcceptedAncestorsFromCache(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(false)
+
+	gBlk.(*Blk).bytes = []byte{0}
+	blk0 := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{1},
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{2},
+	}
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk0.ID()):
+			return blk0, nil
+		case id.Equals(blk1.ID()):
+			return blk1, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have failed")
+	}
+
+	ctx := te.Context()
+	for _, blk := range []snowman.Block{gBlk, blk0, blk1} {
+		ctx.ConsensusDispatcher.Accept(ctx.ChainID, blk.ID(), blk.Bytes())
+	}
+
+	// The accepted blocks should now be served without asking the VM
+	vm.GetBlockF = nil
+	vm.CantGetBlock = true
+
+	sent := new(bool)
+	sender.MultiPutF = func(_ ids.ShortID, requestID uint32, blks [][]byte) {
+		expected := [][]byte{blk1.Bytes(), blk0.Bytes(), gBlk.Bytes()}
+		if len(blks) != len(expected) {
+			t.Fatalf("Should have sent %d blocks but sent %d", len(expected), len(blks))
+		}
+		for i, blkBytes := range blks {
+			if !bytes.Equal(blkBytes, expected[i]) {
+				t.Fatalf("Block %d should have been %v but was %v", i, expected[i], blkBytes)
+			}
+		}
+		*sent = true
+	}
+
+	te.GetAncestors(vdr.ID(), 123, blk1.ID())
+
+	if !*sent {
+		t.Fatalf("Should have sent blocks to peer")
+	}
+}
+
 func TestEnginePushQuery(t *testing.T) {
 	vdr, _, sender, vm, te, gBlk := setup(t)
 
@@ -643,6 +804,60 @@ func TestEngineBuildBlock(t *testing.T) {
 	}
 }
 
+func TestEngineDropsOversizedBuiltBlock(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+
+	te.Config.Context.Limits.MaxBlockSize = 1
+	blk := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{1, 2},
+	}
+
+	vm.BuildBlockF = func() (snowman.Block, error) { return blk, nil }
+	te.Notify(common.PendingTxs)
+
+	if te.Consensus.Issued(blk) {
+		t.Fatalf("Shouldn't have issued a block larger than the limit")
+	}
+}
+
+func TestEngineReportsMisbehavior(t *testing.T) {
+	vdr, _, sender, vm, te, _ := setup(t)
+
+	sender.Default(true)
+
+	evidence := []snow.Evidence{}
+	te.Config.Context.Misbehavior.OnReport(func(e snow.Evidence) { evidence = append(evidence, e) })
+
+	blkID := GenerateID()
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) { return nil, errUnknownBytes }
+	te.Put(vdr.ID(), 0, blkID, []byte{1})
+	vm.ParseBlockF = nil
+
+	if len(evidence) != 1 {
+		t.Fatalf("Should have reported the invalid block")
+	}
+	if e := evidence[0]; !e.ValidatorID.Equals(vdr.ID()) || e.Kind != snow.InvalidContainer ||
+		len(e.ContainerIDs) != 1 || !e.ContainerIDs[0].Equals(blkID) || !bytes.Equal(e.Container, []byte{1}) {
+		t.Fatalf("Reported the wrong evidence %+v", e)
+	}
+
+	votes := ids.Set{}
+	votes.Add(GenerateID(), GenerateID())
+	te.Chits(vdr.ID(), 0, votes)
+
+	if len(evidence) != 2 {
+		t.Fatalf("Should have reported the conflicting votes")
+	}
+	if e := evidence[1]; e.Kind != snow.ConflictingVotes || len(e.ContainerIDs) != 2 {
+		t.Fatalf("Reported the wrong evidence %+v", e)
+	}
+}
+
 func TestEngineRepoll(t *testing.T) {
 	vdr, _, sender, _, te, _ := setup(t)
 
@@ -1076,3 +1291,123 @@ func TestEngineRetryFetch(t *testing.T) {
 		t.Fatalf("Should have requested the block again")
 	}
 }
+
+type statelessBlk struct {
+	*Blk
+	verifyStateless error
+}
+
+func (b *statelessBlk) VerifyStateless() error { return b.verifyStateless }
+
+func TestEngineStatelessVerification(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+	sender.CantChits = false
+	sender.CantPushQuery = false
+
+	invalidBlk := &statelessBlk{
+		Blk: &Blk{
+			parent: gBlk,
+			id:     GenerateID(),
+			status: choices.Processing,
+			bytes:  []byte{1},
+		},
+		verifyStateless: errors.New("invalid signature"),
+	}
+	validBlk := &statelessBlk{
+		Blk: &Blk{
+			parent: gBlk,
+			id:     GenerateID(),
+			status: choices.Processing,
+			bytes:  []byte{2},
+		},
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, invalidBlk.Bytes()):
+			return invalidBlk, nil
+		case bytes.Equal(b, validBlk.Bytes()):
+			return validBlk, nil
+		default:
+			return nil, errUnknownBytes
+		}
+	}
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(invalidBlk.ID()):
+			return invalidBlk, nil
+		case id.Equals(validBlk.ID()):
+			return validBlk, nil
+		default:
+			return nil, errUnknownBytes
+		}
+	}
+
+	te.PushQuery(vdr.ID(), 0, invalidBlk.ID(), invalidBlk.Bytes())
+	if te.Consensus.Issued(invalidBlk) {
+		t.Fatalf("Shouldn't have issued a block that failed stateless verification")
+	}
+
+	te.PushQuery(vdr.ID(), 1, validBlk.ID(), validBlk.Bytes())
+	if !te.Consensus.Issued(validBlk) {
+		t.Fatalf("Should have issued a block that passed stateless verification")
+	}
+
+	if len(te.verifying) != 0 {
+		t.Fatalf("Shouldn't be tracking the verification of decided blocks")
+	}
+}
+
+// blockingStatelessBlk is a block whose stateless verification waits until
+// it's released
+type blockingStatelessBlk struct {
+	*Blk
+	release chan struct{}
+}
+
+func (b *blockingStatelessBlk) VerifyStateless() error {
+	<-b.release
+	return nil
+}
+
+func TestStatelessVerifierIsBounded(t *testing.T) {
+	v := statelessVerifier{}
+	v.initialize(2)
+
+	release := make(chan struct{})
+	newBlk := func() StatelessBlock {
+		return &blockingStatelessBlk{
+			Blk:     &Blk{id: GenerateID()},
+			release: release,
+		}
+	}
+
+	results := []<-chan error{v.verify(newBlk()), v.verify(newBlk())}
+	for _, result := range results {
+		if result == nil {
+			t.Fatalf("Should have started verifying the block")
+		}
+	}
+	if result := v.verify(newBlk()); result != nil {
+		t.Fatalf("Shouldn't have started verifying a block while all the workers are busy")
+	}
+
+	close(release)
+	for _, result := range results {
+		if err := <-result; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := v.verify(newBlk())
+	if result == nil {
+		t.Fatalf("Should have started verifying the block once the workers are free")
+	}
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+}
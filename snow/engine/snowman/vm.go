@@ -55,3 +55,19 @@ type ChainVM interface {
 	// returned.
 	LastAccepted() ids.ID
 }
+
+// HeightIndexedChainVM is a ChainVM that indexes its accepted blocks by height,
+// so that the engine can find the ancestors of accepted blocks without walking
+// back through their parents.
+type HeightIndexedChainVM interface {
+	ChainVM
+
+	// GetBlockIDAtHeight returns the ID of the accepted block at [height]. The
+	// genesis block is at height 0.
+	//
+	// If no block has been accepted at [height], an error should be returned.
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+
+	// LastAcceptedHeight returns the height of the last accepted block.
+	LastAcceptedHeight() (uint64, error)
+}
@@ -43,6 +43,7 @@ func (v *voter) Update() {
 
 	v.t.Config.Context.Log.Verbo("Finishing poll [%d] with:\n%s", v.requestID, &results)
 	v.t.Consensus.RecordPoll(results)
+	results.Release()
 
 	v.t.Config.VM.SetPreference(v.t.Consensus.Preference())
 
@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+// Kinds of the nodes of a Graph
+const (
+	BlockNode  = "block"
+	VertexNode = "vertex"
+	TxNode     = "tx"
+)
+
+// Kinds of the edges of a Graph
+const (
+	ParentEdge     = "parent"     // From a block, or vertex, to its parent
+	TxEdge         = "tx"         // From a vertex to one of its transactions
+	DependencyEdge = "dependency" // From a transaction to one of its dependencies
+)
+
+// Graph is the decisions a chain's consensus is processing, and the decisions
+// they depend on, at one point in time
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a block, vertex or transaction of a Graph
+type GraphNode struct {
+	ID     ids.ID         `json:"id"`
+	Kind   string         `json:"kind"`
+	Status choices.Status `json:"status"`
+
+	// True if consensus currently prefers this decision
+	Preferred bool `json:"preferred"`
+
+	// The confidence and bias of a transaction. Confidence is the number of
+	// consecutive successful polls for the transaction, and bias is the total
+	// number of successful polls for it.
+	Confidence int `json:"confidence"`
+	Bias       int `json:"bias"`
+
+	// The state of the snowball instance deciding between the children of a
+	// block, which includes its confidence counters. Empty if the block has no
+	// processing children.
+	Snowball string `json:"snowball,omitempty"`
+}
+
+// GraphEdge is a dependency of one node of a Graph on another
+type GraphEdge struct {
+	From ids.ID `json:"from"`
+	To   ids.ID `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Sort orders the nodes of the graph by ID, and its edges by the IDs they're
+// from and to, so that the same graph is always written the same way
+func (g *Graph) Sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return bytes.Compare(g.Nodes[i].ID.Bytes(), g.Nodes[j].ID.Bytes()) < 0
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if cmp := bytes.Compare(g.Edges[i].From.Bytes(), g.Edges[j].From.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+		return bytes.Compare(g.Edges[i].To.Bytes(), g.Edges[j].To.Bytes()) < 0
+	})
+}
+
+// DOT returns the graph in the DOT language of Graphviz. Preferred nodes are
+// drawn in bold.
+func (g *Graph) DOT() string {
+	sb := strings.Builder{}
+	sb.WriteString("digraph consensus {\n")
+	for _, node := range g.Nodes {
+		label := fmt.Sprintf("%s %s\n%s", node.Kind, node.ID, node.Status)
+		if node.Kind == TxNode {
+			label += fmt.Sprintf("\nconfidence %d, bias %d", node.Confidence, node.Bias)
+		}
+		style := "solid"
+		if node.Preferred {
+			style = "bold"
+		}
+		sb.WriteString(fmt.Sprintf("\t%q [label=%q, style=%s", node.ID.String(), label, style))
+		if node.Snowball != "" {
+			sb.WriteString(fmt.Sprintf(", tooltip=%q", node.Snowball))
+		}
+		sb.WriteString("];\n")
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("\t%q -> %q [label=%q];\n", edge.From.String(), edge.To.String(), edge.Kind))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+func TestGraphDOT(t *testing.T) {
+	parentID := ids.Empty.Prefix(0)
+	blkID := ids.Empty.Prefix(1)
+	graph := Graph{
+		Nodes: []GraphNode{
+			{ID: blkID, Kind: BlockNode, Status: choices.Processing},
+			{ID: parentID, Kind: BlockNode, Status: choices.Accepted, Preferred: true, Snowball: "SB(Confidence = 1)"},
+		},
+		Edges: []GraphEdge{{From: blkID, To: parentID, Kind: ParentEdge}},
+	}
+	graph.Sort()
+	if !graph.Nodes[0].ID.Equals(parentID) {
+		t.Fatalf("the nodes should have been sorted by ID")
+	}
+
+	dot := graph.DOT()
+	if !strings.HasPrefix(dot, "digraph consensus {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("expected a digraph but got:\n%s", dot)
+	}
+	for _, expected := range []string{
+		`"` + parentID.String() + `" [label="block ` + parentID.String() + `\nAccepted", style=bold, tooltip="SB(Confidence = 1)"];`,
+		`"` + blkID.String() + `" [label="block ` + blkID.String() + `\nProcessing", style=solid];`,
+		`"` + blkID.String() + `" -> "` + parentID.String() + `" [label="parent"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Fatalf("expected the graph to contain %s but it was:\n%s", expected, dot)
+		}
+	}
+}
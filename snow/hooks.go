@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Hooks are side effects, such as index updates, that VM and fx code registers
+// to run when its chain decides a container or finishes bootstrapping. Hooks
+// are run synchronously, in the order they were registered, while the chain's
+// lock is held.
+type Hooks struct {
+	lock sync.Mutex
+
+	onAccept       []func(containerID ids.ID, container []byte)
+	onReject       []func(containerID ids.ID, container []byte)
+	onBootstrapped []func()
+
+	bootstrapped bool
+}
+
+// OnAccept registers [hook] to run when a container is accepted
+func (h *Hooks) OnAccept(hook func(containerID ids.ID, container []byte)) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.onAccept = append(h.onAccept, hook)
+}
+
+// OnReject registers [hook] to run when a container is rejected
+func (h *Hooks) OnReject(hook func(containerID ids.ID, container []byte)) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.onReject = append(h.onReject, hook)
+}
+
+// OnBootstrapped registers [hook] to run when the chain finishes
+// bootstrapping. If the chain has already finished, [hook] runs immediately.
+func (h *Hooks) OnBootstrapped(hook func()) {
+	h.lock.Lock()
+	if !h.bootstrapped {
+		h.onBootstrapped = append(h.onBootstrapped, hook)
+		h.lock.Unlock()
+		return
+	}
+	h.lock.Unlock()
+
+	hook()
+}
+
+// Accept runs the accept hooks. It implements the triggers.Acceptor interface.
+func (h *Hooks) Accept(_, containerID ids.ID, container []byte) error {
+	for _, hook := range h.hooks(&h.onAccept) {
+		hook(containerID, container)
+	}
+	return nil
+}
+
+// Reject runs the reject hooks. It implements the triggers.Rejector interface.
+func (h *Hooks) Reject(_, containerID ids.ID, container []byte) error {
+	for _, hook := range h.hooks(&h.onReject) {
+		hook(containerID, container)
+	}
+	return nil
+}
+
+// Bootstrapped runs the bootstrapped hooks. Only the first call has an effect.
+func (h *Hooks) Bootstrapped() {
+	h.lock.Lock()
+	if h.bootstrapped {
+		h.lock.Unlock()
+		return
+	}
+	h.bootstrapped = true
+	hooks := h.onBootstrapped
+	h.onBootstrapped = nil
+	h.lock.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// hooks returns the current hooks in [registered]. Hooks aren't run with
+// [h.lock] held so that they may register more hooks.
+func (h *Hooks) hooks(registered *[]func(ids.ID, []byte)) []func(ids.ID, []byte) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return *registered
+}
@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestHooks(t *testing.T) {
+	ctx := DefaultContextTest()
+	if err := ctx.DecisionDispatcher.RegisterChain(ctx.ChainID, "hooks", &ctx.Hooks); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := []ids.ID(nil)
+	rejected := []ids.ID(nil)
+	ctx.Hooks.OnAccept(func(containerID ids.ID, _ []byte) { accepted = append(accepted, containerID) })
+	ctx.Hooks.OnReject(func(containerID ids.ID, _ []byte) { rejected = append(rejected, containerID) })
+
+	acceptedID := ids.NewID([32]byte{1})
+	rejectedID := ids.NewID([32]byte{2})
+	ctx.DecisionDispatcher.Accept(ctx.ChainID, acceptedID, nil)
+	ctx.DecisionDispatcher.Reject(ctx.ChainID, rejectedID, nil)
+	ctx.DecisionDispatcher.Accept(ids.NewID([32]byte{3}), ids.NewID([32]byte{4}), nil)
+
+	if len(accepted) != 1 || !accepted[0].Equals(acceptedID) {
+		t.Fatalf("Accept hook should have run once for %s, but ran for %v", acceptedID, accepted)
+	}
+	if len(rejected) != 1 || !rejected[0].Equals(rejectedID) {
+		t.Fatalf("Reject hook should have run once for %s, but ran for %v", rejectedID, rejected)
+	}
+}
+
+func TestHooksBootstrapped(t *testing.T) {
+	hooks := Hooks{}
+
+	numCalls := 0
+	hooks.OnBootstrapped(func() { numCalls++ })
+	if numCalls != 0 {
+		t.Fatalf("Hook shouldn't have run before bootstrapping finished")
+	}
+
+	hooks.Bootstrapped()
+	hooks.Bootstrapped()
+	if numCalls != 1 {
+		t.Fatalf("Hook should have run once, but ran %d times", numCalls)
+	}
+
+	hooks.OnBootstrapped(func() { numCalls++ })
+	if numCalls != 2 {
+		t.Fatalf("Hook registered after bootstrapping should have run immediately")
+	}
+}
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"errors"
+)
+
+var (
+	errNonPositiveLimit = errors.New("container size limits must be positive")
+	errTxDoesntFit      = errors.New("the maximum transaction size can't exceed the maximum block or vertex size")
+)
+
+// Limits are the maximum sizes, in bytes, of the containers a chain issues and
+// accepts. Builders and verifiers both read them from the chain's context, so
+// they can't disagree on what's too large.
+type Limits struct {
+	MaxBlockSize  int
+	MaxVertexSize int
+	MaxTxSize     int
+}
+
+// DefaultLimits are the limits chains run with unless the node is configured
+// otherwise
+var DefaultLimits = Limits{
+	MaxBlockSize:  1 << 20,
+	MaxVertexSize: 1 << 20,
+	MaxTxSize:     1 << 18,
+}
+
+// Verify returns an error if a chain can't run with these limits
+func (l Limits) Verify() error {
+	switch {
+	case l.MaxBlockSize <= 0 || l.MaxVertexSize <= 0 || l.MaxTxSize <= 0:
+		return errNonPositiveLimit
+	case l.MaxTxSize > l.MaxBlockSize || l.MaxTxSize > l.MaxVertexSize:
+		return errTxDoesntFit
+	default:
+		return nil
+	}
+}
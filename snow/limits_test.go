@@ -0,0 +1,26 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"testing"
+)
+
+func TestLimitsVerify(t *testing.T) {
+	if err := DefaultLimits.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	limits := DefaultLimits
+	limits.MaxVertexSize = 0
+	if err := limits.Verify(); err != errNonPositiveLimit {
+		t.Fatalf("should have refused a non-positive limit")
+	}
+
+	limits = DefaultLimits
+	limits.MaxTxSize = limits.MaxBlockSize + 1
+	if err := limits.Verify(); err != errTxDoesntFit {
+		t.Fatalf("should have refused a transaction limit larger than the block limit")
+	}
+}
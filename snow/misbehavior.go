@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// MisbehaviorKind is a way a validator can misbehave
+type MisbehaviorKind uint32
+
+// Kinds of misbehavior
+const (
+	// InvalidContainer is sending a container that can't be parsed
+	InvalidContainer MisbehaviorKind = iota
+
+	// MismatchedContainer is answering a request for a container with a
+	// different container
+	MismatchedContainer
+
+	// ConflictingVotes is voting for conflicting containers in response to one
+	// query
+	ConflictingVotes
+)
+
+// Valid returns true if [k] is a known kind of misbehavior
+func (k MisbehaviorKind) Valid() bool { return k <= ConflictingVotes }
+
+func (k MisbehaviorKind) String() string {
+	switch k {
+	case InvalidContainer:
+		return "Invalid Container"
+	case MismatchedContainer:
+		return "Mismatched Container"
+	case ConflictingVotes:
+		return "Conflicting Votes"
+	default:
+		return "Unknown Misbehavior"
+	}
+}
+
+// Evidence that a validator misbehaved
+type Evidence struct {
+	// The validator that misbehaved, and the chain it misbehaved on
+	ValidatorID ids.ShortID
+	ChainID     ids.ID
+
+	Kind MisbehaviorKind
+
+	// The containers the misbehavior concerns. For a mismatched container,
+	// the ID that was requested and the ID of the container that was sent.
+	// For conflicting votes, the containers voted for.
+	ContainerIDs []ids.ID
+
+	// The container the validator sent, if any
+	Container []byte
+
+	// When the misbehavior was observed
+	Time time.Time
+}
+
+// Misbehavior collects the evidence a chain's engine observes of validators
+// misbehaving, and passes it to the hooks registered to receive it. Hooks are
+// run synchronously, in the order they were registered, while the chain's lock
+// is held.
+type Misbehavior struct {
+	lock     sync.Mutex
+	onReport []func(Evidence)
+}
+
+// OnReport registers [hook] to run when evidence of misbehavior is reported
+func (m *Misbehavior) OnReport(hook func(Evidence)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.onReport = append(m.onReport, hook)
+}
+
+// Report evidence that a validator misbehaved
+func (m *Misbehavior) Report(evidence Evidence) {
+	m.lock.Lock()
+	hooks := m.onReport
+	m.lock.Unlock()
+
+	if evidence.Time.IsZero() {
+		evidence.Time = time.Now()
+	}
+	for _, hook := range hooks {
+		hook(evidence)
+	}
+}
+
+// ReportMisbehavior reports evidence that [validatorID] misbehaved on this
+// chain by sending [container], which concerns [containerIDs]
+func (ctx *Context) ReportMisbehavior(validatorID ids.ShortID, kind MisbehaviorKind, container []byte, containerIDs ...ids.ID) {
+	ctx.Misbehavior.Report(Evidence{
+		ValidatorID:  validatorID,
+		ChainID:      ctx.ChainID,
+		Kind:         kind,
+		ContainerIDs: containerIDs,
+		Container:    container,
+		Time:         ctx.Clock.Time(),
+	})
+}
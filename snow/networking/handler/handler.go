@@ -5,6 +5,7 @@ package handler
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -18,6 +19,27 @@ type Handler struct {
 	wg      sync.WaitGroup
 	engine  common.Engine
 	msgChan <-chan common.Message
+
+	// If true, queries and the VM's notifications are dropped, so the engine
+	// never votes or proposes containers
+	observer bool
+
+	// Containers larger than this many bytes are refused before they reach
+	// the engine. If 0, containers of any size are passed on.
+	maxContainerSize int
+
+	// Resource accounting and throttling
+	budget             float64
+	memoryBudget       uint64
+	usageLock          sync.Mutex
+	usage              Usage
+	lastProcessingTime time.Duration
+
+	// Requests from peers held back while throttled, and their size in bytes
+	delayedLock  sync.Mutex
+	delayedCond  *sync.Cond
+	delayed      []message
+	delayedBytes uint64
 }
 
 // Initialize this consensus handler
@@ -25,6 +47,7 @@ func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message
 	h.msgs = make(chan message, bufferSize)
 	h.engine = engine
 	h.msgChan = msgChan
+	h.delayedCond = sync.NewCond(&h.delayedLock)
 
 	h.wg.Add(1)
 }
@@ -32,6 +55,33 @@ func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message
 // Context of this Handler
 func (h *Handler) Context() *snow.Context { return h.engine.Context() }
 
+// SetObserver sets whether the engine only follows consensus. An observer
+// doesn't answer queries, so it never votes, and drops the VM's notifications,
+// so it never proposes containers. Must be called before Dispatch.
+func (h *Handler) SetObserver(observer bool) { h.observer = observer }
+
+// Observer returns true if the engine only follows consensus
+func (h *Handler) Observer() bool { return h.observer }
+
+// SetMaxContainerSize sets the size, in bytes, of the largest container passed
+// to the engine. Must be called before the handler receives messages.
+func (h *Handler) SetMaxContainerSize(size int) { h.maxContainerSize = size }
+
+// MaxContainerSize returns the size, in bytes, of the largest container passed
+// to the engine. If 0, containers of any size are passed on.
+func (h *Handler) MaxContainerSize() int { return h.maxContainerSize }
+
+// tooLarge returns true if [container], sent by [validatorID], is refused
+// because of its size
+func (h *Handler) tooLarge(validatorID ids.ShortID, container []byte) bool {
+	if h.maxContainerSize == 0 || len(container) <= h.maxContainerSize {
+		return false
+	}
+	h.engine.Context().Log.Debug("dropping a %d byte container from %s, which is larger than the limit of %d bytes",
+		len(container), validatorID, h.maxContainerSize)
+	return true
+}
+
 // Dispatch waits for incoming messages from the network
 // and, when they arrive, sends them to the consensus engine
 func (h *Handler) Dispatch() {
@@ -40,14 +90,20 @@ func (h *Handler) Dispatch() {
 	for {
 		select {
 		case msg := <-h.msgs:
+			h.release(msg)
 			if !h.dispatchMsg(msg) {
 				return
 			}
 		case msg := <-h.msgChan:
+			if h.observer {
+				continue
+			}
 			if !h.dispatchMsg(message{messageType: notifyMsg, notification: msg}) {
 				return
 			}
 		}
+		h.throttle()
+		h.promoteDelayed()
 	}
 }
 
@@ -60,6 +116,9 @@ func (h *Handler) dispatchMsg(msg message) bool {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	start := time.Now()
+	defer func() { h.recordUsage(time.Since(start)) }()
+
 	ctx.Log.Verbo("Forwarding message to consensus: %s", msg)
 
 	switch msg.messageType {
@@ -81,6 +140,12 @@ func (h *Handler) dispatchMsg(msg message) bool {
 		h.engine.GetFailed(msg.validatorID, msg.requestID, msg.containerID)
 	case putMsg:
 		h.engine.Put(msg.validatorID, msg.requestID, msg.containerID, msg.container)
+	case getAncestorsMsg:
+		h.engine.GetAncestors(msg.validatorID, msg.requestID, msg.containerID)
+	case multiPutMsg:
+		h.engine.MultiPut(msg.validatorID, msg.requestID, msg.containers)
+	case getAncestorsFailedMsg:
+		h.engine.GetAncestorsFailed(msg.validatorID, msg.requestID)
 	case pushQueryMsg:
 		h.engine.PushQuery(msg.validatorID, msg.requestID, msg.containerID, msg.container)
 	case pullQueryMsg:
@@ -101,135 +166,187 @@ func (h *Handler) dispatchMsg(msg message) bool {
 // GetAcceptedFrontier passes a GetAcceptedFrontier message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.request(message{
 		messageType: getAcceptedFrontierMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // AcceptedFrontier passes a AcceptedFrontier message received from the network
 // to the consensus engine.
 func (h *Handler) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  acceptedFrontierMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFrontierFailed passes a GetAcceptedFrontierFailed message received
 // from the network to the consensus engine.
 func (h *Handler) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getAcceptedFrontierFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // GetAccepted passes a GetAccepted message received from the
 // network to the consensus engine.
 func (h *Handler) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.request(message{
 		messageType:  getAcceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // Accepted passes a Accepted message received from the network to the consensus
 // engine.
 func (h *Handler) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  acceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFailed passes a GetAcceptedFailed message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getAcceptedFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // Get passes a Get message received from the network to the consensus engine.
 func (h *Handler) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.request(message{
 		messageType: getMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
-	}
+	})
 }
 
 // Put passes a Put message received from the network to the consensus engine.
 func (h *Handler) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
-	h.msgs <- message{
+	// An oversized container fails the request, so the engine can fetch it
+	// from someone else
+	if h.tooLarge(validatorID, container) {
+		h.GetFailed(validatorID, requestID, containerID)
+		return
+	}
+	h.push(message{
 		messageType: putMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
 		container:   container,
-	}
+	})
 }
 
 // GetFailed passes a GetFailed message to the consensus engine.
 func (h *Handler) GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
+	})
+}
+
+// GetAncestors passes a GetAncestors message received from the network to the
+// consensus engine.
+func (h *Handler) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	h.request(message{
+		messageType: getAncestorsMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		containerID: containerID,
+	})
+}
+
+// MultiPut passes a MultiPut message received from the network to the consensus
+// engine.
+func (h *Handler) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	// Containers after an oversized one can't be attached to the requested
+	// container, so they're dropped along with it
+	for i, container := range containers {
+		if h.tooLarge(validatorID, container) {
+			containers = containers[:i]
+			break
+		}
 	}
+	h.push(message{
+		messageType: multiPutMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		containers:  containers,
+	})
+}
+
+// GetAncestorsFailed passes a GetAncestorsFailed message to the consensus
+// engine.
+func (h *Handler) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	h.push(message{
+		messageType: getAncestorsFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
 }
 
 // PushQuery passes a PushQuery message received from the network to the consensus engine.
 func (h *Handler) PushQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID, block []byte) {
-	h.msgs <- message{
+	if h.observer || h.tooLarge(validatorID, block) {
+		return
+	}
+	h.request(message{
 		messageType: pushQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
 		container:   block,
-	}
+	})
 }
 
 // PullQuery passes a PullQuery message received from the network to the consensus engine.
 func (h *Handler) PullQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID) {
-	h.msgs <- message{
+	if h.observer {
+		return
+	}
+	h.request(message{
 		messageType: pullQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
-	}
+	})
 }
 
 // Chits passes a Chits message received from the network to the consensus engine.
 func (h *Handler) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  chitsMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: votes,
-	}
+	})
 }
 
 // QueryFailed passes a QueryFailed message received from the network to the consensus engine.
 func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: queryFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // Shutdown shuts down the dispatcher
@@ -237,8 +354,8 @@ func (h *Handler) Shutdown() { h.msgs <- message{messageType: shutdownMsg}; h.wg
 
 // Notify ...
 func (h *Handler) Notify(msg common.Message) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  notifyMsg,
 		notification: msg,
-	}
+	})
 }
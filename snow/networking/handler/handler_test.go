@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestObserverDropsQueries(t *testing.T) {
+	engine := &common.EngineTest{T: t}
+	handler := &Handler{}
+	handler.Initialize(engine, make(chan common.Message), 2)
+	handler.SetObserver(true)
+
+	vdr := ids.NewShortID([20]byte{1})
+	handler.PullQuery(vdr, 0, ids.Empty)
+	handler.PushQuery(vdr, 1, ids.Empty, nil)
+	if len(handler.msgs) != 0 {
+		t.Fatalf("An observer shouldn't have queued %d queries", len(handler.msgs))
+	}
+
+	// Observers still follow consensus
+	handler.Get(vdr, 2, ids.Empty)
+	if len(handler.msgs) != 1 {
+		t.Fatalf("An observer should have queued the request")
+	}
+}
+
+func TestMaxContainerSize(t *testing.T) {
+	engine := &common.EngineTest{T: t}
+	ctx := snow.DefaultContextTest()
+	engine.ContextF = func() *snow.Context { return ctx }
+	handler := &Handler{}
+	handler.Initialize(engine, make(chan common.Message), 3)
+	handler.SetMaxContainerSize(2)
+
+	vdr := ids.NewShortID([20]byte{1})
+	handler.PushQuery(vdr, 0, ids.Empty, []byte{1, 2, 3})
+	if len(handler.msgs) != 0 {
+		t.Fatalf("An oversized query shouldn't have been queued")
+	}
+
+	handler.Put(vdr, 1, ids.Empty, []byte{1, 2, 3})
+	if msg := <-handler.msgs; msg.messageType != getFailedMsg || msg.requestID != 1 {
+		t.Fatalf("An oversized container should have failed the request")
+	}
+
+	handler.MultiPut(vdr, 2, [][]byte{{1}, {1, 2, 3}, {1}})
+	if msg := <-handler.msgs; msg.messageType != multiPutMsg || len(msg.containers) != 1 {
+		t.Fatalf("The containers from the oversized one on should have been dropped")
+	}
+
+	handler.Put(vdr, 3, ids.Empty, []byte{1, 2})
+	if msg := <-handler.msgs; msg.messageType != putMsg {
+		t.Fatalf("A container within the limit should have been passed on")
+	}
+}
@@ -24,6 +24,9 @@ const (
 	getMsg
 	putMsg
 	getFailedMsg
+	getAncestorsMsg
+	multiPutMsg
+	getAncestorsFailedMsg
 	pushQueryMsg
 	pullQueryMsg
 	chitsMsg
@@ -39,6 +42,7 @@ type message struct {
 	containerID  ids.ID
 	container    []byte
 	containerIDs ids.Set
+	containers   [][]byte
 	notification common.Message
 }
 
@@ -77,6 +81,12 @@ func (t msgType) String() string {
 		return "Put Message"
 	case getFailedMsg:
 		return "Get Failed Message"
+	case getAncestorsMsg:
+		return "Get Ancestors Message"
+	case multiPutMsg:
+		return "Multi Put Message"
+	case getAncestorsFailedMsg:
+		return "Get Ancestors Failed Message"
 	case pushQueryMsg:
 		return "Push Query Message"
 	case pullQueryMsg:
@@ -0,0 +1,211 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// maxDelayedRequests is the number of requests from peers that a throttled
+// handler holds back before the router blocks on it, as it does when the
+// handler isn't throttled
+const maxDelayedRequests = 1024
+
+// Usage is the amount of resources that a chain's handler has used.
+//
+// The memory accounted for is the bytes of the containers and container IDs
+// of the messages the handler holds until the engine processes them. The go
+// runtime doesn't attribute heap allocations to goroutines, so the memory the
+// chain's VM allocates isn't accounted for.
+type Usage struct {
+	// Time spent processing messages while holding the chain's lock
+	ProcessingTime time.Duration
+	// Number of messages processed
+	Processed uint64
+	// Number of requests from peers that were held back, rather than passed to
+	// the message buffer, due to throttling
+	Delayed uint64
+
+	// Bytes of the messages held in the message buffer or held back, and the
+	// most bytes held at once
+	QueuedBytes     uint64
+	PeakQueuedBytes uint64
+}
+
+// SetCPUBudget limits the fraction of time that this handler may spend
+// processing messages to [budget]. If [budget] isn't in (0, 1), the handler
+// isn't throttled.
+//
+// While throttled, requests from peers that don't fit in the message buffer
+// are held back, and passed on in order as the buffer drains, rather than
+// blocking the router. Only once [maxDelayedRequests] are held back, or they
+// hold more bytes than the memory budget, does the router block on this
+// handler.
+// Must be called before Dispatch.
+func (h *Handler) SetCPUBudget(budget float64) {
+	if budget <= 0 || budget >= 1 {
+		budget = 0
+	}
+	h.budget = budget
+}
+
+// SetMemoryBudget limits the bytes of the requests from peers that this
+// handler holds back while throttled to [budget]. Once the requests held back
+// would hold more than [budget] bytes, the router blocks on this handler, as it
+// does when the handler isn't throttled. If [budget] is 0, only the number of
+// requests held back is limited.
+// Must be called before Dispatch.
+func (h *Handler) SetMemoryBudget(budget uint64) { h.memoryBudget = budget }
+
+// MemoryBudget returns the bytes of requests from peers that this handler holds
+// back while throttled, or 0 if they aren't limited.
+func (h *Handler) MemoryBudget() uint64 { return h.memoryBudget }
+
+// CPUBudget returns the fraction of time that this handler may spend
+// processing messages, or 0 if the handler isn't throttled.
+func (h *Handler) CPUBudget() float64 { return h.budget }
+
+// Usage returns the resources that this handler has used
+func (h *Handler) Usage() Usage {
+	h.usageLock.Lock()
+	defer h.usageLock.Unlock()
+
+	return h.usage
+}
+
+// size returns the bytes of the containers and container IDs [msg] holds
+func (msg message) size() uint64 {
+	size := uint64(len(msg.container) + msg.containerIDs.Len()*hashing.HashLen)
+	for _, container := range msg.containers {
+		size += uint64(len(container))
+	}
+	return size
+}
+
+// Pass [msg] to the consensus engine, accounting for the memory it holds until
+// it's processed
+func (h *Handler) push(msg message) {
+	h.hold(msg)
+	h.msgs <- msg
+}
+
+// Record that this handler holds [msg]
+func (h *Handler) hold(msg message) {
+	h.usageLock.Lock()
+	defer h.usageLock.Unlock()
+
+	h.usage.QueuedBytes += msg.size()
+	if h.usage.QueuedBytes > h.usage.PeakQueuedBytes {
+		h.usage.PeakQueuedBytes = h.usage.QueuedBytes
+	}
+}
+
+// Record that this handler no longer holds [msg]
+func (h *Handler) release(msg message) {
+	h.usageLock.Lock()
+	defer h.usageLock.Unlock()
+
+	h.usage.QueuedBytes -= msg.size()
+}
+
+// Record that a message took [processingTime] to process
+func (h *Handler) recordUsage(processingTime time.Duration) {
+	h.usageLock.Lock()
+	defer h.usageLock.Unlock()
+
+	h.usage.ProcessingTime += processingTime
+	h.usage.Processed++
+	h.lastProcessingTime = processingTime
+}
+
+// If this handler has a CPU budget, wait long enough that the time spent
+// processing the last message is within the budget
+func (h *Handler) throttle() {
+	if h.budget == 0 {
+		return
+	}
+
+	h.usageLock.Lock()
+	processingTime := h.lastProcessingTime
+	h.usageLock.Unlock()
+
+	idleTime := time.Duration(float64(processingTime) * (1 - h.budget) / h.budget)
+	time.Sleep(idleTime)
+}
+
+// Pass a request from a peer to the consensus engine. If this handler is
+// throttled and its buffer is full, the request is held back until
+// promoteDelayed makes room for it.
+func (h *Handler) request(msg message) {
+	if h.budget == 0 {
+		h.push(msg)
+		return
+	}
+
+	h.hold(msg)
+	h.delayedLock.Lock()
+	defer h.delayedLock.Unlock()
+
+	// Requests that were held back earlier are passed on first
+	if len(h.delayed) == 0 {
+		select {
+		case h.msgs <- msg:
+			return
+		default:
+		}
+	}
+
+	size := msg.size()
+	for len(h.delayed) >= maxDelayedRequests || h.overMemoryBudget(size) {
+		h.delayedCond.Wait()
+	}
+	h.delayed = append(h.delayed, msg)
+	h.delayedBytes += size
+
+	h.usageLock.Lock()
+	h.usage.Delayed++
+	h.usageLock.Unlock()
+}
+
+// overMemoryBudget returns true if holding back another [size] bytes of
+// requests would exceed the memory budget. A request is always held back if no
+// other is, so that a request larger than the budget doesn't wait forever.
+// Assumes [h.delayedLock] is held.
+func (h *Handler) overMemoryBudget(size uint64) bool {
+	return h.memoryBudget != 0 && len(h.delayed) > 0 && h.delayedBytes+size > h.memoryBudget
+}
+
+// Move as many held back requests as fit into the message buffer
+func (h *Handler) promoteDelayed() {
+	if h.budget == 0 {
+		return
+	}
+
+	h.delayedLock.Lock()
+	defer h.delayedLock.Unlock()
+
+	promoted := 0
+promote:
+	for _, msg := range h.delayed {
+		select {
+		case h.msgs <- msg:
+			promoted++
+			h.delayedBytes -= msg.size()
+		default:
+			break promote
+		}
+	}
+	if promoted == 0 {
+		return
+	}
+
+	remaining := copy(h.delayed, h.delayed[promoted:])
+	for i := remaining; i < len(h.delayed); i++ {
+		h.delayed[i] = message{}
+	}
+	h.delayed = h.delayed[:remaining]
+	h.delayedCond.Broadcast()
+}
@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestHandlerDelaysRequestsWhenThrottled(t *testing.T) {
+	engine := &common.EngineTest{T: t}
+	handler := &Handler{}
+	handler.Initialize(engine, make(chan common.Message), 1)
+	handler.SetCPUBudget(.5)
+
+	vdr := ids.NewShortID([20]byte{1})
+	handler.PullQuery(vdr, 0, ids.Empty)
+	handler.PullQuery(vdr, 1, ids.Empty) // The buffer is full
+	handler.PullQuery(vdr, 2, ids.Empty)
+
+	if usage := handler.Usage(); usage.Delayed != 2 {
+		t.Fatalf("Should have delayed 2 requests but delayed %d", usage.Delayed)
+	}
+
+	for requestID := uint32(0); requestID < 3; requestID++ {
+		if msg := <-handler.msgs; msg.requestID != requestID {
+			t.Fatalf("Should have passed on request %d but passed on %d", requestID, msg.requestID)
+		}
+		handler.promoteDelayed()
+	}
+	if len(handler.delayed) != 0 {
+		t.Fatalf("Should have passed on every delayed request")
+	}
+
+	// Failure notifications are never delayed
+	done := make(chan struct{})
+	go func() {
+		handler.QueryFailed(vdr, 0)
+		close(done)
+	}()
+	<-handler.msgs
+	<-done
+}
+
+func TestHandlerBudgetBounds(t *testing.T) {
+	handler := &Handler{}
+	for _, budget := range []float64{-1, 0, 1, 2} {
+		handler.SetCPUBudget(budget)
+		if handler.CPUBudget() != 0 {
+			t.Fatalf("Budget %f shouldn't throttle the handler", budget)
+		}
+	}
+	handler.SetCPUBudget(.25)
+	if handler.CPUBudget() != .25 {
+		t.Fatalf("Budget should be .25 but is %f", handler.CPUBudget())
+	}
+}
+
+func TestHandlerMemoryBudget(t *testing.T) {
+	engine := &common.EngineTest{T: t}
+	handler := &Handler{}
+	handler.Initialize(engine, make(chan common.Message), 1)
+	handler.SetCPUBudget(.5)
+	handler.SetMemoryBudget(100)
+
+	vdr := ids.NewShortID([20]byte{1})
+	handler.PushQuery(vdr, 0, ids.Empty, make([]byte, 60))
+	handler.PushQuery(vdr, 1, ids.Empty, make([]byte, 60)) // The buffer is full
+
+	// Holding back another request would exceed the memory budget
+	done := make(chan struct{})
+	go func() {
+		handler.PushQuery(vdr, 2, ids.Empty, make([]byte, 60))
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("Shouldn't have held back more bytes than the memory budget")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	for requestID := uint32(0); requestID < 3; requestID++ {
+		msg := <-handler.msgs
+		if msg.requestID != requestID {
+			t.Fatalf("Should have passed on request %d but passed on %d", requestID, msg.requestID)
+		}
+		handler.release(msg)
+		handler.promoteDelayed()
+		if requestID == 0 {
+			<-done // Promoting request 1 made room for request 2 to be held back
+		}
+	}
+
+	usage := handler.Usage()
+	if usage.QueuedBytes != 0 {
+		t.Fatalf("Should hold no messages but holds %d bytes", usage.QueuedBytes)
+	}
+	if usage.PeakQueuedBytes != 180 {
+		t.Fatalf("Should have held at most 180 bytes but held %d", usage.PeakQueuedBytes)
+	}
+}
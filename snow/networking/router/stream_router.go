@@ -0,0 +1,418 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// DefaultStreamWindow is the default number of bytes of a stream's
+	// messages that may wait to be processed
+	DefaultStreamWindow = 8 * 1024 * 1024
+
+	// streamMsgOverhead approximates the size, in bytes, of a message besides
+	// its containers and container IDs
+	streamMsgOverhead = 64
+
+	// responseWindowMultiplier is how many times its window a stream may hold
+	// before the responses its peer sends are dropped too
+	responseWindowMultiplier = 2
+)
+
+// StreamRouter routes incoming messages from the network to the consensus
+// engines over a separate stream for each chain and peer. The messages of one
+// connection are handed off to their streams without waiting for their chains,
+// so a chain that's slow to process its messages, such as one that's
+// bootstrapping, doesn't hold up the messages other chains receive over the
+// same connection.
+//
+// Each stream has its own flow control. Once [window] bytes of a stream's
+// messages are waiting to be processed, the further requests the peer sends
+// are dropped, and time out for the peer, until the chain catches up. A
+// response is only accepted onto a stream if it answers one of this node's
+// outstanding requests to the peer, and no other response to that request is
+// waiting, so a peer can only send as many responses as this node has
+// requests outstanding to it. Responses are accepted past the window, as
+// dropping them would make this node's requests fail, but are dropped too
+// once the stream holds [responseWindowMultiplier] times the window. A chain
+// processes the messages of its streams in turn, so a peer sending it many
+// messages doesn't hold up the messages of its other peers.
+type StreamRouter struct {
+	// Router routes messages to their chains once they leave their streams
+	Router
+
+	log      logging.Logger
+	timeouts *timeout.Manager
+	window   int
+
+	// Number of requests dropped because their stream was full
+	numDropped prometheus.Counter
+
+	// Number of responses dropped because they didn't answer an outstanding
+	// request, or their stream was full
+	numDroppedResponses prometheus.Counter
+
+	lock   sync.Mutex
+	chains map[[32]byte]*chainStreams
+}
+
+// NewStreamRouter returns a router that routes incoming messages to [router]
+// over streams that each buffer up to [window] bytes of messages
+func NewStreamRouter(router Router, window int) *StreamRouter {
+	return &StreamRouter{
+		Router: router,
+		window: window,
+		numDropped: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "gecko",
+				Name:      "stream_dropped_requests",
+				Help:      "Number of requests from peers dropped because the chain had too many of the peer's messages waiting to be processed",
+			}),
+		numDroppedResponses: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "gecko",
+				Name:      "stream_dropped_responses",
+				Help:      "Number of responses from peers dropped because they didn't answer an outstanding request, or the chain had too many of the peer's messages waiting to be processed",
+			}),
+	}
+}
+
+// InitializeMetrics makes the requests and responses this router drops be
+// counted in the metrics registered with [registerer]
+func (sr *StreamRouter) InitializeMetrics(registerer prometheus.Registerer) error {
+	if err := registerer.Register(sr.numDropped); err != nil {
+		return err
+	}
+	return registerer.Register(sr.numDroppedResponses)
+}
+
+// Initialize the router
+func (sr *StreamRouter) Initialize(log logging.Logger, timeouts *timeout.Manager) {
+	sr.log = log
+	sr.timeouts = timeouts
+	sr.chains = make(map[[32]byte]*chainStreams)
+	sr.Router.Initialize(log, timeouts)
+}
+
+// AddChain registers the specified chain so that incoming messages can be
+// routed to it over its streams
+func (sr *StreamRouter) AddChain(chain *handler.Handler) {
+	sr.Router.AddChain(chain)
+
+	ctx := chain.Context()
+	chainID := ctx.ChainID
+	cs := &chainStreams{
+		log:                 sr.log,
+		chainID:             chainID,
+		nodeID:              ctx.NodeID,
+		timeouts:            sr.timeouts,
+		window:              sr.window,
+		numDropped:          sr.numDropped,
+		numDroppedResponses: sr.numDroppedResponses,
+		streams:             make(map[[20]byte]*stream),
+	}
+	cs.cond = sync.NewCond(&cs.lock)
+	cs.wg.Add(1)
+	go cs.dispatch()
+
+	sr.lock.Lock()
+	old := sr.chains[chainID.Key()]
+	sr.chains[chainID.Key()] = cs
+	sr.lock.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+}
+
+// RemoveChain removes the specified chain, and its streams, so that incoming
+// messages can't be routed to it
+func (sr *StreamRouter) RemoveChain(chainID ids.ID) {
+	sr.lock.Lock()
+	cs := sr.chains[chainID.Key()]
+	delete(sr.chains, chainID.Key())
+	sr.lock.Unlock()
+
+	if cs != nil {
+		cs.close()
+	}
+	sr.Router.RemoveChain(chainID)
+}
+
+// Shutdown shuts down this router and the chains it routes messages to
+func (sr *StreamRouter) Shutdown() {
+	sr.lock.Lock()
+	chains := sr.chains
+	sr.chains = make(map[[32]byte]*chainStreams)
+	sr.lock.Unlock()
+
+	for _, cs := range chains {
+		cs.close()
+	}
+	sr.Router.Shutdown()
+}
+
+// push a message of [size] bytes from [validatorID] onto its stream to the
+// chain [chainID]. [deliver] routes the message once it leaves the stream.
+// If [request] is true, the message is a request, which is dropped if the
+// stream is full. Otherwise, it's the response to the request [requestID].
+func (sr *StreamRouter) push(validatorID ids.ShortID, chainID ids.ID, requestID uint32, size int, request bool, deliver func()) {
+	sr.lock.Lock()
+	cs, exists := sr.chains[chainID.Key()]
+	sr.lock.Unlock()
+
+	if !exists {
+		// Routing the message reports that the chain doesn't exist
+		deliver()
+		return
+	}
+	cs.push(validatorID, requestID, size, request, deliver)
+}
+
+// containersSize returns the number of bytes of [containers]
+func containersSize(containers ...[]byte) int {
+	size := 0
+	for _, container := range containers {
+		size += len(container)
+	}
+	return size
+}
+
+// GetAcceptedFrontier implements the ExternalRouter interface
+func (sr *StreamRouter) GetAcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead, true, func() {
+		sr.Router.GetAcceptedFrontier(validatorID, chainID, requestID)
+	})
+}
+
+// AcceptedFrontier implements the ExternalRouter interface
+func (sr *StreamRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+containerIDs.Len()*32, false, func() {
+		sr.Router.AcceptedFrontier(validatorID, chainID, requestID, containerIDs)
+	})
+}
+
+// GetAccepted implements the ExternalRouter interface
+func (sr *StreamRouter) GetAccepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+containerIDs.Len()*32, true, func() {
+		sr.Router.GetAccepted(validatorID, chainID, requestID, containerIDs)
+	})
+}
+
+// Accepted implements the ExternalRouter interface
+func (sr *StreamRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+containerIDs.Len()*32, false, func() {
+		sr.Router.Accepted(validatorID, chainID, requestID, containerIDs)
+	})
+}
+
+// Get implements the ExternalRouter interface
+func (sr *StreamRouter) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead, true, func() {
+		sr.Router.Get(validatorID, chainID, requestID, containerID)
+	})
+}
+
+// Put implements the ExternalRouter interface
+func (sr *StreamRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+len(container), false, func() {
+		sr.Router.Put(validatorID, chainID, requestID, containerID, container)
+	})
+}
+
+// GetAncestors implements the ExternalRouter interface
+func (sr *StreamRouter) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead, true, func() {
+		sr.Router.GetAncestors(validatorID, chainID, requestID, containerID)
+	})
+}
+
+// MultiPut implements the ExternalRouter interface
+func (sr *StreamRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+containersSize(containers...), false, func() {
+		sr.Router.MultiPut(validatorID, chainID, requestID, containers)
+	})
+}
+
+// PushQuery implements the ExternalRouter interface
+func (sr *StreamRouter) PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+len(container), true, func() {
+		sr.Router.PushQuery(validatorID, chainID, requestID, containerID, container)
+	})
+}
+
+// PullQuery implements the ExternalRouter interface
+func (sr *StreamRouter) PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead, true, func() {
+		sr.Router.PullQuery(validatorID, chainID, requestID, containerID)
+	})
+}
+
+// Chits implements the ExternalRouter interface
+func (sr *StreamRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set) {
+	sr.push(validatorID, chainID, requestID, streamMsgOverhead+votes.Len()*32, false, func() {
+		sr.Router.Chits(validatorID, chainID, requestID, votes)
+	})
+}
+
+// chainStreams are the streams of messages from each peer to one chain
+type chainStreams struct {
+	log      logging.Logger
+	chainID  ids.ID
+	nodeID   ids.ShortID // This node, whose responses to its own requests aren't timed
+	timeouts *timeout.Manager
+	window   int
+
+	numDropped, numDroppedResponses prometheus.Counter
+
+	lock    sync.Mutex
+	cond    *sync.Cond
+	wg      sync.WaitGroup
+	closed  bool
+	streams map[[20]byte]*stream // The streams with messages waiting
+	ready   []*stream            // The streams with messages waiting, in the order they're served
+}
+
+// stream of messages from one peer to one chain
+type stream struct {
+	validatorID ids.ShortID
+	msgs        []streamMsg
+	bytes       int                 // The size of [msgs]
+	responses   map[uint32]struct{} // The requests whose responses are in [msgs]
+	dropped     int                 // Requests dropped since the stream was last full
+}
+
+type streamMsg struct {
+	size      int
+	response  bool
+	requestID uint32
+	deliver   func()
+}
+
+// push a message of [size] bytes from [validatorID] onto its stream. If
+// [request] is true, the message is a request, which is dropped if the stream
+// is full. Otherwise, it's the response to the request [requestID], which is
+// dropped unless it answers an outstanding request.
+func (cs *chainStreams) push(validatorID ids.ShortID, requestID uint32, size int, request bool, deliver func()) {
+	// This node's responses to its own requests are sent without timeouts
+	if !request && !validatorID.Equals(cs.nodeID) && !cs.timeouts.IsOutstanding(validatorID, cs.chainID, requestID) {
+		cs.log.Debug("dropping response %d from %s to chain %s, which doesn't answer an outstanding request",
+			requestID, validatorID, cs.chainID)
+		cs.numDroppedResponses.Inc()
+		return
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.closed {
+		return
+	}
+
+	s, exists := cs.streams[validatorID.Key()]
+	if !exists {
+		s = &stream{
+			validatorID: validatorID,
+			responses:   make(map[uint32]struct{}),
+		}
+		cs.streams[validatorID.Key()] = s
+		cs.ready = append(cs.ready, s)
+		cs.cond.Signal()
+	}
+	_, waiting := s.responses[requestID]
+
+	switch {
+	case !exists:
+		// A message is always accepted onto an empty stream, so that messages
+		// larger than the window can be received
+	case request && s.bytes+size > cs.window:
+		if s.dropped == 0 {
+			cs.log.Debug("dropping requests from %s to chain %s, which has %d bytes of its messages waiting to be processed",
+				validatorID, cs.chainID, s.bytes)
+		}
+		s.dropped++
+		cs.numDropped.Inc()
+		return
+	case !request && s.bytes+size > responseWindowMultiplier*cs.window:
+		cs.log.Debug("dropping response %d from %s to chain %s, which has %d bytes of its messages waiting to be processed",
+			requestID, validatorID, cs.chainID, s.bytes)
+		cs.numDroppedResponses.Inc()
+		return
+	case !request && waiting:
+		cs.log.Debug("dropping response %d from %s to chain %s, which already has a response to the request waiting",
+			requestID, validatorID, cs.chainID)
+		cs.numDroppedResponses.Inc()
+		return
+	}
+
+	if !request {
+		s.responses[requestID] = struct{}{}
+	}
+	s.msgs = append(s.msgs, streamMsg{
+		size:      size,
+		response:  !request,
+		requestID: requestID,
+		deliver:   deliver,
+	})
+	s.bytes += size
+}
+
+// dispatch the messages of the streams, serving the streams in turn, until the
+// streams are closed
+func (cs *chainStreams) dispatch() {
+	defer cs.wg.Done()
+
+	for {
+		cs.lock.Lock()
+		for len(cs.ready) == 0 && !cs.closed {
+			cs.cond.Wait()
+		}
+		if cs.closed {
+			cs.lock.Unlock()
+			return
+		}
+
+		s := cs.ready[0]
+		cs.ready = cs.ready[1:]
+		msg := s.msgs[0]
+		s.msgs = s.msgs[1:]
+		s.bytes -= msg.size
+		if msg.response {
+			delete(s.responses, msg.requestID)
+		}
+		if len(s.msgs) != 0 {
+			cs.ready = append(cs.ready, s)
+		} else {
+			delete(cs.streams, s.validatorID.Key())
+		}
+		if s.dropped != 0 {
+			cs.log.Debug("dropped %d requests from %s to chain %s", s.dropped, s.validatorID, cs.chainID)
+			s.dropped = 0
+		}
+		cs.lock.Unlock()
+
+		msg.deliver()
+	}
+}
+
+// close the streams, dropping their waiting messages, and wait for the message
+// being delivered, if any, to be delivered
+func (cs *chainStreams) close() {
+	cs.lock.Lock()
+	cs.closed = true
+	cs.streams = nil
+	cs.ready = nil
+	cs.cond.Broadcast()
+	cs.lock.Unlock()
+
+	cs.wg.Wait()
+}
@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// newTestChain returns a handler of the chain [chainID], whose engine passes
+// the containers it's sent, in puts and push queries, to [puts]
+func newTestChain(t *testing.T, chainID ids.ID, puts chan<- []byte) *handler.Handler {
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = chainID
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.Context { return ctx }
+	engine.PutF = func(_ ids.ShortID, _ uint32, _ ids.ID, container []byte) { puts <- container }
+	engine.PushQueryF = engine.PutF
+
+	h := &handler.Handler{}
+	h.Initialize(engine, nil, 1)
+	go h.Dispatch()
+	return h
+}
+
+// waitDispatched waits for the messages of the chain [chainID] to leave their
+// streams
+func waitDispatched(t *testing.T, sr *StreamRouter, chainID ids.ID) {
+	sr.lock.Lock()
+	cs := sr.chains[chainID.Key()]
+	sr.lock.Unlock()
+
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(time.Millisecond) {
+		cs.lock.Lock()
+		waiting := len(cs.ready)
+		cs.lock.Unlock()
+		if waiting == 0 {
+			return
+		}
+	}
+	t.Fatalf("messages should have left their streams")
+}
+
+func TestStreamRouter(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(time.Hour)
+	go tm.Dispatch()
+
+	sr := NewStreamRouter(&ChainRouter{}, 2*streamMsgOverhead)
+	sr.Initialize(logging.NoLog{}, &tm)
+
+	slowID := ids.Empty.Prefix(0)
+	fastID := ids.Empty.Prefix(1)
+	slowPuts := make(chan []byte)
+	fastPuts := make(chan []byte, 1)
+	sr.AddChain(newTestChain(t, slowID, slowPuts))
+	sr.AddChain(newTestChain(t, fastID, fastPuts))
+
+	vdr0 := ids.NewShortID([20]byte{1})
+	vdr1 := ids.NewShortID([20]byte{2})
+
+	// The slow chain's engine is stuck on the first container, its handler's
+	// buffer holds the second, and the third is being delivered to the
+	// handler, so the fourth waits in the stream, which is then full. Further
+	// requests are dropped.
+	for i := byte(0); i < 3; i++ {
+		sr.PushQuery(vdr0, slowID, uint32(i), ids.Empty, []byte{i})
+		waitDispatched(t, sr, slowID)
+	}
+	sr.PushQuery(vdr0, slowID, 3, ids.Empty, []byte{3})
+	sr.PushQuery(vdr0, slowID, 4, ids.Empty, []byte{4})
+	if dropped := testutil.ToFloat64(sr.numDropped); dropped != 1 {
+		t.Fatalf("should have counted 1 dropped request but counted %v", dropped)
+	}
+
+	// Responses to this node's requests are accepted past the window
+	tm.Register(vdr0, slowID, 6, func() {})
+	sr.Put(vdr0, slowID, 6, ids.Empty, []byte{6})
+
+	// Responses that don't answer an outstanding request, or answer a request
+	// that already has a response waiting, are dropped
+	sr.Put(vdr0, slowID, 7, ids.Empty, []byte{7})
+	sr.Put(vdr0, slowID, 6, ids.Empty, []byte{6})
+	if dropped := testutil.ToFloat64(sr.numDroppedResponses); dropped != 2 {
+		t.Fatalf("should have counted 2 dropped responses but counted %v", dropped)
+	}
+
+	// Responses are dropped once the stream holds twice its window
+	tm.Register(vdr0, slowID, 8, func() {})
+	sr.Put(vdr0, slowID, 8, ids.Empty, make([]byte, 2*streamMsgOverhead))
+	if dropped := testutil.ToFloat64(sr.numDroppedResponses); dropped != 3 {
+		t.Fatalf("should have counted 3 dropped responses but counted %v", dropped)
+	}
+
+	// The fast chain still receives its messages over the same connection
+	sr.PushQuery(vdr0, fastID, 0, ids.Empty, []byte{0})
+	select {
+	case <-fastPuts:
+	case <-time.After(time.Second):
+		t.Fatalf("the slow chain shouldn't have held up the fast chain")
+	}
+
+	// Another peer's stream to the slow chain isn't full
+	sr.PushQuery(vdr1, slowID, 0, ids.Empty, []byte{5})
+
+	received := []byte{}
+	for i := 0; i < 6; i++ {
+		select {
+		case container := <-slowPuts:
+			received = append(received, container[0])
+		case <-time.After(time.Second):
+			t.Fatalf("only received %v", received)
+		}
+	}
+	select {
+	case container := <-slowPuts:
+		t.Fatalf("should have dropped the container %d", container[0])
+	case <-time.After(50 * time.Millisecond):
+	}
+	expected := []byte{0, 1, 2, 3, 5, 6}
+	for i, container := range expected {
+		if received[i] != container {
+			t.Fatalf("expected %v but received %v", expected, received)
+		}
+	}
+
+	sr.Shutdown()
+}
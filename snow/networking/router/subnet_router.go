@@ -186,6 +186,52 @@ func (sr *ChainRouter) GetFailed(validatorID ids.ShortID, chainID ids.ID, reques
 	}
 }
 
+// GetAncestors routes an incoming GetAncestors request from the validator with
+// ID [validatorID] to the consensus engine working on the chain with ID
+// [chainID]
+func (sr *ChainRouter) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetAncestors(validatorID, requestID, containerID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// MultiPut routes an incoming MultiPut message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetAncestors message from this node,
+	// and when we sent that message we set a timeout. Since we got a response,
+	// cancel the timeout.
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.MultiPut(validatorID, requestID, containers)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// GetAncestorsFailed routes an incoming GetAncestorsFailed message from the
+// validator with ID [validatorID] to the consensus engine working on the chain
+// with ID [chainID]
+func (sr *ChainRouter) GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetAncestorsFailed(validatorID, requestID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
 // PushQuery routes an incoming PushQuery request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 func (sr *ChainRouter) PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
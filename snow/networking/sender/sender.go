@@ -102,6 +102,30 @@ func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.
 	s.sender.Put(validatorID, s.ctx.ChainID, requestID, containerID, container)
 }
 
+// GetAncestors sends a GetAncestors message to the consensus engine running on
+// the specified chain to the specified validator. The GetAncestors message
+// signifies that this consensus engine would like the recipient to send this
+// consensus engine the specified container and as many of its ancestors as fit
+// in one MultiPut message.
+func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	s.ctx.Log.Verbo("Sending GetAncestors to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
+	// Add a timeout -- if we don't get a response before the timeout expires,
+	// send this consensus engine a GetAncestorsFailed message
+	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.router.GetAncestorsFailed(validatorID, s.ctx.ChainID, requestID)
+	})
+	s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, containerID)
+}
+
+// MultiPut sends a MultiPut message to the consensus engine running on the
+// specified chain on the specified validator.
+// The MultiPut message gives the recipient the contents of several containers:
+// the container it requested, followed by its ancestors.
+func (s *Sender) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	s.ctx.Log.Verbo("Sending MultiPut to validator %s. RequestID: %d. NumContainers: %d", validatorID, requestID, len(containers))
+	s.sender.MultiPut(validatorID, s.ctx.ChainID, requestID, containers)
+}
+
 // PushQuery sends a PushQuery message to the consensus engines running on the specified chains
 // on the specified validators.
 // The PushQuery message signifies that this consensus engine would like each validator to send
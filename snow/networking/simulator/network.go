@@ -0,0 +1,253 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+)
+
+// Network simulates the network between nodes running in one process, so
+// consensus can be tested end-to-end without running nodes in separate
+// processes or containers. Each node registers the router its consensus
+// messages are delivered to, and sends its messages with the ExternalSender
+// the network returns for it.
+//
+// Messages are delivered asynchronously, after a latency drawn uniformly from
+// the network's latency range. A message may be lost, and is never delivered
+// between nodes in different partitions. Like on the real network, a message
+// that isn't delivered is noticed by its sender only when the request times
+// out.
+type Network struct {
+	lock sync.Mutex
+	rng  *rand.Rand
+
+	routers map[[20]byte]router.ExternalRouter
+
+	minLatency, maxLatency time.Duration
+	loss                   float64
+
+	// The partition each node is in. Nodes that aren't in any partition may
+	// reach every other node that isn't in a partition.
+	partitions map[[20]byte]int
+
+	delivered, dropped uint64
+}
+
+// NewNetwork returns a new network, with no latency, loss or partitions, that
+// draws latencies and losses from a source seeded by [seed]
+func NewNetwork(seed int64) *Network {
+	return &Network{
+		rng:        rand.New(rand.NewSource(seed)),
+		routers:    make(map[[20]byte]router.ExternalRouter),
+		partitions: make(map[[20]byte]int),
+	}
+}
+
+// AddNode connects the node [nodeID], whose consensus messages are delivered
+// to [router], to the network. It returns the ExternalSender the node sends its
+// consensus messages with.
+func (n *Network) AddNode(nodeID ids.ShortID, router router.ExternalRouter) sender.ExternalSender {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.routers[nodeID.Key()] = router
+	return &nodeSender{network: n, nodeID: nodeID}
+}
+
+// RemoveNode disconnects the node [nodeID] from the network. Messages sent to
+// it from then on, including messages already in flight, are dropped.
+func (n *Network) RemoveNode(nodeID ids.ShortID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	delete(n.routers, nodeID.Key())
+	delete(n.partitions, nodeID.Key())
+}
+
+// SetLatency sets the range each message's latency is drawn from
+func (n *Network) SetLatency(min, max time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if max < min {
+		max = min
+	}
+	n.minLatency = min
+	n.maxLatency = max
+}
+
+// SetLoss sets the probability, in [0, 1], that a message is lost
+func (n *Network) SetLoss(loss float64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.loss = loss
+}
+
+// Partition the network so that nodes in different [groups] can't reach each
+// other. Nodes that aren't in any group can reach each other, but not the
+// nodes in a group. Replaces any previous partition.
+func (n *Network) Partition(groups ...ids.ShortSet) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.partitions = make(map[[20]byte]int)
+	for i, group := range groups {
+		for _, nodeID := range group.List() {
+			n.partitions[nodeID.Key()] = i + 1
+		}
+	}
+}
+
+// Heal the network's partition, so every node can reach every other node
+func (n *Network) Heal() { n.Partition() }
+
+// Stats returns the number of messages delivered and dropped so far
+func (n *Network) Stats() (delivered, dropped uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.delivered, n.dropped
+}
+
+// send [deliver] from [from] to [to], unless the message is lost or the nodes
+// can't reach each other
+func (n *Network) send(from, to ids.ShortID, deliver func(router.ExternalRouter)) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	_, connected := n.routers[to.Key()]
+	if !connected || n.partitions[from.Key()] != n.partitions[to.Key()] || n.rng.Float64() < n.loss {
+		n.dropped++
+		return
+	}
+
+	latency := n.minLatency
+	if spread := n.maxLatency - n.minLatency; spread > 0 {
+		latency += time.Duration(n.rng.Int63n(int64(spread) + 1))
+	}
+	time.AfterFunc(latency, func() {
+		n.lock.Lock()
+		router, connected := n.routers[to.Key()]
+		if connected {
+			n.delivered++
+		} else {
+			n.dropped++
+		}
+		n.lock.Unlock()
+
+		if connected {
+			deliver(router)
+		}
+	})
+}
+
+// nodeSender sends the consensus messages of one node over the network
+type nodeSender struct {
+	network *Network
+	nodeID  ids.ShortID
+}
+
+// copySet returns a copy of [s], so a message's contents can't be modified
+// while it's in flight
+func copySet(s ids.Set) ids.Set {
+	c := ids.Set{}
+	c.Union(s)
+	return c
+}
+
+// GetAcceptedFrontier implements the ExternalSender interface
+func (s *nodeSender) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	for _, validatorID := range validatorIDs.List() {
+		s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetAcceptedFrontier(s.nodeID, chainID, requestID)
+		})
+	}
+}
+
+// AcceptedFrontier implements the ExternalSender interface
+func (s *nodeSender) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	containerIDs = copySet(containerIDs)
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.AcceptedFrontier(s.nodeID, chainID, requestID, containerIDs)
+	})
+}
+
+// GetAccepted implements the ExternalSender interface
+func (s *nodeSender) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	for _, validatorID := range validatorIDs.List() {
+		containerIDs := copySet(containerIDs)
+		s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetAccepted(s.nodeID, chainID, requestID, containerIDs)
+		})
+	}
+}
+
+// Accepted implements the ExternalSender interface
+func (s *nodeSender) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	containerIDs = copySet(containerIDs)
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Accepted(s.nodeID, chainID, requestID, containerIDs)
+	})
+}
+
+// Get implements the ExternalSender interface
+func (s *nodeSender) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Get(s.nodeID, chainID, requestID, containerID)
+	})
+}
+
+// Put implements the ExternalSender interface
+func (s *nodeSender) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Put(s.nodeID, chainID, requestID, containerID, container)
+	})
+}
+
+// GetAncestors implements the ExternalSender interface
+func (s *nodeSender) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.GetAncestors(s.nodeID, chainID, requestID, containerID)
+	})
+}
+
+// MultiPut implements the ExternalSender interface
+func (s *nodeSender) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.MultiPut(s.nodeID, chainID, requestID, containers)
+	})
+}
+
+// PushQuery implements the ExternalSender interface
+func (s *nodeSender) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	for _, validatorID := range validatorIDs.List() {
+		s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.PushQuery(s.nodeID, chainID, requestID, containerID, container)
+		})
+	}
+}
+
+// PullQuery implements the ExternalSender interface
+func (s *nodeSender) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	for _, validatorID := range validatorIDs.List() {
+		s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.PullQuery(s.nodeID, chainID, requestID, containerID)
+		})
+	}
+}
+
+// Chits implements the ExternalSender interface
+func (s *nodeSender) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set) {
+	votes = copySet(votes)
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Chits(s.nodeID, chainID, requestID, votes)
+	})
+}
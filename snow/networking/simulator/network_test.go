@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// testNode is a node whose engine answers every query with [vote]
+type testNode struct {
+	ctx    *snow.Context
+	engine *common.EngineTest
+	router *router.ChainRouter
+	sender *sender.Sender
+}
+
+func newTestNode(t *testing.T, network *Network, nodeID ids.ShortID, vote ids.ID) *testNode {
+	ctx := snow.DefaultContextTest()
+	ctx.NodeID = nodeID
+
+	tm := &timeout.Manager{}
+	tm.Initialize(50 * time.Millisecond)
+	go tm.Dispatch()
+
+	node := &testNode{
+		ctx:    ctx,
+		engine: &common.EngineTest{T: t},
+		router: &router.ChainRouter{},
+		sender: &sender.Sender{},
+	}
+	node.router.Initialize(logging.NoLog{}, tm)
+	node.sender.Initialize(ctx, network.AddNode(nodeID, node.router), node.router, tm)
+
+	node.engine.Default(false)
+	node.engine.ContextF = func() *snow.Context { return ctx }
+	node.engine.PullQueryF = func(validatorID ids.ShortID, requestID uint32, _ ids.ID) {
+		votes := ids.Set{}
+		votes.Add(vote)
+		node.sender.Chits(validatorID, requestID, votes)
+	}
+
+	h := &handler.Handler{}
+	h.Initialize(node.engine, nil, 16)
+	go h.Dispatch()
+	node.router.AddChain(h)
+	return node
+}
+
+func TestNetwork(t *testing.T) {
+	network := NewNetwork(0)
+	network.SetLatency(time.Millisecond, 5*time.Millisecond)
+
+	vote := ids.Empty.Prefix(0)
+	nodeIDs := []ids.ShortID{
+		ids.NewShortID([20]byte{1}),
+		ids.NewShortID([20]byte{2}),
+		ids.NewShortID([20]byte{3}),
+	}
+	nodes := []*testNode{}
+	for _, nodeID := range nodeIDs {
+		node := newTestNode(t, network, nodeID, vote)
+		defer node.router.Shutdown()
+		nodes = append(nodes, node)
+	}
+	peers := ids.ShortSet{}
+	peers.Add(nodeIDs[1], nodeIDs[2])
+
+	lock := sync.Mutex{}
+	chits := ids.ShortSet{}
+	failed := ids.ShortSet{}
+	wg := sync.WaitGroup{}
+	nodes[0].engine.ChitsF = func(validatorID ids.ShortID, _ uint32, votes ids.Set) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if !votes.Contains(vote) {
+			t.Errorf("received the wrong votes")
+		}
+		chits.Add(validatorID)
+		wg.Done()
+	}
+	nodes[0].engine.QueryFailedF = func(validatorID ids.ShortID, _ uint32) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		failed.Add(validatorID)
+		wg.Done()
+	}
+	query := func(requestID uint32) {
+		chits.Clear()
+		failed.Clear()
+		wg.Add(peers.Len())
+		vdrs := ids.ShortSet{}
+		vdrs.Union(peers)
+		nodes[0].sender.PullQuery(vdrs, requestID, vote)
+		wg.Wait()
+	}
+
+	// Every peer answers
+	query(0)
+	if !chits.Equals(peers) || failed.Len() != 0 {
+		t.Fatalf("every peer should have answered")
+	}
+	if delivered, dropped := network.Stats(); delivered != 4 || dropped != 0 {
+		t.Fatalf("expected 4 messages delivered and none dropped but got %d and %d", delivered, dropped)
+	}
+
+	// A partitioned node can't reach the other nodes, so its queries time out
+	isolated := ids.ShortSet{}
+	isolated.Add(nodeIDs[0])
+	network.Partition(isolated)
+	query(1)
+	if chits.Len() != 0 || !failed.Equals(peers) {
+		t.Fatalf("every query should have failed while partitioned")
+	}
+
+	// Once healed, every peer answers again
+	network.Heal()
+	query(2)
+	if !chits.Equals(peers) || failed.Len() != 0 {
+		t.Fatalf("every peer should have answered once healed")
+	}
+
+	// Every message is lost
+	network.SetLoss(1)
+	query(3)
+	if chits.Len() != 0 || !failed.Equals(peers) {
+		t.Fatalf("every query should have failed when every message is lost")
+	}
+	if _, dropped := network.Stats(); dropped != 4 {
+		t.Fatalf("expected 4 messages dropped but got %d", dropped)
+	}
+}
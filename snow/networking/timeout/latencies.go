@@ -0,0 +1,91 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// rttWeight is the weight given to a new round trip time sample when
+	// updating a peer's smoothed round trip time
+	rttWeight = 0.125
+)
+
+// Latencies reports the round trip times to peers
+type Latencies interface {
+	// RTT returns the smoothed round trip time to [validatorID], and false if
+	// it hasn't been measured
+	RTT(validatorID ids.ShortID) (time.Duration, bool)
+}
+
+// LatencyTracker measures the round trip times to peers by matching the pings
+// sent to them with the pongs they reply with. A pong is matched with the most
+// recent ping sent to the peer, so pings should be sent far less often than
+// the round trip time.
+type LatencyTracker struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	pings map[[20]byte]time.Time     // Peer -> When the outstanding ping was sent
+	rtts  map[[20]byte]time.Duration // Peer -> Smoothed round trip time
+}
+
+// Initialize this latency tracker
+func (lt *LatencyTracker) Initialize() {
+	lt.pings = make(map[[20]byte]time.Time)
+	lt.rtts = make(map[[20]byte]time.Duration)
+}
+
+// Sent records that a ping was sent to [validatorID]
+func (lt *LatencyTracker) Sent(validatorID ids.ShortID) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+
+	lt.pings[validatorID.Key()] = lt.clock.Time()
+}
+
+// Received records that a pong was received from [validatorID]
+func (lt *LatencyTracker) Received(validatorID ids.ShortID) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+
+	key := validatorID.Key()
+	sent, outstanding := lt.pings[key]
+	if !outstanding {
+		return // Unsolicited pong
+	}
+	delete(lt.pings, key)
+
+	sample := lt.clock.Time().Sub(sent)
+	if rtt, exists := lt.rtts[key]; exists {
+		lt.rtts[key] = rtt + time.Duration(rttWeight*float64(sample-rtt))
+	} else {
+		lt.rtts[key] = sample
+	}
+}
+
+// Remove forgets the round trip time to [validatorID]. This should be called
+// when the peer disconnects.
+func (lt *LatencyTracker) Remove(validatorID ids.ShortID) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+
+	key := validatorID.Key()
+	delete(lt.pings, key)
+	delete(lt.rtts, key)
+}
+
+// RTT implements the Latencies interface
+func (lt *LatencyTracker) RTT(validatorID ids.ShortID) (time.Duration, bool) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+
+	rtt, exists := lt.rtts[validatorID.Key()]
+	return rtt, exists
+}
@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	lt := LatencyTracker{}
+	lt.Initialize()
+
+	vdr := ids.NewShortID([20]byte{1})
+	now := time.Unix(1000, 0)
+	lt.clock.Set(now)
+
+	if _, measured := lt.RTT(vdr); measured {
+		t.Fatalf("Shouldn't have measured the RTT before any pings were sent")
+	}
+
+	lt.Received(vdr)
+	if _, measured := lt.RTT(vdr); measured {
+		t.Fatalf("Shouldn't have measured the RTT from an unsolicited pong")
+	}
+
+	lt.Sent(vdr)
+	lt.clock.Set(now.Add(80 * time.Millisecond))
+	lt.Received(vdr)
+	if rtt, _ := lt.RTT(vdr); rtt != 80*time.Millisecond {
+		t.Fatalf("RTT should have been 80ms but was %s", rtt)
+	}
+
+	// A second pong to the same ping is ignored
+	lt.clock.Set(now.Add(time.Second))
+	lt.Received(vdr)
+	if rtt, _ := lt.RTT(vdr); rtt != 80*time.Millisecond {
+		t.Fatalf("RTT should have been 80ms but was %s", rtt)
+	}
+
+	lt.Sent(vdr)
+	lt.clock.Set(now.Add(time.Second + 160*time.Millisecond))
+	lt.Received(vdr)
+	if rtt, _ := lt.RTT(vdr); rtt != 90*time.Millisecond {
+		t.Fatalf("RTT should have been smoothed to 90ms but was %s", rtt)
+	}
+
+	lt.Remove(vdr)
+	if _, measured := lt.RTT(vdr); measured {
+		t.Fatalf("Shouldn't have an RTT after the peer was removed")
+	}
+}
+
+func TestManagerTimeout(t *testing.T) {
+	lt := &LatencyTracker{}
+	lt.Initialize()
+
+	manager := Manager{}
+	manager.Initialize(time.Second)
+	manager.SetLatencies(lt)
+
+	fast := ids.NewShortID([20]byte{1})
+	slow := ids.NewShortID([20]byte{2})
+	verySlow := ids.NewShortID([20]byte{3})
+	unmeasured := ids.NewShortID([20]byte{4})
+
+	now := time.Unix(1000, 0)
+	for vdr, rtt := range map[[20]byte]time.Duration{
+		fast.Key():     10 * time.Millisecond,
+		slow.Key():     500 * time.Millisecond,
+		verySlow.Key(): 2 * time.Second,
+	} {
+		vdrID := ids.NewShortID(vdr)
+		lt.clock.Set(now)
+		lt.Sent(vdrID)
+		lt.clock.Set(now.Add(rtt))
+		lt.Received(vdrID)
+	}
+
+	if timeout := manager.Timeout(fast); timeout != time.Second {
+		t.Fatalf("Timeout to a fast validator should have been the default, but was %s", timeout)
+	}
+	if timeout := manager.Timeout(slow); timeout != 2*time.Second {
+		t.Fatalf("Timeout to a slow validator should have been 2s, but was %s", timeout)
+	}
+	if timeout := manager.Timeout(verySlow); timeout != MaxTimeoutMultiplier*time.Second {
+		t.Fatalf("Timeout to a very slow validator should have been capped, but was %s", timeout)
+	}
+	if timeout := manager.Timeout(unmeasured); timeout != time.Second {
+		t.Fatalf("Timeout to an unmeasured validator should have been the default, but was %s", timeout)
+	}
+}
@@ -6,14 +6,37 @@ package timeout
 import (
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
+const (
+	// LatencyMultiplier is the number of round trip times to a validator that
+	// a request to it is given before it times out
+	LatencyMultiplier = 4
+
+	// MaxTimeoutMultiplier bounds how much longer than the default duration a
+	// request to a slow validator is given before it times out
+	MaxTimeoutMultiplier = 4
+
+	// LeakCheckFrequency is the amount of time to wait between checking for
+	// requests that were never answered nor timed out
+	LeakCheckFrequency = 10 * time.Second
+)
+
 // Manager registers and fires timeouts for the snow API.
-type Manager struct{ tm timer.TimeoutManager }
+type Manager struct {
+	tm          timer.TimeoutManager
+	duration    time.Duration
+	latencies   Latencies
+	requests    requests
+	leakChecker *timer.Repeater
+}
 
 // Initialize this timeout manager.
 //
@@ -22,20 +45,91 @@ type Manager struct{ tm timer.TimeoutManager }
 //
 // [duration] is the amount of time to allow for external requests
 // before the request times out.
-func (m *Manager) Initialize(duration time.Duration) { m.tm.Initialize(duration) }
+func (m *Manager) Initialize(duration time.Duration) {
+	m.duration = duration
+	m.tm.Initialize(duration)
+	m.requests.Initialize(duration)
+	m.leakChecker = timer.NewRepeater(func() { m.requests.checkLeaks() }, LeakCheckFrequency)
+}
+
+// InitializeLeakDetection makes requests that are never answered nor timed out
+// be logged to [log] and counted in the metrics registered with [registerer]
+func (m *Manager) InitializeLeakDetection(log logging.Logger, registerer prometheus.Registerer) error {
+	m.requests.log = log
+	if err := registerer.Register(m.requests.numOutstanding); err != nil {
+		return err
+	}
+	return registerer.Register(m.requests.numLeaked)
+}
+
+// SetLatencies makes requests to validators whose round trip time is long,
+// relative to the default duration, take longer to time out. This should be
+// called before any requests are registered.
+func (m *Manager) SetLatencies(latencies Latencies) { m.latencies = latencies }
+
+// SetClock makes request deadlines be measured on [clock]. Request timeouts
+// bound real network round trips, so this is meant for tests. This should be
+// called before any requests are registered.
+func (m *Manager) SetClock(clock timer.Clock) {
+	m.tm.SetClock(clock)
+	m.requests.clock = clock
+}
 
 // Dispatch ...
-func (m *Manager) Dispatch() { m.tm.Dispatch() }
+func (m *Manager) Dispatch() {
+	go m.leakChecker.Dispatch()
+	m.tm.Dispatch()
+}
 
 // Register request to time out unless Manager.Cancel is called
 // before the timeout duration passes, with the same request parameters.
 func (m *Manager) Register(validatorID ids.ShortID, chainID ids.ID, requestID uint32, timeout func()) {
-	m.tm.Put(createRequestID(validatorID, chainID, requestID), timeout)
+	id := createRequestID(validatorID, chainID, requestID)
+	duration := m.Timeout(validatorID)
+	m.requests.add(id, validatorID, chainID, requestID, duration)
+	m.tm.PutWithDuration(id, duration, func() {
+		m.requests.remove(id)
+		timeout()
+	})
+}
+
+// Timeout returns how long a request to [validatorID] is given before it times
+// out
+func (m *Manager) Timeout(validatorID ids.ShortID) time.Duration {
+	if m.latencies == nil {
+		return m.duration
+	}
+	rtt, measured := m.latencies.RTT(validatorID)
+	if !measured {
+		return m.duration
+	}
+
+	duration := LatencyMultiplier * rtt
+	switch maxDuration := MaxTimeoutMultiplier * m.duration; {
+	case duration < m.duration:
+		return m.duration
+	case duration > maxDuration:
+		return maxDuration
+	default:
+		return duration
+	}
 }
 
 // Cancel request timeout with the specified parameters.
 func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
-	m.tm.Remove(createRequestID(validatorID, chainID, requestID))
+	id := createRequestID(validatorID, chainID, requestID)
+	m.tm.Remove(id)
+	m.requests.remove(id)
+}
+
+// Outstanding returns the number of requests that haven't been answered or
+// timed out
+func (m *Manager) Outstanding() int { return m.requests.len() }
+
+// IsOutstanding returns true if the request with the specified parameters
+// hasn't been answered or timed out
+func (m *Manager) IsOutstanding(validatorID ids.ShortID, chainID ids.ID, requestID uint32) bool {
+	return m.requests.has(createRequestID(validatorID, chainID, requestID))
 }
 
 func createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
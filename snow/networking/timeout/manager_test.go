@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -46,3 +48,83 @@ func TestManagerCancel(t *testing.T) {
 		t.Fatalf("Should have cancelled the function")
 	}
 }
+
+func TestManagerOutstanding(t *testing.T) {
+	manager := Manager{}
+	manager.Initialize(time.Hour)
+	go manager.Dispatch()
+
+	vdr := ids.NewShortID([20]byte{1})
+	chainID := ids.NewID([32]byte{2})
+
+	manager.Register(vdr, chainID, 0, func() {})
+	manager.Register(vdr, chainID, 1, func() {})
+	if outstanding := manager.Outstanding(); outstanding != 2 {
+		t.Fatalf("Should have had 2 outstanding requests but had %d", outstanding)
+	}
+
+	manager.Cancel(vdr, chainID, 0)
+	if outstanding := manager.Outstanding(); outstanding != 1 {
+		t.Fatalf("Should have had 1 outstanding request but had %d", outstanding)
+	}
+	if manager.IsOutstanding(vdr, chainID, 0) {
+		t.Fatalf("Request 0 shouldn't have been outstanding once it was cancelled")
+	}
+	if !manager.IsOutstanding(vdr, chainID, 1) {
+		t.Fatalf("Request 1 should have been outstanding")
+	}
+
+	manager.Cancel(vdr, chainID, 1)
+	if outstanding := manager.Outstanding(); outstanding != 0 {
+		t.Fatalf("Shouldn't have had outstanding requests but had %d", outstanding)
+	}
+}
+
+func TestManagerTimedOutRequestIsNotOutstanding(t *testing.T) {
+	manager := Manager{}
+	manager.Initialize(time.Millisecond)
+	go manager.Dispatch()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0, wg.Done)
+
+	wg.Wait()
+
+	if outstanding := manager.Outstanding(); outstanding != 0 {
+		t.Fatalf("Shouldn't have had outstanding requests but had %d", outstanding)
+	}
+}
+
+func TestRequestsCheckLeaks(t *testing.T) {
+	r := requests{}
+	r.Initialize(time.Second)
+
+	now := time.Unix(1000, 0)
+	r.clock.Set(now)
+
+	vdr := ids.NewShortID([20]byte{1})
+	chainID := ids.NewID([32]byte{2})
+	r.add(ids.NewID([32]byte{3}), vdr, chainID, 0, time.Second)
+	r.add(ids.NewID([32]byte{4}), vdr, chainID, 1, 10*time.Second)
+
+	r.clock.Set(now.Add(2 * time.Second))
+	if leaked := r.checkLeaks(); leaked != 0 {
+		t.Fatalf("No requests should have leaked before the grace period ended, but %d did", leaked)
+	}
+
+	r.clock.Set(now.Add(3 * time.Second))
+	if leaked := r.checkLeaks(); leaked != 1 {
+		t.Fatalf("1 request should have leaked, but %d did", leaked)
+	}
+	if outstanding := r.len(); outstanding != 1 {
+		t.Fatalf("The leaked request should no longer be tracked, but %d requests are", outstanding)
+	}
+
+	// Registering a request that is already outstanding leaks the earlier one
+	r.add(ids.NewID([32]byte{4}), vdr, chainID, 1, 10*time.Second)
+	if leaked := testutil.ToFloat64(r.numLeaked); leaked != 2 {
+		t.Fatalf("2 requests should have leaked in total, but %v did", leaked)
+	}
+}
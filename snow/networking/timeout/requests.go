@@ -0,0 +1,133 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// request is a request that was sent to a validator and that hasn't been
+// answered or timed out yet
+type request struct {
+	validatorID ids.ShortID
+	chainID     ids.ID
+	requestID   uint32
+	registered  time.Time
+	deadline    time.Time
+}
+
+// requests tracks the outstanding requests so that requests that are never
+// answered nor timed out are detected. Without it, a leaked request only shows
+// up as a chain whose consensus is stuck.
+type requests struct {
+	lock  sync.Mutex
+	log   logging.Logger
+	clock timer.Clock
+
+	// gracePeriod is how long after its deadline a request is considered leaked
+	gracePeriod time.Duration
+
+	outstanding map[[32]byte]request // Request ID -> Request
+
+	numOutstanding prometheus.Gauge
+	numLeaked      prometheus.Counter
+}
+
+func (r *requests) Initialize(gracePeriod time.Duration) {
+	r.log = logging.NoLog{}
+	r.gracePeriod = gracePeriod
+	r.outstanding = make(map[[32]byte]request)
+	r.numOutstanding = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "outstanding_requests",
+			Help:      "Number of requests sent to validators that haven't been answered or timed out",
+		})
+	r.numLeaked = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "leaked_requests",
+			Help:      "Number of requests sent to validators that were never answered nor timed out",
+		})
+}
+
+// add a request that is due to time out after [duration]
+func (r *requests) add(id ids.ID, validatorID ids.ShortID, chainID ids.ID, requestID uint32, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := id.Key()
+	if _, exists := r.outstanding[key]; exists {
+		// The timeout of the earlier request is replaced, so it will never
+		// time out.
+		r.log.Warn("Request %d to %s on chain %s was sent again while it was outstanding", requestID, validatorID, chainID)
+		r.numLeaked.Inc()
+	}
+
+	now := r.clock.Time()
+	r.outstanding[key] = request{
+		validatorID: validatorID,
+		chainID:     chainID,
+		requestID:   requestID,
+		registered:  now,
+		deadline:    now.Add(duration),
+	}
+	r.numOutstanding.Set(float64(len(r.outstanding)))
+}
+
+// remove the request, because it was answered or it timed out
+func (r *requests) remove(id ids.ID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.outstanding, id.Key())
+	r.numOutstanding.Set(float64(len(r.outstanding)))
+}
+
+// has returns true if the request is outstanding
+func (r *requests) has(id ids.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, exists := r.outstanding[id.Key()]
+	return exists
+}
+
+// len returns the number of outstanding requests
+func (r *requests) len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.outstanding)
+}
+
+// checkLeaks logs, and stops tracking, the requests that should have timed out
+// more than the grace period ago. It returns the number of leaked requests.
+func (r *requests) checkLeaks() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	numLeaked := 0
+	for key, req := range r.outstanding {
+		if !now.After(req.deadline.Add(r.gracePeriod)) {
+			continue
+		}
+		r.log.Warn("Request %d to %s on chain %s was never answered nor timed out. It was sent %s ago",
+			req.requestID, req.validatorID, req.chainID, now.Sub(req.registered))
+		delete(r.outstanding, key)
+		numLeaked++
+	}
+
+	r.numLeaked.Add(float64(numLeaked))
+	r.numOutstanding.Set(float64(len(r.outstanding)))
+	return numLeaked
+}
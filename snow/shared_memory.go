@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+)
+
+// MaxMessagePayloadSize is the largest payload a message between chains may
+// carry
+const MaxMessagePayloadSize = 1 << 16
+
+// Message is a payload that a chain sent to another chain on the same node
+type Message struct {
+	// ID commits to every other field of the message
+	ID ids.ID
+
+	// Source is the chain that sent the message
+	Source ids.ID
+
+	// Destination is the chain that receives the message
+	Destination ids.ID
+
+	// Type decides which MessageVerifier of the destination chain verifies the
+	// message
+	Type uint32
+
+	// Nonce is the position of the message in the queue from [Source] to
+	// [Destination]
+	Nonce uint64
+
+	Payload []byte
+}
+
+// MessageVerifier verifies the messages of one type that a chain receives.
+// Like an Fx, it's provided by the receiving VM, which defines what a valid
+// message of that type is. VerifyMessage must not use the SharedMemory.
+type MessageVerifier interface {
+	VerifyMessage(msg *Message) error
+}
+
+// SharedMemory passes messages between the chains on a node. Every ordered
+// pair of chains has a queue of messages. The source chain sends messages into
+// the queue. The destination chain receives them in the order they were sent,
+// and consumes them once it has acted on them.
+//
+// Sending and consuming messages are written in one atomic write with a batch
+// of the chain's database, so that a chain's state and the messages it sent or
+// consumed can't disagree after a crash. The batch must be of a database that
+// is stored in the same database as the shared memory, such as the batch
+// returned by the CommitBatch method of the versiondb a chain is given.
+type SharedMemory interface {
+	// RegisterVerifier sets the verifier of the messages of type [msgType]
+	// that this chain receives. Messages of types without a verifier aren't
+	// received until a verifier is registered.
+	RegisterVerifier(msgType uint32, verifier MessageVerifier) error
+
+	// Send [msgs] to their destinations, and write [batch], in one atomic
+	// write. The Destination, Type and Payload of each message must be set.
+	// Send sets their Source, Nonce and ID. [batch] may be nil.
+	Send(batch database.Batch, msgs ...*Message) error
+
+	// Receive returns up to [limit] of the messages that the chain with ID
+	// [source] sent to this chain and that haven't been consumed, in the order
+	// they were sent. Messages that fail verification are dropped. Messages
+	// whose type has no verifier are skipped, but stay in the queue.
+	Receive(source ids.ID, limit int) ([]*Message, error)
+
+	// Consume [msgs], which were received by this chain, and write [batch], in
+	// one atomic write. A chain should consume a message once it has acted on
+	// it, so that it isn't received again. [batch] may be nil.
+	Consume(batch database.Batch, msgs ...*Message) error
+}
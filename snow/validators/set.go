@@ -42,6 +42,25 @@ type Set interface {
 	// [size]. Otherwise, the length of the returned validators will equal
 	// [size].
 	Sample(size int) []Validator
+
+	// SampleFrom is Sample, drawing from [source] rather than the global
+	// source of randomness. If [source] is nil, the global source is used.
+	SampleFrom(size int, source random.Source) []Validator
+
+	// RegisterCallbackListener registers [listener] to be notified whenever a
+	// validator joins or leaves this set.
+	RegisterCallbackListener(listener SetCallbackListener)
+}
+
+// SetCallbackListener is notified of changes to the membership of a validator
+// set. The callbacks are made after the set has been modified and without
+// holding the set's lock.
+type SetCallbackListener interface {
+	// OnValidatorAdded is called when [vdrID] joins the set
+	OnValidatorAdded(vdrID ids.ShortID)
+
+	// OnValidatorRemoved is called when [vdrID] leaves the set
+	OnValidatorRemoved(vdrID ids.ShortID)
 }
 
 // NewSet returns a new, empty set of validators.
@@ -57,17 +76,26 @@ type set struct {
 	vdrMap   map[[20]byte]int
 	vdrSlice []Validator
 	sampler  random.Weighted
+
+	callbackListeners []SetCallbackListener
 }
 
 // Set implements the Set interface.
 func (s *set) Set(vdrs []Validator) {
 	s.lock.Lock()
-	defer s.lock.Unlock()
+	added, removed := s.set(vdrs)
+	listeners := s.callbackListeners
+	s.lock.Unlock()
 
-	s.set(vdrs)
+	notify(listeners, added, removed)
 }
 
-func (s *set) set(vdrs []Validator) {
+func (s *set) set(vdrs []Validator) (added, removed []ids.ShortID) {
+	oldIDs := ids.ShortSet{}
+	for _, vdr := range s.vdrSlice {
+		oldIDs.Add(vdr.ID())
+	}
+
 	s.vdrMap = make(map[[20]byte]int, len(vdrs))
 	s.vdrSlice = s.vdrSlice[:0]
 	s.sampler.Weights = s.sampler.Weights[:0]
@@ -75,14 +103,35 @@ func (s *set) set(vdrs []Validator) {
 	for _, vdr := range vdrs {
 		s.add(vdr)
 	}
+
+	for _, vdr := range s.vdrSlice {
+		vdrID := vdr.ID()
+		if oldIDs.Contains(vdrID) {
+			oldIDs.Remove(vdrID)
+		} else {
+			added = append(added, vdrID)
+		}
+	}
+	return added, oldIDs.List()
 }
 
 // Add implements the Set interface.
 func (s *set) Add(vdr Validator) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	vdrID := vdr.ID()
 
+	s.lock.Lock()
+	wasMember := s.contains(vdrID)
 	s.add(vdr)
+	isMember := s.contains(vdrID)
+	listeners := s.callbackListeners
+	s.lock.Unlock()
+
+	switch {
+	case !wasMember && isMember:
+		notify(listeners, []ids.ShortID{vdrID}, nil)
+	case wasMember && !isMember:
+		notify(listeners, nil, []ids.ShortID{vdrID})
+	}
 }
 
 func (s *set) add(vdr Validator) {
@@ -105,9 +154,14 @@ func (s *set) add(vdr Validator) {
 // Remove implements the Set interface.
 func (s *set) Remove(vdrID ids.ShortID) {
 	s.lock.Lock()
-	defer s.lock.Unlock()
-
+	wasMember := s.contains(vdrID)
 	s.remove(vdrID)
+	listeners := s.callbackListeners
+	s.lock.Unlock()
+
+	if wasMember {
+		notify(listeners, nil, []ids.ShortID{vdrID})
+	}
 }
 
 func (s *set) remove(vdrID ids.ShortID) {
@@ -172,16 +226,20 @@ func (s *set) list() []Validator {
 }
 
 // Sample implements the Group interface.
-func (s *set) Sample(size int) []Validator {
+func (s *set) Sample(size int) []Validator { return s.SampleFrom(size, nil) }
+
+// SampleFrom implements the Group interface.
+func (s *set) SampleFrom(size int, source random.Source) []Validator {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	return s.sample(size)
+	return s.sample(size, source)
 }
 
-func (s *set) sample(size int) []Validator {
+func (s *set) sample(size int, source random.Source) []Validator {
 	list := make([]Validator, size)[:0]
 
+	s.sampler.Source = source
 	s.sampler.Replace() // Must replace, otherwise changes won't be reflected
 	for ; size > 0 && s.sampler.CanSample(); size-- {
 		i := s.sampler.Sample()
@@ -190,6 +248,25 @@ func (s *set) sample(size int) []Validator {
 	return list
 }
 
+// RegisterCallbackListener implements the Set interface.
+func (s *set) RegisterCallbackListener(listener SetCallbackListener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.callbackListeners = append(s.callbackListeners, listener)
+}
+
+func notify(listeners []SetCallbackListener, added, removed []ids.ShortID) {
+	for _, listener := range listeners {
+		for _, vdrID := range removed {
+			listener.OnValidatorRemoved(vdrID)
+		}
+		for _, vdrID := range added {
+			listener.OnValidatorAdded(vdrID)
+		}
+	}
+}
+
 func (s *set) String() string {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -147,3 +147,60 @@ func TestSamplerString(t *testing.T) {
 		t.Fatalf("Got:\n%s\nExpected:\n%s", str, expected)
 	}
 }
+
+type testCallbackListener struct {
+	added, removed ids.ShortSet
+}
+
+func (l *testCallbackListener) OnValidatorAdded(vdrID ids.ShortID)   { l.added.Add(vdrID) }
+func (l *testCallbackListener) OnValidatorRemoved(vdrID ids.ShortID) { l.removed.Add(vdrID) }
+
+func TestSetCallbackListener(t *testing.T) {
+	vdr0 := GenerateRandomValidator(1)
+	vdr1 := GenerateRandomValidator(1)
+	vdr2 := GenerateRandomValidator(1)
+
+	s := NewSet()
+	listener := &testCallbackListener{}
+	s.RegisterCallbackListener(listener)
+
+	s.Add(vdr0)
+	if !listener.added.Contains(vdr0.ID()) {
+		t.Fatalf("Should have been notified of vdr0 joining")
+	}
+
+	// Updating the weight of a validator isn't a membership change
+	listener.added.Clear()
+	s.Add(NewValidator(vdr0.ID(), 2))
+	if listener.added.Len() != 0 || listener.removed.Len() != 0 {
+		t.Fatalf("Shouldn't have been notified of a weight change")
+	}
+
+	s.Set([]Validator{vdr0, vdr1})
+	if listener.added.Len() != 1 || !listener.added.Contains(vdr1.ID()) {
+		t.Fatalf("Should have been notified of only vdr1 joining")
+	}
+	if listener.removed.Len() != 0 {
+		t.Fatalf("Shouldn't have been notified of any validator leaving")
+	}
+
+	listener.added.Clear()
+	s.Set([]Validator{vdr1, vdr2})
+	if listener.added.Len() != 1 || !listener.added.Contains(vdr2.ID()) {
+		t.Fatalf("Should have been notified of only vdr2 joining")
+	}
+	if listener.removed.Len() != 1 || !listener.removed.Contains(vdr0.ID()) {
+		t.Fatalf("Should have been notified of only vdr0 leaving")
+	}
+
+	listener.removed.Clear()
+	s.Remove(vdr0.ID())
+	if listener.removed.Len() != 0 {
+		t.Fatalf("Shouldn't have been notified of a non-member leaving")
+	}
+
+	s.Remove(vdr1.ID())
+	if !listener.removed.Contains(vdr1.ID()) {
+		t.Fatalf("Should have been notified of vdr1 leaving")
+	}
+}
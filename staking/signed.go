@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+// A node signs these messages with its staking key:
+//
+//   - TLS 1.3 handshakes sign 64 spaces, followed by a context string and the
+//     hash of the handshake.
+//   - TLS 1.2 handshakes sign the client's and the server's 32 byte randoms,
+//     followed by the key exchange parameters. The parameters are 36, 69, 101
+//     or 137 bytes, for X25519, P-256, P-384 and P-521, so the message is 100,
+//     133, 165 or 201 bytes. A client's certificate signs every handshake
+//     message before it, which includes the server's certificate, so it's much
+//     longer.
+//   - Liveness probes sign ProbePrefix followed by a SignedNonceLen byte nonce.
+//   - Node ID proofs sign NodeIDProofPrefix followed by a SignedNonceLen byte
+//     nonce.
+//
+// The TLS handshakes start with bytes the peer chooses, so the messages this
+// package defines can't rely on their prefixes alone. Each is a fixed length
+// that no TLS handshake signs, and starts with a prefix that neither a TLS 1.3
+// handshake nor another of these messages starts with. A signature of one of
+// these messages can't be passed off as a signature of any other message.
+// A new message signed with the staking key must keep this true, and be added
+// to the tests.
+const (
+	// SignedNonceLen is the length of the nonce in a message signed with the
+	// staking key
+	SignedNonceLen = 32
+
+	// ProbePrefix starts the message signed to answer a liveness probe
+	ProbePrefix = "\x1AAva Liveness Probe:\n"
+
+	// NodeIDProofPrefix starts the message signed to prove a node ID
+	NodeIDProofPrefix = "\x1AAva Node ID Proof:\n"
+)
+
+// ProbeMessage returns the message signed to answer a liveness probe with
+// [nonce]
+func ProbeMessage(nonce [SignedNonceLen]byte) []byte {
+	return signedMessage(ProbePrefix, nonce)
+}
+
+// NodeIDProofMessage returns the message signed to prove a node ID with
+// [nonce]
+func NodeIDProofMessage(nonce [SignedNonceLen]byte) []byte {
+	return signedMessage(NodeIDProofPrefix, nonce)
+}
+
+func signedMessage(prefix string, nonce [SignedNonceLen]byte) []byte {
+	msg := make([]byte, 0, len(prefix)+SignedNonceLen)
+	msg = append(msg, prefix...)
+	return append(msg, nonce[:]...)
+}
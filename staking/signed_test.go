@@ -0,0 +1,48 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"strings"
+	"testing"
+)
+
+// The shortest message a TLS 1.2 handshake signs, which is a server's X25519
+// key exchange parameters after the client's and server's randoms
+const minTLS12SignedLen = 100
+
+// The first byte of every message a TLS 1.3 handshake signs
+const tls13SignedByte = ' '
+
+func TestSignedMessagesDontCollide(t *testing.T) {
+	nonce := [SignedNonceLen]byte{1, 2, 3}
+	formats := []struct {
+		name   string
+		prefix string
+		msg    []byte
+	}{
+		{"liveness probe", ProbePrefix, ProbeMessage(nonce)},
+		{"node ID proof", NodeIDProofPrefix, NodeIDProofMessage(nonce)},
+	}
+
+	for _, format := range formats {
+		msg := string(format.msg)
+		switch {
+		case !strings.HasPrefix(msg, format.prefix):
+			t.Fatalf("%s doesn't start with its prefix", format.name)
+		case len(msg) != len(format.prefix)+SignedNonceLen:
+			t.Fatalf("%s is %d bytes but should be %d", format.name, len(msg), len(format.prefix)+SignedNonceLen)
+		case len(msg) >= minTLS12SignedLen:
+			t.Fatalf("%s is %d bytes, which a TLS 1.2 handshake could sign", format.name, len(msg))
+		case msg[0] == tls13SignedByte:
+			t.Fatalf("%s starts like a TLS 1.3 handshake", format.name)
+		}
+
+		for _, other := range formats {
+			if other.name != format.name && strings.HasPrefix(msg, other.prefix) {
+				t.Fatalf("%s starts with the prefix of %s", format.name, other.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// KeySize is the size, in bits, of the RSA keys that are generated for
+	// staking
+	KeySize = 4096
+
+	// CertificateValidityYears is how long, in years, a generated staking
+	// certificate is valid for
+	CertificateValidityYears = 1000
+)
+
+var (
+	errNoCertificate = errors.New("no PEM encoded certificate found")
+)
+
+// GenerateStakingKeyCert generates a new RSA key and self-signed certificate
+// and writes them, PEM encoded, to [keyPath] and [certPath]. Existing files are
+// never overwritten.
+func GenerateStakingKeyCert(keyPath, certPath string) (ids.ShortID, error) {
+	for _, path := range []string{keyPath, certPath} {
+		if _, err := os.Stat(path); err == nil {
+			return ids.ShortID{}, fmt.Errorf("refusing to overwrite existing file %s", path)
+		}
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, KeySize)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("couldn't generate rsa key: %w", err)
+	}
+
+	certBytes, err := NewCertificate(key)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+
+	if err := writePEM(keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), 0400); err != nil {
+		return ids.ShortID{}, err
+	}
+	if err := writePEM(certPath, "CERTIFICATE", certBytes, 0444); err != nil {
+		return ids.ShortID{}, err
+	}
+	return NodeID(certBytes)
+}
+
+// NewCertificate returns a new DER encoded, self-signed certificate for the
+// staking key [key]
+func NewCertificate(key *rsa.PrivateKey) ([]byte, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Country:      []string{"US"},
+			Province:     []string{"NY"},
+			Organization: []string{"Avalabs"},
+			CommonName:   "ava",
+		},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.AddDate(CertificateValidityYears, 0, 0),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create certificate: %w", err)
+	}
+	return certBytes, nil
+}
+
+// NodeID returns the ID of the node that uses the DER encoded certificate
+// [certBytes] for staking
+func NodeID(certBytes []byte) (ids.ShortID, error) {
+	return ids.ToShortID(hashing.PubkeyBytesToAddress(certBytes))
+}
+
+// NodeIDFromFile returns the ID of the node that uses the PEM encoded
+// certificate at [certPath] for staking
+func NodeIDFromFile(certPath string) (ids.ShortID, error) {
+	pemBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem reading staking certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return ids.ShortID{}, errNoCertificate
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing staking certificate: %w", err)
+	}
+	return NodeID(cert.Raw)
+}
+
+func writePEM(path, blockType string, bytes []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("couldn't create directory for %s: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("couldn't create %s: %w", path, err)
+	}
+	if err := pem.Encode(file, &pem.Block{Type: blockType, Bytes: bytes}); err != nil {
+		file.Close()
+		return fmt.Errorf("couldn't write %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateStakingKeyCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staking")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "staker.key")
+	certPath := filepath.Join(dir, "staker.crt")
+
+	nodeID, err := GenerateStakingKeyCert(keyPath, certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileID, err := NodeIDFromFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nodeID.Equals(fileID) {
+		t.Fatalf("Generated node ID %s but the certificate has node ID %s", nodeID, fileID)
+	}
+
+	if _, err := GenerateStakingKeyCert(keyPath, certPath); err == nil {
+		t.Fatalf("Should have refused to overwrite the existing key")
+	}
+}
+
+func TestNodeIDFromFileNotPEM(t *testing.T) {
+	file, err := ioutil.TempFile("", "staker.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write([]byte("not a certificate")); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if _, err := NodeIDFromFile(file.Name()); err == nil {
+		t.Fatalf("Should have errored on a malformed certificate")
+	}
+}
@@ -0,0 +1,49 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/staking"
+)
+
+// main generates a new staking identity, or prints the node ID of an existing
+// one, so operators can provision validator keys before starting a node with
+// them.
+//
+// This only generates keys. A node authenticates itself with a single staking
+// identity, and peers and the validator set only know the node ID derived from
+// it, so there is no window in which an old and a new identity are both valid.
+// To retire a key, add a validator with the node ID of a new identity, start a
+// node with the new identity once that validator is current, and only then
+// stop the node with the old one.
+func main() {
+	keyFile := flag.String("staking-tls-key-file", "staker.key", "Path to write the TLS private key to")
+	certFile := flag.String("staking-tls-cert-file", "staker.crt", "Path to write the TLS certificate to")
+	printOnly := flag.Bool("print-node-id", false, "If true, print the node ID of the existing certificate instead of generating a new identity")
+	flag.Parse()
+
+	var (
+		nodeID ids.ShortID
+		err    error
+	)
+	if *printOnly {
+		nodeID, err = staking.NodeIDFromFile(*certFile)
+	} else {
+		nodeID, err = staking.GenerateStakingKeyCert(*keyFile, *certFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	if !*printOnly {
+		fmt.Printf("Wrote staking key to %s and certificate to %s\n", *keyFile, *certFile)
+	}
+	fmt.Printf("NodeID: %s\n", nodeID)
+}
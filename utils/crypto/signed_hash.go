@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// SignedHashPrefix is prepended to every hash that is signed on behalf of an
+// API caller. No transaction starts with it, so a signature of such a hash
+// can't authorize a transaction.
+const SignedHashPrefix = "\x1AAva Signed Hash:\n"
+
+var (
+	errInvalidHashLen = errors.New("invalid hash length")
+)
+
+// DomainSeparatedHash returns the digest that is signed when [hash] is signed
+// on behalf of an API caller in [domain], such as the ID of a chain. A
+// signature made in one domain is never valid in another.
+func DomainSeparatedHash(domain ids.ID, hash []byte) ([]byte, error) {
+	if len(hash) != hashing.HashLen {
+		return nil, errInvalidHashLen
+	}
+	msg := make([]byte, 0, len(SignedHashPrefix)+2*hashing.HashLen)
+	msg = append(msg, SignedHashPrefix...)
+	msg = append(msg, domain.Bytes()...)
+	msg = append(msg, hash...)
+	return hashing.ComputeHash256(msg), nil
+}
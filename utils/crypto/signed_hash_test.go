@@ -0,0 +1,54 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+func TestDomainSeparatedHash(t *testing.T) {
+	f := FactorySECP256K1R{}
+	skIntf, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := skIntf.(*PrivateKeySECP256K1R)
+
+	hash := hashing.ComputeHash256([]byte("oracle price update"))
+	domain := ids.NewID([32]byte{1})
+	otherDomain := ids.NewID([32]byte{2})
+
+	signedHash, err := DomainSeparatedHash(domain, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.SignHash(signedHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pk, err := f.RecoverHashPublicKey(signedHash, sig); err != nil {
+		t.Fatal(err)
+	} else if !pk.Address().Equals(sk.PublicKey().Address()) {
+		t.Fatalf("Signature should be valid in the domain it was made in")
+	}
+
+	otherSignedHash, err := DomainSeparatedHash(otherDomain, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk, err := f.RecoverHashPublicKey(otherSignedHash, sig); err == nil && pk.Address().Equals(sk.PublicKey().Address()) {
+		t.Fatalf("Signature shouldn't be valid in another domain")
+	}
+	if pk, err := f.RecoverHashPublicKey(hash, sig); err == nil && pk.Address().Equals(sk.PublicKey().Address()) {
+		t.Fatalf("Signature shouldn't be valid without the domain separation")
+	}
+
+	if _, err := DomainSeparatedHash(domain, hash[1:]); err == nil {
+		t.Fatalf("Should have errored due to an invalid hash length")
+	}
+}
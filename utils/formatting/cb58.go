@@ -5,67 +5,206 @@ package formatting
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/mr-tron/base58/base58"
 
 	"github.com/ava-labs/gecko/utils/hashing"
 )
 
+const (
+	checksumLen = 4
+
+	// Buffers larger than this aren't pooled, so that formatting one huge
+	// container doesn't keep its memory in use
+	maxPooledLen = 1 << 20 // 1 MiB
+
+	// hexPrefix starts strings in hex encoding. Neither '0' nor 'x' is in the
+	// base-58 alphabet, so it can't start a string in CB58 encoding.
+	hexPrefix = "0x"
+)
+
 var (
 	errMissingQuotes   = errors.New("missing quotes")
 	errMissingChecksum = errors.New("input string is smaller than the checksum size")
 	errBadChecksum     = errors.New("invalid input checksum")
 )
 
-// CB58 formats bytes in checksummed base-58 encoding
-type CB58 struct{ Bytes []byte }
+// Buffers that hold bytes along with their checksum while they're encoded,
+// so that formatting many containers for the API doesn't allocate a buffer
+// for each of them
+var checkedPool = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
 
-// UnmarshalJSON ...
-func (cb58 *CB58) UnmarshalJSON(b []byte) error {
-	str := string(b)
-	if str == "null" {
-		return nil
+// Encoding is a way of formatting bytes as a string
+type Encoding string
+
+const (
+	// CB58Encoding is checksummed base-58. It's the default.
+	CB58Encoding Encoding = "cb58"
+
+	// HexEncoding is checksummed hex with a 0x prefix. It's much faster to
+	// encode and decode than CB58, especially for large containers.
+	HexEncoding Encoding = "hex"
+)
+
+// ParseEncoding returns the encoding named [name]. If [name] is empty, it's
+// CB58Encoding.
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(strings.ToLower(name)) {
+	case "", CB58Encoding:
+		return CB58Encoding, nil
+	case HexEncoding:
+		return HexEncoding, nil
+	default:
+		return "", fmt.Errorf("unknown encoding %q", name)
 	}
+}
 
-	if len(str) < 2 {
-		return errMissingQuotes
+// Encode formats [b] in encoding [e]
+func (e Encoding) Encode(b []byte) string {
+	if e == HexEncoding {
+		return Hex{Bytes: b}.String()
 	}
+	return CB58{Bytes: b}.String()
+}
 
-	lastIndex := len(str) - 1
-	if str[0] != '"' || str[lastIndex] != '"' {
-		return errMissingQuotes
+// CB58 formats bytes in checksummed base-58 encoding. When parsed, it also
+// accepts bytes in checksummed hex encoding.
+type CB58 struct{ Bytes []byte }
+
+// UnmarshalJSON ...
+func (cb58 *CB58) UnmarshalJSON(b []byte) error {
+	str, null, err := unquote(b)
+	if err != nil || null {
+		return err
 	}
-	return cb58.FromString(str[1:lastIndex])
+	return cb58.FromString(str)
 }
 
 // MarshalJSON ...
-func (cb58 CB58) MarshalJSON() ([]byte, error) { return []byte("\"" + cb58.String() + "\""), nil }
+func (cb58 CB58) MarshalJSON() ([]byte, error) { return quote(cb58.String()), nil }
 
 // FromString ...
 func (cb58 *CB58) FromString(str string) error {
-	b, err := base58.Decode(str)
+	var (
+		b   []byte
+		err error
+	)
+	if strings.HasPrefix(str, hexPrefix) {
+		b, err = hex.DecodeString(str[len(hexPrefix):])
+	} else {
+		b, err = base58.Decode(str)
+	}
 	if err != nil {
 		return err
 	}
-	if len(b) < 4 {
-		return errMissingChecksum
+	rawBytes, err := verifyChecksum(b)
+	if err != nil {
+		return err
 	}
+	cb58.Bytes = rawBytes
+	return nil
+}
+
+func (cb58 CB58) String() string {
+	checked := appendChecksum(cb58.Bytes)
+	defer releaseChecked(checked)
+	return base58.Encode(*checked)
+}
 
-	rawBytes := b[:len(b)-4]
-	checksum := b[len(b)-4:]
+// Hex formats bytes in checksummed hex encoding, with a 0x prefix. When parsed,
+// it also accepts bytes in checksummed base-58 encoding.
+type Hex struct{ Bytes []byte }
 
-	if !bytes.Equal(checksum, hashing.Checksum(rawBytes, 4)) {
-		return errBadChecksum
+// UnmarshalJSON ...
+func (h *Hex) UnmarshalJSON(b []byte) error {
+	str, null, err := unquote(b)
+	if err != nil || null {
+		return err
 	}
+	return h.FromString(str)
+}
 
-	cb58.Bytes = rawBytes
+// MarshalJSON ...
+func (h Hex) MarshalJSON() ([]byte, error) { return quote(h.String()), nil }
+
+// FromString ...
+func (h *Hex) FromString(str string) error {
+	cb58 := CB58{}
+	if err := cb58.FromString(str); err != nil {
+		return err
+	}
+	h.Bytes = cb58.Bytes
 	return nil
 }
 
-func (cb58 CB58) String() string {
-	checked := make([]byte, len(cb58.Bytes)+4)
-	copy(checked, cb58.Bytes)
-	copy(checked[len(cb58.Bytes):], hashing.Checksum(cb58.Bytes, 4))
-	return base58.Encode(checked)
+func (h Hex) String() string {
+	checked := appendChecksum(h.Bytes)
+	defer releaseChecked(checked)
+
+	encoded := make([]byte, len(hexPrefix)+hex.EncodedLen(len(*checked)))
+	copy(encoded, hexPrefix)
+	hex.Encode(encoded[len(hexPrefix):], *checked)
+	return string(encoded)
+}
+
+// appendChecksum returns a buffer from [checkedPool] that holds [b] followed
+// by its checksum. The buffer should be returned to the pool once it's used.
+func appendChecksum(b []byte) *[]byte {
+	checked := checkedPool.Get().(*[]byte)
+	checksum := hashing.ComputeHash256Array(b)
+	*checked = append(append((*checked)[:0], b...), checksum[len(checksum)-checksumLen:]...)
+	return checked
+}
+
+// releaseChecked returns [checked] to [checkedPool]
+func releaseChecked(checked *[]byte) {
+	if cap(*checked) <= maxPooledLen {
+		checkedPool.Put(checked)
+	}
+}
+
+// verifyChecksum returns the bytes in [b] that precede its checksum
+func verifyChecksum(b []byte) ([]byte, error) {
+	if len(b) < checksumLen {
+		return nil, errMissingChecksum
+	}
+	rawBytes := b[:len(b)-checksumLen]
+	checksum := hashing.ComputeHash256Array(rawBytes)
+	if !bytes.Equal(b[len(b)-checksumLen:], checksum[len(checksum)-checksumLen:]) {
+		return nil, errBadChecksum
+	}
+	return rawBytes, nil
+}
+
+// quote returns [str] as a JSON string. [str] must not need escaping.
+func quote(str string) []byte {
+	quoted := make([]byte, len(str)+2)
+	quoted[0] = '"'
+	copy(quoted[1:], str)
+	quoted[len(quoted)-1] = '"'
+	return quoted
+}
+
+// unquote returns the string the JSON string [b] holds, which must not need
+// unescaping, or true if [b] is null
+func unquote(b []byte) (string, bool, error) {
+	str := string(b)
+	if str == "null" {
+		return "", true, nil
+	}
+	if len(str) < 2 {
+		return "", false, errMissingQuotes
+	}
+	lastIndex := len(str) - 1
+	if str[0] != '"' || str[lastIndex] != '"' {
+		return "", false, errMissingQuotes
+	}
+	return str[1:lastIndex], false, nil
 }
@@ -5,6 +5,7 @@ package formatting
 
 import (
 	"bytes"
+	"strconv"
 	"testing"
 )
 
@@ -78,3 +79,121 @@ func TestCB58ParseBytesError(t *testing.T) {
 		t.Fatalf("Incorrectly parsed %s", ui)
 	}
 }
+
+func TestHex(t *testing.T) {
+	addr := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 255}
+	result := Hex{addr}.String()
+	expected := "0x00010203040506070809ff" // Followed by the checksum
+	if len(result) != len(expected)+2*checksumLen || result[:len(expected)] != expected {
+		t.Fatalf("Expected %s followed by a checksum, got %s", expected, result)
+	}
+	hexBytes := Hex{}
+	if err := hexBytes.FromString(result); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hexBytes.Bytes, addr) {
+		t.Fatalf("Expected 0x%x, got 0x%x", addr, hexBytes.Bytes)
+	}
+
+	// Hex can be parsed where CB58 is expected, and vice versa
+	cb58 := CB58{}
+	if err := cb58.FromString(result); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cb58.Bytes, addr) {
+		t.Fatalf("Expected 0x%x, got 0x%x", addr, cb58.Bytes)
+	}
+	if err := hexBytes.FromString("1NVSVezva3bAtJesnUj"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hexBytes.Bytes, addr) {
+		t.Fatalf("Expected 0x%x, got 0x%x", addr, hexBytes.Bytes)
+	}
+
+	// The checksum is verified
+	if err := cb58.FromString(result[:len(result)-1] + "0"); err != errBadChecksum {
+		t.Fatalf("should have failed because the checksum is wrong but got %v", err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	addr := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 255}
+	for name, expected := range map[string]string{
+		"":     CB58{addr}.String(),
+		"cb58": CB58{addr}.String(),
+		"HEX":  Hex{addr}.String(),
+	} {
+		encoding, err := ParseEncoding(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := encoding.Encode(addr); result != expected {
+			t.Fatalf("Expected %s, got %s", expected, result)
+		}
+	}
+	if _, err := ParseEncoding("base64"); err == nil {
+		t.Fatal("should have failed to parse an unknown encoding")
+	}
+}
+
+// Base-58 encoding takes time quadratic in the number of bytes, so the sizes
+// are kept small
+var benchmarkSizes = []int{32, 1024, 4096}
+
+func benchmarkBytes(size int) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func BenchmarkCB58Encode(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		cb58 := CB58{Bytes: benchmarkBytes(size)}
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cb58.MarshalJSON()
+			}
+		})
+	}
+}
+
+func BenchmarkCB58Decode(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		str := CB58{Bytes: benchmarkBytes(size)}.String()
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			cb58 := CB58{}
+			for i := 0; i < b.N; i++ {
+				cb58.FromString(str)
+			}
+		})
+	}
+}
+
+func BenchmarkHexEncode(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		h := Hex{Bytes: benchmarkBytes(size)}
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h.MarshalJSON()
+			}
+		})
+	}
+}
+
+func BenchmarkHexDecode(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		str := Hex{Bytes: benchmarkBytes(size)}.String()
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			h := Hex{}
+			for i := 0; i < b.N; i++ {
+				h.FromString(str)
+			}
+		})
+	}
+}
@@ -20,9 +20,10 @@ var (
 )
 
 // NewCodec returns a new json codec that will convert the first character of
-// the method to uppercase
+// the method to uppercase. Errors returned by services are sent with the code
+// of their class.
 func NewCodec() rpc.Codec {
-	return lowercase{json2.NewCodec()}
+	return lowercase{json2.NewCustomCodecWithErrorMapper(rpc.DefaultEncoderSelector, mapError)}
 }
 
 type lowercase struct{ *json2.Codec }
@@ -0,0 +1,20 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"context"
+	"net/http"
+)
+
+// Context returns the context of the request [r] that a service method is
+// serving. The context is cancelled if the client goes away, so long-running
+// methods should stop once it's done. If [r] is nil, as it is when a service
+// method is called directly, the background context is returned.
+func Context(r *http.Request) context.Context {
+	if r == nil {
+		return context.Background()
+	}
+	return r.Context()
+}
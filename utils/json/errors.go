@@ -0,0 +1,107 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"errors"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// The codes of the errors that the JSON-RPC services return. Each code is a
+// class of errors, so clients can handle an error by its class rather than by
+// its message. The codes are in the range that JSON-RPC 2.0 reserves for
+// server errors. An error that isn't classified has code CodeInternal.
+const (
+	// CodeInternal is the code of an error that the client can't fix, such as
+	// a database failure. It's also the code of any unclassified error.
+	CodeInternal json2.ErrorCode = -32000
+
+	// CodeParse is the code of an error caused by an argument that couldn't be
+	// parsed, such as a malformed address or ID
+	CodeParse json2.ErrorCode = -32001
+
+	// CodeNotFound is the code of an error caused by an argument that names
+	// something that doesn't exist, such as an unknown asset or user
+	CodeNotFound json2.ErrorCode = -32002
+
+	// CodeUnauthorized is the code of an error caused by missing or incorrect
+	// credentials, such as a wrong password
+	CodeUnauthorized json2.ErrorCode = -32003
+
+	// CodeInsufficientFunds is the code of an error caused by an account or
+	// set of addresses that can't pay for the requested operation
+	CodeInsufficientFunds json2.ErrorCode = -32004
+
+	// CodeConflict is the code of an error caused by a conflict with existing
+	// state, such as creating a user that already exists
+	CodeConflict json2.ErrorCode = -32005
+)
+
+var errorClasses = map[json2.ErrorCode]string{
+	CodeInternal:          "internal",
+	CodeParse:             "parse error",
+	CodeNotFound:          "not found",
+	CodeUnauthorized:      "unauthorized",
+	CodeInsufficientFunds: "insufficient funds",
+	CodeConflict:          "conflict",
+}
+
+// Error is an error with a class, given by its code
+type Error struct {
+	Code json2.ErrorCode
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error that this error classifies
+func (e *Error) Unwrap() error { return e.Err }
+
+// ErrorData is the machine-readable data of the errors that the JSON-RPC
+// services return
+type ErrorData struct {
+	// Class is the name of the class of the error, such as "not found"
+	Class string `json:"class"`
+}
+
+// ParseError classifies [err] as an argument that couldn't be parsed
+func ParseError(err error) error { return &Error{Code: CodeParse, Err: err} }
+
+// NotFoundError classifies [err] as an argument that names something that
+// doesn't exist
+func NotFoundError(err error) error { return &Error{Code: CodeNotFound, Err: err} }
+
+// UnauthorizedError classifies [err] as missing or incorrect credentials
+func UnauthorizedError(err error) error { return &Error{Code: CodeUnauthorized, Err: err} }
+
+// InsufficientFundsError classifies [err] as a lack of funds
+func InsufficientFundsError(err error) error { return &Error{Code: CodeInsufficientFunds, Err: err} }
+
+// ConflictError classifies [err] as a conflict with existing state
+func ConflictError(err error) error { return &Error{Code: CodeConflict, Err: err} }
+
+// ErrorCode returns the code of the class of [err]. If [err] wraps several
+// classified errors, the outermost class is used.
+func ErrorCode(err error) json2.ErrorCode {
+	classified := (*Error)(nil)
+	if errors.As(err, &classified) {
+		return classified.Code
+	}
+	return CodeInternal
+}
+
+// mapError converts an error returned by a service into the JSON-RPC error
+// sent to the client
+func mapError(err error) error {
+	if jsonErr, ok := err.(*json2.Error); ok {
+		return jsonErr
+	}
+	code := ErrorCode(err)
+	return &json2.Error{
+		Code:    code,
+		Message: err.Error(),
+		Data:    ErrorData{Class: errorClasses[code]},
+	}
+}
@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+var errTest = NotFoundError(errors.New("unknown thing"))
+
+type ErrorService struct{}
+
+type ErrorArgs struct{ Wrap bool }
+
+type ErrorReply struct{}
+
+func (*ErrorService) Fail(_ *http.Request, args *ErrorArgs, _ *ErrorReply) error {
+	if args.Wrap {
+		return fmt.Errorf("couldn't do it: %w", errTest)
+	}
+	return errors.New("unclassified")
+}
+
+func TestErrorCodes(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(NewCodec(), "application/json")
+	if err := server.RegisterService(&ErrorService{}, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(wrap bool) *json2.Error {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","method":"test.fail","params":{"Wrap":%t},"id":1}`, wrap)
+		request := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+
+		response := struct {
+			Error *json2.Error `json:"error"`
+		}{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Error == nil {
+			t.Fatal("should have responded with an error")
+		}
+		return response.Error
+	}
+
+	jsonErr := call(true)
+	if jsonErr.Code != CodeNotFound {
+		t.Fatalf("expected code %d but got %d", CodeNotFound, jsonErr.Code)
+	}
+	if jsonErr.Message != "couldn't do it: unknown thing" {
+		t.Fatalf("unexpected message %q", jsonErr.Message)
+	}
+	if data, ok := jsonErr.Data.(map[string]interface{}); !ok || data["class"] != "not found" {
+		t.Fatalf("unexpected data %v", jsonErr.Data)
+	}
+
+	jsonErr = call(false)
+	if jsonErr.Code != CodeInternal {
+		t.Fatalf("expected code %d but got %d", CodeInternal, jsonErr.Code)
+	}
+	if data, ok := jsonErr.Data.(map[string]interface{}); !ok || data["class"] != "internal" {
+		t.Fatalf("unexpected data %v", jsonErr.Data)
+	}
+
+	if !errors.Is(fmt.Errorf("wrapped: %w", errTest), errTest) {
+		t.Fatal("a classified error should still match itself")
+	}
+}
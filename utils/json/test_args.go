@@ -0,0 +1,122 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// maxFillDepth bounds how deeply nested values are filled, so that recursive
+// types terminate
+const maxFillDepth = 8
+
+var (
+	requestType    = reflect.TypeOf((*http.Request)(nil))
+	rawMessageType = reflect.TypeOf(stdjson.RawMessage{})
+)
+
+// TestArgsRoundTrip checks that the arguments of every API method of
+// [service] are unchanged by marshalling them to JSON and unmarshalling them.
+// Every exported field of the arguments is set first, so that a field whose
+// value is dropped, or zeroed, on the way is caught. The arguments are
+// compared as JSON, as a field hidden by another field with the same JSON name
+// is never marshalled.
+func TestArgsRoundTrip(t *testing.T, service interface{}) {
+	serviceType := reflect.TypeOf(service)
+	for i := 0; i < serviceType.NumMethod(); i++ {
+		method := serviceType.Method(i)
+
+		// API methods are func(*http.Request, *Args, *Reply) error
+		methodType := method.Type
+		if methodType.NumIn() != 4 || methodType.NumOut() != 1 || methodType.In(1) != requestType {
+			continue
+		}
+		argsType := methodType.In(2)
+		if argsType.Kind() != reflect.Ptr || argsType.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		args := reflect.New(argsType.Elem())
+		fill(args.Elem(), 0)
+		b, err := stdjson.Marshal(args.Interface())
+		if err != nil {
+			t.Errorf("couldn't marshal the arguments of %s: %s", method.Name, err)
+			continue
+		}
+		parsed := reflect.New(argsType.Elem())
+		if err := stdjson.Unmarshal(b, parsed.Interface()); err != nil {
+			t.Errorf("couldn't unmarshal the arguments of %s from %s: %s", method.Name, b, err)
+			continue
+		}
+		remarshalled, err := stdjson.Marshal(parsed.Interface())
+		if err != nil {
+			t.Errorf("couldn't marshal the unmarshalled arguments of %s: %s", method.Name, err)
+			continue
+		}
+		if !bytes.Equal(b, remarshalled) {
+			t.Errorf("the arguments of %s changed from %s to %s when they were unmarshalled", method.Name, b, remarshalled)
+		}
+	}
+}
+
+// fill sets [v], and everything it holds, to non-zero values. Interfaces are
+// left nil, as the type of their value can't be unmarshalled.
+func fill(v reflect.Value, depth int) {
+	if depth > maxFillDepth {
+		return
+	}
+	if v.Type() == rawMessageType {
+		v.SetBytes([]byte(`"raw"`))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(0.5)
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fill(v.Field(i), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(v.Index(i), depth+1)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte{1, 2, 3})
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), depth+1)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		key.SetString("key")
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, depth+1)
+		v.SetMapIndex(key, elem)
+	}
+}
@@ -5,6 +5,7 @@ package logging
 
 import (
 	"path"
+	"sync"
 
 	"github.com/ava-labs/gecko/ids"
 )
@@ -14,11 +15,18 @@ type Factory interface {
 	Make() (Logger, error)
 	MakeChain(chainID ids.ID, subdir string) (Logger, error)
 	MakeSubdir(subdir string) (Logger, error)
+
+	// SetLogLevel and SetDisplayLevel change the level of the loggers this
+	// factory made, and of the loggers it will make
+	SetLogLevel(level Level)
+	SetDisplayLevel(level Level)
+
 	Close()
 }
 
 // factory ...
 type factory struct {
+	lock   sync.Mutex
 	config Config
 
 	loggers []Logger
@@ -33,6 +41,9 @@ func NewFactory(config Config) Factory {
 
 // Make ...
 func (f *factory) Make() (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	l, err := New(f.config)
 	if err == nil {
 		f.loggers = append(f.loggers, l)
@@ -42,6 +53,9 @@ func (f *factory) Make() (Logger, error) {
 
 // MakeChain ...
 func (f *factory) MakeChain(chainID ids.ID, subdir string) (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	config := f.config
 	config.MsgPrefix = "SN " + chainID.String()
 	config.Directory = path.Join(config.Directory, "chain", chainID.String(), subdir)
@@ -55,6 +69,9 @@ func (f *factory) MakeChain(chainID ids.ID, subdir string) (Logger, error) {
 
 // MakeSubdir ...
 func (f *factory) MakeSubdir(subdir string) (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	config := f.config
 	config.Directory = path.Join(config.Directory, subdir)
 
@@ -65,8 +82,33 @@ func (f *factory) MakeSubdir(subdir string) (Logger, error) {
 	return log, err
 }
 
+// SetLogLevel ...
+func (f *factory) SetLogLevel(level Level) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config.LogLevel = level
+	for _, log := range f.loggers {
+		log.SetLogLevel(level)
+	}
+}
+
+// SetDisplayLevel ...
+func (f *factory) SetDisplayLevel(level Level) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config.DisplayLevel = level
+	for _, log := range f.loggers {
+		log.SetDisplayLevel(level)
+	}
+}
+
 // Close ...
 func (f *factory) Close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	for _, log := range f.loggers {
 		log.Stop()
 	}
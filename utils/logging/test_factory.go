@@ -19,5 +19,11 @@ func (NoFactory) MakeChain(ids.ID, string) (Logger, error) { return NoLog{}, nil
 // MakeSubdir ...
 func (NoFactory) MakeSubdir(string) (Logger, error) { return NoLog{}, nil }
 
+// SetLogLevel ...
+func (NoFactory) SetLogLevel(Level) {}
+
+// SetDisplayLevel ...
+func (NoFactory) SetDisplayLevel(Level) {}
+
 // Close ...
 func (NoFactory) Close() {}
@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Source of pseudorandom numbers that samplers draw from. Sampling from a
+// source seeded with a known seed is reproducible.
+type Source interface {
+	// Intn returns a number in [0, n). Panics if n <= 0
+	Intn(n int) int
+
+	// Int63n returns a number in [0, n). Panics if n <= 0
+	Int63n(n int64) int64
+
+	// Float64 returns a number in [0, 1)
+	Float64() float64
+}
+
+// NewSource returns a source seeded with [seed]. It's safe for concurrent use.
+func NewSource(seed int64) Source {
+	return &lockedSource{rand: rand.New(rand.NewSource(seed))}
+}
+
+type lockedSource struct {
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+func (s *lockedSource) Intn(n int) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.rand.Intn(n)
+}
+
+func (s *lockedSource) Int63n(n int64) int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.rand.Int63n(n)
+}
+
+func (s *lockedSource) Float64() float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.rand.Float64()
+}
+
+// globalSource draws from math/rand's global source, which is seeded when this
+// package is initialized
+type globalSource struct{}
+
+func (globalSource) Intn(n int) int       { return rand.Intn(n) }
+func (globalSource) Int63n(n int64) int64 { return rand.Int63n(n) }
+func (globalSource) Float64() float64     { return rand.Float64() }
+
+// orGlobal returns [source], or the global source if [source] is nil
+func orGlobal(source Source) Source {
+	if source == nil {
+		return globalSource{}
+	}
+	return source
+}
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"testing"
+)
+
+func TestSourceReproducible(t *testing.T) {
+	weights := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	s0 := &Weighted{Weights: weights, Source: NewSource(1)}
+	s1 := &Weighted{Weights: weights, Source: NewSource(1)}
+	u0 := &Uniform{N: len(weights), Source: NewSource(2)}
+	u1 := &Uniform{N: len(weights), Source: NewSource(2)}
+	for i := 0; i < 10; i++ {
+		s0.Replace()
+		s1.Replace()
+		if subset0, subset1 := Subset(s0, 4), Subset(s1, 4); !equalInts(subset0, subset1) {
+			t.Fatalf("samplers with the same seed sampled %v and %v", subset0, subset1)
+		}
+
+		u0.Replace()
+		u1.Replace()
+		if subset0, subset1 := Subset(u0, 4), Subset(u1, 4); !equalInts(subset0, subset1) {
+			t.Fatalf("samplers with the same seed sampled %v and %v", subset0, subset1)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
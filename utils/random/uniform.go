@@ -16,15 +16,17 @@ func (m *defaultMap) get(key int, def int) int {
 }
 
 // Uniform implements the Sampler interface by using the uniform distribution in
-// the range [0, N). All operations run in O(1) time.
+// the range [0, N). All operations run in O(1) time. Numbers are drawn from
+// [Source], or from the global source if it's nil.
 type Uniform struct {
-	drawn defaultMap
-	N, i  int
+	drawn  defaultMap
+	N, i   int
+	Source Source
 }
 
 // Sample implements the Sampler interface
 func (s *Uniform) Sample() int {
-	r := Rand(s.i, s.N)
+	r := orGlobal(s.Source).Intn(s.N-s.i) + s.i
 
 	ret := s.drawn.get(r, r)
 	s.drawn[r] = s.drawn.get(s.i, s.i)
@@ -35,7 +37,7 @@ func (s *Uniform) Sample() int {
 
 // SampleReplace implements the Sampler interface
 func (s *Uniform) SampleReplace() int {
-	r := Rand(s.i, s.N)
+	r := orGlobal(s.Source).Intn(s.N-s.i) + s.i
 	return s.drawn.get(r, r)
 }
 
@@ -5,7 +5,6 @@ package random
 
 import (
 	"math"
-	"math/rand"
 )
 
 // Weighted implements the Sampler interface by sampling based on a heap
@@ -14,9 +13,11 @@ import (
 // Node weight is defined as the node's given weight along with it's
 // children's recursive weights. Once sampled, a nodes given weight is set to 0.
 //
-// Replacing runs in O(n) time while sampling runs in O(log(n)) time.
+// Replacing runs in O(n) time while sampling runs in O(log(n)) time. Numbers
+// are drawn from [Source], or from the global source if it's nil.
 type Weighted struct {
 	Weights []uint64
+	Source  Source
 
 	// The reason this is separated from Weights, is because it is set to 0
 	// after being sampled.
@@ -45,7 +46,7 @@ func (s *Weighted) Sample() int {
 // not removed.
 func (s *Weighted) SampleReplace() int {
 	s.init()
-	for w, i := rand.Int63n(s.cumWeights[0]), 0; ; {
+	for w, i := orGlobal(s.Source).Int63n(s.cumWeights[0]), 0; ; {
 		w -= s.weights[i]
 		if w < 0 {
 			return i
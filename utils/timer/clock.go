@@ -8,9 +8,14 @@ import (
 )
 
 // Clock acts as a thin wrapper around global time that allows for easy testing
+// and for running time faster than real time
 type Clock struct {
 	faked bool
 	time  time.Time
+
+	// If rate > 1, this clock reads epoch + rate * (real time since epoch)
+	rate  float64
+	epoch time.Time
 }
 
 // Set the time on the clock
@@ -19,12 +24,41 @@ func (c *Clock) Set(time time.Time) { c.faked = true; c.time = time }
 // Sync this clock with global time
 func (c *Clock) Sync() { c.faked = false }
 
+// Accelerate makes this clock run [rate] times faster than real time, starting
+// at [epoch]. Clocks accelerated with the same epoch and rate read the same
+// time. A rate of at most 1 makes this clock run at real time.
+func (c *Clock) Accelerate(epoch time.Time, rate float64) {
+	c.epoch = epoch
+	c.rate = rate
+}
+
+// Rate returns how many times faster than real time this clock runs
+func (c *Clock) Rate() float64 {
+	if c.rate > 1 {
+		return c.rate
+	}
+	return 1
+}
+
 // Time returns the time on this clock
 func (c *Clock) Time() time.Time {
 	if c.faked {
 		return c.time
 	}
-	return time.Now()
+	now := time.Now()
+	if c.rate > 1 {
+		return c.epoch.Add(time.Duration(float64(now.Sub(c.epoch)) * c.rate))
+	}
+	return now
+}
+
+// RealDuration returns how much real time passes while [duration] passes on
+// this clock
+func (c *Clock) RealDuration(duration time.Duration) time.Duration {
+	if c.rate > 1 {
+		return time.Duration(float64(duration) / c.rate)
+	}
+	return duration
 }
 
 // Unix returns the unix time on this clock.
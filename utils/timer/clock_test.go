@@ -0,0 +1,43 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockAccelerate(t *testing.T) {
+	clock := Clock{}
+	if rate := clock.Rate(); rate != 1 {
+		t.Fatalf("a clock should run at real time by default but runs %f times faster", rate)
+	}
+
+	epoch := time.Now().Add(-time.Hour)
+	clock.Accelerate(epoch, 60)
+	if rate := clock.Rate(); rate != 60 {
+		t.Fatalf("expected the clock to run %d times faster but it runs %f times faster", 60, rate)
+	}
+
+	// An hour of real time passed since the epoch, so 60 hours passed on the
+	// clock
+	if now := clock.Time(); now.Before(epoch.Add(60 * time.Hour)) {
+		t.Fatalf("expected the clock to read at least %s but it read %s", epoch.Add(60*time.Hour), now)
+	}
+	if duration := clock.RealDuration(time.Minute); duration != time.Second {
+		t.Fatalf("a minute on the clock should last %s but lasts %s", time.Second, duration)
+	}
+
+	// A faked time takes precedence
+	fake := time.Unix(1000, 0)
+	clock.Set(fake)
+	if now := clock.Time(); !now.Equal(fake) {
+		t.Fatalf("expected the clock to read %s but it read %s", fake, now)
+	}
+
+	clock.Accelerate(time.Time{}, 0.5)
+	if duration := clock.RealDuration(time.Minute); duration != time.Minute {
+		t.Fatalf("a clock can't run slower than real time, but a minute lasts %s", duration)
+	}
+}
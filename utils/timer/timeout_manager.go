@@ -14,9 +14,9 @@ import (
 type timeoutHandler func()
 
 type timeout struct {
-	id      ids.ID
-	handler timeoutHandler
-	timer   time.Time
+	id       ids.ID
+	handler  timeoutHandler
+	deadline time.Time
 }
 
 // TimeoutManager is a manager for timeouts.
@@ -26,6 +26,7 @@ type TimeoutManager struct {
 	timeoutMap  map[[32]byte]*list.Element
 	timeoutList *list.List
 	timer       *Timer // Timer that will fire to clear the timeouts
+	clock       Clock  // Deadlines are measured on this clock
 }
 
 // Initialize is a constructor b/c Golang, in its wisdom, doesn't ... have them?
@@ -36,6 +37,15 @@ func (tm *TimeoutManager) Initialize(duration time.Duration) {
 	tm.timer = NewTimer(tm.Timeout)
 }
 
+// SetClock makes deadlines be measured on [clock], such as a clock that runs
+// faster than real time. This should be called before any timeouts are put.
+func (tm *TimeoutManager) SetClock(clock Clock) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.clock = clock
+}
+
 // Dispatch ...
 func (tm *TimeoutManager) Dispatch() { tm.timer.Dispatch() }
 
@@ -47,7 +57,16 @@ func (tm *TimeoutManager) Put(id ids.ID, handler func()) {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
-	tm.put(id, handler)
+	tm.put(id, tm.duration, handler)
+}
+
+// PutWithDuration puts hash into the hash map with a timeout of [duration]
+// rather than the default duration
+func (tm *TimeoutManager) PutWithDuration(id ids.ID, duration time.Duration, handler func()) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.put(id, duration, handler)
 }
 
 // Remove the item that no longer needs to be there.
@@ -67,7 +86,7 @@ func (tm *TimeoutManager) Timeout() {
 }
 
 func (tm *TimeoutManager) timeout() {
-	timeBound := time.Now().Add(-tm.duration)
+	timeBound := tm.clock.Time()
 	// removeExpiredHead returns false once there is nothing left to remove
 	for {
 		timeout := tm.removeExpiredHead(timeBound)
@@ -83,16 +102,28 @@ func (tm *TimeoutManager) timeout() {
 	tm.registerTimeout()
 }
 
-func (tm *TimeoutManager) put(id ids.ID, handler timeoutHandler) {
+func (tm *TimeoutManager) put(id ids.ID, duration time.Duration, handler timeoutHandler) {
 	tm.remove(id)
 
-	tm.timeoutMap[id.Key()] = tm.timeoutList.PushBack(timeout{
-		id:      id,
-		handler: handler,
-		timer:   time.Now(),
-	})
+	t := timeout{
+		id:       id,
+		handler:  handler,
+		deadline: tm.clock.Time().Add(duration),
+	}
 
-	if tm.timeoutList.Len() == 1 {
+	// The list is ordered by deadline. Most timeouts have the default duration,
+	// so the new timeout almost always belongs at the back.
+	e := tm.timeoutList.Back()
+	for e != nil && e.Value.(timeout).deadline.After(t.deadline) {
+		e = e.Prev()
+	}
+	if e == nil {
+		tm.timeoutMap[id.Key()] = tm.timeoutList.PushFront(t)
+	} else {
+		tm.timeoutMap[id.Key()] = tm.timeoutList.InsertAfter(t, e)
+	}
+
+	if tm.timeoutList.Front().Value.(timeout).id.Equals(id) {
 		tm.registerTimeout()
 	}
 }
@@ -116,8 +147,7 @@ func (tm *TimeoutManager) removeExpiredHead(t time.Time) func() {
 	e := tm.timeoutList.Front()
 	head := e.Value.(timeout)
 
-	headTime := head.timer
-	if headTime.Before(t) {
+	if head.deadline.Before(t) {
 		tm.remove(head.id)
 		return head.handler
 	}
@@ -134,9 +164,5 @@ func (tm *TimeoutManager) registerTimeout() {
 	e := tm.timeoutList.Front()
 	head := e.Value.(timeout)
 
-	timeBound := time.Now().Add(-tm.duration)
-	headTime := head.timer
-	duration := headTime.Sub(timeBound)
-
-	tm.timer.SetTimeoutIn(duration)
+	tm.timer.SetTimeoutIn(tm.clock.RealDuration(head.deadline.Sub(tm.clock.Time())))
 }
@@ -23,3 +23,45 @@ func TestTimeoutManager(t *testing.T) {
 	tm.Put(ids.NewID([32]byte{}), wg.Done)
 	tm.Put(ids.NewID([32]byte{1}), wg.Done)
 }
+
+func TestTimeoutManagerPutWithDuration(t *testing.T) {
+	fired := make(chan int, 2)
+
+	tm := TimeoutManager{}
+	tm.Initialize(time.Hour)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	tm.Put(ids.NewID([32]byte{}), func() { fired <- 0 })
+	tm.PutWithDuration(ids.NewID([32]byte{1}), 2*time.Millisecond, func() { fired <- 1 })
+	tm.PutWithDuration(ids.NewID([32]byte{2}), time.Millisecond, func() { fired <- 2 })
+
+	if first := <-fired; first != 2 {
+		t.Fatalf("Timeout %d fired first, but timeout 2 had the earliest deadline", first)
+	}
+	if second := <-fired; second != 1 {
+		t.Fatalf("Timeout %d fired second, but timeout 1 had the second earliest deadline", second)
+	}
+}
+
+func TestTimeoutManagerAccelerated(t *testing.T) {
+	fired := make(chan struct{}, 1)
+
+	clock := Clock{}
+	clock.Accelerate(time.Now(), 3600)
+
+	tm := TimeoutManager{}
+	tm.Initialize(time.Hour)
+	tm.SetClock(clock)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	// An hour on the clock passes in a second
+	tm.Put(ids.NewID([32]byte{}), func() { fired <- struct{}{} })
+
+	select {
+	case <-fired:
+	case <-time.After(30 * time.Second):
+		t.Fatal("the timeout should have fired after about a second")
+	}
+}
@@ -151,3 +151,92 @@ func TestPackBool(t *testing.T) {
 		t.Fatal("got back wrong values")
 	}
 }
+
+func TestPacker2DByteSlice(t *testing.T) {
+	p := Packer{MaxSize: 1024}
+	p.Pack2DByteSlice([][]byte{{1, 2, 3}, {}, {4}})
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	expected := []byte{
+		0x00, 0x00, 0x00, 0x03,
+		0x00, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x04,
+	}
+	if !bytes.Equal(p.Bytes, expected) {
+		t.Fatalf("Packer.Pack2DByteSlice wrote:\n%v\nExpected:\n%v", p.Bytes, expected)
+	}
+
+	p2 := Packer{Bytes: p.Bytes}
+	byteSlices := p2.Unpack2DByteSlice()
+	if p2.Errored() {
+		t.Fatal(p2.Err)
+	}
+	if len(byteSlices) != 3 || !bytes.Equal(byteSlices[0], []byte{1, 2, 3}) || len(byteSlices[1]) != 0 || !bytes.Equal(byteSlices[2], []byte{4}) {
+		t.Fatalf("Packer.Unpack2DByteSlice returned %v", byteSlices)
+	}
+
+	// The second slice claims to be longer than the remaining bytes
+	p3 := Packer{Bytes: expected[:17]}
+	p3.Unpack2DByteSlice()
+	if !p3.Errored() {
+		t.Fatal("should have failed to unpack a truncated 2D byte slice")
+	}
+}
+
+// packedValues are the functions that pack and unpack each kind of value that
+// may be read from the network
+var packedValues = []struct {
+	pack   func(*Packer, interface{})
+	unpack func(*Packer) interface{}
+}{
+	{TryPackByte, TryUnpackByte},
+	{TryPackShort, TryUnpackShort},
+	{TryPackInt, TryUnpackInt},
+	{TryPackLong, TryUnpackLong},
+	{TryPackHash, TryUnpackHash},
+	{TryPackHashes, TryUnpackHashes},
+	{TryPackAddr, TryUnpackAddr},
+	{TryPackAddrList, TryUnpackAddrList},
+	{TryPackBytes, TryUnpackBytes},
+	{TryPack2DBytes, TryUnpack2DBytes},
+	{TryPackStr, TryUnpackStr},
+	{TryPackIP, TryUnpackIP},
+	{TryPackIPList, TryUnpackIPList},
+}
+
+// FuzzPackerUnpack checks that unpacking arbitrary bytes never panics, and
+// that whatever is unpacked packs back into the bytes it was unpacked from.
+// Inputs that fail are saved in testdata/fuzz, so they're rerun by go test.
+func FuzzPackerUnpack(f *testing.F) {
+	f.Add(uint8(0), []byte{0x01})
+	f.Add(uint8(5), []byte{0x00, 0x00, 0x00, 0x01, 0x00})
+	f.Add(uint8(9), []byte{
+		0x00, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x01, 0x01,
+		0x00, 0x00, 0x00, 0x00,
+	})
+	f.Add(uint8(10), []byte{0x00, 0x02, 'h', 'i'})
+	f.Add(uint8(12), []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7f, 0x00, 0x00, 0x01, 0x25, 0xb3})
+
+	f.Fuzz(func(t *testing.T, kind uint8, b []byte) {
+		value := packedValues[int(kind)%len(packedValues)]
+
+		p := Packer{Bytes: b}
+		unpacked := value.unpack(&p)
+		if p.Errored() {
+			return
+		}
+
+		repacked := Packer{MaxSize: p.Offset}
+		value.pack(&repacked, unpacked)
+		if repacked.Errored() {
+			t.Fatalf("couldn't repack %v: %s", unpacked, repacked.Err)
+		}
+		if !bytes.Equal(repacked.Bytes, b[:p.Offset]) {
+			t.Fatalf("unpacked %v from %v but it packed into %v", unpacked, b[:p.Offset], repacked.Bytes)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+const (
+	maxAliasLen = 16
+)
+
+var (
+	errNoAlias             = errors.New("alias must not be empty")
+	errAliasTooLong        = fmt.Errorf("alias is too long, maximum size is %d", maxAliasLen)
+	errInvalidAliasChar    = errors.New("alias may only contain upper case letters, digits and '-', and must start with a letter")
+	errAliasTaken          = errors.New("alias is already registered")
+	errUnknownAsset        = errors.New("aliased asset is unknown")
+	errNotAnAsset          = errors.New("aliased tx doesn't create an asset")
+	errUnknownAliasOwner   = errors.New("asset wasn't created with the output the alias is signed by")
+	errUnsupportedOwner    = errors.New("alias can only be signed by the owners of a secp256k1fx output")
+	errWrongCreatorSigners = errors.New("alias isn't signed by the owners of the output")
+	errWrongAliasTx        = errors.New("registered alias wasn't registered by an alias tx")
+)
+
+// aliasPrefix separates the IDs that aliases claim from utxo IDs
+var aliasPrefix = []byte("alias:")
+
+// AliasAssetTx is a transaction that registers [Alias] as a short name of the
+// asset [AssetID], so that it can be used in place of the asset's ID.
+//
+// The tx must be signed by the owners of one of the outputs the asset was
+// created with. Their credential follows the credentials of the tx's inputs.
+// Two txs registering the same alias conflict, so at most one of them is
+// accepted. Once an alias is registered, it can't be registered again.
+type AliasAssetTx struct {
+	BaseTx  `serialize:"true"`
+	AssetID ids.ID `serialize:"true"`
+	Alias   string `serialize:"true"`
+
+	// OutputIndex is the index of the output of the asset's creation tx whose
+	// owners sign this tx
+	OutputIndex uint32 `serialize:"true"`
+
+	// Creator are the indices, into the owners of that output, of the signers
+	Creator secp256k1fx.Input `serialize:"true"`
+}
+
+// AssetIDs returns the IDs of the assets this transaction depends on
+func (t *AliasAssetTx) AssetIDs() ids.Set {
+	assets := t.BaseTx.AssetIDs()
+	assets.Add(t.AssetID)
+	return assets
+}
+
+// AliasID returns the ID that is consumed by registering [t.Alias]. Txs that
+// register the same alias consume the same ID, and so conflict.
+func (t *AliasAssetTx) AliasID() ids.ID { return aliasID(t.Alias) }
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *AliasAssetTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case t.AssetID.IsZero():
+		return errNotAnAsset
+	}
+
+	if err := verifyAlias(t.Alias); err != nil {
+		return err
+	}
+	if err := t.Creator.Verify(); err != nil {
+		return err
+	}
+	return t.BaseTx.SyntacticVerify(ctx, c, numFxs)
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *AliasAssetTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	if err := t.BaseTx.SemanticVerify(vm, uTx, creds); err != nil {
+		return err
+	}
+
+	// Registered aliases, as well as the aliases of the genesis assets, can't
+	// be taken
+	if _, err := vm.Lookup(t.Alias); err == nil {
+		return errAliasTaken
+	}
+
+	asset := UniqueTx{
+		vm:   vm,
+		txID: t.AssetID,
+	}
+	if status := asset.Status(); !status.Fetched() {
+		return errUnknownAsset
+	}
+	createAssetTx, ok := asset.t.tx.UnsignedTx.(*CreateAssetTx)
+	if !ok {
+		return errNotAnAsset
+	}
+	owners, err := createAssetTx.outputOwners(t.OutputIndex)
+	if err != nil {
+		return err
+	}
+
+	cred, ok := creds[len(t.Ins)].Cred.(*secp256k1fx.Credential)
+	if !ok {
+		return errUnsupportedOwner
+	}
+	return verifyOwnerSigs(uTx.UnsignedBytes(), owners, &t.Creator, cred)
+}
+
+// outputOwners returns the owners of the output with index [outputIndex] of
+// the UTXOs created by [t]
+func (t *CreateAssetTx) outputOwners(outputIndex uint32) (*secp256k1fx.OutputOwners, error) {
+	utxos := t.UTXOs()
+	if uint32(len(utxos)) <= outputIndex {
+		return nil, errUnknownAliasOwner
+	}
+	switch out := utxos[outputIndex].Out.(type) {
+	case *secp256k1fx.TransferOutput:
+		return &out.OutputOwners, nil
+	case *secp256k1fx.MintOutput:
+		return &out.OutputOwners, nil
+	default:
+		return nil, errUnsupportedOwner
+	}
+}
+
+// verifyOwnerSigs verifies that [cred] holds the signatures, on [unsignedBytes],
+// of the owners of [owners] that [in] specifies
+func verifyOwnerSigs(unsignedBytes []byte, owners *secp256k1fx.OutputOwners, in *secp256k1fx.Input, cred *secp256k1fx.Credential) error {
+	switch {
+	case uint32(len(in.SigIndices)) != owners.Threshold:
+		return errWrongCreatorSigners
+	case len(in.SigIndices) != len(cred.Sigs):
+		return errWrongCreatorSigners
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	hash := hashing.ComputeHash256(unsignedBytes)
+	for i, index := range in.SigIndices {
+		if int(index) >= len(owners.Addrs) {
+			return errWrongCreatorSigners
+		}
+		sig := cred.Sigs[i]
+		pk, err := factory.RecoverHashPublicKey(hash, sig[:])
+		if err != nil {
+			return err
+		}
+		if !owners.Addrs[index].Equals(pk.Address()) {
+			return errWrongCreatorSigners
+		}
+	}
+	return nil
+}
+
+// verifyAlias returns nil iff [alias] may be registered as the alias of an
+// asset. Aliases are too short to be mistaken for an ID.
+func verifyAlias(alias string) error {
+	switch {
+	case len(alias) == 0:
+		return errNoAlias
+	case len(alias) > maxAliasLen:
+		return errAliasTooLong
+	case alias[0] < 'A' || alias[0] > 'Z':
+		return errInvalidAliasChar
+	}
+	for _, r := range alias {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+		default:
+			return errInvalidAliasChar
+		}
+	}
+	return nil
+}
+
+// aliasID returns the ID consumed by registering [alias]
+func aliasID(alias string) ids.ID {
+	return ids.NewID(hashing.ComputeHash256Array(append(append([]byte(nil), aliasPrefix...), alias...)))
+}
+
+// initAssetAliases gives the assets the aliases registered by accepted txs
+func (vm *VM) initAssetAliases() error {
+	txIDs, err := vm.state.AssetAliases()
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, txID := range txIDs {
+		tx, err := vm.state.Tx(txID)
+		if err != nil {
+			return err
+		}
+		aliasTx, ok := tx.UnsignedTx.(*AliasAssetTx)
+		if !ok {
+			return errWrongAliasTx
+		}
+		if err := vm.Alias(aliasTx.AssetID, aliasTx.Alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerAlias gives the asset the alias registered by the accepted [tx]
+func (vm *VM) registerAlias(tx *AliasAssetTx) error {
+	txIDs, err := vm.state.AssetAliases()
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+	if err := vm.state.SetAssetAliases(append(txIDs, tx.ID())); err != nil {
+		return err
+	}
+	return vm.Alias(tx.AssetID, tx.Alias)
+}
@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func newAliasAssetTx(t *testing.T, vm *VM, assetID ids.ID, alias string, key *crypto.PrivateKeySECP256K1R) *UniqueTx {
+	tx := &Tx{UnsignedTx: &AliasAssetTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+		},
+		AssetID: assetID,
+		Alias:   alias,
+		Creator: secp256k1fx.Input{SigIndices: []uint32{0}},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.SignHash(hashing.ComputeHash256(unsignedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+	tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+	}})
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uTx, err := vm.parseTx(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uTx
+}
+
+func TestVerifyAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		err   error
+	}{
+		{"USDT", nil},
+		{"A-1", nil},
+		{"", errNoAlias},
+		{"ABCDEFGHIJKLMNOPQ", errAliasTooLong},
+		{"usdt", errInvalidAliasChar},
+		{"1USD", errInvalidAliasChar},
+		{"US DT", errInvalidAliasChar},
+	}
+	for _, test := range tests {
+		if err := verifyAlias(test.alias); err != test.err {
+			t.Fatalf("expected %q to be verified with %v but got %v", test.alias, test.err, err)
+		}
+	}
+}
+
+func TestAliasAssetTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	// The first genesis asset's outputs are owned by keys[0]
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+	assetID := genesisTx.ID()
+
+	// Only the owners of the asset's outputs may register an alias
+	forged := newAliasAssetTx(t, vm, assetID, "USDT", keys[1])
+	if err := forged.Verify(); err != errWrongCreatorSigners {
+		t.Fatalf("should have failed because the alias isn't signed by the creator but got %v", err)
+	}
+
+	first := newAliasAssetTx(t, vm, assetID, "USDT", keys[0])
+	if err := first.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Txs registering the same alias conflict
+	firstInputs, forgedInputs := first.InputIDs(), forged.InputIDs()
+	if !firstInputs.Contains(aliasID("USDT")) || !forgedInputs.Contains(aliasID("USDT")) {
+		t.Fatal("txs registering the same alias should conflict")
+	}
+
+	first.Accept()
+	if status := first.Status(); status != choices.Accepted {
+		t.Fatalf("should have accepted the tx but it's %s", status)
+	}
+	if aliasedID, err := vm.Lookup("USDT"); err != nil {
+		t.Fatal(err)
+	} else if !aliasedID.Equals(assetID) {
+		t.Fatalf("alias should resolve to %s but resolves to %s", assetID, aliasedID)
+	}
+
+	// Once registered, the alias can't be registered again
+	if err := forged.t.tx.UnsignedTx.SemanticVerify(vm, forged, forged.t.tx.Creds); err != errAliasTaken {
+		t.Fatalf("should have failed because the alias is taken but got %v", err)
+	}
+
+	// Registered aliases are restored when the VM restarts
+	vm.Aliaser.Initialize()
+	if err := vm.initAssetAliases(); err != nil {
+		t.Fatal(err)
+	}
+	if aliasedID, err := vm.Lookup("USDT"); err != nil || !aliasedID.Equals(assetID) {
+		t.Fatalf("alias should have been restored")
+	}
+}
@@ -0,0 +1,88 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	errInvalidBatchSize = errors.New("the chain's batch size must be positive")
+	errInvalidParents   = errors.New("the chain's vertices must have at least 2 parents")
+)
+
+// ChainConfig is this node's configuration of a chain running the AVM, given
+// to the chain as JSON. It overrides the configuration the VM was created
+// with, so that chains on the same node can be tuned independently. The fee
+// that every tx of a chain must pay is set by the chain's genesis, a
+// FeeGenesis, so that all nodes agree on it.
+type ChainConfig struct {
+	// ID, or alias, of the asset that this node orders txs by the fee of. It
+	// must be an asset of the chain that can be transferred. If it is empty,
+	// the VM's FeeAsset is used. If the chain's genesis designates a fee
+	// asset, it must be that asset.
+	FeeAsset string `json:"feeAsset"`
+
+	// The smallest fee that a tx issued to this node must pay. If it is
+	// omitted, the VM's MinFee is used.
+	MinFee *cjson.Uint64 `json:"minFee"`
+
+	// The number of txs that are issued to consensus together. If it is
+	// omitted, the VM's BatchSize is used.
+	BatchSize *cjson.Uint32 `json:"batchSize"`
+
+	// The longest, in milliseconds, a tx may wait to be batched with other txs
+	// before it is issued to consensus. If it is omitted, the VM's
+	// BatchTimeout is used.
+	BatchTimeout *cjson.Uint64 `json:"batchTimeout"`
+
+	// The most parents each vertex this node builds for the chain references.
+	// It must be at least 2. If it is omitted, the node's consensus parameters
+	// are used.
+	Parents *cjson.Uint32 `json:"parents"`
+}
+
+// initChainConfig applies the chain's configuration, [configBytes], to the
+// VM. [configBytes] may be empty if the chain isn't configured.
+func (vm *VM) initChainConfig(configBytes []byte) (ChainConfig, error) {
+	config := ChainConfig{}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &config); err != nil {
+			return config, fmt.Errorf("couldn't parse the chain's configuration: %w", err)
+		}
+	}
+
+	vm.batchTimeout = batchTimeout
+	if vm.BatchTimeout > 0 {
+		vm.batchTimeout = vm.BatchTimeout
+	}
+	if config.BatchTimeout != nil {
+		vm.batchTimeout = time.Duration(*config.BatchTimeout) * time.Millisecond
+	}
+	vm.batchSize = batchSize
+	if vm.BatchSize > 0 {
+		vm.batchSize = vm.BatchSize
+	}
+	if config.BatchSize != nil {
+		if *config.BatchSize == 0 {
+			return config, errInvalidBatchSize
+		}
+		vm.batchSize = int(*config.BatchSize)
+	}
+	if config.Parents != nil {
+		if *config.Parents < 2 {
+			return config, errInvalidParents
+		}
+		vm.parents = int(*config.Parents)
+	}
+	return config, nil
+}
+
+// VertexParents implements the avalanche.ParentsConfigurableVM interface
+func (vm *VM) VertexParents() int { return vm.parents }
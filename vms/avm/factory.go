@@ -4,6 +4,8 @@
 package avm
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -13,7 +15,27 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct {
+	RegossipFrequency    time.Duration
+	MaxRegossipFrequency time.Duration
+	BatchSize            int
+	BatchTimeout         time.Duration
+	FeeAsset             string
+	MinFee               uint64
+	Reindex              bool
+	WatchAllowedHosts    []string
+}
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{
+		RegossipFrequency:    f.RegossipFrequency,
+		MaxRegossipFrequency: f.MaxRegossipFrequency,
+		BatchSize:            f.BatchSize,
+		BatchTimeout:         f.BatchTimeout,
+		FeeAsset:             f.FeeAsset,
+		MinFee:               f.MinFee,
+		Reindex:              f.Reindex,
+		WatchAllowedHosts:    f.WatchAllowedHosts,
+	}
+}
@@ -0,0 +1,235 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errFeeTooLow               = errors.New("tx fee is below this node's minimum")
+	errInsufficientFee         = errors.New("tx burns less than the chain's fee")
+	errUnknownFeeAsset         = errors.New("fee asset isn't an asset of this chain")
+	errFeeAssetNotTransferable = errors.New("fee asset can't be transferred with any of this chain's feature extensions")
+	errNoFeeAsset              = errors.New("a chain's fee asset must be given with a positive fee")
+	errConflictingFeeAsset     = errors.New("the chain's configuration designates a fee asset other than its genesis'")
+)
+
+// verifyFees returns nil iff [g] charges a positive fee in one of its assets
+// that can pay fees
+func (g *FeeGenesis) verifyFees() error {
+	if g.FeeAsset == "" || g.TxFee == 0 {
+		return errNoFeeAsset
+	}
+	for _, genesisTx := range g.Txs {
+		if genesisTx.Alias != g.FeeAsset {
+			continue
+		}
+		if !canPayFees(&genesisTx.CreateAssetTx) {
+			return fmt.Errorf("%w: %s", errFeeAssetNotTransferable, g.FeeAsset)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errUnknownFeeAsset, g.FeeAsset)
+}
+
+// initFeeAsset resolves the asset that fees are paid in. If the chain's
+// genesis, [genesisBytes], designates the asset, every tx must burn the
+// genesis' fee of it. Otherwise, if the chain's configuration designates the
+// asset, it must resolve to an asset that can pay fees, and if it can't be
+// resolved, txs are treated as paying no fee. Either way, the fee only orders
+// the txs issued to this node, and those paying less than MinFee are dropped.
+func (vm *VM) initFeeAsset(config ChainConfig, genesisBytes []byte) error {
+	if config.MinFee != nil {
+		vm.MinFee = uint64(*config.MinFee)
+	}
+
+	genesis, err := vm.parseGenesis(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if genesis.FeeAsset != "" {
+		assetID, err := vm.Lookup(genesis.FeeAsset)
+		if err != nil {
+			return err
+		}
+		if config.FeeAsset != "" {
+			configAssetID, err := vm.lookupAssetID(config.FeeAsset)
+			if err != nil {
+				return err
+			}
+			if !configAssetID.Equals(assetID) {
+				return fmt.Errorf("%w: %s", errConflictingFeeAsset, config.FeeAsset)
+			}
+		}
+		vm.feeAssetID = assetID
+		vm.requiredFee = genesis.TxFee
+		return nil
+	}
+
+	if config.FeeAsset != "" {
+		vm.FeeAsset = config.FeeAsset
+	}
+	if vm.FeeAsset == "" {
+		return nil
+	}
+
+	assetID, err := vm.lookupAssetID(vm.FeeAsset)
+	if err == nil {
+		err = vm.verifyFeeAsset(assetID)
+	}
+	switch {
+	case err != nil && config.FeeAsset != "":
+		return err
+	case err != nil:
+		vm.ctx.Log.Warn("Couldn't use %s as the fee asset: %s. Txs won't be prioritized by fee", vm.FeeAsset, err)
+		return nil
+	}
+	vm.feeAssetID = assetID
+	return nil
+}
+
+// lookupAssetID returns the ID of the asset with the ID, or alias, [asset]
+func (vm *VM) lookupAssetID(asset string) (ids.ID, error) {
+	if assetID, err := vm.Lookup(asset); err == nil {
+		return assetID, nil
+	}
+	assetID, err := ids.FromString(asset)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("%w: %s", errUnknownFeeAsset, asset)
+	}
+	return assetID, nil
+}
+
+// verifyFeeAsset returns nil iff fees can be paid in [assetID]. The asset must
+// have been created on this chain with an output that a feature extension can
+// transfer an amount of, or that can mint such outputs, as fees are paid by
+// burning an amount of the asset.
+func (vm *VM) verifyFeeAsset(assetID ids.ID) error {
+	tx, err := vm.state.Tx(assetID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errUnknownFeeAsset, assetID)
+	}
+	createAssetTx, ok := tx.UnsignedTx.(*CreateAssetTx)
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownFeeAsset, assetID)
+	}
+	if !canPayFees(createAssetTx) {
+		return fmt.Errorf("%w: %s", errFeeAssetNotTransferable, assetID)
+	}
+	return nil
+}
+
+// canPayFees returns true iff the asset [tx] creates has an output that a
+// feature extension can transfer an amount of, or that can mint such outputs
+func canPayFees(tx *CreateAssetTx) bool {
+	for _, state := range tx.States {
+		for _, out := range state.Outs {
+			switch out.(type) {
+			case FxTransferable, *secp256k1fx.MintOutput:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// txFee returns the amount of the fee asset that [tx] burns
+func (vm *VM) txFee(tx snowstorm.Tx) uint64 {
+	uTx, ok := tx.(*UniqueTx)
+	if !ok || vm.feeAssetID.IsZero() {
+		return 0
+	}
+	uTx.refresh()
+	if uTx.t.tx == nil {
+		return 0
+	}
+	return burned(uTx.t.tx.UnsignedTx, vm.feeAssetID)
+}
+
+// burned returns the amount of [assetID] that [tx] burns. That is, the amount
+// of [assetID] it consumes but doesn't produce.
+func burned(tx UnsignedTx, assetID ids.ID) uint64 {
+	// Amounts can't overflow, as the tx passed syntactic verification
+	consumed, produced := uint64(0), uint64(0)
+	for _, in := range tx.Inputs() {
+		if in.AssetID().Equals(assetID) {
+			consumed += in.Input().Amount()
+		}
+	}
+	for _, out := range tx.Outputs() {
+		if out.AssetID().Equals(assetID) {
+			produced += out.Output().Amount()
+		}
+	}
+	if produced >= consumed {
+		return 0
+	}
+	return consumed - produced
+}
+
+// verifyBurn returns an error if [tx] burns less than the fee of the chain's
+// genesis
+func (vm *VM) verifyBurn(tx UnsignedTx) error {
+	if vm.requiredFee == 0 {
+		return nil
+	}
+	if fee := burned(tx, vm.feeAssetID); fee < vm.requiredFee {
+		return fmt.Errorf("%w: tx burns %d but %d is required", errInsufficientFee, fee, vm.requiredFee)
+	}
+	return nil
+}
+
+// verifyFee returns an error if [tx] pays less than this node's minimum fee
+func (vm *VM) verifyFee(tx snowstorm.Tx) error {
+	if fee := vm.txFee(tx); fee < vm.MinFee {
+		return fmt.Errorf("%w: tx burns %d but %d is required", errFeeTooLow, fee, vm.MinFee)
+	}
+	return nil
+}
+
+// prioritizeTxs orders [txs] so that txs paying higher fees are issued first.
+// Txs paying the same fee keep their order. A tx that depends on another of
+// [txs] is never ordered before it, so a high fee tx pulls the txs it depends
+// on forward with it.
+func (vm *VM) prioritizeTxs(txs []snowstorm.Tx) []snowstorm.Tx {
+	byFee := make([]snowstorm.Tx, len(txs))
+	copy(byFee, txs)
+	fees := make(map[[32]byte]uint64, len(txs))
+	pending := make(map[[32]byte]snowstorm.Tx, len(txs))
+	for _, tx := range txs {
+		key := tx.ID().Key()
+		fees[key] = vm.txFee(tx)
+		pending[key] = tx
+	}
+	sort.SliceStable(byFee, func(i, j int) bool {
+		return fees[byFee[i].ID().Key()] > fees[byFee[j].ID().Key()]
+	})
+
+	ordered := make([]snowstorm.Tx, 0, len(txs))
+	var add func(tx snowstorm.Tx)
+	add = func(tx snowstorm.Tx) {
+		key := tx.ID().Key()
+		if _, ok := pending[key]; !ok {
+			return
+		}
+		delete(pending, key)
+		for _, dep := range tx.Dependencies() {
+			if depTx, ok := pending[dep.ID().Key()]; ok {
+				add(depTx)
+			}
+		}
+		ordered = append(ordered, tx)
+	}
+	for _, tx := range byFee {
+		add(tx)
+	}
+	return ordered
+}
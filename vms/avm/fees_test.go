@@ -0,0 +1,346 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	cjson "github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestIssueTxFees(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{
+		BatchSize:    10,
+		BatchTimeout: time.Minute,
+		FeeAsset:     genesisTx.ID().String(),
+		MinFee:       10,
+	}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Returns a tx that spends [amount] from the UTXO at [outputIndex] of
+	// [txID] and burns [fee] of it
+	newTx := func(txID ids.ID, outputIndex uint32, amount, fee uint64) *Tx {
+		tx := &Tx{UnsignedTx: &BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Outs: []*TransferableOutput{
+				&TransferableOutput{
+					Asset: Asset{ID: genesisTx.ID()},
+					Out: &secp256k1fx.TransferOutput{
+						Amt: amount - fee,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+						},
+					},
+				},
+			},
+			Ins: []*TransferableInput{
+				&TransferableInput{
+					UTXOID: UTXOID{
+						TxID:        txID,
+						OutputIndex: outputIndex,
+					},
+					Asset: Asset{ID: genesisTx.ID()},
+					In: &secp256k1fx.TransferInput{
+						Amt: amount,
+						Input: secp256k1fx.Input{
+							SigIndices: []uint32{0},
+						},
+					},
+				},
+			},
+		}}
+		unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := keys[0].Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+			},
+		})
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Initialize(b)
+		return tx
+	}
+
+	if _, err := vm.IssueTx(newTx(genesisTx.ID(), 0, 50000, 9).Bytes()); !errors.Is(err, errFeeTooLow) {
+		t.Fatalf("should have failed with %s but got %v", errFeeTooLow, err)
+	}
+
+	lowFeeTx := newTx(genesisTx.ID(), 0, 50000, 10)
+	highFeeTx := newTx(genesisTx.ID(), 1, 50000, 1000)
+	childTx := newTx(lowFeeTx.ID(), 0, 50000-10, 100)
+	for _, tx := range []*Tx{lowFeeTx, highFeeTx, childTx} {
+		if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The highest fee tx is issued first. The child tx pays more than its
+	// parent, but can't be issued before it.
+	txs := vm.PendingTxs()
+	if len(txs) != 3 {
+		t.Fatalf("Should have returned %d tx(s)", 3)
+	}
+	for i, expected := range []*Tx{highFeeTx, lowFeeTx, childTx} {
+		if !txs[i].ID().Equals(expected.ID()) {
+			t.Fatalf("tx %d should have been %s but was %s", i, expected.ID(), txs[i].ID())
+		}
+	}
+	if fee := vm.txFee(txs[0]); fee != 1000 {
+		t.Fatalf("fee should have been %d but was %d", 1000, fee)
+	}
+}
+
+func TestChainConfigFeeAsset(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+	defer func() { ctx.Config = nil }()
+
+	// initialize returns a VM whose fee asset is [feeAsset], unless it's
+	// overridden by the chain's configuration [config]
+	initialize := func(feeAsset, config string) (*VM, error) {
+		ctx.Config = []byte(config)
+		vm := &VM{
+			FeeAsset: feeAsset,
+			MinFee:   10,
+		}
+		err := vm.Initialize(
+			ctx,
+			memdb.New(),
+			genesisBytes,
+			make(chan common.Message, 1),
+			[]*common.Fx{&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			}},
+		)
+		if err == nil {
+			vm.Shutdown()
+		}
+		return vm, err
+	}
+
+	// The chain pays fees in its own asset
+	vm, err := initialize("AVA", `{"feeAsset":"asset2","minFee":"5"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID, err := vm.Lookup("asset2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vm.feeAssetID.Equals(assetID) {
+		t.Fatalf("fees should have been paid in %s but are paid in %s", assetID, vm.feeAssetID)
+	}
+	if vm.MinFee != 5 {
+		t.Fatalf("the minimum fee should have been %d but was %d", 5, vm.MinFee)
+	}
+
+	// A chain that isn't configured keeps the VM's fee asset, and pays no fees
+	// if the chain doesn't have it
+	vm, err = initialize("AVA", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vm.feeAssetID.IsZero() || vm.MinFee != 10 {
+		t.Fatalf("shouldn't have paid fees in an asset the chain doesn't have")
+	}
+
+	// The asset a chain is configured to pay fees in must be an asset of the
+	// chain
+	if _, err := initialize("", `{"feeAsset":"AVA"}`); !errors.Is(err, errUnknownFeeAsset) {
+		t.Fatalf("should have failed with %s but got %v", errUnknownFeeAsset, err)
+	}
+	if _, err := initialize("", `{"feeAsset":`); err == nil {
+		t.Fatalf("shouldn't have parsed an invalid configuration")
+	}
+}
+
+// buildFeeGenesisTest returns the genesis of a chain whose txs burn [txFee] of
+// the asset aliased [feeAsset]
+func buildFeeGenesisTest(feeAsset string, txFee uint64) ([]byte, error) {
+	ss := StaticService{}
+	addr0 := keys[0].PublicKey().Address()
+
+	args := BuildGenesisArgs{
+		GenesisData: map[string]AssetDefinition{
+			"asset1": AssetDefinition{
+				Name:   "myFixedCapAsset",
+				Symbol: "MFCA",
+				InitialState: map[string][]interface{}{
+					"fixedCap": []interface{}{
+						Holder{
+							Amount:  50000,
+							Address: addr0.String(),
+						},
+					},
+				},
+			},
+			"asset2": AssetDefinition{
+				Name:   "myOtherFixedCapAsset",
+				Symbol: "MOFCA",
+				InitialState: map[string][]interface{}{
+					"fixedCap": []interface{}{
+						Holder{
+							Amount:  50000,
+							Address: addr0.String(),
+						},
+					},
+				},
+			},
+		},
+		FeeAsset: feeAsset,
+		TxFee:    cjson.Uint64(txFee),
+	}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Bytes.Bytes, nil
+}
+
+func TestGenesisFee(t *testing.T) {
+	if _, err := buildFeeGenesisTest("asset3", 10); !errors.Is(err, errUnknownFeeAsset) {
+		t.Fatalf("should have failed with %s but got %v", errUnknownFeeAsset, err)
+	}
+	if _, err := buildFeeGenesisTest("asset1", 0); err != errNoFeeAsset {
+		t.Fatalf("should have failed with %s but got %v", errNoFeeAsset, err)
+	}
+	genesisBytes, err := buildFeeGenesisTest("asset1", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+	defer func() { ctx.Config = nil }()
+
+	// initialize returns a VM of the chain configured with [config]
+	initialize := func(genesisBytes []byte, config string) (*VM, error) {
+		ctx.Config = []byte(config)
+		vm := &VM{
+			BatchSize:    10,
+			BatchTimeout: time.Minute,
+		}
+		err := vm.Initialize(
+			ctx,
+			memdb.New(),
+			genesisBytes,
+			make(chan common.Message, 1),
+			[]*common.Fx{&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			}},
+		)
+		return vm, err
+	}
+
+	// A chain with the legacy genesis charges no fee
+	vm, err := initialize(BuildGenesisTest(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Shutdown()
+	if vm.requiredFee != 0 {
+		t.Fatalf("the legacy genesis shouldn't have charged a fee")
+	}
+
+	// The configured fee asset must be the genesis' fee asset
+	vm, err = initialize(genesisBytes, `{"feeAsset":"asset1"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Shutdown()
+	if _, err := initialize(genesisBytes, `{"feeAsset":"asset2"}`); !errors.Is(err, errConflictingFeeAsset) {
+		t.Fatalf("should have failed with %s but got %v", errConflictingFeeAsset, err)
+	}
+
+	vm, err = initialize(genesisBytes, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+	assetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vm.feeAssetID.Equals(assetID) || vm.requiredFee != 10 {
+		t.Fatalf("should have charged a fee of 10 of %s but charged %d of %s", assetID, vm.requiredFee, vm.feeAssetID)
+	}
+
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := vm.GetUTXOs(context.Background(), addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc := secp256k1fx.NewKeychain()
+	kc.Add(keys[0])
+
+	// Returns the bytes of a tx that sends 100 of the fee asset and burns
+	// [fee] of it
+	newTx := func(fee uint64) []byte {
+		tx, signers, err := NewSendTx(networkID, chainID, vm.codec, utxos, kc, assetID, 100, ids.NewShortID([20]byte{1}), addr, assetID, fee, vm.clock.Unix())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignSECP256K1Fx(vm.codec, signers); err != nil {
+			t.Fatal(err)
+		}
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	if _, err := vm.IssueTx(newTx(9)); !errors.Is(err, errInsufficientFee) {
+		t.Fatalf("should have failed with %s but got %v", errInsufficientFee, err)
+	}
+	if _, err := vm.IssueTx(newTx(10)); err != nil {
+		t.Fatal(err)
+	}
+}
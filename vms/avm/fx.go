@@ -32,6 +32,36 @@ type Fx interface {
 	VerifyOperation(tx interface{}, utxos, ins, creds, outs []interface{}) error
 }
 
+// OperationFx is the interface a feature extension must implement to define
+// FxOperations, which are carried by UTXOOperationTxs.
+type OperationFx interface {
+	Fx
+
+	// InitializeOperations registers this feature extension's FxOperations. It
+	// is called after the types of the VM and of every feature extension are
+	// registered, so that defining FxOperations doesn't change the type IDs of
+	// the transactions that were already serialized.
+	InitializeOperations(vm interface{}) error
+
+	// VerifyFxOperation verifies that the specified transaction can perform
+	// the operation [op], which consumes the provided utxos. [creds] are the
+	// credentials of the utxos, in the same order. If the transaction can't
+	// perform the operation, a non-nil error should be returned.
+	VerifyFxOperation(tx, op interface{}, creds, utxos []interface{}) error
+}
+
+// FxOperation is the interface a feature extension must provide to define an
+// operation on the UTXOs of its assets. An operation consumes the UTXOs its
+// UTXOOperation references, and produces its outputs. Feature extensions add
+// new kinds of operations by registering new FxOperations, rather than the AVM
+// adding new transaction types.
+type FxOperation interface {
+	verify.Verifiable
+
+	// Outs returns the outputs this operation produces
+	Outs() []verify.Verifiable
+}
+
 // FxTransferable is the interface a feature extension must provide to transfer
 // value between features extensions.
 type FxTransferable interface {
@@ -35,3 +35,13 @@ type GenesisAsset struct {
 	Alias         string `serialize:"true"`
 	CreateAssetTx `serialize:"true"`
 }
+
+// FeeGenesis is the genesis data of a chain whose txs pay a fee. Every tx must
+// burn at least [TxFee] of the genesis asset with the alias [FeeAsset], which
+// must be able to be transferred. Genesis data without the fee fields is a
+// Genesis, and its txs don't pay fees.
+type FeeGenesis struct {
+	Genesis  `serialize:"true"`
+	FeeAsset string `serialize:"true"`
+	TxFee    uint64 `serialize:"true"`
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+)
+
+// consumeInputs marks the inputs of [tx] as consumed by a pending tx. If an
+// input is already consumed by a different pending tx, an error is returned
+// and none of the inputs of [tx] are marked.
+func (vm *VM) consumeInputs(tx snowstorm.Tx) error {
+	txID := tx.ID()
+	inputs := tx.InputIDs().List()
+	for _, inputID := range inputs {
+		if consumerID, exists := vm.consumed[inputID.Key()]; exists && !consumerID.Equals(txID) {
+			return fmt.Errorf("input %s is already spent by pending tx %s", inputID, consumerID)
+		}
+	}
+	for _, inputID := range inputs {
+		vm.consumed[inputID.Key()] = txID
+	}
+	return nil
+}
+
+// releaseInputs unmarks the inputs consumed by [tx], so that they may be spent
+// by another tx once [tx] has been decided
+func (vm *VM) releaseInputs(tx snowstorm.Tx) {
+	txID := tx.ID()
+	for _, inputID := range tx.InputIDs().List() {
+		key := inputID.Key()
+		if consumerID, exists := vm.consumed[key]; exists && consumerID.Equals(txID) {
+			delete(vm.consumed, key)
+		}
+	}
+}
+
+// DroppedTx implements the avalanche.DroppedTxsVM interface. A tx the engine
+// dropped will never be decided, so the inputs it consumed are released and
+// it isn't re-issued when this node restarts.
+func (vm *VM) DroppedTx(tx snowstorm.Tx) {
+	txID := tx.ID()
+	vm.ctx.Log.Debug("Tx %s was dropped instead of being issued to consensus", txID)
+
+	vm.untrackRegossip(txID)
+	vm.releaseInputs(tx)
+	if err := vm.removePendingTx(txID); err != nil {
+		vm.ctx.Log.Error("Failed to remove pending tx %s due to %s", txID, err)
+		return
+	}
+	if err := vm.db.Commit(); err != nil {
+		vm.ctx.Log.Error("Failed to commit dropping tx %s due to %s", txID, err)
+	}
+}
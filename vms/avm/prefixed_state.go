@@ -8,6 +8,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
 )
 
 const (
@@ -16,10 +17,18 @@ const (
 	txStatusID
 	fundsID
 	dbInitializedID
+	pendingTxsID
+	supplyID
+	firstSeenID
+	lastActiveID
+	assetAliasesID
+	acceptedAtID
 )
 
 var (
 	dbInitialized = ids.Empty.Prefix(dbInitializedID)
+	pendingTxs    = ids.Empty.Prefix(pendingTxsID)
+	assetAliases  = ids.Empty.Prefix(assetAliasesID)
 )
 
 // prefixedState wraps a state object. By prefixing the state, there will be no
@@ -27,8 +36,8 @@ var (
 type prefixedState struct {
 	state *state
 
-	tx, utxo, txStatus, funds cache.Cacher
-	uniqueTx                  cache.Deduplicator
+	tx, utxo, txStatus, acceptedAt, funds, supply, firstSeen, lastActive cache.Cacher
+	uniqueTx                                                             cache.Deduplicator
 }
 
 // UniqueTx de-duplicates the transaction.
@@ -64,6 +73,16 @@ func (s *prefixedState) SetStatus(id ids.ID, status choices.Status) error {
 	return s.state.SetStatus(s.uniqueID(id, txStatusID, s.txStatus), status)
 }
 
+// AcceptedAt returns the unix time at which the tx with ID [id] was accepted
+func (s *prefixedState) AcceptedAt(id ids.ID) (uint64, error) {
+	return s.state.Uint64(s.uniqueID(id, acceptedAtID, s.acceptedAt))
+}
+
+// SetAcceptedAt saves the unix time at which the tx with ID [id] was accepted
+func (s *prefixedState) SetAcceptedAt(id ids.ID, timestamp uint64) error {
+	return s.state.SetUint64(s.uniqueID(id, acceptedAtID, s.acceptedAt), timestamp)
+}
+
 // DBInitialized returns the status of this database. If the database is
 // uninitialized, the status will be unknown.
 func (s *prefixedState) DBInitialized() (choices.Status, error) { return s.state.Status(dbInitialized) }
@@ -73,6 +92,26 @@ func (s *prefixedState) SetDBInitialized(status choices.Status) error {
 	return s.state.SetStatus(dbInitialized, status)
 }
 
+// PendingTxs returns the IDs of the transactions that were issued to this node
+// and haven't been decided yet.
+func (s *prefixedState) PendingTxs() ([]ids.ID, error) { return s.state.IDs(pendingTxs) }
+
+// SetPendingTxs saves the IDs of the transactions that were issued to this
+// node and haven't been decided yet.
+func (s *prefixedState) SetPendingTxs(idSlice []ids.ID) error {
+	return s.state.SetIDs(pendingTxs, idSlice)
+}
+
+// AssetAliases returns the IDs of the accepted txs that registered an alias of
+// an asset
+func (s *prefixedState) AssetAliases() ([]ids.ID, error) { return s.state.IDs(assetAliases) }
+
+// SetAssetAliases saves the IDs of the accepted txs that registered an alias
+// of an asset
+func (s *prefixedState) SetAssetAliases(idSlice []ids.ID) error {
+	return s.state.SetIDs(assetAliases, idSlice)
+}
+
 // Funds returns the mapping from the 32 byte representation of an address to a
 // list of utxo IDs that reference the address.
 func (s *prefixedState) Funds(id ids.ID) ([]ids.ID, error) {
@@ -84,6 +123,40 @@ func (s *prefixedState) SetFunds(id ids.ID, idSlice []ids.ID) error {
 	return s.state.SetIDs(s.uniqueID(id, fundsID, s.funds), idSlice)
 }
 
+// Supply returns the amount of the asset [assetID] that is held in UTXOs
+func (s *prefixedState) Supply(assetID ids.ID) (uint64, error) {
+	return s.state.Uint64(s.uniqueID(assetID, supplyID, s.supply))
+}
+
+// SetSupply saves the amount of the asset [assetID] that is held in UTXOs
+func (s *prefixedState) SetSupply(assetID ids.ID, supply uint64) error {
+	return s.state.SetUint64(s.uniqueID(assetID, supplyID, s.supply), supply)
+}
+
+// FirstSeen returns the unix time at which an accepted tx first spent from or
+// paid to the address with ID [addrID]
+func (s *prefixedState) FirstSeen(addrID ids.ID) (uint64, error) {
+	return s.state.Uint64(s.uniqueID(addrID, firstSeenID, s.firstSeen))
+}
+
+// SetFirstSeen saves the unix time at which an accepted tx first spent from or
+// paid to the address with ID [addrID]
+func (s *prefixedState) SetFirstSeen(addrID ids.ID, timestamp uint64) error {
+	return s.state.SetUint64(s.uniqueID(addrID, firstSeenID, s.firstSeen), timestamp)
+}
+
+// LastActive returns the unix time at which an accepted tx last spent from or
+// paid to the address with ID [addrID]
+func (s *prefixedState) LastActive(addrID ids.ID) (uint64, error) {
+	return s.state.Uint64(s.uniqueID(addrID, lastActiveID, s.lastActive))
+}
+
+// SetLastActive saves the unix time at which an accepted tx last spent from or
+// paid to the address with ID [addrID]
+func (s *prefixedState) SetLastActive(addrID ids.ID, timestamp uint64) error {
+	return s.state.SetUint64(s.uniqueID(addrID, lastActiveID, s.lastActive), timestamp)
+}
+
 func (s *prefixedState) uniqueID(id ids.ID, prefix uint64, cacher cache.Cacher) ids.ID {
 	if cachedIDIntf, found := cacher.Get(id); found {
 		return cachedIDIntf.(ids.ID)
@@ -102,6 +175,9 @@ func (s *prefixedState) SpendUTXO(utxoID ids.ID) error {
 	if err := s.SetUTXO(utxoID, nil); err != nil {
 		return err
 	}
+	if err := s.removeSupply(utxo); err != nil {
+		return err
+	}
 
 	addressable, ok := utxo.Out.(FxAddressable)
 	if !ok {
@@ -131,6 +207,9 @@ func (s *prefixedState) FundUTXO(utxo *UTXO) error {
 	if err := s.SetUTXO(utxoID, utxo); err != nil {
 		return err
 	}
+	if err := s.addSupply(utxo); err != nil {
+		return err
+	}
 
 	addressable, ok := utxo.Out.(FxAddressable)
 	if !ok {
@@ -153,3 +232,48 @@ func (s *prefixedState) addUTXO(addrs [][]byte, utxoID ids.ID) error {
 	}
 	return nil
 }
+
+func (s *prefixedState) addSupply(utxo *UTXO) error {
+	transferable, ok := utxo.Out.(FxTransferable)
+	if !ok {
+		return nil
+	}
+	assetID := utxo.AssetID()
+	supply, _ := s.Supply(assetID)
+	newSupply, err := math.Add64(supply, transferable.Amount())
+	if err != nil {
+		return err
+	}
+	return s.SetSupply(assetID, newSupply)
+}
+
+func (s *prefixedState) removeSupply(utxo *UTXO) error {
+	transferable, ok := utxo.Out.(FxTransferable)
+	if !ok {
+		return nil
+	}
+	assetID := utxo.AssetID()
+	supply, _ := s.Supply(assetID)
+	newSupply, err := math.Sub64(supply, transferable.Amount())
+	if err != nil {
+		return err
+	}
+	return s.SetSupply(assetID, newSupply)
+}
+
+// markActive records that the addresses [addrs] were active at unix time
+// [timestamp]
+func (s *prefixedState) markActive(addrs [][]byte, timestamp uint64) error {
+	for _, addr := range addrs {
+		addrID := ids.NewID(hashing.ComputeHash256Array(addr))
+		if firstSeen, _ := s.FirstSeen(addrID); firstSeen == 0 {
+			if err := s.SetFirstSeen(addrID, timestamp); err != nil {
+				return err
+			}
+		}
+		if err := s.SetLastActive(addrID, timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// regossipTx is a locally issued transaction that hasn't been decided yet
+type regossipTx struct {
+	tx snowstorm.Tx
+
+	// next is the time this tx should be re-gossiped at if it is still
+	// undecided
+	next time.Time
+
+	// delay is the amount of time to wait after [next] before re-gossiping
+	// this tx again
+	delay time.Duration
+}
+
+func (vm *VM) initRegossip() {
+	vm.regossip = make(map[[32]byte]*regossipTx)
+
+	vm.numRegossiped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: vm.ctx.Namespace,
+			Name:      "avm_regossiped_txs",
+			Help:      "Number of times locally issued txs were re-gossiped",
+		})
+	if vm.ctx.Metrics != nil {
+		if err := vm.ctx.Metrics.Register(vm.numRegossiped); err != nil {
+			vm.ctx.Log.Error("Failed to register avm_regossiped_txs statistics due to %s", err)
+		}
+	}
+
+	vm.regossipTimer = timer.NewTimer(func() {
+		vm.ctx.Lock.Lock()
+		defer vm.ctx.Lock.Unlock()
+
+		vm.RegossipTxs()
+	})
+	go vm.ctx.Log.RecoverAndPanic(vm.regossipTimer.Dispatch)
+}
+
+// Track [tx] so that it is re-gossiped if it remains undecided for longer
+// than RegossipFrequency
+func (vm *VM) trackRegossip(tx snowstorm.Tx) {
+	if vm.RegossipFrequency <= 0 {
+		return // Re-gossiping is disabled
+	}
+	vm.regossip[tx.ID().Key()] = &regossipTx{
+		tx:    tx,
+		next:  vm.clock.Time().Add(vm.RegossipFrequency),
+		delay: vm.RegossipFrequency,
+	}
+	vm.resetRegossipTimer()
+}
+
+// Stop re-gossiping the tx with ID [txID]
+func (vm *VM) untrackRegossip(txID ids.ID) { delete(vm.regossip, txID.Key()) }
+
+// Schedule the regossip timer to fire when the next tx should be re-gossiped
+func (vm *VM) resetRegossipTimer() {
+	if len(vm.regossip) == 0 {
+		vm.regossipTimer.Cancel()
+		return
+	}
+
+	next := time.Time{}
+	for _, rtx := range vm.regossip {
+		if next.IsZero() || rtx.next.Before(next) {
+			next = rtx.next
+		}
+	}
+	vm.regossipTimer.SetTimeoutIn(next.Sub(vm.clock.Time()))
+}
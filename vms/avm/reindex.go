@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// reindex wipes and rebuilds the indexes that are derived from the UTXO set,
+// which are the address index and the asset supplies. This allows the indexes
+// to be enabled, or repaired, on a node that already has the chain's state
+// without re-bootstrapping.
+func (vm *VM) reindex() error {
+	utxos, txs, err := vm.scanState()
+	if err != nil {
+		return err
+	}
+
+	// Every address and asset that an index may reference was referenced by an
+	// output of a stored tx, so wiping those entries wipes the indexes.
+	for _, tx := range txs {
+		for _, utxo := range tx.UnsignedTx.UTXOs() {
+			if err := vm.state.SetSupply(utxo.AssetID(), 0); err != nil {
+				return err
+			}
+			addressable, ok := utxo.Out.(FxAddressable)
+			if !ok {
+				continue
+			}
+			for _, addr := range addressable.Addresses() {
+				addrID := ids.NewID(hashing.ComputeHash256Array(addr))
+				if err := vm.state.SetFunds(addrID, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, utxo := range utxos {
+		if err := vm.state.addSupply(utxo); err != nil {
+			return err
+		}
+		addressable, ok := utxo.Out.(FxAddressable)
+		if !ok {
+			continue
+		}
+		if err := vm.state.addUTXO(addressable.Addresses(), utxo.InputID()); err != nil {
+			return err
+		}
+	}
+
+	vm.ctx.Log.Info("reindexed %d UTXOs from %d txs", len(utxos), len(txs))
+	return nil
+}
+
+// scanState returns the unspent UTXOs and the txs that are stored in the
+// database. A value is only treated as a UTXO, or a tx, if it is stored under
+// the key that the state would have stored it under.
+func (vm *VM) scanState() ([]*UTXO, []*Tx, error) {
+	iter := vm.db.NewIterator()
+	defer iter.Release()
+
+	utxos := []*UTXO(nil)
+	txs := []*Tx(nil)
+	for iter.Next() {
+		key, err := ids.ToID(iter.Key())
+		if err != nil {
+			continue
+		}
+		value := make([]byte, len(iter.Value()))
+		copy(value, iter.Value())
+
+		utxo := &UTXO{}
+		if err := vm.codec.Unmarshal(value, utxo); err == nil && utxo.InputID().Prefix(utxoID).Equals(key) {
+			utxos = append(utxos, utxo)
+			continue
+		}
+
+		tx := &Tx{}
+		if err := vm.codec.Unmarshal(value, tx); err == nil {
+			tx.Initialize(value)
+			if tx.ID().Prefix(txID).Equals(key) {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	return utxos, txs, iter.Error()
+}
@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// NewSendTx returns the unsigned transaction that sends [amount] of [assetID]
+// to [to] on the chain [chainID] of the network [networkID], and burns [fee]
+// of [feeAssetID], the fee of the chain's genesis. The transaction spends the
+// [utxos] that the keys in [kc] can spend at the unix time [time], and sends
+// the change to [changeAddr]. The i-th element of the returned keys are the
+// keys that sign the i-th input. [c] is the codec of the chain.
+func NewSendTx(networkID uint32, chainID ids.ID, c codec.Codec, utxos []*UTXO, kc *secp256k1fx.Keychain, assetID ids.ID, amount uint64, to, changeAddr ids.ShortID, feeAssetID ids.ID, fee, time uint64) (*Tx, [][]*crypto.PrivateKeySECP256K1R, error) {
+	if amount == 0 {
+		return nil, nil, errInvalidAmount
+	}
+
+	amounts := map[[32]byte]uint64{assetID.Key(): amount}
+	if fee > 0 {
+		total, err := math.Add64(amounts[feeAssetID.Key()], fee)
+		if err != nil {
+			return nil, nil, errSpendOverflow
+		}
+		amounts[feeAssetID.Key()] = total
+	}
+
+	tx := &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs:  []*TransferableOutput{transferOutput(assetID, amount, to)},
+	}
+	signers, err := spend(tx, c, utxos, kc, amounts, changeAddr, time)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Tx{UnsignedTx: tx}, signers, nil
+}
+
+// payFee adds to [tx], which must not have inputs yet, the inputs that burn
+// the fee of the chain's genesis. The fee is spent from the [utxos] that the
+// keys in [kc] can spend, and the change is sent to [changeAddr]. Returns the
+// keys that sign each of [tx]'s inputs.
+func (vm *VM) payFee(tx *BaseTx, utxos []*UTXO, kc *secp256k1fx.Keychain, changeAddr ids.ShortID) ([][]*crypto.PrivateKeySECP256K1R, error) {
+	if vm.requiredFee == 0 {
+		return nil, nil
+	}
+	amounts := map[[32]byte]uint64{vm.feeAssetID.Key(): vm.requiredFee}
+	return spend(tx, vm.codec, utxos, kc, amounts, changeAddr, vm.clock.Unix())
+}
+
+// spend sets the inputs of [tx] to inputs that spend at least [amounts] of
+// each asset, keyed by asset ID, from the [utxos] that the keys in [kc] can
+// spend at the unix time [time]. The change of each asset is sent to
+// [changeAddr]. Returns the keys that sign each of [tx]'s inputs.
+func spend(tx *BaseTx, c codec.Codec, utxos []*UTXO, kc *secp256k1fx.Keychain, amounts map[[32]byte]uint64, changeAddr ids.ShortID, time uint64) ([][]*crypto.PrivateKeySECP256K1R, error) {
+	spent := make(map[[32]byte]uint64, len(amounts))
+	ins := []*TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		if spent[assetKey] >= amounts[assetKey] {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(FxTransferable)
+		if !ok {
+			continue
+		}
+		amountSpent, err := math.Add64(spent[assetKey], input.Amount())
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		spent[assetKey] = amountSpent
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: assetID},
+			In:     input,
+		})
+		keys = append(keys, signers)
+	}
+
+	for assetKey, amount := range amounts {
+		switch {
+		case spent[assetKey] < amount:
+			return nil, errInsufficientFunds
+		case spent[assetKey] > amount:
+			tx.Outs = append(tx.Outs, transferOutput(ids.NewID(assetKey), spent[assetKey]-amount, changeAddr))
+		}
+	}
+
+	sortTransferableInputsWithSigners(ins, keys)
+	sortTransferableOutputs(tx.Outs, c)
+	tx.Ins = ins
+	return keys, nil
+}
+
+// transferOutput returns the output that sends [amount] of [assetID] to [addr]
+func transferOutput(assetID ids.ID, amount uint64, addr ids.ShortID) *TransferableOutput {
+	return &TransferableOutput{
+		Asset: Asset{
+			ID: assetID,
+		},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:      amount,
+			Locktime: 0,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestNewSendTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := vm.GetUTXOs(context.Background(), addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID := ids.ID{}
+	for _, utxo := range utxos {
+		if _, ok := utxo.Out.(*secp256k1fx.TransferOutput); ok {
+			assetID = utxo.AssetID()
+			break
+		}
+	}
+	if assetID.IsZero() {
+		t.Fatalf("expected a genesis utxo that can be sent")
+	}
+	now := vm.clock.Unix()
+
+	// The tx doesn't need the running chain, only the fxs it runs with
+	c, err := NewCodec(snow.DefaultLimits.MaxTxSize, []*common.Fx{&common.Fx{
+		ID: ids.Empty,
+		Fx: &secp256k1fx.Fx{},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	kc.Add(keys[0])
+	to := keys[1].PublicKey().Address()
+
+	if _, _, err := NewSendTx(networkID, chainID, c, utxos, kc, assetID, 0, to, addr, ids.Empty, 0, now); err != errInvalidAmount {
+		t.Fatalf("expected %s but got %v", errInvalidAmount, err)
+	}
+	if _, _, err := NewSendTx(networkID, chainID, c, utxos, kc, assetID, math.MaxUint64, to, addr, ids.Empty, 0, now); err != errInsufficientFunds && err != errSpendOverflow {
+		t.Fatalf("shouldn't have been able to send more than the utxos hold but got %v", err)
+	}
+	if _, _, err := NewSendTx(networkID, chainID, c, utxos, secp256k1fx.NewKeychain(), assetID, 1, to, addr, ids.Empty, 0, now); err != errInsufficientFunds {
+		t.Fatalf("expected %s but got %v", errInsufficientFunds, err)
+	}
+
+	tx, signers, err := NewSendTx(networkID, chainID, c, utxos, kc, assetID, 1, to, addr, ids.Empty, 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SignSECP256K1Fx(c, signers); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := c.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.IssueTx(txBytes); err != nil {
+		t.Fatalf("the chain should have accepted the tx built offline: %s", err)
+	}
+}
+
+func TestNewCodecInvalidFx(t *testing.T) {
+	if _, err := NewCodec(snow.DefaultLimits.MaxTxSize, []*common.Fx{nil}); err != errIncompatibleFx {
+		t.Fatalf("expected %s but got %v", errIncompatibleFx, err)
+	}
+}
@@ -19,32 +19,70 @@ import (
 	"github.com/ava-labs/gecko/utils/json"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/components/watch"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
+const (
+	// maxAddressLabels is the most labels an address may have
+	maxAddressLabels = 16
+
+	// maxLabelLength is the length, in bytes, of the longest label
+	maxLabelLength = 64
+)
+
 var (
-	errUnknownAssetID            = errors.New("unknown asset ID")
+	errUnknownAssetID            = json.NotFoundError(errors.New("unknown asset ID"))
 	errTxNotCreateAsset          = errors.New("transaction doesn't create an asset")
 	errNoHolders                 = errors.New("initialHolders must not be empty")
 	errNoMinters                 = errors.New("no minters provided")
 	errInvalidAmount             = errors.New("amount must be positive")
 	errSpendOverflow             = errors.New("spent amount overflows uint64")
 	errInvalidMintAmount         = errors.New("amount minted must be positive")
-	errAddressesCantMintAsset    = errors.New("provided addresses don't have the authority to mint the provided asset")
+	errMintPaysNoFee             = errors.New("mint txs built by this API don't pay the chain's fee")
+	errAddressesCantMintAsset    = json.UnauthorizedError(errors.New("provided addresses don't have the authority to mint the provided asset"))
 	errCanOnlySignSingleInputTxs = errors.New("can only sign transactions with one input")
-	errUnknownUTXO               = errors.New("unknown utxo")
+	errUnknownUTXO               = json.NotFoundError(errors.New("unknown utxo"))
 	errInvalidUTXO               = errors.New("invalid utxo")
 	errUnknownOutputType         = errors.New("unknown output type")
 	errUnneededAddress           = errors.New("address not required to sign")
 	errUnknownCredentialType     = errors.New("unknown credential type")
+	errTooManyLabels             = fmt.Errorf("an address may have at most %d labels", maxAddressLabels)
+	errLabelTooLong              = fmt.Errorf("labels may be at most %d bytes long", maxLabelLength)
 )
 
+// WriteMethods are the API methods that issue txs or change keystore users.
+// Read-only API replicas don't serve them.
+var WriteMethods = []string{
+	"avm.issueTx",
+	"avm.send",
+	"avm.createFixedCapAsset",
+	"avm.createVariableCapAsset",
+	"avm.createAddress",
+	"avm.importKey",
+	"avm.labelAddress",
+	"avm.registerAlias",
+}
+
+// WatchMethods are the API methods that manage the callback URLs notified of
+// accepted txs. They should only be exposed to this node's operator.
+var WatchMethods = []string{
+	"avm.watchAddress",
+	"avm.unwatchAddress",
+	"avm.listWatches",
+}
+
 // Service defines the base service for the asset vm
 type Service struct{ vm *VM }
 
 // IssueTxArgs are arguments for passing into IssueTx requests
 type IssueTxArgs struct {
 	Tx formatting.CB58 `json:"tx"`
+
+	// IdempotencyKey is an optional client-supplied token. If a tx was
+	// recently issued with the same token, its ID is returned rather than
+	// issuing [Tx] again.
+	IdempotencyKey string `json:"idempotencyKey"`
 }
 
 // IssueTxReply defines the IssueTx replies returned from the API
@@ -56,15 +94,70 @@ type IssueTxReply struct {
 func (service *Service) IssueTx(r *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
 	service.vm.ctx.Log.Verbo("IssueTx called with %s", args.Tx)
 
+	if txID, ok := service.vm.issuedTokens.Get("issueTx", args.IdempotencyKey); ok {
+		reply.TxID = txID
+		return nil
+	}
+
 	txID, err := service.vm.IssueTx(args.Tx.Bytes)
 	if err != nil {
 		return err
 	}
+	service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, txID)
 
 	reply.TxID = txID
 	return nil
 }
 
+// PreviewTxIDArgs are arguments for passing into PreviewTxID requests
+type PreviewTxIDArgs struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// PreviewTxIDReply defines the PreviewTxID replies returned from the API
+type PreviewTxIDReply struct {
+	// The ID of the tx as provided. The credentials are part of the tx's
+	// bytes, so the ID of an unsigned tx changes once it's signed.
+	TxID ids.ID `json:"txID"`
+
+	// True iff the tx has a credential for each of its inputs, so that
+	// [TxID] is the ID it will have when it's issued
+	Signed bool `json:"signed"`
+
+	// The digest of the tx's unsigned bytes, which is what each of its
+	// signers signs
+	UnsignedTxHash formatting.CB58 `json:"unsignedTxHash"`
+}
+
+// PreviewTxID returns the ID a tx will have when it's issued, without issuing
+// it, along with the digest its signers sign. This lets offline signers know
+// the IDs of the txs they sign before they're issued.
+func (service *Service) PreviewTxID(_ *http.Request, args *PreviewTxIDArgs, reply *PreviewTxIDReply) error {
+	service.vm.ctx.Log.Verbo("PreviewTxID called with %s", args.Tx)
+
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
+		return json.ParseError(fmt.Errorf("problem parsing transaction: %w", err))
+	}
+	if tx.UnsignedTx == nil {
+		return json.ParseError(errNilTx)
+	}
+	tx.Initialize(args.Tx.Bytes)
+	if err := tx.UnsignedTx.SyntacticVerify(service.vm.ctx, service.vm.codec, len(service.vm.fxs)); err != nil {
+		return json.ParseError(fmt.Errorf("invalid transaction: %w", err))
+	}
+
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem serializing transaction: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.Signed = tx.SyntacticVerify(service.vm.ctx, service.vm.codec, len(service.vm.fxs)) == nil
+	reply.UnsignedTxHash.Bytes = hashing.ComputeHash256(unsignedBytes)
+	return nil
+}
+
 // GetTxStatusArgs are arguments for passing into GetTxStatus requests
 type GetTxStatusArgs struct {
 	TxID ids.ID `json:"txID"`
@@ -73,6 +166,15 @@ type GetTxStatusArgs struct {
 // GetTxStatusReply defines the GetTxStatus replies returned from the API
 type GetTxStatusReply struct {
 	Status choices.Status `json:"status"`
+
+	// True iff the tx is accepted. Avalanche consensus never reverts the
+	// acceptance of a tx, so an accepted tx is final; there is no
+	// confirmation depth to wait for.
+	Finalized bool `json:"finalized"`
+
+	// Unix time at which this node accepted the tx. Omitted if the tx isn't
+	// accepted, or was accepted before this node recorded acceptance times.
+	AcceptedAt *json.Uint64 `json:"acceptedAt,omitempty"`
 }
 
 // GetTxStatus returns the status of the specified transaction
@@ -89,45 +191,62 @@ func (service *Service) GetTxStatus(r *http.Request, args *GetTxStatusArgs, repl
 	}
 
 	reply.Status = tx.Status()
+	reply.Finalized = reply.Status == choices.Accepted
+	if !reply.Finalized {
+		return nil
+	}
+	if acceptedAt, err := service.vm.state.AcceptedAt(args.TxID); err == nil && acceptedAt != 0 {
+		reply.AcceptedAt = (*json.Uint64)(&acceptedAt)
+	}
 	return nil
 }
 
 // GetUTXOsArgs are arguments for passing into GetUTXOs requests
 type GetUTXOsArgs struct {
 	Addresses []string `json:"addresses"`
+
+	// Encoding of the returned UTXOs, "cb58" (the default) or "hex"
+	Encoding string `json:"encoding"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
 type GetUTXOsReply struct {
-	UTXOs []formatting.CB58 `json:"utxos"`
+	UTXOs    []string            `json:"utxos"`
+	Encoding formatting.Encoding `json:"encoding"`
 }
 
 // GetUTXOs creates an empty account with the name passed in
 func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
 	service.vm.ctx.Log.Verbo("GetUTXOs called with %s", args.Addresses)
 
+	encoding, err := formatting.ParseEncoding(args.Encoding)
+	if err != nil {
+		return err
+	}
+
 	addrSet := ids.Set{}
 	for _, addr := range args.Addresses {
 		addrBytes, err := service.vm.Parse(addr)
 		if err != nil {
-			return err
+			return json.ParseError(err)
 		}
 		addrSet.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
 	}
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	utxos, err := service.vm.GetUTXOs(json.Context(r), addrSet)
 	if err != nil {
 		return err
 	}
 
-	reply.UTXOs = []formatting.CB58{}
+	reply.UTXOs = make([]string, 0, len(utxos))
 	for _, utxo := range utxos {
 		b, err := service.vm.codec.Marshal(utxo)
 		if err != nil {
 			return err
 		}
-		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: b})
+		reply.UTXOs = append(reply.UTXOs, encoding.Encode(b))
 	}
+	reply.Encoding = encoding
 	return nil
 }
 
@@ -152,7 +271,7 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	if err != nil {
 		assetID, err = ids.FromString(args.AssetID)
 		if err != nil {
-			return err
+			return json.ParseError(err)
 		}
 	}
 
@@ -176,6 +295,46 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	return nil
 }
 
+// GetAssetSupplyArgs are arguments for passing into GetAssetSupply requests
+type GetAssetSupplyArgs struct {
+	AssetID string `json:"assetID"`
+}
+
+// GetAssetSupplyReply defines the GetAssetSupply replies returned from the API
+type GetAssetSupplyReply struct {
+	Supply json.Uint64 `json:"supply"`
+}
+
+// GetAssetSupply returns the circulating supply of an asset, which is the
+// amount that was minted minus the amount that was burned. The supply is
+// maintained as transactions are accepted.
+func (service *Service) GetAssetSupply(_ *http.Request, args *GetAssetSupplyArgs, reply *GetAssetSupplyReply) error {
+	service.vm.ctx.Log.Verbo("GetAssetSupply called with %s", args.AssetID)
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return json.ParseError(err)
+		}
+	}
+
+	tx := &UniqueTx{
+		vm:   service.vm,
+		txID: assetID,
+	}
+	if status := tx.Status(); !status.Fetched() {
+		return errUnknownAssetID
+	}
+	if _, ok := tx.t.tx.UnsignedTx.(*CreateAssetTx); !ok {
+		return errTxNotCreateAsset
+	}
+
+	supply, _ := service.vm.state.Supply(assetID)
+	reply.Supply = json.Uint64(supply)
+	return nil
+}
+
 // GetBalanceArgs are arguments for passing into GetBalance requests
 type GetBalanceArgs struct {
 	Address string `json:"address"`
@@ -193,21 +352,21 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 
 	address, err := service.vm.Parse(args.Address)
 	if err != nil {
-		return err
+		return json.ParseError(err)
 	}
 
 	assetID, err := service.vm.Lookup(args.AssetID)
 	if err != nil {
 		assetID, err = ids.FromString(args.AssetID)
 		if err != nil {
-			return err
+			return json.ParseError(err)
 		}
 	}
 
 	addrSet := ids.Set{}
 	addrSet.Add(ids.NewID(hashing.ComputeHash256Array(address)))
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	utxos, err := service.vm.GetUTXOs(json.Context(r), addrSet)
 	if err != nil {
 		return err
 	}
@@ -228,6 +387,65 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 	return nil
 }
 
+// GetAddressInfoArgs are arguments for passing into GetAddressInfo requests
+type GetAddressInfoArgs struct {
+	Address string `json:"address"`
+}
+
+// GetAddressInfoReply defines the GetAddressInfo replies returned from the API
+type GetAddressInfoReply struct {
+	FirstSeen  json.Uint64 `json:"firstSeen"`
+	LastActive json.Uint64 `json:"lastActive"`
+}
+
+// GetAddressInfo returns the unix times at which an accepted tx first and last
+// spent from or paid to [args.Address]. If no accepted tx has, both are 0.
+func (service *Service) GetAddressInfo(r *http.Request, args *GetAddressInfoArgs, reply *GetAddressInfoReply) error {
+	service.vm.ctx.Log.Verbo("GetAddressInfo called with address: %s", args.Address)
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return json.ParseError(err)
+	}
+	addrID := ids.NewID(hashing.ComputeHash256Array(address))
+
+	firstSeen, _ := service.vm.state.FirstSeen(addrID)
+	lastActive, _ := service.vm.state.LastActive(addrID)
+	reply.FirstSeen = json.Uint64(firstSeen)
+	reply.LastActive = json.Uint64(lastActive)
+	return nil
+}
+
+// WatchAddress registers [args.URL] to be notified, with a signed POST, when a
+// tx that spends from or pays to [args.Address] is accepted
+func (service *Service) WatchAddress(_ *http.Request, args *watch.WatchAddressArgs, reply *watch.WatchAddressReply) error {
+	service.vm.ctx.Log.Verbo("WatchAddress called with address: %s", args.Address)
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return json.ParseError(err)
+	}
+	shortAddr, err := ids.ToShortID(address)
+	if err != nil {
+		return err
+	}
+	return service.vm.watcher.WatchAddress(shortAddr, args, reply)
+}
+
+// UnwatchAddress removes the watch with ID [args.WatchID]
+func (service *Service) UnwatchAddress(_ *http.Request, args *watch.UnwatchAddressArgs, reply *watch.UnwatchAddressReply) error {
+	service.vm.ctx.Log.Verbo("UnwatchAddress called with watch: %s", args.WatchID)
+
+	return service.vm.watcher.UnwatchAddress(args, reply)
+}
+
+// ListWatches lists the registered watches
+func (service *Service) ListWatches(_ *http.Request, args *watch.ListWatchesArgs, reply *watch.ListWatchesReply) error {
+	service.vm.ctx.Log.Verbo("ListWatches called")
+
+	return service.vm.watcher.ListWatches(args, reply)
+}
+
 // CreateFixedCapAssetArgs are arguments for passing into CreateFixedCapAsset requests
 type CreateFixedCapAssetArgs struct {
 	Username       string    `json:"username"`
@@ -282,7 +500,7 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 	for _, holder := range args.InitialHolders {
 		address, err := service.vm.Parse(holder.Address)
 		if err != nil {
-			return err
+			return json.ParseError(err)
 		}
 		addr, err := ids.ToShortID(address)
 		if err != nil {
@@ -298,6 +516,14 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 	}
 	initialState.Sort(service.vm.codec)
 
+	signers, err := service.payFee(r, args.Username, args.Password, &tx.UnsignedTx.(*CreateAssetTx).BaseTx)
+	if err != nil {
+		return err
+	}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, signers); err != nil {
+		return err
+	}
+
 	b, err := service.vm.codec.Marshal(tx)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
@@ -373,7 +599,7 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		for _, address := range owner.Minters {
 			addrBytes, err := service.vm.Parse(address)
 			if err != nil {
-				return err
+				return json.ParseError(err)
 			}
 			addr, err := ids.ToShortID(addrBytes)
 			if err != nil {
@@ -386,6 +612,14 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 	}
 	initialState.Sort(service.vm.codec)
 
+	signers, err := service.payFee(r, args.Username, args.Password, &tx.UnsignedTx.(*CreateAssetTx).BaseTx)
+	if err != nil {
+		return err
+	}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, signers); err != nil {
+		return err
+	}
+
 	b, err := service.vm.codec.Marshal(tx)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
@@ -401,6 +635,126 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 	return nil
 }
 
+// RegisterAliasArgs are arguments for passing into RegisterAlias requests
+type RegisterAliasArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	AssetID  string `json:"assetID"`
+	Alias    string `json:"alias"`
+}
+
+// RegisterAliasReply defines the RegisterAlias replies returned from the API
+type RegisterAliasReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// RegisterAlias issues a tx that registers [args.Alias] as an alias of the
+// asset [args.AssetID]. The user must hold the keys that own one of the
+// outputs the asset was created with. The first alias tx to be accepted gets
+// the alias. Once it has, the alias can be used in place of the asset's ID.
+func (service *Service) RegisterAlias(r *http.Request, args *RegisterAliasArgs, reply *RegisterAliasReply) error {
+	service.vm.ctx.Log.Verbo("RegisterAlias called with username: %s assetID: %s alias: %s", args.Username, args.AssetID, args.Alias)
+
+	if err := verifyAlias(args.Alias); err != nil {
+		return json.ParseError(err)
+	}
+	if _, err := service.vm.Lookup(args.Alias); err == nil {
+		return json.ConflictError(errAliasTaken)
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return json.NotFoundError(fmt.Errorf("asset '%s' not found", args.AssetID))
+		}
+	}
+	asset := UniqueTx{
+		vm:   service.vm,
+		txID: assetID,
+	}
+	if status := asset.Status(); !status.Fetched() {
+		return errUnknownAssetID
+	}
+	createAssetTx, ok := asset.t.tx.UnsignedTx.(*CreateAssetTx)
+	if !ok {
+		return errTxNotCreateAsset
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+	addresses, _ := user.Addresses(db)
+	kc := secp256k1fx.NewKeychain()
+	for _, addr := range addresses {
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+	}
+
+	// Sign with the keys of the first output of the asset the user owns
+	var (
+		outputIndex uint32
+		sigIndices  []uint32
+		signers     []*crypto.PrivateKeySECP256K1R
+		found       bool
+	)
+	for i := range createAssetTx.UTXOs() {
+		owners, err := createAssetTx.outputOwners(uint32(i))
+		if err != nil {
+			continue
+		}
+		if sigIndices, signers, found = kc.Match(owners); found {
+			outputIndex = uint32(i)
+			break
+		}
+	}
+	if !found {
+		return json.UnauthorizedError(errWrongCreatorSigners)
+	}
+
+	aliasTx := &AliasAssetTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+		},
+		AssetID:     assetID,
+		Alias:       args.Alias,
+		OutputIndex: outputIndex,
+		Creator: secp256k1fx.Input{
+			SigIndices: sigIndices,
+		},
+	}
+	tx := Tx{UnsignedTx: aliasTx}
+
+	// The creator's credential follows the credentials of the inputs
+	feeSigners, err := service.payFee(r, args.Username, args.Password, &aliasTx.BaseTx)
+	if err != nil {
+		return err
+	}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, append(feeSigners, signers)); err != nil {
+		return err
+	}
+
+	b, err := service.vm.codec.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
 // CreateAddressArgs are arguments for calling CreateAddress
 type CreateAddressArgs struct {
 	Username string `json:"username"`
@@ -464,7 +818,7 @@ func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *E
 
 	address, err := service.vm.Parse(args.Address)
 	if err != nil {
-		return fmt.Errorf("problem parsing address: %w", err)
+		return json.ParseError(fmt.Errorf("problem parsing address: %w", err))
 	}
 
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
@@ -483,6 +837,56 @@ func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *E
 	return nil
 }
 
+// SignHashArgs are arguments for SignHash
+type SignHashArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Address  string          `json:"address"`
+	Hash     formatting.CB58 `json:"hash"`
+}
+
+// SignHashReply is the response for SignHash
+type SignHashReply struct {
+	Signature formatting.CB58 `json:"signature"`
+}
+
+// SignHash signs a 32 byte hash with the key of the provided address. The
+// hash is signed in this chain's domain (see crypto.DomainSeparatedHash), so
+// the signature can't authorize a tx or be replayed on another chain.
+func (service *Service) SignHash(r *http.Request, args *SignHashArgs, reply *SignHashReply) error {
+	service.vm.ctx.Log.Verbo("SignHash called for user '%s'", args.Username)
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return json.ParseError(fmt.Errorf("problem parsing address: %w", err))
+	}
+
+	signedHash, err := crypto.DomainSeparatedHash(service.vm.ctx.ChainID, args.Hash.Bytes)
+	if err != nil {
+		return json.ParseError(fmt.Errorf("problem parsing hash: %w", err))
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	sk, err := user.Key(db, ids.NewID(hashing.ComputeHash256Array(address)))
+	if err != nil {
+		return fmt.Errorf("problem retrieving private key: %w", err)
+	}
+
+	sig, err := sk.SignHash(signedHash)
+	if err != nil {
+		return fmt.Errorf("problem signing hash: %w", err)
+	}
+
+	reply.Signature.Bytes = sig
+	return nil
+}
+
 // ImportKeyArgs are arguments for ImportKey
 type ImportKeyArgs struct {
 	Username   string          `json:"username"`
@@ -510,7 +914,7 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *I
 	factory := crypto.FactorySECP256K1R{}
 	skIntf, err := factory.ToPrivateKey(args.PrivateKey.Bytes)
 	if err != nil {
-		return fmt.Errorf("problem parsing private key %s: %w", args.PrivateKey, err)
+		return json.ParseError(fmt.Errorf("problem parsing private key %s: %w", args.PrivateKey, err))
 	}
 	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
@@ -529,6 +933,115 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *I
 	return nil
 }
 
+// LabelAddressArgs are arguments for LabelAddress
+type LabelAddressArgs struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Address  string   `json:"address"`
+	Labels   []string `json:"labels"`
+}
+
+// LabelAddressReply is the response for LabelAddress
+type LabelAddressReply struct {
+	Success bool `json:"success"`
+}
+
+// LabelAddress replaces the labels, such as "hot wallet", of an address the
+// provided user controls. No labels removes the address's labels.
+func (service *Service) LabelAddress(r *http.Request, args *LabelAddressArgs, reply *LabelAddressReply) error {
+	service.vm.ctx.Log.Verbo("LabelAddress called for user '%s'", args.Username)
+
+	if len(args.Labels) > maxAddressLabels {
+		return errTooManyLabels
+	}
+	for _, label := range args.Labels {
+		if len(label) > maxLabelLength {
+			return errLabelTooLong
+		}
+	}
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return json.ParseError(fmt.Errorf("problem parsing address: %w", err))
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addressID := ids.NewID(hashing.ComputeHash256Array(address))
+	if _, err := user.Key(db, addressID); err != nil {
+		return fmt.Errorf("problem retrieving private key: %w", err)
+	}
+	if err := user.SetLabels(db, addressID, args.Labels); err != nil {
+		return fmt.Errorf("problem saving labels: %w", err)
+	}
+
+	reply.Success = true
+	return nil
+}
+
+// ListAddressesArgs are arguments for ListAddresses
+type ListAddressesArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// APIAddress is an address a user controls and its labels
+type APIAddress struct {
+	Address string   `json:"address"`
+	Labels  []string `json:"labels"`
+}
+
+// ListAddressesReply is the response for ListAddresses
+type ListAddressesReply struct {
+	Addresses []APIAddress `json:"addresses"`
+}
+
+// ListAddresses returns the addresses the provided user controls, in the order
+// they were created or imported, along with their labels
+func (service *Service) ListAddresses(r *http.Request, args *ListAddressesArgs, reply *ListAddressesReply) error {
+	service.vm.ctx.Log.Verbo("ListAddresses called for user '%s'", args.Username)
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+	addresses, _ := user.Addresses(db)
+
+	listed := ids.Set{}
+	reply.Addresses = []APIAddress{}
+	for _, addr := range addresses {
+		// A key that was imported more than once is listed once
+		if listed.Contains(addr) {
+			continue
+		}
+		listed.Add(addr)
+
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		labels, err := user.Labels(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving labels: %w", err)
+		}
+		if labels == nil {
+			labels = []string{}
+		}
+		reply.Addresses = append(reply.Addresses, APIAddress{
+			Address: service.vm.Format(sk.PublicKey().Address().Bytes()),
+			Labels:  labels,
+		})
+	}
+	return nil
+}
+
 // SendArgs are arguments for passing into Send requests
 type SendArgs struct {
 	Username string      `json:"username"`
@@ -536,6 +1049,11 @@ type SendArgs struct {
 	Amount   json.Uint64 `json:"amount"`
 	AssetID  string      `json:"assetID"`
 	To       string      `json:"to"`
+
+	// IdempotencyKey is an optional client-supplied token. If this user
+	// recently sent a tx with the same token, its ID is returned rather than
+	// sending again.
+	IdempotencyKey string `json:"idempotencyKey"`
 }
 
 // SendReply defines the Send replies returned from the API
@@ -547,6 +1065,13 @@ type SendReply struct {
 func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply) error {
 	service.vm.ctx.Log.Verbo("Send called with username: %s", args.Username)
 
+	// Tokens are scoped to the user so that users can't collide
+	tokenScope := "send:" + args.Username
+	if txID, ok := service.vm.issuedTokens.Get(tokenScope, args.IdempotencyKey); ok {
+		reply.TxID = txID
+		return nil
+	}
+
 	if args.Amount == 0 {
 		return errInvalidAmount
 	}
@@ -555,22 +1080,74 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 	if err != nil {
 		assetID, err = ids.FromString(args.AssetID)
 		if err != nil {
-			return fmt.Errorf("asset '%s' not found", args.AssetID)
+			return json.NotFoundError(fmt.Errorf("asset '%s' not found", args.AssetID))
 		}
 	}
 
 	toBytes, err := service.vm.Parse(args.To)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
+		return json.ParseError(fmt.Errorf("problem parsing to address: %w", err))
 	}
 	to, err := ids.ToShortID(toBytes)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
+		return json.ParseError(fmt.Errorf("problem parsing to address: %w", err))
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	utxos, kc, err := service.userFunds(r, args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return err
+	}
+
+	// Change is sent back to the user's first address
+	if len(kc.Keys) == 0 {
+		return json.InsufficientFundsError(errInsufficientFunds)
+	}
+	changeAddr := kc.Keys[0].PublicKey().Address()
+
+	tx, signers, err := NewSendTx(
+		service.vm.ctx.NetworkID,
+		service.vm.ctx.ChainID,
+		service.vm.codec,
+		utxos,
+		kc,
+		assetID,
+		uint64(args.Amount),
+		to,
+		changeAddr,
+		service.vm.feeAssetID,
+		service.vm.requiredFee,
+		service.vm.clock.Unix(),
+	)
+	if err == errInsufficientFunds {
+		return json.InsufficientFundsError(err)
+	} else if err != nil {
+		return err
+	}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, signers); err != nil {
+		return err
+	}
+
+	b, err := service.vm.codec.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	service.vm.issuedTokens.Put(tokenScope, args.IdempotencyKey, txID)
+
+	reply.TxID = txID
+	return nil
+}
+
+// userFunds returns the UTXOs of the user [username], and a keychain of the
+// user's keys
+func (service *Service) userFunds(r *http.Request, username, password string) ([]*UTXO, *secp256k1fx.Keychain, error) {
+	db, err := service.vm.ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving user: %w", err)
 	}
 
 	user := userState{vm: service.vm}
@@ -579,142 +1156,42 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 
 	addrs := ids.Set{}
 	addrs.Add(addresses...)
-	utxos, err := service.vm.GetUTXOs(addrs)
+	utxos, err := service.vm.GetUTXOs(json.Context(r), addrs)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
 	}
 
 	kc := secp256k1fx.NewKeychain()
 	for _, addr := range addresses {
 		sk, err := user.Key(db, addr)
 		if err != nil {
-			return fmt.Errorf("problem retrieving private key: %w", err)
+			return nil, nil, fmt.Errorf("problem retrieving private key: %w", err)
 		}
 		kc.Add(sk)
 	}
+	return utxos, kc, nil
+}
 
-	amountSpent := uint64(0)
-	time := service.vm.clock.Unix()
-
-	ins := []*TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
-	for _, utxo := range utxos {
-		if !utxo.AssetID().Equals(assetID) {
-			continue
-		}
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
-		if err != nil {
-			continue
-		}
-		input, ok := inputIntf.(FxTransferable)
-		if !ok {
-			continue
-		}
-		spent, err := math.Add64(amountSpent, input.Amount())
-		if err != nil {
-			return errSpendOverflow
-		}
-		amountSpent = spent
-
-		in := &TransferableInput{
-			UTXOID: utxo.UTXOID,
-			Asset:  Asset{ID: assetID},
-			In:     input,
-		}
-
-		ins = append(ins, in)
-		keys = append(keys, signers)
-
-		if amountSpent >= uint64(args.Amount) {
-			break
-		}
-	}
-
-	if amountSpent < uint64(args.Amount) {
-		return errInsufficientFunds
-	}
-
-	sortTransferableInputsWithSigners(ins, keys)
-
-	outs := []*TransferableOutput{
-		&TransferableOutput{
-			Asset: Asset{
-				ID: assetID,
-			},
-			Out: &secp256k1fx.TransferOutput{
-				Amt:      uint64(args.Amount),
-				Locktime: 0,
-				OutputOwners: secp256k1fx.OutputOwners{
-					Threshold: 1,
-					Addrs:     []ids.ShortID{to},
-				},
-			},
-		},
-	}
-
-	if amountSpent > uint64(args.Amount) {
-		changeAddr := kc.Keys[0].PublicKey().Address()
-		outs = append(outs,
-			&TransferableOutput{
-				Asset: Asset{
-					ID: assetID,
-				},
-				Out: &secp256k1fx.TransferOutput{
-					Amt:      amountSpent - uint64(args.Amount),
-					Locktime: 0,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
-				},
-			},
-		)
-	}
-
-	sortTransferableOutputs(outs, service.vm.codec)
-
-	tx := Tx{
-		UnsignedTx: &BaseTx{
-			NetID: service.vm.ctx.NetworkID,
-			BCID:  service.vm.ctx.ChainID,
-			Outs:  outs,
-			Ins:   ins,
-		},
+// payFee adds to [tx] the inputs that burn the fee of the chain's genesis,
+// paid by the user [username], and returns the keys that sign each input. The
+// change is sent back to the user's first address. If the chain's txs don't
+// pay a fee, [tx] is unchanged.
+func (service *Service) payFee(r *http.Request, username, password string, tx *BaseTx) ([][]*crypto.PrivateKeySECP256K1R, error) {
+	if service.vm.requiredFee == 0 {
+		return nil, nil
 	}
-
-	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	utxos, kc, err := service.userFunds(r, username, password)
 	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-	hash := hashing.ComputeHash256(unsignedBytes)
-
-	for _, credKeys := range keys {
-		cred := &secp256k1fx.Credential{}
-		for _, key := range credKeys {
-			sig, err := key.SignHash(hash)
-			if err != nil {
-				return fmt.Errorf("problem creating transaction: %w", err)
-			}
-			fixedSig := [crypto.SECP256K1RSigLen]byte{}
-			copy(fixedSig[:], sig)
-
-			cred.Sigs = append(cred.Sigs, fixedSig)
-		}
-		tx.Creds = append(tx.Creds, &Credential{Cred: cred})
+		return nil, err
 	}
-
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+	if len(kc.Keys) == 0 {
+		return nil, json.InsufficientFundsError(errInsufficientFunds)
 	}
-
-	txID, err := service.vm.IssueTx(b)
-	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+	signers, err := service.vm.payFee(tx, utxos, kc, kc.Keys[0].PublicKey().Address())
+	if err == errInsufficientFunds {
+		return nil, json.InsufficientFundsError(err)
 	}
-
-	reply.TxID = txID
-	return nil
+	return signers, err
 }
 
 type innerSortTransferableInputsWithSigners struct {
@@ -748,6 +1225,146 @@ func isSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, si
 	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
 
+// GetSpendPlanArgs are arguments for passing into GetSpendPlan requests
+type GetSpendPlanArgs struct {
+	Addresses []string    `json:"addresses"`
+	AssetID   string      `json:"assetID"`
+	Amount    json.Uint64 `json:"amount"`
+}
+
+// SpendPlanInput describes a UTXO that a spend would consume
+type SpendPlanInput struct {
+	TxID        ids.ID      `json:"txID"`
+	OutputIndex json.Uint32 `json:"outputIndex"`
+	Amount      json.Uint64 `json:"amount"`
+
+	// SigIndices are the indices, into the UTXO's owners, of the addresses
+	// that must sign
+	SigIndices []json.Uint32 `json:"sigIndices"`
+
+	// Signers are the addresses that must sign
+	Signers []string `json:"signers"`
+}
+
+// GetSpendPlanReply defines the GetSpendPlan replies returned from the API
+type GetSpendPlanReply struct {
+	Inputs []SpendPlanInput `json:"inputs"`
+
+	// Consumed is the sum of the amounts of [Inputs]
+	Consumed json.Uint64 `json:"consumed"`
+
+	// Change is the amount that is consumed but not sent
+	Change json.Uint64 `json:"change"`
+
+	// Signers are the addresses that must sign at least one of [Inputs]
+	Signers []string `json:"signers"`
+}
+
+// GetSpendPlan returns the UTXOs that would be consumed to send [args.Amount]
+// of an asset from [args.Addresses], along with the addresses whose
+// signatures are required to spend them. No keys are needed, so the
+// signatures of a multisig spend can be collected before the tx is built.
+// UTXOs are consumed in the same order as they are consumed by Send.
+func (service *Service) GetSpendPlan(r *http.Request, args *GetSpendPlanArgs, reply *GetSpendPlanReply) error {
+	service.vm.ctx.Log.Verbo("GetSpendPlan called with addresses: %s assetID: %s", args.Addresses, args.AssetID)
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return json.NotFoundError(fmt.Errorf("asset '%s' not found", args.AssetID))
+		}
+	}
+
+	addrs := ids.ShortSet{}
+	addrSet := ids.Set{}
+	for _, addrStr := range args.Addresses {
+		addrBytes, err := service.vm.Parse(addrStr)
+		if err != nil {
+			return json.ParseError(fmt.Errorf("problem parsing address '%s': %w", addrStr, err))
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return json.ParseError(fmt.Errorf("problem parsing address '%s': %w", addrStr, err))
+		}
+		addrs.Add(addr)
+		addrSet.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
+	}
+
+	utxos, err := service.vm.GetUTXOs(json.Context(r), addrSet)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	consumed := uint64(0)
+	time := service.vm.clock.Unix()
+	signers := ids.ShortSet{}
+
+	reply.Inputs = []SpendPlanInput{}
+	reply.Signers = []string{}
+	for _, utxo := range utxos {
+		if consumed >= uint64(args.Amount) {
+			break
+		}
+		if !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || time < out.Locktime {
+			continue
+		}
+		sigIndices, able := matchOwners(&out.OutputOwners, addrs)
+		if !able {
+			continue
+		}
+		amount, err := math.Add64(consumed, out.Amount())
+		if err != nil {
+			return errSpendOverflow
+		}
+		consumed = amount
+
+		input := SpendPlanInput{
+			TxID:        utxo.TxID,
+			OutputIndex: json.Uint32(utxo.OutputIndex),
+			Amount:      json.Uint64(out.Amount()),
+		}
+		for _, sigIndex := range sigIndices {
+			signer := service.vm.Format(out.Addrs[sigIndex].Bytes())
+			if !signers.Contains(out.Addrs[sigIndex]) {
+				signers.Add(out.Addrs[sigIndex])
+				reply.Signers = append(reply.Signers, signer)
+			}
+			input.SigIndices = append(input.SigIndices, json.Uint32(sigIndex))
+			input.Signers = append(input.Signers, signer)
+		}
+		reply.Inputs = append(reply.Inputs, input)
+	}
+
+	if consumed < uint64(args.Amount) {
+		return json.InsufficientFundsError(errInsufficientFunds)
+	}
+
+	reply.Consumed = json.Uint64(consumed)
+	reply.Change = json.Uint64(consumed - uint64(args.Amount))
+	return nil
+}
+
+// matchOwners returns the indices of the first [owners.Threshold] addresses of
+// [owners] that are in [addrs], and whether there were enough of them
+func matchOwners(owners *secp256k1fx.OutputOwners, addrs ids.ShortSet) ([]uint32, bool) {
+	sigIndices := []uint32{}
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigIndices)) < owners.Threshold; i++ {
+		if addrs.Contains(owners.Addrs[i]) {
+			sigIndices = append(sigIndices, i)
+		}
+	}
+	return sigIndices, uint32(len(sigIndices)) == owners.Threshold
+}
+
 // CreateMintTxArgs are arguments for passing into CreateMintTx requests
 type CreateMintTxArgs struct {
 	Amount  json.Uint64 `json:"amount"`
@@ -768,22 +1385,25 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 	if args.Amount == 0 {
 		return errInvalidMintAmount
 	}
+	if service.vm.requiredFee > 0 {
+		return errMintPaysNoFee
+	}
 
 	assetID, err := service.vm.Lookup(args.AssetID)
 	if err != nil {
 		assetID, err = ids.FromString(args.AssetID)
 		if err != nil {
-			return fmt.Errorf("asset '%s' not found", args.AssetID)
+			return json.NotFoundError(fmt.Errorf("asset '%s' not found", args.AssetID))
 		}
 	}
 
 	toBytes, err := service.vm.Parse(args.To)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+		return json.ParseError(fmt.Errorf("problem parsing to address '%s': %w", args.To, err))
 	}
 	to, err := ids.ToShortID(toBytes)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+		return json.ParseError(fmt.Errorf("problem parsing to address '%s': %w", args.To, err))
 	}
 
 	addrs := ids.Set{}
@@ -791,17 +1411,17 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 	for _, minter := range args.Minters {
 		addrBytes, err := service.vm.Parse(minter)
 		if err != nil {
-			return fmt.Errorf("problem parsing minter address '%s': %w", minter, err)
+			return json.ParseError(fmt.Errorf("problem parsing minter address '%s': %w", minter, err))
 		}
 		addr, err := ids.ToShortID(addrBytes)
 		if err != nil {
-			return fmt.Errorf("problem parsing minter address '%s': %w", minter, err)
+			return json.ParseError(fmt.Errorf("problem parsing minter address '%s': %w", minter, err))
 		}
 		addrs.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
 		minters.Add(addr)
 	}
 
-	utxos, err := service.vm.GetUTXOs(addrs)
+	utxos, err := service.vm.GetUTXOs(json.Context(r), addrs)
 	if err != nil {
 		return fmt.Errorf("problem getting user's UTXOs: %w", err)
 	}
@@ -824,39 +1444,33 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 			}
 
 			tx := Tx{
-				UnsignedTx: &OperationTx{
+				UnsignedTx: &UTXOOperationTx{
 					BaseTx: BaseTx{
 						NetID: service.vm.ctx.NetworkID,
 						BCID:  service.vm.ctx.ChainID,
 					},
-					Ops: []*Operation{
-						&Operation{
+					Ops: []*UTXOOperation{
+						&UTXOOperation{
 							Asset: Asset{
 								ID: assetID,
 							},
-							Ins: []*OperableInput{
-								&OperableInput{
-									UTXOID: utxo.UTXOID,
-									In: &secp256k1fx.MintInput{
-										Input: secp256k1fx.Input{
-											SigIndices: sigs,
-										},
-									},
-								},
+							UTXOIDs: []*UTXOID{
+								&utxo.UTXOID,
 							},
-							Outs: []*OperableOutput{
-								&OperableOutput{
-									&secp256k1fx.MintOutput{
-										OutputOwners: out.OutputOwners,
+							Op: &secp256k1fx.MintOperation{
+								MintInput: secp256k1fx.MintInput{
+									Input: secp256k1fx.Input{
+										SigIndices: sigs,
 									},
 								},
-								&OperableOutput{
-									&secp256k1fx.TransferOutput{
-										Amt: uint64(args.Amount),
-										OutputOwners: secp256k1fx.OutputOwners{
-											Threshold: 1,
-											Addrs:     []ids.ShortID{to},
-										},
+								MintOutput: secp256k1fx.MintOutput{
+									OutputOwners: out.OutputOwners,
+								},
+								TransferOutput: secp256k1fx.TransferOutput{
+									Amt: uint64(args.Amount),
+									OutputOwners: secp256k1fx.OutputOwners{
+										Threshold: 1,
+										Addrs:     []ids.ShortID{to},
 									},
 								},
 							},
@@ -896,7 +1510,7 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 
 	minter, err := service.vm.Parse(args.Minter)
 	if err != nil {
-		return fmt.Errorf("problem parsing address '%s': %w", args.Minter, err)
+		return json.ParseError(fmt.Errorf("problem parsing address '%s': %w", args.Minter, err))
 	}
 
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
@@ -4,11 +4,25 @@
 package avm
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
@@ -56,6 +70,423 @@ func TestGetAssetDescription(t *testing.T) {
 	}
 }
 
+func TestGetAssetSupply(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	s := Service{vm: vm}
+
+	reply := GetAssetSupplyReply{}
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{
+		AssetID: genesisTx.ID().String(),
+	}, &reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Supply != 300000 {
+		t.Fatalf("Wrong supply returned from GetAssetSupply %d", reply.Supply)
+	}
+
+	// Burn one of the genesis UTXOs
+	burnTx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&burnTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	burnTx.Creds = append(burnTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(burnTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID, err := vm.IssueTx(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.GetTx(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Accept()
+
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{
+		AssetID: genesisTx.ID().String(),
+	}, &reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Supply != 250000 {
+		t.Fatalf("Wrong supply returned from GetAssetSupply %d", reply.Supply)
+	}
+}
+
+func TestGetAddressInfo(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	s := Service{vm: vm}
+	args := &GetAddressInfoArgs{Address: vm.Format(keys[0].PublicKey().Address().Bytes())}
+
+	reply := GetAddressInfoReply{}
+	if err := s.GetAddressInfo(nil, args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.FirstSeen != 0 || reply.LastActive != 0 {
+		t.Fatalf("Address shouldn't have been active before any tx was accepted")
+	}
+
+	// Spend one of the address's genesis UTXOs
+	spendTx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&spendTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	spendTx.Creds = append(spendTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(spendTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID, err := vm.IssueTx(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.GetTx(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.clock.Set(time.Unix(1000, 0))
+	tx.Accept()
+
+	if err := s.GetAddressInfo(nil, args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.FirstSeen != 1000 || reply.LastActive != 1000 {
+		t.Fatalf("Address should have first been seen and last active at %d but was at %d and %d", 1000, reply.FirstSeen, reply.LastActive)
+	}
+}
+
+func TestGetTxStatusFinalized(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	spendTx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&spendTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	spendTx.Creds = append(spendTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(spendTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID, err := vm.IssueTx(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	reply := GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: txID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Status != choices.Processing || reply.Finalized || reply.AcceptedAt != nil {
+		t.Fatalf("A processing tx shouldn't be finalized")
+	}
+
+	tx, err := vm.GetTx(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.clock.Set(time.Unix(1000, 0))
+	tx.Accept()
+
+	reply = GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: txID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Status != choices.Accepted || !reply.Finalized {
+		t.Fatalf("An accepted tx should be finalized")
+	}
+	if reply.AcceptedAt == nil || *reply.AcceptedAt != 1000 {
+		t.Fatalf("Tx should have been accepted at %d but was at %v", 1000, reply.AcceptedAt)
+	}
+}
+
+func TestIssueTxIdempotencyKey(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	tx.Creds = append(tx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	args := &IssueTxArgs{
+		Tx:             formatting.CB58{Bytes: b},
+		IdempotencyKey: "retry me",
+	}
+
+	reply := IssueTxReply{}
+	if err := s.IssueTx(nil, args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	txID := reply.TxID
+
+	// Retrying with the same key should return the original tx without
+	// issuing the provided tx
+	args.Tx = formatting.CB58{Bytes: []byte{0}}
+	reply = IssueTxReply{}
+	if err := s.IssueTx(nil, args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.TxID.Equals(txID) {
+		t.Fatalf("Retried IssueTx returned %s, expected %s", reply.TxID, txID)
+	}
+
+	// Without the key, the provided tx is issued
+	args.IdempotencyKey = ""
+	if err := s.IssueTx(nil, args, &reply); err == nil {
+		t.Fatalf("Should have failed to issue an invalid tx")
+	}
+}
+
 func TestGetBalance(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -98,6 +529,114 @@ func TestGetBalance(t *testing.T) {
 	}
 }
 
+func TestGetUTXOsCancelled(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	s := Service{vm: vm}
+	args := &GetUTXOsArgs{
+		Addresses: []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+	}
+
+	reply := GetUTXOsReply{}
+	if err := s.GetUTXOs(httptest.NewRequest("POST", "/", nil), args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) == 0 {
+		t.Fatal("should have returned the genesis UTXOs")
+	}
+
+	// The client went away
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest("POST", "/", nil).WithContext(reqCtx)
+	if err := s.GetUTXOs(request, args, &GetUTXOsReply{}); err != context.Canceled {
+		t.Fatalf("should have failed with %s but got %v", context.Canceled, err)
+	}
+}
+
+func TestGetUTXOsHexEncoding(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	s := Service{vm: vm}
+	args := &GetUTXOsArgs{
+		Addresses: []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+	}
+
+	reply := GetUTXOsReply{}
+	if err := s.GetUTXOs(httptest.NewRequest("POST", "/", nil), args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) == 0 {
+		t.Fatal("should have returned the genesis UTXOs")
+	}
+
+	args.Encoding = "hex"
+	hexReply := GetUTXOsReply{}
+	if err := s.GetUTXOs(httptest.NewRequest("POST", "/", nil), args, &hexReply); err != nil {
+		t.Fatal(err)
+	}
+	if hexReply.Encoding != formatting.HexEncoding || len(hexReply.UTXOs) != len(reply.UTXOs) {
+		t.Fatalf("should have returned the same UTXOs in hex")
+	}
+
+	// The UTXOs aren't returned in any particular order
+	utxos := map[string]bool{}
+	for _, utxo := range reply.UTXOs {
+		cb58 := formatting.CB58{}
+		if err := cb58.FromString(utxo); err != nil {
+			t.Fatal(err)
+		}
+		utxos[string(cb58.Bytes)] = true
+	}
+	for _, utxo := range hexReply.UTXOs {
+		hex := formatting.Hex{}
+		if err := hex.FromString(utxo); err != nil {
+			t.Fatal(err)
+		}
+		if !utxos[string(hex.Bytes)] {
+			t.Fatal("encodings should hold the same UTXOs")
+		}
+	}
+}
+
 func TestCreateFixedCapAsset(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -186,3 +725,319 @@ func TestCreateVariableCapAsset(t *testing.T) {
 		t.Fatalf("Wrong assetID returned from CreateFixedCapAsset %s", reply.AssetID)
 	}
 }
+
+func TestGetSpendPlan(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	s := Service{vm: vm}
+	addr0 := vm.Format(keys[0].PublicKey().Address().Bytes())
+	addr1 := vm.Format(keys[1].PublicKey().Address().Bytes())
+
+	reply := GetSpendPlanReply{}
+	err = s.GetSpendPlan(nil, &GetSpendPlanArgs{
+		Addresses: []string{addr0, addr1},
+		AssetID:   genesisTx.ID().String(),
+		Amount:    150000,
+	}, &reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consumed := json.Uint64(0)
+	for _, input := range reply.Inputs {
+		if !input.TxID.Equals(genesisTx.ID()) {
+			t.Fatalf("Wrong UTXO returned from GetSpendPlan %s:%d", input.TxID, input.OutputIndex)
+		}
+		if len(input.Signers) != 1 || input.Signers[0] != addr0 || len(input.SigIndices) != 1 || input.SigIndices[0] != 0 {
+			t.Fatalf("Wrong signers returned from GetSpendPlan %v %v", input.Signers, input.SigIndices)
+		}
+		consumed += input.Amount
+	}
+	if consumed != reply.Consumed || consumed < 150000 {
+		t.Fatalf("Wrong amount consumed by GetSpendPlan %d", reply.Consumed)
+	}
+	if reply.Change != reply.Consumed-150000 {
+		t.Fatalf("Wrong change returned from GetSpendPlan %d", reply.Change)
+	}
+	if len(reply.Signers) != 1 || reply.Signers[0] != addr0 {
+		t.Fatalf("Wrong signers returned from GetSpendPlan %v", reply.Signers)
+	}
+
+	err = s.GetSpendPlan(nil, &GetSpendPlanArgs{
+		Addresses: []string{addr0},
+		AssetID:   genesisTx.ID().String(),
+		Amount:    300001,
+	}, &GetSpendPlanReply{})
+	if !errors.Is(err, errInsufficientFunds) {
+		t.Fatalf("Should have errored due to insufficient funds, got %v", err)
+	}
+
+	err = s.GetSpendPlan(nil, &GetSpendPlanArgs{
+		Addresses: []string{addr1},
+		AssetID:   genesisTx.ID().String(),
+		Amount:    1,
+	}, &GetSpendPlanReply{})
+	if !errors.Is(err, errInsufficientFunds) {
+		t.Fatalf("Should have errored due to insufficient funds, got %v", err)
+	}
+}
+
+func TestLabelAddress(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.ctx.Keystore = ks.NewBlockchainKeyStore(vm.ctx.ChainID)
+	defer func() { vm.ctx.Keystore = nil }()
+
+	s := Service{vm: vm}
+
+	addresses := []string{}
+	for _, key := range keys[:2] {
+		reply := ImportKeyReply{}
+		if err := s.ImportKey(nil, &ImportKeyArgs{
+			Username:   "bob",
+			Password:   "launch",
+			PrivateKey: formatting.CB58{Bytes: key.Bytes()},
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		addresses = append(addresses, reply.Address)
+	}
+
+	if err := s.LabelAddress(nil, &LabelAddressArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  addresses[1],
+		Labels:   []string{"hot wallet", "exchange"},
+	}, &LabelAddressReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := ListAddressesReply{}
+	if err := s.ListAddresses(nil, &ListAddressesArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Addresses) != 2 {
+		t.Fatalf("expected 2 addresses but got %d", len(reply.Addresses))
+	}
+	if reply.Addresses[0].Address != addresses[0] || len(reply.Addresses[0].Labels) != 0 {
+		t.Fatalf("expected %s without labels but got %v", addresses[0], reply.Addresses[0])
+	}
+	if labels := reply.Addresses[1].Labels; reply.Addresses[1].Address != addresses[1] ||
+		len(labels) != 2 || labels[0] != "hot wallet" || labels[1] != "exchange" {
+		t.Fatalf("expected %s to be labeled but got %v", addresses[1], reply.Addresses[1])
+	}
+
+	// Removing the labels
+	if err := s.LabelAddress(nil, &LabelAddressArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  addresses[1],
+	}, &LabelAddressReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ListAddresses(nil, &ListAddressesArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Addresses[1].Labels) != 0 {
+		t.Fatalf("the labels should have been removed")
+	}
+
+	// Only the user's own addresses can be labeled
+	if err := s.LabelAddress(nil, &LabelAddressArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  vm.Format(keys[2].PublicKey().Address().Bytes()),
+		Labels:   []string{"cold sweep"},
+	}, &LabelAddressReply{}); err == nil {
+		t.Fatalf("shouldn't have labeled an address the user doesn't control")
+	}
+	if err := s.LabelAddress(nil, &LabelAddressArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  addresses[0],
+		Labels:   []string{strings.Repeat("a", maxLabelLength+1)},
+	}, &LabelAddressReply{}); err != errLabelTooLong {
+		t.Fatalf("expected %s but got %v", errLabelTooLong, err)
+	}
+}
+
+func TestWriteMethodsExist(t *testing.T) {
+	service := reflect.TypeOf(&Service{})
+	for _, method := range WriteMethods {
+		name := strings.TrimPrefix(method, "avm.")
+		name = strings.ToUpper(name[:1]) + name[1:]
+		if _, exists := service.MethodByName(name); !exists {
+			t.Fatalf("%s isn't a method of the service", method)
+		}
+	}
+}
+
+func TestServiceArgsRoundTrip(t *testing.T) {
+	json.TestArgsRoundTrip(t, &Service{})
+	json.TestArgsRoundTrip(t, &StaticService{})
+}
+
+func TestPreviewTxID(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedTxBytes, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	unsignedReply := PreviewTxIDReply{}
+	if err := s.PreviewTxID(nil, &PreviewTxIDArgs{Tx: formatting.CB58{Bytes: unsignedTxBytes}}, &unsignedReply); err != nil {
+		t.Fatal(err)
+	}
+	if unsignedReply.Signed {
+		t.Fatalf("a tx without credentials shouldn't be reported as signed")
+	}
+	if !bytes.Equal(unsignedReply.UnsignedTxHash.Bytes, hashing.ComputeHash256(unsignedBytes)) {
+		t.Fatalf("wrong digest of the unsigned tx")
+	}
+
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+	tx.Creds = append(tx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+	signedTxBytes, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedReply := PreviewTxIDReply{}
+	if err := s.PreviewTxID(nil, &PreviewTxIDArgs{Tx: formatting.CB58{Bytes: signedTxBytes}}, &signedReply); err != nil {
+		t.Fatal(err)
+	}
+	if !signedReply.Signed {
+		t.Fatalf("a tx with a credential for each input should be reported as signed")
+	}
+	if !bytes.Equal(signedReply.UnsignedTxHash.Bytes, unsignedReply.UnsignedTxHash.Bytes) {
+		t.Fatalf("signing a tx shouldn't change the digest its signers sign")
+	}
+
+	issueReply := IssueTxReply{}
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: formatting.CB58{Bytes: signedTxBytes}}, &issueReply); err != nil {
+		t.Fatal(err)
+	}
+	if !issueReply.TxID.Equals(signedReply.TxID) {
+		t.Fatalf("previewed tx ID %s but the tx was issued as %s", signedReply.TxID, issueReply.TxID)
+	}
+
+	if err := s.PreviewTxID(nil, &PreviewTxIDArgs{Tx: formatting.CB58{Bytes: []byte{0}}}, &PreviewTxIDReply{}); err == nil {
+		t.Fatalf("shouldn't have previewed the ID of malformed bytes")
+	}
+}
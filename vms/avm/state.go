@@ -174,3 +174,42 @@ func (s *state) SetIDs(id ids.ID, idSlice []ids.ID) error {
 
 	return s.vm.db.Put(id.Bytes(), bytes)
 }
+
+// Uint64 returns a uint64 from storage
+func (s *state) Uint64(id ids.ID) (uint64, error) {
+	if valueIntf, found := s.c.Get(id); found {
+		if value, ok := valueIntf.(uint64); ok {
+			return value, nil
+		}
+		return 0, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var value uint64
+	if err := s.vm.codec.Unmarshal(bytes, &value); err != nil {
+		return 0, err
+	}
+
+	s.c.Put(id, value)
+	return value, nil
+}
+
+// SetUint64 saves a uint64 to the database.
+func (s *state) SetUint64(id ids.ID, value uint64) error {
+	if value == 0 {
+		s.c.Evict(id)
+		return s.vm.db.Delete(id.Bytes())
+	}
+
+	s.c.Put(id, value)
+
+	bytes, err := s.vm.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.vm.db.Put(id.Bytes(), bytes)
+}
@@ -26,6 +26,13 @@ type StaticService struct{}
 // BuildGenesisArgs are arguments for BuildGenesis
 type BuildGenesisArgs struct {
 	GenesisData map[string]AssetDefinition `json:"genesisData"`
+
+	// Alias, in [GenesisData], of the asset that fees are paid in. If it is
+	// empty, txs don't pay fees.
+	FeeAsset string `json:"feeAsset"`
+
+	// Amount of the fee asset that every tx must burn
+	TxFee cjson.Uint64 `json:"txFee"`
 }
 
 // AssetDefinition ...
@@ -36,6 +43,52 @@ type AssetDefinition struct {
 	InitialState map[string][]interface{} `json:"initialState"`
 }
 
+// GenesisHolder is an initial holder of a fixed cap asset. The holder's output
+// is owned by [Address] or, if [Addresses] is given, by [Addresses], any
+// [Threshold] of which may spend it. If [Threshold] is 0, it is 1. The output
+// can't be spent before the unix time [Locktime], which allows vesting
+// schedules to be encoded at genesis.
+type GenesisHolder struct {
+	Amount    cjson.Uint64 `json:"amount"`
+	Address   string       `json:"address"`
+	Addresses []string     `json:"addresses"`
+	Threshold cjson.Uint32 `json:"threshold"`
+	Locktime  cjson.Uint64 `json:"locktime"`
+}
+
+// output returns the output that pays [holder] its initial amount
+func (holder *GenesisHolder) output() (*secp256k1fx.TransferOutput, error) {
+	addresses := holder.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{holder.Address}
+	}
+	threshold := uint32(holder.Threshold)
+	if threshold == 0 {
+		threshold = 1
+	}
+
+	out := &secp256k1fx.TransferOutput{
+		Amt:      uint64(holder.Amount),
+		Locktime: uint64(holder.Locktime),
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: threshold,
+		},
+	}
+	for _, address := range addresses {
+		cb58 := formatting.CB58{}
+		if err := cb58.FromString(address); err != nil {
+			return nil, err
+		}
+		addr, err := ids.ToShortID(cb58.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		out.Addrs = append(out.Addrs, addr)
+	}
+	out.Sort()
+	return out, out.OutputOwners.Verify()
+}
+
 // BuildGenesisReply is the reply from BuildGenesis
 type BuildGenesisReply struct {
 	Bytes formatting.CB58 `json:"bytes"`
@@ -78,25 +131,15 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 					if err != nil {
 						return err
 					}
-					holder := Holder{}
+					holder := GenesisHolder{}
 					if err := json.Unmarshal(b, &holder); err != nil {
 						return err
 					}
-					cb58 := formatting.CB58{}
-					if err := cb58.FromString(holder.Address); err != nil {
-						return err
-					}
-					addr, err := ids.ToShortID(cb58.Bytes)
+					out, err := holder.output()
 					if err != nil {
 						return err
 					}
-					initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-						Amt: uint64(holder.Amount),
-						OutputOwners: secp256k1fx.OutputOwners{
-							Threshold: 1,
-							Addrs:     []ids.ShortID{addr},
-						},
-					})
+					initialState.Outs = append(initialState.Outs, out)
 				}
 				initialState.Sort(c)
 				asset.States = append(asset.States, initialState)
@@ -145,7 +188,21 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 	}
 	g.Sort()
 
-	b, err := c.Marshal(&g)
+	// Genesis data of a chain without fees keeps the layout that predates fees
+	var genesis interface{} = &g
+	if args.FeeAsset != "" || args.TxFee != 0 {
+		feeGenesis := &FeeGenesis{
+			Genesis:  g,
+			FeeAsset: args.FeeAsset,
+			TxFee:    uint64(args.TxFee),
+		}
+		if err := feeGenesis.verifyFees(); err != nil {
+			return err
+		}
+		genesis = feeGenesis
+	}
+
+	b, err := c.Marshal(genesis)
 	if err != nil {
 		return err
 	}
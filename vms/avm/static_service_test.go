@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 func TestBuildGenesis(t *testing.T) {
@@ -96,3 +97,60 @@ func TestBuildGenesis(t *testing.T) {
 		)
 	}
 }
+
+func TestBuildGenesisVestingHolder(t *testing.T) {
+	ss := StaticService{}
+
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset1": AssetDefinition{
+			Name:   "myVestedAsset",
+			Symbol: "MVA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					GenesisHolder{
+						Amount: 100000,
+						Addresses: []string{
+							"A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+							"6mxBGnjGDCKgkVe7yfrmvMA7xE7qCv3vv",
+						},
+						Threshold: 2,
+						Locktime:  1600000000,
+					},
+				},
+			},
+		},
+	}}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := GetFirstTxFromGenesisTest(reply.Bytes.Bytes, t)
+	utxos := tx.UTXOs()
+	if len(utxos) != 1 {
+		t.Fatalf("Expected %d genesis UTXO(s) but got %d", 1, len(utxos))
+	}
+	out, ok := utxos[0].Out.(*secp256k1fx.TransferOutput)
+	switch {
+	case !ok:
+		t.Fatalf("Expected a transfer output but got %T", utxos[0].Out)
+	case out.Amt != 100000:
+		t.Fatalf("Expected amount %d but got %d", 100000, out.Amt)
+	case out.Locktime != 1600000000:
+		t.Fatalf("Expected locktime %d but got %d", 1600000000, out.Locktime)
+	case out.Threshold != 2:
+		t.Fatalf("Expected threshold %d but got %d", 2, out.Threshold)
+	case len(out.Addrs) != 2:
+		t.Fatalf("Expected %d owners but got %d", 2, len(out.Addrs))
+	}
+
+	// A threshold that the owners can't meet is invalid
+	args.GenesisData["asset1"].InitialState["fixedCap"][0] = GenesisHolder{
+		Amount:    100000,
+		Address:   "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+		Threshold: 2,
+	}
+	if err := ss.BuildGenesis(nil, &args, &reply); err == nil {
+		t.Fatalf("Should have errored due to an unspendable output")
+	}
+}
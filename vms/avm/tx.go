@@ -5,11 +5,15 @@ package avm
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ava-labs/gecko/ids"
 
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 var (
@@ -49,6 +53,33 @@ type Tx struct {
 // specified UTXOs. The returned array should not be modified.
 func (t *Tx) Credentials() []*Credential { return t.Creds }
 
+// SignSECP256K1Fx appends a secp256k1fx credential to [t] for each element of
+// [signers], signed by the keys of that element. [c] is the codec of the chain
+// [t] is issued on.
+func (t *Tx) SignSECP256K1Fx(c codec.Codec, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	unsignedBytes, err := c.Marshal(&t.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	for _, credKeys := range signers {
+		cred := &secp256k1fx.Credential{}
+		for _, key := range credKeys {
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return fmt.Errorf("problem creating transaction: %w", err)
+			}
+			fixedSig := [crypto.SECP256K1RSigLen]byte{}
+			copy(fixedSig[:], sig)
+
+			cred.Sigs = append(cred.Sigs, fixedSig)
+		}
+		t.Creds = append(t.Creds, &Credential{Cred: cred})
+	}
+	return nil
+}
+
 // SyntacticVerify verifies that this transaction is well-formed.
 func (t *Tx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
 	switch {
@@ -66,8 +97,11 @@ func (t *Tx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error
 		}
 	}
 
-	numInputs := len(t.InputUTXOs())
-	if numInputs != len(t.Creds) {
+	numCreds := len(t.InputUTXOs())
+	if _, ok := t.UnsignedTx.(*AliasAssetTx); ok {
+		numCreds++ // The credential of the asset's creator
+	}
+	if numCreds != len(t.Creds) {
 		return errWrongNumberOfCredentials
 	}
 	return nil
@@ -78,6 +112,9 @@ func (t *Tx) SemanticVerify(vm *VM, uTx *UniqueTx) error {
 	if t == nil {
 		return errNilTx
 	}
+	if err := vm.verifyBurn(t.UnsignedTx); err != nil {
+		return err
+	}
 
 	return t.UnsignedTx.SemanticVerify(vm, uTx, t.Creds)
 }
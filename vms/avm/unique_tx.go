@@ -93,13 +93,33 @@ func (tx *UniqueTx) ID() ids.ID { return tx.txID }
 
 // Accept is called when the transaction was finalized as accepted by consensus
 func (tx *UniqueTx) Accept() {
+	// The tx is decided even if applying it fails below, so the inputs it
+	// consumed must not stay locked
+	defer tx.vm.releaseInputs(tx)
+
 	if err := tx.setStatus(choices.Accepted); err != nil {
 		tx.vm.ctx.Log.Error("Failed to accept tx %s due to %s", tx.txID, err)
 		return
 	}
+	now := tx.vm.clock.Unix()
+	if err := tx.vm.state.SetAcceptedAt(tx.txID, now); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record when tx %s was accepted due to %s", tx.txID, err)
+		return
+	}
+
+	addresses, err := tx.addresses()
+	if err != nil {
+		tx.vm.ctx.Log.Error("Failed to get the addresses of tx %s due to %s", tx.txID, err)
+		return
+	}
+	if err := tx.vm.state.markActive(addresses, now); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record the activity of tx %s due to %s", tx.txID, err)
+		return
+	}
 
 	// Remove spent utxos
-	for _, utxoID := range tx.InputIDs().List() {
+	for _, utxo := range tx.InputUTXOs() {
+		utxoID := utxo.InputID()
 		if err := tx.vm.state.SpendUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return
@@ -114,20 +134,56 @@ func (tx *UniqueTx) Accept() {
 		}
 	}
 
+	if aliasTx, ok := tx.t.tx.UnsignedTx.(*AliasAssetTx); ok {
+		if err := tx.vm.registerAlias(aliasTx); err != nil {
+			tx.vm.ctx.Log.Error("Failed to register alias %s due to %s", aliasTx.Alias, err)
+			return
+		}
+	}
+
 	txID := tx.ID()
 	tx.vm.ctx.Log.Verbo("Accepting Tx: %s", txID)
 
+	tx.vm.untrackRegossip(txID)
+	if err := tx.vm.removePendingTx(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to remove pending tx %s due to %s", txID, err)
+	}
+
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", tx.txID, err)
 	}
 
 	tx.vm.pubsub.Publish("accepted", txID)
+	tx.vm.notifyWatchers(txID, addresses)
 
 	tx.t.deps = nil // Needed to prevent a memory leak
 }
 
+// addresses returns the addresses this tx spends from and pays to. It must be
+// called before the spent utxos are removed.
+func (tx *UniqueTx) addresses() ([][]byte, error) {
+	utxos := tx.UTXOs()
+	for _, in := range tx.InputUTXOs() {
+		utxo, err := tx.vm.state.UTXO(in.InputID())
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	addresses := [][]byte(nil)
+	for _, utxo := range utxos {
+		if addressable, ok := utxo.Out.(FxAddressable); ok {
+			addresses = append(addresses, addressable.Addresses()...)
+		}
+	}
+	return addresses, nil
+}
+
 // Reject is called when the transaction was finalized as rejected by consensus
 func (tx *UniqueTx) Reject() {
+	defer tx.vm.releaseInputs(tx)
+
 	if err := tx.setStatus(choices.Rejected); err != nil {
 		tx.vm.ctx.Log.Error("Failed to reject tx %s due to %s", tx.txID, err)
 		return
@@ -136,6 +192,11 @@ func (tx *UniqueTx) Reject() {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Debug("Rejecting Tx: %s", txID)
 
+	tx.vm.untrackRegossip(txID)
+	if err := tx.vm.removePendingTx(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to remove pending tx %s due to %s", txID, err)
+	}
+
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit reject %s due to %s", tx.txID, err)
 	}
@@ -181,7 +242,8 @@ func (tx *UniqueTx) Dependencies() []snowstorm.Tx {
 	return tx.t.deps
 }
 
-// InputIDs returns the set of utxoIDs this transaction consumes
+// InputIDs returns the set of utxoIDs this transaction consumes. A tx that
+// registers an alias also consumes the ID of the alias.
 func (tx *UniqueTx) InputIDs() ids.Set {
 	tx.refresh()
 	if tx.t.tx == nil || tx.t.inputs.Len() != 0 {
@@ -191,6 +253,9 @@ func (tx *UniqueTx) InputIDs() ids.Set {
 	for _, utxo := range tx.InputUTXOs() {
 		tx.t.inputs.Add(utxo.InputID())
 	}
+	if aliasTx, ok := tx.t.tx.UnsignedTx.(*AliasAssetTx); ok {
+		tx.t.inputs.Add(aliasTx.AliasID())
+	}
 	return tx.t.inputs
 }
 
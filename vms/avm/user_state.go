@@ -12,6 +12,10 @@ import (
 
 var addresses = ids.Empty
 
+// labelsPrefix prefixes the hashes of addresses to get the keys their labels
+// are stored under
+const labelsPrefix = 0
+
 type userState struct{ vm *VM }
 
 func (s *userState) SetAddresses(db database.Database, addrs []ids.ID) error {
@@ -34,6 +38,35 @@ func (s *userState) Addresses(db database.Database) ([]ids.ID, error) {
 	return addresses, nil
 }
 
+// SetLabels replaces the labels of the address whose hash is [address]. No
+// labels removes the address's labels.
+func (s *userState) SetLabels(db database.Database, address ids.ID, labels []string) error {
+	key := address.Prefix(labelsPrefix).Bytes()
+	if len(labels) == 0 {
+		return db.Delete(key)
+	}
+	bytes, err := s.vm.codec.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	return db.Put(key, bytes)
+}
+
+// Labels returns the labels of the address whose hash is [address]
+func (s *userState) Labels(db database.Database, address ids.ID) ([]string, error) {
+	bytes, err := db.Get(address.Prefix(labelsPrefix).Bytes())
+	if err == database.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	labels := []string(nil)
+	if err := s.vm.codec.Unmarshal(bytes, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
 func (s *userState) SetKey(db database.Database, sk *crypto.PrivateKeySECP256K1R) error {
 	return db.Put(hashing.ComputeHash256(sk.PublicKey().Address().Bytes()), sk.Bytes())
 }
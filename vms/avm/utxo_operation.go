@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNilFxOperation            = errors.New("nil fx operation is not valid")
+	errNotSortedAndUniqueUTXOIDs = errors.New("utxo IDs not sorted and unique")
+)
+
+// UTXOOperation is an operation, defined by a feature extension, on the UTXOs
+// of an asset. The operation consumes the UTXOs [UTXOIDs] references.
+type UTXOOperation struct {
+	Asset `serialize:"true"`
+
+	UTXOIDs []*UTXOID   `serialize:"true"`
+	Op      FxOperation `serialize:"true"`
+}
+
+// Verify implements the verify.Verifiable interface
+func (op *UTXOOperation) Verify(c codec.Codec) error {
+	switch {
+	case op == nil:
+		return errNilOperation
+	case op.Op == nil:
+		return errNilFxOperation
+	case len(op.UTXOIDs) == 0 && len(op.Op.Outs()) == 0:
+		return errEmptyOperation
+	case !isSortedAndUniqueUTXOIDs(op.UTXOIDs):
+		return errNotSortedAndUniqueUTXOIDs
+	}
+
+	for _, utxoID := range op.UTXOIDs {
+		if err := utxoID.Verify(); err != nil {
+			return err
+		}
+	}
+	return verify.All(&op.Asset, op.Op)
+}
+
+type innerSortUTXOOperation struct {
+	ops   []*UTXOOperation
+	codec codec.Codec
+}
+
+func (ops *innerSortUTXOOperation) Less(i, j int) bool {
+	iOp := ops.ops[i]
+	jOp := ops.ops[j]
+
+	iBytes, err := ops.codec.Marshal(iOp)
+	if err != nil {
+		return false
+	}
+	jBytes, err := ops.codec.Marshal(jOp)
+	if err != nil {
+		return false
+	}
+	return bytes.Compare(iBytes, jBytes) == -1
+}
+func (ops *innerSortUTXOOperation) Len() int      { return len(ops.ops) }
+func (ops *innerSortUTXOOperation) Swap(i, j int) { o := ops.ops; o[j], o[i] = o[i], o[j] }
+
+func sortUTXOOperations(ops []*UTXOOperation, c codec.Codec) {
+	sort.Sort(&innerSortUTXOOperation{ops: ops, codec: c})
+}
+func isSortedAndUniqueUTXOOperations(ops []*UTXOOperation, c codec.Codec) bool {
+	return utils.IsSortedAndUnique(&innerSortUTXOOperation{ops: ops, codec: c})
+}
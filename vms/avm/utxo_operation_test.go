@@ -0,0 +1,207 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+func TestUTXOOperationVerifyNil(t *testing.T) {
+	c := codec.NewDefault()
+	op := (*UTXOOperation)(nil)
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to nil operation")
+	}
+}
+
+func TestUTXOOperationVerifyNilFxOperation(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+		},
+	}
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to nil fx operation")
+	}
+}
+
+func TestUTXOOperationVerifyEmpty(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		Op: &testOperable{},
+	}
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to empty operation")
+	}
+}
+
+func TestUTXOOperationVerifyUTXOIDsNotSorted(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 1,
+			},
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+		},
+		Op: &testOperable{},
+	}
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to unsorted utxoIDs")
+	}
+}
+
+func TestUTXOOperationVerifyUTXOIDsNotUnique(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+		},
+		Op: &testOperable{},
+	}
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to duplicated utxoIDs")
+	}
+}
+
+func TestUTXOOperationVerifyInvalidFxOperation(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+		},
+		Op: &testOperable{testVerifiable: testVerifiable{err: errors.New("")}},
+	}
+	if err := op.Verify(c); err == nil {
+		t.Fatalf("Should have errored due to an invalid fx operation")
+	}
+}
+
+func TestUTXOOperationVerify(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 0,
+			},
+		},
+		Op: &testOperable{},
+	}
+	if err := op.Verify(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUTXOOperationVerifyOnlyOutputs(t *testing.T) {
+	c := codec.NewDefault()
+	op := &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		Op: &testOperable{
+			Outputs: []verify.Verifiable{
+				&testVerifiable{},
+			},
+		},
+	}
+	if err := op.Verify(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUTXOOperationSorting(t *testing.T) {
+	c := codec.NewDefault()
+	c.RegisterType(&testOperable{})
+
+	ops := []*UTXOOperation{
+		&UTXOOperation{
+			Asset: Asset{
+				ID: ids.Empty,
+			},
+			UTXOIDs: []*UTXOID{
+				&UTXOID{
+					TxID:        ids.Empty,
+					OutputIndex: 1,
+				},
+			},
+			Op: &testOperable{},
+		},
+		&UTXOOperation{
+			Asset: Asset{
+				ID: ids.Empty,
+			},
+			UTXOIDs: []*UTXOID{
+				&UTXOID{
+					TxID:        ids.Empty,
+					OutputIndex: 0,
+				},
+			},
+			Op: &testOperable{},
+		},
+	}
+	if isSortedAndUniqueUTXOOperations(ops, c) {
+		t.Fatalf("Shouldn't be sorted")
+	}
+	sortUTXOOperations(ops, c)
+	if !isSortedAndUniqueUTXOOperations(ops, c) {
+		t.Fatalf("Should be sorted")
+	}
+	ops = append(ops, &UTXOOperation{
+		Asset: Asset{
+			ID: ids.Empty,
+		},
+		UTXOIDs: []*UTXOID{
+			&UTXOID{
+				TxID:        ids.Empty,
+				OutputIndex: 1,
+			},
+		},
+		Op: &testOperable{},
+	})
+	if isSortedAndUniqueUTXOOperations(ops, c) {
+		t.Fatalf("Shouldn't be unique")
+	}
+}
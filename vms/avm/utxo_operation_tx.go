@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+// UTXOOperationTx is a transaction that performs operations, defined by
+// feature extensions, on the UTXOs of assets. Each UTXO an operation consumes
+// has a credential, after the credentials of the transaction's inputs.
+type UTXOOperationTx struct {
+	BaseTx `serialize:"true"`
+	Ops    []*UTXOOperation `serialize:"true"`
+}
+
+// Operations track which ops this transaction is performing. The returned array
+// should not be modified.
+func (t *UTXOOperationTx) Operations() []*UTXOOperation { return t.Ops }
+
+// InputUTXOs track which UTXOs this transaction is consuming.
+func (t *UTXOOperationTx) InputUTXOs() []*UTXOID {
+	utxos := t.BaseTx.InputUTXOs()
+	for _, op := range t.Ops {
+		utxos = append(utxos, op.UTXOIDs...)
+	}
+	return utxos
+}
+
+// AssetIDs returns the IDs of the assets this transaction depends on
+func (t *UTXOOperationTx) AssetIDs() ids.Set {
+	assets := t.BaseTx.AssetIDs()
+	for _, op := range t.Ops {
+		assets.Add(op.AssetID())
+	}
+	return assets
+}
+
+// UTXOs returns the UTXOs transaction is producing.
+func (t *UTXOOperationTx) UTXOs() []*UTXO {
+	txID := t.ID()
+	utxos := t.BaseTx.UTXOs()
+
+	for _, op := range t.Ops {
+		asset := op.AssetID()
+		for _, out := range op.Op.Outs() {
+			utxos = append(utxos, &UTXO{
+				UTXOID: UTXOID{
+					TxID:        txID,
+					OutputIndex: uint32(len(utxos)),
+				},
+				Asset: Asset{
+					ID: asset,
+				},
+				Out: out,
+			})
+		}
+	}
+
+	return utxos
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *UTXOOperationTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	}
+
+	if err := t.BaseTx.SyntacticVerify(ctx, c, numFxs); err != nil {
+		return err
+	}
+
+	inputs := ids.Set{}
+	for _, in := range t.Ins {
+		inputs.Add(in.InputID())
+	}
+
+	for _, op := range t.Ops {
+		if err := op.Verify(c); err != nil {
+			return err
+		}
+		for _, utxoID := range op.UTXOIDs {
+			inputID := utxoID.InputID()
+			if inputs.Contains(inputID) {
+				return errDoubleSpend
+			}
+			inputs.Add(inputID)
+		}
+	}
+	if !isSortedAndUniqueUTXOOperations(t.Ops, c) {
+		return errOperationsNotSortedUnique
+	}
+	return nil
+}
+
+// SemanticVerify that this transaction is well-formed.
+func (t *UTXOOperationTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	if err := t.BaseTx.SemanticVerify(vm, uTx, creds); err != nil {
+		return err
+	}
+	offset := len(t.BaseTx.Ins)
+	for _, op := range t.Ops {
+		opAssetID := op.AssetID()
+
+		utxos := []interface{}{}
+		credIntfs := []interface{}{}
+		for i, utxoID := range op.UTXOIDs {
+			credIntfs = append(credIntfs, creds[i+offset].Cred)
+
+			utxo, err := vm.state.UTXO(utxoID.InputID())
+			if err != nil {
+				inputTx, inputIndex := utxoID.InputSource()
+				parent := UniqueTx{
+					vm:   vm,
+					txID: inputTx,
+				}
+
+				if err := parent.Verify(); err != nil {
+					return errMissingUTXO
+				} else if status := parent.Status(); status.Decided() {
+					return errMissingUTXO
+				}
+
+				parentUTXOs := parent.UTXOs()
+				if uint32(len(parentUTXOs)) <= inputIndex || int(inputIndex) < 0 {
+					return errInvalidUTXO
+				}
+				utxo = parentUTXOs[int(inputIndex)]
+			}
+
+			utxoAssetID := utxo.AssetID()
+			if !utxoAssetID.Equals(opAssetID) {
+				return errAssetIDMismatch
+			}
+			utxos = append(utxos, utxo.Out)
+		}
+		offset += len(op.UTXOIDs)
+
+		fxIndex, err := vm.getFx(op.Op)
+		if err != nil {
+			return err
+		}
+		fx, ok := vm.fxs[fxIndex].Fx.(OperationFx)
+		if !ok {
+			return errIncompatibleFx
+		}
+
+		if !vm.verifyFxUsage(fxIndex, opAssetID) {
+			return errIncompatibleFx
+		}
+
+		if err := fx.VerifyFxOperation(uTx, op.Op, credIntfs, utxos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
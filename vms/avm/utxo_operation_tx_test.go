@@ -0,0 +1,399 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// newSECP256K1Codec returns the codec of a chain run with the secp256k1fx
+func newSECP256K1Codec(t *testing.T) codec.Codec {
+	c, err := NewCodec(snow.DefaultLimits.MaxTxSize, []*common.Fx{&common.Fx{
+		ID: ids.Empty,
+		Fx: &secp256k1fx.Fx{},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOperationTxSerializedBeforeUTXOOperations(t *testing.T) {
+	// An OperationTx that mints, serialized before UTXOOperationTxs existed
+	serialized := []byte{
+		// txID:
+		0x00, 0x00, 0x00, 0x02,
+		// networkID:
+		0x00, 0x00, 0xa8, 0x66,
+		// chainID:
+		0x05, 0x04, 0x03, 0x02, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// number of outs:
+		0x00, 0x00, 0x00, 0x00,
+		// number of inputs:
+		0x00, 0x00, 0x00, 0x00,
+		// number of operations:
+		0x00, 0x00, 0x00, 0x01,
+		// operation[0]:
+		// assetID:
+		0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// number of inputs:
+		0x00, 0x00, 0x00, 0x01,
+		// input[0]:
+		// txID:
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// output index:
+		0x00, 0x00, 0x00, 0x01,
+		// fxID:
+		0x00, 0x00, 0x00, 0x05,
+		// secp256k1 Mint Input:
+		// number of signature indices:
+		0x00, 0x00, 0x00, 0x01,
+		// signature index[0]:
+		0x00, 0x00, 0x00, 0x00,
+		// number of outputs:
+		0x00, 0x00, 0x00, 0x02,
+		// output[0]:
+		// fxID:
+		0x00, 0x00, 0x00, 0x03,
+		// secp256k1 Mint Output:
+		// threshold:
+		0x00, 0x00, 0x00, 0x01,
+		// number of addresses:
+		0x00, 0x00, 0x00, 0x01,
+		// address[0]:
+		0xfc, 0xed, 0xa8, 0xf9, 0x0f, 0xcb, 0x5d, 0x30,
+		0x61, 0x4b, 0x99, 0xd7, 0x9f, 0xc4, 0xba, 0xa2,
+		0x93, 0x07, 0x76, 0x26,
+		// output[1]:
+		// fxID:
+		0x00, 0x00, 0x00, 0x04,
+		// secp256k1 Transferable Output:
+		// amount:
+		0x00, 0x00, 0x12, 0x30, 0x9c, 0xe5, 0x40, 0x00,
+		// locktime:
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// threshold:
+		0x00, 0x00, 0x00, 0x01,
+		// number of addresses:
+		0x00, 0x00, 0x00, 0x01,
+		// address[0]:
+		0xfc, 0xed, 0xa8, 0xf9, 0x0f, 0xcb, 0x5d, 0x30,
+		0x61, 0x4b, 0x99, 0xd7, 0x9f, 0xc4, 0xba, 0xa2,
+		0x93, 0x07, 0x76, 0x26,
+		// number of credentials:
+		0x00, 0x00, 0x00, 0x01,
+		// credential[0]:
+		// fxID:
+		0x00, 0x00, 0x00, 0x07,
+		// secp256k1 Credential:
+		// number of signatures:
+		0x00, 0x00, 0x00, 0x01,
+		// signature[0]:
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00,
+	}
+
+	c := newSECP256K1Codec(t)
+	tx := Tx{}
+	if err := c.Unmarshal(serialized, &tx); err != nil {
+		t.Fatal(err)
+	}
+	opTx, ok := tx.UnsignedTx.(*OperationTx)
+	switch {
+	case !ok:
+		t.Fatalf("should have parsed an OperationTx but parsed %T", tx.UnsignedTx)
+	case len(opTx.Ops) != 1 || len(opTx.Ops[0].Ins) != 1 || len(opTx.Ops[0].Outs) != 2:
+		t.Fatalf("should have parsed the operation's input and outputs")
+	}
+	if _, ok := opTx.Ops[0].Ins[0].In.(*secp256k1fx.MintInput); !ok {
+		t.Fatalf("should have parsed a mint input but parsed %T", opTx.Ops[0].Ins[0].In)
+	}
+	tx.Initialize(serialized)
+	if err := tx.SyntacticVerify(ctx, c, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.Marshal(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(serialized, b) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", serialized, b)
+	}
+}
+
+func TestUTXOOperationTxTypeIDs(t *testing.T) {
+	c := newSECP256K1Codec(t)
+
+	// Registering UTXOOperationTxs and their operations doesn't change the type
+	// IDs of the types that were registered before them
+	for typeID, unsignedTx := range []UnsignedTx{
+		&BaseTx{BCID: chainID},
+		&CreateAssetTx{BaseTx: BaseTx{BCID: chainID}},
+		&OperationTx{BaseTx: BaseTx{BCID: chainID}},
+	} {
+		b, err := c.Marshal(&Tx{UnsignedTx: unsignedTx})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b[:4], []byte{0x00, 0x00, 0x00, byte(typeID)}) {
+			t.Fatalf("%T should have the type ID %d but has 0x%x", unsignedTx, typeID, b[:4])
+		}
+	}
+	for typeID, unsignedTx := range map[byte]UnsignedTx{
+		0x08: &AliasAssetTx{BaseTx: BaseTx{BCID: chainID}, AssetID: asset},
+		0x09: &UTXOOperationTx{BaseTx: BaseTx{BCID: chainID}},
+	} {
+		b, err := c.Marshal(&Tx{UnsignedTx: unsignedTx})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b[:4], []byte{0x00, 0x00, 0x00, typeID}) {
+			t.Fatalf("%T should have the type ID %d but has 0x%x", unsignedTx, typeID, b[:4])
+		}
+	}
+}
+
+func TestUTXOOperationTxSerialization(t *testing.T) {
+	expected := []byte{
+		// txID:
+		0x00, 0x00, 0x00, 0x09,
+		// networkID:
+		0x00, 0x00, 0xa8, 0x66,
+		// chainID:
+		0x05, 0x04, 0x03, 0x02, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// number of outs:
+		0x00, 0x00, 0x00, 0x00,
+		// number of inputs:
+		0x00, 0x00, 0x00, 0x00,
+		// number of operations:
+		0x00, 0x00, 0x00, 0x01,
+		// operation[0]:
+		// assetID:
+		0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// number of utxoIDs:
+		0x00, 0x00, 0x00, 0x01,
+		// utxoID[0]:
+		// txID:
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// output index:
+		0x00, 0x00, 0x00, 0x01,
+		// fxID:
+		0x00, 0x00, 0x00, 0x0a,
+		// secp256k1 Mint Operation:
+		// number of signature indices:
+		0x00, 0x00, 0x00, 0x01,
+		// signature index[0]:
+		0x00, 0x00, 0x00, 0x00,
+		// mint output:
+		// threshold:
+		0x00, 0x00, 0x00, 0x01,
+		// number of addresses:
+		0x00, 0x00, 0x00, 0x01,
+		// address[0]:
+		0xfc, 0xed, 0xa8, 0xf9, 0x0f, 0xcb, 0x5d, 0x30,
+		0x61, 0x4b, 0x99, 0xd7, 0x9f, 0xc4, 0xba, 0xa2,
+		0x93, 0x07, 0x76, 0x26,
+		// transfer output:
+		// amount:
+		0x00, 0x00, 0x12, 0x30, 0x9c, 0xe5, 0x40, 0x00,
+		// locktime:
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// threshold:
+		0x00, 0x00, 0x00, 0x01,
+		// number of addresses:
+		0x00, 0x00, 0x00, 0x01,
+		// address[0]:
+		0xfc, 0xed, 0xa8, 0xf9, 0x0f, 0xcb, 0x5d, 0x30,
+		0x61, 0x4b, 0x99, 0xd7, 0x9f, 0xc4, 0xba, 0xa2,
+		0x93, 0x07, 0x76, 0x26,
+		// number of credentials:
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	tx := &Tx{UnsignedTx: &UTXOOperationTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+		},
+		Ops: []*UTXOOperation{
+			&UTXOOperation{
+				Asset: Asset{
+					ID: asset,
+				},
+				UTXOIDs: []*UTXOID{
+					&UTXOID{
+						TxID:        ids.Empty,
+						OutputIndex: 1,
+					},
+				},
+				Op: &secp256k1fx.MintOperation{
+					MintInput: secp256k1fx.MintInput{
+						Input: secp256k1fx.Input{
+							SigIndices: []uint32{0},
+						},
+					},
+					MintOutput: secp256k1fx.MintOutput{
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+						},
+					},
+					TransferOutput: secp256k1fx.TransferOutput{
+						Amt: 20 * units.KiloAva,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	c := newSECP256K1Codec(t)
+	b, err := c.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, b) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, b)
+	}
+
+	parsed := Tx{}
+	if err := c.Unmarshal(b, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.UnsignedTx.(*UTXOOperationTx); !ok {
+		t.Fatalf("should have parsed a UTXOOperationTx but parsed %T", parsed.UnsignedTx)
+	}
+}
+
+func TestIssueUTXOOperationTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	assetID, err := vm.Lookup("asset2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := vm.GetUTXOs(context.Background(), addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mint output of asset2 that [addr] can spend alone
+	var mintUTXO *UTXO
+	for _, utxo := range utxos {
+		if out, ok := utxo.Out.(*secp256k1fx.MintOutput); ok && utxo.AssetID().Equals(assetID) && out.Threshold == 1 {
+			mintUTXO = utxo
+		}
+	}
+	if mintUTXO == nil {
+		t.Fatalf("should have found the mint output of asset2")
+	}
+	mintOut := mintUTXO.Out.(*secp256k1fx.MintOutput)
+	sigIndex := uint32(0)
+	for i, minter := range mintOut.Addrs {
+		if minter.Equals(addr) {
+			sigIndex = uint32(i)
+		}
+	}
+
+	// Returns the bytes of a signed tx that mints asset2, replacing the mint
+	// output with one owned by [owners]
+	newTx := func(owners secp256k1fx.OutputOwners) []byte {
+		tx := &Tx{UnsignedTx: &UTXOOperationTx{
+			BaseTx: BaseTx{
+				NetID: networkID,
+				BCID:  chainID,
+			},
+			Ops: []*UTXOOperation{
+				&UTXOOperation{
+					Asset:   Asset{ID: assetID},
+					UTXOIDs: []*UTXOID{&mintUTXO.UTXOID},
+					Op: &secp256k1fx.MintOperation{
+						MintInput: secp256k1fx.MintInput{
+							Input: secp256k1fx.Input{
+								SigIndices: []uint32{sigIndex},
+							},
+						},
+						MintOutput: secp256k1fx.MintOutput{
+							OutputOwners: owners,
+						},
+						TransferOutput: secp256k1fx.TransferOutput{
+							Amt: 1,
+							OutputOwners: secp256k1fx.OutputOwners{
+								Threshold: 1,
+								Addrs:     []ids.ShortID{addr},
+							},
+						},
+					},
+				},
+			},
+		}}
+		if err := tx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+			t.Fatal(err)
+		}
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	wrongOwners := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{keys[1].PublicKey().Address()},
+	}
+	if _, err := vm.IssueTx(newTx(wrongOwners)); err == nil {
+		t.Fatalf("shouldn't have issued a mint that changes the owners of the mint output")
+	}
+	if _, err := vm.IssueTx(newTx(mintOut.OutputOwners)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,6 +3,10 @@
 
 package avm
 
+import (
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
 type testVerifiable struct{ err error }
 
 func (v *testVerifiable) Verify() error { return v.err }
@@ -22,3 +26,11 @@ type testAddressable struct {
 }
 
 func (a *testAddressable) Addresses() [][]byte { return a.Addrs }
+
+type testOperable struct {
+	testVerifiable
+
+	Outputs []verify.Verifiable
+}
+
+func (o *testOperable) Outs() []verify.Verifiable { return o.Outputs }
@@ -4,6 +4,7 @@
 package avm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gorilla/rpc/v2"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
@@ -24,6 +26,8 @@ import (
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/idempotency"
+	"github.com/ava-labs/gecko/vms/components/watch"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
 )
@@ -49,6 +53,43 @@ var (
 type VM struct {
 	ids.Aliaser
 
+	// RegossipFrequency is how long a locally issued tx may remain undecided
+	// before it is re-gossiped. If it is non-positive, txs aren't re-gossiped.
+	RegossipFrequency time.Duration
+
+	// MaxRegossipFrequency caps the backoff between re-gossips of a tx. If it
+	// is non-positive, the backoff is uncapped.
+	MaxRegossipFrequency time.Duration
+
+	// BatchSize is the number of txs that are issued to consensus together. If
+	// it is non-positive, a default is used. The chain's configuration, a
+	// ChainConfig, may override it.
+	BatchSize int
+
+	// BatchTimeout is the longest a tx may wait to be batched with other txs
+	// before it is issued to consensus. If it is non-positive, a default is
+	// used. The chain's configuration, a ChainConfig, may override it.
+	BatchTimeout time.Duration
+
+	// FeeAsset is the ID, or alias, of the asset that fees are paid in. A tx's
+	// fee is the amount of this asset that it burns. If it is empty, txs don't
+	// pay fees. The chain's configuration, a ChainConfig, may override it. If
+	// the chain's genesis designates a fee asset, that asset is used instead.
+	FeeAsset string
+
+	// MinFee is the smallest fee that a tx issued to this node must pay. Txs
+	// issued to this node are issued to consensus in order of decreasing fee.
+	// Every tx must pay the fee of the chain's genesis, whatever MinFee is.
+	MinFee uint64
+
+	// Reindex causes the address index and the asset supplies to be rebuilt
+	// from the UTXO set when the VM is initialized.
+	Reindex bool
+
+	// WatchAllowedHosts are the only hosts that address watches may send
+	// callbacks to. If it's empty, callbacks may be sent to any public address.
+	WatchAllowedHosts []string
+
 	// Contains information of where this VM is executing
 	ctx *snow.Context
 
@@ -63,11 +104,30 @@ type VM struct {
 	state *prefixedState
 
 	// Transaction issuing
+	feeAssetID   ids.ID
+	requiredFee  uint64 // The fee of the chain's genesis, which every tx must burn
 	timer        *timer.Timer
 	batchTimeout time.Duration
+	batchSize    int
+	parents      int // The most parents of the chain's vertices, or 0 if not configured
+	lastFlush    time.Time
 	txs          []snowstorm.Tx
 	toEngine     chan<- common.Message
 
+	// Maps the inputs consumed by pending txs to the ID of the consuming tx
+	consumed map[[32]byte]ids.ID
+
+	// Remembers the txs issued by API calls with an idempotency key
+	issuedTokens idempotency.Tokens
+
+	// Notifies callback URLs of the accepted txs that involve their addresses
+	watcher watch.Watcher
+
+	// Transaction re-gossiping
+	regossipTimer *timer.Timer
+	regossip      map[[32]byte]*regossipTx
+	numRegossiped prometheus.Counter
+
 	baseDB database.Database
 	db     *versiondb.Database
 
@@ -107,7 +167,6 @@ func (vm *VM) Initialize(
 	vm.toEngine = toEngine
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
-	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.Aliaser.Initialize()
 
 	vm.pubsub = cjson.NewPubSubServer(ctx)
@@ -128,44 +187,23 @@ func (vm *VM) Initialize(
 			vm: vm,
 		},
 
-		tx:       &cache.LRU{Size: idCacheSize},
-		utxo:     &cache.LRU{Size: idCacheSize},
-		txStatus: &cache.LRU{Size: idCacheSize},
-		funds:    &cache.LRU{Size: idCacheSize},
+		tx:         &cache.LRU{Size: idCacheSize},
+		utxo:       &cache.LRU{Size: idCacheSize},
+		txStatus:   &cache.LRU{Size: idCacheSize},
+		acceptedAt: &cache.LRU{Size: idCacheSize},
+		funds:      &cache.LRU{Size: idCacheSize},
+		supply:     &cache.LRU{Size: idCacheSize},
+
+		firstSeen:  &cache.LRU{Size: idCacheSize},
+		lastActive: &cache.LRU{Size: idCacheSize},
 
 		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
 	}
 
-	c := codec.NewDefault()
-	c.RegisterType(&BaseTx{})
-	c.RegisterType(&CreateAssetTx{})
-	c.RegisterType(&OperationTx{})
-
-	vm.fxs = make([]*parsedFx, len(fxs))
-	for i, fxContainer := range fxs {
-		if fxContainer == nil {
-			return errIncompatibleFx
-		}
-		fx, ok := fxContainer.Fx.(Fx)
-		if !ok {
-			return errIncompatibleFx
-		}
-		vm.fxs[i] = &parsedFx{
-			ID: fxContainer.ID,
-			Fx: fx,
-		}
-		vm.codec = &codecRegistry{
-			index:         i,
-			typeToFxIndex: vm.typeToFxIndex,
-			codec:         c,
-		}
-		if err := fx.Initialize(vm); err != nil {
-			return err
-		}
+	if err := vm.initCodec(ctx.Limits.MaxTxSize, fxs); err != nil {
+		return err
 	}
 
-	vm.codec = c
-
 	if err := vm.initAliases(genesisBytes); err != nil {
 		return err
 	}
@@ -175,6 +213,22 @@ func (vm *VM) Initialize(
 			return err
 		}
 	}
+	if err := vm.initAssetAliases(); err != nil {
+		return err
+	}
+
+	if vm.Reindex {
+		if err := vm.reindex(); err != nil {
+			return err
+		}
+	}
+	config, err := vm.initChainConfig(ctx.Config)
+	if err != nil {
+		return err
+	}
+	if err := vm.initFeeAsset(config, genesisBytes); err != nil {
+		return err
+	}
 
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
@@ -183,7 +237,17 @@ func (vm *VM) Initialize(
 		vm.FlushTxs()
 	})
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
-	vm.batchTimeout = batchTimeout
+
+	vm.consumed = make(map[[32]byte]ids.ID)
+	vm.initRegossip()
+
+	if err := vm.initWatcher(); err != nil {
+		return err
+	}
+
+	if err := vm.initPendingTxs(); err != nil {
+		return err
+	}
 
 	return vm.db.Commit()
 }
@@ -191,6 +255,8 @@ func (vm *VM) Initialize(
 // Shutdown implements the avalanche.DAGVM interface
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	vm.regossipTimer.Stop()
+	vm.watcher.Shutdown()
 	if err := vm.baseDB.Close(); err != nil {
 		vm.ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -222,11 +288,20 @@ func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
 	}
 }
 
+// VerifyGenesis implements the common.GenesisVerifier interface
+func (vm *VM) VerifyGenesis(limits snow.Limits, genesisBytes []byte, fxs []*common.Fx) error {
+	if err := vm.initCodec(limits.MaxTxSize, fxs); err != nil {
+		return err
+	}
+	_, err := vm.parseGenesis(genesisBytes)
+	return err
+}
+
 // PendingTxs implements the avalanche.DAGVM interface
 func (vm *VM) PendingTxs() []snowstorm.Tx {
 	vm.timer.Cancel()
 
-	txs := vm.txs
+	txs := vm.prioritizeTxs(vm.txs)
 	vm.txs = nil
 	return txs
 }
@@ -260,21 +335,38 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err := tx.Verify(); err != nil {
 		return ids.ID{}, err
 	}
+	if err := vm.verifyFee(tx); err != nil {
+		return ids.ID{}, err
+	}
+	if err := vm.consumeInputs(tx); err != nil {
+		return ids.ID{}, err
+	}
+	if err := vm.addPendingTx(tx.ID()); err != nil {
+		vm.releaseInputs(tx)
+		return ids.ID{}, err
+	}
 	vm.issueTx(tx)
+	vm.trackRegossip(tx)
 	return tx.ID(), nil
 }
 
 // GetUTXOs returns the utxos that at least one of the provided addresses is
-// referenced in.
-func (vm *VM) GetUTXOs(addrs ids.Set) ([]*UTXO, error) {
+// referenced in. It stops early, with an error, once [ctx] is done.
+func (vm *VM) GetUTXOs(ctx context.Context, addrs ids.Set) ([]*UTXO, error) {
 	utxoIDs := ids.Set{}
 	for _, addr := range addrs.List() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		utxos, _ := vm.state.Funds(addr)
 		utxoIDs.Add(utxos...)
 	}
 
 	utxos := []*UTXO{}
 	for _, utxoID := range utxoIDs.List() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		utxo, err := vm.state.UTXO(utxoID)
 		if err != nil {
 			return nil, err
@@ -308,6 +400,7 @@ func (vm *VM) FlushTxs() {
 	if len(vm.txs) != 0 {
 		select {
 		case vm.toEngine <- common.PendingTxs:
+			vm.lastFlush = vm.clock.Time()
 		default:
 			vm.ctx.Log.Warn("Delaying issuance of transactions due to contention")
 			vm.timer.SetTimeoutIn(vm.batchTimeout)
@@ -315,23 +408,136 @@ func (vm *VM) FlushTxs() {
 	}
 }
 
+// RegossipTxs re-issues the locally issued txs that are still undecided after
+// their re-gossip deadline, so that they are pushed to validators in a new
+// vertex. Each time a tx is re-gossiped, the delay until it is re-gossiped
+// again is doubled, up to MaxRegossipFrequency.
+func (vm *VM) RegossipTxs() {
+	now := vm.clock.Time()
+	for key, rtx := range vm.regossip {
+		if rtx.tx.Status().Decided() {
+			delete(vm.regossip, key)
+			continue
+		}
+		if now.Before(rtx.next) {
+			continue
+		}
+
+		vm.ctx.Log.Debug("re-gossiping undecided tx %s", rtx.tx.ID())
+		vm.issueTx(rtx.tx)
+		vm.numRegossiped.Inc()
+
+		rtx.delay *= 2
+		if vm.MaxRegossipFrequency > 0 && rtx.delay > vm.MaxRegossipFrequency {
+			rtx.delay = vm.MaxRegossipFrequency
+		}
+		rtx.next = now.Add(rtx.delay)
+	}
+	vm.resetRegossipTimer()
+}
+
 /*
  ******************************************************************************
  ********************************** Helpers ***********************************
  ******************************************************************************
  */
 
-func (vm *VM) initAliases(genesisBytes []byte) error {
-	genesis := Genesis{}
-	if err := vm.codec.Unmarshal(genesisBytes, &genesis); err != nil {
-		return err
+// initCodec registers the types of this VM and of the feature extensions
+// [fxs] with the codec that transactions are parsed with
+func (vm *VM) initCodec(maxTxSize int, fxs []*common.Fx) error {
+	vm.typeToFxIndex = map[reflect.Type]int{}
+
+	c := codec.NewWithMaxSize(maxTxSize)
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+
+	vm.fxs = make([]*parsedFx, len(fxs))
+	for i, fxContainer := range fxs {
+		if fxContainer == nil {
+			return errIncompatibleFx
+		}
+		fx, ok := fxContainer.Fx.(Fx)
+		if !ok {
+			return errIncompatibleFx
+		}
+		vm.fxs[i] = &parsedFx{
+			ID: fxContainer.ID,
+			Fx: fx,
+		}
+		vm.codec = &codecRegistry{
+			index:         i,
+			typeToFxIndex: vm.typeToFxIndex,
+			codec:         c,
+		}
+		if err := fx.Initialize(vm); err != nil {
+			return err
+		}
+	}
+
+	// Registered after the Fxs' types, so that their type IDs are unchanged
+	c.RegisterType(&AliasAssetTx{})
+	c.RegisterType(&UTXOOperationTx{})
+
+	for i, parsed := range vm.fxs {
+		fx, ok := parsed.Fx.(OperationFx)
+		if !ok {
+			continue
+		}
+		vm.codec = &codecRegistry{
+			index:         i,
+			typeToFxIndex: vm.typeToFxIndex,
+			codec:         c,
+		}
+		if err := fx.InitializeOperations(vm); err != nil {
+			return err
+		}
+	}
+
+	vm.codec = c
+	return nil
+}
+
+// NewCodec returns the codec of an AVM chain run with [fxs], in that order,
+// whose transactions are at most [maxTxSize] bytes long
+func NewCodec(maxTxSize int, fxs []*common.Fx) (codec.Codec, error) {
+	vm := &VM{}
+	if err := vm.initCodec(maxTxSize, fxs); err != nil {
+		return nil, err
+	}
+	return vm.codec, nil
+}
+
+// parseGenesis returns the genesis data [genesisBytes] encodes. Genesis data
+// without the fee fields is returned as a FeeGenesis that doesn't charge fees.
+func (vm *VM) parseGenesis(genesisBytes []byte) (*FeeGenesis, error) {
+	// Neither layout parses the other's bytes, as the fee fields are either
+	// missing or left over
+	genesis := &FeeGenesis{}
+	if err := vm.codec.Unmarshal(genesisBytes, &genesis.Genesis); err != nil {
+		if err := vm.codec.Unmarshal(genesisBytes, genesis); err != nil {
+			return nil, err
+		}
+		if err := genesis.verifyFees(); err != nil {
+			return nil, err
+		}
 	}
 
 	for _, genesisTx := range genesis.Txs {
 		if len(genesisTx.Outs) != 0 {
-			return errGenesisAssetMustHaveState
+			return nil, errGenesisAssetMustHaveState
 		}
+	}
+	return genesis, nil
+}
+
+func (vm *VM) initAliases(genesisBytes []byte) error {
+	genesis, err := vm.parseGenesis(genesisBytes)
+	if err != nil {
+		return err
+	}
 
+	for _, genesisTx := range genesis.Txs {
 		tx := Tx{
 			UnsignedTx: &genesisTx.CreateAssetTx,
 		}
@@ -350,16 +556,12 @@ func (vm *VM) initAliases(genesisBytes []byte) error {
 }
 
 func (vm *VM) initState(genesisBytes []byte) error {
-	genesis := Genesis{}
-	if err := vm.codec.Unmarshal(genesisBytes, &genesis); err != nil {
+	genesis, err := vm.parseGenesis(genesisBytes)
+	if err != nil {
 		return err
 	}
 
 	for _, genesisTx := range genesis.Txs {
-		if len(genesisTx.Outs) != 0 {
-			return errGenesisAssetMustHaveState
-		}
-
 		tx := Tx{
 			UnsignedTx: &genesisTx.CreateAssetTx,
 		}
@@ -418,10 +620,76 @@ func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
 	return tx, nil
 }
 
+// Re-issue the transactions that were issued to this node, but weren't decided
+// before this node last shut down
+func (vm *VM) initPendingTxs() error {
+	pending, _ := vm.state.PendingTxs()
+	stillPending := []ids.ID(nil)
+	for _, txID := range pending {
+		tx := &UniqueTx{
+			vm:   vm,
+			txID: txID,
+		}
+		if tx.Status() != choices.Processing {
+			continue
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			vm.ctx.Log.Debug("dropping pending tx %s due to %s", txID, err)
+			continue
+		}
+		if err := vm.consumeInputs(tx); err != nil {
+			vm.ctx.Log.Debug("dropping pending tx %s due to %s", txID, err)
+			continue
+		}
+		stillPending = append(stillPending, txID)
+		vm.issueTx(tx)
+		vm.trackRegossip(tx)
+	}
+	if len(stillPending) > 0 {
+		vm.ctx.Log.Info("re-issuing %d pending transactions", len(stillPending))
+	}
+	return vm.state.SetPendingTxs(stillPending)
+}
+
+// Mark [txID] as issued to this node so that it is re-issued if this node
+// restarts before it is decided
+func (vm *VM) addPendingTx(txID ids.ID) error {
+	pending, _ := vm.state.PendingTxs()
+	pendingSet := ids.Set{}
+	pendingSet.Add(pending...)
+	pendingSet.Add(txID)
+	if err := vm.state.SetPendingTxs(pendingSet.List()); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
+
+// Remove [txID] from the set of transactions to re-issue on restart. The
+// caller is expected to commit the database.
+func (vm *VM) removePendingTx(txID ids.ID) error {
+	pending, err := vm.state.PendingTxs()
+	if err != nil {
+		return nil // There are no pending txs
+	}
+	pendingSet := ids.Set{}
+	pendingSet.Add(pending...)
+	if !pendingSet.Contains(txID) {
+		return nil
+	}
+	pendingSet.Remove(txID)
+	return vm.state.SetPendingTxs(pendingSet.List())
+}
+
+// issueTx adds [tx] to the batch of txs to issue to consensus. If no txs were
+// issued within the last batch timeout, the node is under low load and [tx] is
+// issued immediately to keep latency low. Otherwise, txs are batched until
+// either the batch is full or the batch timeout expires.
 func (vm *VM) issueTx(tx snowstorm.Tx) {
 	vm.txs = append(vm.txs, tx)
 	switch {
-	case len(vm.txs) == batchSize:
+	case len(vm.txs) >= vm.batchSize:
+		vm.FlushTxs()
+	case len(vm.txs) == 1 && vm.clock.Time().Sub(vm.lastFlush) >= vm.batchTimeout:
 		vm.FlushTxs()
 	case len(vm.txs) == 1:
 		vm.timer.SetTimeoutIn(vm.batchTimeout)
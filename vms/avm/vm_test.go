@@ -5,11 +5,14 @@ package avm
 
 import (
 	"bytes"
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -45,7 +48,7 @@ func init() {
 	}
 }
 
-func GetFirstTxFromGenesisTest(genesisBytes []byte, t *testing.T) *Tx {
+func GetFirstTxFromGenesisTest(genesisBytes []byte, t testing.TB) *Tx {
 	c := codec.NewDefault()
 	c.RegisterType(&BaseTx{})
 	c.RegisterType(&CreateAssetTx{})
@@ -82,7 +85,7 @@ func GetFirstTxFromGenesisTest(genesisBytes []byte, t *testing.T) *Tx {
 	return nil
 }
 
-func BuildGenesisTest(t *testing.T) []byte {
+func BuildGenesisTest(t testing.TB) []byte {
 	ss := StaticService{}
 
 	addr0 := keys[0].PublicKey().Address()
@@ -160,7 +163,7 @@ func BuildGenesisTest(t *testing.T) []byte {
 	return reply.Bytes.Bytes
 }
 
-func GenesisVM(t *testing.T) *VM {
+func GenesisVM(t testing.TB) *VM {
 	genesisBytes := BuildGenesisTest(t)
 
 	ctx.Lock.Lock()
@@ -370,6 +373,24 @@ func TestInvalidGenesis(t *testing.T) {
 	}
 }
 
+func TestVerifyGenesis(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	fxs := []*common.Fx{&common.Fx{
+		ID: ids.Empty,
+		Fx: &secp256k1fx.Fx{},
+	}}
+
+	if err := (&VM{}).VerifyGenesis(snow.DefaultLimits, genesisBytes, fxs); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&VM{}).VerifyGenesis(snow.DefaultLimits, genesisBytes[:len(genesisBytes)-1], fxs); err == nil {
+		t.Fatalf("Should have errored due to a truncated genesis")
+	}
+	if err := (&VM{}).VerifyGenesis(snow.DefaultLimits, genesisBytes, nil); err == nil {
+		t.Fatalf("Should have errored due to the genesis' outputs requiring a missing Fx")
+	}
+}
+
 func TestInvalidFx(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -536,7 +557,7 @@ func TestGenesisGetUTXOs(t *testing.T) {
 
 	addrs := ids.Set{}
 	addrs.Add(addr)
-	utxos, err := vm.GetUTXOs(addrs)
+	utxos, err := vm.GetUTXOs(context.Background(), addrs)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -547,3 +568,756 @@ func TestGenesisGetUTXOs(t *testing.T) {
 		t.Fatalf("Wrong number of utxos (%d) returned", len(utxos))
 	}
 }
+
+func TestIssueTxPersisted(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	db := memdb.New()
+
+	ctx.Lock.Lock()
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		db,
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}}
+
+	unsignedBytes, err := vm.codec.Marshal(&newTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := keys[0]
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	newTx.Creds = append(newTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx.Initialize(b)
+
+	if _, err := vm.IssueTx(newTx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the VM before the tx was decided
+	restartedVM := &VM{}
+	err = restartedVM.Initialize(
+		ctx,
+		db,
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := restartedVM.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have re-issued %d tx(s)", 1)
+	}
+	if !txs[0].ID().Equals(newTx.ID()) {
+		t.Fatalf("Re-issued the wrong tx")
+	}
+
+	txs[0].Accept()
+	ctx.Lock.Unlock()
+
+	if pending, _ := restartedVM.state.PendingTxs(); len(pending) != 0 {
+		t.Fatalf("Should have removed the accepted tx from the pending txs")
+	}
+}
+
+func TestRegossipTx(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	db := memdb.New()
+
+	ctx.Lock.Lock()
+	vm := &VM{
+		RegossipFrequency:    time.Minute,
+		MaxRegossipFrequency: 3 * time.Minute,
+	}
+	err := vm.Initialize(
+		ctx,
+		db,
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}}
+
+	unsignedBytes, err := vm.codec.Marshal(&newTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := keys[0]
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	newTx.Creds = append(newTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx.Initialize(b)
+
+	now := time.Now()
+	vm.clock.Set(now)
+
+	if _, err := vm.IssueTx(newTx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if txs := vm.PendingTxs(); len(txs) != 1 {
+		t.Fatalf("Should have issued %d tx(s)", 1)
+	}
+
+	vm.RegossipTxs()
+	if txs := vm.PendingTxs(); len(txs) != 0 {
+		t.Fatalf("Shouldn't have re-gossiped the tx before the regossip frequency")
+	}
+
+	vm.clock.Set(now.Add(time.Minute))
+	vm.RegossipTxs()
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have re-gossiped %d tx(s)", 1)
+	}
+	if !txs[0].ID().Equals(newTx.ID()) {
+		t.Fatalf("Re-gossiped the wrong tx")
+	}
+
+	// The delay should have doubled
+	vm.clock.Set(now.Add(2 * time.Minute))
+	vm.RegossipTxs()
+	if txs := vm.PendingTxs(); len(txs) != 0 {
+		t.Fatalf("Shouldn't have re-gossiped the tx before the backoff expired")
+	}
+
+	vm.clock.Set(now.Add(3 * time.Minute))
+	vm.RegossipTxs()
+	if txs := vm.PendingTxs(); len(txs) != 1 {
+		t.Fatalf("Should have re-gossiped %d tx(s)", 1)
+	}
+	if rtx := vm.regossip[newTx.ID().Key()]; rtx.delay != vm.MaxRegossipFrequency {
+		t.Fatalf("Backoff should have been capped at %s but was %s", vm.MaxRegossipFrequency, rtx.delay)
+	}
+
+	txs[0].Accept()
+	ctx.Lock.Unlock()
+
+	if len(vm.regossip) != 0 {
+		t.Fatalf("Should have stopped re-gossiping the accepted tx")
+	}
+}
+
+func TestIssueTxAdaptiveBatching(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	toEngine := make(chan common.Message, 1)
+	vm := &VM{
+		BatchSize:    2,
+		BatchTimeout: time.Minute,
+	}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		toEngine,
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	vm.clock.Set(now)
+
+	// Under low load, txs should be issued immediately
+	vm.issueTx(&snowstorm.TestTx{Identifier: ids.Empty.Prefix(0)})
+	select {
+	case <-toEngine:
+	default:
+		t.Fatalf("Should have issued the tx immediately")
+	}
+	if txs := vm.PendingTxs(); len(txs) != 1 {
+		t.Fatalf("Should have issued %d tx(s)", 1)
+	}
+
+	// Shortly after issuing a tx, txs should be batched
+	vm.issueTx(&snowstorm.TestTx{Identifier: ids.Empty.Prefix(1)})
+	select {
+	case <-toEngine:
+		t.Fatalf("Should have batched the tx")
+	default:
+	}
+
+	// Once the batch is full, it should be issued
+	vm.issueTx(&snowstorm.TestTx{Identifier: ids.Empty.Prefix(2)})
+	select {
+	case <-toEngine:
+	default:
+		t.Fatalf("Should have issued the full batch")
+	}
+	if txs := vm.PendingTxs(); len(txs) != 2 {
+		t.Fatalf("Should have issued %d tx(s)", 2)
+	}
+
+	// After the chain has been idle, txs should be issued immediately again
+	vm.clock.Set(now.Add(time.Minute))
+	vm.issueTx(&snowstorm.TestTx{Identifier: ids.Empty.Prefix(3)})
+	select {
+	case <-toEngine:
+	default:
+		t.Fatalf("Should have issued the tx immediately")
+	}
+}
+
+func TestChainConfigBatching(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+	defer func() { ctx.Config = nil }()
+
+	// initialize returns a VM that batches [batchSize] txs, unless it's
+	// overridden by the chain's configuration [config]
+	initialize := func(config string) (*VM, error) {
+		ctx.Config = []byte(config)
+		vm := &VM{
+			BatchSize:    2,
+			BatchTimeout: time.Minute,
+		}
+		err := vm.Initialize(
+			ctx,
+			memdb.New(),
+			genesisBytes,
+			make(chan common.Message, 1),
+			[]*common.Fx{&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			}},
+		)
+		if err == nil {
+			vm.Shutdown()
+		}
+		return vm, err
+	}
+
+	vm, err := initialize(`{"batchSize":5,"batchTimeout":"250","parents":"3"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case vm.batchSize != 5:
+		t.Fatalf("the chain should batch %d txs but batches %d", 5, vm.batchSize)
+	case vm.batchTimeout != 250*time.Millisecond:
+		t.Fatalf("the chain's batch timeout should be %s but is %s", 250*time.Millisecond, vm.batchTimeout)
+	case vm.VertexParents() != 3:
+		t.Fatalf("the chain's vertices should have %d parents but have %d", 3, vm.VertexParents())
+	}
+
+	// A chain that isn't configured keeps the VM's configuration
+	vm, err = initialize("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.batchSize != 2 || vm.batchTimeout != time.Minute || vm.VertexParents() != 0 {
+		t.Fatalf("an unconfigured chain shouldn't have overridden the VM's batching")
+	}
+
+	if _, err := initialize(`{"batchSize":0}`); err != errInvalidBatchSize {
+		t.Fatalf("should have failed with %s but got %v", errInvalidBatchSize, err)
+	}
+	if _, err := initialize(`{"parents":1}`); err != errInvalidParents {
+		t.Fatalf("should have failed with %s but got %v", errInvalidParents, err)
+	}
+}
+
+func TestIssueTxDoubleSpend(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	baseTx := BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}
+	signTx := func(unsignedTx UnsignedTx) []byte {
+		tx := &Tx{UnsignedTx: unsignedTx}
+		unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := keys[0].Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{
+					fixedSig,
+				},
+			},
+		})
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	firstTxBytes := signTx(&baseTx)
+	secondTxBytes := signTx(&OperationTx{BaseTx: baseTx})
+
+	firstTxID, err := vm.IssueTx(firstTxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.IssueTx(firstTxBytes); err != nil {
+		t.Fatalf("Re-issuing a pending tx should not be considered a double spend: %s", err)
+	}
+	if _, err := vm.IssueTx(secondTxBytes); err == nil {
+		t.Fatalf("Should have rejected a tx that spends the input of a pending tx")
+	}
+
+	firstTx, err := vm.GetTx(firstTxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstTx.Reject()
+
+	if _, err := vm.IssueTx(secondTxBytes); err != nil {
+		t.Fatalf("Should have allowed spending the input of a rejected tx: %s", err)
+	}
+}
+
+func TestDroppedTxReleasesInputs(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	baseTx := BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}
+	signTx := func(unsignedTx UnsignedTx) []byte {
+		tx := &Tx{UnsignedTx: unsignedTx}
+		unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := keys[0].Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{
+					fixedSig,
+				},
+			},
+		})
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	firstTxBytes := signTx(&baseTx)
+	secondTxBytes := signTx(&OperationTx{BaseTx: baseTx})
+
+	firstTxID, err := vm.IssueTx(firstTxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstTx, err := vm.GetTx(firstTxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The engine drops the tx instead of issuing it into consensus
+	vm.DroppedTx(firstTx)
+
+	if pending, _ := vm.state.PendingTxs(); len(pending) != 0 {
+		t.Fatalf("Shouldn't have persisted a dropped tx as pending")
+	}
+	if _, err := vm.IssueTx(secondTxBytes); err != nil {
+		t.Fatalf("Should have allowed spending the input of a dropped tx: %s", err)
+	}
+}
+
+func TestReindex(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	db := memdb.New()
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		db,
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	addr := keys[0].PublicKey().Address()
+	addrID := ids.NewID(hashing.ComputeHash256Array(addr.Bytes()))
+
+	funds, err := vm.state.Funds(addrID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(funds) == 0 {
+		t.Fatalf("Genesis should have funded the address")
+	}
+
+	// Corrupt the indexes as if they had never been maintained
+	if err := vm.state.SetSupply(genesisTx.ID(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.state.SetFunds(addrID, []ids.ID{ids.Empty}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the VM with the indexes being rebuilt
+	restartedVM := &VM{Reindex: true}
+	err = restartedVM.Initialize(
+		ctx,
+		db,
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if supply, err := restartedVM.state.Supply(genesisTx.ID()); err != nil {
+		t.Fatal(err)
+	} else if supply != 300000 {
+		t.Fatalf("Reindexed supply should be %d but is %d", 300000, supply)
+	}
+
+	reindexedFunds, err := restartedVM.state.Funds(addrID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFunds := ids.Set{}
+	expectedFunds.Add(funds...)
+	actualFunds := ids.Set{}
+	actualFunds.Add(reindexedFunds...)
+	if !expectedFunds.Equals(actualFunds) {
+		t.Fatalf("Reindexed funds %v should be %v", reindexedFunds, funds)
+	}
+}
+
+func TestIssueTxSpendingProcessingTx(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	// Returns a tx that sends the [amount] held by the UTXO at [outputIndex]
+	// of [txID] back to the key that controls it
+	newTx := func(txID ids.ID, outputIndex uint32, amount uint64) []byte {
+		tx := &Tx{UnsignedTx: &BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Outs: []*TransferableOutput{
+				&TransferableOutput{
+					Asset: Asset{ID: genesisTx.ID()},
+					Out: &secp256k1fx.TransferOutput{
+						Amt: amount,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+						},
+					},
+				},
+			},
+			Ins: []*TransferableInput{
+				&TransferableInput{
+					UTXOID: UTXOID{
+						TxID:        txID,
+						OutputIndex: outputIndex,
+					},
+					Asset: Asset{ID: genesisTx.ID()},
+					In: &secp256k1fx.TransferInput{
+						Amt: amount,
+						Input: secp256k1fx.Input{
+							SigIndices: []uint32{0},
+						},
+					},
+				},
+			},
+		}}
+		unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := keys[0].Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+			},
+		})
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	parentID, err := vm.IssueTx(newTx(genesisTx.ID(), 1, 50000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The parent is still processing, so the UTXO spent by the child isn't in
+	// the UTXO set yet
+	if _, err := vm.IssueTx(newTx(parentID, 0, 50000)); err != nil {
+		t.Fatalf("should have allowed spending an output of a processing tx: %s", err)
+	}
+}
+
+// FuzzParseTx checks that parsing arbitrary bytes received from a peer or
+// issued to avm.issueTx never panics. Inputs that fail are saved in
+// testdata/fuzz, so they're rerun by go test.
+func FuzzParseTx(f *testing.F) {
+	genesisBytes := BuildGenesisTest(f)
+	vm := GenesisVM(f)
+	defer vm.Shutdown()
+
+	f.Add(GetFirstTxFromGenesisTest(genesisBytes, f).Bytes())
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		if tx, err := vm.ParseTx(b); err == nil {
+			_ = tx.Verify()
+		}
+	})
+}
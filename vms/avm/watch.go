@@ -0,0 +1,37 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+// The watches are stored under their own prefix of the chain's database. They
+// aren't part of the chain's state, so they're written outside of [vm.db].
+var watchPrefix = []byte("watch")
+
+// initWatcher loads the registered watches and starts notifying them of the
+// txs accepted once the chain is bootstrapped
+func (vm *VM) initWatcher() error {
+	format := func(address ids.ShortID) string { return vm.Format(address.Bytes()) }
+	vm.watcher.AllowedHosts = vm.WatchAllowedHosts
+	if err := vm.watcher.Initialize(vm.ctx.Log, prefixdb.New(watchPrefix, vm.baseDB), vm.ctx.ChainID, format); err != nil {
+		return err
+	}
+	vm.ctx.Hooks.OnBootstrapped(vm.watcher.Bootstrapped)
+	return nil
+}
+
+// notifyWatchers notifies the watches of [addresses] that the tx [txID], which
+// involves them, was accepted
+func (vm *VM) notifyWatchers(txID ids.ID, addresses [][]byte) {
+	shortAddrs := make([]ids.ShortID, 0, len(addresses))
+	for _, address := range addresses {
+		if shortAddr, err := ids.ToShortID(address); err == nil {
+			shortAddrs = append(shortAddrs, shortAddr)
+		}
+	}
+	vm.watcher.Notify(txID, shortAddrs)
+}
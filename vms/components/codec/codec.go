@@ -38,6 +38,7 @@ var (
 	errNeedPointer               = errors.New("must unmarshal into a pointer")
 	errMarshalUnregisteredType   = errors.New("can't marshal an unregistered type")
 	errUnmarshalUnregisteredType = errors.New("can't unmarshal an unregistered type")
+	errUnmarshalWrongType        = errors.New("can't unmarshal a type that doesn't implement the interface")
 	errUnknownType               = errors.New("don't know how to marshal/unmarshal this type")
 	errMarshalUnexportedField    = errors.New("can't serialize an unexported field")
 	errUnmarshalUnexportedField  = errors.New("can't deserialize into an unexported field")
@@ -98,6 +99,10 @@ func New(maxSize, maxSliceLen int) Codec {
 // NewDefault returns a new codec with reasonable default values
 func NewDefault() Codec { return New(defaultMaxSize, defaultMaxSliceLength) }
 
+// NewWithMaxSize returns a new codec that marshals and unmarshals values of at
+// most [maxSize] bytes
+func NewWithMaxSize(maxSize int) Codec { return New(maxSize, defaultMaxSliceLength) }
+
 // RegisterType is used to register types that may be unmarshaled into an interface typed value
 // [val] is a value of the type being registered
 func (c codec) RegisterType(val interface{}) error {
@@ -324,6 +329,10 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 		if !ok {
 			return errUnmarshalUnregisteredType
 		}
+		// The type must be assignable to the field, or setting it would panic
+		if !typ.AssignableTo(field.Type()) {
+			return errUnmarshalWrongType
+		}
 		concreteInstancePtr := reflect.New(typ) // instance of the proper type
 		// Unmarshal into the struct
 		if err := c.unmarshal(p, concreteInstancePtr.Elem()); err != nil {
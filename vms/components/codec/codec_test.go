@@ -276,6 +276,24 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestInterfaceWrongType(t *testing.T) {
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+	codec.RegisterType(&MyInnerStruct3{})
+
+	// MyInnerStruct3 doesn't implement Foo
+	var v interface{} = &MyInnerStruct3{F: &MyInnerStruct{}}
+	bytes, err := codec.Marshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unmarshaledFoo Foo
+	if err := codec.Unmarshal(bytes, &unmarshaledFoo); err != errUnmarshalWrongType {
+		t.Fatalf("should have failed to unmarshal a type that doesn't implement the interface but got %v", err)
+	}
+}
+
 func TestSliceOfInterface(t *testing.T) {
 	mySlice := []Foo{
 		&MyInnerStruct{
@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package idempotency
+
+import (
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// DefaultSize is the number of tokens remembered by default
+const DefaultSize = 4096
+
+// Tokens remembers the IDs of transactions that were issued by API calls that
+// specified a client-supplied idempotency token. When a call is retried with
+// the same token, the ID of the originally issued transaction can be returned
+// rather than issuing a duplicate transaction.
+//
+// Only the most recently used tokens are remembered.
+type Tokens struct {
+	Size int
+
+	issued cache.LRU
+}
+
+// Get returns the ID of the transaction that was issued in [scope] with
+// [token], if it is remembered.
+// [scope] separates the tokens of different API methods and users.
+func (t *Tokens) Get(scope, token string) (ids.ID, bool) {
+	if token == "" {
+		return ids.ID{}, false
+	}
+	t.init()
+	txID, ok := t.issued.Get(t.key(scope, token))
+	if !ok {
+		return ids.ID{}, false
+	}
+	return txID.(ids.ID), true
+}
+
+// Put remembers that [txID] was issued in [scope] with [token].
+// If [token] is empty, this is a no-op.
+func (t *Tokens) Put(scope, token string, txID ids.ID) {
+	if token == "" {
+		return
+	}
+	t.init()
+	t.issued.Put(t.key(scope, token), txID)
+}
+
+func (t *Tokens) init() {
+	if t.Size <= 0 {
+		t.Size = DefaultSize
+	}
+	t.issued.Size = t.Size
+}
+
+func (t *Tokens) key(scope, token string) ids.ID {
+	p := wrappers.Packer{MaxSize: wrappers.IntLen*2 + len(scope) + len(token)}
+	p.PackBytes([]byte(scope))
+	p.PackBytes([]byte(token))
+	return ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+}
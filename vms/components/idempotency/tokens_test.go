@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package idempotency
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestTokens(t *testing.T) {
+	tokens := Tokens{Size: 1}
+	txID := ids.NewID([32]byte{1})
+
+	if _, ok := tokens.Get("scope", "token"); ok {
+		t.Fatalf("Shouldn't have found an unknown token")
+	}
+
+	tokens.Put("scope", "token", txID)
+	if id, ok := tokens.Get("scope", "token"); !ok {
+		t.Fatalf("Should have found the token")
+	} else if !id.Equals(txID) {
+		t.Fatalf("Returned %s, expected %s", id, txID)
+	}
+
+	if _, ok := tokens.Get("other scope", "token"); ok {
+		t.Fatalf("Tokens shouldn't be shared across scopes")
+	}
+
+	tokens.Put("scope", "", txID)
+	if _, ok := tokens.Get("scope", ""); ok {
+		t.Fatalf("The empty token should never be remembered")
+	}
+
+	tokens.Put("scope", "newer token", ids.NewID([32]byte{2}))
+	if _, ok := tokens.Get("scope", "token"); ok {
+		t.Fatalf("The least recently used token should have been evicted")
+	}
+}
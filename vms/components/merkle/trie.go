@@ -0,0 +1,191 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package merkle implements a sparse Merkle trie that maps 20 byte keys to
+// values. The hash of an empty subtrie is all zeros, so only the nodes of
+// non-empty subtries are stored. The root commits to every key and value in the
+// trie, and a Proof shows that a key has a value, or has none, under a root.
+//
+// The nodes of a trie are stored in the database passed into each call, which
+// should be used only by that trie. Only the hashes of the values are stored,
+// so the values must be stored elsewhere.
+package merkle
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// Depth is the number of levels of the trie below the root. Every key has one
+// leaf, at the bottom of the trie, whose path is the bits of the key.
+const Depth = 8 * 20
+
+const (
+	leafPrefix byte = iota
+	nodePrefix
+)
+
+var (
+	errInvalidProof  = errors.New("proof doesn't match the root")
+	errWrongSiblings = errors.New("proof has the wrong number of siblings")
+)
+
+// Root returns the root of the trie stored in [db]
+func Root(db database.Database) (ids.ID, error) {
+	root, err := getNode(db, 0, [20]byte{})
+	return ids.NewID(root), err
+}
+
+// Put sets the value of [key] to [value] in the trie stored in [db]. If [value]
+// is nil, [key] is removed from the trie.
+func Put(db database.Database, key ids.ShortID, value []byte) error {
+	path := key.Key()
+	hash := [32]byte{}
+	if value != nil {
+		hash = leafHash(path, value)
+	}
+	if err := putNode(db, Depth, path, hash); err != nil {
+		return err
+	}
+	for depth := Depth; depth > 0; depth-- {
+		sibling, err := getNode(db, depth, flip(path, depth-1))
+		if err != nil {
+			return err
+		}
+		if bit(path, depth-1) {
+			hash = nodeHash(sibling, hash)
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+		if err := putNode(db, depth-1, path, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prove returns a proof that [key] has [value] in the trie stored in [db]. If
+// [value] is nil, the proof shows that [key] isn't in the trie. The proof is
+// only valid if [value] is the value of [key].
+func Prove(db database.Database, key ids.ShortID, value []byte) (*Proof, error) {
+	path := key.Key()
+	proof := &Proof{
+		Key:   key,
+		Value: value,
+	}
+	for depth := Depth; depth > 0; depth-- {
+		sibling, err := getNode(db, depth, flip(path, depth-1))
+		if err != nil {
+			return nil, err
+		}
+		if sibling != [32]byte{} {
+			proof.Bitmap[(Depth-depth)/8] |= 1 << ((Depth - depth) % 8)
+			proof.Siblings = append(proof.Siblings, ids.NewID(sibling))
+		}
+	}
+	return proof, nil
+}
+
+// Proof shows that a key has a value, or has none, under a root
+type Proof struct {
+	Key ids.ShortID
+
+	// Value is nil if the proof shows that [Key] isn't in the trie
+	Value []byte
+
+	// Bitmap has bit i set iff the sibling of the node i levels above the leaf
+	// of [Key] isn't empty
+	Bitmap [Depth / 8]byte
+
+	// Siblings are the non-empty siblings of the nodes on the path from the
+	// leaf of [Key] to the root, in that order
+	Siblings []ids.ID
+}
+
+// Verify returns nil iff this proof is valid under [root]
+func (p *Proof) Verify(root ids.ID) error {
+	path := p.Key.Key()
+	hash := [32]byte{}
+	if p.Value != nil {
+		hash = leafHash(path, p.Value)
+	}
+
+	siblings := p.Siblings
+	for depth := Depth; depth > 0; depth-- {
+		sibling := [32]byte{}
+		if p.Bitmap[(Depth-depth)/8]&(1<<((Depth-depth)%8)) != 0 {
+			if len(siblings) == 0 {
+				return errWrongSiblings
+			}
+			sibling = siblings[0].Key()
+			siblings = siblings[1:]
+		}
+		if bit(path, depth-1) {
+			hash = nodeHash(sibling, hash)
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+	}
+	if len(siblings) != 0 {
+		return errWrongSiblings
+	}
+	if !root.Equals(ids.NewID(hash)) {
+		return errInvalidProof
+	}
+	return nil
+}
+
+// leafHash returns the hash of the leaf of [path] whose value is [value]
+func leafHash(path [20]byte, value []byte) [32]byte {
+	valueHash := hashing.ComputeHash256Array(value)
+	return hashing.ComputeHash256Array(append(append([]byte{leafPrefix}, path[:]...), valueHash[:]...))
+}
+
+// nodeHash returns the hash of the node whose children are [left] and [right]
+func nodeHash(left, right [32]byte) [32]byte {
+	if left == [32]byte{} && right == [32]byte{} {
+		return [32]byte{}
+	}
+	return hashing.ComputeHash256Array(append(append([]byte{nodePrefix}, left[:]...), right[:]...))
+}
+
+// bit returns the [i]th bit of [path], counting from the most significant bit
+func bit(path [20]byte, i int) bool { return path[i/8]&(0x80>>uint(i%8)) != 0 }
+
+// flip returns [path] with the [i]th bit flipped
+func flip(path [20]byte, i int) [20]byte {
+	path[i/8] ^= 0x80 >> uint(i%8)
+	return path
+}
+
+// nodeKey returns the key that the node at [depth] on [path] is stored under.
+// Only the first [depth] bits of [path] are part of the key.
+func nodeKey(depth int, path [20]byte) []byte {
+	for i := depth; i < Depth; i++ {
+		path[i/8] &^= 0x80 >> uint(i%8)
+	}
+	return append([]byte{byte(depth)}, path[:]...)
+}
+
+func getNode(db database.Database, depth int, path [20]byte) ([32]byte, error) {
+	hash := [32]byte{}
+	value, err := db.Get(nodeKey(depth, path))
+	switch {
+	case err == database.ErrNotFound:
+		return hash, nil
+	case err != nil:
+		return hash, err
+	}
+	copy(hash[:], value)
+	return hash, nil
+}
+
+func putNode(db database.Database, depth int, path [20]byte, hash [32]byte) error {
+	if hash == [32]byte{} {
+		return db.Delete(nodeKey(depth, path))
+	}
+	return db.Put(nodeKey(depth, path), hash[:])
+}
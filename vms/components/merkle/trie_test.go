@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestTrie(t *testing.T) {
+	db := memdb.New()
+
+	if root, err := Root(db); err != nil {
+		t.Fatal(err)
+	} else if !root.Equals(ids.Empty) {
+		t.Fatalf("Empty trie should have an empty root")
+	}
+
+	key0 := ids.NewShortID([20]byte{0x80})
+	key1 := ids.NewShortID([20]byte{0x80, 1})
+	key2 := ids.NewShortID([20]byte{2})
+
+	if err := Put(db, key0, []byte("zero")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Put(db, key1, []byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	root, err := Root(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := Prove(db, key1, []byte("one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Siblings) != 1 {
+		t.Fatalf("Proof should have had 1 sibling but had %d", len(proof.Siblings))
+	}
+
+	proof.Value = []byte("two")
+	if err := proof.Verify(root); err == nil {
+		t.Fatalf("Proof of the wrong value should have failed")
+	}
+
+	proof, err = Prove(db, key2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(root); err != nil {
+		t.Fatal(err)
+	}
+	proof.Value = []byte("two")
+	if err := proof.Verify(root); err == nil {
+		t.Fatalf("Proof of a missing key should have failed")
+	}
+
+	// Removing a key restores the previous root and removes its nodes
+	if err := Put(db, key2, []byte("two")); err != nil {
+		t.Fatal(err)
+	}
+	if newRoot, err := Root(db); err != nil {
+		t.Fatal(err)
+	} else if newRoot.Equals(root) {
+		t.Fatalf("Adding a key should have changed the root")
+	}
+	if err := Put(db, key2, nil); err != nil {
+		t.Fatal(err)
+	}
+	if newRoot, err := Root(db); err != nil {
+		t.Fatal(err)
+	} else if !newRoot.Equals(root) {
+		t.Fatalf("Removing a key should have restored the root")
+	}
+
+	if err := Put(db, key0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Put(db, key1, nil); err != nil {
+		t.Fatal(err)
+	}
+	iter := db.NewIterator()
+	defer iter.Release()
+	if iter.Next() {
+		t.Fatalf("Empty trie shouldn't have stored any nodes")
+	}
+}
@@ -68,6 +68,12 @@ type State interface {
 	// GetTime gets the time associated with [key] in [db]
 	GetTime(db database.Database, key ids.ID) (time.Time, error)
 
+	// PutUint64 associates [key] with [value] in [db]
+	PutUint64(db database.Database, key ids.ID, value uint64) error
+
+	// GetUint64 gets the uint64 associated with [key] in [db]
+	GetUint64(db database.Database, key ids.ID) (uint64, error)
+
 	// Register a new type.
 	// When values that were Put with [typeID] are retrieved from the database,
 	// they will be unmarshaled from bytes using [unmarshal].
@@ -206,6 +212,25 @@ func (s *state) GetTime(db database.Database, key ids.ID) (time.Time, error) {
 	return time.Time{}, errWrongType
 }
 
+// PutUint64 associates [key] with [value] in [db]
+func (s *state) PutUint64(db database.Database, key ids.ID, value uint64) error {
+	return s.Put(db, Uint64TypeID, key, uint64Marshaller(value))
+}
+
+// GetUint64 gets the uint64 associated with [key] in [db]
+func (s *state) GetUint64(db database.Database, key ids.ID) (uint64, error) {
+	valueInterface, err := s.Get(db, Uint64TypeID, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if value, ok := valueInterface.(uint64); ok {
+		return value, nil
+	}
+
+	return 0, errWrongType
+}
+
 // Prefix [ID] with [typeID] to prevent key collisions in the database
 func (s *state) uniqueID(ID ids.ID, typeID uint64) ids.ID {
 	uIDCache, cacheExists := s.uniqueIDCaches[typeID]
@@ -228,11 +253,12 @@ func NewState() State {
 		uniqueIDCaches: make(map[uint64]*cache.LRU),
 	}
 
-	// Register ID, Status and time.Time so they can be put/get without client code
+	// Register ID, Status, time.Time and uint64 so they can be put/get without client code
 	// having to register them
 	state.RegisterType(IDTypeID, unmarshalID)
 	state.RegisterType(StatusTypeID, unmarshalStatus)
 	state.RegisterType(TimeTypeID, unmarshalTime)
+	state.RegisterType(Uint64TypeID, unmarshalUint64)
 
 	return state
 }
@@ -247,3 +273,12 @@ func (tm *timeMarshaller) Bytes() []byte {
 	p.PackLong(uint64(tm.t.Unix()))
 	return p.Bytes
 }
+
+// So we can marshal uint64
+type uint64Marshaller uint64
+
+func (um uint64Marshaller) Bytes() []byte {
+	p := wrappers.Packer{MaxSize: 8}
+	p.PackLong(uint64(um))
+	return p.Bytes
+}
@@ -16,4 +16,6 @@ const (
 	TimeTypeID
 	// BlockTypeID is the type ID of blocks in state
 	BlockTypeID
+	// Uint64TypeID is the type ID for uint64
+	Uint64TypeID
 )
@@ -29,3 +29,9 @@ func unmarshalTime(bytes []byte) (interface{}, error) {
 	unixTime := p.UnpackLong()
 	return time.Unix(int64(unixTime), 0), nil
 }
+
+func unmarshalUint64(bytes []byte) (interface{}, error) {
+	p := wrappers.Packer{Bytes: bytes}
+	value := p.UnpackLong()
+	return value, p.Err
+}
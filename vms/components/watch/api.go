@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watch
+
+import (
+	"encoding/hex"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// The arguments and replies of the API methods that manage a chain's watches
+
+// WatchAddressArgs are the arguments to watchAddress
+type WatchAddressArgs struct {
+	Address string `json:"address"`
+	URL     string `json:"url"`
+}
+
+// WatchAddressReply is the reply from watchAddress. [Secret], hex encoded,
+// signs the notifications of the watch.
+type WatchAddressReply struct {
+	WatchID ids.ID `json:"watchID"`
+	Secret  string `json:"secret"`
+}
+
+// UnwatchAddressArgs are the arguments to unwatchAddress
+type UnwatchAddressArgs struct {
+	WatchID ids.ID `json:"watchID"`
+}
+
+// UnwatchAddressReply is the reply from unwatchAddress
+type UnwatchAddressReply struct {
+	Success bool `json:"success"`
+}
+
+// ListWatchesArgs are the arguments to listWatches
+type ListWatchesArgs struct{}
+
+// APIWatch is a watch, without its secret
+type APIWatch struct {
+	WatchID ids.ID `json:"watchID"`
+	Address string `json:"address"`
+	URL     string `json:"url"`
+}
+
+// ListWatchesReply is the reply from listWatches
+type ListWatchesReply struct {
+	Watches []APIWatch `json:"watches"`
+}
+
+// WatchAddress registers a watch of [address], as in Watcher.Watch
+func (w *Watcher) WatchAddress(address ids.ShortID, args *WatchAddressArgs, reply *WatchAddressReply) error {
+	watch, err := w.Watch(address, args.URL)
+	if err != nil {
+		return err
+	}
+	reply.WatchID = watch.ID
+	reply.Secret = hex.EncodeToString(watch.Secret)
+	return nil
+}
+
+// UnwatchAddress removes a watch, as in Watcher.Unwatch
+func (w *Watcher) UnwatchAddress(args *UnwatchAddressArgs, reply *UnwatchAddressReply) error {
+	if err := w.Unwatch(args.WatchID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ListWatches lists the registered watches, as in Watcher.Watches
+func (w *Watcher) ListWatches(_ *ListWatchesArgs, reply *ListWatchesReply) error {
+	watches := w.Watches()
+	reply.Watches = make([]APIWatch, len(watches))
+	for i, watch := range watches {
+		reply.Watches[i] = APIWatch{
+			WatchID: watch.ID,
+			Address: w.format(watch.Address),
+			URL:     watch.URL,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,539 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watch
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	// SignatureHeader of a notification is "sha256=" followed by the hex
+	// encoded HMAC-SHA256 of the notification's body, keyed by the secret of
+	// the watch it's sent for
+	SignatureHeader = "X-Gecko-Signature"
+
+	// Defaults of the Watcher's retry parameters
+	DefaultMaxAttempts    = 8
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 5 * time.Minute
+
+	secretLen       = 32
+	queueSize       = 1024
+	numDeliverers   = 4
+	deliveryTimeout = 10 * time.Second
+)
+
+var (
+	watchesPrefix = []byte("watches")
+	pendingPrefix = []byte("pending")
+
+	// Networks that callbacks may only be sent to if their host is allowed
+	nonPublicNetworks = parseCIDRs(
+		"0.0.0.0/8",      // "This" network
+		"10.0.0.0/8",     // Private
+		"100.64.0.0/10",  // Carrier-grade NAT
+		"172.16.0.0/12",  // Private
+		"192.168.0.0/16", // Private
+		"fc00::/7",       // Unique local
+	)
+
+	errInvalidURL     = errors.New("callback URL must be an absolute http or https URL")
+	errHostNotAllowed = errors.New("callback URL's host isn't allowed")
+	errNoAddress      = errors.New("watched address must not be empty")
+	errUnknownWatch   = errors.New("unknown watch")
+)
+
+// Watch is a callback URL that's notified of the accepted txs that involve an
+// address
+type Watch struct {
+	ID      ids.ID      `serialize:"true"`
+	Address ids.ShortID `serialize:"true"`
+	URL     string      `serialize:"true"`
+
+	// Secret signs the notifications sent to [URL]
+	Secret []byte `serialize:"true"`
+}
+
+// Notification is POSTed, as JSON, to the URL of a watch when a tx that
+// involves its address is accepted
+type Notification struct {
+	WatchID ids.ID `json:"watchID"`
+	ChainID ids.ID `json:"chainID"`
+	TxID    ids.ID `json:"txID"`
+	Address string `json:"address"`
+}
+
+// delivery is a notification that's being sent. Deliveries are stored until
+// they're sent or given up on, so they survive restarts.
+type delivery struct {
+	ID       ids.ID
+	WatchID  ids.ID `serialize:"true"`
+	Body     []byte `serialize:"true"`
+	Attempts uint32 `serialize:"true"`
+}
+
+// Watcher keeps the watches registered on a chain, and notifies them of the
+// accepted txs that involve their addresses. A notification that fails is
+// retried, with exponential backoff, until it's been attempted [MaxAttempts]
+// times.
+//
+// Watches are local to this node. They, and the notifications that weren't
+// sent yet, are kept in their own database so they survive restarts. The txs
+// accepted while the chain is bootstrapping were accepted long ago, so their
+// watches aren't notified of them.
+//
+// Anyone who can register a watch can make this node send requests to the
+// callback URL. If [AllowedHosts] is empty, callbacks may be sent to any host
+// whose addresses are public, and never to loopback, link-local or private
+// addresses. Otherwise, they may only be sent to [AllowedHosts], whatever
+// their addresses.
+type Watcher struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	AllowedHosts   []string
+	Client         *http.Client
+
+	log       logging.Logger
+	watchesDB database.Database
+	pendingDB database.Database
+	codec     codec.Codec
+	chainID   ids.ID
+
+	// format returns the string representation of an address on the chain
+	format func(ids.ShortID) string
+
+	lock         sync.RWMutex
+	watches      map[[32]byte]*Watch
+	byAddr       map[[20]byte]map[[32]byte]*Watch
+	bootstrapped bool
+
+	queue  chan *delivery
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Initialize the watcher of the chain [chainID], whose watches are stored in
+// [db], and start sending notifications. [format] returns the string
+// representation of an address on the chain.
+func (w *Watcher) Initialize(log logging.Logger, db database.Database, chainID ids.ID, format func(ids.ShortID) string) error {
+	if w.MaxAttempts <= 0 {
+		w.MaxAttempts = DefaultMaxAttempts
+	}
+	if w.InitialBackoff <= 0 {
+		w.InitialBackoff = DefaultInitialBackoff
+	}
+	if w.MaxBackoff <= 0 {
+		w.MaxBackoff = DefaultMaxBackoff
+	}
+	if w.Client == nil {
+		w.Client = w.newClient()
+	}
+	w.log = log
+	w.watchesDB = prefixdb.New(watchesPrefix, db)
+	w.pendingDB = prefixdb.New(pendingPrefix, db)
+	w.codec = codec.NewDefault()
+	w.chainID = chainID
+	w.format = format
+	w.watches = make(map[[32]byte]*Watch)
+	w.byAddr = make(map[[20]byte]map[[32]byte]*Watch)
+	w.queue = make(chan *delivery, queueSize)
+	w.closed = make(chan struct{})
+
+	iter := w.watchesDB.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		watch := &Watch{}
+		if err := w.codec.Unmarshal(iter.Value(), watch); err != nil {
+			return fmt.Errorf("couldn't parse watch: %w", err)
+		}
+		w.add(watch)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	pending, err := w.pending()
+	if err != nil {
+		return err
+	}
+
+	w.wg.Add(numDeliverers)
+	for i := 0; i < numDeliverers; i++ {
+		go w.deliverAll()
+	}
+	for _, d := range pending {
+		w.enqueue(d)
+	}
+	return nil
+}
+
+// pending returns the stored deliveries
+func (w *Watcher) pending() ([]*delivery, error) {
+	iter := w.pendingDB.NewIterator()
+	defer iter.Release()
+
+	pending := []*delivery(nil)
+	for iter.Next() {
+		d := &delivery{}
+		if err := w.codec.Unmarshal(iter.Value(), d); err != nil {
+			return nil, fmt.Errorf("couldn't parse notification: %w", err)
+		}
+		d.ID = ids.NewID(hashing.ComputeHash256Array(d.Body))
+		pending = append(pending, d)
+	}
+	return pending, iter.Error()
+}
+
+// Bootstrapped is called when the chain finishes bootstrapping. Only the txs
+// accepted after that are notified.
+func (w *Watcher) Bootstrapped() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.bootstrapped = true
+}
+
+// Shutdown stops sending notifications. Notifications that weren't sent yet
+// are sent once the watcher is initialized again.
+func (w *Watcher) Shutdown() {
+	if w.closed == nil {
+		return
+	}
+	close(w.closed)
+	w.wg.Wait()
+}
+
+// Watch [address], so [callbackURL] is notified when a tx that involves it is
+// accepted. It returns the new watch, whose secret signs its notifications.
+func (w *Watcher) Watch(address ids.ShortID, callbackURL string) (*Watch, error) {
+	if address.IsZero() {
+		return nil, errNoAddress
+	}
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errInvalidURL
+	}
+	if !w.hostAllowed(u.Hostname()) {
+		return nil, errHostNotAllowed
+	}
+
+	secret := make([]byte, secretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	watch := &Watch{
+		ID:      ids.NewID(hashing.ComputeHash256Array(secret)),
+		Address: address,
+		URL:     callbackURL,
+		Secret:  secret,
+	}
+	watchBytes, err := w.codec.Marshal(watch)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.watchesDB.Put(watch.ID.Bytes(), watchBytes); err != nil {
+		return nil, err
+	}
+	w.add(watch)
+	return watch, nil
+}
+
+// Unwatch removes the watch with ID [watchID]. Its notifications that weren't
+// sent yet are dropped.
+func (w *Watcher) Unwatch(watchID ids.ID) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	watch, ok := w.watches[watchID.Key()]
+	if !ok {
+		return errUnknownWatch
+	}
+	if err := w.watchesDB.Delete(watchID.Bytes()); err != nil {
+		return err
+	}
+	delete(w.watches, watchID.Key())
+	addrWatches := w.byAddr[watch.Address.Key()]
+	delete(addrWatches, watchID.Key())
+	if len(addrWatches) == 0 {
+		delete(w.byAddr, watch.Address.Key())
+	}
+	return nil
+}
+
+// Watches returns the registered watches, sorted by ID
+func (w *Watcher) Watches() []*Watch {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	watches := make([]*Watch, 0, len(w.watches))
+	for _, watch := range w.watches {
+		watches = append(watches, watch)
+	}
+	sort.Slice(watches, func(i, j int) bool {
+		return bytes.Compare(watches[i].ID.Bytes(), watches[j].ID.Bytes()) < 0
+	})
+	return watches
+}
+
+// Notify the watches of [addresses] that the tx [txID], which involves them,
+// was accepted. The notifications are sent in the background. Nothing is
+// notified until the chain has finished bootstrapping.
+func (w *Watcher) Notify(txID ids.ID, addresses []ids.ShortID) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if !w.bootstrapped {
+		return
+	}
+	notified := make(map[[32]byte]bool)
+	for _, address := range addresses {
+		for watchID, watch := range w.byAddr[address.Key()] {
+			if notified[watchID] {
+				continue
+			}
+			notified[watchID] = true
+
+			body, err := json.Marshal(Notification{
+				WatchID: watch.ID,
+				ChainID: w.chainID,
+				TxID:    txID,
+				Address: w.format(watch.Address),
+			})
+			if err != nil {
+				w.log.Error("couldn't create the notification of tx %s: %s", txID, err)
+				continue
+			}
+			d := &delivery{
+				ID:      ids.NewID(hashing.ComputeHash256Array(body)),
+				WatchID: watch.ID,
+				Body:    body,
+			}
+			if err := w.store(d); err != nil {
+				w.log.Error("couldn't store the notification of tx %s: %s", txID, err)
+				continue
+			}
+			w.enqueue(d)
+		}
+	}
+}
+
+// store [d], so it's sent after a restart if it isn't sent before
+func (w *Watcher) store(d *delivery) error {
+	deliveryBytes, err := w.codec.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return w.pendingDB.Put(d.ID.Bytes(), deliveryBytes)
+}
+
+// remove [d], which was sent or given up on
+func (w *Watcher) remove(d *delivery) {
+	if err := w.pendingDB.Delete(d.ID.Bytes()); err != nil {
+		w.log.Error("couldn't remove the notification %s: %s", d.ID, err)
+	}
+}
+
+func (w *Watcher) add(watch *Watch) {
+	w.watches[watch.ID.Key()] = watch
+	addrWatches, ok := w.byAddr[watch.Address.Key()]
+	if !ok {
+		addrWatches = make(map[[32]byte]*Watch)
+		w.byAddr[watch.Address.Key()] = addrWatches
+	}
+	addrWatches[watch.ID.Key()] = watch
+}
+
+// watch returns the watch with ID [watchID], or nil if it was removed
+func (w *Watcher) watch(watchID ids.ID) *Watch {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.watches[watchID.Key()]
+}
+
+// enqueue [d] to be sent. If too many notifications are queued, it's enqueued
+// again later.
+func (w *Watcher) enqueue(d *delivery) {
+	select {
+	case w.queue <- d:
+	default:
+		w.log.Debug("delaying notification %s because too many are queued", d.ID)
+		w.enqueueAfter(d, w.InitialBackoff)
+	}
+}
+
+// enqueueAfter enqueues [d] after [delay], unless the watcher was shut down
+func (w *Watcher) enqueueAfter(d *delivery, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		select {
+		case <-w.closed:
+		default:
+			w.enqueue(d)
+		}
+	})
+}
+
+func (w *Watcher) deliverAll() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.closed:
+			return
+		case d := <-w.queue:
+			w.deliver(d)
+		}
+	}
+}
+
+// deliver [d], and schedule it to be retried if it fails
+func (w *Watcher) deliver(d *delivery) {
+	watch := w.watch(d.WatchID)
+	if watch == nil {
+		w.remove(d)
+		return
+	}
+	err := w.post(watch, d)
+	if err == nil {
+		w.remove(d)
+		return
+	}
+
+	d.Attempts++
+	if int(d.Attempts) >= w.MaxAttempts {
+		w.log.Warn("gave up notifying %s after %d attempts: %s", watch.URL, d.Attempts, err)
+		w.remove(d)
+		return
+	}
+	if err := w.store(d); err != nil {
+		w.log.Error("couldn't store the notification %s: %s", d.ID, err)
+	}
+	backoff := w.InitialBackoff << (d.Attempts - 1)
+	if backoff > w.MaxBackoff || backoff <= 0 {
+		backoff = w.MaxBackoff
+	}
+	w.log.Debug("notifying %s failed with %s. Retrying in %s", watch.URL, err, backoff)
+	w.enqueueAfter(d, backoff)
+}
+
+func (w *Watcher) post(watch *Watch, d *delivery) error {
+	request, err := http.NewRequest("POST", watch.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(watch.Secret, d.Body))
+
+	response, err := w.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("callback responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// hostAllowed returns true if callbacks may be sent to [host]
+func (w *Watcher) hostAllowed(host string) bool {
+	if len(w.AllowedHosts) > 0 {
+		for _, allowed := range w.AllowedHosts {
+			if host == allowed {
+				return true
+			}
+		}
+		return false
+	}
+	if host == "localhost" {
+		return false
+	}
+	// A host name's addresses are checked when a callback is sent to it
+	ip := net.ParseIP(host)
+	return ip == nil || publicIP(ip)
+}
+
+// newClient returns the client that sends callbacks. Unless the allowed hosts
+// are set, it refuses to connect to addresses that aren't public. It never
+// follows redirects, which could point anywhere.
+func (w *Watcher) newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if len(w.AllowedHosts) == 0 {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errHostNotAllowed
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicIP returns true if [ip] is a public unicast address
+func publicIP(ip net.IP) bool {
+	if !ip.IsGlobalUnicast() || ip.IsLoopback() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// Sign returns the value of the SignatureHeader of a notification with [body]
+// sent for a watch with [secret]
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
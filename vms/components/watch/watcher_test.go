@@ -0,0 +1,219 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestWatcher(t *testing.T) {
+	type received struct {
+		notification Notification
+		signature    string
+		body         []byte
+	}
+	notifications := make(chan received, 4)
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// The first notification fails, so it's retried
+		if failures > 0 {
+			failures--
+			http.Error(writer, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(request.Body)
+		r := received{signature: request.Header.Get(SignatureHeader), body: body}
+		if err := json.Unmarshal(body, &r.notification); err != nil {
+			t.Error(err)
+		}
+		notifications <- r
+	}))
+	defer server.Close()
+
+	db := memdb.New()
+	chainID := ids.NewID([32]byte{1})
+	format := func(address ids.ShortID) string { return "X-" + address.String() }
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedHosts := []string{serverURL.Hostname()}
+	w := &Watcher{InitialBackoff: time.Millisecond, AllowedHosts: allowedHosts}
+	if err := w.Initialize(logging.NoLog{}, db, chainID, format); err != nil {
+		t.Fatal(err)
+	}
+
+	address := ids.NewShortID([20]byte{2})
+	if _, err := w.Watch(address, "ftp://example.com"); err != errInvalidURL {
+		t.Fatalf("expected %s but got %v", errInvalidURL, err)
+	}
+	if _, err := w.Watch(address, "http://example.com"); err != errHostNotAllowed {
+		t.Fatalf("expected %s but got %v", errHostNotAllowed, err)
+	}
+	watch, err := w.Watch(address, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Txs accepted while bootstrapping aren't notified
+	txID := ids.NewID([32]byte{3})
+	w.Notify(txID, []ids.ShortID{address})
+	select {
+	case <-notifications:
+		t.Fatal("shouldn't have notified a tx accepted while bootstrapping")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if pending, err := w.pending(); err != nil || len(pending) != 0 {
+		t.Fatalf("shouldn't have stored a notification while bootstrapping")
+	}
+
+	w.Bootstrapped()
+	w.Notify(txID, []ids.ShortID{ids.NewShortID([20]byte{4}), address, address})
+	select {
+	case r := <-notifications:
+		if !r.notification.WatchID.Equals(watch.ID) || !r.notification.TxID.Equals(txID) || !r.notification.ChainID.Equals(chainID) {
+			t.Fatalf("unexpected notification %+v", r.notification)
+		}
+		if r.notification.Address != format(address) {
+			t.Fatalf("expected the address %s but got %s", format(address), r.notification.Address)
+		}
+		if r.signature != Sign(watch.Secret, r.body) {
+			t.Fatalf("notification has the wrong signature")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("should have retried the notification")
+	}
+	select {
+	case <-notifications:
+		t.Fatal("should have notified the watch once")
+	case <-time.After(50 * time.Millisecond):
+	}
+	w.Shutdown()
+
+	if pending, err := w.pending(); err != nil || len(pending) != 0 {
+		t.Fatalf("should have removed the sent notification")
+	}
+
+	// Watches survive restarts
+	w = &Watcher{AllowedHosts: allowedHosts}
+	if err := w.Initialize(logging.NoLog{}, db, chainID, format); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Shutdown()
+	if watches := w.Watches(); len(watches) != 1 || !watches[0].ID.Equals(watch.ID) {
+		t.Fatalf("should have restored the watch")
+	}
+	if err := w.Unwatch(watch.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Unwatch(watch.ID); err != errUnknownWatch {
+		t.Fatalf("expected %s but got %v", errUnknownWatch, err)
+	}
+	if watches := w.Watches(); len(watches) != 0 {
+		t.Fatalf("should have removed the watch")
+	}
+}
+
+func TestWatcherResumesNotifications(t *testing.T) {
+	notified := make(chan struct{}, 1)
+	available := uint32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.LoadUint32(&available) == 0 {
+			http.Error(writer, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		notified <- struct{}{}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedHosts := []string{serverURL.Hostname()}
+
+	db := memdb.New()
+	chainID := ids.NewID([32]byte{1})
+	format := func(address ids.ShortID) string { return address.String() }
+	w := &Watcher{InitialBackoff: time.Hour, AllowedHosts: allowedHosts}
+	if err := w.Initialize(logging.NoLog{}, db, chainID, format); err != nil {
+		t.Fatal(err)
+	}
+	w.Bootstrapped()
+	address := ids.NewShortID([20]byte{2})
+	if _, err := w.Watch(address, server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	// The notification fails, and is shut down before it's retried
+	w.Notify(ids.NewID([32]byte{3}), []ids.ShortID{address})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pending, err := w.pending()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) == 1 && pending[0].Attempts == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("should have stored the failed notification")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	w.Shutdown()
+
+	// The notification is sent once the watcher is initialized again
+	atomic.StoreUint32(&available, 1)
+	w = &Watcher{AllowedHosts: allowedHosts}
+	if err := w.Initialize(logging.NoLog{}, db, chainID, format); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Shutdown()
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("should have sent the stored notification")
+	}
+}
+
+func TestWatcherRestrictsHosts(t *testing.T) {
+	w := &Watcher{}
+	for _, host := range []string{"localhost", "127.0.0.1", "10.0.0.1", "192.168.1.1", "169.254.169.254", "::1", "fd00::1", "0.0.0.0"} {
+		if w.hostAllowed(host) {
+			t.Fatalf("shouldn't allow callbacks to %s", host)
+		}
+	}
+	for _, host := range []string{"example.com", "8.8.8.8", "2001:4860:4860::8888"} {
+		if !w.hostAllowed(host) {
+			t.Fatalf("should allow callbacks to %s", host)
+		}
+	}
+
+	// Host names that resolve to addresses that aren't public are refused
+	// when the callback is sent
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("shouldn't have connected to a loopback address")
+	}))
+	defer server.Close()
+	if _, err := w.newClient().Post(server.URL, "application/json", nil); err == nil {
+		t.Fatal("should have refused to connect to a loopback address")
+	}
+
+	// Allowed hosts are allowed whatever their addresses
+	w = &Watcher{AllowedHosts: []string{"127.0.0.1"}}
+	if !w.hostAllowed("127.0.0.1") || w.hostAllowed("example.com") {
+		t.Fatal("should only allow callbacks to the allowed hosts")
+	}
+}
@@ -27,6 +27,17 @@ const (
 	GenesisTestKey  = "0xabd71b35d559563fea757f0f5edbde286fb8c043105b15abb7cd57189306d7d1"
 )
 
+// WriteMethods are the API methods that issue txs or change the accounts
+// held by the node. Read-only API replicas don't serve them.
+var WriteMethods = []string{
+	"eth_sendRawTransaction",
+	"eth_sendTransaction",
+	"personal_newAccount",
+	"personal_importRawKey",
+	"personal_sendTransaction",
+	"personal_signAndSendTransaction",
+}
+
 // DebugAPI introduces helper functions for debuging
 type DebugAPI struct{ vm *VM }
 
@@ -56,6 +56,7 @@ var (
 	errUnknownBlock   = errors.New("unknown block")
 	errBlockFrequency = errors.New("too frequent block issuance")
 	errUnsupportedFXs = errors.New("unsupported feature extensions")
+	errNoChainConfig  = errors.New("genesis is missing its chain config")
 )
 
 func maxDuration(x, y time.Duration) time.Duration {
@@ -347,6 +348,21 @@ func (vm *VM) CreateStaticHandlers() map[string]*commonEng.HTTPHandler {
 	}
 }
 
+// VerifyGenesis implements the commonEng.GenesisVerifier interface
+func (vm *VM) VerifyGenesis(_ snow.Limits, b []byte, fxs []*commonEng.Fx) error {
+	if len(fxs) > 0 {
+		return errUnsupportedFXs
+	}
+	g := new(core.Genesis)
+	if err := json.Unmarshal(b, g); err != nil {
+		return err
+	}
+	if g.Config == nil {
+		return errNoChainConfig
+	}
+	return nil
+}
+
 /*
  ******************************************************************************
  *********************************** Helpers **********************************
@@ -12,46 +12,61 @@ import (
 // being rejected.
 type Abort struct {
 	CommonDecisionBlock `serialize:"true"`
+
+	// AccountRoot is the root of the account trie once this block is accepted
+	AccountRoot ids.ID `serialize:"true"`
 }
 
+// accountRoot implements the rootedBlock interface
+func (a *Abort) accountRoot() ids.ID { return a.AccountRoot }
+
 // Verify this block performs a valid state transition.
 //
 // The parent block must be a proposal
 //
 // This function also sets onAcceptDB database if the verification passes.
-func (a *Abort) Verify() error {
+func (a *Abort) Verify() error { return a.verifyAbort(a, &a.AccountRoot) }
+
+// verifyAbort verifies [blk], an abort block that commits to the account root
+// [root], or to none if [root] is nil
+func (cdb *CommonDecisionBlock) verifyAbort(blk Block, root *ids.ID) error {
 	// Abort is a decision, so its parent must be a proposal
-	if parent, ok := a.parentBlock().(*ProposalBlock); ok {
-		a.onAcceptDB, a.onAcceptFunc = parent.onAbort()
-	} else {
+	parent, ok := cdb.parentBlock().(*ProposalBlock)
+	if !ok {
 		return errInvalidBlockType
 	}
+	cdb.onAcceptDB, cdb.onAcceptFunc = parent.onAbort()
+	if err := cdb.vm.verifyAccountRoot(cdb.onAcceptDB, root); err != nil {
+		return err
+	}
 
-	a.vm.currentBlocks[a.ID().Key()] = a
-	a.parentBlock().addChild(a)
+	cdb.vm.currentBlocks[blk.ID().Key()] = blk
+	parent.addChild(blk)
 	return nil
 }
 
-// newAbortBlock returns a new *Abort block where the block's parent, a proposal
-// block, has ID [parentID].
-func (vm *VM) newAbortBlock(parentID ids.ID) *Abort {
-	abort := &Abort{
-		CommonDecisionBlock: CommonDecisionBlock{
-			CommonBlock: CommonBlock{
-				Block: core.NewBlock(parentID),
-				vm:    vm,
-			},
+// newAbortBlock returns a new abort block where the block's parent, a proposal
+// block, has ID [parentID]. The block commits to the account root [root], or
+// is a *legacyAbort if [root] is nil.
+func (vm *VM) newAbortBlock(parentID ids.ID, root *ids.ID) Block {
+	common := CommonDecisionBlock{
+		CommonBlock: CommonBlock{
+			Block: core.NewBlock(parentID),
+			vm:    vm,
 		},
 	}
+	var abort Block
+	if root == nil {
+		abort = &legacyAbort{CommonDecisionBlock: common}
+	} else {
+		abort = &Abort{CommonDecisionBlock: common, AccountRoot: *root}
+	}
 
 	// We serialize this block as a Block so that it can be deserialized into a
 	// Block
-	blk := Block(abort)
-	bytes, err := Codec.Marshal(&blk)
-	if err != nil {
+	bytes, err := Codec.Marshal(&abort)
+	if err != nil || abort.initialize(vm, bytes) != nil {
 		return nil
 	}
-
-	abort.Block.Initialize(bytes, vm.SnowmanVM)
 	return abort
 }
@@ -0,0 +1,133 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/merkle"
+)
+
+// The accounts are also stored in a Merkle trie, keyed by address, whose root
+// commits to every account. This lets the owner of an account prove its nonce
+// and balance to anyone who knows the root.
+//
+// Once the chain time reaches AccountRootTime, every decision block commits to
+// the root of the account trie in the state it results in, and the block is
+// invalid if the root is wrong. So a root is known to be right once a block
+// that commits to it is accepted.
+
+var (
+	errMissingAccountRoot      = errors.New("block doesn't commit to the account root")
+	errUnexpectedAccountRoot   = errors.New("block commits to the account root before it's activated")
+	errWrongAccountRoot        = errors.New("block commits to the wrong account root")
+	errAccountRootNotCommitted = errors.New("the last accepted decision block doesn't commit to an account root")
+)
+
+// rootedBlock is a decision block that commits to the root of the account trie
+// in the state it results in
+type rootedBlock interface {
+	accountRoot() ids.ID
+}
+
+var (
+	accountTriePrefix         = []byte("account trie")
+	accountTrieInitializedKey = ids.NewID([32]byte{'a', 'c', 'c', 'o', 'u', 'n', 't', ' ', 't', 'r', 'i', 'e'})
+)
+
+// accountTrie returns the database that the account trie is stored in, on top
+// of [db]
+func accountTrie(db database.Database) database.Database {
+	return prefixdb.New(accountTriePrefix, db)
+}
+
+// get the root of the account trie in [db]
+func (vm *VM) getAccountRoot(db database.Database) (ids.ID, error) {
+	return merkle.Root(accountTrie(db))
+}
+
+// accountRootCommitment returns the account root that a decision block that
+// results in the state [db] must commit to, or nil if it mustn't commit to one
+func (vm *VM) accountRootCommitment(db database.Database) (*ids.ID, error) {
+	chainTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	if !active(vm.Upgrades.AccountRootTime, chainTime) {
+		return nil, nil
+	}
+	root, err := vm.getAccountRoot(db)
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// verifyAccountRoot returns nil if [root] is the account root that a decision
+// block that results in the state [db] must commit to. [root] is nil if the
+// block doesn't commit to one.
+func (vm *VM) verifyAccountRoot(db database.Database, root *ids.ID) error {
+	expected, err := vm.accountRootCommitment(db)
+	switch {
+	case err != nil:
+		return err
+	case expected == nil && root == nil:
+		return nil
+	case expected == nil:
+		return errUnexpectedAccountRoot
+	case root == nil:
+		return errMissingAccountRoot
+	case !expected.Equals(*root):
+		return errWrongAccountRoot
+	default:
+		return nil
+	}
+}
+
+// get a proof of the account with address [address] against the root of the
+// account trie in [db]. If the account doesn't exist, the proof shows that it
+// isn't in the trie.
+func (vm *VM) getAccountProof(db database.Database, address ids.ShortID) (*merkle.Proof, error) {
+	exists, err := vm.State.Has(db, accountTypeID, address.LongID())
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	if exists {
+		account, err := vm.getAccount(db, address)
+		if err != nil {
+			return nil, err
+		}
+		value = account.Bytes()
+	}
+	return merkle.Prove(accountTrie(db), address, value)
+}
+
+// initAccountTrie builds the account trie from the accounts in the database if
+// the chain was created before the trie was maintained
+func (vm *VM) initAccountTrie() error {
+	if vm.State.GetStatus(vm.DB, accountTrieInitializedKey) == choices.Accepted {
+		return nil
+	}
+
+	accounts, err := vm.accounts(vm.DB)
+	if err != nil {
+		return err
+	}
+	trie := accountTrie(vm.DB)
+	for _, account := range accounts {
+		if err := merkle.Put(trie, account.Address, account.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := vm.State.PutStatus(vm.DB, accountTrieInitializedKey, choices.Accepted); err != nil {
+		return err
+	}
+	vm.Ctx.Log.Info("built the account trie from %d accounts", len(accounts))
+	return vm.DB.Commit()
+}
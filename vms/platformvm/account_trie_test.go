@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/merkle"
+)
+
+// acceptTransfer builds, verifies and accepts a block that transfers [amount]
+// from the account of keys[0] to [to]
+func acceptTransfer(t *testing.T, vm *VM, nonce uint64, to ids.ShortID, amount uint64) Block {
+	tx, err := vm.newTransferTx(nonce, to, amount, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	blk, err := vm.BuildBlock()
+	vm.Ctx.Lock.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept()
+	return blk.(Block)
+}
+
+func TestGetAccountProof(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	// The genesis block doesn't commit to an account root
+	reply := &GetAccountProofReply{}
+	if err := service.GetAccountProof(nil, &GetAccountProofArgs{Address: keys[0].PublicKey().Address()}, reply); err != errAccountRootNotCommitted {
+		t.Fatalf("should have failed with %s but got %v", errAccountRootNotCommitted, err)
+	}
+
+	verify := func(address ids.ShortID) *GetAccountProofReply {
+		reply := &GetAccountProofReply{}
+		if err := service.GetAccountProof(nil, &GetAccountProofArgs{Address: address}, reply); err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.getBlock(reply.BlockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root := blk.(rootedBlock).accountRoot(); !root.Equals(reply.Root) {
+			t.Fatalf("proved the account against %s but block %s commits to %s", reply.Root, reply.BlockID, root)
+		}
+		proof := merkle.Proof{
+			Key:      address,
+			Siblings: reply.Siblings,
+		}
+		if len(reply.Account.Bytes) != 0 {
+			proof.Value = reply.Account.Bytes
+		}
+		copy(proof.Bitmap[:], reply.Bitmap.Bytes)
+		if err := proof.Verify(reply.Root); err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+
+	to := ids.NewShortID([20]byte{1})
+	blk := acceptTransfer(t, vm, defaultNonce+1, to, 1)
+
+	address := keys[0].PublicKey().Address()
+	reply = verify(address)
+	if !reply.BlockID.Equals(blk.ID()) {
+		t.Fatalf("should have proved the account against the last accepted block")
+	}
+	account := Account{}
+	if err := Codec.Unmarshal(reply.Account.Bytes, &account); err != nil {
+		t.Fatal(err)
+	}
+	if !account.Address.Equals(address) || account.Balance != defaultBalance-txFee-1 {
+		t.Fatalf("proved the wrong account")
+	}
+
+	// Accounts that don't exist are proven to be absent
+	key, _ := vm.factory.NewPrivateKey()
+	if reply := verify(key.PublicKey().Address()); len(reply.Account.Bytes) != 0 {
+		t.Fatalf("proved an account that doesn't exist")
+	}
+
+	// Updating an account updates the root
+	acceptTransfer(t, vm, defaultNonce+2, to, 1)
+	if newReply := verify(address); newReply.Root.Equals(reply.Root) {
+		t.Fatalf("updating an account should have changed the root")
+	}
+
+	// Rebuilding the trie from the accounts results in the same root
+	root, err := vm.getAccountRoot(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.State.PutStatus(vm.DB, accountTrieInitializedKey, choices.Processing); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initAccountTrie(); err != nil {
+		t.Fatal(err)
+	}
+	if newRoot, err := vm.getAccountRoot(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if !newRoot.Equals(root) {
+		t.Fatalf("rebuilding the trie should have resulted in the same root")
+	}
+}
+
+func TestVerifyAccountRoot(t *testing.T) {
+	vm := defaultVM()
+	to := ids.NewShortID([20]byte{1})
+
+	newBlock := func(root *ids.ID) Block {
+		tx, err := vm.newTransferTx(defaultNonce+1, to, 1, keys[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.newStandardBlock(vm.LastAccepted(), []DecisionTx{tx}, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk
+	}
+
+	// Only a block that commits to the root of the state it results in is
+	// valid
+	blk := newBlock(&ids.Empty)
+	if err := blk.Verify(); err != errWrongAccountRoot {
+		t.Fatalf("should have failed with %s but got %v", errWrongAccountRoot, err)
+	}
+	root, err := vm.accountRootCommitment(blk.(*StandardBlock).onAcceptDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newBlock(root).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := newBlock(nil).Verify(); err != errMissingAccountRoot {
+		t.Fatalf("should have failed with %s but got %v", errMissingAccountRoot, err)
+	}
+
+	// Before the account root is committed to, blocks mustn't commit to one
+	vm.Upgrades.AccountRootTime = defaultGenesisTime.Add(time.Second)
+	if err := newBlock(root).Verify(); err != errUnexpectedAccountRoot {
+		t.Fatalf("should have failed with %s but got %v", errUnexpectedAccountRoot, err)
+	}
+	blk = newBlock(nil)
+	if _, ok := blk.(*legacyStandardBlock); !ok {
+		t.Fatalf("should have built a legacy block")
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks built before the account root is committed to keep the type ID
+	// that standard blocks had before
+	if typeID := binary.BigEndian.Uint32(blk.Bytes()); typeID != 3 {
+		t.Fatalf("legacy standard blocks should have type ID 3 but have %d", typeID)
+	}
+	parsed, err := vm.unmarshalBlockFunc(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.(*legacyStandardBlock); !ok || !parsed.ID().Equals(blk.ID()) {
+		t.Fatalf("should have parsed the legacy block")
+	}
+}
@@ -4,16 +4,24 @@
 package platformvm
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
 )
 
-// UnsignedAddDefaultSubnetDelegatorTx is an unsigned addDefaultSubnetDelegatorTx
+var (
+	errDelegationTooSmall    = errors.New("amount delegated is too low")
+	errDelegationCapExceeded = errors.New("delegation would exceed the validator's delegation cap")
+)
+
+// UnsignedAddDefaultSubnetDelegatorTx is an unsigned AddDefaultSubnetDelegatorTx
 type UnsignedAddDefaultSubnetDelegatorTx struct {
 	DurationValidator `serialize:"true"`
 	NetworkID         uint32      `serialize:"true"`
@@ -21,13 +29,13 @@ type UnsignedAddDefaultSubnetDelegatorTx struct {
 	Destination       ids.ShortID `serialize:"true"`
 }
 
-// addDefaultSubnetDelegatorTx is a transaction that, if it is in a
+// AddDefaultSubnetDelegatorTx is a transaction that, if it is in a
 // ProposalBlock that is accepted and followed by a Commit block, adds a
 // delegator to the pending validator set of the default subnet. (That is, the
 // validator in the tx will have their weight increase at some point in the
 // future.) The transaction fee will be paid from the account who signed the
 // transaction.
-type addDefaultSubnetDelegatorTx struct {
+type AddDefaultSubnetDelegatorTx struct {
 	UnsignedAddDefaultSubnetDelegatorTx `serialize:"true"`
 
 	// Sig is the signature of the public key whose corresponding account pays
@@ -44,7 +52,7 @@ type addDefaultSubnetDelegatorTx struct {
 }
 
 // initialize [tx]
-func (tx *addDefaultSubnetDelegatorTx) initialize(vm *VM) error {
+func (tx *AddDefaultSubnetDelegatorTx) initialize(vm *VM) error {
 	tx.vm = vm
 	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
 	tx.bytes = bytes
@@ -52,11 +60,25 @@ func (tx *addDefaultSubnetDelegatorTx) initialize(vm *VM) error {
 	return err
 }
 
-func (tx *addDefaultSubnetDelegatorTx) ID() ids.ID { return tx.id }
+func (tx *AddDefaultSubnetDelegatorTx) ID() ids.ID { return tx.id }
+
+// verifySignatures implements the signedTx interface
+func (tx *AddDefaultSubnetDelegatorTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetDelegatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
 
 // SyntacticVerify return nil iff [tx] is valid
 // If [tx] is valid, sets [tx.accountID]
-func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
+func (tx *AddDefaultSubnetDelegatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
@@ -73,11 +95,8 @@ func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
 	}
 
 	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetDelegatorTx)
@@ -98,21 +117,26 @@ func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 }
 
 // SemanticVerify this transaction is valid.
-func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
+func (tx *AddDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
 	if err := tx.SyntacticVerify(); err != nil {
 		return nil, nil, nil, nil, err
 	}
 
-	// Ensure the proposed validator starts after the current timestamp
+	// Ensure the proposed validator starts after the current timestamp, but
+	// not too long after it
 	currentTimestamp, err := tx.vm.getTimestamp(db)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
 	validatorStartTime := tx.StartTime()
-	if !currentTimestamp.Before(validatorStartTime) {
-		return nil, nil, nil, nil, fmt.Errorf("chain timestamp (%s) not before validator's start time (%s)",
-			currentTimestamp,
-			validatorStartTime)
+	if err := tx.vm.verifyStartTime(currentTimestamp, validatorStartTime); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// Ensure delegator is delegating at least the minimum amount
+	limitDelegation := active(tx.vm.Upgrades.DelegationLimitsTime, currentTimestamp)
+	if limitDelegation && tx.Wght < tx.vm.minDelegationAmount {
+		return nil, nil, nil, nil, errDelegationTooSmall
 	}
 
 	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
@@ -137,29 +161,41 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't get current validators of default subnet: %v", err)
 	}
-	if dsValidator, err := currentEvents.getDefaultSubnetStaker(tx.NodeID); err == nil {
-		if !tx.DurationValidator.BoundedBy(dsValidator.StartTime(), dsValidator.EndTime()) {
-			return nil, nil, nil, nil, errDSValidatorSubset
-		}
-	} else {
+	pendingEvents, err := tx.vm.getPendingValidators(db, DefaultSubnetID)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("couldn't get pending validators of default subnet: %v", err)
+	}
+	dsValidator, err := currentEvents.getDefaultSubnetStaker(tx.NodeID)
+	if err != nil {
 		// They aren't currently validating the default subnet.
 		// See if they will validate the default subnet in the future.
-		pendingDSValidators, err := tx.vm.getPendingValidators(db, DefaultSubnetID)
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("couldn't get pending validators of default subnet: %v", err)
-		}
-		dsValidator, err := pendingDSValidators.getDefaultSubnetStaker(tx.NodeID)
+		dsValidator, err = pendingEvents.getDefaultSubnetStaker(tx.NodeID)
 		if err != nil {
 			return nil, nil, nil, nil, errDSValidatorSubset
 		}
-		if !tx.DurationValidator.BoundedBy(dsValidator.StartTime(), dsValidator.EndTime()) {
-			return nil, nil, nil, nil, errDSValidatorSubset
-		}
+	}
+	if !tx.DurationValidator.BoundedBy(dsValidator.StartTime(), dsValidator.EndTime()) {
+		return nil, nil, nil, nil, errDSValidatorSubset
 	}
 
-	pendingEvents, err := tx.vm.getPendingValidators(db, DefaultSubnetID)
-	if err != nil {
-		return nil, nil, nil, nil, err
+	// Ensure the validator's own stake plus the most stake delegated to it
+	// while this delegator delegates stays within its delegation cap
+	if limitDelegation {
+		delegated, err := peakDelegatedStake(tx.NodeID, tx.StartTime(), tx.EndTime(), currentEvents, pendingEvents)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		totalStake, err := math.Add64(dsValidator.Wght, delegated)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if totalStake, err = math.Add64(totalStake, tx.Wght); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		// If the cap overflows, no total stake can exceed it
+		if maxStake, err := math.Mul64(dsValidator.Wght, tx.vm.delegationCapMultiplier); err == nil && totalStake > maxStake {
+			return nil, nil, nil, nil, errDelegationCapExceeded
+		}
 	}
 
 	pendingEvents.Add(tx) // add validator to set of pending validators
@@ -180,9 +216,71 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 	return onCommitDB, onAbortDB, nil, nil, nil
 }
 
+// delegatedStake returns the amount of $AVA delegated to the validator with ID
+// [nodeID] by the delegators in [heaps]
+func delegatedStake(nodeID ids.ShortID, heaps ...*EventHeap) (uint64, error) {
+	delegated := uint64(0)
+	for _, h := range heaps {
+		for _, txIntf := range h.Txs {
+			tx, ok := txIntf.(*AddDefaultSubnetDelegatorTx)
+			if !ok || !nodeID.Equals(tx.NodeID) {
+				continue
+			}
+			newDelegated, err := math.Add64(delegated, tx.Wght)
+			if err != nil {
+				return 0, err
+			}
+			delegated = newDelegated
+		}
+	}
+	return delegated, nil
+}
+
+// peakDelegatedStake returns the most $AVA delegated to the validator with ID
+// [nodeID] at any one time between [startTime] and [endTime] by the delegators
+// in [heaps]. A delegator counts from its start time to its end time,
+// inclusive.
+func peakDelegatedStake(nodeID ids.ShortID, startTime, endTime time.Time, heaps ...*EventHeap) (uint64, error) {
+	delegators := []*AddDefaultSubnetDelegatorTx(nil)
+	for _, h := range heaps {
+		for _, txIntf := range h.Txs {
+			tx, ok := txIntf.(*AddDefaultSubnetDelegatorTx)
+			if !ok || !nodeID.Equals(tx.NodeID) || tx.EndTime().Before(startTime) || tx.StartTime().After(endTime) {
+				continue
+			}
+			delegators = append(delegators, tx)
+		}
+	}
+
+	// The delegated stake only grows when a delegator starts, so it peaks at
+	// [startTime] or at the start time of one of the delegators
+	peak := uint64(0)
+	for _, start := range delegators {
+		peakTime := start.StartTime()
+		if peakTime.Before(startTime) {
+			peakTime = startTime
+		}
+		delegated := uint64(0)
+		for _, tx := range delegators {
+			if tx.StartTime().After(peakTime) || tx.EndTime().Before(peakTime) {
+				continue
+			}
+			newDelegated, err := math.Add64(delegated, tx.Wght)
+			if err != nil {
+				return 0, err
+			}
+			delegated = newDelegated
+		}
+		if delegated > peak {
+			peak = delegated
+		}
+	}
+	return peak, nil
+}
+
 // InitiallyPrefersCommit returns true if the proposed validators start time is
 // after the current wall clock time,
-func (tx *addDefaultSubnetDelegatorTx) InitiallyPrefersCommit() bool {
+func (tx *AddDefaultSubnetDelegatorTx) InitiallyPrefersCommit() bool {
 	return tx.StartTime().After(tx.vm.clock.Time())
 }
 
@@ -195,8 +293,8 @@ func (vm *VM) newAddDefaultSubnetDelegatorTx(
 	destination ids.ShortID,
 	networkID uint32,
 	key *crypto.PrivateKeySECP256K1R,
-) (*addDefaultSubnetDelegatorTx, error) {
-	tx := &addDefaultSubnetDelegatorTx{
+) (*AddDefaultSubnetDelegatorTx, error) {
+	tx := &AddDefaultSubnetDelegatorTx{
 		UnsignedAddDefaultSubnetDelegatorTx: UnsignedAddDefaultSubnetDelegatorTx{
 			DurationValidator: DurationValidator{
 				Validator: Validator{
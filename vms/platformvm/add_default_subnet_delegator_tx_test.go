@@ -15,7 +15,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 	vm := defaultVM()
 
 	// Case 1: tx is nil
-	var tx *addDefaultSubnetDelegatorTx
+	var tx *AddDefaultSubnetDelegatorTx
 	if err := tx.SyntacticVerify(); err == nil {
 		t.Fatal("should have errored because tx is nil")
 	}
@@ -377,3 +377,67 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	}
 	txFee = txFeeSaved // Reset tx fee
 }
+
+func TestAddDefaultSubnetDelegatorTxLimits(t *testing.T) {
+	vm := defaultVM()
+	nodeID := defaultKey.PublicKey().Address() // a genesis validator staking defaultStakeAmount
+	newTx := func(nonce, weight uint64, startTime, endTime time.Time) *AddDefaultSubnetDelegatorTx {
+		tx, err := vm.newAddDefaultSubnetDelegatorTx(
+			nonce,
+			weight,
+			uint64(startTime.Unix()),
+			uint64(endTime.Unix()),
+			nodeID,
+			defaultKey.PublicKey().Address(),
+			testNetworkID,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// Case 1: Delegates less than the minimum delegation amount
+	vm.minDelegationAmount = 2 * DefaultMinimumDelegationAmount
+	tx := newTx(defaultNonce+1, DefaultMinimumDelegationAmount, defaultValidateStartTime, defaultValidateEndTime)
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != errDelegationTooSmall {
+		t.Fatalf("should have failed because the delegation is too small but got %v", err)
+	}
+
+	// Case 2: The limits aren't enforced before they're activated
+	vm.Upgrades.DelegationLimitsTime = defaultGenesisTime.Add(time.Second)
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != nil {
+		t.Fatalf("should have allowed the delegation before the limits are activated: %s", err)
+	}
+	vm.Upgrades.DelegationLimitsTime = time.Time{}
+	vm.minDelegationAmount = DefaultMinimumDelegationAmount
+
+	// Case 3: Fills the validator's delegation cap for the first day
+	fillEndTime := defaultValidateStartTime.Add(MinimumStakingDuration)
+	fillTx := newTx(defaultNonce+1, (DefaultDelegationCapMultiplier-1)*defaultStakeAmount, defaultValidateStartTime, fillEndTime)
+	onCommitDB, _, _, _, err := fillTx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatalf("should have allowed delegating up to the cap: %s", err)
+	}
+
+	// Case 4: Exceeds the validator's delegation cap while it's filled
+	tx = newTx(defaultNonce+2, DefaultMinimumDelegationAmount, fillEndTime, defaultValidateEndTime)
+	if _, _, _, _, err := tx.SemanticVerify(onCommitDB); err != errDelegationCapExceeded {
+		t.Fatalf("should have failed because the delegation cap is exceeded but got %v", err)
+	}
+
+	// Case 5: Doesn't overlap with the delegation filling the cap
+	startTime := fillEndTime.Add(time.Second)
+	tx = newTx(defaultNonce+2, (DefaultDelegationCapMultiplier-1)*defaultStakeAmount, startTime, defaultValidateEndTime)
+	if _, _, _, _, err := tx.SemanticVerify(onCommitDB); err != nil {
+		t.Fatalf("should have allowed a delegation after the cap is no longer filled: %s", err)
+	}
+
+	// Case 6: A higher cap allows the overlapping delegation
+	vm.delegationCapMultiplier = DefaultDelegationCapMultiplier + 1
+	tx = newTx(defaultNonce+2, DefaultMinimumDelegationAmount, defaultValidateStartTime, defaultValidateEndTime)
+	if _, _, _, _, err := tx.SemanticVerify(onCommitDB); err != nil {
+		t.Fatalf("should have allowed the delegation under a higher cap: %s", err)
+	}
+}
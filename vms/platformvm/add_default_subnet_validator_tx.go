@@ -24,7 +24,7 @@ var (
 	errTooManyShares  = fmt.Errorf("a staker can only require at most %d shares from delegators", NumberOfShares)
 )
 
-// UnsignedAddDefaultSubnetValidatorTx is an unsigned addDefaultSubnetValidatorTx
+// UnsignedAddDefaultSubnetValidatorTx is an unsigned AddDefaultSubnetValidatorTx
 type UnsignedAddDefaultSubnetValidatorTx struct {
 	DurationValidator `serialize:"true"`
 	NetworkID         uint32      `serialize:"true"`
@@ -33,10 +33,10 @@ type UnsignedAddDefaultSubnetValidatorTx struct {
 	Shares            uint32      `serialize:"true"`
 }
 
-// addDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
+// AddDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
 // is accepted and followed by a Commit block, adds a validator to the pending validator set of the default subnet.
 // (That is, the validator in the tx will validate at some point in the future.)
-type addDefaultSubnetValidatorTx struct {
+type AddDefaultSubnetValidatorTx struct {
 	UnsignedAddDefaultSubnetValidatorTx `serialize:"true"`
 
 	// Signature on the byte repr. of UnsignedAddValidatorTx
@@ -51,7 +51,7 @@ type addDefaultSubnetValidatorTx struct {
 }
 
 // initialize [tx]
-func (tx *addDefaultSubnetValidatorTx) initialize(vm *VM) error {
+func (tx *AddDefaultSubnetValidatorTx) initialize(vm *VM) error {
 	tx.vm = vm
 	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
 	tx.bytes = bytes
@@ -59,11 +59,25 @@ func (tx *addDefaultSubnetValidatorTx) initialize(vm *VM) error {
 	return err
 }
 
-func (tx *addDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
+func (tx *AddDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
+
+// verifySignatures implements the signedTx interface
+func (tx *AddDefaultSubnetValidatorTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
 
 // SyntacticVerify that this transaction is well formed
 // If [tx] is valid, this method also populates [tx.accountID]
-func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
+func (tx *AddDefaultSubnetValidatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
@@ -84,11 +98,8 @@ func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
 	}
 
 	// Byte representation of the unsigned transaction
@@ -108,21 +119,20 @@ func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
 }
 
 // SemanticVerify this transaction is valid.
-func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
+func (tx *AddDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
 	if err := tx.SyntacticVerify(); err != nil {
 		return nil, nil, nil, nil, err
 	}
 
-	// Ensure the proposed validator starts after the current time
+	// Ensure the proposed validator starts after the current time, but not
+	// too long after it
 	currentTime, err := tx.vm.getTimestamp(db)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
 	startTime := tx.StartTime()
-	if !currentTime.Before(startTime) {
-		return nil, nil, nil, nil, fmt.Errorf("chain timestamp (%s) not before validator's start time (%s)",
-			currentTime,
-			startTime)
+	if err := tx.vm.verifyStartTime(currentTime, startTime); err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
@@ -192,14 +202,14 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 
 // InitiallyPrefersCommit returns true if the proposed validators start time is
 // after the current wall clock time,
-func (tx *addDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
+func (tx *AddDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
 	return tx.StartTime().After(tx.vm.clock.Time())
 }
 
 // NewAddDefaultSubnetValidatorTx returns a new NewAddDefaultSubnetValidatorTx
 func (vm *VM) newAddDefaultSubnetValidatorTx(nonce, stakeAmt, startTime, endTime uint64, nodeID, destination ids.ShortID, shares, networkID uint32, key *crypto.PrivateKeySECP256K1R,
-) (*addDefaultSubnetValidatorTx, error) {
-	tx := &addDefaultSubnetValidatorTx{
+) (*AddDefaultSubnetValidatorTx, error) {
+	tx := &AddDefaultSubnetValidatorTx{
 		UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
 			NetworkID: networkID,
 			DurationValidator: DurationValidator{
@@ -21,7 +21,7 @@ var (
 	errDSValidatorSubset       = errors.New("all subnets must be a subset of the default subnet")
 )
 
-// UnsignedAddNonDefaultSubnetValidatorTx is an unsigned addNonDefaultSubnetValidatorTx
+// UnsignedAddNonDefaultSubnetValidatorTx is an unsigned AddNonDefaultSubnetValidatorTx
 type UnsignedAddNonDefaultSubnetValidatorTx struct {
 	// The validator
 	SubnetValidator `serialize:"true"`
@@ -33,12 +33,12 @@ type UnsignedAddNonDefaultSubnetValidatorTx struct {
 	Nonce uint64 `serialize:"true"`
 }
 
-// addNonDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
+// AddNonDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
 // is accepted and followed by a Commit block, adds a validator to the pending validator set of a subnet
 // other than the default subnet.
 // (That is, the validator in the tx will validate at some point in the future.)
 // The transaction fee will be paid from the account whose ID is [Sigs[0].Address()]
-type addNonDefaultSubnetValidatorTx struct {
+type AddNonDefaultSubnetValidatorTx struct {
 	UnsignedAddNonDefaultSubnetValidatorTx `serialize:"true"`
 
 	// When a subnet is created, it specifies a set of public keys ("control keys") such
@@ -62,7 +62,7 @@ type addNonDefaultSubnetValidatorTx struct {
 }
 
 // initialize [tx]
-func (tx *addNonDefaultSubnetValidatorTx) initialize(vm *VM) error {
+func (tx *AddNonDefaultSubnetValidatorTx) initialize(vm *VM) error {
 	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
 	if err != nil {
 		return err
@@ -73,11 +73,31 @@ func (tx *addNonDefaultSubnetValidatorTx) initialize(vm *VM) error {
 	return nil
 }
 
-func (tx *addNonDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
+func (tx *AddNonDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
+
+// verifySignatures implements the signedTx interface
+func (tx *AddNonDefaultSubnetValidatorTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedAddNonDefaultSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
+	for _, sig := range tx.ControlSigs {
+		if _, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:]); err != nil {
+			return err
+		}
+	}
+	_, err = tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
+	return err
+}
 
 // SyntacticVerify return nil iff [tx] is valid
 // If [tx] is valid, sets [tx.accountID]
-func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
+func (tx *AddNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
@@ -98,11 +118,8 @@ func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
 	}
 
 	// Byte representation of the unsigned transaction
@@ -134,9 +151,9 @@ func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 }
 
 // getDefaultSubnetStaker ...
-func (h *EventHeap) getDefaultSubnetStaker(id ids.ShortID) (*addDefaultSubnetValidatorTx, error) {
+func (h *EventHeap) getDefaultSubnetStaker(id ids.ShortID) (*AddDefaultSubnetValidatorTx, error) {
 	for _, txIntf := range h.Txs {
-		tx, ok := txIntf.(*addDefaultSubnetValidatorTx)
+		tx, ok := txIntf.(*AddDefaultSubnetValidatorTx)
 		if !ok {
 			continue
 		}
@@ -149,7 +166,7 @@ func (h *EventHeap) getDefaultSubnetStaker(id ids.ShortID) (*addDefaultSubnetVal
 }
 
 // SemanticVerify this transaction is valid.
-func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
+func (tx *AddNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
 	// Ensure tx is syntactically valid
 	if err := tx.SyntacticVerify(); err != nil {
 		return nil, nil, nil, nil, err
@@ -221,16 +238,15 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 		}
 	}
 
-	// Ensure the proposed validator starts after the current timestamp
+	// Ensure the proposed validator starts after the current timestamp, but
+	// not too long after it
 	currentTimestamp, err := tx.vm.getTimestamp(db)
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't get current timestamp: %v", err)
 	}
 	validatorStartTime := tx.StartTime()
-	if !currentTimestamp.Before(validatorStartTime) {
-		return nil, nil, nil, nil, fmt.Errorf("chain timestamp (%s) not before validator's start time (%s)",
-			currentTimestamp,
-			validatorStartTime)
+	if err := tx.vm.verifyStartTime(currentTimestamp, validatorStartTime); err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
@@ -297,7 +313,7 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 
 // InitiallyPrefersCommit returns true if the proposed validators start time is
 // after the current wall clock time,
-func (tx *addNonDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
+func (tx *AddNonDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
 	return tx.StartTime().After(tx.vm.clock.Time())
 }
 
@@ -311,8 +327,8 @@ func (vm *VM) newAddNonDefaultSubnetValidatorTx(
 	networkID uint32,
 	controlKeys []*crypto.PrivateKeySECP256K1R,
 	payerKey *crypto.PrivateKeySECP256K1R,
-) (*addNonDefaultSubnetValidatorTx, error) {
-	tx := &addNonDefaultSubnetValidatorTx{
+) (*AddNonDefaultSubnetValidatorTx, error) {
+	tx := &AddNonDefaultSubnetValidatorTx{
 		UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
 			SubnetValidator: SubnetValidator{
 				DurationValidator: DurationValidator{
@@ -0,0 +1,281 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+// maxAliasNameLength is the length, in bytes, of the longest address alias
+const maxAliasNameLength = 64
+
+// The address aliases are stored under their own prefix of the chain's
+// database. They aren't part of the chain's state, so they're written outside
+// of [vm.DB].
+var addressAliasPrefix = []byte("address aliases")
+
+var (
+	errInvalidAliasName = json.ParseError(fmt.Errorf("alias names must be 1 to %d bytes long and mustn't be an address", maxAliasNameLength))
+	errNoAliasAddress   = json.ParseError(errors.New("call is missing field 'address'"))
+	errUnknownAlias     = json.NotFoundError(errors.New("unknown address alias"))
+
+	shortIDType      = reflect.TypeOf(ids.ShortID{})
+	shortIDSliceType = reflect.TypeOf([]ids.ShortID{})
+)
+
+// addressAliases are the node-local names of addresses, which may be passed to
+// the API instead of the addresses. They aren't part of the chain's state.
+type addressAliases struct{ db database.Database }
+
+// initAddressAliases loads the address aliases stored in [db]
+func (vm *VM) initAddressAliases(db database.Database) {
+	vm.addressAliases = addressAliases{db: prefixdb.New(addressAliasPrefix, db)}
+}
+
+// put [name] as an alias of [address], replacing any address it aliased
+func (a *addressAliases) put(name string, address ids.ShortID) error {
+	if !validAliasName(name) {
+		return errInvalidAliasName
+	}
+	return a.db.Put([]byte(name), address.Bytes())
+}
+
+// remove the alias [name]
+func (a *addressAliases) remove(name string) error {
+	if has, err := a.db.Has([]byte(name)); err != nil {
+		return err
+	} else if !has {
+		return errUnknownAlias
+	}
+	return a.db.Delete([]byte(name))
+}
+
+// get the address [name] aliases
+func (a *addressAliases) get(name string) (ids.ShortID, error) {
+	addressBytes, err := a.db.Get([]byte(name))
+	if err == database.ErrNotFound {
+		return ids.ShortID{}, errUnknownAlias
+	} else if err != nil {
+		return ids.ShortID{}, err
+	}
+	return ids.ToShortID(addressBytes)
+}
+
+// list the aliases and the addresses they alias, ordered by name
+func (a *addressAliases) list() ([]string, []ids.ShortID, error) {
+	names := []string{}
+	addresses := []ids.ShortID{}
+
+	iter := a.db.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		address, err := ids.ToShortID(iter.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, string(iter.Key()))
+		addresses = append(addresses, address)
+	}
+	return names, addresses, iter.Error()
+}
+
+// validAliasName returns true if [name] may be an alias. An address can't be
+// an alias, so that an address passed to the API is never resolved.
+func validAliasName(name string) bool {
+	if len(name) == 0 || len(name) > maxAliasNameLength {
+		return false
+	}
+	_, err := ids.ShortFromString(name)
+	return err != nil
+}
+
+// resolve the aliases passed in [params], the params of the Service method
+// [method], as addresses. Only the params of type ids.ShortID or
+// []ids.ShortID are resolved.
+func (a *addressAliases) resolve(method string, params stdjson.RawMessage) (stdjson.RawMessage, error) {
+	argsType, exists := argsTypeOf(method)
+	if !exists {
+		return params, nil
+	}
+	// gorilla's JSON-RPC codec accepts the params as an object, or as an array
+	// holding the object
+	fields := map[string]stdjson.RawMessage{}
+	wrapped := []map[string]stdjson.RawMessage{}
+	if err := stdjson.Unmarshal(params, &fields); err != nil {
+		if err := stdjson.Unmarshal(params, &wrapped); err != nil || len(wrapped) != 1 {
+			return params, nil // Let the codec report the malformed params
+		}
+		fields = wrapped[0]
+	}
+
+	resolved := false
+	for _, field := range addressFields(argsType) {
+		value, exists := fields[field]
+		if !exists {
+			continue
+		}
+		value, changed, err := a.resolveValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			fields[field] = value
+			resolved = true
+		}
+	}
+	if !resolved {
+		return params, nil
+	}
+	return stdjson.Marshal(fields)
+}
+
+// resolveValue resolves [value], an address or a list of addresses, and
+// returns whether any alias was resolved
+func (a *addressAliases) resolveValue(value stdjson.RawMessage) (stdjson.RawMessage, bool, error) {
+	name := ""
+	if err := stdjson.Unmarshal(value, &name); err == nil {
+		if !validAliasName(name) {
+			return value, false, nil
+		}
+		address, err := a.get(name)
+		if err == errUnknownAlias {
+			return value, false, nil // Let the codec report the invalid address
+		} else if err != nil {
+			return nil, false, err
+		}
+		resolved, err := stdjson.Marshal(address.String())
+		return resolved, true, err
+	}
+
+	values := []stdjson.RawMessage{}
+	if err := stdjson.Unmarshal(value, &values); err != nil {
+		return value, false, nil
+	}
+	changed := false
+	for i, element := range values {
+		element, elementChanged, err := a.resolveValue(element)
+		if err != nil {
+			return nil, false, err
+		}
+		values[i] = element
+		changed = changed || elementChanged
+	}
+	if !changed {
+		return value, false, nil
+	}
+	resolved, err := stdjson.Marshal(values)
+	return resolved, true, err
+}
+
+// argsTypeOf returns the type of the args of the Service method called by the
+// JSON-RPC method [method], such as platform.getAccount
+func argsTypeOf(method string) (reflect.Type, bool) {
+	sections := strings.SplitN(method, ".", 2)
+	if len(sections) != 2 || sections[0] != "platform" {
+		return nil, false
+	}
+	firstRune, runeLen := utf8.DecodeRuneInString(sections[1])
+	if firstRune == utf8.RuneError {
+		return nil, false
+	}
+	name := string(unicode.ToUpper(firstRune)) + sections[1][runeLen:]
+
+	serviceMethod, exists := reflect.TypeOf(&Service{}).MethodByName(name)
+	if !exists || serviceMethod.Type.NumIn() != 4 {
+		return nil, false
+	}
+	argsType := serviceMethod.Type.In(2)
+	if argsType.Kind() != reflect.Ptr || argsType.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	return argsType.Elem(), true
+}
+
+// addressFields returns the JSON names of the fields of the struct type [t],
+// including the fields of its embedded structs, that hold addresses
+func addressFields(t reflect.Type) []string {
+	fields := []string(nil)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, addressFields(field.Type)...)
+			continue
+		}
+		if field.Type != shortIDType && field.Type != shortIDSliceType {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		if name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// aliasHandler resolves the address aliases passed to the API before the
+// API call is served. Like the API call, it's served with the chain's lock
+// held.
+type aliasHandler struct {
+	vm      *VM
+	handler http.Handler
+}
+
+func (h aliasHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Body == nil {
+		h.handler.ServeHTTP(writer, request)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	call := map[string]stdjson.RawMessage{}
+	if err := stdjson.Unmarshal(body, &call); err != nil {
+		h.handler.ServeHTTP(writer, request) // Not a JSON-RPC call
+		return
+	}
+	method := ""
+	if err := stdjson.Unmarshal(call["method"], &method); err != nil || len(call["params"]) == 0 {
+		h.handler.ServeHTTP(writer, request)
+		return
+	}
+
+	params, err := h.vm.addressAliases.resolve(method, call["params"])
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	call["params"] = params
+
+	body, err = stdjson.Marshal(call)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	h.handler.ServeHTTP(writer, request)
+}
@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestAddressAliases(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	service := Service{vm: vm}
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	address := keys[0].PublicKey().Address()
+	if err := service.SetAddressAlias(nil, &SetAddressAliasArgs{
+		Username: "bob",
+		Password: "launch",
+		Name:     "cold-storage",
+		Address:  address,
+	}, &SetAddressAliasReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.SetAddressAlias(nil, &SetAddressAliasArgs{
+		Username: "bob",
+		Password: "wrong",
+		Name:     "hot-wallet",
+		Address:  address,
+	}, &SetAddressAliasReply{}); err == nil {
+		t.Fatalf("shouldn't have set an alias without a keystore user")
+	}
+	if err := service.SetAddressAlias(nil, &SetAddressAliasArgs{
+		Username: "bob",
+		Password: "launch",
+		Name:     address.String(),
+		Address:  address,
+	}, &SetAddressAliasReply{}); err != errInvalidAliasName {
+		t.Fatalf("expected %s but got %v", errInvalidAliasName, err)
+	}
+
+	aliases := ListAddressAliasesReply{}
+	if err := service.ListAddressAliases(nil, &ListAddressAliasesArgs{}, &aliases); err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases.Aliases) != 1 || aliases.Aliases[0].Name != "cold-storage" || !aliases.Aliases[0].Address.Equals(address) {
+		t.Fatalf("expected only cold-storage to alias %s but got %v", address, aliases.Aliases)
+	}
+
+	// The alias is resolved when it's passed as an address
+	handler := vm.CreateHandlers()[""].Handler
+	call := func(params string) map[string]json.RawMessage {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"platform.getAccount","params":%s}`, params)
+		request := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, request)
+		if writer.Code != http.StatusOK {
+			t.Fatalf("call failed with %d: %s", writer.Code, writer.Body.String())
+		}
+		response := map[string]json.RawMessage{}
+		if err := json.Unmarshal(writer.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	for _, params := range []string{
+		`{"address":"cold-storage"}`,
+		`[{"address":"cold-storage"}]`,
+		fmt.Sprintf(`{"address":"%s"}`, address),
+	} {
+		reply := GetAccountReply{}
+		if err := json.Unmarshal(call(params)["result"], &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Address.Equals(address) {
+			t.Fatalf("expected %s to be resolved as %s but got %s", params, address, reply.Address)
+		}
+	}
+
+	// Removed aliases aren't resolved
+	if err := service.RemoveAddressAlias(nil, &RemoveAddressAliasArgs{
+		Username: "bob",
+		Password: "launch",
+		Name:     "cold-storage",
+	}, &RemoveAddressAliasReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, failed := call(`{"address":"cold-storage"}`)["error"]; !failed {
+		t.Fatalf("shouldn't have resolved a removed alias")
+	}
+	if err := service.RemoveAddressAlias(nil, &RemoveAddressAliasArgs{
+		Username: "bob",
+		Password: "launch",
+		Name:     "cold-storage",
+	}, &RemoveAddressAliasReply{}); err != errUnknownAlias {
+		t.Fatalf("expected %s but got %v", errUnknownAlias, err)
+	}
+}
@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errStartTimeTooEarly = errors.New("staker's start time is too early")
+	errStartTimeTooLate  = errors.New("staker's start time is too far in the future")
+)
+
+// verifyStartTime returns nil iff a staker may start at [startTime] when the
+// chain time is [chainTime]. The staker must start after the chain time, but,
+// once the bound is activated, at most [vm.maxFutureStartTime] after it.
+func (vm *VM) verifyStartTime(chainTime, startTime time.Time) error {
+	if !chainTime.Before(startTime) {
+		return fmt.Errorf("chain timestamp (%s) not before validator's start time (%s)",
+			chainTime,
+			startTime)
+	}
+	if latest := vm.latestStartTime(chainTime); startTime.After(latest) {
+		return fmt.Errorf("%w: start time (%s) is after %s, which is %s after the chain timestamp",
+			errStartTimeTooLate,
+			startTime,
+			latest,
+			vm.maxFutureStartTime)
+	}
+	return nil
+}
+
+// latestStartTime returns the latest start time of a staker when the chain
+// time is [chainTime]
+func (vm *VM) latestStartTime(chainTime time.Time) time.Time {
+	if !active(vm.Upgrades.StartTimeBoundTime, chainTime) {
+		return maxTime
+	}
+	return chainTime.Add(vm.maxFutureStartTime)
+}
+
+// earliestIssuableStartTime returns the earliest start time of a staker that
+// this node will propose adding at [localTime]. A staker that starts earlier
+// might start before the proposal is decided, so it's dropped.
+func (vm *VM) earliestIssuableStartTime(localTime time.Time) time.Time {
+	return localTime.Add(vm.minStartTimeLead)
+}
+
+// verifyIssuableStartTime returns nil iff this node will propose adding a
+// staker that starts at [startTime]
+func (vm *VM) verifyIssuableStartTime(startTime time.Time) error {
+	localTime := vm.clock.Time()
+	if earliest := vm.earliestIssuableStartTime(localTime); startTime.Before(earliest) {
+		return fmt.Errorf("%w: start time (%s) must be at least %s after this node's time (%s)",
+			errStartTimeTooEarly,
+			startTime,
+			vm.minStartTimeLead,
+			localTime)
+	}
+	return nil
+}
+
+// verifyBuildableStartTime returns nil iff this node will build a transaction
+// adding a validator that starts at [startTime]. The validator must start at
+// least [vm.startTimeMargin] after this node's time, so that the transaction
+// can be signed and issued before this node stops proposing it.
+func (vm *VM) verifyBuildableStartTime(startTime time.Time) error {
+	localTime := vm.clock.Time()
+	if earliest := localTime.Add(vm.startTimeMargin); startTime.Before(earliest) {
+		return fmt.Errorf("%w: start time (%s) must be at least %s after this node's time (%s)",
+			errStartTimeTooEarly,
+			startTime,
+			vm.startTimeMargin,
+			localTime)
+	}
+	return nil
+}
+
+// suggestedStartTime returns a start time of a validator that this node will
+// build a transaction adding at [localTime], and that leaves [vm.startTimeMargin]
+// to build, sign and issue the transaction. It's rounded up to the second, as
+// start times are in seconds.
+func (vm *VM) suggestedStartTime(localTime time.Time) time.Time {
+	startTime := localTime.Add(2 * vm.startTimeMargin)
+	if truncated := startTime.Truncate(time.Second); truncated.Before(startTime) {
+		startTime = truncated.Add(time.Second)
+	}
+	return startTime
+}
+
+// advanceTimeReadyTime returns the local time at which this node may propose
+// advancing the chain time to [changeTime]. The proposals this node makes are
+// at least [vm.advanceTimePacing] apart.
+func (vm *VM) advanceTimeReadyTime(changeTime time.Time) time.Time {
+	if paced := vm.lastAdvanceTimeProposal.Add(vm.advanceTimePacing); paced.After(changeTime) {
+		return paced
+	}
+	return changeTime
+}
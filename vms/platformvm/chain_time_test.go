@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestVerifyStartTime(t *testing.T) {
+	vm := defaultVM()
+
+	if err := vm.verifyStartTime(defaultGenesisTime, defaultGenesisTime); err == nil {
+		t.Fatal("should have failed because the start time isn't after the chain time")
+	}
+	if err := vm.verifyStartTime(defaultGenesisTime, defaultGenesisTime.Add(DefaultMaxFutureStartTime)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.verifyStartTime(defaultGenesisTime, defaultGenesisTime.Add(DefaultMaxFutureStartTime).Add(time.Second)); !errors.Is(err, errStartTimeTooLate) {
+		t.Fatalf("should have failed with %s but got %v", errStartTimeTooLate, err)
+	}
+
+	// A staker that starts too far in the future is rejected by verification
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(defaultGenesisTime.Add(DefaultMaxFutureStartTime).Add(time.Second).Unix()),
+		uint64(defaultGenesisTime.Add(DefaultMaxFutureStartTime).Add(time.Second).Add(MinimumStakingDuration).Unix()),
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); !errors.Is(err, errStartTimeTooLate) {
+		t.Fatalf("should have failed with %s but got %v", errStartTimeTooLate, err)
+	}
+
+	// Before the bound is activated, a staker may start any time after the
+	// chain time
+	vm.Upgrades.StartTimeBoundTime = defaultGenesisTime.Add(time.Second)
+	tx, err = vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(defaultGenesisTime.Add(DefaultMaxFutureStartTime).Add(time.Second).Unix()),
+		uint64(defaultGenesisTime.Add(DefaultMaxFutureStartTime).Add(time.Second).Add(MinimumStakingDuration).Unix()),
+		ids.NewShortID([20]byte{1}),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != nil {
+		t.Fatalf("should have allowed the start time before the bound is activated: %s", err)
+	}
+	vm.Upgrades.StartTimeBoundTime = time.Time{}
+
+	// The staker isn't issued if it starts too soon after this node's time
+	if err := vm.verifyIssuableStartTime(defaultGenesisTime.Add(DefaultMinStartTimeLead).Add(-time.Second)); !errors.Is(err, errStartTimeTooEarly) {
+		t.Fatalf("should have failed with %s but got %v", errStartTimeTooEarly, err)
+	}
+	if err := vm.verifyIssuableStartTime(defaultGenesisTime.Add(DefaultMinStartTimeLead)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildableStartTime(t *testing.T) {
+	vm := defaultVM()
+	vm.clock.Set(defaultGenesisTime)
+
+	if err := vm.verifyBuildableStartTime(defaultGenesisTime.Add(DefaultStartTimeMargin).Add(-time.Second)); !errors.Is(err, errStartTimeTooEarly) {
+		t.Fatalf("should have failed with %s but got %v", errStartTimeTooEarly, err)
+	}
+	if err := vm.verifyBuildableStartTime(defaultGenesisTime.Add(DefaultStartTimeMargin)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The suggested start time leaves the margin to build, sign and issue the
+	// transaction
+	suggested := vm.suggestedStartTime(defaultGenesisTime.Add(time.Second / 2))
+	if expected := defaultGenesisTime.Add(2 * DefaultStartTimeMargin).Add(time.Second); !suggested.Equal(expected) {
+		t.Fatalf("expected the suggested start time to be %s but was %s", expected, suggested)
+	}
+	if err := vm.verifyBuildableStartTime(suggested.Add(-DefaultStartTimeMargin)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdvanceTimePacing(t *testing.T) {
+	vm := defaultVM()
+	vm.advanceTimePacing = time.Minute
+
+	// The genesis validators are due to leave, but this node proposed
+	// advancing the time too recently
+	vm.clock.Set(defaultValidateEndTime)
+	vm.lastAdvanceTimeProposal = defaultValidateEndTime
+
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	if _, err := vm.BuildBlock(); err != errNoPendingBlocks {
+		t.Fatalf("should have failed with %s but got %v", errNoPendingBlocks, err)
+	}
+
+	vm.clock.Set(defaultValidateEndTime.Add(time.Minute))
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := blk.(*ProposalBlock).Tx.(*advanceTimeTx)
+	if !ok {
+		t.Fatal("should have proposed advancing the chain time")
+	}
+	if !tx.Timestamp().Equal(defaultValidateEndTime) {
+		t.Fatalf("should have proposed advancing the chain time to %s but proposed %s", defaultValidateEndTime, tx.Timestamp())
+	}
+	if !vm.lastAdvanceTimeProposal.Equal(defaultValidateEndTime.Add(time.Minute)) {
+		t.Fatal("should have recorded the time of the proposal")
+	}
+}
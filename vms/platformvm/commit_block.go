@@ -12,45 +12,61 @@ import (
 // being enacted.
 type Commit struct {
 	CommonDecisionBlock `serialize:"true"`
+
+	// AccountRoot is the root of the account trie once this block is accepted
+	AccountRoot ids.ID `serialize:"true"`
 }
 
+// accountRoot implements the rootedBlock interface
+func (c *Commit) accountRoot() ids.ID { return c.AccountRoot }
+
 // Verify this block performs a valid state transition.
 //
 // The parent block must either be a proposal
 //
 // This function also sets the onCommit databases if the verification passes.
-func (c *Commit) Verify() error {
+func (c *Commit) Verify() error { return c.verifyCommit(c, &c.AccountRoot) }
+
+// verifyCommit verifies [blk], a commit block that commits to the account root
+// [root], or to none if [root] is nil
+func (cdb *CommonDecisionBlock) verifyCommit(blk Block, root *ids.ID) error {
 	// the parent of an Commit block should always be a proposal
-	if parent, ok := c.parentBlock().(*ProposalBlock); ok {
-		c.onAcceptDB, c.onAcceptFunc = parent.onCommit()
-	} else {
+	parent, ok := cdb.parentBlock().(*ProposalBlock)
+	if !ok {
 		return errInvalidBlockType
 	}
+	cdb.onAcceptDB, cdb.onAcceptFunc = parent.onCommit()
+	if err := cdb.vm.verifyAccountRoot(cdb.onAcceptDB, root); err != nil {
+		return err
+	}
 
-	c.vm.currentBlocks[c.ID().Key()] = c
-	c.parentBlock().addChild(c)
+	cdb.vm.currentBlocks[blk.ID().Key()] = blk
+	parent.addChild(blk)
 	return nil
 }
 
-// newCommitBlock returns a new *Commit block where the block's parent, a
-// proposal block, has ID [parentID].
-func (vm *VM) newCommitBlock(parentID ids.ID) *Commit {
-	commit := &Commit{
-		CommonDecisionBlock: CommonDecisionBlock{
-			CommonBlock: CommonBlock{
-				Block: core.NewBlock(parentID),
-				vm:    vm,
-			},
+// newCommitBlock returns a new commit block where the block's parent, a
+// proposal block, has ID [parentID]. The block commits to the account root
+// [root], or is a *legacyCommit if [root] is nil.
+func (vm *VM) newCommitBlock(parentID ids.ID, root *ids.ID) Block {
+	common := CommonDecisionBlock{
+		CommonBlock: CommonBlock{
+			Block: core.NewBlock(parentID),
+			vm:    vm,
 		},
 	}
+	var commit Block
+	if root == nil {
+		commit = &legacyCommit{CommonDecisionBlock: common}
+	} else {
+		commit = &Commit{CommonDecisionBlock: common, AccountRoot: *root}
+	}
 
 	// We serialize this block as a Block so that it can be deserialized into a
 	// Block
-	blk := Block(commit)
-	bytes, err := Codec.Marshal(&blk)
-	if err != nil {
+	bytes, err := Codec.Marshal(&commit)
+	if err != nil || commit.initialize(vm, bytes) != nil {
 		return nil
 	}
-	commit.Block.Initialize(bytes, vm.SnowmanVM)
 	return commit
 }
@@ -10,6 +10,8 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/vms/components/core"
 )
@@ -87,6 +89,9 @@ type Block interface {
 	// [bytes] is the byte representation of this block
 	initialize(vm *VM, bytes []byte) error
 
+	// ParentID returns the ID of this block's parent, without fetching it
+	ParentID() ids.ID
+
 	// parentBlock returns the parent block, similarly to Parent. However, it
 	// provides the more specific staking.Block interface.
 	parentBlock() Block
@@ -182,6 +187,9 @@ type CommonDecisionBlock struct {
 
 	// to be executed if this block is accepted
 	onAcceptFunc func()
+
+	// messages sent to other chains if this block is accepted
+	messages []*snow.Message
 }
 
 // initialize this block
@@ -218,8 +226,11 @@ func (cdb *CommonDecisionBlock) Accept() {
 	if err := cdb.onAcceptDB.Commit(); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit onAcceptDB")
 	}
-	if err := cdb.vm.DB.Commit(); err != nil {
-		cdb.vm.Ctx.Log.Warn("unable to commit vm's DB")
+	if err := cdb.vm.acceptHeight(cdb.vm.DB, cdb.ID()); err != nil {
+		cdb.vm.Ctx.Log.Warn("unable to advance the height: %s", err)
+	}
+	if err := cdb.vm.commit(cdb.messages); err != nil {
+		cdb.vm.Ctx.Log.Warn("unable to commit vm's DB: %s", err)
 	}
 
 	for _, child := range cdb.children {
@@ -71,6 +71,20 @@ func (tx *CreateChainTx) Key() crypto.PublicKey { return tx.key }
 // Bytes returns the byte representation of a CreateChainTx
 func (tx *CreateChainTx) Bytes() []byte { return tx.bytes }
 
+// verifySignatures implements the signedTx interface
+func (tx *CreateChainTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedCreateChainTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
+
 // SyntacticVerify this transaction is well-formed
 // Also populates [tx.Key] with the public key that signed this transaction
 func (tx *CreateChainTx) SyntacticVerify() error {
@@ -124,25 +138,22 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
-	// Deduct tx fee from payer's account
-	account, err := tx.vm.getAccount(db, tx.Key().Address())
-	if err != nil {
-		return nil, err
-	}
-	account, err = account.Remove(0, tx.Nonce)
+	// Deduct tx fee and the burned creation fee from payer's account
+	fees, err := tx.vm.creationFees(db)
 	if err != nil {
 		return nil, err
 	}
-	if err := tx.vm.putAccount(db, account); err != nil {
+	if err := tx.vm.burnCreationFee(db, tx.Key().Address(), tx.Nonce, fees.Chain); err != nil {
 		return nil, err
 	}
 
 	// If this proposal is committed, create the new blockchain using the chain manager
 	onAccept := func() {
 		chainParams := chains.ChainParameters{
-			ID:          tx.ID(),
-			GenesisData: tx.GenesisData,
-			VMAlias:     tx.VMID.String(),
+			ID:           tx.ID(),
+			GenesisData:  tx.GenesisData,
+			VMAlias:      tx.VMID.String(),
+			Dependencies: []ids.ID{tx.vm.Ctx.ChainID}, // The platform chain must finish bootstrapping first
 		}
 		for _, fxID := range tx.FxIDs {
 			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -140,3 +141,69 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 		t.Fatalf("should have failed because there is already a chain with ID %s", tx.id)
 	}
 }
+
+func TestSemanticVerifyCreationFee(t *testing.T) {
+	vm := defaultVM()
+	vm.Upgrades.CreationFees = CreationFees{Chain: defaultBalance / 4}
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDB := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(newDB); err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := vm.getAccount(newDB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - txFee - vm.Upgrades.CreationFees.Chain; account.Balance != expected {
+		t.Fatalf("expected the payer's balance to be %d but was %d", expected, account.Balance)
+	}
+
+	// The fee is burned
+	genesisSupply, err := vm.getTotalSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supply, err := vm.getTotalSupply(newDB); err != nil {
+		t.Fatal(err)
+	} else if supply != genesisSupply-vm.Upgrades.CreationFees.Chain {
+		t.Fatalf("expected the supply to shrink by %d but it shrank by %d", vm.Upgrades.CreationFees.Chain, genesisSupply-supply)
+	}
+
+	// The payer can't afford the fee
+	vm.Upgrades.CreationFees.Chain = defaultBalance + 1
+	tx, err = vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		avm.ID,
+		nil,
+		"other chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the payer can't afford the creation fee")
+	}
+
+	// Before the fee is activated, creating a chain is free
+	vm.Upgrades.CreationFeeTime = defaultGenesisTime.Add(time.Second)
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != nil {
+		t.Fatalf("should have allowed creating the chain before the fee is activated: %s", err)
+	}
+}
@@ -60,6 +60,20 @@ type CreateSubnetTx struct {
 	bytes []byte
 }
 
+// verifySignatures implements the signedTx interface
+func (tx *CreateSubnetTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedCreateSubnetTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
+
 // SyntacticVerify nil iff [tx] is syntactically valid.
 // If [tx] is valid, this method sets [tx.key]
 func (tx *CreateSubnetTx) SyntacticVerify() error {
@@ -115,16 +129,12 @@ func (tx *CreateSubnetTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
-	// Deduct tx fee from payer's account
-	account, err := tx.vm.getAccount(db, tx.key.Address())
-	if err != nil {
-		return nil, err
-	}
-	account, err = account.Remove(0, tx.Nonce)
+	// Deduct tx fee and the burned creation fee from payer's account
+	fees, err := tx.vm.creationFees(db)
 	if err != nil {
 		return nil, err
 	}
-	if err := tx.vm.putAccount(db, account); err != nil {
+	if err := tx.vm.burnCreationFee(db, tx.key.Address(), tx.Nonce, fees.Subnet); err != nil {
 		return nil, err
 	}
 
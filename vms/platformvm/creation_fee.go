@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/utils/units"
+)
+
+var (
+	// DefaultCreationFees are the fees for creating subnets and chains on the
+	// public networks. Every subnet and chain uses the resources of the nodes
+	// that validate it for as long as the network exists, so creating one
+	// should be costly.
+	DefaultCreationFees = CreationFees{
+		Subnet: units.KiloAva,
+		Chain:  units.KiloAva,
+	}
+)
+
+// CreationFees are the amounts of $AVA that are burned to create a subnet or
+// a chain. They are paid by the account that issues the tx, along with its tx
+// fee.
+type CreationFees struct {
+	// Subnet is the fee to create a subnet
+	Subnet uint64
+
+	// Chain is the fee to create a chain
+	Chain uint64
+}
+
+// creationFees returns the fees burned to create subnets and chains at the
+// chain time in [db]. Before the fees are activated, creating them is free.
+func (vm *VM) creationFees(db database.Database) (CreationFees, error) {
+	chainTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return CreationFees{}, err
+	}
+	if !active(vm.Upgrades.CreationFeeTime, chainTime) {
+		return CreationFees{}, nil
+	}
+	return vm.Upgrades.CreationFees, nil
+}
+
+// burnCreationFee deducts [fee] from the account with address [payer] in
+// [db], which spends its nonce [nonce], and removes it from the total supply
+func (vm *VM) burnCreationFee(db database.Database, payer ids.ShortID, nonce uint64, fee uint64) error {
+	account, err := vm.getAccount(db, payer)
+	if err != nil {
+		return err
+	}
+	account, err = account.Remove(fee, nonce)
+	if err != nil {
+		return fmt.Errorf("couldn't pay creation fee of %d: %w", fee, err)
+	}
+	if err := vm.putAccount(db, account); err != nil {
+		return err
+	}
+	if fee == 0 {
+		return nil
+	}
+	return vm.burnSupply(db, fee)
+}
+
+// decrease the total supply in [db] by the burned [amount]
+func (vm *VM) burnSupply(db database.Database, amount uint64) error {
+	totalSupply, err := vm.getTotalSupply(db)
+	if err != nil {
+		return err
+	}
+	newTotalSupply, err := math.Sub64(totalSupply, amount)
+	if err != nil {
+		return err
+	}
+	return vm.putTotalSupply(db, newTotalSupply)
+}
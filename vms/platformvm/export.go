@@ -0,0 +1,250 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"encoding/csv"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// ExportEndpoint of the P-Chain dumps every account, at the last accepted
+	// block, for audits and airdrop snapshots. Its ?format= is "csv" (the
+	// default) or "json". It should only be exposed to this node's operator.
+	ExportEndpoint = "/export"
+
+	// LastAcceptedHeader of an export is the ID of the block it was taken at
+	LastAcceptedHeader = "X-Last-Accepted-Block"
+
+	// Exported accounts are flushed to the client in batches of this size
+	exportFlushInterval = 1024
+)
+
+// ExportedAccount is an account, as exported by ExportEndpoint
+type ExportedAccount struct {
+	Address ids.ShortID     `json:"address"`
+	Nonce   json.Uint64     `json:"nonce"`
+	Balance json.Uint64     `json:"balance"`
+	Locked  []LockedTranche `json:"locked"`
+}
+
+// LockedTranche is $AVA staked by a default subnet validator that's returned
+// to the account at [UnlockTime], a Unix time, when the validator stops
+// validating
+type LockedTranche struct {
+	Amount     json.Uint64 `json:"amount"`
+	UnlockTime json.Uint64 `json:"unlockTime"`
+}
+
+// accountEncoder writes exported accounts to a response
+type accountEncoder interface {
+	// encode the account to the response
+	encode(ExportedAccount) error
+
+	// finish the response once the last account is written
+	finish() error
+}
+
+// exportHandler serves ExportEndpoint
+type exportHandler struct{ vm *VM }
+
+func (h exportHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	lastAccepted := h.vm.LastAccepted()
+
+	var encoder accountEncoder
+	switch format := strings.ToLower(request.URL.Query().Get("format")); format {
+	case "", "csv":
+		writer.Header().Set("Content-Type", "text/csv")
+		encoder = newCSVAccountEncoder(writer)
+	case "json":
+		writer.Header().Set("Content-Type", "application/json")
+		encoder = newJSONAccountEncoder(writer, lastAccepted)
+	default:
+		http.Error(writer, fmt.Sprintf("unknown export format %q", format), http.StatusBadRequest)
+		return
+	}
+	writer.Header().Set(LastAcceptedHeader, lastAccepted.String())
+
+	// Once the first account is written, the response can't fail anymore, so
+	// a failed export is cut short
+	if err := h.vm.exportAccounts(encoder, writer); err != nil {
+		h.vm.Ctx.Log.Warn("failed to export the accounts: %s", err)
+	}
+}
+
+// exportAccounts encodes the accounts in the database, followed by the
+// addresses that only have $AVA locked, with [encoder]. The accounts are
+// flushed to [writer] in batches, if it supports flushing.
+func (vm *VM) exportAccounts(encoder accountEncoder, writer io.Writer) error {
+	tranches, err := vm.lockedTranches(vm.DB)
+	if err != nil {
+		return err
+	}
+
+	flusher, _ := writer.(http.Flusher)
+	numExported := 0
+	export := func(account Account) error {
+		key := account.Address.Key()
+		if err := encoder.encode(ExportedAccount{
+			Address: account.Address,
+			Nonce:   json.Uint64(account.Nonce),
+			Balance: json.Uint64(account.Balance),
+			Locked:  tranches[key],
+		}); err != nil {
+			return err
+		}
+		delete(tranches, key)
+
+		numExported++
+		if flusher != nil && numExported%exportFlushInterval == 0 {
+			flusher.Flush()
+		}
+		return nil
+	}
+	if err := vm.forEachAccount(vm.DB, export); err != nil {
+		return err
+	}
+
+	// The rest of the locked $AVA is returned to addresses that don't have an
+	// account yet. They're sorted so the export is deterministic.
+	lockedOnly := make([]ids.ShortID, 0, len(tranches))
+	for key := range tranches {
+		lockedOnly = append(lockedOnly, ids.NewShortID(key))
+	}
+	sort.Slice(lockedOnly, func(i, j int) bool {
+		return bytes.Compare(lockedOnly[i].Bytes(), lockedOnly[j].Bytes()) < 0
+	})
+	for _, address := range lockedOnly {
+		if err := export(Account{Address: address}); err != nil {
+			return err
+		}
+	}
+	return encoder.finish()
+}
+
+// lockedTranches returns the $AVA staked by the current and pending default
+// subnet validators in [db], by the address it's returned to
+func (vm *VM) lockedTranches(db database.Database) (map[[20]byte][]LockedTranche, error) {
+	currentValidators, err := vm.getCurrentValidators(db, DefaultSubnetID)
+	if err != nil {
+		return nil, err
+	}
+	pendingValidators, err := vm.getPendingValidators(db, DefaultSubnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	tranches := make(map[[20]byte][]LockedTranche)
+	for _, stakers := range [][]TimedTx{currentValidators.Txs, pendingValidators.Txs} {
+		for _, staker := range stakers {
+			validatorTx, ok := staker.(*AddDefaultSubnetValidatorTx)
+			if !ok {
+				continue
+			}
+			key := validatorTx.Destination.Key()
+			tranches[key] = append(tranches[key], LockedTranche{
+				Amount:     json.Uint64(validatorTx.Wght),
+				UnlockTime: json.Uint64(validatorTx.End),
+			})
+		}
+	}
+	return tranches, nil
+}
+
+// csvAccountEncoder writes a row of address,nonce,balance,locked for each
+// account. Locked is a list of amount@unlockTime, separated by ';'.
+type csvAccountEncoder struct {
+	writer  *csv.Writer
+	started bool
+}
+
+func newCSVAccountEncoder(writer io.Writer) *csvAccountEncoder {
+	return &csvAccountEncoder{writer: csv.NewWriter(writer)}
+}
+
+func (e *csvAccountEncoder) encode(account ExportedAccount) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	locked := make([]string, len(account.Locked))
+	for i, tranche := range account.Locked {
+		locked[i] = fmt.Sprintf("%d@%d", uint64(tranche.Amount), uint64(tranche.UnlockTime))
+	}
+	return e.writer.Write([]string{
+		account.Address.String(),
+		strconv.FormatUint(uint64(account.Nonce), 10),
+		strconv.FormatUint(uint64(account.Balance), 10),
+		strings.Join(locked, ";"),
+	})
+}
+
+func (e *csvAccountEncoder) finish() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// start writes the header row, unless it's been written already
+func (e *csvAccountEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.writer.Write([]string{"address", "nonce", "balance", "locked"})
+}
+
+// jsonAccountEncoder writes {"lastAccepted": ..., "accounts": [...]}, one
+// account at a time
+type jsonAccountEncoder struct {
+	writer       io.Writer
+	lastAccepted ids.ID
+	numEncoded   int
+}
+
+func newJSONAccountEncoder(writer io.Writer, lastAccepted ids.ID) *jsonAccountEncoder {
+	return &jsonAccountEncoder{
+		writer:       writer,
+		lastAccepted: lastAccepted,
+	}
+}
+
+func (e *jsonAccountEncoder) encode(account ExportedAccount) error {
+	if account.Locked == nil {
+		account.Locked = []LockedTranche{}
+	}
+	b, err := stdjson.Marshal(account)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if e.numEncoded == 0 {
+		separator = fmt.Sprintf(`{"lastAccepted":%q,"accounts":[`, e.lastAccepted)
+	}
+	e.numEncoded++
+	_, err = fmt.Fprintf(e.writer, "%s%s", separator, b)
+	return err
+}
+
+func (e *jsonAccountEncoder) finish() error {
+	if e.numEncoded == 0 {
+		_, err := fmt.Fprintf(e.writer, `{"lastAccepted":%q,"accounts":[]}`, e.lastAccepted)
+		return err
+	}
+	_, err := io.WriteString(e.writer, "]}")
+	return err
+}
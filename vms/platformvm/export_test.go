@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExportAccounts(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	export := func(format string) *httptest.ResponseRecorder {
+		writer := httptest.NewRecorder()
+		exportHandler{vm: vm}.ServeHTTP(writer, httptest.NewRequest("GET", ExportEndpoint+"?format="+format, nil))
+		return writer
+	}
+
+	writer := export("json")
+	if writer.Code != http.StatusOK {
+		t.Fatalf("export failed with %d: %s", writer.Code, writer.Body.String())
+	}
+	if lastAccepted := writer.Header().Get(LastAcceptedHeader); lastAccepted != vm.LastAccepted().String() {
+		t.Fatalf("expected the export to be taken at %s but it was taken at %s", vm.LastAccepted(), lastAccepted)
+	}
+	reply := struct {
+		LastAccepted string            `json:"lastAccepted"`
+		Accounts     []ExportedAccount `json:"accounts"`
+	}{}
+	if err := json.Unmarshal(writer.Body.Bytes(), &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Accounts) != len(keys) {
+		t.Fatalf("expected %d accounts but got %d", len(keys), len(reply.Accounts))
+	}
+	for _, account := range reply.Accounts {
+		if uint64(account.Balance) != defaultBalance || account.Nonce != defaultNonce {
+			t.Fatalf("account %s has the wrong balance or nonce", account.Address)
+		}
+		// Each genesis validator's stake is returned to its own account
+		if len(account.Locked) != 1 ||
+			uint64(account.Locked[0].Amount) != defaultStakeAmount ||
+			int64(account.Locked[0].UnlockTime) != defaultValidateEndTime.Unix() {
+			t.Fatalf("account %s has the wrong locked tranches: %v", account.Address, account.Locked)
+		}
+	}
+
+	writer = export("csv")
+	rows, err := csv.NewReader(writer.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(keys)+1 {
+		t.Fatalf("expected a header and %d rows but got %d rows", len(keys), len(rows))
+	}
+	expectedLocked := fmt.Sprintf("%d@%d", defaultStakeAmount, defaultValidateEndTime.Unix())
+	if rows[1][0] != reply.Accounts[0].Address.String() || rows[1][3] != expectedLocked {
+		t.Fatalf("unexpected row %v", rows[1])
+	}
+
+	if writer := export("xml"); writer.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown format to fail with %d but got %d", http.StatusBadRequest, writer.Code)
+	}
+}
@@ -4,9 +4,12 @@
 package platformvm
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/vms/platformvm/reward"
 )
 
 // ID of the platform VM
@@ -16,14 +19,30 @@ var (
 
 // Factory can create new instances of the Platform Chain
 type Factory struct {
-	ChainManager chains.Manager
-	Validators   validators.Manager
+	ChainManager      chains.Manager
+	Uptimes           Uptimes
+	Validators        validators.Manager
+	Reindex           bool
+	MinStartTimeLead  time.Duration
+	StartTimeMargin   time.Duration
+	AdvanceTimePacing time.Duration
+	RewardCurve       reward.Curve
+	Upgrades          Upgrades
+	WatchAllowedHosts []string
 }
 
 // New returns a new instance of the Platform Chain
 func (f *Factory) New() interface{} {
 	return &VM{
-		ChainManager: f.ChainManager,
-		Validators:   f.Validators,
+		ChainManager:      f.ChainManager,
+		Uptimes:           f.Uptimes,
+		Validators:        f.Validators,
+		Reindex:           f.Reindex,
+		MinStartTimeLead:  f.MinStartTimeLead,
+		StartTimeMargin:   f.StartTimeMargin,
+		AdvanceTimePacing: f.AdvanceTimePacing,
+		RewardCurve:       f.RewardCurve,
+		Upgrades:          f.Upgrades,
+		WatchAllowedHosts: f.WatchAllowedHosts,
 	}
 }
@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+// Decision blocks accepted before AccountRootTime don't commit to an account
+// root. They keep their own types, with the type IDs decision blocks had before
+// the root was committed to, so that their bytes, and so their IDs, don't
+// change. The genesis block is always a *legacyCommit.
+
+// legacyCommit is a Commit block that doesn't commit to an account root
+type legacyCommit struct {
+	CommonDecisionBlock `serialize:"true"`
+}
+
+// Verify implements the snowman.Block interface
+func (c *legacyCommit) Verify() error { return c.verifyCommit(c, nil) }
+
+// legacyAbort is an Abort block that doesn't commit to an account root
+type legacyAbort struct {
+	CommonDecisionBlock `serialize:"true"`
+}
+
+// Verify implements the snowman.Block interface
+func (a *legacyAbort) Verify() error { return a.verifyAbort(a, nil) }
+
+// legacyStandardBlock is a StandardBlock that doesn't commit to an account root
+type legacyStandardBlock struct {
+	CommonDecisionBlock `serialize:"true"`
+
+	Txs []DecisionTx `serialize:"true"`
+}
+
+// initialize this block
+func (sb *legacyStandardBlock) initialize(vm *VM, bytes []byte) error {
+	if err := sb.CommonDecisionBlock.initialize(vm, bytes); err != nil {
+		return err
+	}
+	return initializeDecisionTxs(vm, sb.Txs)
+}
+
+// VerifyStateless implements the snowman.StatelessBlock interface
+func (sb *legacyStandardBlock) VerifyStateless() error { return verifyDecisionTxSignatures(sb.Txs) }
+
+// Verify implements the snowman.Block interface
+func (sb *legacyStandardBlock) Verify() error { return sb.verifyStandard(sb, sb.Txs, nil) }
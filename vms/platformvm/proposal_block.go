@@ -14,6 +14,8 @@ import (
 // ProposalTx is an operation that can be proposed
 type ProposalTx interface {
 	initialize(vm *VM) error
+	// Verify this transaction is well-formed.
+	SyntacticVerify() error
 	// Attempts to verify this transaction with the provided state.
 	SemanticVerify(database.Database) (onCommitDB *versiondb.Database, onAbortDB *versiondb.Database, onCommitFunc func(), onAbortFunc func(), err error)
 	InitiallyPrefersCommit() bool
@@ -79,6 +81,24 @@ func (pb *ProposalBlock) onAbort() (*versiondb.Database, func()) {
 	return pb.onAbortDB, pb.onAbortFunc
 }
 
+// Accept implements the snowman.Block interface
+// The proposal doesn't change the chain's state until a Commit block is
+// accepted, so the state in the vm's DB is the state once this block is
+// accepted.
+func (pb *ProposalBlock) Accept() {
+	pb.CommonBlock.Accept()
+
+	if err := pb.vm.acceptHeight(pb.vm.DB, pb.ID()); err != nil {
+		pb.vm.Ctx.Log.Warn("unable to advance the height: %s", err)
+	}
+}
+
+// VerifyStateless verifies the signature of this block's transaction.
+//
+// The consensus engine may call this concurrently with the verification of
+// other blocks, so it only reads the block's transaction.
+func (pb *ProposalBlock) VerifyStateless() error { return verifySignatures(pb.Tx) }
+
 // Verify this block is valid.
 //
 // The parent block must either be a Commit or an Abort block.
@@ -100,6 +120,20 @@ func (pb *ProposalBlock) Verify() error {
 	if err != nil {
 		return err
 	}
+	pb.onCommitFunc = pb.vm.watchOnAccept(pb.Tx, pb.onCommitFunc)
+
+	// The validator events aren't part of the chain's state, so failing to
+	// compute them mustn't make the block invalid
+	if events, err := pb.vm.validatorChanges(pdb, pb.onCommitDB, pb.Tx, pb.ID(), true); err == nil {
+		pb.onCommitFunc = pb.vm.recordOnAccept(events, pb.onCommitFunc)
+	} else {
+		pb.vm.Ctx.Log.Warn("couldn't compute the validator events of block %s: %s", pb.ID(), err)
+	}
+	if events, err := pb.vm.validatorChanges(pdb, pb.onAbortDB, pb.Tx, pb.ID(), false); err == nil {
+		pb.onAbortFunc = pb.vm.recordOnAccept(events, pb.onAbortFunc)
+	} else {
+		pb.vm.Ctx.Log.Warn("couldn't compute the validator events of block %s: %s", pb.ID(), err)
+	}
 
 	pb.vm.currentBlocks[pb.ID().Key()] = pb
 	parent.addChild(pb)
@@ -110,8 +144,14 @@ func (pb *ProposalBlock) Verify() error {
 func (pb *ProposalBlock) Options() [2]snowman.Block {
 	blockID := pb.ID()
 
-	commit := pb.vm.newCommitBlock(blockID)
-	abort := pb.vm.newAbortBlock(blockID)
+	// If the account roots can't be computed, the options don't commit to
+	// them, and so fail verification
+	commitRoot, abortRoot, err := pb.accountRoots()
+	if err != nil {
+		pb.vm.Ctx.Log.Error("couldn't compute the account roots of the options of %s: %s", blockID, err)
+	}
+	commit := pb.vm.newCommitBlock(blockID, commitRoot)
+	abort := pb.vm.newAbortBlock(blockID, abortRoot)
 
 	if err := pb.vm.State.PutBlock(pb.vm.DB, commit); err != nil {
 		pb.vm.Ctx.Log.Warn(errDBPutBlock.Error())
@@ -127,6 +167,31 @@ func (pb *ProposalBlock) Options() [2]snowman.Block {
 	return [2]snowman.Block{abort, commit}
 }
 
+// accountRoots returns the account roots that the Commit and Abort children of
+// this block must commit to
+func (pb *ProposalBlock) accountRoots() (*ids.ID, *ids.ID, error) {
+	onCommitDB, onAbortDB := pb.onCommitDB, pb.onAbortDB
+	if onCommitDB == nil || onAbortDB == nil {
+		// This block hasn't been verified, so its proposal is applied to the
+		// state of its parent without keeping the result
+		parent, ok := pb.parentBlock().(decision)
+		if !ok {
+			return nil, nil, errInvalidBlockType
+		}
+		var err error
+		onCommitDB, onAbortDB, _, _, err = pb.Tx.SemanticVerify(parent.onAccept())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	commitRoot, err := pb.vm.accountRootCommitment(onCommitDB)
+	if err != nil {
+		return nil, nil, err
+	}
+	abortRoot, err := pb.vm.accountRootCommitment(onAbortDB)
+	return commitRoot, abortRoot, err
+}
+
 // newProposalBlock creates a new block that proposes to issue a transaction.
 // The parent of this block has ID [parentID]. The parent must be a decision block.
 // Returns nil if there's an error while creating this block
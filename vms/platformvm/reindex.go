@@ -0,0 +1,119 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/state"
+)
+
+// reindex rebuilds the total supply of $AVA from the accounts and the stakers
+// in the database
+func (vm *VM) reindex() error {
+	totalSupply, err := vm.stateSupply(vm.DB)
+	if err != nil {
+		return err
+	}
+	if err := vm.putTotalSupply(vm.DB, totalSupply); err != nil {
+		return err
+	}
+	vm.Ctx.Log.Info("reindexed total supply of %d", totalSupply)
+	return vm.DB.Commit()
+}
+
+// initTotalSupply computes the total supply of $AVA if the chain was created
+// before the total supply was tracked. Rewards depend on the total supply, so
+// they can't be computed without it.
+func (vm *VM) initTotalSupply() error {
+	has, err := vm.State.Has(vm.DB, state.Uint64TypeID, totalSupplyKey)
+	if err != nil || has {
+		return err
+	}
+	return vm.reindex()
+}
+
+// stateSupply returns the amount of $AVA in [db], which is the $AVA held in
+// accounts plus the $AVA locked by the default subnet's validators. (Delegated
+// $AVA isn't removed from the delegator's account, so it isn't counted twice.)
+func (vm *VM) stateSupply(db database.Database) (uint64, error) {
+	totalSupply, err := vm.accountsBalance(db)
+	if err != nil {
+		return 0, err
+	}
+
+	currentValidators, err := vm.getCurrentValidators(db, DefaultSubnetID)
+	if err != nil {
+		return 0, err
+	}
+	pendingValidators, err := vm.getPendingValidators(db, DefaultSubnetID)
+	if err != nil {
+		return 0, err
+	}
+	for _, stakers := range [][]TimedTx{currentValidators.Txs, pendingValidators.Txs} {
+		for _, staker := range stakers {
+			validatorTx, ok := staker.(*AddDefaultSubnetValidatorTx)
+			if !ok {
+				continue
+			}
+			if totalSupply, err = math.Add64(totalSupply, validatorTx.Wght); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return totalSupply, nil
+}
+
+// accountsBalance returns the sum of the balances of the accounts in [db]
+func (vm *VM) accountsBalance(db database.Database) (uint64, error) {
+	accounts, err := vm.accounts(db)
+	if err != nil {
+		return 0, err
+	}
+
+	balance := uint64(0)
+	for _, account := range accounts {
+		if balance, err = math.Add64(balance, account.Balance); err != nil {
+			return 0, err
+		}
+	}
+	return balance, nil
+}
+
+// accounts returns the accounts in [db]
+func (vm *VM) accounts(db database.Database) ([]Account, error) {
+	accounts := []Account(nil)
+	err := vm.forEachAccount(db, func(account Account) error {
+		accounts = append(accounts, account)
+		return nil
+	})
+	return accounts, err
+}
+
+// forEachAccount calls [f] with each account in [db], in the order they're
+// stored in, until [f] fails. A value is only treated as an account if it is
+// stored under the key that the account would have been stored under.
+func (vm *VM) forEachAccount(db database.Database, f func(Account) error) error {
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		key, err := ids.ToID(iter.Key())
+		if err != nil {
+			continue
+		}
+		account := Account{}
+		if err := Codec.Unmarshal(iter.Value(), &account); err != nil || account.Address.IsZero() {
+			continue
+		}
+		if !account.Address.LongID().Prefix(accountTypeID).Equals(key) {
+			continue
+		}
+		if err := f(account); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
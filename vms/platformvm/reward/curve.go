@@ -0,0 +1,132 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package reward computes the $AVA minted to reward stakers. Each network picks
+// the emission schedule, a Curve, it runs with at genesis.
+package reward
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// hoursPerYear is the number of hours in the year the annual rates of curves
+// are over
+const hoursPerYear = 365. * 24.
+
+// Curve is an emission schedule. It determines how much newly minted $AVA a
+// staker is rewarded with. Every node of a network must run with the same
+// curve.
+type Curve interface {
+	// Reward returns the amount of $AVA to reward a staker that stakes
+	// [amount] for [duration], starting at [start], if the total supply of
+	// $AVA is [supply] when the staker is rewarded
+	Reward(amount uint64, start time.Time, duration time.Duration, supply uint64) uint64
+
+	// Rate returns the annual rate, as a fraction of the staked amount, at
+	// which a staker that starts staking at [start] would be rewarded, if the
+	// total supply of $AVA is [supply]
+	Rate(start time.Time, supply uint64) float64
+
+	// String describes this curve
+	String() string
+}
+
+// Compounding rewards stakers as if their stake earned compound interest at
+// the annual rate [AnnualRate]
+type Compounding struct{ AnnualRate float64 }
+
+// Reward implements the Curve interface
+func (c Compounding) Reward(amount uint64, _ time.Time, duration time.Duration, _ uint64) uint64 {
+	years := duration.Hours() / hoursPerYear
+
+	// Total value of the stake and its reward
+	value := float64(amount) * math.Pow(1+c.AnnualRate, years)
+	return uint64(value - float64(amount))
+}
+
+// Rate implements the Curve interface
+func (c Compounding) Rate(time.Time, uint64) float64 { return c.AnnualRate }
+
+func (c Compounding) String() string { return fmt.Sprintf("compounding(%g)", c.AnnualRate) }
+
+// Linear rewards stakers as if their stake earned simple interest at the
+// annual rate [AnnualRate]
+type Linear struct{ AnnualRate float64 }
+
+// Reward implements the Curve interface
+func (l Linear) Reward(amount uint64, _ time.Time, duration time.Duration, _ uint64) uint64 {
+	years := duration.Hours() / hoursPerYear
+	return uint64(float64(amount) * l.AnnualRate * years)
+}
+
+// Rate implements the Curve interface
+func (l Linear) Rate(time.Time, uint64) float64 { return l.AnnualRate }
+
+func (l Linear) String() string { return fmt.Sprintf("linear(%g)", l.AnnualRate) }
+
+// Halving rewards stakers according to [Curve], but halves their reward every
+// [Period] after [Start]. A staker's reward is halved as many times as periods
+// ended before the staker started staking.
+type Halving struct {
+	Curve  Curve
+	Start  time.Time
+	Period time.Duration
+}
+
+// Reward implements the Curve interface
+func (h Halving) Reward(amount uint64, start time.Time, duration time.Duration, supply uint64) uint64 {
+	return h.Curve.Reward(amount, start, duration, supply) >> h.halvings(start)
+}
+
+// Rate implements the Curve interface
+func (h Halving) Rate(start time.Time, supply uint64) float64 {
+	return h.Curve.Rate(start, supply) / math.Pow(2, float64(h.halvings(start)))
+}
+
+func (h Halving) String() string {
+	return fmt.Sprintf("halving(%s, every %s from %s)", h.Curve, h.Period, h.Start.UTC().Format(time.RFC3339))
+}
+
+// halvings returns the number of times the reward of a staker that starts at
+// [start] is halved
+func (h Halving) halvings(start time.Time) uint {
+	if h.Period <= 0 || !start.After(h.Start) {
+		return 0
+	}
+	halvings := uint(start.Sub(h.Start) / h.Period)
+	if halvings > 63 {
+		return 63
+	}
+	return halvings
+}
+
+// Capped rewards stakers according to [Curve], but never mints more than would
+// bring the total supply of $AVA over [MaxSupply]
+type Capped struct {
+	Curve     Curve
+	MaxSupply uint64
+}
+
+// Reward implements the Curve interface
+func (c Capped) Reward(amount uint64, start time.Time, duration time.Duration, supply uint64) uint64 {
+	if supply >= c.MaxSupply {
+		return 0
+	}
+	reward := c.Curve.Reward(amount, start, duration, supply)
+	if remaining := c.MaxSupply - supply; reward > remaining {
+		return remaining
+	}
+	return reward
+}
+
+// Rate implements the Curve interface
+func (c Capped) Rate(start time.Time, supply uint64) float64 {
+	if supply >= c.MaxSupply {
+		return 0
+	}
+	return c.Curve.Rate(start, supply)
+}
+
+func (c Capped) String() string { return fmt.Sprintf("capped(%s, at %d)", c.Curve, c.MaxSupply) }
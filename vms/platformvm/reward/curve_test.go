@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reward
+
+import (
+	"testing"
+	"time"
+)
+
+const yearDuration = 365 * 24 * time.Hour
+
+var genesis = time.Unix(1572566400, 0)
+
+func TestCompounding(t *testing.T) {
+	curve := Compounding{AnnualRate: 0.04}
+	if reward := curve.Reward(1000000, genesis, yearDuration, 0); reward != 40000 {
+		t.Fatalf("expected a reward of %d but got %d", 40000, reward)
+	}
+	if reward := curve.Reward(1000000, genesis, 2*yearDuration, 0); reward != 81600 {
+		t.Fatalf("expected a reward of %d but got %d", 81600, reward)
+	}
+	if rate := curve.Rate(genesis, 0); rate != 0.04 {
+		t.Fatalf("expected a rate of %g but got %g", 0.04, rate)
+	}
+}
+
+func TestLinear(t *testing.T) {
+	curve := Linear{AnnualRate: 0.05}
+	if reward := curve.Reward(1000000, genesis, 2*yearDuration, 0); reward != 100000 {
+		t.Fatalf("expected a reward of %d but got %d", 100000, reward)
+	}
+	if reward := curve.Reward(1000000, genesis, 0, 0); reward != 0 {
+		t.Fatalf("expected no reward but got %d", reward)
+	}
+}
+
+func TestHalving(t *testing.T) {
+	curve := Halving{
+		Curve:  Linear{AnnualRate: 0.08},
+		Start:  genesis,
+		Period: 4 * yearDuration,
+	}
+
+	tests := []struct {
+		start  time.Time
+		reward uint64
+		rate   float64
+	}{
+		{start: genesis.Add(-time.Hour), reward: 80000, rate: 0.08},
+		{start: genesis.Add(yearDuration), reward: 80000, rate: 0.08},
+		{start: genesis.Add(4 * yearDuration), reward: 40000, rate: 0.04},
+		{start: genesis.Add(9 * yearDuration), reward: 20000, rate: 0.02},
+	}
+	for _, test := range tests {
+		if reward := curve.Reward(1000000, test.start, yearDuration, 0); reward != test.reward {
+			t.Fatalf("starting at %s, expected a reward of %d but got %d", test.start, test.reward, reward)
+		}
+		if rate := curve.Rate(test.start, 0); rate != test.rate {
+			t.Fatalf("starting at %s, expected a rate of %g but got %g", test.start, test.rate, rate)
+		}
+	}
+}
+
+func TestCapped(t *testing.T) {
+	curve := Capped{
+		Curve:     Linear{AnnualRate: 0.1},
+		MaxSupply: 1050000,
+	}
+	if reward := curve.Reward(100000, genesis, yearDuration, 1000000); reward != 10000 {
+		t.Fatalf("expected a reward of %d but got %d", 10000, reward)
+	}
+	if reward := curve.Reward(1000000, genesis, yearDuration, 1000000); reward != 50000 {
+		t.Fatalf("expected the reward to be capped at %d but got %d", 50000, reward)
+	}
+	if reward := curve.Reward(1000000, genesis, yearDuration, 1050000); reward != 0 {
+		t.Fatalf("expected no reward once the supply is capped but got %d", reward)
+	}
+	if rate := curve.Rate(genesis, 1050000); rate != 0 {
+		t.Fatalf("expected a rate of 0 once the supply is capped but got %g", rate)
+	}
+}
@@ -33,6 +33,9 @@ type rewardValidatorTx struct {
 	TxID ids.ID `serialize:"true"`
 
 	vm *VM
+
+	// The staker being removed. Set by SemanticVerify.
+	staker TimedTx
 }
 
 func (tx *rewardValidatorTx) initialize(vm *VM) error {
@@ -93,7 +96,14 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 			endTime)
 	}
 
+	// The reward may depend on how much $AVA has already been minted
+	supply, err := tx.vm.getTotalSupply(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	heap.Pop(currentEvents) // Remove validator from the validator set
+	tx.staker = vdrTx
 
 	onCommitDB := versiondb.New(db)
 	// If this tx's proposal is committed, remove the validator from the validator set and update the
@@ -110,10 +120,10 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 	}
 
 	switch vdrTx := vdrTx.(type) {
-	case *addDefaultSubnetValidatorTx:
+	case *AddDefaultSubnetValidatorTx:
 		duration := vdrTx.Duration()
 		amount := vdrTx.Wght
-		reward := reward(duration, amount, InflationRate)
+		reward := tx.vm.rewardCurve.Reward(amount, vdrTx.StartTime(), duration, supply)
 		amountWithReward, err := math.Add64(amount, reward)
 		if err != nil {
 			amountWithReward = amount
@@ -146,10 +156,14 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		if err := tx.vm.putAccount(onCommitDB, accountWithReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
+		// The reward is newly minted $AVA
+		if err := tx.vm.mintSupply(onCommitDB, amountWithReward-amount); err != nil {
+			return nil, nil, nil, nil, err
+		}
 		if err := tx.vm.putAccount(onAbortDB, accountNoReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
-	case *addDefaultSubnetDelegatorTx:
+	case *AddDefaultSubnetDelegatorTx:
 		parentTx, err := currentEvents.getDefaultSubnetStaker(vdrTx.NodeID)
 		if err != nil {
 			return nil, nil, nil, nil, err
@@ -157,7 +171,7 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 
 		duration := vdrTx.Duration()
 		amount := vdrTx.Wght
-		reward := reward(duration, amount, InflationRate)
+		reward := tx.vm.rewardCurve.Reward(amount, vdrTx.StartTime(), duration, supply)
 
 		// Because parentTx.Shares <= NumberOfShares this will never underflow
 		delegatorShares := NumberOfShares - uint64(parentTx.Shares)
@@ -206,6 +220,11 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		if err := tx.vm.putAccount(onAbortDB, delegatorAccountNoReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
+		// Delegated $AVA isn't removed from the delegator's account when it's
+		// staked, so the amount returned to the delegator is newly minted too
+		if err := tx.vm.mintSupply(onAbortDB, amount); err != nil {
+			return nil, nil, nil, nil, err
+		}
 
 		validatorAccountID := parentTx.Destination
 		validatorAccount, err := tx.vm.getAccount(onCommitDB, validatorAccountID) // account receiving staked $AVA (and, if applicable, reward)
@@ -227,6 +246,16 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		if err := tx.vm.putAccount(onCommitDB, validatorAccountWithReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
+
+		// The delegator's stake and reward, and the validator's reward, are
+		// newly minted $AVA
+		newlyMinted, err := math.Add64(delegatorAmountWithReward, validatorReward)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if err := tx.vm.mintSupply(onCommitDB, newlyMinted); err != nil {
+			return nil, nil, nil, nil, err
+		}
 	default:
 		return nil, nil, nil, nil, errShouldBeDSValidator
 	}
@@ -243,14 +272,24 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 	return onCommitDB, onAbortDB, updateValidators, updateValidators, nil
 }
 
-// InitiallyPrefersCommit returns true.
+// InitiallyPrefersCommit returns true if this node was connected to the
+// validator, or the validator the delegator delegated to, for at least
+// MinimumUptime of the staking period.
 //
-// Right now, *Commit (that is, remove the validator and reward them) is always
-// preferred over *Abort (remove the validator but don't reward them.)
-//
-// TODO: A validator should receive a reward only if they are sufficiently
-// responsive and correct during the time they are validating.
-func (tx *rewardValidatorTx) InitiallyPrefersCommit() bool { return true }
+// If this node observed less than half of the staking period, it doesn't know
+// enough to judge, so it prefers *Commit (that is, remove the staker and reward
+// them) over *Abort (remove the staker but don't reward them.)
+func (tx *rewardValidatorTx) InitiallyPrefersCommit() bool {
+	if tx.vm.Uptimes == nil || tx.staker == nil {
+		return true
+	}
+	startTime := tx.staker.StartTime()
+	uptime, observed := tx.vm.Uptimes.Uptime(tx.staker.Vdr().ID(), startTime)
+	if 2*observed < tx.staker.EndTime().Sub(startTime) {
+		return true
+	}
+	return uptime >= MinimumUptime
+}
 
 // RewardStakerTx creates a new transaction that proposes to remove the staker
 // [validatorID] from the default validator set.
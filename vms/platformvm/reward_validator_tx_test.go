@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 )
@@ -54,13 +55,13 @@ func TestRewardValidatorTxSyntacticVerify(t *testing.T) {
 
 func TestRewardValidatorTxSemanticVerify(t *testing.T) {
 	vm := defaultVM()
-	var nextToRemove *addDefaultSubnetValidatorTx
+	var nextToRemove *AddDefaultSubnetValidatorTx
 	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
 	if err != nil {
 		t.Fatal(err)
 	}
 	// ID of validator that should leave DS validator set next
-	nextToRemove = currentValidators.Peek().(*addDefaultSubnetValidatorTx)
+	nextToRemove = currentValidators.Peek().(*AddDefaultSubnetValidatorTx)
 
 	// Case 1: Chain timestamp is wrong
 	tx, err := vm.newRewardValidatorTx(nextToRemove.ID())
@@ -126,6 +127,23 @@ func TestRewardValidatorTxSemanticVerify(t *testing.T) {
 	if account.Balance <= defaultBalance-txFee {
 		t.Fatal("expected account balance to have increased due to receiving validator reward")
 	}
+
+	// the validator reward should have been added to the total supply
+	genesisSupply, err := vm.getTotalSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reward := account.Balance - defaultBalance - nextToRemove.Wght
+	if supply, err := vm.getTotalSupply(onCommitDB); err != nil {
+		t.Fatal(err)
+	} else if supply != genesisSupply+reward {
+		t.Fatalf("expected total supply %d but got %d", genesisSupply+reward, supply)
+	}
+	if supply, err := vm.getTotalSupply(onAbortDB); err != nil {
+		t.Fatal(err)
+	} else if supply != genesisSupply {
+		t.Fatalf("expected total supply %d but got %d", genesisSupply, supply)
+	}
 }
 
 func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
@@ -231,3 +249,154 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
 	}
 }
+
+// test that the total supply is the $AVA held in accounts and locked by
+// validators after a delegator and a validator are rewarded
+func TestRewardTxsMaintainTotalSupply(t *testing.T) {
+	vm := defaultVM()
+
+	keyIntf1, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1 := keyIntf1.(*crypto.PrivateKeySECP256K1R)
+
+	keyIntf2, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2 := keyIntf2.(*crypto.PrivateKeySECP256K1R)
+
+	vdrTx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,     // nonce
+		defaultStakeAmount, // stakeAmt
+		uint64(defaultValidateEndTime.Add(-365*24*time.Hour).Unix())-1,
+		uint64(defaultValidateEndTime.Unix())-1,
+		key1.PublicKey().Address(), // node ID
+		key1.PublicKey().Address(), // destination
+		NumberOfShares/4,
+		testNetworkID,
+		key1,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delTx, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultNonce+1,     // nonce
+		defaultStakeAmount, // stakeAmt
+		uint64(defaultValidateEndTime.Add(-365*24*time.Hour).Unix())-1,
+		uint64(defaultValidateEndTime.Unix())-1,
+		key1.PublicKey().Address(), // node ID
+		key2.PublicKey().Address(), // destination
+		testNetworkID,
+		key2,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentValidators.Add(vdrTx)
+	currentValidators.Add(delTx)
+	if err := vm.putCurrentValidators(vm.DB, currentValidators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putTimestamp(vm.DB, defaultValidateEndTime.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	// Count the validator's stake in the total supply
+	if err := vm.reindex(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSupply := func(db database.Database) {
+		totalSupply, err := vm.getTotalSupply(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stateSupply, err := vm.stateSupply(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if totalSupply != stateSupply {
+			t.Fatalf("expected total supply %d but got %d", stateSupply, totalSupply)
+		}
+	}
+
+	tx, err := vm.newRewardValidatorTx(delTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err := tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(onCommitDB)
+	checkSupply(onAbortDB)
+
+	tx, err = vm.newRewardValidatorTx(vdrTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err = tx.SemanticVerify(onCommitDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(onCommitDB)
+	checkSupply(onAbortDB)
+}
+
+// testUptimes reports the same uptime for every node
+type testUptimes struct {
+	uptime   float64
+	observed time.Duration
+}
+
+func (u testUptimes) Status(ids.ShortID) (bool, time.Time) { return u.uptime > 0, time.Time{} }
+
+func (u testUptimes) Uptime(ids.ShortID, time.Time) (float64, time.Duration) {
+	return u.uptime, u.observed
+}
+
+func TestRewardValidatorTxPreferenceByUptime(t *testing.T) {
+	vm := defaultVM()
+	if err := vm.putTimestamp(vm.DB, defaultValidateEndTime); err != nil {
+		t.Fatal(err)
+	}
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextToRemove := currentValidators.Peek()
+	stakingPeriod := nextToRemove.EndTime().Sub(nextToRemove.StartTime())
+
+	tests := []struct {
+		uptimes       Uptimes
+		prefersCommit bool
+	}{
+		{nil, true},
+		{testUptimes{uptime: 1, observed: stakingPeriod}, true},
+		{testUptimes{uptime: MinimumUptime, observed: stakingPeriod}, true},
+		{testUptimes{uptime: MinimumUptime / 2, observed: stakingPeriod}, false},
+		{testUptimes{uptime: 0, observed: stakingPeriod / 2}, false},
+		{testUptimes{uptime: 0, observed: stakingPeriod / 4}, true}, // Too little observed to judge
+	}
+	for _, test := range tests {
+		vm.Uptimes = test.uptimes
+
+		tx, err := vm.newRewardValidatorTx(nextToRemove.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != nil {
+			t.Fatal(err)
+		}
+		if prefersCommit := tx.InitiallyPrefersCommit(); prefersCommit != test.prefersCommit {
+			t.Fatalf("with uptimes %+v, expected to prefer commit: %v, but got %v", test.uptimes, test.prefersCommit, prefersCommit)
+		}
+	}
+}
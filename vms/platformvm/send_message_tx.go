@@ -0,0 +1,233 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+var (
+	errMessageToSelf         = errors.New("the platform chain can't send messages to itself")
+	errMessagePayloadTooLong = fmt.Errorf("message payload is longer than %d bytes", snow.MaxMessagePayloadSize)
+	errMessagesNotActivated  = errors.New("messages aren't activated yet")
+	errUnknownDestination    = errors.New("messages can only be sent to chains created on the platform chain")
+	errNoSharedMemory        = errors.New("the platform chain has no shared memory to send messages through")
+)
+
+// UnsignedSendMessageTx is an unsigned transaction that sends a message to
+// another chain, through the shared memory of the nodes that run both chains
+type UnsignedSendMessageTx struct {
+	// The VM this tx exists within
+	vm *VM
+
+	// ID is this transaction's ID
+	ID ids.ID
+
+	// NetworkID is the ID of the network this tx was issued on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account that pays the tx fee
+	Nonce uint64 `serialize:"true"`
+
+	// ID of the chain the message is sent to
+	Destination ids.ID `serialize:"true"`
+
+	// Type of the message, which decides how the destination chain verifies it
+	Type uint32 `serialize:"true"`
+
+	// Payload of the message
+	Payload []byte `serialize:"true"`
+}
+
+// SendMessageTx is a transaction that sends a message to another chain. The
+// message is sent once the block containing the transaction is accepted, in
+// the same write as the rest of the block's changes.
+type SendMessageTx struct {
+	UnsignedSendMessageTx `serialize:"true"`
+
+	// The public key that signed this transaction
+	// The transaction fee is paid from the corresponding account
+	// (ie the account whose ID is [key].Address())
+	// [key] is non-nil iff this tx is valid
+	key crypto.PublicKey
+
+	// Signature on the UnsignedSendMessageTx's byte repr
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// Byte representation of this transaction (including signature)
+	bytes []byte
+}
+
+// verifySignatures implements the signedTx interface
+func (tx *SendMessageTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedSendMessageTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
+
+// SyntacticVerify nil iff [tx] is syntactically valid.
+// If [tx] is valid, this method sets [tx.key]
+func (tx *SendMessageTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.ID.IsZero():
+		return errInvalidID
+	case tx.NetworkID != tx.vm.Ctx.NetworkID:
+		return errWrongNetworkID
+	case tx.Destination.Equals(tx.vm.Ctx.ChainID):
+		return errMessageToSelf
+	case len(tx.Payload) > snow.MaxMessagePayloadSize:
+		return errMessagePayloadTooLong
+	}
+
+	// Byte representation of the unsigned transaction
+	unsignedIntf := interface{}(&tx.UnsignedSendMessageTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+
+	// Recover signature from byte repr. of unsigned tx
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:]) // the public key that signed [tx]
+	if err != nil {
+		return err
+	}
+
+	tx.key = key
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *SendMessageTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	// Blocks accepted before messages were activated never contain one
+	chainTime, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	if !active(tx.vm.Upgrades.MessageTime, chainTime) {
+		return nil, errMessagesNotActivated
+	}
+
+	chains, err := tx.vm.getChains(db)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, chain := range chains {
+		if chain.ID().Equals(tx.Destination) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errUnknownDestination
+	}
+
+	// Deduct the tx fee from the payer's account
+	account, err := tx.vm.getAccount(db, tx.key.Address())
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Remove(0, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// message returns the message that [tx] sends
+func (tx *SendMessageTx) message() *snow.Message {
+	return &snow.Message{
+		Destination: tx.Destination,
+		Type:        tx.Type,
+		Payload:     tx.Payload,
+	}
+}
+
+// commit the pending writes of vm.DB, and send [msgs], in one atomic write
+func (vm *VM) commit(msgs []*snow.Message) error {
+	if len(msgs) == 0 {
+		return vm.DB.Commit()
+	}
+	if vm.Ctx.SharedMemory == nil {
+		if err := vm.DB.Commit(); err != nil {
+			return err
+		}
+		return errNoSharedMemory
+	}
+
+	batch, err := vm.DB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := vm.Ctx.SharedMemory.Send(batch, msgs...); err != nil {
+		return err
+	}
+	vm.DB.Abort() // The batch was written, so the operations are committed
+	return nil
+}
+
+// Bytes returns the byte representation of [tx]
+func (tx *SendMessageTx) Bytes() []byte {
+	if tx.bytes != nil {
+		return tx.bytes
+	}
+	var err error
+	tx.bytes, err = Codec.Marshal(tx)
+	if err != nil {
+		tx.vm.Ctx.Log.Error("problem marshaling tx: %v", err)
+	}
+	return tx.bytes
+}
+
+// initialize sets [tx.vm] to [vm]
+func (tx *SendMessageTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	if err != nil {
+		return err
+	}
+	tx.bytes = txBytes
+	tx.ID = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return nil
+}
+
+func (vm *VM) newSendMessageTx(nonce uint64, destination ids.ID, msgType uint32, payload []byte, key *crypto.PrivateKeySECP256K1R) (*SendMessageTx, error) {
+	tx := &SendMessageTx{UnsignedSendMessageTx: UnsignedSendMessageTx{
+		vm:          vm,
+		NetworkID:   vm.Ctx.NetworkID,
+		Nonce:       nonce,
+		Destination: destination,
+		Type:        msgType,
+		Payload:     payload,
+	}}
+	if err := signSingle(&tx.UnsignedSendMessageTx, key, &tx.Sig); err != nil {
+		return nil, err
+	}
+	return tx, tx.initialize(vm)
+}
@@ -0,0 +1,165 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/avm"
+)
+
+// testSharedMemory records the messages sent through it
+type testSharedMemory struct {
+	sent []*snow.Message
+}
+
+func (sm *testSharedMemory) RegisterVerifier(uint32, snow.MessageVerifier) error { return nil }
+
+func (sm *testSharedMemory) Send(batch database.Batch, msgs ...*snow.Message) error {
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	sm.sent = append(sm.sent, msgs...)
+	return nil
+}
+
+func (sm *testSharedMemory) Receive(ids.ID, int) ([]*snow.Message, error) { return nil, nil }
+
+func (sm *testSharedMemory) Consume(database.Batch, ...*snow.Message) error { return nil }
+
+// putTestChain creates a chain that messages can be sent to
+func putTestChain(t *testing.T, vm *VM) ids.ID {
+	chain, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putChains(vm.DB, []*CreateChainTx{chain}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.DB.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return chain.ID()
+}
+
+func TestSendMessageTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	destination := putTestChain(t, vm)
+	payload := []byte{1, 2, 3}
+
+	tx, err := vm.newSendMessageTx(defaultNonce+1, destination, 1, payload, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+	sender, err := vm.getAccount(db, keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - txFee; sender.Balance != expected {
+		t.Fatalf("expected the sender's balance to be %d but was %d", expected, sender.Balance)
+	}
+
+	// The nonce was spent
+	if _, err := tx.SemanticVerify(versiondb.New(db)); err == nil {
+		t.Fatal("should have failed because the nonce was already spent")
+	}
+
+	// Messages can only be sent to chains that exist
+	tx, err = vm.newSendMessageTx(defaultNonce+1, ids.NewID([32]byte{1}), 1, payload, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != errUnknownDestination {
+		t.Fatalf("should have failed with %s but got %v", errUnknownDestination, err)
+	}
+
+	// Messages can't be sent to the platform chain
+	tx, err = vm.newSendMessageTx(defaultNonce+1, vm.Ctx.ChainID, 1, payload, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errMessageToSelf {
+		t.Fatalf("should have failed with %s but got %v", errMessageToSelf, err)
+	}
+
+	// Messages aren't valid before they're activated
+	vm.Upgrades.MessageTime = defaultGenesisTime.Add(time.Second)
+	tx, err = vm.newSendMessageTx(defaultNonce+1, destination, 1, payload, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != errMessagesNotActivated {
+		t.Fatalf("should have failed with %s but got %v", errMessagesNotActivated, err)
+	}
+}
+
+func TestSendMessageTxAccept(t *testing.T) {
+	vm := defaultVM()
+	sharedMemory := &testSharedMemory{}
+	vm.Ctx.SharedMemory = sharedMemory
+	destination := putTestChain(t, vm)
+	payload := []byte{1, 2, 3}
+
+	tx, err := vm.newSendMessageTx(defaultNonce+1, destination, 7, payload, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	blk, err := vm.BuildBlock()
+	vm.Ctx.Lock.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sharedMemory.sent) != 0 {
+		t.Fatal("shouldn't have sent the message before the block was accepted")
+	}
+	blk.Accept()
+
+	if len(sharedMemory.sent) != 1 {
+		t.Fatalf("should have sent 1 message but sent %d", len(sharedMemory.sent))
+	}
+	msg := sharedMemory.sent[0]
+	switch {
+	case !msg.Destination.Equals(destination):
+		t.Fatalf("sent the message to %s instead of %s", msg.Destination, destination)
+	case msg.Type != 7:
+		t.Fatalf("sent a message of type %d instead of 7", msg.Type)
+	case !bytes.Equal(msg.Payload, payload):
+		t.Fatalf("sent the payload %v instead of %v", msg.Payload, payload)
+	}
+
+	// The state of the block was written with the message
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blk.ID()) {
+		t.Fatalf("the last accepted block is %s instead of %s", lastAccepted, blk.ID())
+	}
+	sender, err := vm.getAccount(vm.DB, keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender.Nonce != defaultNonce+1 {
+		t.Fatalf("expected the sender's nonce to be %d but was %d", defaultNonce+1, sender.Nonce)
+	}
+}
@@ -9,29 +9,47 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"time"
+
+	stdmath "math"
 
 	"github.com/gorilla/rpc/v2/json2"
 
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/vms/components/watch"
 )
 
 var (
 	errMissingDecisionBlock = errors.New("should have a decision block within the past two blocks")
-	errParsingID            = errors.New("error parsing ID")
+	errParsingID            = json.ParseError(errors.New("error parsing ID"))
 	errGetAccount           = errors.New("error retrieving account information")
 	errGetAccounts          = errors.New("error getting accounts controlled by specified user")
-	errGetUser              = errors.New("error while getting user. Does user exist?")
 	errNoMethodWithGenesis  = errors.New("no method was provided but genesis data was provided")
+	errInvalidGenesis       = errors.New("invalid genesis data")
 	errCreatingTransaction  = errors.New("problem while creating transaction")
 	errNoDestination        = errors.New("call is missing field 'stakeDestination'")
 	errNoSource             = errors.New("call is missing field 'stakeSource'")
 	errGetStakeSource       = errors.New("couldn't get account specified in 'stakeSource'")
+	errNoSigners            = errors.New("call is missing field 'signer' or 'signers'")
+	errOneSigner            = errors.New("this tx must be signed by exactly one signer")
+	errNoUptimes            = errors.New("this node doesn't track its connections to validators")
+	errNoKeys               = json.ParseError(errors.New("call is missing field 'privateKey' or 'privateKeys'"))
+	errNoNodeID             = json.ParseError(errors.New("call is missing field 'nodeID'"))
+	errTooManyKeys          = json.ParseError(fmt.Errorf("at most %d private keys can be imported at once", MaxImportedKeys))
 )
 
+// MaxImportedKeys is the most private keys platform.importKey imports at once
+const MaxImportedKeys = 1024
+
 var key *crypto.PrivateKeySECP256K1R
 
 func init() {
@@ -48,6 +66,38 @@ func init() {
 	key = pk.(*crypto.PrivateKeySECP256K1R)
 }
 
+// InternalMethods are the API methods that act with the keys of keystore
+// users. They should only be exposed to this node's operator.
+var InternalMethods = []string{
+	"platform.listAccounts",
+	"platform.createAccount",
+	"platform.importKey",
+	"platform.sign",
+	"platform.signHash",
+	"platform.setAddressAlias",
+	"platform.removeAddressAlias",
+	"platform.listAddressAliases",
+}
+
+// WriteMethods are the API methods that issue txs or change keystore users.
+// Read-only API replicas don't serve them.
+var WriteMethods = []string{
+	"platform.issueTx",
+	"platform.createAccount",
+	"platform.importKey",
+	"platform.setAddressAlias",
+	"platform.removeAddressAlias",
+	"platform.sendMessage",
+}
+
+// WatchMethods are the API methods that manage the callback URLs notified of
+// accepted txs. They should only be exposed to this node's operator.
+var WatchMethods = []string{
+	"platform.watchAddress",
+	"platform.unwatchAddress",
+	"platform.listWatches",
+}
+
 // Service defines the API calls that can be made to the platform chain
 type Service struct{ vm *VM }
 
@@ -149,7 +199,7 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 
 	validators, err := service.vm.getCurrentValidators(service.vm.DB, args.SubnetID)
 	if err != nil {
-		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
+		return json.NotFoundError(fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID))
 	}
 
 	reply.Validators = make([]APIValidator, validators.Len())
@@ -157,11 +207,8 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 		vdr := tx.Vdr()
 		weight := json.Uint64(vdr.Weight())
 		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
-				ID:          vdr.ID(),
-				StartTime:   json.Uint64(tx.StartTime().Unix()),
-				EndTime:     json.Uint64(tx.EndTime().Unix()),
-				StakeAmount: &weight,
+			if reply.Validators[i], err = defaultSubnetAPIValidator(tx, validators); err != nil {
+				return err
 			}
 		} else {
 			reply.Validators[i] = APIValidator{
@@ -176,6 +223,95 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	return nil
 }
 
+// defaultSubnetAPIValidator returns the API representation of [tx], a staker in
+// [validators]. If [tx] adds a validator, the amount delegated to it by the
+// delegators in [validators] is included.
+func defaultSubnetAPIValidator(tx TimedTx, validators *EventHeap) (APIValidator, error) {
+	vdr := tx.Vdr()
+	weight := json.Uint64(vdr.Weight())
+	apiVdr := APIValidator{
+		ID:          vdr.ID(),
+		StartTime:   json.Uint64(tx.StartTime().Unix()),
+		EndTime:     json.Uint64(tx.EndTime().Unix()),
+		StakeAmount: &weight,
+	}
+	switch tx := tx.(type) {
+	case *AddDefaultSubnetValidatorTx:
+		delegated, err := delegatedStake(tx.NodeID, validators)
+		if err != nil {
+			return APIValidator{}, fmt.Errorf("couldn't get the stake delegated to %s: %w", tx.NodeID, err)
+		}
+		delegatedAmount := json.Uint64(delegated)
+		feeRate := json.Uint32(tx.Shares)
+		rewardOwner := tx.Destination
+		apiVdr.DelegatedAmount = &delegatedAmount
+		apiVdr.DelegationFeeRate = &feeRate
+		apiVdr.RewardOwner = &rewardOwner
+	case *AddDefaultSubnetDelegatorTx:
+		rewardOwner := tx.Destination
+		apiVdr.RewardOwner = &rewardOwner
+	}
+	return apiVdr, nil
+}
+
+// GetConnectedValidatorsArgs are the arguments for calling GetConnectedValidators
+type GetConnectedValidatorsArgs struct{}
+
+// APIConnectedValidator is whether this node is connected to a validator of
+// the default subnet
+type APIConnectedValidator struct {
+	ID        ids.ShortID `json:"id"`
+	Connected bool        `json:"connected"`
+
+	// Unix time, in seconds, this node last heard from the validator. 0 if it
+	// never has.
+	LastSeen json.Uint64 `json:"lastSeen"`
+
+	// Fraction of the validator's staking period, as far as this node
+	// observed it, that this node was connected to it
+	Uptime float64 `json:"uptime"`
+}
+
+// GetConnectedValidatorsReply are the results from calling GetConnectedValidators
+type GetConnectedValidatorsReply struct {
+	Validators []APIConnectedValidator `json:"validators"`
+}
+
+// GetConnectedValidators returns whether this node is connected to each
+// current validator of the default subnet, when it last heard from it, and its
+// uptime
+func (service *Service) GetConnectedValidators(_ *http.Request, _ *GetConnectedValidatorsArgs, reply *GetConnectedValidatorsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getConnectedValidators called")
+
+	if service.vm.Uptimes == nil {
+		return errNoUptimes
+	}
+	validators, err := service.vm.getCurrentValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the current validators: %w", err)
+	}
+
+	reply.Validators = []APIConnectedValidator{}
+	for _, tx := range validators.Txs {
+		tx, ok := tx.(*AddDefaultSubnetValidatorTx)
+		if !ok {
+			continue
+		}
+		connected, lastSeen := service.vm.Uptimes.Status(tx.NodeID)
+		uptime, _ := service.vm.Uptimes.Uptime(tx.NodeID, tx.StartTime())
+		apiVdr := APIConnectedValidator{
+			ID:        tx.NodeID,
+			Connected: connected,
+			Uptime:    uptime,
+		}
+		if !lastSeen.IsZero() {
+			apiVdr.LastSeen = json.Uint64(lastSeen.Unix())
+		}
+		reply.Validators = append(reply.Validators, apiVdr)
+	}
+	return nil
+}
+
 // GetPendingValidatorsArgs are the arguments for calling GetPendingValidators
 type GetPendingValidatorsArgs struct {
 	// Subnet we're getting the pending validators of
@@ -198,7 +334,7 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 
 	validators, err := service.vm.getPendingValidators(service.vm.DB, args.SubnetID)
 	if err != nil {
-		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
+		return json.NotFoundError(fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID))
 	}
 
 	reply.Validators = make([]APIValidator, validators.Len())
@@ -206,11 +342,8 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 		vdr := tx.Vdr()
 		weight := json.Uint64(vdr.Weight())
 		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
-				ID:          vdr.ID(),
-				StartTime:   json.Uint64(tx.StartTime().Unix()),
-				EndTime:     json.Uint64(tx.EndTime().Unix()),
-				StakeAmount: &weight,
+			if reply.Validators[i], err = defaultSubnetAPIValidator(tx, validators); err != nil {
+				return err
 			}
 		} else {
 			reply.Validators[i] = APIValidator{
@@ -233,14 +366,28 @@ type SampleValidatorsArgs struct {
 	// ID of subnet to sample validators from
 	// If omitted, defaults to the default subnet
 	SubnetID ids.ID `json:"subnetID"`
+
+	// If true, the reply includes the weight of each sampled validator, and
+	// the total weight of the subnet
+	IncludeWeights bool `json:"includeWeights"`
+
+	// If given, the sample is drawn with a source seeded with [Seed], so the
+	// same seed gives the same sample of the same validators
+	Seed *json.Uint64 `json:"seed"`
 }
 
 // SampleValidatorsReply are the results from calling Sample
 type SampleValidatorsReply struct {
 	Validators []ids.ShortID `json:"validators"`
+
+	// Weights[i] is the weight of Validators[i]. Only set if the weights were
+	// requested.
+	Weights     []json.Uint64 `json:"weights,omitempty"`
+	TotalWeight *json.Uint64  `json:"totalWeight,omitempty"`
 }
 
-// SampleValidators returns a sampling of the list of current validators
+// SampleValidators returns a sampling of the list of current validators. Each
+// validator is sampled with probability proportional to its weight.
 func (service *Service) SampleValidators(_ *http.Request, args *SampleValidatorsArgs, reply *SampleValidatorsReply) error {
 	service.vm.Ctx.Log.Debug("Sample called with {Size = %d}", args.Size)
 
@@ -248,22 +395,102 @@ func (service *Service) SampleValidators(_ *http.Request, args *SampleValidators
 		args.SubnetID = DefaultSubnetID
 	}
 
-	validators, ok := service.vm.Validators.GetValidatorSet(args.SubnetID)
+	vdrs, ok := service.vm.Validators.GetValidatorSet(args.SubnetID)
 	if !ok {
-		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
+		return json.NotFoundError(fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID))
 	}
 
-	sample := validators.Sample(int(args.Size))
+	sample := []validators.Validator(nil)
+	if args.Seed == nil {
+		sample = vdrs.Sample(int(args.Size))
+	} else {
+		// The order validators were added in changes which validators a seed
+		// samples, so they're sampled from a copy of the set in a canonical
+		// order
+		list := vdrs.List()
+		sort.Slice(list, func(i, j int) bool {
+			return bytes.Compare(list[i].ID().Bytes(), list[j].ID().Bytes()) < 0
+		})
+		sorted := validators.NewSet()
+		sorted.Set(list)
+		sample = sorted.SampleFrom(int(args.Size), random.NewSource(int64(*args.Seed)))
+	}
 	if setLen := len(sample); setLen != int(args.Size) {
 		return fmt.Errorf("current number of validators (%d) is insufficient to sample %d validators", setLen, args.Size)
 	}
 
-	reply.Validators = make([]ids.ShortID, int(args.Size))
+	sort.Slice(sample, func(i, j int) bool {
+		return bytes.Compare(sample[i].ID().Bytes(), sample[j].ID().Bytes()) < 0
+	})
+	reply.Validators = make([]ids.ShortID, len(sample))
 	for i, vdr := range sample {
 		reply.Validators[i] = vdr.ID()
 	}
+	if !args.IncludeWeights {
+		return nil
+	}
+
+	reply.Weights = make([]json.Uint64, len(sample))
+	for i, vdr := range sample {
+		reply.Weights[i] = json.Uint64(vdr.Weight())
+	}
+	totalWeight := uint64(0)
+	for _, vdr := range vdrs.List() {
+		newWeight, err := math.Add64(totalWeight, vdr.Weight())
+		if err != nil {
+			return fmt.Errorf("total weight of subnet %s overflows: %w", args.SubnetID, err)
+		}
+		totalWeight = newWeight
+	}
+	reply.TotalWeight = (*json.Uint64)(&totalWeight)
+	return nil
+}
+
+// GetValidatorSetHashArgs are the arguments for calling GetValidatorSetHash
+type GetValidatorSetHashArgs struct {
+	// Height of the snapshot, which must be a multiple of ValidatorSetEpoch
+	// If omitted, defaults to the most recent snapshot
+	Height *json.Uint64 `json:"height"`
+}
+
+// GetValidatorSetHashReply are the results from calling GetValidatorSetHash
+type GetValidatorSetHashReply struct {
+	Height     json.Uint64    `json:"height"`
+	Hash       ids.ID         `json:"hash"`
+	Validators []APIValidator `json:"validators"`
+}
+
+// GetValidatorSetHash returns a snapshot of the default subnet's validator set
+// and its aggregate hash. Snapshots are taken every ValidatorSetEpoch blocks.
+func (service *Service) GetValidatorSetHash(_ *http.Request, args *GetValidatorSetHashArgs, reply *GetValidatorSetHashReply) error {
+	service.vm.Ctx.Log.Debug("GetValidatorSetHash called")
+
+	var height uint64
+	if args.Height != nil {
+		height = uint64(*args.Height)
+	} else {
+		lastHeight, err := service.vm.getHeight(service.vm.DB)
+		if err != nil {
+			return err
+		}
+		height = lastHeight - lastHeight%ValidatorSetEpoch
+	}
+
+	validatorSet, err := service.vm.getValidatorSet(service.vm.DB, height)
+	if err != nil {
+		return fmt.Errorf("couldn't get the validator set at height %d: %w", height, err)
+	}
 
-	ids.SortShortIDs(reply.Validators)
+	reply.Height = json.Uint64(validatorSet.Height)
+	reply.Hash = validatorSet.Hash()
+	reply.Validators = make([]APIValidator, len(validatorSet.Validators))
+	for i, vdr := range validatorSet.Validators {
+		weight := json.Uint64(vdr.Wght)
+		reply.Validators[i] = APIValidator{
+			ID:     vdr.NodeID,
+			Weight: &weight,
+		}
+	}
 	return nil
 }
 
@@ -301,6 +528,397 @@ func (service *Service) GetAccount(_ *http.Request, args *GetAccountArgs, reply
 	return nil
 }
 
+// GetAccountProofArgs are the arguments for calling GetAccountProof
+type GetAccountProofArgs struct {
+	// Address of the account to prove
+	Address ids.ShortID `json:"address"`
+}
+
+// GetAccountProofReply is the response from calling GetAccountProof
+type GetAccountProofReply struct {
+	// ID of the last accepted decision block, whose state the proof is against
+	BlockID ids.ID `json:"blockID"`
+
+	// Root of the account trie once [BlockID] was accepted, which the block
+	// commits to
+	Root ids.ID `json:"root"`
+
+	// Account is the serialized account, or empty if the account doesn't exist
+	// and the proof shows that it isn't in the trie
+	Account formatting.CB58 `json:"account"`
+
+	// Bitmap and Siblings are the proof, as described by merkle.Proof
+	Bitmap   formatting.CB58 `json:"bitmap"`
+	Siblings []ids.ID        `json:"siblings"`
+}
+
+// GetAccountProof returns a proof of an account's nonce and balance against the
+// account root committed to by the last accepted decision block. Anyone who
+// knows that the block was accepted can verify the account without trusting
+// this node. It fails if the block doesn't commit to an account root, as is the
+// case of the blocks accepted before AccountRootTime and of the genesis block.
+func (service *Service) GetAccountProof(_ *http.Request, args *GetAccountProofArgs, reply *GetAccountProofReply) error {
+	service.vm.Ctx.Log.Debug("GetAccountProof called with %s", args.Address)
+
+	if args.Address.IsZero() {
+		return errEmptyAccountAddress
+	}
+
+	// A proposal block doesn't change the state, so the state is the one its
+	// parent results in
+	blk, err := service.vm.getBlock(service.vm.LastAccepted())
+	if err != nil {
+		return fmt.Errorf("couldn't get the last accepted block: %w", err)
+	}
+	if proposal, ok := blk.(*ProposalBlock); ok {
+		if blk = proposal.parentBlock(); blk == nil {
+			return fmt.Errorf("couldn't get the parent of the last accepted block %s", proposal.ID())
+		}
+	}
+	committed, ok := blk.(rootedBlock)
+	if !ok {
+		return errAccountRootNotCommitted
+	}
+
+	root, err := service.vm.getAccountRoot(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the root of the account trie: %w", err)
+	}
+	if !root.Equals(committed.accountRoot()) {
+		return fmt.Errorf("the account root %s differs from the root %s that block %s commits to", root, committed.accountRoot(), blk.ID())
+	}
+	proof, err := service.vm.getAccountProof(service.vm.DB, args.Address)
+	if err != nil {
+		return fmt.Errorf("couldn't prove the account: %w", err)
+	}
+
+	reply.BlockID = blk.ID()
+	reply.Root = root
+	reply.Account = formatting.CB58{Bytes: proof.Value}
+	reply.Bitmap = formatting.CB58{Bytes: proof.Bitmap[:]}
+	reply.Siblings = proof.Siblings
+	if reply.Siblings == nil {
+		reply.Siblings = []ids.ID{}
+	}
+	return nil
+}
+
+// GetTotalSupplyArgs are the arguments for calling GetTotalSupply
+type GetTotalSupplyArgs struct{}
+
+// GetTotalSupplyReply is the response from calling GetTotalSupply
+type GetTotalSupplyReply struct {
+	Supply json.Uint64 `json:"supply"`
+}
+
+// GetTotalSupply returns the amount of $AVA that has been minted and not burned
+func (service *Service) GetTotalSupply(_ *http.Request, _ *GetTotalSupplyArgs, reply *GetTotalSupplyReply) error {
+	service.vm.Ctx.Log.Debug("platform.getTotalSupply called")
+
+	totalSupply, err := service.vm.getTotalSupply(service.vm.DB)
+	if err != nil {
+		return err
+	}
+	reply.Supply = json.Uint64(totalSupply)
+	return nil
+}
+
+// GetStakingParametersArgs are the arguments for calling GetStakingParameters
+type GetStakingParametersArgs struct{}
+
+// GetStakingParametersReply is the response from calling GetStakingParameters
+type GetStakingParametersReply struct {
+	// The minimum amount of $AVA a validator must stake
+	MinimumStakeAmount json.Uint64 `json:"minimumStakeAmount"`
+
+	// The minimum amount of $AVA a delegator must delegate
+	MinimumDelegationAmount json.Uint64 `json:"minimumDelegationAmount"`
+
+	// A validator's own stake plus the stake delegated to it may be at most
+	// this many times its own stake
+	DelegationCapMultiplier json.Uint64 `json:"delegationCapMultiplier"`
+
+	// The shortest and longest amount of time, in seconds, a staker may stake
+	MinimumStakingDuration json.Uint64 `json:"minimumStakingDuration"`
+	MaximumStakingDuration json.Uint64 `json:"maximumStakingDuration"`
+}
+
+// GetStakingParameters returns the limits that stakers must abide by
+func (service *Service) GetStakingParameters(_ *http.Request, _ *GetStakingParametersArgs, reply *GetStakingParametersReply) error {
+	service.vm.Ctx.Log.Debug("platform.getStakingParameters called")
+
+	reply.MinimumStakeAmount = json.Uint64(MinimumStakeAmount)
+	reply.MinimumDelegationAmount = json.Uint64(service.vm.minDelegationAmount)
+	reply.DelegationCapMultiplier = json.Uint64(service.vm.delegationCapMultiplier)
+	reply.MinimumStakingDuration = json.Uint64(MinimumStakingDuration / time.Second)
+	reply.MaximumStakingDuration = json.Uint64(MaximumStakingDuration / time.Second)
+	return nil
+}
+
+// GetDelegationCapacityArgs are the arguments for calling GetDelegationCapacity
+type GetDelegationCapacityArgs struct {
+	// ID of the validator
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// APICapacityChange is the delegation capacity a validator has from [Time] on,
+// once the delegators whose staking period ends at [Time] are removed
+type APICapacityChange struct {
+	Time     json.Uint64 `json:"time"`
+	Capacity json.Uint64 `json:"capacity"`
+}
+
+// GetDelegationCapacityReply is the response from calling GetDelegationCapacity
+type GetDelegationCapacityReply struct {
+	// The validator's own stake, and the most it and its delegators may stake
+	StakeAmount json.Uint64 `json:"stakeAmount"`
+	MaxStake    json.Uint64 `json:"maxStake"`
+
+	// The stake delegated to the validator by current and pending delegators.
+	// Only the delegators whose staking periods overlap count against its
+	// delegation cap together.
+	Delegated json.Uint64 `json:"delegated"`
+
+	// How much more stake may be delegated to the validator now, until it
+	// stops validating
+	Capacity json.Uint64 `json:"capacity"`
+
+	// Unix time at which the validator stops validating. Delegation periods
+	// must end by then.
+	EndTime json.Uint64 `json:"endTime"`
+
+	// How the capacity grows as the delegators' staking periods end, sorted by
+	// time
+	Timeline []APICapacityChange `json:"timeline"`
+}
+
+// GetDelegationCapacity returns how much stake may still be delegated to the
+// validator [args.NodeID] before it hits its delegation cap, and how that
+// changes as its current and pending delegators leave
+func (service *Service) GetDelegationCapacity(_ *http.Request, args *GetDelegationCapacityArgs, reply *GetDelegationCapacityReply) error {
+	service.vm.Ctx.Log.Debug("platform.getDelegationCapacity called with nodeID: %s", args.NodeID)
+
+	currentEvents, err := service.vm.getCurrentValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get current validators of default subnet: %w", err)
+	}
+	pendingEvents, err := service.vm.getPendingValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get pending validators of default subnet: %w", err)
+	}
+	validator, err := currentEvents.getDefaultSubnetStaker(args.NodeID)
+	if err != nil {
+		if validator, err = pendingEvents.getDefaultSubnetStaker(args.NodeID); err != nil {
+			return json.NotFoundError(fmt.Errorf("%s isn't a current or pending validator of the default subnet", args.NodeID))
+		}
+	}
+
+	chainTime, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain time: %w", err)
+	}
+	delegated, err := delegatedStake(args.NodeID, currentEvents, pendingEvents)
+	if err != nil {
+		return err
+	}
+	// As in AddDefaultSubnetDelegatorTx.SemanticVerify, if the cap overflows no
+	// total stake can exceed it
+	maxStake, err := math.Mul64(validator.Wght, service.vm.delegationCapMultiplier)
+	if err != nil {
+		maxStake = stdmath.MaxUint64
+	}
+	// The capacity of a delegation from [startTime] until the validator stops
+	// validating
+	capacity := func(startTime time.Time) (uint64, error) {
+		peak, err := peakDelegatedStake(args.NodeID, startTime, validator.EndTime(), currentEvents, pendingEvents)
+		if err != nil {
+			return 0, err
+		}
+		totalStake, err := math.Add64(validator.Wght, peak)
+		if err != nil || totalStake > maxStake {
+			return 0, nil
+		}
+		return maxStake - totalStake, nil
+	}
+	currentCapacity, err := capacity(chainTime)
+	if err != nil {
+		return err
+	}
+
+	reply.StakeAmount = json.Uint64(validator.Wght)
+	reply.MaxStake = json.Uint64(maxStake)
+	reply.Delegated = json.Uint64(delegated)
+	reply.Capacity = json.Uint64(currentCapacity)
+	reply.EndTime = json.Uint64(validator.EndTime().Unix())
+
+	// A delegator counts against the cap until it's removed, at the end of its
+	// staking period
+	endTimes := []time.Time(nil)
+	for _, h := range []*EventHeap{currentEvents, pendingEvents} {
+		for _, txIntf := range h.Txs {
+			if tx, ok := txIntf.(*AddDefaultSubnetDelegatorTx); ok && args.NodeID.Equals(tx.NodeID) {
+				endTimes = append(endTimes, tx.EndTime())
+			}
+		}
+	}
+	sort.Slice(endTimes, func(i, j int) bool { return endTimes[i].Before(endTimes[j]) })
+
+	reply.Timeline = []APICapacityChange{}
+	for _, endTime := range endTimes {
+		if last := len(reply.Timeline) - 1; last >= 0 && int64(reply.Timeline[last].Time) == endTime.Unix() {
+			continue
+		}
+		// Start times are in seconds, so the next delegation may start a
+		// second after this delegator ends
+		endCapacity, err := capacity(endTime.Add(time.Second))
+		if err != nil {
+			return err
+		}
+		reply.Timeline = append(reply.Timeline, APICapacityChange{
+			Time:     json.Uint64(endTime.Unix()),
+			Capacity: json.Uint64(endCapacity),
+		})
+	}
+	return nil
+}
+
+// GetRewardRateArgs are the arguments for calling GetRewardRate
+type GetRewardRateArgs struct{}
+
+// GetRewardRateReply is the response from calling GetRewardRate
+type GetRewardRateReply struct {
+	// The emission schedule stakers are rewarded by
+	Curve string `json:"curve"`
+
+	// The annual rate, as a fraction of the staked amount, at which a staker
+	// starting at the current chain time would be rewarded
+	Rate float64 `json:"rate"`
+}
+
+// GetRewardRate returns the emission schedule in effect, and the rate at which
+// it currently rewards stakers
+func (service *Service) GetRewardRate(_ *http.Request, _ *GetRewardRateArgs, reply *GetRewardRateReply) error {
+	service.vm.Ctx.Log.Debug("platform.getRewardRate called")
+
+	chainTime, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain time: %w", err)
+	}
+	supply, err := service.vm.getTotalSupply(service.vm.DB)
+	if err != nil {
+		return err
+	}
+	reply.Curve = service.vm.rewardCurve.String()
+	reply.Rate = service.vm.rewardCurve.Rate(chainTime, supply)
+	return nil
+}
+
+// GetChainTimeArgs are the arguments for calling GetChainTime
+type GetChainTimeArgs struct{}
+
+// GetChainTimeReply is the response from calling GetChainTime
+// All times are Unix times, in seconds.
+type GetChainTimeReply struct {
+	// The chain time once the last accepted block was accepted
+	ChainTime json.Uint64 `json:"chainTime"`
+
+	// This node's time
+	LocalTime json.Uint64 `json:"localTime"`
+
+	// The next time a validator joins or leaves a validator set. Once this
+	// node's time reaches it, this node proposes advancing the chain time to
+	// it. 0 if no change is scheduled.
+	NextChangeTime json.Uint64 `json:"nextChangeTime"`
+
+	// The earliest and latest start times of a staker that this node would
+	// propose adding now. Until the start time bound is activated, the latest
+	// start time is unbounded.
+	EarliestStartTime json.Uint64 `json:"earliestStartTime"`
+	LatestStartTime   json.Uint64 `json:"latestStartTime"`
+}
+
+// GetChainTime returns the chain time, along with the start times a staker
+// issued to this node may have
+func (service *Service) GetChainTime(_ *http.Request, _ *GetChainTimeArgs, reply *GetChainTimeReply) error {
+	service.vm.Ctx.Log.Debug("platform.getChainTime called")
+
+	chainTime, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain time: %w", err)
+	}
+	localTime := service.vm.clock.Time()
+
+	reply.ChainTime = json.Uint64(chainTime.Unix())
+	reply.LocalTime = json.Uint64(localTime.Unix())
+	nextChangeTime := service.vm.nextValidatorChangeTime(service.vm.DB, true)
+	if nextEndTime := service.vm.nextValidatorChangeTime(service.vm.DB, false); nextEndTime.Before(nextChangeTime) {
+		nextChangeTime = nextEndTime
+	}
+	if nextChangeTime.Before(maxTime) {
+		reply.NextChangeTime = json.Uint64(nextChangeTime.Unix())
+	}
+
+	// Start times are in seconds, so round the earliest start time up
+	earliest := service.vm.earliestIssuableStartTime(localTime)
+	if truncated := earliest.Truncate(time.Second); truncated.Before(earliest) {
+		earliest = truncated.Add(time.Second)
+	}
+	if !earliest.After(chainTime) {
+		earliest = chainTime.Add(time.Second)
+	}
+	reply.EarliestStartTime = json.Uint64(earliest.Unix())
+	reply.LatestStartTime = json.Uint64(service.vm.latestStartTime(chainTime).Unix())
+	return nil
+}
+
+// GetSuggestedStakingTimesArgs are the arguments for calling
+// GetSuggestedStakingTimes
+type GetSuggestedStakingTimesArgs struct{}
+
+// GetSuggestedStakingTimesReply is the response from calling
+// GetSuggestedStakingTimes
+type GetSuggestedStakingTimesReply struct {
+	// A start time of a validator that this node will build, and propose, the
+	// transaction adding, if the transaction is issued soon
+	StartTime json.Uint64 `json:"startTime"`
+
+	// The earliest and latest end times of a validator that starts at
+	// [StartTime]
+	MinEndTime json.Uint64 `json:"minEndTime"`
+	MaxEndTime json.Uint64 `json:"maxEndTime"`
+
+	// How long, in seconds, after this node's time a validator must start for
+	// this node to build the transaction adding it
+	StartTimeMargin json.Uint64 `json:"startTimeMargin"`
+}
+
+// GetSuggestedStakingTimes returns the staking period of a validator that's
+// safe to build, sign and issue the transaction adding now. A validator that
+// starts too soon is dropped before it's added, so the transaction adding it
+// is never accepted.
+func (service *Service) GetSuggestedStakingTimes(_ *http.Request, _ *GetSuggestedStakingTimesArgs, reply *GetSuggestedStakingTimesReply) error {
+	service.vm.Ctx.Log.Debug("platform.getSuggestedStakingTimes called")
+
+	chainTime, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain time: %w", err)
+	}
+
+	startTime := service.vm.suggestedStartTime(service.vm.clock.Time())
+	if !startTime.After(chainTime) {
+		startTime = chainTime.Add(time.Second)
+	}
+	if err := service.vm.verifyStartTime(chainTime, startTime); err != nil {
+		return fmt.Errorf("no start time is safe: %w", err)
+	}
+
+	reply.StartTime = json.Uint64(startTime.Unix())
+	reply.MinEndTime = json.Uint64(startTime.Add(MinimumStakingDuration).Unix())
+	reply.MaxEndTime = json.Uint64(startTime.Add(MaximumStakingDuration).Unix())
+	reply.StartTimeMargin = json.Uint64(service.vm.startTimeMargin / time.Second)
+	return nil
+}
+
 // ListAccountsArgs are the arguments to ListAccounts
 type ListAccountsArgs struct {
 	// List all of the accounts controlled by this user
@@ -314,13 +932,13 @@ type ListAccountsReply struct {
 }
 
 // ListAccounts lists all of the accounts controlled by [args.Username]
-func (service *Service) ListAccounts(_ *http.Request, args *ListAccountsArgs, reply *ListAccountsReply) error {
+func (service *Service) ListAccounts(r *http.Request, args *ListAccountsArgs, reply *ListAccountsReply) error {
 	service.vm.Ctx.Log.Debug("platform.listAccounts called for user '%s'", args.Username)
 
 	// db holds the user's info that pertains to the Platform Chain
 	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
-		return errGetUser
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
 	}
 
 	// The user
@@ -334,8 +952,14 @@ func (service *Service) ListAccounts(_ *http.Request, args *ListAccountsArgs, re
 		return errGetAccounts
 	}
 
+	// Stop looking up the accounts if the client goes away
+	ctx := json.Context(r)
+
 	var accounts []APIAccount
 	for _, accountID := range accountIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		account, err := service.vm.getAccount(service.vm.DB, accountID) // Get account whose ID is [accountID]
 		if err != nil && err != database.ErrNotFound {
 			service.vm.Ctx.Log.Error("couldn't get account from database: %v", err)
@@ -383,7 +1007,7 @@ func (service *Service) CreateAccount(_ *http.Request, args *CreateAccountArgs,
 	// userDB holds the user's info that pertains to the Platform Chain
 	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
-		return errGetUser
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
 	}
 
 	// The user creating a new account
@@ -404,21 +1028,218 @@ func (service *Service) CreateAccount(_ *http.Request, args *CreateAccountArgs,
 		byteFormatter := formatting.CB58{}
 		err := byteFormatter.FromString(args.PrivateKey)
 		if err != nil {
-			return errors.New("problem while parsing privateKey")
+			return json.ParseError(errors.New("problem while parsing privateKey"))
 		}
 		pk, err := service.vm.factory.ToPrivateKey(byteFormatter.Bytes)
 		if err != nil {
-			return errors.New("problem while parsing privateKey")
+			return json.ParseError(errors.New("problem while parsing privateKey"))
 		}
 		privKey = pk.(*crypto.PrivateKeySECP256K1R)
 	}
 
-	if err := user.putAccount(privKey); err != nil { // Save the private key
-		return errors.New("problem saving account")
-	}
+	if err := user.putAccount(privKey); err != nil { // Save the private key
+		return errors.New("problem saving account")
+	}
+
+	reply.Address = privKey.PublicKey().Address()
+
+	return nil
+}
+
+// ImportKeyArgs are the arguments to ImportKey
+type ImportKeyArgs struct {
+	// User that will control the accounts
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The private key to import
+	PrivateKey string `json:"privateKey"`
+
+	// More private keys to import, such as when migrating many accounts
+	PrivateKeys []string `json:"privateKeys"`
+}
+
+// ImportKeyReply is the reply from ImportKey
+type ImportKeyReply struct {
+	// Address of the account controlled by [PrivateKey], if it was given
+	Address ids.ShortID `json:"address"`
+
+	// Addresses of the accounts controlled by the imported keys, in the order
+	// the keys were given. Each address appears once.
+	Addresses []ids.ShortID `json:"addresses"`
+
+	// Those of [Addresses] the user already controlled
+	Existing []ids.ShortID `json:"existing"`
+}
+
+// ImportKey gives [args.Username] control of the accounts controlled by the
+// given private keys. Either all of the keys are imported or none are.
+func (service *Service) ImportKey(_ *http.Request, args *ImportKeyArgs, reply *ImportKeyReply) error {
+	service.vm.Ctx.Log.Debug("platform.importKey called for user '%s'", args.Username)
+
+	keyStrs := args.PrivateKeys
+	if args.PrivateKey != "" {
+		keyStrs = append([]string{args.PrivateKey}, keyStrs...)
+	}
+	switch {
+	case len(keyStrs) == 0:
+		return errNoKeys
+	case len(keyStrs) > MaxImportedKeys:
+		return errTooManyKeys
+	}
+
+	// Parse all the keys before importing any of them
+	privKeys := make([]*crypto.PrivateKeySECP256K1R, 0, len(keyStrs))
+	reply.Addresses = make([]ids.ShortID, 0, len(keyStrs))
+	addresses := ids.ShortSet{}
+	for i, keyStr := range keyStrs {
+		byteFormatter := formatting.CB58{}
+		if err := byteFormatter.FromString(keyStr); err != nil {
+			return json.ParseError(fmt.Errorf("problem while parsing private key %d: %w", i, err))
+		}
+		pk, err := service.vm.factory.ToPrivateKey(byteFormatter.Bytes)
+		if err != nil {
+			return json.ParseError(fmt.Errorf("problem while parsing private key %d: %w", i, err))
+		}
+		privKey := pk.(*crypto.PrivateKeySECP256K1R)
+		address := privKey.PublicKey().Address()
+		if addresses.Contains(address) {
+			continue
+		}
+		addresses.Add(address)
+		privKeys = append(privKeys, privKey)
+		reply.Addresses = append(reply.Addresses, address)
+	}
+
+	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
+	}
+
+	// The keys are written to the user's database in one batch, so a failure
+	// doesn't leave some of them imported
+	vdb := versiondb.New(userDB)
+	user := user{db: vdb}
+	existing, err := user.putAccounts(privKeys)
+	if err != nil {
+		return fmt.Errorf("problem saving accounts: %w", err)
+	}
+	if err := vdb.Commit(); err != nil {
+		return fmt.Errorf("problem saving accounts: %w", err)
+	}
+
+	if args.PrivateKey != "" {
+		reply.Address = reply.Addresses[0]
+	}
+	reply.Existing = existing
+	if reply.Existing == nil {
+		reply.Existing = []ids.ShortID{}
+	}
+	return nil
+}
+
+/*
+ ******************************************************
+ ***************** Address Aliases ********************
+ ******************************************************
+ */
+
+// SetAddressAliasArgs are the arguments for calling SetAddressAlias
+type SetAddressAliasArgs struct {
+	// A keystore user of this node
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The alias, such as "cold-storage"
+	Name string `json:"name"`
+
+	// The address it aliases
+	Address ids.ShortID `json:"address"`
+}
+
+// SetAddressAliasReply is the reply from calling SetAddressAlias
+type SetAddressAliasReply struct {
+	Success bool `json:"success"`
+}
+
+// SetAddressAlias makes [args.Name] an alias of [args.Address] on this node,
+// replacing the address it aliased, if any. The alias may then be passed to
+// this API anywhere an address is expected. Aliases are shared by the
+// keystore users of this node, and any of them may change them.
+func (service *Service) SetAddressAlias(_ *http.Request, args *SetAddressAliasArgs, reply *SetAddressAliasReply) error {
+	service.vm.Ctx.Log.Debug("platform.setAddressAlias called for user '%s' with name '%s'", args.Username, args.Name)
+
+	if args.Address.IsZero() {
+		return errNoAliasAddress
+	}
+	if _, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password); err != nil {
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
+	}
+	if err := service.vm.addressAliases.put(args.Name, args.Address); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// RemoveAddressAliasArgs are the arguments for calling RemoveAddressAlias
+type RemoveAddressAliasArgs struct {
+	// A keystore user of this node
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The alias to remove
+	Name string `json:"name"`
+}
+
+// RemoveAddressAliasReply is the reply from calling RemoveAddressAlias
+type RemoveAddressAliasReply struct {
+	Success bool `json:"success"`
+}
+
+// RemoveAddressAlias removes the alias [args.Name] from this node
+func (service *Service) RemoveAddressAlias(_ *http.Request, args *RemoveAddressAliasArgs, reply *RemoveAddressAliasReply) error {
+	service.vm.Ctx.Log.Debug("platform.removeAddressAlias called for user '%s' with name '%s'", args.Username, args.Name)
+
+	if _, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password); err != nil {
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
+	}
+	if err := service.vm.addressAliases.remove(args.Name); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ListAddressAliasesArgs are the arguments for calling ListAddressAliases
+type ListAddressAliasesArgs struct{}
 
-	reply.Address = privKey.PublicKey().Address()
+// APIAddressAlias is an alias of an address
+type APIAddressAlias struct {
+	Name    string      `json:"name"`
+	Address ids.ShortID `json:"address"`
+}
 
+// ListAddressAliasesReply is the reply from calling ListAddressAliases
+type ListAddressAliasesReply struct {
+	Aliases []APIAddressAlias `json:"aliases"`
+}
+
+// ListAddressAliases returns the address aliases of this node, ordered by name
+func (service *Service) ListAddressAliases(_ *http.Request, _ *ListAddressAliasesArgs, reply *ListAddressAliasesReply) error {
+	service.vm.Ctx.Log.Debug("platform.listAddressAliases called")
+
+	names, addresses, err := service.vm.addressAliases.list()
+	if err != nil {
+		return err
+	}
+	reply.Aliases = make([]APIAddressAlias, len(names))
+	for i, name := range names {
+		reply.Aliases[i] = APIAddressAlias{
+			Name:    name,
+			Address: addresses[i],
+		}
+	}
 	return nil
 }
 
@@ -426,6 +1247,16 @@ type genericTx struct {
 	Tx interface{} `serialize:"true"`
 }
 
+// marshalUnsignedTx returns the bytes of the unsigned transaction [tx], which
+// are signed with Sign
+func marshalUnsignedTx(tx interface{}) ([]byte, error) {
+	txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCreatingTransaction, err)
+	}
+	return txBytes, nil
+}
+
 /*
  ******************************************************
  ************ Add Validators to Subnets ***************
@@ -456,7 +1287,7 @@ func (service *Service) AddDefaultSubnetValidator(_ *http.Request, args *AddDefa
 	}
 
 	// Create the transaction
-	tx := addDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
+	tx := AddDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
 		DurationValidator: DurationValidator{
 			Validator: Validator{
 				NodeID: args.ID,
@@ -470,10 +1301,18 @@ func (service *Service) AddDefaultSubnetValidator(_ *http.Request, args *AddDefa
 		NetworkID:   service.vm.Ctx.NetworkID,
 		Shares:      uint32(args.DelegationFeeRate),
 	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
+	}
 
-	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	txBytes, err := marshalUnsignedTx(&tx)
 	if err != nil {
-		return fmt.Errorf("problem while creating transaction: %w", err)
+		return err
+	}
+	// A validator that starts too soon would be dropped before it's added, so
+	// its transaction is never accepted
+	if err := service.vm.verifyBuildableStartTime(time.Unix(int64(args.StartTime), 0)); err != nil {
+		return fmt.Errorf("%w. platform.getSuggestedStakingTimes suggests a start time", err)
 	}
 
 	reply.UnsignedTx.Bytes = txBytes
@@ -507,7 +1346,7 @@ func (service *Service) AddDefaultSubnetDelegator(_ *http.Request, args *AddDefa
 	}
 
 	// Create the transaction
-	tx := addDefaultSubnetDelegatorTx{UnsignedAddDefaultSubnetDelegatorTx: UnsignedAddDefaultSubnetDelegatorTx{
+	tx := AddDefaultSubnetDelegatorTx{UnsignedAddDefaultSubnetDelegatorTx: UnsignedAddDefaultSubnetDelegatorTx{
 		DurationValidator: DurationValidator{
 			Validator: Validator{
 				NodeID: args.ID,
@@ -520,10 +1359,13 @@ func (service *Service) AddDefaultSubnetDelegator(_ *http.Request, args *AddDefa
 		Nonce:       uint64(args.PayerNonce),
 		Destination: args.Destination,
 	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
+	}
 
-	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	txBytes, err := marshalUnsignedTx(&tx)
 	if err != nil {
-		return fmt.Errorf("problem while creating transaction: %w", err)
+		return err
 	}
 
 	reply.UnsignedTx.Bytes = txBytes
@@ -550,33 +1392,28 @@ type AddNonDefaultSubnetValidatorResponse struct {
 // AddNonDefaultSubnetValidator adds a validator to a subnet other than the default subnet
 // Returns the unsigned transaction, which must be signed using Sign
 func (service *Service) AddNonDefaultSubnetValidator(_ *http.Request, args *AddNonDefaultSubnetValidatorArgs, response *AddNonDefaultSubnetValidatorResponse) error {
-	tx := addNonDefaultSubnetValidatorTx{
-		UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
-			SubnetValidator: SubnetValidator{
-				DurationValidator: DurationValidator{
-					Validator: Validator{
-						NodeID: args.APIValidator.ID,
-						Wght:   args.weight(),
-					},
-					Start: uint64(args.StartTime),
-					End:   uint64(args.EndTime),
+	tx := AddNonDefaultSubnetValidatorTx{UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
+		SubnetValidator: SubnetValidator{
+			DurationValidator: DurationValidator{
+				Validator: Validator{
+					NodeID: args.APIValidator.ID,
+					Wght:   args.weight(),
 				},
-				Subnet: args.SubnetID,
+				Start: uint64(args.StartTime),
+				End:   uint64(args.EndTime),
 			},
-			NetworkID: service.vm.Ctx.NetworkID,
-			Nonce:     uint64(args.PayerNonce),
+			Subnet: args.SubnetID,
 		},
-		ControlSigs: nil,
-		PayerSig:    [crypto.SECP256K1RSigLen]byte{},
-		vm:          nil,
-		id:          ids.ID{},
-		senderID:    ids.ShortID{},
-		bytes:       nil,
+		NetworkID: service.vm.Ctx.NetworkID,
+		Nonce:     uint64(args.PayerNonce),
+	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return err
 	}
 
-	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	txBytes, err := marshalUnsignedTx(&tx)
 	if err != nil {
-		return errCreatingTransaction
+		return err
 	}
 
 	response.UnsignedTx.Bytes = txBytes
@@ -598,7 +1435,13 @@ type SignArgs struct {
 	// The address of the key signing the bytes
 	Signer ids.ShortID `json:"signer"`
 
-	// User that controls Signer
+	// The addresses of additional keys signing the bytes
+	// Each signature is placed where the key can sign the tx, so a tx that
+	// requires both control signatures and a payer signature can be signed
+	// in one call
+	Signers []ids.ShortID `json:"signers"`
+
+	// User that controls Signer and Signers
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
@@ -613,172 +1456,122 @@ type SignResponse struct {
 func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignResponse) error {
 	service.vm.Ctx.Log.Debug("platform.sign called")
 
-	// Get the key of the Signer
+	signers := args.Signers
+	if !args.Signer.IsZero() {
+		signers = append([]ids.ShortID{args.Signer}, signers...)
+	}
+	if len(signers) == 0 {
+		return errNoSigners
+	}
+
+	// Get the keys of the signers
 	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("couldn't get data for user '%s'. Does user exist?", args.Username)
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
 	}
 	user := user{db: db}
 
-	key, err := user.getKey(args.Signer) // Key of [args.Signer]
-	if err != nil {
-		return errDB
-	}
-	if !bytes.Equal(key.PublicKey().Address().Bytes(), args.Signer.Bytes()) { // sanity check
-		return errors.New("got unexpected key from database")
+	signerSet := ids.ShortSet{}
+	keys := make([]*crypto.PrivateKeySECP256K1R, len(signers))
+	for i, signer := range signers {
+		if signerSet.Contains(signer) {
+			return fmt.Errorf("%s was specified as a signer more than once", signer)
+		}
+		signerSet.Add(signer)
+
+		key, err := user.getKey(signer) // Key of [signer]
+		if err != nil {
+			return errDB
+		}
+		if !bytes.Equal(key.PublicKey().Address().Bytes(), signer.Bytes()) { // sanity check
+			return errors.New("got unexpected key from database")
+		}
+		keys[i] = key
 	}
 
 	genTx := genericTx{}
 	if err := Codec.Unmarshal(args.Tx.Bytes, &genTx); err != nil {
-		return err
+		return json.ParseError(err)
 	}
 
-	switch tx := genTx.Tx.(type) {
-	case *addDefaultSubnetValidatorTx:
-		genTx.Tx, err = service.signAddDefaultSubnetValidatorTx(tx, key)
-	case *addDefaultSubnetDelegatorTx:
-		genTx.Tx, err = service.signAddDefaultSubnetDelegatorTx(tx, key)
-	case *addNonDefaultSubnetValidatorTx:
-		genTx.Tx, err = service.signAddNonDefaultSubnetValidatorTx(tx, key)
-	case *CreateSubnetTx:
-		genTx.Tx, err = service.signCreateSubnetTx(tx, key)
-	default:
-		err = errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
-	}
-	if err != nil {
-		return err
+	// An AddNonDefaultSubnetValidatorTx is signed by the control keys of the
+	// subnet the validator is added to
+	controlKeys := []ids.ShortID(nil)
+	threshold := uint16(0)
+	if tx, ok := genTx.Tx.(*AddNonDefaultSubnetValidatorTx); ok {
+		subnet, err := service.vm.getSubnet(service.vm.DB, tx.SubnetID())
+		if err != nil {
+			return fmt.Errorf("problem getting subnet information: %w", err)
+		}
+		controlKeys = subnet.ControlKeys
+		threshold = subnet.Threshold
 	}
 
-	reply.Tx.Bytes, err = Codec.Marshal(genTx)
+	reply.Tx.Bytes, err = signTx(genTx, keys, controlKeys, threshold)
+	if err == errUnknownTxType {
+		return json.ParseError(err)
+	}
 	return err
 }
 
-// Sign [unsigned] with [key]
-func (service *Service) signAddDefaultSubnetValidatorTx(tx *addDefaultSubnetValidatorTx, key *crypto.PrivateKeySECP256K1R) (*addDefaultSubnetValidatorTx, error) {
-	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
-
-	// TODO: Should we check if tx is already signed?
-	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetValidatorTx)
-	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
-	if err != nil {
-		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
-	}
+// SignHashArgs are the arguments to SignHash
+type SignHashArgs struct {
+	// The 32 byte hash to sign
+	Hash formatting.CB58 `json:"hash"`
 
-	sig, err := key.Sign(unsignedTxBytes)
-	if err != nil {
-		return nil, errors.New("error while signing")
-	}
-	if len(sig) != crypto.SECP256K1RSigLen {
-		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
-	}
-	copy(tx.Sig[:], sig)
+	// The address of the key signing the hash
+	Signer ids.ShortID `json:"signer"`
 
-	return tx, nil
+	// User that controls Signer
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
-// Sign [unsigned] with [key]
-func (service *Service) signAddDefaultSubnetDelegatorTx(tx *addDefaultSubnetDelegatorTx, key *crypto.PrivateKeySECP256K1R) (*addDefaultSubnetDelegatorTx, error) {
-	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
-
-	// TODO: Should we check if tx is already signed?
-	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetDelegatorTx)
-	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
-	if err != nil {
-		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
-	}
-
-	sig, err := key.Sign(unsignedTxBytes)
-	if err != nil {
-		return nil, errors.New("error while signing")
-	}
-	if len(sig) != crypto.SECP256K1RSigLen {
-		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
-	}
-	copy(tx.Sig[:], sig)
-
-	return tx, nil
+// SignHashResponse is the response from SignHash
+type SignHashResponse struct {
+	Signature formatting.CB58 `json:"signature"`
 }
 
-// Sign [xt] with [key]
-func (service *Service) signCreateSubnetTx(tx *CreateSubnetTx, key *crypto.PrivateKeySECP256K1R) (*CreateSubnetTx, error) {
-	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
+// SignHash signs [args.Hash] with the key of [args.Signer]. The hash is signed
+// in this chain's domain (see crypto.DomainSeparatedHash), so the signature
+// can't authorize a tx or be replayed on another chain.
+func (service *Service) SignHash(_ *http.Request, args *SignHashArgs, reply *SignHashResponse) error {
+	service.vm.Ctx.Log.Debug("platform.signHash called")
 
-	// TODO: Should we check if tx is already signed?
-	unsignedIntf := interface{}(&tx.UnsignedCreateSubnetTx)
-	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	signedHash, err := crypto.DomainSeparatedHash(service.vm.Ctx.ChainID, args.Hash.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+		return json.ParseError(fmt.Errorf("problem parsing hash: %w", err))
 	}
 
-	sig, err := key.Sign(unsignedTxBytes)
-	if err != nil {
-		return nil, errors.New("error while signing")
-	}
-	if len(sig) != crypto.SECP256K1RSigLen {
-		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
-	}
-	copy(tx.Sig[:], sig)
-
-	return tx, nil
-}
-
-// Signs an unsigned or partially signed addNonDefaultSubnetValidatorTx with [key]
-// If [key] is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
-// If [key] is a control key for the subnet and there is no empty spot in tx.ControlSigs, signs as payer
-// If [key] is not a control key, sign as payer (account controlled by [key] pays the tx fee)
-// Sorts tx.ControlSigs before returning
-// Assumes each element of tx.ControlSigs is actually a signature, not just empty bytes
-func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubnetValidatorTx, key *crypto.PrivateKeySECP256K1R) (*addNonDefaultSubnetValidatorTx, error) {
-	service.vm.Ctx.Log.Debug("platform.signAddNonDefaultSubnetValidatorTx called")
-
-	// Compute the byte repr. of the unsigned tx and the signature of [key] over it
-	unsignedIntf := interface{}(&tx.UnsignedAddNonDefaultSubnetValidatorTx)
-	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	// Get the key of the Signer
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
-		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
 	}
-	sig, err := key.Sign(unsignedTxBytes)
+	user := user{db: db}
+	key, err := user.getKey(args.Signer)
 	if err != nil {
-		return nil, errors.New("error while signing")
-	}
-	if len(sig) != crypto.SECP256K1RSigLen {
-		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+		return errDB
 	}
 
-	// Get information about the subnet
-	subnet, err := service.vm.getSubnet(service.vm.DB, tx.SubnetID())
+	sig, err := key.SignHash(signedHash)
 	if err != nil {
-		return nil, fmt.Errorf("problem getting subnet information: %v", err)
-	}
-
-	// Find the location at which [key] should put its signature.
-	// If [key] is a control key for this subnet and there is an empty spot in tx.ControlSigs, sign there
-	// If [key] is a control key for this subnet and there is no empty spot in tx.ControlSigs, sign as payer
-	// If [key] is not a control key, sign as payer (account controlled by [key] pays the tx fee)
-	controlKeySet := ids.ShortSet{}
-	controlKeySet.Add(subnet.ControlKeys...)
-	isControlKey := controlKeySet.Contains(key.PublicKey().Address())
-
-	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
-
-	if isControlKey && len(tx.ControlSigs) != int(subnet.Threshold) { // Sign as controlSig
-		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
-		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
-	} else if payerSigEmpty { // sign as payer
-		copy(tx.PayerSig[:], sig)
-	} else {
-		return nil, errors.New("no place for key to sign")
+		return fmt.Errorf("problem signing hash: %w", err)
 	}
 
-	crypto.SortSECP2561RSigs(tx.ControlSigs)
-
-	return tx, nil
+	reply.Signature.Bytes = sig
+	return nil
 }
 
 // IssueTxArgs are the arguments to IssueTx
 type IssueTxArgs struct {
 	// Tx being sent to the network
 	Tx formatting.CB58 `json:"tx"`
+
+	// IdempotencyKey is an optional client-supplied token. If a tx was
+	// recently issued with the same token, its ID is returned rather than
+	// issuing [Tx] again.
+	IdempotencyKey string `json:"idempotencyKey"`
 }
 
 // IssueTxResponse is the response from IssueTx
@@ -789,9 +1582,14 @@ type IssueTxResponse struct {
 
 // IssueTx issues the transaction [args.Tx] to the network
 func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *IssueTxResponse) error {
+	if txID, ok := service.vm.issuedTokens.Get("issueTx", args.IdempotencyKey); ok {
+		response.TxID = txID
+		return nil
+	}
+
 	genTx := genericTx{}
 	if err := Codec.Unmarshal(args.Tx.Bytes, &genTx); err != nil {
-		return err
+		return json.ParseError(err)
 	}
 
 	switch tx := genTx.Tx.(type) {
@@ -799,20 +1597,67 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *Is
 		if err := tx.initialize(service.vm); err != nil {
 			return fmt.Errorf("error initializing tx: %s", err)
 		}
+		if err := service.vm.verifyIssuableStartTime(tx.StartTime()); err != nil {
+			return err
+		}
 		service.vm.unissuedEvents.Push(tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, response.TxID)
+		return nil
+	case *CreateChainTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
 		defer service.vm.resetTimer()
 		response.TxID = tx.ID()
+		service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, response.TxID)
 		return nil
 	case *CreateSubnetTx:
 		if err := tx.initialize(service.vm); err != nil {
 			return fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID
+		service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, response.TxID)
+		return nil
+	case *TransferTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID
+		service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, response.TxID)
+		return nil
+	case *SendMessageTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
 		defer service.vm.resetTimer()
 		response.TxID = tx.ID
+		service.vm.issuedTokens.Put("issueTx", args.IdempotencyKey, response.TxID)
 		return nil
 	default:
-		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		return json.ParseError(errUnknownTxType)
 	}
 }
 
@@ -843,26 +1688,264 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	service.vm.Ctx.Log.Debug("platform.createSubnet called")
 
 	// Create the transaction
-	tx := CreateSubnetTx{
-		UnsignedCreateSubnetTx: UnsignedCreateSubnetTx{
-			NetworkID:   service.vm.Ctx.NetworkID,
-			Nonce:       uint64(args.PayerNonce),
-			ControlKeys: args.ControlKeys,
-			Threshold:   uint16(args.Threshold),
-		},
-		key:   nil,
-		Sig:   [65]byte{},
-		bytes: nil,
+	tx := CreateSubnetTx{UnsignedCreateSubnetTx: UnsignedCreateSubnetTx{
+		NetworkID:   service.vm.Ctx.NetworkID,
+		Nonce:       uint64(args.PayerNonce),
+		ControlKeys: args.ControlKeys,
+		Threshold:   uint16(args.Threshold),
+	}}
+
+	txBytes, err := marshalUnsignedTx(&tx)
+	if err != nil {
+		return err
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+/*
+ ******************************************************
+ ***************** Transfer $AVA **********************
+ ******************************************************
+ */
+
+// TransferArgs are the arguments to Transfer
+type TransferArgs struct {
+	// Address of the account the $AVA is sent to
+	To ids.ShortID `json:"to"`
+
+	// Amount of $AVA sent
+	Amount json.Uint64 `json:"amount"`
+
+	// Nonce of the account the $AVA is sent from
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// TransferResponse is the response from a call to Transfer
+type TransferResponse struct {
+	// Byte representation of the unsigned transaction to send the $AVA
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// Transfer returns an unsigned transaction that sends [args.Amount] $AVA to
+// [args.To]. The unsigned transaction must be signed with the key of the
+// account the $AVA is sent from.
+func (service *Service) Transfer(_ *http.Request, args *TransferArgs, response *TransferResponse) error {
+	service.vm.Ctx.Log.Debug("platform.transfer called")
+
+	tx := TransferTx{UnsignedTransferTx: UnsignedTransferTx{
+		NetworkID: service.vm.Ctx.NetworkID,
+		Nonce:     uint64(args.PayerNonce),
+		To:        args.To,
+		Amount:    uint64(args.Amount),
+	}}
+
+	txBytes, err := marshalUnsignedTx(&tx)
+	if err != nil {
+		return err
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+// SendArgs are the arguments to Send
+type SendArgs struct {
+	// User that controls the account the $AVA is sent from
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Address of the account the $AVA is sent to
+	To ids.ShortID `json:"to"`
+
+	// Amount of $AVA sent
+	Amount json.Uint64 `json:"amount"`
+}
+
+// SendResponse is the response from a call to Send
+type SendResponse struct {
+	// ID of the transaction sending the $AVA
+	TxID ids.ID `json:"txID"`
+}
+
+// Send issues a transaction that sends [args.Amount] $AVA to [args.To] from
+// an account controlled by [args.Username] that can afford it. The nonce and
+// balance of the account account for the transfers this node hasn't put into
+// blocks yet, so several transfers may be sent before the first is accepted.
+func (service *Service) Send(_ *http.Request, args *SendArgs, response *SendResponse) error {
+	service.vm.Ctx.Log.Debug("platform.send called")
+
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("couldn't get data for user '%s': %w", args.Username, err)
+	}
+	user := user{db: db}
+	accountIDs, err := user.getAccountIDs()
+	if err != nil {
+		return fmt.Errorf("couldn't get accounts of user '%s': %w", args.Username, err)
+	}
+
+	// The state if the preferred block were to be accepted
+	preferred, err := service.vm.getBlock(service.vm.Preferred())
+	if err != nil {
+		return err
+	}
+	preferredDecision, ok := preferred.(decision)
+	if !ok {
+		return errInvalidBlockType
+	}
+	preferredDB := preferredDecision.onAccept()
+
+	for _, accountID := range accountIDs {
+		account, err := service.vm.getAccount(preferredDB, accountID)
+		if err != nil {
+			return err
+		}
+		// Spend what the transfers this node hasn't put into blocks yet leave
+		nonce, sent := account.Nonce, uint64(0)
+		for _, tx := range service.vm.unissuedDecisionTxs {
+			if tx, ok := tx.(*TransferTx); ok && tx.SyntacticVerify() == nil && tx.key.Address().Equals(accountID) {
+				nonce++
+				sent += tx.Amount
+			}
+		}
+		if balance, err := math.Sub64(account.Balance, sent); err != nil || balance < uint64(args.Amount) {
+			continue
+		}
+
+		key, err := user.getKey(accountID)
+		if err != nil {
+			return errDB
+		}
+		tx, err := service.vm.newTransferTx(nonce+1, args.To, uint64(args.Amount), key)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		if err := service.vm.persistUnissuedTxs(); err != nil {
+			return fmt.Errorf("problem persisting tx: %w", err)
+		}
+		service.vm.resetTimer()
+
+		response.TxID = tx.ID
+		return nil
 	}
+	return fmt.Errorf("%w: no account of user '%s' holds %d $nAVA", errInsufficientFunds, args.Username, args.Amount)
+}
+
+/*
+ ******************************************************
+ ************* Send messages to chains ****************
+ ******************************************************
+ */
+
+// SendMessageArgs are the arguments to SendMessage
+type SendMessageArgs struct {
+	// ID of the chain the message is sent to
+	Destination ids.ID `json:"destination"`
 
-	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	// Type of the message, which decides how the destination chain verifies it
+	Type json.Uint32 `json:"type"`
+
+	// Payload of the message
+	Payload formatting.CB58 `json:"payload"`
+
+	// Nonce of the account that pays the transaction fee
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// SendMessageResponse is the response from a call to SendMessage
+type SendMessageResponse struct {
+	// Byte representation of the unsigned transaction to send the message
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// SendMessage returns an unsigned transaction that sends a message to the chain
+// [args.Destination]. Once the transaction is accepted, the nodes that run the
+// destination chain can receive the message through their shared memory. The
+// unsigned transaction must be signed with the key of the account that pays
+// the transaction fee.
+func (service *Service) SendMessage(_ *http.Request, args *SendMessageArgs, response *SendMessageResponse) error {
+	service.vm.Ctx.Log.Debug("platform.sendMessage called")
+
+	tx := SendMessageTx{UnsignedSendMessageTx: UnsignedSendMessageTx{
+		NetworkID:   service.vm.Ctx.NetworkID,
+		Nonce:       uint64(args.PayerNonce),
+		Destination: args.Destination,
+		Type:        uint32(args.Type),
+		Payload:     args.Payload.Bytes,
+	}}
+
+	txBytes, err := marshalUnsignedTx(&tx)
 	if err != nil {
-		return errCreatingTransaction
+		return err
 	}
 
 	response.UnsignedTx.Bytes = txBytes
 	return nil
+}
+
+// GetValidatorEventsArgs are the arguments for calling GetValidatorEvents
+type GetValidatorEventsArgs struct {
+	// The node whose events are returned
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// APIValidatorEvent is a transition in the lifecycle of a validator or
+// delegator
+type APIValidatorEvent struct {
+	// One of added, started, ended or rewarded
+	Kind string `json:"kind"`
+
+	// The tx that added the staker, and the subnet it stakes on
+	TxID      ids.ID `json:"txID"`
+	SubnetID  ids.ID `json:"subnetID"`
+	Delegator bool   `json:"delegator"`
+
+	// The proposal block that made the transition, the height of the decision
+	// block that enacted it, and the chain time once it happened
+	BlockID ids.ID      `json:"blockID"`
+	Height  json.Uint64 `json:"height"`
+	Time    json.Uint64 `json:"time"`
+}
+
+// GetValidatorEventsReply is the response from calling GetValidatorEvents
+type GetValidatorEventsReply struct {
+	Events []APIValidatorEvent `json:"events"`
+}
+
+// GetValidatorEvents returns the history of the node [args.NodeID] as a
+// validator or delegator of any subnet, in the order it happened. Only the
+// transitions accepted since this node started recording them are returned,
+// so the genesis validators aren't reported as added or started.
+func (service *Service) GetValidatorEvents(_ *http.Request, args *GetValidatorEventsArgs, reply *GetValidatorEventsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getValidatorEvents called with %s", args.NodeID)
 
+	if args.NodeID.IsZero() {
+		return errNoNodeID
+	}
+	events, err := service.vm.validatorEvents.list(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the validator events: %w", err)
+	}
+
+	reply.Events = make([]APIValidatorEvent, len(events))
+	for i, event := range events {
+		reply.Events[i] = APIValidatorEvent{
+			Kind:      event.Kind.String(),
+			TxID:      event.TxID,
+			SubnetID:  event.SubnetID,
+			Delegator: event.Delegator,
+			BlockID:   event.BlockID,
+			Height:    json.Uint64(event.Height),
+			Time:      json.Uint64(event.Time),
+		}
+	}
+	return nil
 }
 
 /*
@@ -904,14 +1987,14 @@ type CreateBlockchainReply struct {
 func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchainArgs, reply *CreateBlockchainReply) error {
 	vmID, err := service.vm.ChainManager.LookupVM(args.VMID)
 	if err != nil {
-		return fmt.Errorf("no VM with ID '%s' found", args.VMID)
+		return json.NotFoundError(fmt.Errorf("no VM with ID '%s' found", args.VMID))
 	}
 
 	fxIDs := []ids.ID(nil)
 	for _, fxIDStr := range args.FxIDs {
 		fxID, err := service.vm.ChainManager.LookupVM(fxIDStr)
 		if err != nil {
-			return fmt.Errorf("no FX with ID '%s' found", fxIDStr)
+			return json.NotFoundError(fmt.Errorf("no FX with ID '%s' found", fxIDStr))
 		}
 		fxIDs = append(fxIDs, fxID)
 	}
@@ -944,6 +2027,11 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		return errNoMethodWithGenesis
 	}
 
+	// A chain whose genesis data its VM can't initialize with could never run
+	if err := service.vm.ChainManager.VerifyGenesis(vmID, fxIDs, genesisBytes); err != nil {
+		return fmt.Errorf("%w for VM %s: %s", errInvalidGenesis, args.VMID, err)
+	}
+
 	// TODO: Should use the key store to sign this transaction.
 	// TODO: Nonce shouldn't always be 0
 	tx, err := service.vm.newCreateChainTx(0, genesisBytes, vmID, fxIDs, args.Name, service.vm.Ctx.NetworkID, key)
@@ -953,6 +2041,9 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 
 	// Add this tx to the set of unissued txs
 	service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+	if err := service.vm.persistUnissuedTxs(); err != nil {
+		return fmt.Errorf("problem persisting transaction: %w", err)
+	}
 	service.vm.resetTimer()
 
 	reply.BlockchainID = tx.ID()
@@ -982,7 +2073,7 @@ func (service *Service) GetBlockchainStatus(_ *http.Request, args *GetBlockchain
 
 	bID, err := ids.FromString(args.BlockchainID)
 	if err != nil {
-		return fmt.Errorf("problem parsing blockchainID '%s': %w", args.BlockchainID, err)
+		return json.ParseError(fmt.Errorf("problem parsing blockchainID '%s': %w", args.BlockchainID, err))
 	}
 
 	lastAcceptedID := service.vm.LastAccepted()
@@ -1028,3 +2119,35 @@ func (service *Service) chainExists(blockID ids.ID, chainID ids.ID) (bool, error
 
 	return false, nil
 }
+
+/*
+ ******************************************************
+ ******************* Watches **************************
+ ******************************************************
+ */
+
+// WatchAddress registers [args.URL] to be notified, with a signed POST, when a
+// tx that pays from or to the account [args.Address] is accepted
+func (service *Service) WatchAddress(_ *http.Request, args *watch.WatchAddressArgs, reply *watch.WatchAddressReply) error {
+	service.vm.Ctx.Log.Verbo("WatchAddress called with address: %s", args.Address)
+
+	address, err := ids.ShortFromString(args.Address)
+	if err != nil {
+		return json.ParseError(err)
+	}
+	return service.vm.watcher.WatchAddress(address, args, reply)
+}
+
+// UnwatchAddress removes the watch with ID [args.WatchID]
+func (service *Service) UnwatchAddress(_ *http.Request, args *watch.UnwatchAddressArgs, reply *watch.UnwatchAddressReply) error {
+	service.vm.Ctx.Log.Verbo("UnwatchAddress called with watch: %s", args.WatchID)
+
+	return service.vm.watcher.UnwatchAddress(args, reply)
+}
+
+// ListWatches lists the registered watches
+func (service *Service) ListWatches(_ *http.Request, args *watch.ListWatchesArgs, reply *watch.ListWatchesReply) error {
+	service.vm.Ctx.Log.Verbo("ListWatches called")
+
+	return service.vm.watcher.ListWatches(args, reply)
+}
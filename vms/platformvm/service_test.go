@@ -4,8 +4,23 @@
 package platformvm
 
 import (
+	"container/heap"
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/platformvm/reward"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
 func TestAddDefaultSubnetValidator(t *testing.T) {
@@ -21,6 +36,31 @@ func TestAddDefaultSubnetValidator(t *testing.T) {
 	}
 }
 
+func TestAddDefaultSubnetValidatorDuration(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	args := AddDefaultSubnetValidatorArgs{}
+	args.StartTime = 1
+	args.EndTime = 1 + 3650*24*60*60
+	err := service.AddDefaultSubnetValidator(nil, &args, &AddDefaultSubnetValidatorResponse{})
+	if !errors.Is(err, errStakeTooLong) {
+		t.Fatalf("should have failed because the staking period is too long but got %v", err)
+	}
+	if expected := "staking period is too long: duration 3650d exceeds max 365d"; err.Error() != expected {
+		t.Fatalf("expected error %q but got %q", expected, err)
+	}
+
+	args.EndTime = 1 + 60*60
+	err = service.AddDefaultSubnetValidator(nil, &args, &AddDefaultSubnetValidatorResponse{})
+	if !errors.Is(err, errStakeTooShort) {
+		t.Fatalf("should have failed because the staking period is too short but got %v", err)
+	}
+	if expected := "staking period is too short: duration 1h0m0s is below min 1d"; err.Error() != expected {
+		t.Fatalf("expected error %q but got %q", expected, err)
+	}
+}
+
 func TestCreateBlockchainArgsParsing(t *testing.T) {
 	jsonString := `{"vmID":"lol","chainName":"awesome","genesisData":{"key":"value"}}`
 	args := CreateBlockchainArgs{}
@@ -32,3 +72,662 @@ func TestCreateBlockchainArgsParsing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGetTotalSupply(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetTotalSupplyReply{}
+	if err := service.GetTotalSupply(nil, &GetTotalSupplyArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each genesis account holds [defaultBalance] and each genesis validator
+	// stakes [defaultStakeAmount]
+	expected := uint64(len(keys)) * (defaultBalance + defaultStakeAmount)
+	if uint64(reply.Supply) != expected {
+		t.Fatalf("expected total supply %d but got %d", expected, reply.Supply)
+	}
+}
+
+func TestSignMultipleSigners(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	db, err := vm.Ctx.Keystore.GetDatabase("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := user{db: db}
+	for _, key := range keys {
+		if err := user.putAccount(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unsignedTx := &AddNonDefaultSubnetValidatorTx{
+		UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
+			SubnetValidator: SubnetValidator{
+				DurationValidator: DurationValidator{
+					Validator: Validator{
+						NodeID: keys[0].PublicKey().Address(),
+						Wght:   defaultWeight,
+					},
+					Start: uint64(defaultValidateStartTime.Unix()) + 1,
+					End:   uint64(defaultValidateEndTime.Unix()),
+				},
+				Subnet: testSubnet1.ID,
+			},
+			NetworkID: testNetworkID,
+			Nonce:     defaultNonce + 1,
+		},
+	}
+	txBytes, err := Codec.Marshal(genericTx{Tx: unsignedTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys[0] and keys[1] are control keys of testSubnet1 and keys[4] pays the
+	// fee. The payer is listed last but must still sign as payer.
+	reply := SignResponse{}
+	if err := service.Sign(nil, &SignArgs{
+		Tx: formatting.CB58{Bytes: txBytes},
+		Signers: []ids.ShortID{
+			keys[0].PublicKey().Address(),
+			keys[1].PublicKey().Address(),
+			keys[4].PublicKey().Address(),
+		},
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(reply.Tx.Bytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := genTx.Tx.(*AddNonDefaultSubnetValidatorTx)
+	if !ok {
+		t.Fatalf("Sign returned the wrong type of tx")
+	}
+	unsignedIntf := interface{}(&tx.UnsignedAddNonDefaultSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	payer, err := factory.RecoverPublicKey(unsignedBytes, tx.PayerSig[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !payer.Address().Equals(keys[4].PublicKey().Address()) {
+		t.Fatalf("Payer signature should be from %s but is from %s", keys[4].PublicKey().Address(), payer.Address())
+	}
+
+	signers := ids.ShortSet{}
+	for _, sig := range tx.ControlSigs {
+		signer, err := factory.RecoverPublicKey(unsignedBytes, sig[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers.Add(signer.Address())
+	}
+	if signers.Len() != 2 || !signers.Contains(keys[0].PublicKey().Address()) || !signers.Contains(keys[1].PublicKey().Address()) {
+		t.Fatalf("Control signatures should be from keys[0] and keys[1]")
+	}
+
+	// A key can only sign once
+	if err := service.Sign(nil, &SignArgs{
+		Tx: formatting.CB58{Bytes: txBytes},
+		Signers: []ids.ShortID{
+			keys[0].PublicKey().Address(),
+			keys[0].PublicKey().Address(),
+		},
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err == nil {
+		t.Fatalf("Should have failed because a signer was duplicated")
+	}
+}
+
+func TestGetStakingParameters(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetStakingParametersReply{}
+	if err := service.GetStakingParameters(nil, &GetStakingParametersArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case uint64(reply.MinimumStakeAmount) != MinimumStakeAmount:
+		t.Fatalf("expected minimum stake amount %d but got %d", MinimumStakeAmount, reply.MinimumStakeAmount)
+	case uint64(reply.MinimumDelegationAmount) != DefaultMinimumDelegationAmount:
+		t.Fatalf("expected minimum delegation amount %d but got %d", DefaultMinimumDelegationAmount, reply.MinimumDelegationAmount)
+	case uint64(reply.DelegationCapMultiplier) != DefaultDelegationCapMultiplier:
+		t.Fatalf("expected delegation cap multiplier %d but got %d", DefaultDelegationCapMultiplier, reply.DelegationCapMultiplier)
+	case time.Duration(reply.MinimumStakingDuration)*time.Second != MinimumStakingDuration:
+		t.Fatalf("expected minimum staking duration %s but got %ds", MinimumStakingDuration, reply.MinimumStakingDuration)
+	case time.Duration(reply.MaximumStakingDuration)*time.Second != MaximumStakingDuration:
+		t.Fatalf("expected maximum staking duration %s but got %ds", MaximumStakingDuration, reply.MaximumStakingDuration)
+	}
+}
+
+func TestGetRewardRate(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetRewardRateReply{}
+	if err := service.GetRewardRate(nil, &GetRewardRateArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Curve != DefaultRewardCurve.String() || reply.Rate != 0.04 {
+		t.Fatalf("expected the default curve at a rate of 0.04 but got %s at a rate of %g", reply.Curve, reply.Rate)
+	}
+
+	// Once the supply reaches its cap, stakers aren't rewarded
+	supply, err := vm.getTotalSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.rewardCurve = reward.Capped{Curve: DefaultRewardCurve, MaxSupply: supply}
+	if err := service.GetRewardRate(nil, &GetRewardRateArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Rate != 0 {
+		t.Fatalf("expected a rate of 0 but got %g", reply.Rate)
+	}
+}
+
+func TestGetChainTime(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	vm.clock.Set(defaultGenesisTime.Add(time.Second / 2))
+
+	reply := GetChainTimeReply{}
+	if err := service.GetChainTime(nil, &GetChainTimeArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case int64(reply.ChainTime) != defaultGenesisTime.Unix():
+		t.Fatalf("expected chain time %d but got %d", defaultGenesisTime.Unix(), reply.ChainTime)
+	case int64(reply.LocalTime) != defaultGenesisTime.Unix():
+		t.Fatalf("expected local time %d but got %d", defaultGenesisTime.Unix(), reply.LocalTime)
+	case int64(reply.NextChangeTime) != defaultValidateEndTime.Unix():
+		t.Fatalf("expected next change time %d but got %d", defaultValidateEndTime.Unix(), reply.NextChangeTime)
+	case int64(reply.EarliestStartTime) != defaultGenesisTime.Add(DefaultMinStartTimeLead).Add(time.Second).Unix():
+		t.Fatalf("expected earliest start time %d but got %d", defaultGenesisTime.Add(DefaultMinStartTimeLead).Add(time.Second).Unix(), reply.EarliestStartTime)
+	case int64(reply.LatestStartTime) != defaultGenesisTime.Add(DefaultMaxFutureStartTime).Unix():
+		t.Fatalf("expected latest start time %d but got %d", defaultGenesisTime.Add(DefaultMaxFutureStartTime).Unix(), reply.LatestStartTime)
+	}
+}
+
+func TestGetSuggestedStakingTimes(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	vm.clock.Set(defaultGenesisTime)
+
+	reply := GetSuggestedStakingTimesReply{}
+	if err := service.GetSuggestedStakingTimes(nil, &GetSuggestedStakingTimesArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	startTime := defaultGenesisTime.Add(2 * DefaultStartTimeMargin)
+	switch {
+	case int64(reply.StartTime) != startTime.Unix():
+		t.Fatalf("expected start time %d but got %d", startTime.Unix(), reply.StartTime)
+	case int64(reply.MinEndTime) != startTime.Add(MinimumStakingDuration).Unix():
+		t.Fatalf("expected min end time %d but got %d", startTime.Add(MinimumStakingDuration).Unix(), reply.MinEndTime)
+	case int64(reply.MaxEndTime) != startTime.Add(MaximumStakingDuration).Unix():
+		t.Fatalf("expected max end time %d but got %d", startTime.Add(MaximumStakingDuration).Unix(), reply.MaxEndTime)
+	case time.Duration(reply.StartTimeMargin)*time.Second != DefaultStartTimeMargin:
+		t.Fatalf("expected start time margin %s but got %ds", DefaultStartTimeMargin, reply.StartTimeMargin)
+	}
+
+	// The suggested times are accepted by AddDefaultSubnetValidator, but a
+	// start time within the margin isn't
+	args := AddDefaultSubnetValidatorArgs{}
+	args.ID = keys[0].PublicKey().Address()
+	args.Destination = keys[0].PublicKey().Address()
+	args.StartTime = reply.StartTime
+	args.EndTime = reply.MaxEndTime
+	if err := service.AddDefaultSubnetValidator(nil, &args, &AddDefaultSubnetValidatorResponse{}); err != nil {
+		t.Fatal(err)
+	}
+	args.StartTime = reply.StartTime - reply.StartTimeMargin - reply.StartTimeMargin/2
+	args.EndTime = args.StartTime + reply.MinEndTime - reply.StartTime
+	if err := service.AddDefaultSubnetValidator(nil, &args, &AddDefaultSubnetValidatorResponse{}); !errors.Is(err, errStartTimeTooEarly) {
+		t.Fatalf("should have failed with %s but got %v", errStartTimeTooEarly, err)
+	}
+}
+
+func TestGetCurrentValidatorsDelegation(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	nodeID := keys[0].PublicKey().Address()
+	delegatorDestination := keys[1].PublicKey().Address()
+	delegator, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultNonce+1,
+		DefaultMinimumDelegationAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		nodeID,
+		delegatorDestination,
+		testNetworkID,
+		keys[1],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heap.Push(validators, delegator)
+	if err := vm.putCurrentValidators(vm.DB, validators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetCurrentValidatorsReply{}
+	if err := service.GetCurrentValidators(nil, &GetCurrentValidatorsArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Validators) != len(keys)+1 {
+		t.Fatalf("expected %d validators but got %d", len(keys)+1, len(reply.Validators))
+	}
+	for _, vdr := range reply.Validators {
+		switch {
+		case vdr.DelegationFeeRate == nil:
+			// This is the delegator
+			if vdr.RewardOwner == nil || !vdr.RewardOwner.Equals(delegatorDestination) {
+				t.Fatalf("delegator should report reward owner %s", delegatorDestination)
+			}
+			if vdr.DelegatedAmount != nil {
+				t.Fatal("delegator shouldn't report a delegated amount")
+			}
+		case vdr.ID.Equals(nodeID):
+			if uint64(*vdr.DelegatedAmount) != DefaultMinimumDelegationAmount {
+				t.Fatalf("expected delegated amount %d but got %d", DefaultMinimumDelegationAmount, *vdr.DelegatedAmount)
+			}
+			fallthrough
+		default:
+			if uint32(*vdr.DelegationFeeRate) != NumberOfShares {
+				t.Fatalf("expected delegation fee rate %d but got %d", NumberOfShares, *vdr.DelegationFeeRate)
+			}
+			if !vdr.RewardOwner.Equals(vdr.ID) {
+				t.Fatalf("expected reward owner %s but got %s", vdr.ID, vdr.RewardOwner)
+			}
+			if !vdr.ID.Equals(nodeID) && uint64(*vdr.DelegatedAmount) != 0 {
+				t.Fatalf("expected no delegated amount but got %d", *vdr.DelegatedAmount)
+			}
+		}
+	}
+}
+
+func TestGetDelegationCapacity(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	nodeID := keys[0].PublicKey().Address()
+	validators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	earlyEndTime := defaultValidateEndTime.Add(-time.Hour)
+	for i, endTime := range []time.Time{defaultValidateEndTime, earlyEndTime} {
+		delegator, err := vm.newAddDefaultSubnetDelegatorTx(
+			defaultNonce+1,
+			DefaultMinimumDelegationAmount*uint64(i+1),
+			uint64(defaultValidateStartTime.Unix()),
+			uint64(endTime.Unix()),
+			nodeID,
+			keys[1].PublicKey().Address(),
+			testNetworkID,
+			keys[1],
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		heap.Push(validators, delegator)
+	}
+	if err := vm.putCurrentValidators(vm.DB, validators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetDelegationCapacityReply{}
+	if err := service.GetDelegationCapacity(nil, &GetDelegationCapacityArgs{NodeID: nodeID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	maxStake := defaultStakeAmount * DefaultDelegationCapMultiplier
+	delegated := 3 * DefaultMinimumDelegationAmount
+	switch {
+	case uint64(reply.MaxStake) != maxStake:
+		t.Fatalf("expected max stake %d but got %d", maxStake, reply.MaxStake)
+	case uint64(reply.Delegated) != delegated:
+		t.Fatalf("expected delegated stake %d but got %d", delegated, reply.Delegated)
+	case uint64(reply.Capacity) != maxStake-defaultStakeAmount-delegated:
+		t.Fatalf("expected capacity %d but got %d", maxStake-defaultStakeAmount-delegated, reply.Capacity)
+	case int64(reply.EndTime) != defaultValidateEndTime.Unix():
+		t.Fatalf("expected end time %d but got %d", defaultValidateEndTime.Unix(), reply.EndTime)
+	case len(reply.Timeline) != 2:
+		t.Fatalf("expected 2 capacity changes but got %d", len(reply.Timeline))
+	}
+	// The larger delegation ends first
+	expected := []struct{ time, capacity uint64 }{
+		{uint64(earlyEndTime.Unix()), maxStake - defaultStakeAmount - DefaultMinimumDelegationAmount},
+		{uint64(defaultValidateEndTime.Unix()), maxStake - defaultStakeAmount},
+	}
+	for i, change := range reply.Timeline {
+		if uint64(change.Time) != expected[i].time || uint64(change.Capacity) != expected[i].capacity {
+			t.Fatalf("expected capacity %d from %d but got %d from %d", expected[i].capacity, expected[i].time, change.Capacity, change.Time)
+		}
+	}
+
+	if err := service.GetDelegationCapacity(nil, &GetDelegationCapacityArgs{NodeID: ids.NewShortID([20]byte{1})}, &reply); err == nil {
+		t.Fatal("should have failed because the node isn't a validator")
+	}
+}
+
+func TestSignHash(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	db, err := vm.Ctx.Keystore.GetDatabase("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := user{db: db}
+	if err := user.putAccount(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	args := SignHashArgs{
+		Signer:   keys[0].PublicKey().Address(),
+		Username: "bob",
+		Password: "launch",
+	}
+	args.Hash.Bytes = hashing.ComputeHash256([]byte("oracle price update"))
+	reply := SignHashResponse{}
+	if err := service.SignHash(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	signedHash, err := crypto.DomainSeparatedHash(vm.Ctx.ChainID, args.Hash.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := vm.factory.RecoverHashPublicKey(signedHash, reply.Signature.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Address().Equals(keys[0].PublicKey().Address()) {
+		t.Fatalf("signature should have been made by the signer")
+	}
+
+	args.Password = "wrong"
+	if err := service.SignHash(nil, &args, &reply); err == nil {
+		t.Fatal("should have failed because the password is wrong")
+	}
+}
+
+// FuzzGenericTx checks that parsing arbitrary bytes given to platform.sign or
+// platform.issueTx never panics, and neither does verifying the parsed tx.
+// Inputs that fail are saved in testdata/fuzz, so they're rerun by go test.
+func FuzzGenericTx(f *testing.F) {
+	vm := defaultVM()
+
+	validatorTx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		keys[0].PublicKey().Address(),
+		keys[0].PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		f.Fatal(err)
+	}
+	subnetTx, err := vm.newCreateSubnetTx(testNetworkID, defaultNonce+1, []ids.ShortID{keys[0].PublicKey().Address()}, 1, keys[0])
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, tx := range []interface{}{validatorTx, subnetTx} {
+		txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(txBytes)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		genTx := genericTx{}
+		if err := Codec.Unmarshal(b, &genTx); err != nil {
+			return
+		}
+		if _, err := Codec.Marshal(genTx); err != nil {
+			t.Fatalf("couldn't marshal a parsed tx: %s", err)
+		}
+
+		switch tx := genTx.Tx.(type) {
+		case DecisionTx:
+			if err := tx.initialize(vm); err == nil {
+				_ = tx.SyntacticVerify()
+			}
+		case ProposalTx:
+			if err := tx.initialize(vm); err == nil {
+				_ = tx.SyntacticVerify()
+			}
+		}
+	})
+}
+
+func TestImportKey(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	cb58 := func(key *crypto.PrivateKeySECP256K1R) string {
+		return formatting.CB58{Bytes: key.Bytes()}.String()
+	}
+
+	reply := ImportKeyReply{}
+	if err := service.ImportKey(nil, &ImportKeyArgs{
+		Username:   "bob",
+		Password:   "launch",
+		PrivateKey: cb58(keys[0]),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Address.Equals(keys[0].PublicKey().Address()) || len(reply.Existing) != 0 {
+		t.Fatalf("should have imported %s", keys[0].PublicKey().Address())
+	}
+
+	// A key that can't be parsed fails the whole import
+	if err := service.ImportKey(nil, &ImportKeyArgs{
+		Username:    "bob",
+		Password:    "launch",
+		PrivateKeys: []string{cb58(keys[2]), "not a key"},
+	}, &reply); err == nil {
+		t.Fatal("should have failed to parse a key")
+	}
+
+	reply = ImportKeyReply{}
+	if err := service.ImportKey(nil, &ImportKeyArgs{
+		Username:    "bob",
+		Password:    "launch",
+		PrivateKeys: []string{cb58(keys[0]), cb58(keys[1]), cb58(keys[1])},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Addresses) != 2 || !reply.Addresses[0].Equals(keys[0].PublicKey().Address()) || !reply.Addresses[1].Equals(keys[1].PublicKey().Address()) {
+		t.Fatalf("should have imported each key once but got %v", reply.Addresses)
+	}
+	if len(reply.Existing) != 1 || !reply.Existing[0].Equals(keys[0].PublicKey().Address()) {
+		t.Fatalf("should have reported that %s already existed but got %v", keys[0].PublicKey().Address(), reply.Existing)
+	}
+
+	accounts := ListAccountsReply{}
+	if err := service.ListAccounts(nil, &ListAccountsArgs{Username: "bob", Password: "launch"}, &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.Accounts) != 2 {
+		t.Fatalf("bob should control 2 accounts but controls %d", len(accounts.Accounts))
+	}
+}
+
+func TestSampleValidators(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	subnetID := ids.Empty.Prefix(500)
+	vdrs := validators.NewSet()
+	totalWeight := uint64(0)
+	for i := 1; i <= 10; i++ {
+		vdrs.Add(validators.NewValidator(ids.NewShortID([20]byte{byte(i)}), uint64(i)))
+		totalWeight += uint64(i)
+	}
+	vm.Validators.PutValidatorSet(subnetID, vdrs)
+
+	seed := cjson.Uint64(7)
+	args := SampleValidatorsArgs{
+		Size:           3,
+		SubnetID:       subnetID,
+		IncludeWeights: true,
+		Seed:           &seed,
+	}
+	reply := SampleValidatorsReply{}
+	if err := service.SampleValidators(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(reply.Validators) != 3:
+		t.Fatalf("expected 3 validators but got %d", len(reply.Validators))
+	case len(reply.Weights) != 3:
+		t.Fatalf("expected 3 weights but got %d", len(reply.Weights))
+	case reply.TotalWeight == nil || uint64(*reply.TotalWeight) != totalWeight:
+		t.Fatalf("expected a total weight of %d but got %v", totalWeight, reply.TotalWeight)
+	}
+	for i, vdrID := range reply.Validators {
+		if expected := uint64(vdrID.Bytes()[0]); uint64(reply.Weights[i]) != expected {
+			t.Fatalf("expected %s to have weight %d but got %d", vdrID, expected, reply.Weights[i])
+		}
+	}
+
+	// The same seed samples the same validators, whatever order they were
+	// added in
+	reversed := validators.NewSet()
+	for i := 10; i >= 1; i-- {
+		reversed.Add(validators.NewValidator(ids.NewShortID([20]byte{byte(i)}), uint64(i)))
+	}
+	vm.Validators.PutValidatorSet(subnetID, reversed)
+	again := SampleValidatorsReply{}
+	if err := service.SampleValidators(nil, &args, &again); err != nil {
+		t.Fatal(err)
+	}
+	for i, vdrID := range reply.Validators {
+		if !again.Validators[i].Equals(vdrID) {
+			t.Fatalf("expected the seed to sample %s but it sampled %s", reply.Validators, again.Validators)
+		}
+	}
+
+	// Weights are only included if requested
+	args.IncludeWeights = false
+	unweighted := SampleValidatorsReply{}
+	if err := service.SampleValidators(nil, &args, &unweighted); err != nil {
+		t.Fatal(err)
+	}
+	if unweighted.Weights != nil || unweighted.TotalWeight != nil {
+		t.Fatal("shouldn't have included weights")
+	}
+
+	args.Size = 11
+	if err := service.SampleValidators(nil, &args, &SampleValidatorsReply{}); err == nil {
+		t.Fatal("shouldn't sample more validators than the subnet has")
+	}
+}
+
+func TestSend(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	db, err := vm.Ctx.Keystore.GetDatabase("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := user{db: db}
+	if err := user.putAccount(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two transfers sent before either is put into a block spend consecutive
+	// nonces
+	args := SendArgs{
+		Username: "bob",
+		Password: "launch",
+		To:       ids.NewShortID([20]byte{1}),
+		Amount:   cjson.Uint64(defaultBalance / 2),
+	}
+	for i := 0; i < 2; i++ {
+		if err := service.Send(nil, &args, &SendResponse{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(vm.unissuedDecisionTxs) != 2 {
+		t.Fatalf("expected 2 unissued txs but got %d", len(vm.unissuedDecisionTxs))
+	}
+	for i, txIntf := range vm.unissuedDecisionTxs {
+		if tx := txIntf.(*TransferTx); tx.Nonce != defaultNonce+1+uint64(i) {
+			t.Fatalf("expected nonce %d but got %d", defaultNonce+1+uint64(i), tx.Nonce)
+		}
+	}
+
+	// The account can't afford a third transfer
+	if err := service.Send(nil, &args, &SendResponse{}); !errors.Is(err, errInsufficientFunds) {
+		t.Fatalf("should have failed with %s but got %v", errInsufficientFunds, err)
+	}
+}
@@ -0,0 +1,179 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+var (
+	errUnknownTxType   = errors.New("could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createChainTx, createSubnetTx, transferTx, sendMessageTx")
+	errNeedsSubnet     = errors.New("an addNonDefaultSubnetValidatorTx must be signed with SignSubnetValidatorTx")
+	errNotSubnetTx     = errors.New("only an addNonDefaultSubnetValidatorTx may be signed with SignSubnetValidatorTx")
+	errWrongSigLen     = fmt.Errorf("signatures must be %d bytes long", crypto.SECP256K1RSigLen)
+	errSigningFailed   = errors.New("error while signing")
+	errNoPlaceForSig   = errors.New("no place for the key to sign")
+	errMarshalUnsigned = errors.New("error serializing unsigned tx")
+)
+
+// SignTx signs [txBytes], an unsigned transaction of the platform chain, with
+// [keys] and returns the signed transaction's bytes. Every transaction but an
+// AddNonDefaultSubnetValidatorTx is signed by exactly one key, the key of the
+// account that pays for it. An AddNonDefaultSubnetValidatorTx must be signed
+// with SignSubnetValidatorTx.
+func SignTx(txBytes []byte, keys []*crypto.PrivateKeySECP256K1R) ([]byte, error) {
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(txBytes, &genTx); err != nil {
+		return nil, err
+	}
+	if _, ok := genTx.Tx.(*AddNonDefaultSubnetValidatorTx); ok {
+		return nil, errNeedsSubnet
+	}
+	return signTx(genTx, keys, nil, 0)
+}
+
+// SignSubnetValidatorTx signs [txBytes], an unsigned or partially signed
+// AddNonDefaultSubnetValidatorTx, with [keys]. [controlKeys] and [threshold]
+// are those of the subnet the validator is added to, as returned by
+// platform.getSubnets.
+func SignSubnetValidatorTx(txBytes []byte, keys []*crypto.PrivateKeySECP256K1R, controlKeys []ids.ShortID, threshold uint16) ([]byte, error) {
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(txBytes, &genTx); err != nil {
+		return nil, err
+	}
+	if _, ok := genTx.Tx.(*AddNonDefaultSubnetValidatorTx); !ok {
+		return nil, errNotSubnetTx
+	}
+	return signTx(genTx, keys, controlKeys, threshold)
+}
+
+// signTx signs [genTx] with [keys] and returns the signed tx's bytes.
+// [controlKeys] and [threshold] are only used to sign an
+// AddNonDefaultSubnetValidatorTx.
+func signTx(genTx genericTx, keys []*crypto.PrivateKeySECP256K1R, controlKeys []ids.ShortID, threshold uint16) ([]byte, error) {
+	var err error
+	switch tx := genTx.Tx.(type) {
+	case *AddDefaultSubnetValidatorTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedAddDefaultSubnetValidatorTx, keys[0], &tx.Sig)
+	case *AddDefaultSubnetDelegatorTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedAddDefaultSubnetDelegatorTx, keys[0], &tx.Sig)
+	case *AddNonDefaultSubnetValidatorTx:
+		err = signAddNonDefaultSubnetValidatorTx(tx, keys, controlKeys, threshold)
+	case *CreateChainTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedCreateChainTx, keys[0], &tx.Sig)
+	case *CreateSubnetTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedCreateSubnetTx, keys[0], &tx.Sig)
+	case *TransferTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedTransferTx, keys[0], &tx.Sig)
+	case *SendMessageTx:
+		if len(keys) != 1 {
+			return nil, errOneSigner
+		}
+		err = signSingle(&tx.UnsignedSendMessageTx, keys[0], &tx.Sig)
+	default:
+		err = errUnknownTxType
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Codec.Marshal(genTx)
+}
+
+// signSingle signs [unsignedTx] with [key] and writes the signature to [sig]
+func signSingle(unsignedTx interface{}, key *crypto.PrivateKeySECP256K1R, sig *[crypto.SECP256K1RSigLen]byte) error {
+	unsignedTxBytes, err := Codec.Marshal(&unsignedTx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errMarshalUnsigned, err)
+	}
+	return signBytes(unsignedTxBytes, key, sig)
+}
+
+// signBytes signs [unsignedTxBytes] with [key] and writes the signature to
+// [sig]
+func signBytes(unsignedTxBytes []byte, key *crypto.PrivateKeySECP256K1R, sig *[crypto.SECP256K1RSigLen]byte) error {
+	sigBytes, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return errSigningFailed
+	}
+	if len(sigBytes) != crypto.SECP256K1RSigLen {
+		return errWrongSigLen
+	}
+	copy(sig[:], sigBytes)
+	return nil
+}
+
+// Signs an unsigned or partially signed AddNonDefaultSubnetValidatorTx with [keys]
+// Keys that aren't [controlKeys] sign first, so that they sign as payer
+// For each key:
+// If the key is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
+// If the key is a control key for the subnet and there is no empty spot in tx.ControlSigs, signs as payer
+// If the key is not a control key, sign as payer (account controlled by the key pays the tx fee)
+// Sorts tx.ControlSigs before returning
+// Assumes each element of tx.ControlSigs is actually a signature, not just empty bytes
+func signAddNonDefaultSubnetValidatorTx(tx *AddNonDefaultSubnetValidatorTx, keys []*crypto.PrivateKeySECP256K1R, controlKeys []ids.ShortID, threshold uint16) error {
+	// Compute the byte repr. of the unsigned tx
+	unsignedIntf := interface{}(&tx.UnsignedAddNonDefaultSubnetValidatorTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errMarshalUnsigned, err)
+	}
+
+	controlKeySet := ids.ShortSet{}
+	controlKeySet.Add(controlKeys...)
+
+	// Keys that can only sign as payer go first so that a control key doesn't
+	// take the payer's spot
+	sortedKeys := make([]*crypto.PrivateKeySECP256K1R, 0, len(keys))
+	for _, key := range keys {
+		if !controlKeySet.Contains(key.PublicKey().Address()) {
+			sortedKeys = append(sortedKeys, key)
+		}
+	}
+	for _, key := range keys {
+		if controlKeySet.Contains(key.PublicKey().Address()) {
+			sortedKeys = append(sortedKeys, key)
+		}
+	}
+
+	for _, key := range sortedKeys {
+		sig := [crypto.SECP256K1RSigLen]byte{}
+		if err := signBytes(unsignedTxBytes, key, &sig); err != nil {
+			return err
+		}
+
+		// Find the location at which [key] should put its signature.
+		isControlKey := controlKeySet.Contains(key.PublicKey().Address())
+		payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
+
+		if isControlKey && len(tx.ControlSigs) != int(threshold) { // Sign as controlSig
+			tx.ControlSigs = append(tx.ControlSigs, sig)
+		} else if payerSigEmpty { // sign as payer
+			tx.PayerSig = sig
+		} else {
+			return fmt.Errorf("%w: %s", errNoPlaceForSig, key.PublicKey().Address())
+		}
+	}
+
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
+	return nil
+}
@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func TestSignTx(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	txBytes, err := marshalUnsignedTx(&AddDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
+		DurationValidator: DurationValidator{
+			Validator: Validator{
+				NodeID: keys[0].PublicKey().Address(),
+				Wght:   MinimumStakeAmount,
+			},
+			Start: uint64(defaultValidateStartTime.Unix()),
+			End:   uint64(defaultValidateEndTime.Unix()),
+		},
+		Nonce:       defaultNonce + 1,
+		Destination: keys[0].PublicKey().Address(),
+		NetworkID:   testNetworkID,
+		Shares:      NumberOfShares,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignSubnetValidatorTx(txBytes, keys[:1], nil, 0); err != errNotSubnetTx {
+		t.Fatalf("expected %s but got %v", errNotSubnetTx, err)
+	}
+	if _, err := SignTx(txBytes, keys[:2]); err != errOneSigner {
+		t.Fatalf("expected %s but got %v", errOneSigner, err)
+	}
+	signedBytes, err := SignTx(txBytes, keys[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tx signed offline is the tx the chain would parse
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(signedBytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := genTx.Tx.(*AddDefaultSubnetValidatorTx)
+	if !ok {
+		t.Fatalf("built the wrong type of tx")
+	}
+	if err := tx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+	if !tx.senderID.Equals(keys[0].PublicKey().Address()) {
+		t.Fatalf("expected the tx to be signed by %s but it was signed by %s", keys[0].PublicKey().Address(), tx.senderID)
+	}
+
+}
+
+func TestSignSubnetValidatorTx(t *testing.T) {
+	vm := defaultVM() // Creates testSubnet1
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	txBytes, err := marshalUnsignedTx(&AddNonDefaultSubnetValidatorTx{UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
+		SubnetValidator: SubnetValidator{
+			DurationValidator: DurationValidator{
+				Validator: Validator{
+					NodeID: keys[0].PublicKey().Address(),
+					Wght:   defaultWeight,
+				},
+				Start: uint64(defaultValidateStartTime.Unix()) + 1,
+				End:   uint64(defaultValidateEndTime.Unix()),
+			},
+			Subnet: testSubnet1.ID,
+		},
+		NetworkID: testNetworkID,
+		Nonce:     defaultNonce + 1,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignTx(txBytes, keys[:1]); err != errNeedsSubnet {
+		t.Fatalf("expected %s but got %v", errNeedsSubnet, err)
+	}
+
+	// keys[0] and keys[1] control testSubnet1 and keys[4] pays the fee
+	signedBytes, err := SignSubnetValidatorTx(
+		txBytes,
+		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[4], keys[1]},
+		testSubnet1.ControlKeys,
+		testSubnet1.Threshold,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(signedBytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := genTx.Tx.(*AddNonDefaultSubnetValidatorTx)
+	if !ok {
+		t.Fatalf("built the wrong type of tx")
+	}
+	if len(tx.ControlSigs) != 2 {
+		t.Fatalf("expected 2 control signatures but got %d", len(tx.ControlSigs))
+	}
+	if tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} {
+		t.Fatalf("the payer should have signed")
+	}
+}
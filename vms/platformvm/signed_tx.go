@@ -0,0 +1,21 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+// signedTx is a tx whose signatures can be checked without reading the chain's
+// state or modifying the tx, so they can be checked concurrently with the
+// verification of other blocks. The keys recovered from the signatures are
+// cached by the VM's factory, so they aren't recovered again when the tx is
+// verified.
+type signedTx interface {
+	verifySignatures() error
+}
+
+// verifySignatures checks the signatures of [tx], if it's signed
+func verifySignatures(tx interface{}) error {
+	if tx, ok := tx.(signedTx); ok {
+		return tx.verifySignatures()
+	}
+	return nil
+}
@@ -4,28 +4,80 @@
 package platformvm
 
 import (
+	"bytes"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/core"
 )
 
+const (
+	// standardBlockHeaderLen is the number of bytes of a standard block, other
+	// than its transactions: its type ID, parent ID, number of transactions
+	// and account root
+	standardBlockHeaderLen = wrappers.IntLen + hashing.HashLen + wrappers.IntLen + hashing.HashLen
+
+	// standardBlockTxOverhead is the number of bytes a standard block spends on
+	// each of its transactions, in addition to the transaction's bytes: its
+	// type ID
+	standardBlockTxOverhead = wrappers.IntLen
+)
+
 // DecisionTx is an operation that can be decided without being proposed
 type DecisionTx interface {
 	initialize(vm *VM) error
 
+	// Bytes returns the byte representation of this transaction
+	Bytes() []byte
+
+	// Verify this transaction is well-formed.
+	SyntacticVerify() error
+
 	// Attempt to verify this transaction with the provided state. The provided
 	// database can be modified arbitrarily. If a nil error is returned, it is
 	// assumped onAccept is non-nil.
 	SemanticVerify(database.Database) (onAccept func(), err error)
 }
 
+// We use this type so we can serialize a list of DecisionTx
+// by defining a Bytes method on it
+type decisionTxList []DecisionTx
+
+// Bytes returns the byte representation of a list of DecisionTx
+func (txs decisionTxList) Bytes() []byte {
+	bytes, _ := Codec.Marshal(txs)
+	return bytes
+}
+
+// Len implements the sort.Interface interface
+func (txs decisionTxList) Len() int { return len(txs) }
+
+// Less implements the sort.Interface interface. Transactions are ordered by
+// their byte representation so that the order is deterministic.
+func (txs decisionTxList) Less(i, j int) bool {
+	return bytes.Compare(txs[i].Bytes(), txs[j].Bytes()) == -1
+}
+
+// Swap implements the sort.Interface interface
+func (txs decisionTxList) Swap(i, j int) { txs[i], txs[j] = txs[j], txs[i] }
+
 // StandardBlock being accepted results in the transactions contained in the
 // block to be accepted and committed to the chain.
+//
+// A standard block is decided by a single vote, rather than being proposed and
+// then committed or aborted. The chain is linear, so a standard block built
+// while a proposal is processing is built on that proposal's preferred option,
+// and isn't accepted until the proposal is.
 type StandardBlock struct {
 	CommonDecisionBlock `serialize:"true"`
 
 	Txs []DecisionTx `serialize:"true"`
+
+	// AccountRoot is the root of the account trie once this block is accepted
+	AccountRoot ids.ID `serialize:"true"`
 }
 
 // initialize this block
@@ -33,76 +85,111 @@ func (sb *StandardBlock) initialize(vm *VM, bytes []byte) error {
 	if err := sb.CommonDecisionBlock.initialize(vm, bytes); err != nil {
 		return err
 	}
-	for _, tx := range sb.Txs {
-		if err := tx.initialize(vm); err != nil {
-			return err
-		}
-	}
-	return nil
+	return initializeDecisionTxs(vm, sb.Txs)
 }
 
+// accountRoot implements the rootedBlock interface
+func (sb *StandardBlock) accountRoot() ids.ID { return sb.AccountRoot }
+
+// VerifyStateless verifies the signatures of this block's transactions.
+//
+// The consensus engine may call this concurrently with the verification of
+// other blocks, so it only reads the block's transactions.
+func (sb *StandardBlock) VerifyStateless() error { return verifyDecisionTxSignatures(sb.Txs) }
+
 // Verify this block performs a valid state transition.
 //
 // The parent block must be a proposal
 //
 // This function also sets onAcceptDB database if the verification passes.
-func (sb *StandardBlock) Verify() error {
+func (sb *StandardBlock) Verify() error { return sb.verifyStandard(sb, sb.Txs, &sb.AccountRoot) }
+
+// initialize [txs], the transactions of a standard block
+func initializeDecisionTxs(vm *VM, txs []DecisionTx) error {
+	for _, tx := range txs {
+		if err := tx.initialize(vm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify the signatures of [txs], the transactions of a standard block
+func verifyDecisionTxSignatures(txs []DecisionTx) error {
+	for _, tx := range txs {
+		if err := verifySignatures(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyStandard verifies [blk], a standard block with the transactions [txs]
+// that commits to the account root [root], or to none if [root] is nil
+func (cdb *CommonDecisionBlock) verifyStandard(blk Block, txs []DecisionTx, root *ids.ID) error {
 	// StandardBlock is not a modifier on a proposal block, so its parent must
 	// be a decision.
-	parent, ok := sb.parentBlock().(decision)
+	parent, ok := cdb.parentBlock().(decision)
 	if !ok {
 		return errInvalidBlockType
 	}
 
 	pdb := parent.onAccept()
 
-	sb.onAcceptDB = versiondb.New(pdb)
+	cdb.onAcceptDB = versiondb.New(pdb)
+	cdb.messages = nil
 	funcs := []func(){}
-	for _, tx := range sb.Txs {
-		onAccept, err := tx.SemanticVerify(sb.onAcceptDB)
+	for _, tx := range txs {
+		onAccept, err := tx.SemanticVerify(cdb.onAcceptDB)
 		if err != nil {
 			return err
 		}
-		if onAccept != nil {
-			funcs = append(funcs, onAccept)
+		funcs = append(funcs, cdb.vm.watchOnAccept(tx, onAccept))
+		if msgTx, ok := tx.(*SendMessageTx); ok {
+			cdb.messages = append(cdb.messages, msgTx.message())
 		}
 	}
+	if err := cdb.vm.verifyAccountRoot(cdb.onAcceptDB, root); err != nil {
+		return err
+	}
 
 	if numFuncs := len(funcs); numFuncs == 1 {
-		sb.onAcceptFunc = funcs[0]
+		cdb.onAcceptFunc = funcs[0]
 	} else if numFuncs > 1 {
-		sb.onAcceptFunc = func() {
+		cdb.onAcceptFunc = func() {
 			for _, f := range funcs {
 				f()
 			}
 		}
 	}
 
-	sb.vm.currentBlocks[sb.ID().Key()] = sb
-	sb.parentBlock().addChild(sb)
+	cdb.vm.currentBlocks[blk.ID().Key()] = blk
+	cdb.parentBlock().addChild(blk)
 	return nil
 }
 
-// newStandardBlock returns a new *StandardBlock where the block's parent, a
-// decision block, has ID [parentID].
-func (vm *VM) newStandardBlock(parentID ids.ID, txs []DecisionTx) (*StandardBlock, error) {
-	sb := &StandardBlock{
-		CommonDecisionBlock: CommonDecisionBlock{
-			CommonBlock: CommonBlock{
-				Block: core.NewBlock(parentID),
-				vm:    vm,
-			},
+// newStandardBlock returns a new standard block where the block's parent, a
+// decision block, has ID [parentID]. The block commits to the account root
+// [root], or is a *legacyStandardBlock if [root] is nil.
+func (vm *VM) newStandardBlock(parentID ids.ID, txs []DecisionTx, root *ids.ID) (Block, error) {
+	common := CommonDecisionBlock{
+		CommonBlock: CommonBlock{
+			Block: core.NewBlock(parentID),
+			vm:    vm,
 		},
-		Txs: txs,
+	}
+	var blk Block
+	if root == nil {
+		blk = &legacyStandardBlock{CommonDecisionBlock: common, Txs: txs}
+	} else {
+		blk = &StandardBlock{CommonDecisionBlock: common, Txs: txs, AccountRoot: *root}
 	}
 
 	// We serialize this block as a Block so that it can be deserialized into a
 	// Block
-	blk := Block(sb)
 	bytes, err := Codec.Marshal(&blk)
 	if err != nil {
 		return nil, err
 	}
-	sb.Block.Initialize(bytes, vm.SnowmanVM)
-	return sb, nil
+	return blk, blk.initialize(vm, bytes)
 }
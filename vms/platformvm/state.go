@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
 	"time"
@@ -11,6 +12,8 @@ import (
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/merkle"
 )
 
 // This file contains methods of VM that deal with getting/putting values from database
@@ -143,9 +146,42 @@ func (vm *VM) putAccount(db database.Database, account Account) error {
 	if err != nil {
 		return errDBPutAccount
 	}
+	if err := merkle.Put(accountTrie(db), account.Address, account.Bytes()); err != nil {
+		return errDBPutAccount
+	}
+	return nil
+}
+
+// get the amount of $AVA that has been minted and not burned
+func (vm *VM) getTotalSupply(db database.Database) (uint64, error) {
+	totalSupply, err := vm.State.GetUint64(db, totalSupplyKey)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get total supply: %w", err)
+	}
+	return totalSupply, nil
+}
+
+// put the amount of $AVA that has been minted and not burned in [db]
+func (vm *VM) putTotalSupply(db database.Database, totalSupply uint64) error {
+	if err := vm.State.PutUint64(db, totalSupplyKey, totalSupply); err != nil {
+		return fmt.Errorf("couldn't put total supply: %w", err)
+	}
 	return nil
 }
 
+// increase the total supply in [db] by the newly minted [amount]
+func (vm *VM) mintSupply(db database.Database, amount uint64) error {
+	totalSupply, err := vm.getTotalSupply(db)
+	if err != nil {
+		return err
+	}
+	newTotalSupply, err := math.Add64(totalSupply, amount)
+	if err != nil {
+		return err
+	}
+	return vm.putTotalSupply(db, newTotalSupply)
+}
+
 // get the blockchains that exist
 func (vm *VM) getChains(db database.Database) ([]*CreateChainTx, error) {
 	chainsInterface, err := vm.State.Get(db, chainsTypeID, chainsKey)
@@ -225,6 +261,73 @@ func (vm *VM) getSubnet(db database.Database, ID ids.ID) (*CreateSubnetTx, error
 	return nil, fmt.Errorf("couldn't find subnet with ID %s", ID)
 }
 
+// get the events that were issued to this node but haven't been put into a
+// block yet
+func (vm *VM) getUnissuedEvents(db database.Database) (*EventHeap, error) {
+	has, err := vm.State.Has(db, validatorsTypeID, unissuedEventsKey)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return &EventHeap{SortByStartTime: true}, nil
+	}
+	eventsInterface, err := vm.State.Get(db, validatorsTypeID, unissuedEventsKey)
+	if err != nil {
+		return nil, err
+	}
+	events, ok := eventsInterface.(*EventHeap)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve *EventHeap from database but got different type")
+		return nil, errDBUnissuedTxs
+	}
+	heap.Init(events)
+	return events, nil
+}
+
+// put the events that haven't been put into a block yet in [db]
+func (vm *VM) putUnissuedEvents(db database.Database, events *EventHeap) error {
+	if events.Len() == 0 {
+		return vm.State.Put(db, validatorsTypeID, unissuedEventsKey, nil)
+	}
+	if err := vm.State.Put(db, validatorsTypeID, unissuedEventsKey, events); err != nil {
+		return errDBPutUnissuedTxs
+	}
+	return nil
+}
+
+// get the decision transactions that were issued to this node but haven't
+// been put into a block yet
+func (vm *VM) getUnissuedDecisionTxs(db database.Database) ([]DecisionTx, error) {
+	has, err := vm.State.Has(db, unissuedDecisionTxsTypeID, unissuedDecisionTxsKey)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	txsInterface, err := vm.State.Get(db, unissuedDecisionTxsTypeID, unissuedDecisionTxsKey)
+	if err != nil {
+		return nil, err
+	}
+	txs, ok := txsInterface.([]DecisionTx)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve []DecisionTx from database but got different type")
+		return nil, errDBUnissuedTxs
+	}
+	return txs, nil
+}
+
+// put the decision transactions that haven't been put into a block yet in [db]
+func (vm *VM) putUnissuedDecisionTxs(db database.Database, txs decisionTxList) error {
+	if len(txs) == 0 {
+		return vm.State.Put(db, unissuedDecisionTxsTypeID, unissuedDecisionTxsKey, nil)
+	}
+	if err := vm.State.Put(db, unissuedDecisionTxsTypeID, unissuedDecisionTxsKey, txs); err != nil {
+		return errDBPutUnissuedTxs
+	}
+	return nil
+}
+
 // register each type that we'll be storing in the database
 // so that [vm.State] knows how to unmarshal these types from bytes
 func (vm *VM) registerDBTypes() {
@@ -286,6 +389,34 @@ func (vm *VM) registerDBTypes() {
 	if err := vm.State.RegisterType(subnetsTypeID, unmarshalSubnetsFunc); err != nil {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
+
+	unmarshalDecisionTxsFunc := func(bytes []byte) (interface{}, error) {
+		var txs []DecisionTx
+		if err := Codec.Unmarshal(bytes, &txs); err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			if err := tx.initialize(vm); err != nil {
+				return nil, err
+			}
+		}
+		return txs, nil
+	}
+	if err := vm.State.RegisterType(unissuedDecisionTxsTypeID, unmarshalDecisionTxsFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
+	unmarshalValidatorSetFunc := func(bytes []byte) (interface{}, error) {
+		validatorSet := &ValidatorSet{}
+		if err := Codec.Unmarshal(bytes, validatorSet); err != nil {
+			return nil, err
+		}
+		return validatorSet, nil
+	}
+	if err := vm.State.RegisterType(validatorSetTypeID, unmarshalValidatorSetFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
 }
 
 // Unmarshal a Block from bytes and initialize it
@@ -47,6 +47,11 @@ type APIValidator struct {
 	Weight      *json.Uint64 `json:"weight,omitempty"`
 	StakeAmount *json.Uint64 `json:"stakeAmount,omitempty"`
 	ID          ids.ShortID  `json:"id"`
+
+	// Only reported for validators of the default subnet
+	DelegatedAmount   *json.Uint64 `json:"delegatedAmount,omitempty"`
+	DelegationFeeRate *json.Uint32 `json:"delegationFeeRate,omitempty"`
+	RewardOwner       *ids.ShortID `json:"rewardOwner,omitempty"`
 }
 
 func (v *APIValidator) weight() uint64 {
@@ -151,7 +156,7 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 			return errValidatorAddsNoValue
 		}
 
-		tx := &addDefaultSubnetValidatorTx{
+		tx := &AddDefaultSubnetValidatorTx{
 			UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
 				DurationValidator: DurationValidator{
 					Validator: Validator{
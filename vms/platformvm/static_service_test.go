@@ -213,3 +213,8 @@ func TestBuildGenesisInvalidEndtime(t *testing.T) {
 		t.Fatalf("Should have errored due to an invalid end time")
 	}
 }
+
+func TestServiceArgsRoundTrip(t *testing.T) {
+	json.TestArgsRoundTrip(t, &Service{})
+	json.TestArgsRoundTrip(t, &StaticService{})
+}
@@ -0,0 +1,191 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+var (
+	errNoTransferAmount      = errors.New("transfer must send a positive amount")
+	errTransfersNotActivated = errors.New("transfers aren't activated yet")
+	errInsufficientFunds     = errors.New("insufficient funds")
+)
+
+// UnsignedTransferTx is an unsigned transfer of $AVA between two accounts
+type UnsignedTransferTx struct {
+	// The VM this tx exists within
+	vm *VM
+
+	// ID is this transaction's ID
+	ID ids.ID
+
+	// NetworkID is the ID of the network this tx was issued on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account the $AVA is sent from
+	Nonce uint64 `serialize:"true"`
+
+	// Address of the account the $AVA is sent to
+	To ids.ShortID `serialize:"true"`
+
+	// Amount of $AVA sent
+	Amount uint64 `serialize:"true"`
+}
+
+// TransferTx is a transfer of $AVA between two accounts
+type TransferTx struct {
+	UnsignedTransferTx `serialize:"true"`
+
+	// The public key that signed this transaction
+	// The $AVA, and the transaction fee, are paid from the corresponding
+	// account (ie the account whose ID is [key].Address())
+	// [key] is non-nil iff this tx is valid
+	key crypto.PublicKey
+
+	// Signature on the UnsignedTransferTx's byte repr
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// Byte representation of this transaction (including signature)
+	bytes []byte
+}
+
+// verifySignatures implements the signedTx interface
+func (tx *TransferTx) verifySignatures() error {
+	if tx == nil {
+		return errNilTx
+	}
+	unsignedIntf := interface{}(&tx.UnsignedTransferTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	_, err = tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	return err
+}
+
+// SyntacticVerify nil iff [tx] is syntactically valid.
+// If [tx] is valid, this method sets [tx.key]
+func (tx *TransferTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.ID.IsZero():
+		return errInvalidID
+	case tx.NetworkID != tx.vm.Ctx.NetworkID:
+		return errWrongNetworkID
+	case tx.To.IsZero():
+		return errEmptyAccountAddress
+	case tx.Amount == 0:
+		return errNoTransferAmount
+	}
+
+	// Byte representation of the unsigned transaction
+	unsignedIntf := interface{}(&tx.UnsignedTransferTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+
+	// Recover signature from byte repr. of unsigned tx
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:]) // the public key that signed [tx]
+	if err != nil {
+		return err
+	}
+
+	tx.key = key
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *TransferTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	// Blocks accepted before transfers were activated never contain one
+	chainTime, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	if !active(tx.vm.Upgrades.TransferTime, chainTime) {
+		return nil, errTransfersNotActivated
+	}
+
+	// Deduct the amount, and the tx fee, from the sender's account
+	sender, err := tx.vm.getAccount(db, tx.key.Address())
+	if err != nil {
+		return nil, err
+	}
+	sender, err = sender.Remove(tx.Amount, tx.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send %d: %w", tx.Amount, err)
+	}
+	if err := tx.vm.putAccount(db, sender); err != nil {
+		return nil, err
+	}
+
+	// The recipient is read after the sender is written, in case they're the
+	// same account
+	recipient, err := tx.vm.getAccount(db, tx.To)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err = recipient.Add(tx.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, recipient); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Bytes returns the byte representation of [tx]
+func (tx *TransferTx) Bytes() []byte {
+	if tx.bytes != nil {
+		return tx.bytes
+	}
+	var err error
+	tx.bytes, err = Codec.Marshal(tx)
+	if err != nil {
+		tx.vm.Ctx.Log.Error("problem marshaling tx: %v", err)
+	}
+	return tx.bytes
+}
+
+// initialize sets [tx.vm] to [vm]
+func (tx *TransferTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	if err != nil {
+		return err
+	}
+	tx.bytes = txBytes
+	tx.ID = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return nil
+}
+
+func (vm *VM) newTransferTx(nonce uint64, to ids.ShortID, amount uint64, key *crypto.PrivateKeySECP256K1R) (*TransferTx, error) {
+	tx := &TransferTx{UnsignedTransferTx: UnsignedTransferTx{
+		vm:        vm,
+		NetworkID: vm.Ctx.NetworkID,
+		Nonce:     nonce,
+		To:        to,
+		Amount:    amount,
+	}}
+	if err := signSingle(&tx.UnsignedTransferTx, key, &tx.Sig); err != nil {
+		return nil, err
+	}
+	return tx, tx.initialize(vm)
+}
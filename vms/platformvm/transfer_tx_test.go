@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestTransferTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	to := ids.NewShortID([20]byte{1})
+
+	tx, err := vm.newTransferTx(defaultNonce+1, to, defaultBalance/4, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	sender, err := vm.getAccount(db, keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - txFee - defaultBalance/4; sender.Balance != expected {
+		t.Fatalf("expected the sender's balance to be %d but was %d", expected, sender.Balance)
+	}
+	if sender.Nonce != defaultNonce+1 {
+		t.Fatalf("expected the sender's nonce to be %d but was %d", defaultNonce+1, sender.Nonce)
+	}
+	recipient, err := vm.getAccount(db, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipient.Balance != defaultBalance/4 {
+		t.Fatalf("expected the recipient's balance to be %d but was %d", defaultBalance/4, recipient.Balance)
+	}
+
+	// The nonce was spent
+	if _, err := tx.SemanticVerify(versiondb.New(db)); err == nil {
+		t.Fatal("should have failed because the nonce was already spent")
+	}
+
+	// The sender can't afford the transfer
+	tx, err = vm.newTransferTx(defaultNonce+1, to, defaultBalance+1, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the sender can't afford the transfer")
+	}
+
+	// Transfers aren't valid before they're activated
+	vm.Upgrades.TransferTime = defaultGenesisTime.Add(time.Second)
+	tx, err = vm.newTransferTx(defaultNonce+1, to, defaultBalance/4, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != errTransfersNotActivated {
+		t.Fatalf("should have failed with %s but got %v", errTransfersNotActivated, err)
+	}
+}
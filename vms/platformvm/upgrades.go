@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"time"
+)
+
+// Upgrades are changes to the rules of the platform chain. Each takes effect
+// once the chain time reaches its activation time, so the blocks accepted
+// before it still verify when a node bootstraps. They must be the same on
+// every node of the network, so they're set per network rather than by each
+// node. A zero activation time means the change is in effect from genesis.
+type Upgrades struct {
+	// DelegationLimitsTime is when delegators must start delegating at least
+	// MinimumDelegationAmount, and when the stake delegated to a validator
+	// starts being bounded by DelegationCapMultiplier
+	DelegationLimitsTime time.Time
+
+	// MinimumDelegationAmount is the minimum amount of $AVA one must delegate
+	// to a validator. If it is 0, DefaultMinimumDelegationAmount is used.
+	MinimumDelegationAmount uint64
+
+	// DelegationCapMultiplier bounds a validator's own stake plus the most
+	// stake delegated to it at any one time to this many times its own stake.
+	// If it is 0, DefaultDelegationCapMultiplier is used.
+	DelegationCapMultiplier uint64
+
+	// StartTimeBoundTime is when a staker's start time starts being bounded
+	// to at most MaxFutureStartTime after the chain time
+	StartTimeBoundTime time.Time
+
+	// MaxFutureStartTime is how long after the chain time a staker may start.
+	// If it is 0, DefaultMaxFutureStartTime is used.
+	MaxFutureStartTime time.Duration
+
+	// CreationFeeTime is when creating subnets and chains starts burning
+	// CreationFees
+	CreationFeeTime time.Time
+
+	// CreationFees are burned to create subnets and chains. If they're zero,
+	// creating subnets and chains is free.
+	CreationFees CreationFees
+
+	// TransferTime is when $AVA starts being transferable between accounts
+	TransferTime time.Time
+
+	// MessageTime is when messages start being sendable from the platform
+	// chain to the other chains
+	MessageTime time.Time
+
+	// AccountRootTime is when decision blocks start committing to the root of
+	// the account trie in the state they result in
+	AccountRootTime time.Time
+}
+
+// active returns true if a change that activates at [activationTime] is in
+// effect at chain time [chainTime]
+func active(activationTime, chainTime time.Time) bool {
+	return !chainTime.Before(activationTime)
+}
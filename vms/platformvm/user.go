@@ -64,39 +64,50 @@ func (u *user) controlsAccount(ID ids.ShortID) (bool, error) {
 // putAccount persists that this user controls the account whose ID is
 // [privKey].PublicKey().Address()
 func (u *user) putAccount(privKey *crypto.PrivateKeySECP256K1R) error {
-	newAccountID := privKey.PublicKey().Address() // Account thie privKey controls
-	controlsAccount, err := u.controlsAccount(newAccountID)
-	if err != nil {
-		return err
-	}
-	if controlsAccount { // user already controls this account. Do nothing.
-		return nil
-	}
+	_, err := u.putAccounts([]*crypto.PrivateKeySECP256K1R{privKey})
+	return err
+}
 
-	err = u.db.Put(newAccountID.Bytes(), privKey.Bytes()) // Account ID --> private key
+// putAccounts persists that this user controls the accounts whose IDs are
+// the addresses of [privKeys]. Returns the IDs of the accounts the user
+// already controlled.
+func (u *user) putAccounts(privKeys []*crypto.PrivateKeySECP256K1R) ([]ids.ShortID, error) {
+	// Accounts this user already controls
+	accountIDs, err := u.getAccountIDs()
 	if err != nil {
-		return errDB
+		return nil, errDB
 	}
 
-	accountIDs := make([]ids.ShortID, 0) // Add account to list of accounts user controls
-	userHasAccounts, err := u.db.Has(accountIDsKey)
-	if err != nil {
-		return errDB
-	}
-	if userHasAccounts { // Get accountIDs this user already controls, if they exist
-		if accountIDs, err = u.getAccountIDs(); err != nil {
-			return errDB
+	existing := []ids.ShortID(nil)
+	for _, privKey := range privKeys {
+		newAccountID := privKey.PublicKey().Address() // Account this privKey controls
+		controlsAccount, err := u.controlsAccount(newAccountID)
+		if err != nil {
+			return nil, err
 		}
+		if controlsAccount { // user already controls this account. Do nothing.
+			existing = append(existing, newAccountID)
+			continue
+		}
+
+		err = u.db.Put(newAccountID.Bytes(), privKey.Bytes()) // Account ID --> private key
+		if err != nil {
+			return nil, errDB
+		}
+		accountIDs = append(accountIDs, newAccountID)
+	}
+	if len(existing) == len(privKeys) {
+		return existing, nil
 	}
-	accountIDs = append(accountIDs, newAccountID)
+
 	bytes, err := Codec.Marshal(accountIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := u.db.Put(accountIDsKey, bytes); err != nil {
-		return errDB
+		return nil, errDB
 	}
-	return nil
+	return existing, nil
 }
 
 // Key returns the private key that controls the account with the specified ID
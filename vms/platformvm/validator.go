@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
@@ -55,6 +56,29 @@ func (v *DurationValidator) BoundedBy(startTime, endTime time.Time) bool {
 	return !v.StartTime().Before(startTime) && !v.EndTime().After(endTime)
 }
 
+// VerifyStakingDuration returns an error if this staker stakes for less than
+// MinimumStakingDuration or more than MaximumStakingDuration
+func (v *DurationValidator) VerifyStakingDuration() error {
+	switch duration := v.Duration(); {
+	case duration < MinimumStakingDuration:
+		return fmt.Errorf("%w: duration %s is below min %s",
+			errStakeTooShort, formatStakingDuration(duration), formatStakingDuration(MinimumStakingDuration))
+	case duration > MaximumStakingDuration:
+		return fmt.Errorf("%w: duration %s exceeds max %s",
+			errStakeTooLong, formatStakingDuration(duration), formatStakingDuration(MaximumStakingDuration))
+	}
+	return nil
+}
+
+// formatStakingDuration formats [d] in days if it is a whole number of days
+func formatStakingDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
+
 // SubnetValidator validates a blockchain on the AVA network.
 type SubnetValidator struct {
 	DurationValidator `serialize:"true"`
@@ -0,0 +1,225 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+// The validator events are stored under their own prefix of the chain's
+// database. They aren't part of the chain's state, so they're written outside
+// of [vm.DB].
+var validatorEventPrefix = []byte("validator events")
+
+// ValidatorEventKind is a transition in the lifecycle of a validator or
+// delegator
+type ValidatorEventKind uint8
+
+// The transitions in the lifecycle of a validator or delegator
+const (
+	// ValidatorAdded means the staker was added to the pending stakers
+	ValidatorAdded ValidatorEventKind = iota
+	// ValidatorStarted means the staker started staking
+	ValidatorStarted
+	// ValidatorEnded means the staker was removed from the current stakers
+	// without being rewarded
+	ValidatorEnded
+	// ValidatorRewarded means the staker was removed from the current stakers
+	// and its stake was returned with a reward
+	ValidatorRewarded
+)
+
+func (k ValidatorEventKind) String() string {
+	switch k {
+	case ValidatorAdded:
+		return "added"
+	case ValidatorStarted:
+		return "started"
+	case ValidatorEnded:
+		return "ended"
+	case ValidatorRewarded:
+		return "rewarded"
+	default:
+		return "unknown"
+	}
+}
+
+// validatorEvent is a transition in the lifecycle of the staker added by the
+// tx with ID [TxID]
+type validatorEvent struct {
+	Kind      ValidatorEventKind `serialize:"true"`
+	NodeID    ids.ShortID        `serialize:"true"`
+	SubnetID  ids.ID             `serialize:"true"`
+	TxID      ids.ID             `serialize:"true"`
+	Delegator bool               `serialize:"true"`
+
+	// The chain time once the transition happened
+	Time uint64 `serialize:"true"`
+
+	// The proposal block that made the transition, and the height of the
+	// decision block that enacted it
+	BlockID ids.ID `serialize:"true"`
+	Height  uint64 `serialize:"true"`
+}
+
+// validatorEventLog is the history of the transitions in the lifecycle of the
+// stakers of every subnet, since this node started recording it. It isn't part
+// of the chain's state.
+type validatorEventLog struct{ db database.Database }
+
+// initValidatorEvents loads the validator events stored in [db]
+func (vm *VM) initValidatorEvents(db database.Database) {
+	vm.validatorEvents = validatorEventLog{db: prefixdb.New(validatorEventPrefix, db)}
+}
+
+// put [event] in the log. Events are keyed by node ID, then height, so the
+// events of a node are listed in the order they happened.
+func (l *validatorEventLog) put(event *validatorEvent) error {
+	key := make([]byte, 0, len(event.NodeID.Bytes())+8+len(event.TxID.Bytes())+1)
+	key = append(key, event.NodeID.Bytes()...)
+	key = append(key, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], event.Height)
+	key = append(key, event.TxID.Bytes()...)
+	key = append(key, byte(event.Kind))
+
+	eventBytes, err := Codec.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return l.db.Put(key, eventBytes)
+}
+
+// list the events of the node with ID [nodeID], in the order they happened
+func (l *validatorEventLog) list(nodeID ids.ShortID) ([]*validatorEvent, error) {
+	events := []*validatorEvent{}
+
+	iter := l.db.NewIteratorWithPrefix(nodeID.Bytes())
+	defer iter.Release()
+	for iter.Next() {
+		event := &validatorEvent{}
+		if err := Codec.Unmarshal(iter.Value(), event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, iter.Error()
+}
+
+// validatorChanges returns the events of the stakers whose status changes
+// between [before] and [after], the states of the chain before and after the
+// proposal [tx] of the block with ID [blockID] is decided. [committed] is true
+// if the proposal is committed. The heights of the events are set once the
+// proposal is decided.
+func (vm *VM) validatorChanges(before, after database.Database, tx ProposalTx, blockID ids.ID, committed bool) ([]*validatorEvent, error) {
+	timestamp, err := vm.getTimestamp(after)
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := vm.getSubnets(after)
+	if err != nil {
+		return nil, err
+	}
+	subnetIDs := []ids.ID{DefaultSubnetID}
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+
+	events := []*validatorEvent(nil)
+	newEvent := func(kind ValidatorEventKind, subnetID ids.ID, staker TimedTx) {
+		_, delegator := staker.(*AddDefaultSubnetDelegatorTx)
+		events = append(events, &validatorEvent{
+			Kind:      kind,
+			NodeID:    staker.Vdr().ID(),
+			SubnetID:  subnetID,
+			TxID:      staker.ID(),
+			Delegator: delegator,
+			Time:      uint64(timestamp.Unix()),
+			BlockID:   blockID,
+		})
+	}
+
+	for _, subnetID := range subnetIDs {
+		pendingBefore, err := vm.getPendingValidators(before, subnetID)
+		if err != nil {
+			return nil, err
+		}
+		currentBefore, err := vm.getCurrentValidators(before, subnetID)
+		if err != nil {
+			return nil, err
+		}
+		pendingAfter, err := vm.getPendingValidators(after, subnetID)
+		if err != nil {
+			return nil, err
+		}
+		currentAfter, err := vm.getCurrentValidators(after, subnetID)
+		if err != nil {
+			return nil, err
+		}
+
+		known := ids.Set{}
+		current := ids.Set{}
+		for _, staker := range pendingBefore.Txs {
+			known.Add(staker.ID())
+		}
+		for _, staker := range currentBefore.Txs {
+			known.Add(staker.ID())
+			current.Add(staker.ID())
+		}
+
+		for _, staker := range pendingAfter.Txs {
+			if !known.Contains(staker.ID()) {
+				newEvent(ValidatorAdded, subnetID, staker)
+			}
+		}
+		stillCurrent := ids.Set{}
+		for _, staker := range currentAfter.Txs {
+			stillCurrent.Add(staker.ID())
+			if !known.Contains(staker.ID()) {
+				newEvent(ValidatorAdded, subnetID, staker)
+			}
+			if !current.Contains(staker.ID()) {
+				newEvent(ValidatorStarted, subnetID, staker)
+			}
+		}
+		for _, staker := range currentBefore.Txs {
+			if stillCurrent.Contains(staker.ID()) {
+				continue
+			}
+			kind := ValidatorEnded
+			if reward, ok := tx.(*rewardValidatorTx); ok && committed && reward.TxID.Equals(staker.ID()) {
+				kind = ValidatorRewarded
+			}
+			newEvent(kind, subnetID, staker)
+		}
+	}
+	return events, nil
+}
+
+// recordOnAccept returns [onAccept], wrapped so [events] are recorded when
+// the decision block that enacts them is accepted. [onAccept] may be nil.
+func (vm *VM) recordOnAccept(events []*validatorEvent, onAccept func()) func() {
+	return func() {
+		if onAccept != nil {
+			onAccept()
+		}
+		if len(events) == 0 {
+			return
+		}
+		height, err := vm.getHeight(vm.DB)
+		if err != nil {
+			vm.Ctx.Log.Warn("couldn't record validator events: %s", err)
+			return
+		}
+		for _, event := range events {
+			event.Height = height
+			if err := vm.validatorEvents.put(event); err != nil {
+				vm.Ctx.Log.Warn("couldn't record validator event: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+)
+
+// acceptProposal builds a proposal block and accepts it with its option at
+// [option]
+func acceptProposal(t *testing.T, vm *VM, option int) *ProposalBlock {
+	vm.Ctx.Lock.Lock()
+	blk, err := vm.BuildBlock()
+	vm.Ctx.Lock.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := blk.(*ProposalBlock)
+	decision := block.Options()[option]
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept()
+	if err := decision.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	decision.Accept()
+	return block
+}
+
+func TestValidatorEventsAdded(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.unissuedEvents.Add(tx)
+	block := acceptProposal(t, vm, 0)
+
+	reply := GetValidatorEventsReply{}
+	if err := service.GetValidatorEvents(nil, &GetValidatorEventsArgs{NodeID: ID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Events) != 1 {
+		t.Fatalf("expected 1 event but got %d", len(reply.Events))
+	}
+	event := reply.Events[0]
+	height, err := vm.getHeight(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case event.Kind != ValidatorAdded.String():
+		t.Fatalf("expected the validator to have been added but it was %s", event.Kind)
+	case !event.TxID.Equals(tx.ID()):
+		t.Fatalf("expected the event of tx %s but got %s", tx.ID(), event.TxID)
+	case !event.SubnetID.Equals(DefaultSubnetID):
+		t.Fatalf("expected the event to be on the default subnet but it's on %s", event.SubnetID)
+	case event.Delegator:
+		t.Fatal("a validator shouldn't be reported as a delegator")
+	case !event.BlockID.Equals(block.ID()):
+		t.Fatalf("expected the event to reference block %s but it references %s", block.ID(), event.BlockID)
+	case uint64(event.Height) != height:
+		t.Fatalf("expected the event at height %d but it's at %d", height, event.Height)
+	}
+}
+
+func TestValidatorEventsRewarded(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	// Advance the time so the genesis validators leave, and reward the first
+	// of them while removing the second without reward
+	vm.clock.Set(defaultValidateEndTime)
+	acceptProposal(t, vm, 0)
+	rewarded := acceptProposal(t, vm, 0).Tx.(*rewardValidatorTx)
+	ended := acceptProposal(t, vm, 1).Tx.(*rewardValidatorTx)
+
+	tests := []struct {
+		tx   *rewardValidatorTx
+		kind ValidatorEventKind
+	}{
+		{rewarded, ValidatorRewarded},
+		{ended, ValidatorEnded},
+	}
+	for _, test := range tests {
+		reply := GetValidatorEventsReply{}
+		args := GetValidatorEventsArgs{NodeID: test.tx.staker.Vdr().ID()}
+		if err := service.GetValidatorEvents(nil, &args, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if len(reply.Events) != 1 {
+			t.Fatalf("expected 1 event but got %d", len(reply.Events))
+		}
+		if event := reply.Events[0]; event.Kind != test.kind.String() || !event.TxID.Equals(test.tx.TxID) {
+			t.Fatalf("expected the validator added by %s to have been %s but got %+v", test.tx.TxID, test.kind, event)
+		}
+	}
+
+	if err := service.GetValidatorEvents(nil, &GetValidatorEventsArgs{}, &GetValidatorEventsReply{}); err != errNoNodeID {
+		t.Fatalf("expected %s but got %v", errNoNodeID, err)
+	}
+}
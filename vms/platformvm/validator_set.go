@@ -0,0 +1,219 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/state"
+)
+
+// ValidatorSetEpoch is the number of blocks between two snapshots of the
+// default subnet's validator set. A snapshot is taken when a block whose
+// height is a multiple of ValidatorSetEpoch is accepted, so the genesis
+// validator set is the first snapshot.
+const ValidatorSetEpoch = 100
+
+var (
+	heightKey       = ids.NewID([32]byte{'h', 'e', 'i', 'g', 'h', 't'})
+	heightIndexKey  = ids.NewID([32]byte{'h', 'e', 'i', 'g', 'h', 't', ' ', 'i', 'n', 'd', 'e', 'x'})
+	validatorSetKey = ids.NewID([32]byte{'v', 'a', 'l', 'i', 'd', 'a', 't', 'o', 'r', ' ', 's', 'e', 't'})
+
+	errNoValidatorSet  = errors.New("no validator set snapshot at that height")
+	errNoBlockAtHeight = errors.New("no block has been accepted at that height")
+)
+
+// ValidatorSet is a snapshot of the default subnet's validator set, taken when
+// the block at [Height] was accepted
+type ValidatorSet struct {
+	Height uint64 `serialize:"true"`
+
+	// Sorted by node ID
+	Validators []Validator `serialize:"true"`
+}
+
+// Hash returns the aggregate hash of the validator set, which commits to the
+// node ID and weight of every validator
+func (vs *ValidatorSet) Hash() ids.ID {
+	bytes, _ := Codec.Marshal(vs.Validators)
+	return ids.NewID(hashing.ComputeHash256Array(bytes))
+}
+
+// Bytes returns the byte representation of this validator set
+func (vs *ValidatorSet) Bytes() []byte {
+	bytes, _ := Codec.Marshal(vs)
+	return bytes
+}
+
+type innerSortValidators []Validator
+
+func (vdrs innerSortValidators) Less(i, j int) bool {
+	return bytes.Compare(vdrs[i].NodeID.Bytes(), vdrs[j].NodeID.Bytes()) == -1
+}
+func (vdrs innerSortValidators) Len() int      { return len(vdrs) }
+func (vdrs innerSortValidators) Swap(i, j int) { vdrs[j], vdrs[i] = vdrs[i], vdrs[j] }
+
+// get the height of the last accepted block from [db]
+func (vm *VM) getHeight(db database.Database) (uint64, error) {
+	height, err := vm.State.GetUint64(db, heightKey)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get height: %w", err)
+	}
+	return height, nil
+}
+
+// put the height of the last accepted block in [db]
+func (vm *VM) putHeight(db database.Database, height uint64) error {
+	if err := vm.State.PutUint64(db, heightKey, height); err != nil {
+		return fmt.Errorf("couldn't put height: %w", err)
+	}
+	return nil
+}
+
+// get the ID of the accepted block at [height] from [db]
+func (vm *VM) getBlockIDAtHeight(db database.Database, height uint64) (ids.ID, error) {
+	blkID, err := vm.State.GetID(db, heightIndexKey.Prefix(height))
+	if err == database.ErrNotFound {
+		return ids.ID{}, fmt.Errorf("%w: %d", errNoBlockAtHeight, height)
+	}
+	return blkID, err
+}
+
+// put [blkID] in [db] as the ID of the accepted block at [height]
+func (vm *VM) putBlockIDAtHeight(db database.Database, height uint64, blkID ids.ID) error {
+	if err := vm.State.PutID(db, heightIndexKey.Prefix(height), blkID); err != nil {
+		return fmt.Errorf("couldn't index block %s at height %d: %w", blkID, height, err)
+	}
+	return nil
+}
+
+// get the snapshot of the default subnet's validator set at [height]
+func (vm *VM) getValidatorSet(db database.Database, height uint64) (*ValidatorSet, error) {
+	validatorSetIntf, err := vm.State.Get(db, validatorSetTypeID, validatorSetKey.Prefix(height))
+	if err == database.ErrNotFound {
+		return nil, errNoValidatorSet
+	}
+	if err != nil {
+		return nil, err
+	}
+	validatorSet, ok := validatorSetIntf.(*ValidatorSet)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve *ValidatorSet from database but got different type")
+		return nil, errDB
+	}
+	return validatorSet, nil
+}
+
+// put a snapshot of the default subnet's validator set in [db]
+func (vm *VM) putValidatorSet(db database.Database, validatorSet *ValidatorSet) error {
+	return vm.State.Put(db, validatorSetTypeID, validatorSetKey.Prefix(validatorSet.Height), validatorSet)
+}
+
+// acceptHeight advances the height stored in [db] when the block with ID
+// [blkID] is accepted, indexes the block by its height, and snapshots the
+// default subnet's validator set if the block starts a new epoch. [db] must
+// hold the chain's state once the block is accepted. If no height is stored,
+// the accepted block is the genesis block.
+func (vm *VM) acceptHeight(db database.Database, blkID ids.ID) error {
+	height := uint64(0)
+	has, err := vm.State.Has(db, state.Uint64TypeID, heightKey)
+	if err != nil {
+		return err
+	}
+	if has {
+		lastHeight, err := vm.getHeight(db)
+		if err != nil {
+			return err
+		}
+		height = lastHeight + 1
+	}
+	if err := vm.putHeight(db, height); err != nil {
+		return err
+	}
+	if err := vm.putBlockIDAtHeight(db, height, blkID); err != nil {
+		return err
+	}
+	if height%ValidatorSetEpoch != 0 {
+		return nil
+	}
+
+	currentValidators, err := vm.getCurrentValidators(db, DefaultSubnetID)
+	if err != nil {
+		return err
+	}
+	validatorSet := &ValidatorSet{Height: height}
+	for _, vdr := range vm.getValidators(currentValidators) {
+		validatorSet.Validators = append(validatorSet.Validators, Validator{
+			NodeID: vdr.ID(),
+			Wght:   vdr.Weight(),
+		})
+	}
+	sort.Sort(innerSortValidators(validatorSet.Validators))
+	return vm.putValidatorSet(db, validatorSet)
+}
+
+// initHeight stores the height of the last accepted block if the chain was
+// created before heights were tracked. The height is found by walking back
+// from the last accepted block to the genesis block.
+func (vm *VM) initHeight() error {
+	has, err := vm.State.Has(vm.DB, state.Uint64TypeID, heightKey)
+	if err != nil || has {
+		return err
+	}
+
+	height := uint64(0)
+	for blkID := vm.LastAccepted(); ; height++ {
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("couldn't get ancestor %s of the last accepted block: %w", blkID, err)
+		}
+		blkID = blk.ParentID()
+		if blkID.Equals(ids.Empty) {
+			break
+		}
+	}
+	if err := vm.putHeight(vm.DB, height); err != nil {
+		return err
+	}
+	return vm.DB.Commit()
+}
+
+// initHeightIndex indexes the accepted blocks by height if the chain was
+// created before they were indexed. The blocks are indexed by walking back
+// from the last accepted block to the most recent indexed block, or to the
+// genesis block.
+func (vm *VM) initHeightIndex() error {
+	height, err := vm.getHeight(vm.DB)
+	if err != nil {
+		return err
+	}
+
+	for blkID := vm.LastAccepted(); ; height-- {
+		indexed, err := vm.State.Has(vm.DB, state.IDTypeID, heightIndexKey.Prefix(height))
+		if err != nil {
+			return err
+		}
+		if indexed {
+			break
+		}
+		if err := vm.putBlockIDAtHeight(vm.DB, height, blkID); err != nil {
+			return err
+		}
+		if height == 0 {
+			break
+		}
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("couldn't get ancestor %s of the last accepted block: %w", blkID, err)
+		}
+		blkID = blk.ParentID()
+	}
+	return vm.DB.Commit()
+}
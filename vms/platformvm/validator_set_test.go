@@ -0,0 +1,133 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/state"
+)
+
+func TestValidatorSetSnapshots(t *testing.T) {
+	vm := defaultVM()
+
+	if height, err := vm.getHeight(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if height != 0 {
+		t.Fatalf("genesis should be at height 0 but was at %d", height)
+	}
+
+	genesisSet, err := vm.getValidatorSet(vm.DB, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genesisSet.Validators) != len(keys) {
+		t.Fatalf("expected %d validators at genesis but got %d", len(keys), len(genesisSet.Validators))
+	}
+	for i := 1; i < len(genesisSet.Validators); i++ {
+		if !innerSortValidators(genesisSet.Validators).Less(i-1, i) {
+			t.Fatalf("validators should be sorted by node ID")
+		}
+	}
+
+	// Accept a proposal block and its commit block
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.unissuedEvents.Add(tx)
+	vm.Ctx.Lock.Lock()
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	block := blk.(*ProposalBlock)
+	commit := block.Options()[0].(*Commit)
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept()
+	if err := commit.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	commit.Accept()
+
+	if height, err := vm.getHeight(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if height != 2 {
+		t.Fatalf("expected height 2 but was at %d", height)
+	}
+	if _, err := vm.getValidatorSet(vm.DB, 2); err != errNoValidatorSet {
+		t.Fatalf("shouldn't have snapshotted the validator set at height 2")
+	}
+
+	// The height of a chain created before heights were tracked is recovered
+	if err := vm.State.Put(vm.DB, state.Uint64TypeID, heightKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if height, err := vm.getHeight(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if height != 2 {
+		t.Fatalf("expected height 2 but was at %d", height)
+	}
+
+	service := Service{vm: vm}
+	reply := GetValidatorSetHashReply{}
+	if err := service.GetValidatorSetHash(nil, &GetValidatorSetHashArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Height != 0 || !reply.Hash.Equals(genesisSet.Hash()) || len(reply.Validators) != len(genesisSet.Validators) {
+		t.Fatalf("expected the genesis snapshot but got the one at height %d with hash %s", reply.Height, reply.Hash)
+	}
+
+	height := json.Uint64(1)
+	if err := service.GetValidatorSetHash(nil, &GetValidatorSetHashArgs{Height: &height}, &reply); err == nil {
+		t.Fatalf("should have errored because there is no snapshot at height 1")
+	}
+}
+
+func TestHeightIndex(t *testing.T) {
+	vm := defaultVM()
+	genesisID := vm.LastAccepted()
+
+	// Advancing the time accepts a proposal block and its commit block
+	vm.clock.Set(defaultValidateEndTime)
+	block := acceptProposal(t, vm, 0)
+
+	for height, expectedID := range []ids.ID{genesisID, block.ID(), vm.LastAccepted()} {
+		blkID, err := vm.GetBlockIDAtHeight(uint64(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !blkID.Equals(expectedID) {
+			t.Fatalf("expected block %s at height %d but got %s", expectedID, height, blkID)
+		}
+	}
+	if _, err := vm.GetBlockIDAtHeight(3); !errors.Is(err, errNoBlockAtHeight) {
+		t.Fatalf("expected %s but got %v", errNoBlockAtHeight, err)
+	}
+}
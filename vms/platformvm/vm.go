@@ -7,6 +7,7 @@ import (
 	"container/heap"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	stdmath "math"
@@ -16,6 +17,7 @@ import (
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/validators"
@@ -26,6 +28,9 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/components/idempotency"
+	"github.com/ava-labs/gecko/vms/components/watch"
+	"github.com/ava-labs/gecko/vms/platformvm/reward"
 )
 
 const (
@@ -35,21 +40,39 @@ const (
 	chainsTypeID
 	blockTypeID
 	subnetsTypeID
+	unissuedDecisionTxsTypeID
+	validatorSetTypeID
 
 	// Delta is the synchrony bound used for safe decision making
 	Delta = 10 * time.Second // TODO change to longer period (2 minutes?) before release
 
-	// InflationRate is the maximum inflation rate of AVA from staking
-	InflationRate = 1.04
-
-	// BatchSize is the number of decision transaction to place into a block
+	// BatchSize is the maximum number of decision transactions to place into a
+	// block
 	BatchSize = 30
 
+	// MaxBatchBytes is the maximum total size, in bytes, of the decision
+	// transactions placed into a block
+	MaxBatchBytes = 512 * 1024
+
+	// sigCacheSize is the number of public keys recovered from signatures that
+	// are cached, so that checking a block's signatures before it's verified
+	// makes verifying them cheap
+	sigCacheSize = 2048
+
 	// TODO: Incorporate these constants + turn them into governable parameters
 
 	// MinimumStakeAmount is the minimum amount of $AVA one must bond to be a staker
 	MinimumStakeAmount = 10 * units.MicroAva
 
+	// DefaultMinimumDelegationAmount is the minimum amount of $AVA one must
+	// delegate to a validator, unless the VM is configured otherwise
+	DefaultMinimumDelegationAmount = MinimumStakeAmount
+
+	// DefaultDelegationCapMultiplier bounds the stake delegated to a validator,
+	// unless the VM is configured otherwise. A validator's own stake plus the
+	// stake delegated to it may be at most this many times its own stake.
+	DefaultDelegationCapMultiplier = 5
+
 	// MinimumStakingDuration is the shortest amount of time a staker can bond
 	// their funds for.
 	MinimumStakingDuration = 24 * time.Hour
@@ -58,12 +81,35 @@ const (
 	// their funds for.
 	MaximumStakingDuration = 365 * 24 * time.Hour
 
+	// DefaultMaxFutureStartTime is how long after the chain time a staker may
+	// start, unless the VM is configured otherwise
+	DefaultMaxFutureStartTime = MaximumStakingDuration
+
+	// DefaultMinStartTimeLead is how long after this node's time a staker must
+	// start for this node to propose adding it, unless the VM is configured
+	// otherwise
+	DefaultMinStartTimeLead = Delta
+
+	// DefaultStartTimeMargin is how long after this node's time a validator
+	// must start for this node to build the transaction adding it, unless the
+	// VM is configured otherwise
+	DefaultStartTimeMargin = time.Minute
+
 	// NumberOfShares is the number of shares that a delegator is
 	// rewarded
 	NumberOfShares = 1000000
+
+	// MinimumUptime is the fraction of its staking period this node must have
+	// been connected to a validator to initially prefer rewarding the
+	// validator and its delegators
+	MinimumUptime = 0.6
 )
 
 var (
+	// DefaultRewardCurve is the emission schedule stakers are rewarded by,
+	// unless the VM is configured otherwise
+	DefaultRewardCurve reward.Curve = reward.Compounding{AnnualRate: 0.04}
+
 	// taken from https://stackoverflow.com/questions/25065055/what-is-the-maximum-time-time-in-go/32620397#32620397
 	maxTime = time.Unix(1<<63-62135596801, 0) // 0 is used because we drop the nano-seconds
 
@@ -75,6 +121,10 @@ var (
 	pendingValidatorsKey = ids.NewID([32]byte{'p', 'e', 'n', 'd', 'i', 'n', 'g'})
 	chainsKey            = ids.NewID([32]byte{'c', 'h', 'a', 'i', 'n', 's'})
 	subnetsKey           = ids.NewID([32]byte{'s', 'u', 'b', 'n', 'e', 't', 's'})
+	totalSupplyKey       = ids.NewID([32]byte{'s', 'u', 'p', 'p', 'l', 'y'})
+
+	unissuedEventsKey      = ids.NewID([32]byte{'u', 'n', 'i', 's', 's', 'u', 'e', 'd', ' ', 'e', 'v', 'e', 'n', 't', 's'})
+	unissuedDecisionTxsKey = ids.NewID([32]byte{'u', 'n', 'i', 's', 's', 'u', 'e', 'd', ' ', 'd', 'e', 'c', 'i', 's', 'i', 'o', 'n', 's'})
 )
 
 var (
@@ -92,8 +142,11 @@ var (
 	errDBChains               = errors.New("couldn't retrieve chain list from database")
 	errDBPutChains            = errors.New("couldn't put chain list in database")
 	errDBPutBlock             = errors.New("couldn't put block in database")
+	errDBUnissuedTxs          = errors.New("couldn't retrieve unissued transactions from database")
+	errDBPutUnissuedTxs       = errors.New("couldn't put unissued transactions in database")
 	errRegisteringType        = errors.New("error registering type with database")
 	errMissingBlock           = errors.New("missing block")
+	errBlockTooLarge          = errors.New("block is larger than the maximum block size")
 )
 
 // Codec does serialization and deserialization
@@ -105,18 +158,18 @@ func init() {
 	errs := wrappers.Errs{}
 	errs.Add(
 		Codec.RegisterType(&ProposalBlock{}),
-		Codec.RegisterType(&Abort{}),
-		Codec.RegisterType(&Commit{}),
-		Codec.RegisterType(&StandardBlock{}),
+		Codec.RegisterType(&legacyAbort{}),
+		Codec.RegisterType(&legacyCommit{}),
+		Codec.RegisterType(&legacyStandardBlock{}),
 
 		Codec.RegisterType(&UnsignedAddDefaultSubnetValidatorTx{}),
-		Codec.RegisterType(&addDefaultSubnetValidatorTx{}),
+		Codec.RegisterType(&AddDefaultSubnetValidatorTx{}),
 
 		Codec.RegisterType(&UnsignedAddNonDefaultSubnetValidatorTx{}),
-		Codec.RegisterType(&addNonDefaultSubnetValidatorTx{}),
+		Codec.RegisterType(&AddNonDefaultSubnetValidatorTx{}),
 
 		Codec.RegisterType(&UnsignedAddDefaultSubnetDelegatorTx{}),
-		Codec.RegisterType(&addDefaultSubnetDelegatorTx{}),
+		Codec.RegisterType(&AddDefaultSubnetDelegatorTx{}),
 
 		Codec.RegisterType(&UnsignedCreateChainTx{}),
 		Codec.RegisterType(&CreateChainTx{}),
@@ -126,12 +179,33 @@ func init() {
 
 		Codec.RegisterType(&advanceTimeTx{}),
 		Codec.RegisterType(&rewardValidatorTx{}),
+
+		Codec.RegisterType(&UnsignedTransferTx{}),
+		Codec.RegisterType(&TransferTx{}),
+
+		Codec.RegisterType(&Abort{}),
+		Codec.RegisterType(&Commit{}),
+		Codec.RegisterType(&StandardBlock{}),
+
+		Codec.RegisterType(&UnsignedSendMessageTx{}),
+		Codec.RegisterType(&SendMessageTx{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
 	}
 }
 
+// Uptimes reports when this node was connected to other nodes
+type Uptimes interface {
+	// Status returns whether this node is connected to [nodeID], and the last
+	// time it heard from [nodeID]
+	Status(nodeID ids.ShortID) (bool, time.Time)
+
+	// Uptime returns the fraction of the time since [since] this node was
+	// connected to [nodeID], and how much of that time it observed
+	Uptime(nodeID ids.ShortID, since time.Time) (float64, time.Duration)
+}
+
 // VM implements the snowman.ChainVM interface
 type VM struct {
 	*core.SnowmanVM
@@ -141,6 +215,43 @@ type VM struct {
 	// The node's chain manager
 	ChainManager chains.Manager
 
+	// Uptimes reports when this node was connected to the validators. If it's
+	// nil, this node always initially prefers rewarding stakers.
+	Uptimes Uptimes
+
+	// If true, the total supply of $AVA is rebuilt from the accounts and the
+	// stakers when the chain is initialized
+	Reindex bool
+
+	// WatchAllowedHosts are the only hosts that address watches may send
+	// callbacks to. If it's empty, callbacks may be sent to any public address.
+	WatchAllowedHosts []string
+
+	// Upgrades are the changes to the rules of this chain and when they take
+	// effect. They must be the same on every node of the network.
+	Upgrades Upgrades
+
+	// MinStartTimeLead is how long after this node's time a staker must start
+	// for this node to propose adding it. If it is 0, DefaultMinStartTimeLead
+	// is used.
+	MinStartTimeLead time.Duration
+
+	// StartTimeMargin is how long after this node's time a validator must
+	// start for this node to build the transaction adding it, which leaves
+	// time for the transaction to be signed and issued. If it is 0,
+	// DefaultStartTimeMargin is used. It's at least MinStartTimeLead.
+	StartTimeMargin time.Duration
+
+	// RewardCurve is the emission schedule stakers are rewarded by. It must be
+	// the same on every node of the network. If it is nil, DefaultRewardCurve
+	// is used.
+	RewardCurve reward.Curve
+
+	// AdvanceTimePacing is the minimum time between two proposals this node
+	// makes to advance the chain time. If it is 0, this node proposes
+	// advancing the chain time as soon as the next validator set change is due.
+	AdvanceTimePacing time.Duration
+
 	// Used to create and use keys.
 	factory crypto.FactorySECP256K1R
 
@@ -155,6 +266,35 @@ type VM struct {
 	unissuedEvents      *EventHeap
 	unissuedDecisionTxs []DecisionTx
 
+	// Remembers the txs issued by API calls with an idempotency key
+	issuedTokens idempotency.Tokens
+
+	// Notifies the callback URLs watching accounts of their accepted txs
+	watcher watch.Watcher
+
+	// The node-local names of addresses, which may be passed to the API
+	// instead of the addresses
+	addressAliases addressAliases
+
+	// The history of the transitions in the lifecycle of the stakers
+	validatorEvents validatorEventLog
+
+	// The delegation limits in effect
+	minDelegationAmount     uint64
+	delegationCapMultiplier uint64
+
+	// The chain time limits in effect
+	maxFutureStartTime time.Duration
+	minStartTimeLead   time.Duration
+	startTimeMargin    time.Duration
+	advanceTimePacing  time.Duration
+
+	// The emission schedule in effect
+	rewardCurve reward.Curve
+
+	// The local time at which this node last proposed advancing the chain time
+	lastAdvanceTimeProposal time.Time
+
 	// This timer goes off when it is time for the next validator to add/leave the validator set
 	// When it goes off resetTimer() is called, triggering creation of a new block
 	timer *timer.Timer
@@ -175,6 +315,10 @@ func (vm *VM) Initialize(
 		return errUnsupportedFXs
 	}
 
+	// The chain's clock, which may run faster than real time on test networks
+	vm.clock = ctx.Clock
+	vm.factory.Cache.Size = sigCacheSize
+
 	// Initialize the inner VM, which has a lot of boiler-plate logic
 	vm.SnowmanVM = &core.SnowmanVM{}
 	if err := vm.SnowmanVM.Initialize(ctx, db, vm.unmarshalBlockFunc, msgs); err != nil {
@@ -184,6 +328,35 @@ func (vm *VM) Initialize(
 	// Register this VM's types with the database so we can get/put structs to/from it
 	vm.registerDBTypes()
 
+	vm.minDelegationAmount = DefaultMinimumDelegationAmount
+	if vm.Upgrades.MinimumDelegationAmount != 0 {
+		vm.minDelegationAmount = vm.Upgrades.MinimumDelegationAmount
+	}
+	vm.delegationCapMultiplier = DefaultDelegationCapMultiplier
+	if vm.Upgrades.DelegationCapMultiplier != 0 {
+		vm.delegationCapMultiplier = vm.Upgrades.DelegationCapMultiplier
+	}
+	vm.maxFutureStartTime = DefaultMaxFutureStartTime
+	if vm.Upgrades.MaxFutureStartTime != 0 {
+		vm.maxFutureStartTime = vm.Upgrades.MaxFutureStartTime
+	}
+	vm.minStartTimeLead = DefaultMinStartTimeLead
+	if vm.MinStartTimeLead != 0 {
+		vm.minStartTimeLead = vm.MinStartTimeLead
+	}
+	vm.startTimeMargin = DefaultStartTimeMargin
+	if vm.StartTimeMargin != 0 {
+		vm.startTimeMargin = vm.StartTimeMargin
+	}
+	if vm.startTimeMargin < vm.minStartTimeLead {
+		vm.startTimeMargin = vm.minStartTimeLead
+	}
+	vm.advanceTimePacing = vm.AdvanceTimePacing
+	vm.rewardCurve = DefaultRewardCurve
+	if vm.RewardCurve != nil {
+		vm.rewardCurve = vm.RewardCurve
+	}
+
 	// If the database is empty, create the platform chain anew using
 	// the provided genesis state
 	if !vm.DBInitialized() {
@@ -207,6 +380,27 @@ func (vm *VM) Initialize(
 			return errDBPutCurrentValidators
 		}
 
+		// Persist the amount of $AVA that exists at genesis, which is the sum
+		// of the account balances and the amounts staked by the validators
+		totalSupply := uint64(0)
+		for _, account := range genesis.Accounts {
+			newTotalSupply, err := math.Add64(totalSupply, account.Balance)
+			if err != nil {
+				return err
+			}
+			totalSupply = newTotalSupply
+		}
+		for _, validator := range genesis.Validators.Txs {
+			newTotalSupply, err := math.Add64(totalSupply, validator.Vdr().Weight())
+			if err != nil {
+				return err
+			}
+			totalSupply = newTotalSupply
+		}
+		if err := vm.putTotalSupply(vm.DB, totalSupply); err != nil {
+			return err
+		}
+
 		// Persist the subnets that exist at genesis (none do)
 		if err := vm.putSubnets(vm.DB, []*CreateSubnetTx{}); err != nil {
 			return fmt.Errorf("error putting genesis subnets: %v", err)
@@ -242,19 +436,58 @@ func (vm *VM) Initialize(
 		// Create the genesis block and save it as being accepted
 		// (We don't just do genesisBlock.Accept() because then it'd look for genesisBlock's
 		// non-existent parent)
-		genesisBlock := vm.newCommitBlock(ids.Empty)
+		genesisBlock, ok := vm.newCommitBlock(ids.Empty, nil).(*legacyCommit)
+		if !ok {
+			return errDB
+		}
 		if err := vm.State.PutBlock(vm.DB, genesisBlock); err != nil {
 			return errDB
 		}
 		genesisBlock.onAcceptDB = versiondb.New(vm.DB)
 		genesisBlock.CommonBlock.Accept()
+		if err := vm.acceptHeight(vm.DB, genesisBlock.ID()); err != nil {
+			return err
+		}
+
+		// The genesis accounts were put in the account trie
+		if err := vm.State.PutStatus(vm.DB, accountTrieInitializedKey, choices.Accepted); err != nil {
+			return err
+		}
 
 		vm.SetDBInitialized()
+	} else {
+		if err := vm.initHeight(); err != nil {
+			ctx.Log.Error("failed to initialize the platform chain's height: %s", err)
+			return err
+		}
+		if err := vm.initHeightIndex(); err != nil {
+			ctx.Log.Error("failed to index the platform chain's blocks by height: %s", err)
+			return err
+		}
+		if err := vm.initAccountTrie(); err != nil {
+			ctx.Log.Error("failed to build the account trie: %s", err)
+			return err
+		}
+		if err := vm.initTotalSupply(); err != nil {
+			ctx.Log.Error("failed to compute the total supply of $AVA: %s", err)
+			return err
+		}
+	}
+
+	if vm.Reindex {
+		if err := vm.reindex(); err != nil {
+			ctx.Log.Error("failed to reindex the platform chain: %s", err)
+			return err
+		}
 	}
 
 	// Transactions from clients that have not yet been put into blocks
-	// and added to consensus
-	vm.unissuedEvents = &EventHeap{SortByStartTime: true}
+	// and added to consensus. These are persisted so that a restart doesn't
+	// drop them.
+	if err := vm.initUnissuedTxs(); err != nil {
+		ctx.Log.Error("failed to load unissued transactions: %s", err)
+		return err
+	}
 
 	vm.currentBlocks = make(map[[32]byte]Block)
 	vm.timer = timer.NewTimer(func() {
@@ -276,6 +509,12 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	if err := vm.initWatcher(db); err != nil {
+		return err
+	}
+	vm.initAddressAliases(db)
+	vm.initValidatorEvents(db)
+
 	// Build off the most recently accepted block
 	vm.SetPreference(vm.LastAccepted())
 
@@ -291,9 +530,10 @@ func (vm *VM) initBlockchains() error {
 	}
 	for _, chain := range existingChains { // Create each blockchain
 		chainParams := chains.ChainParameters{
-			ID:          chain.ID(),
-			GenesisData: chain.GenesisData,
-			VMAlias:     chain.VMID.String(),
+			ID:           chain.ID(),
+			GenesisData:  chain.GenesisData,
+			VMAlias:      chain.VMID.String(),
+			Dependencies: []ids.ID{vm.Ctx.ChainID}, // The platform chain must finish bootstrapping first
 		}
 		for _, fxID := range chain.FxIDs {
 			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
@@ -303,9 +543,44 @@ func (vm *VM) initBlockchains() error {
 	return nil
 }
 
+// Load the transactions that were issued to this node but hadn't been put
+// into a block when this node last shut down
+func (vm *VM) initUnissuedTxs() error {
+	unissuedEvents, err := vm.getUnissuedEvents(vm.DB)
+	if err != nil {
+		return errDBUnissuedTxs
+	}
+	unissuedDecisionTxs, err := vm.getUnissuedDecisionTxs(vm.DB)
+	if err != nil {
+		return errDBUnissuedTxs
+	}
+	vm.unissuedEvents = unissuedEvents
+	vm.unissuedDecisionTxs = unissuedDecisionTxs
+
+	if numTxs := vm.unissuedEvents.Len() + len(vm.unissuedDecisionTxs); numTxs > 0 {
+		vm.Ctx.Log.Info("loaded %d unissued transactions from the database", numTxs)
+	}
+	return nil
+}
+
+// Persist the transactions that haven't been put into a block yet so that
+// they aren't lost if this node restarts
+func (vm *VM) persistUnissuedTxs() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		vm.putUnissuedEvents(vm.DB, vm.unissuedEvents),
+		vm.putUnissuedDecisionTxs(vm.DB, vm.unissuedDecisionTxs),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+	return vm.DB.Commit()
+}
+
 // Shutdown this blockchain
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	vm.watcher.Shutdown()
 	if err := vm.DB.Close(); err != nil {
 		vm.Ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -316,28 +591,6 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	vm.Ctx.Log.Debug("in BuildBlock")
 	preferredID := vm.Preferred()
 
-	// If there are pending decision txs, build a block with a batch of them
-	if len(vm.unissuedDecisionTxs) > 0 {
-		numTxs := BatchSize
-		if numTxs > len(vm.unissuedDecisionTxs) {
-			numTxs = len(vm.unissuedDecisionTxs)
-		}
-		var txs []DecisionTx
-		txs, vm.unissuedDecisionTxs = vm.unissuedDecisionTxs[:numTxs], vm.unissuedDecisionTxs[numTxs:]
-		blk, err := vm.newStandardBlock(preferredID, txs)
-		if err != nil {
-			return nil, err
-		}
-		if err := blk.Verify(); err != nil {
-			vm.resetTimer()
-			return nil, err
-		}
-		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
-			return nil, err
-		}
-		return blk, vm.DB.Commit()
-	}
-
 	// Get the preferred block (which we want to build off)
 	preferred, err := vm.getBlock(preferredID)
 	vm.Ctx.Log.AssertNoError(err)
@@ -351,6 +604,37 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		return nil, errInvalidBlockType
 	}
 
+	// If there are pending decision txs, build a block with a batch of them.
+	// They're issued ahead of any proposal that's due, so they never wait for
+	// a proposal that hasn't been issued yet.
+	if len(vm.unissuedDecisionTxs) > 0 {
+		txs, onAcceptDB := vm.packDecisionTxs(db)
+		if err := vm.putUnissuedDecisionTxs(vm.DB, vm.unissuedDecisionTxs); err != nil {
+			return nil, err
+		}
+		if len(txs) > 0 {
+			root, err := vm.accountRootCommitment(onAcceptDB)
+			if err != nil {
+				return nil, err
+			}
+			blk, err := vm.newStandardBlock(preferredID, txs, root)
+			if err != nil {
+				return nil, err
+			}
+			if err := blk.Verify(); err != nil {
+				vm.resetTimer()
+				return nil, err
+			}
+			if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+				return nil, err
+			}
+			return blk, vm.DB.Commit()
+		}
+		if err := vm.DB.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
 	// The chain time if the preferred block were to be committed
 	currentChainTimestamp, err := vm.getTimestamp(db)
 	if err != nil {
@@ -397,7 +681,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	}
 
 	localTime := vm.clock.Time()
-	if !localTime.Before(nextValidatorSetChangeTime) { // time is at or after the time for the next validator to join/leave
+	if !localTime.Before(vm.advanceTimeReadyTime(nextValidatorSetChangeTime)) { // time is at or after the time for the next validator to join/leave
 		advanceTimeTx, err := vm.newAdvanceTimeTx(nextValidatorSetChangeTime)
 		if err != nil {
 			return nil, err
@@ -409,12 +693,13 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
 			return nil, err
 		}
+		vm.lastAdvanceTimeProposal = localTime
 		return blk, vm.DB.Commit()
 	}
 
 	// Propose adding a new validator but only if their start time is in the
-	// future relative to local time (plus Delta)
-	syncTime := localTime.Add(Delta)
+	// future relative to local time (plus the minimum lead)
+	syncTime := vm.earliestIssuableStartTime(localTime)
 	for vm.unissuedEvents.Len() > 0 {
 		tx := vm.unissuedEvents.Remove()
 		if !syncTime.After(tx.StartTime()) {
@@ -425,10 +710,16 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 			if err := vm.State.PutBlock(vm.DB, blk); err != nil {
 				return nil, err
 			}
+			if err := vm.putUnissuedEvents(vm.DB, vm.unissuedEvents); err != nil {
+				return nil, err
+			}
 			return blk, vm.DB.Commit()
 		}
 		vm.Ctx.Log.Debug("dropping tx to add validator because start time too late")
 	}
+	if err := vm.persistUnissuedTxs(); err != nil {
+		return nil, err
+	}
 
 	vm.Ctx.Log.Debug("BuildBlock returning error (no blocks)")
 	return nil, errNoPendingBlocks
@@ -436,6 +727,9 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 
 // ParseBlock implements the snowman.ChainVM interface
 func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
+	if len(bytes) > vm.Ctx.Limits.MaxBlockSize {
+		return nil, errBlockTooLarge
+	}
 	blockInterface, err := vm.unmarshalBlockFunc(bytes)
 	if err != nil {
 		return nil, errors.New("problem parsing block")
@@ -456,6 +750,14 @@ func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
 // GetBlock implements the snowman.ChainVM interface
 func (vm *VM) GetBlock(blkID ids.ID) (snowman.Block, error) { return vm.getBlock(blkID) }
 
+// GetBlockIDAtHeight implements the snowman.HeightIndexedChainVM interface
+func (vm *VM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	return vm.getBlockIDAtHeight(vm.DB, height)
+}
+
+// LastAcceptedHeight implements the snowman.HeightIndexedChainVM interface
+func (vm *VM) LastAcceptedHeight() (uint64, error) { return vm.getHeight(vm.DB) }
+
 func (vm *VM) getBlock(blkID ids.ID) (Block, error) {
 	// If block is in memory, return it.
 	if blk, exists := vm.currentBlocks[blkID.Key()]; exists {
@@ -487,7 +789,14 @@ func (vm *VM) SetPreference(blkID ids.ID) {
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
 	// Create a service with name "platform"
 	handler := vm.SnowmanVM.NewHandler("platform", &Service{vm: vm})
-	return map[string]*common.HTTPHandler{"": handler}
+	handler.Handler = aliasHandler{vm: vm, handler: handler.Handler}
+	return map[string]*common.HTTPHandler{
+		"": handler,
+		ExportEndpoint: &common.HTTPHandler{
+			LockOptions: common.ReadLock,
+			Handler:     exportHandler{vm: vm},
+		},
+	}
 }
 
 // CreateStaticHandlers implements the snowman.ChainVM interface
@@ -499,6 +808,60 @@ func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
 	}
 }
 
+// packDecisionTxs removes from the unissued decision txs a batch of txs that
+// can be placed into a block together, assuming the state in [db]. Txs are
+// considered in a deterministic order. A tx that is invalid given [db] is
+// retried after the other txs of the batch, as it may depend on them (e.g.
+// a higher nonce from the same account). Txs that are still invalid are
+// dropped. Txs that don't fit into the batch, or into a block no larger than the
+// chain's maximum block size, remain unissued. It also returns the state once
+// the batch is accepted.
+func (vm *VM) packDecisionTxs(db database.Database) ([]DecisionTx, database.Database) {
+	remaining := decisionTxList(vm.unissuedDecisionTxs)
+	sort.Stable(remaining)
+
+	batchDB := versiondb.New(db)
+	batch := []DecisionTx(nil)
+	batchBytes := 0
+	blockBytes := standardBlockHeaderLen
+	deferred := decisionTxList(nil)
+	for progress := true; progress; {
+		progress = false
+		retry := decisionTxList(nil)
+		for _, tx := range remaining {
+			txBytes := len(tx.Bytes())
+			if len(batch) >= BatchSize ||
+				batchBytes+txBytes > MaxBatchBytes ||
+				blockBytes+txBytes+standardBlockTxOverhead > vm.Ctx.Limits.MaxBlockSize {
+				deferred = append(deferred, tx)
+				continue
+			}
+
+			txDB := versiondb.New(batchDB)
+			if _, err := tx.SemanticVerify(txDB); err != nil {
+				retry = append(retry, tx)
+				continue
+			}
+			if err := txDB.Commit(); err != nil {
+				vm.Ctx.Log.Error("failed to commit decision tx to batch: %s", err)
+				retry = append(retry, tx)
+				continue
+			}
+			batch = append(batch, tx)
+			batchBytes += txBytes
+			blockBytes += txBytes + standardBlockTxOverhead
+			progress = true
+		}
+		remaining = retry
+	}
+	vm.unissuedDecisionTxs = deferred
+
+	if len(remaining) > 0 {
+		vm.Ctx.Log.Debug("dropping %d invalid decision txs", len(remaining))
+	}
+	return batch, batchDB
+}
+
 // Check if there is a block ready to be added to consensus
 // If so, notify the consensus engine
 func (vm *VM) resetTimer() {
@@ -551,12 +914,13 @@ func (vm *VM) resetTimer() {
 	}
 
 	localTime := vm.clock.Time()
-	if !localTime.Before(nextValidatorSetChangeTime) { // time is at or after the time for the next validator to join/leave
+	advanceTime := vm.advanceTimeReadyTime(nextValidatorSetChangeTime)
+	if !localTime.Before(advanceTime) { // time is at or after the time for the next validator to join/leave
 		vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeTimestamp
 		return
 	}
 
-	syncTime := localTime.Add(Delta)
+	syncTime := vm.earliestIssuableStartTime(localTime)
 	for vm.unissuedEvents.Len() > 0 {
 		if !syncTime.After(vm.unissuedEvents.Peek().StartTime()) {
 			vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeAddValidator
@@ -565,13 +929,16 @@ func (vm *VM) resetTimer() {
 		// If the tx doesn't meet the syncrony bound, drop it
 		vm.unissuedEvents.Remove()
 		vm.Ctx.Log.Debug("dropping tx to add validator because its start time has passed")
+		if err := vm.persistUnissuedTxs(); err != nil {
+			vm.Ctx.Log.Error("failed to persist unissued transactions: %s", err)
+		}
 	}
 
-	waitTime := nextValidatorSetChangeTime.Sub(localTime)
-	vm.Ctx.Log.Info("next scheduled event is at %s (%s in the future)", nextValidatorSetChangeTime, waitTime)
+	waitTime := advanceTime.Sub(localTime)
+	vm.Ctx.Log.Info("next scheduled event is at %s (%s in the future)", advanceTime, waitTime)
 
 	// Wake up when it's time to add/remove the next validator
-	vm.timer.SetTimeoutIn(waitTime)
+	vm.timer.SetTimeoutIn(vm.clock.RealDuration(waitTime))
 }
 
 // If [start], returns the time at which the next validator (of any subnet) in the pending set starts validating
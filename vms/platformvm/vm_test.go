@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/components/state"
 	"github.com/ava-labs/gecko/vms/timestampvm"
 )
 
@@ -119,10 +120,10 @@ func defaultVM() *VM {
 	vm.Validators = validators.NewManager()
 	vm.Validators.PutValidatorSet(DefaultSubnetID, defaultSubnet)
 
-	vm.clock.Set(defaultGenesisTime)
 	db := memdb.New()
 	msgChan := make(chan common.Message, 1)
 	ctx := defaultContext()
+	ctx.Clock.Set(defaultGenesisTime)
 	if err := vm.Initialize(ctx, db, genesisBytes, msgChan, nil); err != nil {
 		panic(err)
 	}
@@ -1060,3 +1061,371 @@ func TestCreateSubnet(t *testing.T) {
 	}
 
 }
+
+// Ensure transactions that haven't been put into a block survive a restart
+func TestUnissuedTxsPersisted(t *testing.T) {
+	vm := defaultVM()
+
+	createSubnetTx, err := vm.newCreateSubnetTx(
+		testNetworkID,
+		defaultNonce+1,
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		1,       // threshold
+		keys[0], // payer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+	addValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		keys[1],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, createSubnetTx)
+	vm.unissuedEvents.Add(addValidatorTx)
+	if err := vm.persistUnissuedTxs(); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	restartedVM := &VM{
+		SnowmanVM:  &core.SnowmanVM{},
+		Validators: vm.Validators,
+	}
+	restartedCtx := defaultContext()
+	restartedCtx.Clock.Set(defaultGenesisTime)
+	if err := restartedVM.Initialize(restartedCtx, vm.DB.GetDatabase(), nil, make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(restartedVM.unissuedDecisionTxs) != 1 {
+		t.Fatalf("should have loaded 1 unissued decision tx but loaded %d", len(restartedVM.unissuedDecisionTxs))
+	}
+	if tx, ok := restartedVM.unissuedDecisionTxs[0].(*CreateSubnetTx); !ok {
+		t.Fatal("should have loaded a *CreateSubnetTx")
+	} else if !tx.ID.Equals(createSubnetTx.ID) {
+		t.Fatalf("loaded tx %s but expected %s", tx.ID, createSubnetTx.ID)
+	}
+
+	if restartedVM.unissuedEvents.Len() != 1 {
+		t.Fatalf("should have loaded 1 unissued event but loaded %d", restartedVM.unissuedEvents.Len())
+	}
+	if txID := restartedVM.unissuedEvents.Peek().ID(); !txID.Equals(addValidatorTx.ID()) {
+		t.Fatalf("loaded event %s but expected %s", txID, addValidatorTx.ID())
+	}
+
+	// Building a block should remove the decision tx from the persisted set
+	restartedVM.Ctx.Lock.Lock()
+	if _, err := restartedVM.BuildBlock(); err != nil {
+		t.Fatal(err)
+	}
+	restartedVM.Ctx.Lock.Unlock()
+
+	decisionTxs, err := restartedVM.getUnissuedDecisionTxs(restartedVM.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisionTxs) != 0 {
+		t.Fatalf("should have persisted 0 unissued decision txs but persisted %d", len(decisionTxs))
+	}
+}
+
+// test that multiple dependent decision txs are packed into one block and
+// that invalid decision txs are dropped
+func TestBuildBlockPacksDecisionTxs(t *testing.T) {
+	vm := defaultVM()
+
+	newSubnetTx := func(nonce uint64) *CreateSubnetTx {
+		tx, err := vm.newCreateSubnetTx(
+			testNetworkID,
+			nonce,
+			[]ids.ShortID{keys[0].PublicKey().Address()},
+			1,       // threshold
+			keys[0], // payer
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	secondTx := newSubnetTx(defaultNonce + 2)
+	firstTx := newSubnetTx(defaultNonce + 1)
+	invalidTx := newSubnetTx(defaultNonce)
+
+	genesisSubnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, secondTx, invalidTx, firstTx)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	sb, ok := blk.(*StandardBlock)
+	if !ok {
+		t.Fatalf("should have built a standard block but built %T", blk)
+	}
+	if len(sb.Txs) != 2 {
+		t.Fatalf("should have packed 2 txs but packed %d", len(sb.Txs))
+	}
+	if len(vm.unissuedDecisionTxs) != 0 {
+		t.Fatalf("should have dropped the invalid tx")
+	}
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept()
+
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numCreated := len(subnets) - len(genesisSubnets); numCreated != 2 {
+		t.Fatalf("should have created 2 subnets but created %d", numCreated)
+	}
+}
+
+// Ensure pending decision txs are issued before a pending proposal, rather
+// than waiting for the proposal to be decided
+func TestBuildBlockIssuesDecisionTxsBeforeProposals(t *testing.T) {
+	vm := defaultVM()
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+
+	addValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+2,
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createSubnetTx, err := vm.newCreateSubnetTx(
+		testNetworkID,
+		defaultNonce+1,
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		1,       // threshold
+		keys[0], // payer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	vm.unissuedEvents.Add(addValidatorTx)
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, createSubnetTx)
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blk.(*StandardBlock); !ok {
+		t.Fatalf("should have built a standard block but built %T", blk)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept()
+
+	blk, err = vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blk.(*ProposalBlock); !ok {
+		t.Fatalf("should have built a proposal block but built %T", blk)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure a standard block with a tx that has an invalid signature fails
+// stateless verification, and that stateless verification doesn't modify the
+// block's txs, so it can run concurrently with their verification
+func TestStandardBlockVerifyStateless(t *testing.T) {
+	vm := defaultVM()
+
+	newTx := func() *CreateChainTx {
+		tx, err := vm.newCreateChainTx(
+			defaultNonce+1,
+			nil,
+			timestampvm.ID,
+			nil,
+			"chain name",
+			testNetworkID,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	validTx := newTx()
+	invalidTx := newTx()
+	invalidTx.Sig[crypto.SECP256K1RSigLen-1] = 4 // Not a valid recovery ID
+
+	blk, err := vm.newStandardBlock(vm.LastAccepted(), []DecisionTx{validTx}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := blk.(*legacyStandardBlock)
+
+	done := make(chan error)
+	for i := 0; i < 4; i++ {
+		go func() { done <- sb.VerifyStateless() }()
+	}
+	if err := validTx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blk, err = vm.newStandardBlock(vm.LastAccepted(), []DecisionTx{newTx(), invalidTx}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb = blk.(*legacyStandardBlock)
+	if err := sb.VerifyStateless(); err == nil {
+		t.Fatalf("Should have failed because a tx has an invalid signature")
+	}
+	for _, tx := range sb.Txs {
+		if tx.(*CreateChainTx).key != nil {
+			t.Fatalf("Stateless verification shouldn't have modified the tx")
+		}
+	}
+}
+
+func TestReindex(t *testing.T) {
+	vm := defaultVM()
+
+	// Corrupt the total supply as if it had never been maintained
+	if err := vm.putTotalSupply(vm.DB, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.reindex(); err != nil {
+		t.Fatal(err)
+	}
+
+	totalSupply, err := vm.getTotalSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := uint64(len(keys)) * (defaultBalance + defaultStakeAmount); totalSupply != expected {
+		t.Fatalf("expected total supply %d but got %d", expected, totalSupply)
+	}
+}
+
+func TestInitTotalSupply(t *testing.T) {
+	vm := defaultVM()
+
+	// Drop the total supply as if the chain was created before it was tracked
+	if err := vm.State.Put(vm.DB, state.Uint64TypeID, totalSupplyKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initTotalSupply(); err != nil {
+		t.Fatal(err)
+	}
+
+	totalSupply, err := vm.getTotalSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := uint64(len(keys)) * (defaultBalance + defaultStakeAmount); totalSupply != expected {
+		t.Fatalf("expected total supply %d but got %d", expected, totalSupply)
+	}
+
+	// A tracked total supply isn't recomputed
+	if err := vm.putTotalSupply(vm.DB, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initTotalSupply(); err != nil {
+		t.Fatal(err)
+	}
+	if totalSupply, err := vm.getTotalSupply(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if totalSupply != 1 {
+		t.Fatalf("shouldn't have recomputed the total supply")
+	}
+}
+
+// test that standard blocks are built no larger than the chain's maximum block
+// size, and that the decision txs that don't fit wait for the next block
+func TestBuildBlockRespectsMaxBlockSize(t *testing.T) {
+	vm := defaultVM()
+
+	newSubnetTx := func(nonce uint64) *CreateSubnetTx {
+		tx, err := vm.newCreateSubnetTx(
+			testNetworkID,
+			nonce,
+			[]ids.ShortID{keys[0].PublicKey().Address()},
+			1,       // threshold
+			keys[0], // payer
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	firstTx := newSubnetTx(defaultNonce + 1)
+	secondTx := newSubnetTx(defaultNonce + 2)
+
+	// Only one of the txs fits in a block
+	vm.Ctx.Limits.MaxBlockSize = standardBlockHeaderLen + standardBlockTxOverhead + len(firstTx.Bytes())
+
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, firstTx, secondTx)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sb, ok := blk.(*StandardBlock)
+	if !ok {
+		t.Fatalf("should have built a standard block but built %T", blk)
+	}
+	if len(sb.Txs) != 1 {
+		t.Fatalf("should have packed 1 tx but packed %d", len(sb.Txs))
+	}
+	if blkLen := len(blk.Bytes()); blkLen > vm.Ctx.Limits.MaxBlockSize {
+		t.Fatalf("built a %d byte block, which is larger than the limit of %d bytes", blkLen, vm.Ctx.Limits.MaxBlockSize)
+	}
+	if len(vm.unissuedDecisionTxs) != 1 {
+		t.Fatalf("should have left 1 unissued decision tx but left %d", len(vm.unissuedDecisionTxs))
+	}
+}
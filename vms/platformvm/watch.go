@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// The watches are stored under their own prefix of the chain's database. They
+// aren't part of the chain's state, so they're written outside of [vm.DB].
+var watchPrefix = []byte("watch")
+
+// initWatcher loads the watches registered in [db] and starts notifying them
+// of the txs accepted once the chain is bootstrapped
+func (vm *VM) initWatcher(db database.Database) error {
+	format := func(address ids.ShortID) string { return address.String() }
+	vm.watcher.AllowedHosts = vm.WatchAllowedHosts
+	if err := vm.watcher.Initialize(vm.Ctx.Log, prefixdb.New(watchPrefix, db), vm.Ctx.ChainID, format); err != nil {
+		return err
+	}
+	vm.Ctx.Hooks.OnBootstrapped(vm.watcher.Bootstrapped)
+	return nil
+}
+
+// watchOnAccept returns [onAccept], wrapped so the watches of the accounts
+// [tx] pays from or to are notified when it's accepted. [onAccept] may be nil.
+func (vm *VM) watchOnAccept(tx interface{}, onAccept func()) func() {
+	return func() {
+		if onAccept != nil {
+			onAccept()
+		}
+		if txID, addresses := txAddresses(tx); len(addresses) > 0 {
+			vm.watcher.Notify(txID, addresses)
+		}
+	}
+}
+
+// txAddresses returns the ID of [tx] and the addresses of the accounts it pays
+// from or to. [tx] must have been verified.
+func txAddresses(tx interface{}) (ids.ID, []ids.ShortID) {
+	var addresses []ids.ShortID
+	switch tx := tx.(type) {
+	case *CreateChainTx:
+		addresses = []ids.ShortID{tx.key.Address()}
+	case *CreateSubnetTx:
+		addresses = []ids.ShortID{tx.key.Address()}
+	case *TransferTx:
+		addresses = []ids.ShortID{tx.key.Address(), tx.To}
+	case *SendMessageTx:
+		addresses = []ids.ShortID{tx.key.Address()}
+	case *AddNonDefaultSubnetValidatorTx:
+		addresses = []ids.ShortID{tx.senderID}
+	case *AddDefaultSubnetValidatorTx:
+		addresses = []ids.ShortID{tx.senderID, tx.Destination}
+	case *AddDefaultSubnetDelegatorTx:
+		addresses = []ids.ShortID{tx.senderID, tx.Destination}
+	case *rewardValidatorTx:
+		// The stake, and its reward, are paid to the staker's destination
+		switch staker := tx.staker.(type) {
+		case *AddDefaultSubnetValidatorTx:
+			addresses = []ids.ShortID{staker.Destination}
+		case *AddDefaultSubnetDelegatorTx:
+			addresses = []ids.ShortID{staker.Destination}
+		}
+	default:
+		return ids.ID{}, nil
+	}
+
+	txBytes, err := Codec.Marshal(tx)
+	if err != nil {
+		return ids.ID{}, nil
+	}
+	return ids.NewID(hashing.ComputeHash256Array(txBytes)), addresses
+}
@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+)
+
+func TestTxAddresses(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	sender := keys[0].PublicKey().Address()
+	destination := keys[1].PublicKey().Address()
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		keys[0].PublicKey().Address(),
+		destination,
+		NumberOfShares,
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+
+	txID, addresses := txAddresses(tx)
+	if !txID.Equals(tx.ID()) {
+		t.Fatalf("expected the tx ID %s but got %s", tx.ID(), txID)
+	}
+	if len(addresses) != 2 || !addresses[0].Equals(sender) || !addresses[1].Equals(destination) {
+		t.Fatalf("expected the sender and the destination but got %v", addresses)
+	}
+
+	// Rewarding the validator pays its destination
+	rewardTx := &rewardValidatorTx{TxID: tx.ID(), staker: tx}
+	if _, addresses := txAddresses(rewardTx); len(addresses) != 1 || !addresses[0].Equals(destination) {
+		t.Fatalf("expected the destination but got %v", addresses)
+	}
+
+	if _, addresses := txAddresses(&advanceTimeTx{}); len(addresses) != 0 {
+		t.Fatalf("advancing the time shouldn't involve any account")
+	}
+}
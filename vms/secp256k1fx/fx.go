@@ -18,9 +18,11 @@ var (
 	errWrongOutputType     = errors.New("wrong output type")
 	errWrongInputType      = errors.New("wrong input type")
 	errWrongCredentialType = errors.New("wrong credential type")
+	errWrongOperationType  = errors.New("wrong operation type")
 
 	errWrongNumberOfOutputs     = errors.New("wrong number of outputs for an operation")
 	errWrongNumberOfInputs      = errors.New("wrong number of inputs for an operation")
+	errWrongNumberOfUTXOs       = errors.New("wrong number of utxos for an operation")
 	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
 
 	errWrongMintCreated = errors.New("wrong mint output created from the operation")
@@ -57,6 +59,20 @@ func (fx *Fx) Initialize(vmIntf interface{}) error {
 	return nil
 }
 
+// InitializeOperations registers the operations this Fx defines. They're
+// registered after the types of the VM that runs this Fx, so that the type IDs
+// of the types registered by Initialize don't change.
+func (fx *Fx) InitializeOperations(vmIntf interface{}) error {
+	vm, ok := vmIntf.(VM)
+	if !ok {
+		return errWrongVMType
+	}
+
+	c := vm.Codec()
+	c.RegisterType(&MintOperation{})
+	return nil
+}
+
 // VerifyOperation ...
 func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
 	tx, ok := txIntf.(Tx)
@@ -110,6 +126,37 @@ func (fx *Fx) verifyOperation(tx Tx, utxo *MintOutput, in *MintInput, cred *Cred
 	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
 }
 
+// VerifyFxOperation verifies that [txIntf] can perform [opIntf], a
+// MintOperation, on the mint output it consumes
+func (fx *Fx) VerifyFxOperation(txIntf, opIntf interface{}, credsIntf, utxosIntf []interface{}) error {
+	tx, ok := txIntf.(Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	op, ok := opIntf.(*MintOperation)
+	if !ok {
+		return errWrongOperationType
+	}
+
+	if len(utxosIntf) != 1 {
+		return errWrongNumberOfUTXOs
+	}
+	if len(credsIntf) != 1 {
+		return errWrongNumberOfCredentials
+	}
+
+	utxo, ok := utxosIntf[0].(*MintOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	cred, ok := credsIntf[0].(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+
+	return fx.verifyOperation(tx, utxo, &op.MintInput, cred, &op.MintOutput, &op.TransferOutput)
+}
+
 // VerifyTransfer ...
 func (fx *Fx) VerifyTransfer(txIntf, utxoIntf, inIntf, credIntf interface{}) error {
 	tx, ok := txIntf.(Tx)
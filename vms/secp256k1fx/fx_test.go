@@ -65,6 +65,17 @@ func TestFxInitializeInvalid(t *testing.T) {
 	}
 }
 
+func TestFxInitializeOperations(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.InitializeOperations(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.InitializeOperations(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
 func TestFxVerifyTransfer(t *testing.T) {
 	vm := testVM{}
 	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
@@ -1213,3 +1224,591 @@ func TestFxVerifyOperationMismatchedMintOutput(t *testing.T) {
 		t.Fatalf("Should have errored due to a mismatched mint output")
 	}
 }
+
+func TestFxVerifyFxOperation(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt:      1,
+			Locktime: 0,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyFxOperationUnknownTx(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(nil, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to an invalid tx")
+	}
+}
+
+func TestFxVerifyFxOperationWrongOperationType(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a wrong operation type")
+	}
+}
+
+func TestFxVerifyFxOperationWrongNumberOfUTXOs(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo, utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a wrong number of utxos")
+	}
+}
+
+func TestFxVerifyFxOperationWrongNumberOfCredentials(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	if err := fx.VerifyFxOperation(tx, op, nil, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a wrong number of credentials")
+	}
+}
+
+func TestFxVerifyFxOperationWrongUTXOType(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a wrong utxo type")
+	}
+}
+
+func TestFxVerifyFxOperationWrongCredentialType(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{nil}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a wrong credential type")
+	}
+}
+
+func TestFxVerifyFxOperationInvalidInput(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0, 0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to an invalid mint input")
+	}
+}
+
+func TestFxVerifyFxOperationInvalidTransferOutput(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 0,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to an invalid transfer output")
+	}
+}
+
+func TestFxVerifyFxOperationInvalid(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 0,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to an invalid mint output")
+	}
+}
+
+func TestFxVerifyFxOperationMismatchedMintOutput(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	op := &MintOperation{
+		MintInput: MintInput{
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+		MintOutput: MintOutput{
+			OutputOwners: OutputOwners{
+				Threshold: 0,
+				Addrs:     []ids.ShortID{},
+			},
+		},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					ids.NewShortID(addrBytes),
+				},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	creds := []interface{}{cred}
+	if err := fx.VerifyFxOperation(tx, op, creds, utxos); err == nil {
+		t.Fatalf("VerifyFxOperation should have errored due to a mismatched mint output")
+	}
+}
@@ -0,0 +1,38 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNilMintOperation = errors.New("nil mint operation")
+)
+
+// MintOperation consumes a mint output, with the signatures [MintInput]
+// indexes, to mint [TransferOutput]. The consumed mint output is replaced with
+// [MintOutput], which must have the same owners.
+type MintOperation struct {
+	MintInput      MintInput      `serialize:"true"`
+	MintOutput     MintOutput     `serialize:"true"`
+	TransferOutput TransferOutput `serialize:"true"`
+}
+
+// Outs returns the outputs this operation produces
+func (op *MintOperation) Outs() []verify.Verifiable {
+	return []verify.Verifiable{&op.MintOutput, &op.TransferOutput}
+}
+
+// Verify this operation is syntactically valid
+func (op *MintOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilMintOperation
+	default:
+		return verify.All(&op.MintInput, &op.MintOutput, &op.TransferOutput)
+	}
+}
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestMintOperationVerifyNil(t *testing.T) {
+	op := (*MintOperation)(nil)
+	if err := op.Verify(); err == nil {
+		t.Fatalf("MintOperation.Verify should have returned an error due to an nil operation")
+	}
+}
+
+func TestMintOperationVerifyInvalidInput(t *testing.T) {
+	op := MintOperation{
+		MintInput: MintInput{Input: Input{SigIndices: []uint32{1, 0}}},
+	}
+	if err := op.Verify(); err == nil {
+		t.Fatalf("MintOperation.Verify should have returned an error due to unsorted signatures")
+	}
+}
+
+func TestMintOperationOuts(t *testing.T) {
+	op := MintOperation{
+		MintInput: MintInput{Input: Input{SigIndices: []uint32{0}}},
+		MintOutput: MintOutput{OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.ShortEmpty},
+		}},
+		TransferOutput: TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.ShortEmpty},
+			},
+		},
+	}
+	if err := op.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	outs := op.Outs()
+	if len(outs) != 2 || outs[0] != &op.MintOutput || outs[1] != &op.TransferOutput {
+		t.Fatalf("MintOperation.Outs should return the new mint output and the minted output")
+	}
+}
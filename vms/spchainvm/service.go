@@ -11,6 +11,10 @@ import (
 	"github.com/ava-labs/gecko/utils/json"
 )
 
+// WriteMethods are the API methods that issue txs. Read-only API replicas
+// don't serve them.
+var WriteMethods = []string{"spchain.issueTx"}
+
 // Service defines the API exposed by the payments vm
 type Service struct{ vm *VM }
 
@@ -215,6 +215,13 @@ func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
 	}
 }
 
+// VerifyGenesis implements the common.GenesisVerifier interface
+func (vm *VM) VerifyGenesis(_ snow.Limits, genesisBytes []byte, _ []*common.Fx) error {
+	c := Codec{}
+	_, err := c.UnmarshalGenesis(genesisBytes)
+	return err
+}
+
 // IssueTx ...
 // TODO: Remove this
 func (vm *VM) IssueTx(b []byte, onDecide func(choices.Status)) (ids.ID, error) {
@@ -16,6 +16,10 @@ var (
 	errNilID = errors.New("nil ID is not valid")
 )
 
+// WriteMethods are the API methods that issue txs. Read-only API replicas
+// don't serve them.
+var WriteMethods = []string{"spdag.issueTx"}
+
 // Service defines the API services exposed by the ava vm
 type Service struct{ vm *VM }
 
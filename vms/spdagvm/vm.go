@@ -162,6 +162,13 @@ func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
 	}
 }
 
+// VerifyGenesis implements the common.GenesisVerifier interface
+func (vm *VM) VerifyGenesis(_ snow.Limits, genesisBytes []byte, _ []*common.Fx) error {
+	c := Codec{}
+	_, err := c.UnmarshalTx(genesisBytes)
+	return err
+}
+
 // PendingTxs returns the transactions that have not yet
 // been added to consensus
 func (vm *VM) PendingTxs() []snowstorm.Tx {
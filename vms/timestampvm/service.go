@@ -18,6 +18,10 @@ var (
 	errNoSuchBlock = errors.New("couldn't get block from database. Does it exist?")
 )
 
+// WriteMethods are the API methods that propose blocks. Read-only API replicas
+// don't serve them.
+var WriteMethods = []string{"timestamp.proposeBlock"}
+
 // Service is the API service for this VM
 type Service struct{ vm *VM }
 
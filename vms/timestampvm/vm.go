@@ -50,7 +50,7 @@ func (vm *VM) Initialize(
 		ctx.Log.Error("error initializing SnowmanVM: %v", err)
 		return err
 	}
-	vm.codec = codec.NewDefault()
+	vm.codec = codec.NewWithMaxSize(ctx.Limits.MaxBlockSize)
 
 	// If database is empty, create it using the provided genesis data
 	if !vm.DBInitialized() {
@@ -107,6 +107,14 @@ func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
 // We return nil because this VM has no static API
 func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler { return nil }
 
+// VerifyGenesis implements the common.GenesisVerifier interface
+func (vm *VM) VerifyGenesis(_ snow.Limits, genesisData []byte, _ []*common.Fx) error {
+	if len(genesisData) > dataLen {
+		return errBadGenesisBytes
+	}
+	return nil
+}
+
 // BuildBlock returns a block that this vm wants to add to consensus
 func (vm *VM) BuildBlock() (snowman.Block, error) {
 	if len(vm.mempool) == 0 { // There is no block to be built
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// AVMBuilder constructs the transactions of an AVM chain, and signs them,
+// without a running node. The transactions are serialized with the codec the
+// chain parses them with, so a transaction built and signed offline can be
+// issued with avm.issueTx.
+type AVMBuilder struct {
+	networkID  uint32
+	chainID    ids.ID
+	codec      codec.Codec
+	feeAssetID ids.ID
+	txFee      uint64
+}
+
+// NewAVMBuilder returns an AVMBuilder of the transactions of the chain
+// [chainID] of the network [networkID]. [limits] and [fxs] must be those the
+// chain is run with, in the same order, so that the transactions are
+// serialized as the chain expects. The transactions burn [txFee] of
+// [feeAssetID], the fee of the chain's genesis, or nothing if [txFee] is 0.
+func NewAVMBuilder(networkID uint32, chainID ids.ID, limits snow.Limits, fxs []*common.Fx, feeAssetID ids.ID, txFee uint64) (*AVMBuilder, error) {
+	c, err := avm.NewCodec(limits.MaxTxSize, fxs)
+	if err != nil {
+		return nil, err
+	}
+	return &AVMBuilder{
+		networkID:  networkID,
+		chainID:    chainID,
+		codec:      c,
+		feeAssetID: feeAssetID,
+		txFee:      txFee,
+	}, nil
+}
+
+// Codec returns the codec the transactions are serialized with
+func (b *AVMBuilder) Codec() codec.Codec { return b.codec }
+
+// Send returns the signed transaction that sends [amount] of [assetID] to
+// [to], and pays the chain's fee. The transaction spends the [utxos] that the
+// keys in [kc] can spend at the unix time [time], and sends the change to
+// [changeAddr].
+func (b *AVMBuilder) Send(utxos []*avm.UTXO, kc *secp256k1fx.Keychain, assetID ids.ID, amount uint64, to, changeAddr ids.ShortID, time uint64) ([]byte, error) {
+	tx, signers, err := avm.NewSendTx(b.networkID, b.chainID, b.codec, utxos, kc, assetID, amount, to, changeAddr, b.feeAssetID, b.txFee, time)
+	if err != nil {
+		return nil, err
+	}
+	return b.Sign(tx, signers)
+}
+
+// Sign [tx] and return its bytes. The i-th credential of [tx] is signed by
+// [signers][i], the keys that spend the i-th input.
+func (b *AVMBuilder) Sign(tx *avm.Tx, signers [][]*crypto.PrivateKeySECP256K1R) ([]byte, error) {
+	if err := tx.SignSECP256K1Fx(b.codec, signers); err != nil {
+		return nil, err
+	}
+	txBytes, err := b.codec.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	return txBytes, nil
+}
@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestAVMBuilderSend(t *testing.T) {
+	key := newTestKey(t)
+	addr := key.PublicKey().Address()
+	to := ids.NewShortID([20]byte{1})
+	chainID := ids.NewID([32]byte{1})
+	assetID := ids.NewID([32]byte{2})
+
+	b, err := NewAVMBuilder(testNetworkID, chainID, snow.DefaultLimits, []*common.Fx{&common.Fx{
+		ID: ids.Empty,
+		Fx: &secp256k1fx.Fx{},
+	}}, assetID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utxos := []*avm.UTXO{&avm.UTXO{
+		UTXOID: avm.UTXOID{TxID: ids.NewID([32]byte{3})},
+		Asset:  avm.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 10,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}}
+	kc := secp256k1fx.NewKeychain()
+	kc.Add(key)
+
+	if _, err := b.Send(utxos, kc, assetID, 10, to, addr, 0); err == nil {
+		t.Fatalf("shouldn't have been able to send more than the utxos hold, with the fee")
+	}
+	txBytes, err := b.Send(utxos, kc, assetID, 4, to, addr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := avm.Tx{}
+	if err := b.Codec().Unmarshal(txBytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case tx.NetworkID() != testNetworkID:
+		t.Fatalf("built the tx for network %d instead of %d", tx.NetworkID(), testNetworkID)
+	case !tx.ChainID().Equals(chainID):
+		t.Fatalf("built the tx for chain %s instead of %s", tx.ChainID(), chainID)
+	case len(tx.Inputs()) != 1:
+		t.Fatalf("expected 1 input but got %d", len(tx.Inputs()))
+	case len(tx.Outputs()) != 2:
+		t.Fatalf("expected an output and the change but got %d outputs", len(tx.Outputs()))
+	case len(tx.Creds) != 1:
+		t.Fatalf("expected 1 credential but got %d", len(tx.Creds))
+	}
+	produced := uint64(0)
+	for _, out := range tx.Outputs() {
+		produced += out.Output().Amount()
+	}
+	if produced != 9 {
+		t.Fatalf("should have burned the fee of 1 but produced %d of the 10 spent", produced)
+	}
+
+	// The credential is the key's signature of the unsigned tx
+	unsignedBytes, err := b.Codec().Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, ok := tx.Creds[0].Cred.(*secp256k1fx.Credential)
+	if !ok || len(cred.Sigs) != 1 {
+		t.Fatalf("expected a credential with 1 signature")
+	}
+	factory := crypto.FactorySECP256K1R{}
+	signer, err := factory.RecoverHashPublicKey(hashing.ComputeHash256(unsignedBytes), cred.Sigs[0][:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.Address().Equals(addr) {
+		t.Fatalf("expected the tx to be signed by %s but it was signed by %s", addr, signer.Address())
+	}
+}
+
+func TestNewAVMBuilderInvalidFx(t *testing.T) {
+	if _, err := NewAVMBuilder(testNetworkID, ids.Empty, snow.DefaultLimits, []*common.Fx{nil}, ids.Empty, 0); err == nil {
+		t.Fatalf("shouldn't have built the codec of an invalid fx")
+	}
+}
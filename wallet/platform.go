@@ -0,0 +1,184 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+// PlatformBuilder constructs the transactions of the platform chain, and signs
+// them, without a running node. The transactions are serialized with the codec
+// the chain parses them with, so a transaction built and signed offline can be
+// issued with platform.issueTx.
+type PlatformBuilder struct {
+	// ID of the network the transactions are issued on
+	NetworkID uint32
+}
+
+// AddDefaultSubnetValidator returns an unsigned transaction that adds
+// [nodeID] as a validator of the default subnet from [startTime] to [endTime],
+// staking [weight]. The stake and the reward are sent to [destination], and
+// [shares] is the validator's fee for delegations, out of
+// platformvm.NumberOfShares.
+// [nonce] is the next unused nonce of the account that pays the stake.
+func (b PlatformBuilder) AddDefaultSubnetValidator(nodeID ids.ShortID, weight, startTime, endTime uint64, destination ids.ShortID, shares uint32, nonce uint64) ([]byte, error) {
+	tx := platformvm.AddDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: platformvm.UnsignedAddDefaultSubnetValidatorTx{
+		DurationValidator: platformvm.DurationValidator{
+			Validator: platformvm.Validator{
+				NodeID: nodeID,
+				Wght:   weight,
+			},
+			Start: startTime,
+			End:   endTime,
+		},
+		Nonce:       nonce,
+		Destination: destination,
+		NetworkID:   b.NetworkID,
+		Shares:      shares,
+	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return nil, err
+	}
+	return b.marshal(&tx)
+}
+
+// AddDefaultSubnetDelegator returns an unsigned transaction that delegates
+// [weight] to the default subnet validator [nodeID] from [startTime] to
+// [endTime]. The stake and the reward are sent to [destination].
+// [nonce] is the next unused nonce of the account that pays the stake.
+func (b PlatformBuilder) AddDefaultSubnetDelegator(nodeID ids.ShortID, weight, startTime, endTime uint64, destination ids.ShortID, nonce uint64) ([]byte, error) {
+	tx := platformvm.AddDefaultSubnetDelegatorTx{UnsignedAddDefaultSubnetDelegatorTx: platformvm.UnsignedAddDefaultSubnetDelegatorTx{
+		DurationValidator: platformvm.DurationValidator{
+			Validator: platformvm.Validator{
+				NodeID: nodeID,
+				Wght:   weight,
+			},
+			Start: startTime,
+			End:   endTime,
+		},
+		NetworkID:   b.NetworkID,
+		Nonce:       nonce,
+		Destination: destination,
+	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return nil, err
+	}
+	return b.marshal(&tx)
+}
+
+// AddNonDefaultSubnetValidator returns an unsigned transaction that adds
+// [nodeID] as a validator of [subnetID], with weight [weight], from
+// [startTime] to [endTime]. It must be signed with SignSubnetValidator.
+// [nonce] is the next unused nonce of the account that pays the tx fee.
+func (b PlatformBuilder) AddNonDefaultSubnetValidator(nodeID ids.ShortID, subnetID ids.ID, weight, startTime, endTime, nonce uint64) ([]byte, error) {
+	tx := platformvm.AddNonDefaultSubnetValidatorTx{UnsignedAddNonDefaultSubnetValidatorTx: platformvm.UnsignedAddNonDefaultSubnetValidatorTx{
+		SubnetValidator: platformvm.SubnetValidator{
+			DurationValidator: platformvm.DurationValidator{
+				Validator: platformvm.Validator{
+					NodeID: nodeID,
+					Wght:   weight,
+				},
+				Start: startTime,
+				End:   endTime,
+			},
+			Subnet: subnetID,
+		},
+		NetworkID: b.NetworkID,
+		Nonce:     nonce,
+	}}
+	if err := tx.VerifyStakingDuration(); err != nil {
+		return nil, err
+	}
+	return b.marshal(&tx)
+}
+
+// CreateSubnet returns an unsigned transaction that creates a subnet.
+// Validators are added to the subnet with the signatures of [threshold] of
+// [controlKeys].
+// [nonce] is the next unused nonce of the account that pays the tx fee.
+func (b PlatformBuilder) CreateSubnet(controlKeys []ids.ShortID, threshold uint16, nonce uint64) ([]byte, error) {
+	tx := platformvm.CreateSubnetTx{UnsignedCreateSubnetTx: platformvm.UnsignedCreateSubnetTx{
+		NetworkID:   b.NetworkID,
+		Nonce:       nonce,
+		ControlKeys: controlKeys,
+		Threshold:   threshold,
+	}}
+	return b.marshal(&tx)
+}
+
+// CreateChain returns an unsigned transaction that creates the chain
+// [chainName], run by the VM [vmID] with the feature extensions [fxIDs], from
+// the genesis data [genesisData].
+// [nonce] is the next unused nonce of the account that pays the tx fee.
+func (b PlatformBuilder) CreateChain(chainName string, vmID ids.ID, fxIDs []ids.ID, genesisData []byte, nonce uint64) ([]byte, error) {
+	// The chain only accepts sorted feature extensions
+	sortedFxIDs := append([]ids.ID(nil), fxIDs...)
+	ids.SortIDs(sortedFxIDs)
+
+	tx := platformvm.CreateChainTx{UnsignedCreateChainTx: platformvm.UnsignedCreateChainTx{
+		NetworkID:   b.NetworkID,
+		Nonce:       nonce,
+		ChainName:   chainName,
+		VMID:        vmID,
+		FxIDs:       sortedFxIDs,
+		GenesisData: genesisData,
+	}}
+	return b.marshal(&tx)
+}
+
+// Transfer returns an unsigned transaction that sends [amount] $AVA to the
+// account [to].
+// [nonce] is the next unused nonce of the account the $AVA is sent from.
+func (b PlatformBuilder) Transfer(to ids.ShortID, amount, nonce uint64) ([]byte, error) {
+	tx := platformvm.TransferTx{UnsignedTransferTx: platformvm.UnsignedTransferTx{
+		NetworkID: b.NetworkID,
+		Nonce:     nonce,
+		To:        to,
+		Amount:    amount,
+	}}
+	return b.marshal(&tx)
+}
+
+// SendMessage returns an unsigned transaction that sends a message of type
+// [msgType] carrying [payload] to the chain [destination].
+// [nonce] is the next unused nonce of the account that pays the tx fee.
+func (b PlatformBuilder) SendMessage(destination ids.ID, msgType uint32, payload []byte, nonce uint64) ([]byte, error) {
+	tx := platformvm.SendMessageTx{UnsignedSendMessageTx: platformvm.UnsignedSendMessageTx{
+		NetworkID:   b.NetworkID,
+		Nonce:       nonce,
+		Destination: destination,
+		Type:        msgType,
+		Payload:     payload,
+	}}
+	return b.marshal(&tx)
+}
+
+// Sign [txBytes], a transaction returned by this PlatformBuilder, with
+// [keys]. Every transaction but an AddNonDefaultSubnetValidatorTx is signed by
+// exactly one key, the key of the account that pays for it.
+func (b PlatformBuilder) Sign(txBytes []byte, keys []*crypto.PrivateKeySECP256K1R) ([]byte, error) {
+	return platformvm.SignTx(txBytes, keys)
+}
+
+// SignSubnetValidator signs [txBytes], an unsigned or partially signed
+// AddNonDefaultSubnetValidatorTx, with [keys]. [controlKeys] and [threshold]
+// are those of the subnet the validator is added to, as returned by
+// platform.getSubnets.
+func (b PlatformBuilder) SignSubnetValidator(txBytes []byte, keys []*crypto.PrivateKeySECP256K1R, controlKeys []ids.ShortID, threshold uint16) ([]byte, error) {
+	return platformvm.SignSubnetValidatorTx(txBytes, keys, controlKeys, threshold)
+}
+
+// marshal the unsigned transaction [tx] as the chain parses it, prefixed with
+// its type ID
+func (b PlatformBuilder) marshal(tx interface{}) ([]byte, error) {
+	txBytes, err := platformvm.Codec.Marshal(&tx)
+	if err != nil {
+		return nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	return txBytes, nil
+}
@@ -0,0 +1,180 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+const testNetworkID = 10
+
+func newTestKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.(*crypto.PrivateKeySECP256K1R)
+}
+
+// parsePlatformTx returns the transaction the platform chain parses from
+// [txBytes]
+func parsePlatformTx(t *testing.T, txBytes []byte) interface{} {
+	var tx interface{}
+	if err := platformvm.Codec.Unmarshal(txBytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// verifySigner fails the test unless [sig] is [key]'s signature of [unsignedTx]
+func verifySigner(t *testing.T, unsignedTx interface{}, sig []byte, key *crypto.PrivateKeySECP256K1R) {
+	unsignedBytes, err := platformvm.Codec.Marshal(&unsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	signer, err := factory.RecoverPublicKey(unsignedBytes, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.Address().Equals(key.PublicKey().Address()) {
+		t.Fatalf("expected the tx to be signed by %s but it was signed by %s", key.PublicKey().Address(), signer.Address())
+	}
+}
+
+func TestPlatformBuilderTransfer(t *testing.T) {
+	key := newTestKey(t)
+	to := ids.NewShortID([20]byte{1})
+
+	b := PlatformBuilder{NetworkID: testNetworkID}
+	txBytes, err := b.Transfer(to, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedBytes, err := b.Sign(txBytes, []*crypto.PrivateKeySECP256K1R{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, ok := parsePlatformTx(t, signedBytes).(*platformvm.TransferTx)
+	switch {
+	case !ok:
+		t.Fatalf("built the wrong type of tx")
+	case tx.NetworkID != testNetworkID:
+		t.Fatalf("built the tx for network %d instead of %d", tx.NetworkID, testNetworkID)
+	case !tx.To.Equals(to):
+		t.Fatalf("sent the $AVA to %s instead of %s", tx.To, to)
+	case tx.Amount != 5:
+		t.Fatalf("sent %d $AVA instead of 5", tx.Amount)
+	case tx.Nonce != 1:
+		t.Fatalf("used the nonce %d instead of 1", tx.Nonce)
+	}
+	verifySigner(t, &tx.UnsignedTransferTx, tx.Sig[:], key)
+}
+
+func TestPlatformBuilderCreateChain(t *testing.T) {
+	key := newTestKey(t)
+	vmID := ids.NewID([32]byte{1})
+	fxIDs := []ids.ID{ids.NewID([32]byte{3}), ids.NewID([32]byte{2})}
+	genesisData := []byte{1, 2, 3}
+
+	b := PlatformBuilder{NetworkID: testNetworkID}
+	txBytes, err := b.CreateChain("chain name", vmID, fxIDs, genesisData, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedBytes, err := b.Sign(txBytes, []*crypto.PrivateKeySECP256K1R{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, ok := parsePlatformTx(t, signedBytes).(*platformvm.CreateChainTx)
+	switch {
+	case !ok:
+		t.Fatalf("built the wrong type of tx")
+	case tx.ChainName != "chain name":
+		t.Fatalf("named the chain %q instead of %q", tx.ChainName, "chain name")
+	case !tx.VMID.Equals(vmID):
+		t.Fatalf("ran the chain with the VM %s instead of %s", tx.VMID, vmID)
+	case !ids.IsSortedAndUniqueIDs(tx.FxIDs) || len(tx.FxIDs) != len(fxIDs):
+		t.Fatalf("the chain's feature extensions should have been sorted but were %v", tx.FxIDs)
+	case string(tx.GenesisData) != string(genesisData):
+		t.Fatalf("built the chain from the genesis data %v instead of %v", tx.GenesisData, genesisData)
+	}
+	verifySigner(t, &tx.UnsignedCreateChainTx, tx.Sig[:], key)
+
+	// The caller's feature extensions aren't reordered
+	if !fxIDs[0].Equals(ids.NewID([32]byte{3})) {
+		t.Fatalf("shouldn't have sorted the given feature extensions")
+	}
+}
+
+func TestPlatformBuilderAddNonDefaultSubnetValidator(t *testing.T) {
+	controlKey := newTestKey(t)
+	payerKey := newTestKey(t)
+	controlKeys := []ids.ShortID{controlKey.PublicKey().Address()}
+
+	b := PlatformBuilder{NetworkID: testNetworkID}
+	txBytes, err := b.AddNonDefaultSubnetValidator(
+		ids.NewShortID([20]byte{1}),
+		ids.NewID([32]byte{1}),
+		1,
+		uint64(platformvm.MinimumStakingDuration.Seconds()),
+		uint64(2*platformvm.MinimumStakingDuration.Seconds()),
+		1,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Sign(txBytes, []*crypto.PrivateKeySECP256K1R{payerKey}); err == nil {
+		t.Fatalf("should have required the subnet's control keys")
+	}
+
+	signedBytes, err := b.SignSubnetValidator(
+		txBytes,
+		[]*crypto.PrivateKeySECP256K1R{controlKey, payerKey},
+		controlKeys,
+		1,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, ok := parsePlatformTx(t, signedBytes).(*platformvm.AddNonDefaultSubnetValidatorTx)
+	switch {
+	case !ok:
+		t.Fatalf("built the wrong type of tx")
+	case len(tx.ControlSigs) != 1:
+		t.Fatalf("expected 1 control signature but got %d", len(tx.ControlSigs))
+	}
+	verifySigner(t, &tx.UnsignedAddNonDefaultSubnetValidatorTx, tx.ControlSigs[0][:], controlKey)
+	verifySigner(t, &tx.UnsignedAddNonDefaultSubnetValidatorTx, tx.PayerSig[:], payerKey)
+
+	if _, err := b.SignSubnetValidator(signedBytes, nil, controlKeys, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.SignSubnetValidator(signedBytes, []*crypto.PrivateKeySECP256K1R{newTestKey(t)}, controlKeys, 1); err == nil {
+		t.Fatalf("shouldn't have been signed by a third key")
+	}
+}
+
+func TestPlatformBuilderStakingDuration(t *testing.T) {
+	b := PlatformBuilder{NetworkID: testNetworkID}
+	if _, err := b.AddDefaultSubnetValidator(
+		ids.NewShortID([20]byte{1}),
+		platformvm.MinimumStakeAmount,
+		1,
+		2,
+		ids.NewShortID([20]byte{1}),
+		platformvm.NumberOfShares,
+		1,
+	); err == nil {
+		t.Fatalf("shouldn't have built a tx with a staking period that's too short")
+	}
+}